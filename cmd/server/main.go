@@ -5,12 +5,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"timelocker-backend/docs"
 	abiHandler "timelocker-backend/internal/api/abi"
+	adminHandler "timelocker-backend/internal/api/admin"
+	auditHandler "timelocker-backend/internal/api/audit"
 	authHandler "timelocker-backend/internal/api/auth"
 	chainHandler "timelocker-backend/internal/api/chain"
 	emailHandler "timelocker-backend/internal/api/email"
@@ -19,11 +23,16 @@ import (
 	notificationHandler "timelocker-backend/internal/api/notification"
 	publicHandler "timelocker-backend/internal/api/public"
 	timelockHandler "timelocker-backend/internal/api/timelock"
+	userHandler "timelocker-backend/internal/api/user"
 
 	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/middleware"
 	abiRepo "timelocker-backend/internal/repository/abi"
+	apiTokenRepo "timelocker-backend/internal/repository/apitoken"
+	auditRepo "timelocker-backend/internal/repository/audit"
 	chainRepo "timelocker-backend/internal/repository/chain"
 	emailRepo "timelocker-backend/internal/repository/email"
+	errorLogRepo "timelocker-backend/internal/repository/errorlog"
 	goldskyRepo "timelocker-backend/internal/repository/goldsky"
 	notificationRepo "timelocker-backend/internal/repository/notification"
 	publicRepo "timelocker-backend/internal/repository/public"
@@ -32,6 +41,8 @@ import (
 
 	userRepo "timelocker-backend/internal/repository/user"
 	abiService "timelocker-backend/internal/service/abi"
+	adminService "timelocker-backend/internal/service/admin"
+	auditService "timelocker-backend/internal/service/audit"
 	authService "timelocker-backend/internal/service/auth"
 	chainService "timelocker-backend/internal/service/chain"
 	emailService "timelocker-backend/internal/service/email"
@@ -41,11 +52,13 @@ import (
 	publicService "timelocker-backend/internal/service/public"
 	scannerService "timelocker-backend/internal/service/scanner"
 	timelockService "timelocker-backend/internal/service/timelock"
+	userService "timelocker-backend/internal/service/user"
 
 	"timelocker-backend/pkg/database"
 
 	"timelocker-backend/pkg/logger"
 	"timelocker-backend/pkg/utils"
+	"timelocker-backend/pkg/version"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -62,10 +75,15 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Read-only API token created via /api/v1/auth/api-tokens/create.
 
 func main() {
 	logger.Init(logger.DefaultConfig())
-	logger.Info("Starting Timelock Backend v1.0.0")
+	buildInfo := version.Get()
+	logger.Info("Starting Timelock Backend", "version", buildInfo.Version, "git_commit", buildInfo.GitCommit, "build_time", buildInfo.BuildTime, "go_version", buildInfo.GoVersion)
 
 	// 创建根context和WaitGroup用于协调关闭
 	ctx, cancel := context.WithCancel(context.Background())
@@ -81,6 +99,10 @@ func main() {
 		logger.Error("Failed to load config: ", err)
 		os.Exit(1)
 	}
+	if err := utils.SetOutboundAllowlist(cfg.Security.OutboundURLAllowlist); err != nil {
+		logger.Error("Invalid security.outbound_url_allowlist: ", err)
+		os.Exit(1)
+	}
 
 	// 2. 连接数据库
 	db, err := database.NewPostgresConnection(&cfg.Database)
@@ -89,8 +111,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 设置logger数据库写入器，使错误日志可以写入数据库
-	logger.SetDB(db)
+	// 设置logger数据库写入器，使错误日志（按配置包括警告）可以写入数据库
+	logger.SetDB(db, logger.LogLevel(strings.ToUpper(strings.TrimSpace(cfg.Server.DBLogLevel))))
+
+	// 注册审计日志回调，配置变更与审计日志在同一事务中写入
+	if err := auditRepo.RegisterCallbacks(db); err != nil {
+		logger.Error("Failed to register audit callbacks: ", err)
+		os.Exit(1)
+	}
 
 	// 3. 连接Redis
 	// redisClient, err := database.NewRedisConnection(&cfg.Redis)
@@ -107,6 +135,9 @@ func main() {
 	emailRepository := emailRepo.NewEmailRepository(db)
 	notificationRepository := notificationRepo.NewRepository(db)
 	safeRepository := safeRepo.NewRepository(db)
+	apiTokenRepository := apiTokenRepo.NewRepository(db)
+	auditRepository := auditRepo.NewRepository(db)
+	errorLogRepository := errorLogRepo.NewRepository(db)
 
 	// Goldsky Flow 仓库
 	goldskyFlowRepository := goldskyRepo.NewFlowRepository(db)
@@ -115,15 +146,25 @@ func main() {
 	publicRepository := publicRepo.NewRepository(db)
 
 	// 5. 初始化JWT管理器
+	jwtVerificationKeys, err := utils.ParseJWTVerificationKeys(cfg.JWT.VerificationKeys)
+	if err != nil {
+		logger.Error("Invalid jwt.verification_keys: ", err)
+		os.Exit(1)
+	}
 	jwtManager := utils.NewJWTManager(
 		cfg.JWT.Secret,
 		cfg.JWT.AccessExpiry,
 		cfg.JWT.RefreshExpiry,
+		utils.JWTOptions{
+			KeyID:            cfg.JWT.KeyID,
+			VerificationKeys: jwtVerificationKeys,
+			Issuer:           cfg.JWT.Issuer,
+			Audience:         cfg.JWT.Audience,
+		},
 	)
 
 	// 6. 初始化服务层
-	abiSvc := abiService.NewService(abiRepository)
-	chainSvc := chainService.NewService(chainRepository)
+	chainSvc := chainService.NewService(chainRepository, cfg.Explorer)
 
 	// 初始化 email 和 notification 服务（使用 Goldsky Flow Repository）
 	emailSvc := emailService.NewEmailService(emailRepository, chainRepository, timelockRepository, goldskyFlowRepository, cfg)
@@ -147,13 +188,20 @@ func main() {
 	goldskyProcessor := goldskyService.NewWebhookProcessor(
 		timelockRepository,
 		goldskyFlowRepository,
+		goldskyRepo.NewWebhookEventRepository(db),
 		goldskySvc,
 		emailSvc,
 		notificationSvc,
 	)
 
 	// 初始化 Flow 服务
-	flowSvc := flowService.NewFlowService(goldskyFlowRepository, chainRepository, goldskySvc)
+	flowSvc := flowService.NewFlowService(goldskyFlowRepository, chainRepository, goldskySvc, notificationSvc, emailSvc)
+
+	// 初始化用户设置服务
+	userSvc := userService.NewService(emailSvc, notificationSvc)
+
+	// 审计日志服务
+	auditSvc := auditService.NewService(auditRepository)
 
 	// 7. 设置Gin和路由
 	gin.SetMode(cfg.Server.Mode)
@@ -163,7 +211,7 @@ func main() {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -173,6 +221,32 @@ func main() {
 		c.Next()
 	})
 
+	// 请求体大小限制：在绑定参数前拦截超大请求，ABI/批量导入路径使用较大上限
+	router.Use(middleware.BodyLimit(middleware.BodyLimitOptions{
+		MaxBytes:      cfg.Server.MaxBodySize,
+		LargeMaxBytes: cfg.Server.MaxLargeBodySize,
+		LargePaths:    cfg.Server.LargeBodyPaths,
+	}))
+
+	// 响应压缩：列表/导出等较大的 JSON 响应启用 gzip
+	if cfg.Server.GzipEnabled {
+		router.Use(middleware.Gzip(middleware.GzipOptions{
+			MinSize:      cfg.Server.GzipMinSize,
+			Level:        cfg.Server.GzipLevel,
+			ExcludePaths: cfg.Server.GzipExcludePaths,
+		}))
+	}
+
+	// 读接口查询超时：列表/搜索类接口的慢查询超时后取消并返回 503
+	router.Use(middleware.QueryTimeout(middleware.QueryTimeoutOptions{
+		Timeout: cfg.Database.StatementTimeout,
+		Paths:   cfg.Database.StatementTimeoutPaths,
+	}))
+
+	// 维护模式：开启后写接口返回 503（可通过管理员接口切换）
+	maintenanceState := middleware.NewMaintenanceState(cfg.Server.MaintenanceMode, cfg.Server.MaintenanceMessage)
+	router.Use(middleware.MaintenanceMode(maintenanceState))
+
 	// 9. 创建API路由组
 	v1 := router.Group("/api/v1")
 	{
@@ -191,7 +265,11 @@ func main() {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	// 健康检查端点
 	router.GET("/api/v1/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "maintenance": maintenanceState.Enabled(), "version": buildInfo})
+	})
+	// 构建信息（版本、git commit、构建时间、Go 版本），用于排障时确认运行中的构建
+	router.GET("/api/v1/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildInfo)
 	})
 
 	// 11. 启动 RPC 管理器（auth 和 timelock 服务需要）
@@ -201,8 +279,18 @@ func main() {
 	} else {
 		logger.Info("RPC Manager started successfully")
 	}
+	// RPC 健康状态（含各链 eth_getLogs 探测到的区块跨度）
+	router.GET("/api/v1/health/rpc", func(c *gin.Context) {
+		c.JSON(http.StatusOK, rpcManager.GetStatus())
+	})
+
+	// 通知分发队列统计（投递/发送/丢弃累计数，及关闭时排空结果）
+	router.GET("/api/v1/health/notifications", func(c *gin.Context) {
+		c.JSON(http.StatusOK, goldskySvc.NotificationQueueStats())
+	})
 
 	// 12. 启动 Goldsky 服务
+	goldskySvc.SetRPCLogSource(rpcManager, goldskyRepo.NewRPCFallbackCursorRepository(db)) // 无 subgraph 的链回退到 RPC 扫描日志
 	if err := goldskySvc.Start(); err != nil {
 		logger.Error("Failed to start Goldsky service", err)
 	} else {
@@ -210,8 +298,10 @@ func main() {
 	}
 
 	// 13. 初始化需要 RPC 的服务和处理器
-	authSvc := authService.NewService(userRepository, safeRepository, rpcManager, jwtManager)
-	timelockSvc := timelockService.NewService(timelockRepository, chainRepository, rpcManager, goldskySvc, &cfg.Timelock)
+	authSvc := authService.NewService(userRepository, safeRepository, apiTokenRepository, rpcManager, jwtManager)
+	abiSvc := abiService.NewService(abiRepository, rpcManager, cfg.ABI)
+	timelockSvc := timelockService.NewService(timelockRepository, chainRepository, rpcManager, goldskySvc, notificationSvc, abiSvc, notificationSvc, chainSvc, &cfg.Timelock)
+	adminSvc := adminService.NewAdminService(goldskyFlowRepository, notificationRepository, emailRepository, errorLogRepository, notificationSvc, emailSvc, goldskySvc, rpcManager, chainSvc)
 
 	// 14. 初始化处理器并注册路由
 	authHandler := authHandler.NewHandler(authSvc)
@@ -229,12 +319,21 @@ func main() {
 	flowHdl := flowHandler.NewFlowHandler(flowSvc, authSvc)
 	flowHdl.RegisterRoutes(v1)
 
-	notificationHdl := notificationHandler.NewNotificationHandler(notificationSvc, authSvc)
+	notificationHdl := notificationHandler.NewNotificationHandler(notificationSvc, authSvc, cfg.JWT.ReauthMaxAge)
 	notificationHdl.RegisterRoutes(v1)
 
 	goldskyHdl := goldskyHandler.NewWebhookHandler(goldskyProcessor, chainRepository)
 	goldskyHdl.RegisterRoutes(v1)
 
+	adminHdl := adminHandler.NewAdminHandler(adminSvc, authSvc, cfg.Admin.WalletAddresses, maintenanceState)
+	adminHdl.RegisterRoutes(v1)
+
+	userHdl := userHandler.NewHandler(userSvc, authSvc)
+	userHdl.RegisterRoutes(v1)
+
+	auditHdl := auditHandler.NewHandler(auditSvc, authSvc, cfg.Admin.WalletAddresses)
+	auditHdl.RegisterRoutes(v1)
+
 	// goldskySyncHdl := goldskyHandler.NewSyncHandler(goldskySvc)
 	// goldskySyncHdl.RegisterRoutes(v1)
 
@@ -250,10 +349,14 @@ func main() {
 
 		runOnce := func() {
 			logger.Info("Starting scheduled timelock data refresh", "interval", refreshInterval.String())
-			if err := timelockSvc.RefreshAllTimeLockData(ctx); err != nil {
-				logger.Error("Failed to refresh timelock data", err)
-			} else {
+			err := timelockSvc.RefreshAllTimeLockData(ctx)
+			switch {
+			case err == nil:
 				logger.Info("Scheduled timelock data refresh completed successfully")
+			case ctx.Err() != nil:
+				logger.Info("Scheduled timelock data refresh stopped by shutdown")
+			default:
+				logger.Error("Failed to refresh timelock data", err)
 			}
 		}
 
@@ -273,6 +376,189 @@ func main() {
 		}
 	}()
 
+	// 启动定时任务：RPC eth_getLogs 区块跨度能力探测（启动后立即探测一次，再周期性重新探测）
+	logsProbeInterval := cfg.RPC.LogsProbeInterval
+	if logsProbeInterval <= 0 {
+		logsProbeInterval = 6 * time.Hour
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer logger.Info("RPC logs capability probe task stopped")
+
+		rpcManager.ProbeAllLogsCapabilities(ctx)
+
+		ticker := time.NewTicker(logsProbeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rpcManager.ProbeAllLogsCapabilities(ctx)
+			}
+		}
+	}()
+
+	// 启动定时任务：全局统计快照（interval <= 0 时不启动）
+	if cfg.StatsHistory.Interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logger.Info("Stats snapshot task stopped")
+
+			runOnce := func() {
+				if err := publicSvc.SnapshotStats(ctx); err != nil {
+					logger.Error("Failed to snapshot global stats", err)
+				}
+			}
+
+			runOnce()
+
+			ticker := time.NewTicker(cfg.StatsHistory.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runOnce()
+				}
+			}
+		}()
+	}
+
+	// 启动定时任务：终态 flow 归档（retention_months <= 0 时不启动）
+	if cfg.FlowArchive.RetentionMonths > 0 {
+		archiveInterval := cfg.FlowArchive.Interval
+		if archiveInterval <= 0 {
+			archiveInterval = 24 * time.Hour
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logger.Info("Flow archive task stopped")
+
+			runOnce := func() {
+				before := time.Now().AddDate(0, -cfg.FlowArchive.RetentionMonths, 0)
+				if err := flowSvc.ArchiveTerminalFlows(ctx, before, cfg.FlowArchive.BatchSize); err != nil {
+					logger.Error("Failed to archive terminal flows", err)
+				}
+			}
+
+			runOnce()
+
+			ticker := time.NewTicker(archiveInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runOnce()
+				}
+			}
+		}()
+	}
+
+	// 启动定时任务：清理过期 error_logs（retention_days <= 0 时不启动）
+	if cfg.ErrorLogCleanup.RetentionDays > 0 {
+		cleanupInterval := cfg.ErrorLogCleanup.Interval
+		if cleanupInterval <= 0 {
+			cleanupInterval = 24 * time.Hour
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logger.Info("Error log cleanup task stopped")
+
+			runOnce := func() {
+				before := time.Now().AddDate(0, 0, -cfg.ErrorLogCleanup.RetentionDays)
+				if _, err := adminSvc.CleanupErrorLogs(ctx, before, cfg.ErrorLogCleanup.BatchSize); err != nil {
+					logger.Error("Failed to clean up error logs", err)
+				}
+			}
+
+			runOnce()
+
+			ticker := time.NewTicker(cleanupInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runOnce()
+				}
+			}
+		}()
+	}
+
+	// 启动定时任务：检测合约已被删除的孤立 flow，配置 archive_after 时归档（interval <= 0 时不启动）
+	if cfg.OrphanedFlows.Interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logger.Info("Orphaned flow cleanup task stopped")
+
+			runOnce := func() {
+				var archiveBefore time.Time
+				if cfg.OrphanedFlows.ArchiveAfter > 0 {
+					archiveBefore = time.Now().Add(-cfg.OrphanedFlows.ArchiveAfter)
+				}
+				if _, err := adminSvc.CleanupOrphanedFlows(ctx, archiveBefore, cfg.OrphanedFlows.BatchSize); err != nil {
+					logger.Error("Failed to clean up orphaned flows", err)
+				}
+			}
+
+			runOnce()
+
+			ticker := time.NewTicker(cfg.OrphanedFlows.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runOnce()
+				}
+			}
+		}()
+	}
+
+	// 启动定时任务：处理确认数不足而暂存的 Goldsky webhook 事件（仅配置了 confirmation_depth 的链会暂存）
+	goldskyProcessor.SetConfirmationSource(rpcManager, cfg.Goldsky.ConfirmationMaxWait, cfg.Goldsky.ConfirmationMaxPending)
+	confirmationCheckInterval := cfg.Goldsky.ConfirmationCheckInterval
+	if confirmationCheckInterval <= 0 {
+		confirmationCheckInterval = 5 * time.Second
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer logger.Info("Webhook confirmation check task stopped")
+
+		ticker := time.NewTicker(confirmationCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// 未确认的事件仍为 processing，由 Goldsky 定时同步补齐
+				if pending := goldskyProcessor.PendingConfirmationCount(); pending > 0 {
+					logger.Warn("Dropping unconfirmed webhook events on shutdown", "count", pending)
+				}
+				return
+			case <-ticker.C:
+				goldskyProcessor.ProcessPendingConfirmations(ctx)
+			}
+		}
+	}()
+
 	// 16. 启动邮箱验证码清理定时任务
 	wg.Add(1)
 	go func() {
@@ -297,6 +583,33 @@ func main() {
 		}
 	}()
 
+	// 启动定时任务：重发临时失败的通知邮件（retry_max_count <= 0 时不启动）
+	if cfg.Email.RetryMaxCount > 0 {
+		retryInterval := cfg.Email.RetryInterval
+		if retryInterval <= 0 {
+			retryInterval = 5 * time.Minute
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logger.Info("Email send retry task stopped")
+
+			ticker := time.NewTicker(retryInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := emailSvc.RetryFailedSends(ctx); err != nil {
+						logger.Error("Failed to retry failed email sends", err)
+					}
+				}
+			}
+		}()
+	}
+
 	// 17. 启动HTTP服务器
 	addr := ":" + cfg.Server.Port
 	srv := &http.Server{
@@ -323,8 +636,21 @@ func main() {
 	// 19. 开始优雅关闭（逆序关闭）
 
 	// Step 1: 停止HTTP服务器（最后启动的最先关闭）
-	logger.Info("Stopping HTTP server...")
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownTimeout := cfg.Server.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+	waitTimeout := cfg.Server.ShutdownWaitTimeout
+	if waitTimeout <= 0 {
+		waitTimeout = 15 * time.Second
+	}
+	if cfg.Notification.DrainTimeout >= waitTimeout {
+		logger.Warn("notification.drain_timeout is not shorter than server.shutdown_wait_timeout, queued notifications may be cut off by the forced exit",
+			"drain_timeout", cfg.Notification.DrainTimeout.String(), "shutdown_wait_timeout", waitTimeout.String())
+	}
+
+	logger.Info("Stopping HTTP server...", "timeout", shutdownTimeout.String())
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("HTTP server shutdown error: ", err)
 	} else {
@@ -336,18 +662,17 @@ func main() {
 	logger.Info("Cancelling context to stop all services...")
 	cancel()
 
-	// Step 3: 停止 Goldsky 服务
-	logger.Info("Stopping Goldsky service...")
-	goldskySvc.Stop()
-
-	// Step 4: 停止 RPC 管理器
-	logger.Info("Stopping RPC manager...")
-	rpcManager.Stop()
-
-	// Step 5: 等待所有goroutine结束
-	logger.Info("Waiting for all goroutines to finish...")
+	// Step 3-5: 停止 Goldsky 服务（含通知队列排空）、RPC 管理器并等待所有goroutine结束，整体受 shutdown_wait_timeout 约束
+	logger.Info("Waiting for all services to stop...", "timeout", waitTimeout.String())
 	done := make(chan struct{})
 	go func() {
+		logger.Info("Stopping Goldsky service...")
+		goldskySvc.Stop()
+
+		logger.Info("Stopping RPC manager...")
+		rpcManager.Stop()
+
+		logger.Info("Waiting for all goroutines to finish...")
 		wg.Wait()
 		close(done)
 	}()
@@ -355,7 +680,13 @@ func main() {
 	select {
 	case <-done:
 		logger.Info("All services stopped gracefully")
-	case <-time.After(15 * time.Second):
-		logger.Error("Timeout waiting for services to stop, forcing exit", nil)
+	case <-time.After(waitTimeout):
+		// 打印仍在运行的 goroutine 堆栈，便于排查卡住关闭的任务
+		buf := make([]byte, 1<<20)
+		n := runtime.Stack(buf, true)
+		logger.Error("Timeout waiting for services to stop, forcing exit", nil,
+			"timeout", waitTimeout.String(),
+			"goroutines", runtime.NumGoroutine(),
+			"stack", string(buf[:n]))
 	}
 }
@@ -3,6 +3,7 @@ package abi
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"timelocker-backend/internal/middleware"
 	abiService "timelocker-backend/internal/service/abi"
@@ -39,25 +40,53 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		// POST /api/v1/abi/list
 		abiGroup.POST("/list", h.GetABIList)
 
+		// 分页获取用户的ABI（可选包含共享ABI），附带使用次数
+		// GET /api/v1/abi/list?page=1&page_size=20&name=&include_shared=false
+		abiGroup.GET("/list", h.GetABIPage)
+
+		// 按函数名（或 0x 选择器）搜索平台共享ABI
+		// GET /api/v1/abi/shared/search?function=transfer
+		abiGroup.GET("/shared/search", h.SearchSharedABIs)
+
 		// 创建新的ABI
 		// POST /api/v1/abi
-		abiGroup.POST("", h.CreateABI)
+		abiGroup.POST("", middleware.RequireWriteScope(), h.CreateABI)
 
 		// 验证ABI格式
 		// POST /api/v1/abi/validate
 		abiGroup.POST("/validate", h.ValidateABI)
 
+		// 解码任意calldata
+		// POST /api/v1/abi/decode
+		abiGroup.POST("/decode", h.DecodeCalldata)
+
+		// 依次尝试用户ABI、共享ABI与公共签名库尽力解码calldata
+		// POST /api/v1/abi/decode-best-effort
+		abiGroup.POST("/decode-best-effort", h.DecodeBestEffort)
+
+		// 校验ABI中的函数是否在已部署合约的字节码中实现
+		// POST /api/v1/abi/verify-against-contract
+		abiGroup.POST("/verify-against-contract", h.VerifyABIAgainstContract)
+
 		// 获取ABI详情
 		// POST /api/v1/abi/get
 		abiGroup.POST("/get", h.GetABIByID)
 
 		// 更新ABI
 		// POST /api/v1/abi/update
-		abiGroup.POST("/update", h.UpdateABI)
+		abiGroup.POST("/update", middleware.RequireWriteScope(), h.UpdateABI)
 
 		// 删除ABI
 		// POST /api/v1/abi/delete
-		abiGroup.POST("/delete", h.DeleteABI)
+		abiGroup.POST("/delete", middleware.RequireWriteScope(), h.DeleteABI)
+
+		// 按ID删除ABI
+		// DELETE /api/v1/abi/:id?force=false
+		abiGroup.DELETE("/:id", middleware.RequireWriteScope(), h.DeleteABIByID)
+
+		// 使用ABI重新解码用户相关的历史流程
+		// POST /api/v1/abi/:id/redecode
+		abiGroup.POST("/:id/redecode", middleware.RequireWriteScope(), h.RedecodeFlows)
 	}
 }
 
@@ -71,6 +100,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 // @Param request body types.CreateABIRequest true "创建ABI请求体"
 // @Success 201 {object} types.APIResponse{data=types.ABIResponse} "ABI创建成功"
 // @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或ABI格式无效"
+// @Failure 413 {object} types.APIResponse{error=types.APIError} "请求体或ABI内容超出大小限制（REQUEST_TOO_LARGE / ABI_TOO_LARGE）"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
 // @Failure 409 {object} types.APIResponse{error=types.APIError} "ABI名称已存在"
 // @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
@@ -116,6 +146,9 @@ func (h *Handler) CreateABI(c *gin.Context) {
 		case errors.Is(err, abiService.ErrInvalidABI):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_ABI"
+		case errors.Is(err, abiService.ErrABITooLarge):
+			statusCode = http.StatusRequestEntityTooLarge
+			errorCode = "ABI_TOO_LARGE"
 		case errors.Is(err, abiService.ErrABINameExists):
 			statusCode = http.StatusConflict
 			errorCode = "ABI_NAME_EXISTS"
@@ -189,6 +222,135 @@ func (h *Handler) GetABIList(c *gin.Context) {
 	})
 }
 
+// GetABIPage 分页获取用户的ABI
+// @Summary 分页获取用户的ABI
+// @Description 分页返回用户自己创建的ABI（include_shared=true 时同时返回平台共享ABI），支持按名称模糊搜索。列表项不含 abi_content，附带函数数量 function_count 与 used_in_flows（用户相关流程中调用了该 ABI 任一函数的流程数，按函数选择器索引统计，不含已归档流程）。
+// @Tags ABI
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页大小，默认20，最大100"
+// @Param name query string false "名称模糊搜索"
+// @Param include_shared query bool false "是否包含共享ABI"
+// @Success 200 {object} types.APIResponse{data=types.GetABIPageResponse} "获取ABI列表成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/list [get]
+func (h *Handler) GetABIPage(c *gin.Context) {
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetABIPage Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.GetABIPageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.abiService.GetABIPage(c.Request.Context(), walletAddress, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		logger.Error("GetABIPage Error:", err, "wallet_address", walletAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SearchSharedABIs 按函数名搜索共享ABI
+// @Summary 按函数名搜索共享ABI
+// @Description 在平台共享ABI（ERC20、ERC721、Compound Timelock 等）中查找包含指定函数的ABI，函数名不区分大小写模糊匹配（如 transfer 同时匹配 transferFrom）；传入 0x 开头的 4 字节选择器时按选择器精确匹配，便于为无法解码的调用找到合适的ABI。结果按ABI分组，附带匹配的函数签名与选择器，不含 abi_content。
+// @Tags ABI
+// @Produce json
+// @Security BearerAuth
+// @Param function query string true "函数名或 0x 开头的 4 字节选择器"
+// @Success 200 {object} types.APIResponse{data=types.SearchSharedABIsResponse} "搜索成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/shared/search [get]
+func (h *Handler) SearchSharedABIs(c *gin.Context) {
+	if _, _, ok := middleware.GetUserFromContext(c); !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("SearchSharedABIs Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.SearchSharedABIsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.abiService.SearchSharedABIs(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, abiService.ErrInvalidABISearch) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_REQUEST",
+					Message: "Invalid request parameters",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		logger.Error("SearchSharedABIs Error:", err, "function", req.Function)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
 // GetABIByID 根据ID获取ABI详情
 // @Summary 获取ABI详情
 // @Description 根据ABI ID获取详细信息。用户只能访问自己创建的ABI或平台共享的ABI。
@@ -280,6 +442,7 @@ func (h *Handler) GetABIByID(c *gin.Context) {
 // @Param request body types.UpdateABIWithIDRequest true "更新ABI请求体（包含ID）"
 // @Success 200 {object} types.APIResponse{data=types.ABIResponse} "ABI更新成功"
 // @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或ABI格式无效"
+// @Failure 413 {object} types.APIResponse{error=types.APIError} "请求体或ABI内容超出大小限制（REQUEST_TOO_LARGE / ABI_TOO_LARGE）"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
 // @Failure 403 {object} types.APIResponse{error=types.APIError} "无权更新该ABI"
 // @Failure 404 {object} types.APIResponse{error=types.APIError} "ABI不存在"
@@ -333,6 +496,9 @@ func (h *Handler) UpdateABI(c *gin.Context) {
 		case errors.Is(err, abiService.ErrInvalidABI):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_ABI"
+		case errors.Is(err, abiService.ErrABITooLarge):
+			statusCode = http.StatusRequestEntityTooLarge
+			errorCode = "ABI_TOO_LARGE"
 		case errors.Is(err, abiService.ErrABINameExists):
 			statusCode = http.StatusConflict
 			errorCode = "ABI_NAME_EXISTS"
@@ -361,7 +527,7 @@ func (h *Handler) UpdateABI(c *gin.Context) {
 
 // DeleteABI 删除ABI
 // @Summary 删除ABI
-// @Description 删除用户创建的ABI。用户只能删除自己创建的ABI，不能删除平台共享的ABI。删除操作是不可逆的。
+// @Description 删除用户创建的ABI。用户只能删除自己创建的ABI，不能删除平台共享的ABI。ABI仍被用户相关流程使用时返回 409（ABI_IN_USE），force=true 时强制删除。删除操作是不可逆的。
 // @Tags ABI
 // @Accept json
 // @Produce json
@@ -372,6 +538,7 @@ func (h *Handler) UpdateABI(c *gin.Context) {
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
 // @Failure 403 {object} types.APIResponse{error=types.APIError} "无权删除该ABI或尝试删除共享ABI"
 // @Failure 404 {object} types.APIResponse{error=types.APIError} "ABI不存在"
+// @Failure 409 {object} types.APIResponse{error=types.APIError} "ABI仍被流程使用（ABI_IN_USE）"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
 // @Router /api/v1/abi/delete [post]
 func (h *Handler) DeleteABI(c *gin.Context) {
@@ -403,8 +570,58 @@ func (h *Handler) DeleteABI(c *gin.Context) {
 		return
 	}
 
-	// 调用服务层
-	if err := h.abiService.DeleteABI(c.Request.Context(), req.ID, walletAddress); err != nil {
+	h.deleteABI(c, walletAddress, req.ID, req.Force)
+}
+
+// DeleteABIByID 按ID删除ABI
+// @Summary 按ID删除ABI
+// @Description 删除用户创建的ABI，同时删除其函数选择器索引。不能删除平台共享的ABI。用户相关流程中仍有调用该ABI函数的流程时返回 409（ABI_IN_USE），确认后传 force=true 强制删除。删除操作是不可逆的。
+// @Tags ABI
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ABI ID"
+// @Param force query bool false "仍被流程使用时是否强制删除"
+// @Success 200 {object} types.APIResponse "ABI删除成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "无效的ABI ID"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权删除该ABI或尝试删除共享ABI"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "ABI不存在"
+// @Failure 409 {object} types.APIResponse{error=types.APIError} "ABI仍被流程使用（ABI_IN_USE）"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/{id} [delete]
+func (h *Handler) DeleteABIByID(c *gin.Context) {
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("DeleteABIByID Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid ABI id",
+			},
+		})
+		return
+	}
+	force, _ := strconv.ParseBool(c.Query("force"))
+
+	h.deleteABI(c, walletAddress, id, force)
+}
+
+// deleteABI 调用服务层删除ABI并输出结果
+func (h *Handler) deleteABI(c *gin.Context, walletAddress string, id int64, force bool) {
+	if err := h.abiService.DeleteABI(c.Request.Context(), id, walletAddress, force); err != nil {
 		var statusCode int
 		var errorCode string
 
@@ -418,6 +635,9 @@ func (h *Handler) DeleteABI(c *gin.Context) {
 		case errors.Is(err, abiService.ErrCannotDeleteShared):
 			statusCode = http.StatusForbidden
 			errorCode = "CANNOT_DELETE_SHARED_ABI"
+		case errors.Is(err, abiService.ErrABIInUse):
+			statusCode = http.StatusConflict
+			errorCode = "ABI_IN_USE"
 		default:
 			statusCode = http.StatusInternalServerError
 			errorCode = "INTERNAL_ERROR"
@@ -430,11 +650,11 @@ func (h *Handler) DeleteABI(c *gin.Context) {
 				Message: err.Error(),
 			},
 		})
-		logger.Error("DeleteABI Error:", err, "id", req.ID, "wallet_address", walletAddress)
+		logger.Error("DeleteABI Error:", err, "id", id, "wallet_address", walletAddress)
 		return
 	}
 
-	logger.Info("DeleteABI Success:", "id", req.ID, "wallet_address", walletAddress)
+	logger.Info("DeleteABI Success:", "id", id, "wallet_address", walletAddress, "force", force)
 	c.JSON(http.StatusOK, types.APIResponse{
 		Success: true,
 		Data:    gin.H{"message": "ABI deleted successfully"},
@@ -451,6 +671,7 @@ func (h *Handler) DeleteABI(c *gin.Context) {
 // @Param request body object{abi_content=string} true "验证ABI请求体"
 // @Success 200 {object} types.APIResponse{data=types.ABIValidationResult} "ABI验证完成"
 // @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 413 {object} types.APIResponse{error=types.APIError} "请求体或ABI内容超出大小限制（REQUEST_TOO_LARGE / ABI_TOO_LARGE）"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
 // @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
@@ -491,6 +712,10 @@ func (h *Handler) ValidateABI(c *gin.Context) {
 	// 调用服务层
 	result, err := h.abiService.ValidateABI(c.Request.Context(), req.ABIContent)
 	if err != nil {
+		if errors.Is(err, abiService.ErrABITooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, types.APIResponse{Success: false, Error: &types.APIError{Code: "ABI_TOO_LARGE", Message: err.Error()}})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
@@ -508,3 +733,334 @@ func (h *Handler) ValidateABI(c *gin.Context) {
 		Data:    result,
 	})
 }
+
+// DecodeBestEffort 尽力解码calldata
+// @Summary 尽力解码calldata
+// @Description 不指定ABI，按 calldata 的4字节选择器依次尝试：用户自己的ABI（confidence=high）、平台共享ABI（medium）、服务端配置的公共签名库（low，可能存在选择器碰撞，仅在前两者都无法解码时查询）。返回优先级最高的解码结果及来源，其他同样可以解码的候选放在 alternatives 中。
+// @Tags ABI
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.DecodeBestEffortRequest true "带4字节选择器的calldata"
+// @Success 200 {object} types.APIResponse{data=types.DecodeBestEffortResponse} "解码成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或calldata格式错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "没有可以解码该选择器的ABI或签名"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/decode-best-effort [post]
+func (h *Handler) DecodeBestEffort(c *gin.Context) {
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("DecodeBestEffort Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.DecodeBestEffortRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	result, err := h.abiService.DecodeBestEffort(c.Request.Context(), walletAddress, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, abiService.ErrSelectorNotInABI):
+			statusCode = http.StatusUnprocessableEntity
+			errorCode = "SELECTOR_NOT_IN_ABI"
+		case errors.Is(err, abiService.ErrInvalidCalldata):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_CALLDATA"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("DecodeBestEffort Error:", err, "wallet_address", walletAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// VerifyABIAgainstContract 校验ABI与已部署合约
+// @Summary 校验ABI与已部署合约
+// @Description 通过 eth_getCode 读取合约字节码，提取其中的4字节函数选择器，返回ABI中已实现（implemented）与缺失（missing）的函数。合约为 EIP-1167 最小代理或 EIP-1967 代理时扫描实现合约的字节码（proxy_type、code_address）。选择器由字节码中的 PUSH 立即数推断，implemented 可能存在极少量误报。
+// @Tags ABI
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.VerifyABIAgainstContractRequest true "ABI ID 与合约所在链、地址"
+// @Success 200 {object} types.APIResponse{data=types.VerifyABIAgainstContractResponse} "校验完成"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误、合约地址无效或ABI无效"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权访问该ABI"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "ABI不存在或该地址没有合约代码"
+// @Failure 503 {object} types.APIResponse{error=types.APIError} "RPC连接失败"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/verify-against-contract [post]
+func (h *Handler) VerifyABIAgainstContract(c *gin.Context) {
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("VerifyABIAgainstContract Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.VerifyABIAgainstContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	result, err := h.abiService.VerifyABIAgainstContract(c.Request.Context(), walletAddress, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, abiService.ErrABINotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "ABI_NOT_FOUND"
+		case errors.Is(err, abiService.ErrAccessDenied):
+			statusCode = http.StatusForbidden
+			errorCode = "ACCESS_DENIED"
+		case errors.Is(err, abiService.ErrInvalidABI):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_ABI"
+		case errors.Is(err, abiService.ErrInvalidContractAddress):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_CONTRACT_ADDRESS"
+		case errors.Is(err, abiService.ErrContractNotDeployed):
+			statusCode = http.StatusNotFound
+			errorCode = "CONTRACT_NOT_DEPLOYED"
+		case errors.Is(err, abiService.ErrRPCConnection):
+			statusCode = http.StatusServiceUnavailable
+			errorCode = "RPC_CONNECTION_ERROR"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("VerifyABIAgainstContract Error:", err, "abi_id", req.ABIID, "chain_id", req.ChainID, "wallet_address", walletAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// DecodeCalldata 解码任意calldata
+// @Summary 解码calldata
+// @Description 使用指定ABI（abi_id 或 abi_content）解码任意calldata。function_signature 为空时 calldata_hex 需带4字节函数选择器；非空时按 Compound 风格解析（签名 + 不含选择器的参数数据），此时ABI可选，提供时用于补全参数名。
+// @Tags ABI
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.DecodeCalldataRequest true "解码请求体"
+// @Success 200 {object} types.APIResponse{data=types.DecodedCalldata} "解码成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误、ABI无效或calldata无法解码"
+// @Failure 413 {object} types.APIResponse{error=types.APIError} "请求体或ABI内容超出大小限制（REQUEST_TOO_LARGE / ABI_TOO_LARGE）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权访问该ABI"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "ABI不存在"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "函数选择器不在ABI中"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/decode [post]
+func (h *Handler) DecodeCalldata(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("DecodeCalldata Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.DecodeCalldataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("DecodeCalldata Error:", errors.New("invalid request parameters"), "error", err)
+		return
+	}
+
+	// 调用服务层
+	result, err := h.abiService.DecodeCalldata(c.Request.Context(), walletAddress, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, abiService.ErrABINotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "ABI_NOT_FOUND"
+		case errors.Is(err, abiService.ErrAccessDenied):
+			statusCode = http.StatusForbidden
+			errorCode = "ACCESS_DENIED"
+		case errors.Is(err, abiService.ErrSelectorNotInABI):
+			statusCode = http.StatusUnprocessableEntity
+			errorCode = "SELECTOR_NOT_IN_ABI"
+		case errors.Is(err, abiService.ErrInvalidABI):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_ABI"
+		case errors.Is(err, abiService.ErrABITooLarge):
+			statusCode = http.StatusRequestEntityTooLarge
+			errorCode = "ABI_TOO_LARGE"
+		case errors.Is(err, abiService.ErrInvalidCalldata):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_CALLDATA"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("DecodeCalldata Error:", err, "abi_id", req.ABIID, "wallet_address", walletAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// RedecodeFlows 使用ABI重新解码历史流程
+// @Summary 使用ABI重新解码历史流程
+// @Description 上传目标合约的ABI后，用该ABI重新解析用户相关流程中调用了其函数的calldata（Compound 按函数签名、OpenZeppelin 按函数选择器），解码结果写入按用户隔离的缓存，并在流程列表的 decoded_calls 中返回。每种标准单次最多处理最近的 1000 个流程。
+// @Tags ABI
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ABI ID"
+// @Success 200 {object} types.APIResponse{data=types.RedecodeFlowsResponse} "重新解码完成"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "无效的ABI ID"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权访问该ABI"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "ABI不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/abi/{id}/redecode [post]
+func (h *Handler) RedecodeFlows(c *gin.Context) {
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("RedecodeFlows Error:", errors.New("user not authenticated"))
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid ABI id",
+			},
+		})
+		return
+	}
+
+	response, err := h.abiService.RedecodeFlows(c.Request.Context(), id, walletAddress)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, abiService.ErrABINotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "ABI_NOT_FOUND"
+		case errors.Is(err, abiService.ErrAccessDenied):
+			statusCode = http.StatusForbidden
+			errorCode = "ACCESS_DENIED"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("RedecodeFlows Error:", err, "id", id, "wallet_address", walletAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -0,0 +1,740 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"timelocker-backend/internal/middleware"
+	"timelocker-backend/internal/service/admin"
+	"timelocker-backend/internal/service/auth"
+	"timelocker-backend/internal/service/chain"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 管理员处理器
+type AdminHandler struct {
+	adminService   admin.AdminService
+	authService    auth.Service
+	adminAddresses []string
+	maintenance    *middleware.MaintenanceState
+}
+
+// NewAdminHandler 创建管理员处理器
+func NewAdminHandler(adminService admin.AdminService, authService auth.Service, adminAddresses []string, maintenance *middleware.MaintenanceState) *AdminHandler {
+	return &AdminHandler{
+		adminService:   adminService,
+		authService:    authService,
+		adminAddresses: adminAddresses,
+		maintenance:    maintenance,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *AdminHandler) RegisterRoutes(router *gin.RouterGroup) {
+	adminGroup := router.Group("/admin")
+	adminGroup.Use(middleware.AuthMiddleware(h.authService), middleware.RequireWriteScope(), middleware.RequireAdmin(h.adminAddresses))
+	{
+		// 重发流程通知
+		// POST /api/v1/admin/flows/resend-notification
+		// http://localhost:8080/api/v1/admin/flows/resend-notification
+		adminGroup.POST("/flows/resend-notification", h.ResendFlowNotification)
+		// 模拟流程状态变化通知（用于测试通知链路）
+		// POST /api/v1/admin/flows/simulate-transition
+		// http://localhost:8080/api/v1/admin/flows/simulate-transition
+		adminGroup.POST("/flows/simulate-transition", h.SimulateFlowTransition)
+		// 手动设置流程状态
+		// POST /api/v1/admin/flows/set-status
+		// http://localhost:8080/api/v1/admin/flows/set-status
+		adminGroup.POST("/flows/set-status", h.SetFlowStatus)
+		// 查询全部流程
+		// GET /api/v1/admin/flows
+		// http://localhost:8080/api/v1/admin/flows?chain_id=1&standard=compound&status=waiting&initiator=0x...
+		adminGroup.GET("/flows", h.ListFlows)
+		// 汇总合约已被删除的孤立流程
+		// GET /api/v1/admin/flows/orphaned
+		// http://localhost:8080/api/v1/admin/flows/orphaned
+		adminGroup.GET("/flows/orphaned", h.GetOrphanedFlows)
+		// 获取维护模式状态
+		// GET /api/v1/admin/maintenance
+		// http://localhost:8080/api/v1/admin/maintenance
+		adminGroup.GET("/maintenance", h.GetMaintenanceMode)
+		// 切换维护模式
+		// POST /api/v1/admin/maintenance
+		// http://localhost:8080/api/v1/admin/maintenance
+		adminGroup.POST("/maintenance", h.SetMaintenanceMode)
+		// 设置/轮换链的区块浏览器 API Key
+		// POST /api/v1/admin/chains/explorer-api-key
+		// http://localhost:8080/api/v1/admin/chains/explorer-api-key
+		adminGroup.POST("/chains/explorer-api-key", h.SetExplorerAPIKey)
+		// 设置链的 Webhook 事件确认深度
+		// POST /api/v1/admin/chains/confirmation-depth
+		// http://localhost:8080/api/v1/admin/chains/confirmation-depth
+		adminGroup.POST("/chains/confirmation-depth", h.SetConfirmationDepth)
+		// 立即自检所有链的 subgraph
+		// GET /api/v1/admin/subgraphs/check
+		// http://localhost:8080/api/v1/admin/subgraphs/check
+		adminGroup.GET("/subgraphs/check", h.CheckSubgraphs)
+		// 查询 error_logs（可按级别过滤）
+		// GET /api/v1/admin/error-logs
+		// http://localhost:8080/api/v1/admin/error-logs?level=warn&keyword=rpc&page=1&page_size=20
+		adminGroup.GET("/error-logs", h.ListErrorLogs)
+		// 按用户或合约立即重算流程状态
+		// POST /api/v1/admin/recalc-status
+		// http://localhost:8080/api/v1/admin/recalc-status
+		adminGroup.POST("/recalc-status", h.RecalcFlowStatus)
+	}
+}
+
+// ResendFlowNotification 重发流程通知
+// @Summary 重发流程通知（管理员）
+// @Description 清除指定流程某一状态的通知去重记录（notification_logs / email_send_logs），并重新发送该状态的通知
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.ResendFlowNotificationRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.ResendFlowNotificationResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/flows/resend-notification [post]
+func (h *AdminHandler) ResendFlowNotification(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.ResendFlowNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.ResendFlowNotification(c.Request.Context(), adminAddress, &req)
+	if err != nil {
+		if errors.Is(err, admin.ErrFlowNotFound) {
+			c.JSON(http.StatusNotFound, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "FLOW_NOT_FOUND",
+					Message: "Flow not found",
+				},
+			})
+			return
+		}
+		logger.Error("ResendFlowNotification Error: ", err, "admin", adminAddress, "flow_id", req.FlowID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to resend flow notification",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SimulateFlowTransition 模拟流程状态变化通知
+// @Summary 模拟流程状态变化通知（管理员）
+// @Description 按指定的状态变化（如 ready→expired）走一遍完整的邮件与渠道通知流程，用于测试通知链路而无需等待真实的宽限期。不修改流程状态；消息标记为 [SIMULATED]；去重与发送记录只保存在本次请求的沙箱内，不读写真实的 notification_logs / email_send_logs，可重复调用
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.SimulateFlowTransitionRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.SimulateFlowTransitionResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/flows/simulate-transition [post]
+func (h *AdminHandler) SimulateFlowTransition(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.SimulateFlowTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.SimulateFlowTransition(c.Request.Context(), adminAddress, &req)
+	if err != nil {
+		if errors.Is(err, admin.ErrFlowNotFound) {
+			c.JSON(http.StatusNotFound, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "FLOW_NOT_FOUND",
+					Message: "Flow not found",
+				},
+			})
+			return
+		}
+		logger.Error("SimulateFlowTransition Error: ", err, "admin", adminAddress, "flow_id", req.FlowID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to simulate flow transition",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SetFlowStatus 手动设置流程状态
+// @Summary 手动设置流程状态（管理员）
+// @Description 恢复因漏掉事件而卡住的流程：按合法的状态流转设置流程状态，并在状态历史中记录操作人与原因；verify=true 时先向 Goldsky（Compound）或链上（OpenZeppelin）确认目标状态。不会发送通知，需要时可调用重发通知接口
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.SetFlowStatusRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.SetFlowStatusResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 409 {object} types.APIResponse{error=types.APIError} "当前状态不一致或目标状态未被确认"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "非法的状态流转"
+// @Failure 503 {object} types.APIResponse{error=types.APIError} "无法向数据源确认状态"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/flows/set-status [post]
+func (h *AdminHandler) SetFlowStatus(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.SetFlowStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.SetFlowStatus(c.Request.Context(), adminAddress, &req)
+	if err != nil {
+		status, code, message := http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to set flow status"
+		switch {
+		case errors.Is(err, admin.ErrFlowNotFound):
+			status, code, message = http.StatusNotFound, "FLOW_NOT_FOUND", "Flow not found"
+		case errors.Is(err, admin.ErrIllegalStatusTransition):
+			status, code, message = http.StatusUnprocessableEntity, "ILLEGAL_STATUS_TRANSITION", "Illegal flow status transition"
+		case errors.Is(err, admin.ErrFlowStatusMismatch):
+			status, code, message = http.StatusConflict, "FLOW_STATUS_MISMATCH", "Flow current status does not match status_from"
+		case errors.Is(err, admin.ErrStatusNotConfirmed):
+			status, code, message = http.StatusConflict, "STATUS_NOT_CONFIRMED", "Target status not confirmed by data source"
+		case errors.Is(err, admin.ErrVerificationUnavailable):
+			status, code, message = http.StatusServiceUnavailable, "VERIFICATION_UNAVAILABLE", "Unable to verify flow status"
+		default:
+			logger.Error("SetFlowStatus Error: ", err, "admin", adminAddress, "flow_id", req.FlowID)
+		}
+		c.JSON(status, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    code,
+				Message: message,
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ListFlows 查询全部流程
+// @Summary 查询全部流程（管理员）
+// @Description 直接查询 Compound 与 OpenZeppelin 两张流程表，不关联用户与合约的关系，用于运维排查；支持按链、标准、状态、合约地址与创建时间范围过滤，按创建时间倒序分页，并附带合约备注与创建者
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param standard query string false "标准compound, openzeppelin"
+// @Param chain_id query int false "链ID"
+// @Param status query string false "状态all, waiting, ready, executed, cancelled, expired"
+// @Param contract_address query string false "合约地址"
+// @Param initiator query string false "发起人地址（不区分大小写）"
+// @Param created_from query string false "创建时间下限（含），RFC3339"
+// @Param created_to query string false "创建时间上限（不含），RFC3339"
+// @Param include_archived query bool false "是否包含已归档的终态流程"
+// @Param page query int false "页码，默认为1"
+// @Param page_size query int false "每页大小，默认为20，最大100"
+// @Success 200 {object} types.APIResponse{data=types.GetAdminFlowListResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/flows [get]
+func (h *AdminHandler) ListFlows(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.GetAdminFlowListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.ListFlows(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, admin.ErrInvalidFlowListFilter) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_PARAMS",
+					Message: "Invalid request parameters",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		logger.Error("ListFlows Error: ", err, "admin", adminAddress)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to list flows",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetMaintenanceMode 获取维护模式状态
+// @Summary 获取维护模式状态（管理员）
+// @Description 返回当前是否处于维护模式以及最后一次切换的操作人与时间
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.MaintenanceStatus}
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Router /api/v1/admin/maintenance [get]
+func (h *AdminHandler) GetMaintenanceMode(c *gin.Context) {
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    h.maintenance.Status(),
+	})
+}
+
+// SetMaintenanceMode 切换维护模式
+// @Summary 切换维护模式（管理员）
+// @Description 开启后所有写接口（PUT/PATCH/DELETE 以及需要写权限的 POST 接口）返回 503，查询接口、健康检查与管理员接口不受影响；仅在当前进程生效，重启后恢复为配置值
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.SetMaintenanceModeRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.MaintenanceStatus}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Router /api/v1/admin/maintenance [post]
+func (h *AdminHandler) SetMaintenanceMode(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.SetMaintenanceModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	status := h.maintenance.Set(*req.Enabled, req.Message, strings.ToLower(adminAddress))
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// SetExplorerAPIKey 设置/轮换链的区块浏览器 API Key
+// @Summary 设置/轮换区块浏览器 API Key（管理员）
+// @Description 为指定链设置新的区块浏览器 API Key（立即生效并清除该 key 的限流状态）；api_key 为空时清除，回退到配置中的默认 key。响应只返回脱敏后的 key
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.SetExplorerAPIKeyRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.ExplorerAPIKeyStatus}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "链不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/chains/explorer-api-key [post]
+func (h *AdminHandler) SetExplorerAPIKey(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.SetExplorerAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.SetExplorerAPIKey(c.Request.Context(), adminAddress, &req)
+	if err != nil {
+		if errors.Is(err, chain.ErrChainNotFound) {
+			c.JSON(http.StatusNotFound, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "CHAIN_NOT_FOUND",
+					Message: "Chain not found",
+				},
+			})
+			return
+		}
+		logger.Error("SetExplorerAPIKey Error: ", err, "admin", adminAddress, "chain_id", req.ChainID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to set explorer api key",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SetConfirmationDepth 设置链的 Webhook 事件确认深度
+// @Summary 设置链的 Webhook 事件确认深度（管理员）
+// @Description Goldsky webhook 事件所在区块达到指定确认数后才创建/更新流程，未达到时暂存等待；0 表示收到即处理。对易重组的链建议设置适当的确认数
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.SetConfirmationDepthRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.ConfirmationDepthStatus}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "链不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/chains/confirmation-depth [post]
+func (h *AdminHandler) SetConfirmationDepth(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.SetConfirmationDepthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.SetConfirmationDepth(c.Request.Context(), adminAddress, &req)
+	if err != nil {
+		if errors.Is(err, chain.ErrChainNotFound) {
+			c.JSON(http.StatusNotFound, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "CHAIN_NOT_FOUND",
+					Message: "Chain not found",
+				},
+			})
+			return
+		}
+		logger.Error("SetConfirmationDepth Error: ", err, "admin", adminAddress, "chain_id", req.ChainID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to set confirmation depth",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetOrphanedFlows 汇总孤立流程
+// @Summary 汇总孤立流程（管理员）
+// @Description 按合约汇总热表中合约已被所有导入者删除的流程（用户流程列表中不再显示，管理员流程列表中标记 contract_removed），定时任务按 orphaned_flows.archive_after 将其归档
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.GetOrphanedFlowsResponse}
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/flows/orphaned [get]
+func (h *AdminHandler) GetOrphanedFlows(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	response, err := h.adminService.GetOrphanedFlows(c.Request.Context())
+	if err != nil {
+		logger.Error("GetOrphanedFlows error", err, "admin", adminAddress)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get orphaned flows",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// CheckSubgraphs 自检所有链的 subgraph
+// @Summary 自检 subgraph（管理员）
+// @Description 对每条已配置 subgraph 的链执行一次轻量查询，返回是否可达、schema 是否兼容、已索引区块与失败原因；自检失败的链在同步循环中跳过，直到再次自检通过
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.CheckSubgraphsResponse}
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 503 {object} types.APIResponse{error=types.APIError} "Goldsky 服务未配置"
+// @Router /api/v1/admin/subgraphs/check [get]
+func (h *AdminHandler) CheckSubgraphs(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	response, err := h.adminService.CheckSubgraphs(c.Request.Context(), adminAddress)
+	if err != nil {
+		if errors.Is(err, admin.ErrGoldskyUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "GOLDSKY_UNAVAILABLE",
+					Message: "Goldsky service is not configured",
+				},
+			})
+			return
+		}
+		logger.Error("CheckSubgraphs error", err, "admin", adminAddress)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to check subgraphs",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ListErrorLogs 查询 error_logs
+// @Summary 查询错误日志（管理员）
+// @Description 分页查询写入数据库的日志，按时间倒序；默认只记录 error/fatal，server.db_log_level 配置为 warn 时也记录警告。支持按级别、调用位置前缀、函数名、关键字与时间范围过滤
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param level query string false "日志级别error, fatal, warn"
+// @Param caller query string false "调用位置（文件:行号）前缀"
+// @Param function query string false "函数名"
+// @Param keyword query string false "消息或错误信息关键字（不区分大小写）"
+// @Param from query string false "时间下限（含），RFC3339"
+// @Param to query string false "时间上限（不含），RFC3339"
+// @Param page query int false "页码，默认为1"
+// @Param page_size query int false "每页大小，默认为20，最大100"
+// @Success 200 {object} types.APIResponse{data=types.GetAdminErrorLogListResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/admin/error-logs [get]
+func (h *AdminHandler) ListErrorLogs(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.GetAdminErrorLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.ListErrorLogs(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, admin.ErrInvalidErrorLogFilter) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_PARAMS",
+					Message: "Invalid request parameters",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		logger.Error("ListErrorLogs Error: ", err, "admin", adminAddress)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to list error logs",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// RecalcFlowStatus 立即重算流程状态
+// @Summary 立即重算流程状态（管理员）
+// @Description 按用户（user_address）或合约（chain_id + contract_address）范围，立即对已到期的 Compound 流程执行与定时检查相同的状态计算（waiting->ready、ready->expired），变更写库并发送通知；单次最多处理 100 个流程，truncated 为 true 时需再次调用
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.RecalcFlowStatusRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.RecalcFlowStatusResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Failure 503 {object} types.APIResponse{error=types.APIError} "Goldsky 服务未配置"
+// @Router /api/v1/admin/recalc-status [post]
+func (h *AdminHandler) RecalcFlowStatus(c *gin.Context) {
+	_, adminAddress, _ := middleware.GetUserFromContext(c)
+
+	var req types.RecalcFlowStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.adminService.RecalcFlowStatus(c.Request.Context(), adminAddress, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidRecalcScope):
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_PARAMS",
+					Message: "Invalid request parameters",
+					Details: err.Error(),
+				},
+			})
+		case errors.Is(err, admin.ErrGoldskyUnavailable):
+			c.JSON(http.StatusServiceUnavailable, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "GOLDSKY_UNAVAILABLE",
+					Message: "Goldsky service is not configured",
+				},
+			})
+		default:
+			logger.Error("RecalcFlowStatus error", err, "admin", adminAddress)
+			c.JSON(http.StatusInternalServerError, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INTERNAL_ERROR",
+					Message: "Failed to recalculate flow status",
+					Details: err.Error(),
+				},
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"timelocker-backend/internal/middleware"
+	"timelocker-backend/internal/service/audit"
+	"timelocker-backend/internal/service/auth"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 审计日志处理器
+type Handler struct {
+	auditService audit.Service
+	authService  auth.Service
+	admins       map[string]struct{}
+}
+
+// NewHandler 创建审计日志处理器
+func NewHandler(auditService audit.Service, authService auth.Service, adminAddresses []string) *Handler {
+	admins := make(map[string]struct{}, len(adminAddresses))
+	for _, addr := range adminAddresses {
+		if addr = strings.ToLower(strings.TrimSpace(addr)); addr != "" {
+			admins[addr] = struct{}{}
+		}
+	}
+	return &Handler{
+		auditService: auditService,
+		authService:  authService,
+		admins:       admins,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	auditGroup := router.Group("/audit", middleware.AuthMiddleware(h.authService))
+	{
+		// 查询配置变更审计日志（普通用户只能查自己的，管理员可查全部）
+		// GET /api/v1/audit
+		// http://localhost:8080/api/v1/audit?target_type=notification_config&action=update&page=1&page_size=20
+		auditGroup.GET("", h.ListAuditLogs)
+	}
+}
+
+// ListAuditLogs 查询审计日志
+// @Summary 查询配置变更审计日志
+// @Description 查询通知配置、timelock 合约、邮箱、API令牌的创建/更新/删除记录，按时间倒序分页。普通用户只能查询自己的操作记录；管理员可通过 actor 指定操作人，不指定时查询全部
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param actor query string false "操作人地址（仅管理员可指定他人）"
+// @Param target_type query string false "目标类型notification_config, timelock, email, api_token"
+// @Param action query string false "动作create, update, delete"
+// @Param page query int false "页码，默认为1"
+// @Param page_size query int false "每页大小，默认为20，最大100"
+// @Success 200 {object} types.APIResponse{data=types.GetAuditLogsResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非管理员查询他人的审计日志"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/audit [get]
+func (h *Handler) ListAuditLogs(c *gin.Context) {
+	_, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	var req types.GetAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	_, isAdmin := h.admins[strings.ToLower(walletAddress)]
+	response, err := h.auditService.ListAuditLogs(c.Request.Context(), walletAddress, isAdmin, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, audit.ErrInvalidAuditFilter):
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_PARAMS",
+					Message: "Invalid request parameters",
+					Details: err.Error(),
+				},
+			})
+		case errors.Is(err, audit.ErrAuditAccessDenied):
+			c.JSON(http.StatusForbidden, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "ACCESS_DENIED",
+					Message: "Only admins can view other users' audit logs",
+				},
+			})
+		default:
+			logger.Error("ListAuditLogs Error: ", err, "wallet_address", walletAddress)
+			c.JSON(http.StatusInternalServerError, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INTERNAL_ERROR",
+					Message: "Failed to list audit logs",
+					Details: err.Error(),
+				},
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -49,6 +49,19 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		// POST /api/v1/auth/profile
 		// http://localhost:8080/api/v1/auth/profile
 		authGroup.POST("/profile", middleware.AuthMiddleware(h.authService), h.GetProfile)
+
+		// API令牌管理（API令牌本身不能再创建/吊销令牌）
+		// POST /api/v1/auth/api-tokens/create
+		// http://localhost:8080/api/v1/auth/api-tokens/create
+		authGroup.POST("/api-tokens/create", middleware.AuthMiddleware(h.authService), middleware.RequireWriteScope(), h.CreateAPIToken)
+
+		// POST /api/v1/auth/api-tokens/list
+		// http://localhost:8080/api/v1/auth/api-tokens/list
+		authGroup.POST("/api-tokens/list", middleware.AuthMiddleware(h.authService), h.ListAPITokens)
+
+		// POST /api/v1/auth/api-tokens/revoke
+		// http://localhost:8080/api/v1/auth/api-tokens/revoke
+		authGroup.POST("/api-tokens/revoke", middleware.AuthMiddleware(h.authService), middleware.RequireWriteScope(), h.RevokeAPIToken)
 	}
 }
 
@@ -112,7 +125,7 @@ func (h *Handler) GetNonce(c *gin.Context) {
 
 // WalletConnect 钱包连接认证
 // @Summary 钱包连接认证（支持EOA和Safe钱包）
-// @Description 通过钱包进行用户认证。EOA钱包：1.先调用/auth/nonce获取随机nonce和消息 2.让用户对消息进行签名 3.调用此接口完成认证。Safe钱包：直接提供Safe地址和chain_id即可，系统会验证地址是否为有效的Safe合约。
+// @Description 通过钱包进行用户认证。EOA钱包：1.先调用/auth/nonce获取随机nonce和消息 2.让用户对消息进行签名 3.调用此接口完成认证。Safe钱包：同样需要先获取nonce并提供Safe地址、chain_id、消息与签名，签名按 EIP-1271 由 Safe 合约校验；或同时提供signer_address由所有者签名，此时会从链上确认签名者仍为 Safe 当前所有者并刷新所有者与阈值。不接受未签名的 Safe 登录。
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -120,6 +133,7 @@ func (h *Handler) GetNonce(c *gin.Context) {
 // @Success 200 {object} types.APIResponse{data=types.WalletConnectResponse} "认证成功，返回访问令牌和用户信息"
 // @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_WALLET_ADDRESS: 钱包地址格式无效; MISSING_REQUIRED_FIELDS: EOA钱包缺少必需字段; INVALID_SAFE_CONTRACT: 地址不是有效的Safe合约"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "认证失败 - INVALID_SIGNATURE: 签名验证失败; SIGNATURE_RECOVERY_FAILED: 无法从签名恢复地址; INVALID_NONCE: nonce无效或已过期; NONCE_ALREADY_USED: nonce已被使用"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "NOT_SAFE_OWNER: 签名者不是 Safe 当前所有者"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 服务器内部错误; DATABASE_ERROR: 数据库操作失败; TOKEN_GENERATION_FAILED: JWT令牌生成失败"
 // @Router /api/v1/auth/wallet-connect [post]
 func (h *Handler) WalletConnect(c *gin.Context) {
@@ -146,22 +160,25 @@ func (h *Handler) WalletConnect(c *gin.Context) {
 
 		// 根据错误类型和消息内容确定具体的错误码
 		switch {
-		case err == auth.ErrInvalidAddress:
+		case errors.Is(err, auth.ErrInvalidAddress):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_WALLET_ADDRESS"
-		case err == auth.ErrInvalidSignature:
+		case errors.Is(err, auth.ErrInvalidSignature):
 			statusCode = http.StatusUnauthorized
 			errorCode = "INVALID_SIGNATURE"
-		case err == auth.ErrSignatureRecovery:
+		case errors.Is(err, auth.ErrSignatureRecovery):
 			statusCode = http.StatusUnauthorized
 			errorCode = "SIGNATURE_RECOVERY_FAILED"
-		case err == auth.ErrInvalidNonce:
+		case errors.Is(err, auth.ErrInvalidNonce):
 			statusCode = http.StatusUnauthorized
 			errorCode = "INVALID_NONCE"
-		case err == auth.ErrNonceUsed:
+		case errors.Is(err, auth.ErrNonceUsed):
 			statusCode = http.StatusUnauthorized
 			errorCode = "NONCE_ALREADY_USED"
-		case strings.Contains(err.Error(), "EOA wallet requires"):
+		case errors.Is(err, auth.ErrNotSafeOwner):
+			statusCode = http.StatusForbidden
+			errorCode = "NOT_SAFE_OWNER"
+		case strings.Contains(err.Error(), "EOA wallet requires"), strings.Contains(err.Error(), "signed Safe login requires"):
 			statusCode = http.StatusBadRequest
 			errorCode = "MISSING_REQUIRED_FIELDS"
 		case strings.Contains(err.Error(), "not a valid Safe contract"):
@@ -320,3 +337,189 @@ func (h *Handler) GetProfile(c *gin.Context) {
 		Data:    profile,
 	})
 }
+
+// CreateAPIToken 创建API令牌
+// @Summary 创建API令牌
+// @Description 为当前用户创建一个长期有效的只读API令牌，供脚本通过 X-API-Key 请求头访问查询类接口。明文令牌只在本次响应中返回，服务端仅保存哈希。
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.CreateAPITokenRequest true "创建API令牌请求体"
+// @Success 200 {object} types.APIResponse{data=types.CreateAPITokenResponse} "成功创建API令牌"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "只读API令牌无权操作"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/auth/api-tokens/create [post]
+func (h *Handler) CreateAPIToken(c *gin.Context) {
+	userID, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("CreateAPIToken Error: ", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("CreateAPIToken Error: ", errors.New("invalid request parameters"), "error: ", err)
+		return
+	}
+
+	response, err := h.authService.CreateAPIToken(c.Request.Context(), userID, walletAddress, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "INTERNAL_ERROR"
+		if strings.Contains(err.Error(), "name is required") {
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_REQUEST"
+		}
+		logger.Error("CreateAPIToken Error: ", err, "errorCode: ", errorCode)
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	logger.Info("CreateAPIToken: ", "User: ", walletAddress, "token_id", response.APIToken.ID)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ListAPITokens 获取API令牌列表
+// @Summary 获取API令牌列表
+// @Description 获取当前用户创建的所有API令牌（不含明文，包含已吊销的令牌）。
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.APITokenListResponse} "成功获取API令牌列表"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/auth/api-tokens/list [post]
+func (h *Handler) ListAPITokens(c *gin.Context) {
+	userID, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("ListAPITokens Error: ", errors.New("user not authenticated"))
+		return
+	}
+
+	response, err := h.authService.ListAPITokens(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("ListAPITokens Error: ", err)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	logger.Info("ListAPITokens: ", "User: ", walletAddress, "total", response.Total)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// RevokeAPIToken 吊销API令牌
+// @Summary 吊销API令牌
+// @Description 吊销当前用户的指定API令牌，吊销后立即失效。
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.RevokeAPITokenRequest true "吊销API令牌请求体"
+// @Success 200 {object} types.APIResponse "成功吊销API令牌"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "只读API令牌无权操作"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "API令牌不存在或已吊销"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/auth/api-tokens/revoke [post]
+func (h *Handler) RevokeAPIToken(c *gin.Context) {
+	userID, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("RevokeAPIToken Error: ", errors.New("user not authenticated"))
+		return
+	}
+
+	var req types.RevokeAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("RevokeAPIToken Error: ", errors.New("invalid request parameters"), "error: ", err)
+		return
+	}
+
+	if err := h.authService.RevokeAPIToken(c.Request.Context(), userID, req.ID); err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch err {
+		case auth.ErrAPITokenNotFound:
+			statusCode = http.StatusNotFound
+			errorCode = "API_TOKEN_NOT_FOUND"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+		logger.Error("RevokeAPIToken Error: ", err, "errorCode: ", errorCode)
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	logger.Info("RevokeAPIToken: ", "User: ", walletAddress, "token_id", req.ID)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    gin.H{"message": "API token revoked successfully"},
+	})
+}
@@ -1,6 +1,7 @@
 package email
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"timelocker-backend/internal/middleware"
@@ -40,21 +41,39 @@ func (h *EmailHandler) RegisterRoutes(router *gin.RouterGroup) {
 		// 更新邮箱备注
 		// POST /api/v1/emails/remark
 		// http://localhost:8080/api/v1/emails/remark
-		emailGroup.POST("/remark", h.UpdateEmailRemark)
+		emailGroup.POST("/remark", middleware.RequireWriteScope(), h.UpdateEmailRemark)
 		// 删除邮箱
 		// POST /api/v1/emails/delete
 		// http://localhost:8080/api/v1/emails/delete
-		emailGroup.POST("/delete", h.DeleteEmail)
+		emailGroup.POST("/delete", middleware.RequireWriteScope(), h.DeleteEmail)
+		// 设置邮箱接收通知的状态过滤
+		// POST /api/v1/emails/notify-statuses
+		// http://localhost:8080/api/v1/emails/notify-statuses
+		emailGroup.POST("/notify-statuses", middleware.RequireWriteScope(), h.UpdateEmailNotifyStatuses)
 
 		// 邮箱验证
 		// 发送验证码
 		// POST /api/v1/emails/send-verification
 		// http://localhost:8080/api/v1/emails/send-verification
-		emailGroup.POST("/send-verification", h.SendVerificationCode)
+		emailGroup.POST("/send-verification", middleware.RequireWriteScope(), h.SendVerificationCode)
+		// 为全部未验证邮箱重新发送验证码
+		// POST /api/v1/emails/resend-all
+		// http://localhost:8080/api/v1/emails/resend-all
+		emailGroup.POST("/resend-all", middleware.RequireWriteScope(), h.ResendAllVerificationCodes)
 		// 验证邮箱
 		// POST /api/v1/emails/verify
 		// http://localhost:8080/api/v1/emails/verify
-		emailGroup.POST("/verify", h.VerifyEmail)
+		emailGroup.POST("/verify", middleware.RequireWriteScope(), h.VerifyEmail)
+
+		// 邮件偏好
+		// 获取邮件偏好
+		// POST /api/v1/emails/preferences
+		// http://localhost:8080/api/v1/emails/preferences
+		emailGroup.POST("/preferences", h.GetEmailPreferences)
+		// 更新邮件偏好
+		// POST /api/v1/emails/preferences/update
+		// http://localhost:8080/api/v1/emails/preferences/update
+		emailGroup.POST("/preferences/update", middleware.RequireWriteScope(), h.UpdateEmailPreferences)
 	}
 }
 
@@ -92,12 +111,6 @@ func (h *EmailHandler) GetEmails(c *gin.Context) {
 	req := types.GetEmailsRequest{Page: 1, PageSize: 10}
 	_ = c.ShouldBindQuery(&req)
 	_ = c.ShouldBindJSON(&req)
-	if req.Page <= 0 {
-		req.Page = 1
-	}
-	if req.PageSize <= 0 || req.PageSize > 100 {
-		req.PageSize = 10
-	}
 
 	result, err := h.emailService.GetUserEmails(c.Request.Context(), userIDInt, req.Page, req.PageSize)
 	if err != nil {
@@ -226,6 +239,63 @@ func (h *EmailHandler) DeleteEmail(c *gin.Context) {
 	})
 }
 
+// UpdateEmailNotifyStatuses 设置邮箱接收通知的状态过滤
+// @Summary 设置邮箱通知状态过滤
+// @Description 设置某个邮箱只接收指定目标状态的流程通知（如个人邮箱只收 ready，团队邮箱只收 expired）；notify_statuses 为空表示接收全部状态
+// @Tags Email
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.UpdateEmailNotifyStatusesRequest true "状态过滤请求（包含ID）"
+// @Success 200 {object} types.APIResponse
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未授权"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "邮箱不存在"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "状态值无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/emails/notify-statuses [post]
+func (h *EmailHandler) UpdateEmailNotifyStatuses(c *gin.Context) {
+	// 获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{Success: false, Error: &types.APIError{Code: "UNAUTHORIZED", Message: "User not authenticated"}})
+		return
+	}
+
+	userIDInt, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Invalid user ID format"}})
+		return
+	}
+
+	var req types.UpdateEmailNotifyStatusesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", err)
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_REQUEST", Message: "Invalid request body", Details: err.Error()}})
+		return
+	}
+
+	statuses, err := h.emailService.UpdateEmailNotifyStatuses(c.Request.Context(), req.ID, userIDInt, req.NotifyStatuses)
+	if err != nil {
+		if errors.Is(err, email.ErrUserEmailNotFound) {
+			c.JSON(http.StatusNotFound, types.APIResponse{Success: false, Error: &types.APIError{Code: "EMAIL_NOT_FOUND", Message: "Email not found"}})
+			return
+		}
+		if errors.Is(err, email.ErrInvalidNotifyStatus) {
+			c.JSON(http.StatusUnprocessableEntity, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_NOTIFY_STATUS", Message: "Invalid notify status", Details: err.Error()}})
+			return
+		}
+		logger.Error("Failed to update email notify statuses", err, "userID", userIDInt, "userEmailID", req.ID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Failed to update email notify statuses", Details: err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    gin.H{"notify_statuses": statuses},
+	})
+}
+
 // ===== 邮箱验证相关API =====
 
 // SendVerificationCode 发送验证码
@@ -310,6 +380,48 @@ func (h *EmailHandler) SendVerificationCode(c *gin.Context) {
 	})
 }
 
+// ResendAllVerificationCodes 为全部未验证邮箱重新发送验证码
+// @Summary 为全部未验证邮箱重新发送验证码
+// @Description 为当前用户所有未验证且可投递的邮箱生成并发送新的验证码，返回每个邮箱的结果（sent / rate_limited / undeliverable / failed）。已验证的邮箱不会重发；每个邮箱仍受 1 分钟的重发间隔限制，同一用户 1 分钟内只能调用一次
+// @Tags Email
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.ResendAllVerificationResponse}
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未授权"
+// @Failure 429 {object} types.APIResponse{error=types.APIError} "调用过于频繁"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/emails/resend-all [post]
+func (h *EmailHandler) ResendAllVerificationCodes(c *gin.Context) {
+	// 获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{Success: false, Error: &types.APIError{Code: "UNAUTHORIZED", Message: "User not authenticated"}})
+		return
+	}
+
+	userIDInt, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Invalid user ID format"}})
+		return
+	}
+
+	response, err := h.emailService.ResendAllVerificationCodes(c.Request.Context(), userIDInt)
+	if err != nil {
+		if errors.Is(err, email.ErrResendAllTooSoon) {
+			c.JSON(http.StatusTooManyRequests, types.APIResponse{Success: false, Error: &types.APIError{Code: "TOO_MANY_REQUESTS", Message: "Verification codes resent recently, please wait", Details: err.Error()}})
+			return
+		}
+		logger.Error("Failed to resend verification codes", err, "userID", userIDInt)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Failed to resend verification codes", Details: err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
 // VerifyEmail 验证邮箱
 // @Summary 验证邮箱
 // @Description 使用验证码验证邮箱地址。email 必填，code 为6位数字。
@@ -386,3 +498,102 @@ func (h *EmailHandler) VerifyEmail(c *gin.Context) {
 		Data:    gin.H{"message": "Email verified successfully"},
 	})
 }
+
+// ===== 邮件偏好相关API =====
+
+// GetEmailPreferences 获取邮件偏好
+// @Summary 获取邮件偏好
+// @Description 获取当前用户通知邮件的抄送、密送地址与发件人名称；未设置发件人名称时返回系统默认名称
+// @Tags Email
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.EmailPreferencesResponse}
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未授权"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/emails/preferences [post]
+func (h *EmailHandler) GetEmailPreferences(c *gin.Context) {
+	// 获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{Success: false, Error: &types.APIError{Code: "UNAUTHORIZED", Message: "User not authenticated"}})
+		return
+	}
+
+	userIDInt, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Invalid user ID format"}})
+		return
+	}
+
+	response, err := h.emailService.GetEmailPreferences(c.Request.Context(), userIDInt)
+	if err != nil {
+		logger.Error("Failed to get email preferences", err, "userID", userIDInt)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Failed to get email preferences", Details: err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// UpdateEmailPreferences 更新邮件偏好
+// @Summary 更新邮件偏好
+// @Description 设置通知邮件的抄送、密送地址与发件人名称；字段不传表示不修改，传空字符串表示清除（发件人名称恢复为默认值）
+// @Tags Email
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.UpdateEmailPreferencesRequest true "邮件偏好"
+// @Success 200 {object} types.APIResponse{data=types.EmailPreferencesResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未授权"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/emails/preferences/update [post]
+func (h *EmailHandler) UpdateEmailPreferences(c *gin.Context) {
+	// 获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{Success: false, Error: &types.APIError{Code: "UNAUTHORIZED", Message: "User not authenticated"}})
+		return
+	}
+
+	userIDInt, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Invalid user ID format"}})
+		return
+	}
+
+	var req types.UpdateEmailPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", err)
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_REQUEST", Message: "Invalid request body", Details: err.Error()}})
+		return
+	}
+
+	response, err := h.emailService.UpdateEmailPreferences(c.Request.Context(), userIDInt, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, email.ErrInvalidCcEmail):
+			c.JSON(http.StatusUnprocessableEntity, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_CC_EMAIL", Message: "Invalid cc email format", Details: err.Error()}})
+			return
+		case errors.Is(err, email.ErrInvalidBccEmail):
+			c.JSON(http.StatusUnprocessableEntity, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_BCC_EMAIL", Message: "Invalid bcc email format", Details: err.Error()}})
+			return
+		case errors.Is(err, email.ErrInvalidFromName):
+			c.JSON(http.StatusUnprocessableEntity, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_FROM_NAME", Message: "Invalid from name", Details: err.Error()}})
+			return
+		}
+		logger.Error("Failed to update email preferences", err, "userID", userIDInt)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{Success: false, Error: &types.APIError{Code: "INTERNAL_ERROR", Message: "Failed to update email preferences", Details: err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
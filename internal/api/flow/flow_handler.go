@@ -1,6 +1,7 @@
 package flow
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -44,12 +45,61 @@ func (h *FlowHandler) RegisterRoutes(router *gin.RouterGroup) {
 		// POST /api/v1/flows/transaction/detail
 		// http://localhost:8080/api/v1/flows/transaction/detail
 		flows.POST("/transaction/detail", h.GetTransactionDetail)
+		// 获取流程状态变更历史（需要鉴权）
+		// POST /api/v1/flows/history
+		// http://localhost:8080/api/v1/flows/history
+		flows.POST("/history", middleware.AuthMiddleware(h.authService), h.GetFlowStatusHistory)
+
+		// 获取流程调用列表（含批量操作子调用）
+		// POST /api/v1/flows/calls
+		// http://localhost:8080/api/v1/flows/calls
+		flows.POST("/calls", middleware.AuthMiddleware(h.authService), h.GetFlowCalls)
+
+		// 获取流程交易在 Goldsky 中的原始索引数据（排障用）
+		// GET /api/v1/flows/goldsky-tx?standard=&chain_id=&contract_address=&flow_id=&tx_type=
+		// http://localhost:8080/api/v1/flows/goldsky-tx?standard=compound&chain_id=1&contract_address=0x...&flow_id=0x...&tx_type=queue
+		flows.GET("/goldsky-tx", middleware.AuthMiddleware(h.authService), h.GetFlowGoldskyTx)
+
+		// 排查通知：流程进入目标状态时当前用户的哪些通知配置与邮箱会/不会收到通知及原因
+		// GET /api/v1/flows/would-notify?standard=&chain_id=&contract_address=&flow_id=&status_to=
+		// http://localhost:8080/api/v1/flows/would-notify?standard=compound&chain_id=1&contract_address=0x...&flow_id=0x...&status_to=expired
+		flows.GET("/would-notify", middleware.AuthMiddleware(h.authService), h.GetFlowWouldNotify)
+
+		// 获取需要用户关注的流程（ready 或 24 小时内到达 eta 的 waiting 流程）
+		// GET /api/v1/flows/actionable
+		// http://localhost:8080/api/v1/flows/actionable?limit=50
+		flows.GET("/actionable", middleware.AuthMiddleware(h.authService), h.GetActionableFlows)
+
+		// 获取按 eta 日期分组的 waiting/ready 流程（日历视图）
+		// GET /api/v1/flows/calendar
+		// http://localhost:8080/api/v1/flows/calendar?from=2026-10-01&to=2026-10-31&tz=Asia/Shanghai
+		flows.GET("/calendar", middleware.AuthMiddleware(h.authService), h.GetFlowCalendar)
+
+		// 预览流程通知消息
+		// GET /api/v1/flows/preview-notification?standard=&chain_id=&contract_address=&flow_id=&status_from=&status_to=
+		// http://localhost:8080/api/v1/flows/preview-notification?standard=compound&chain_id=1&contract_address=0x...&flow_id=0x...&status_to=ready
+		flows.GET("/preview-notification", middleware.AuthMiddleware(h.authService), h.PreviewFlowNotification)
+
+		// 设置流程备注（如取消原因，note 为空时清除）
+		// POST /api/v1/flows/note
+		// http://localhost:8080/api/v1/flows/note
+		flows.POST("/note", middleware.AuthMiddleware(h.authService), middleware.RequireWriteScope(), h.SetFlowNote)
+
+		// 标记可疑流程（可选向自己的通知渠道发送告警）
+		// POST /api/v1/flows/flag
+		// http://localhost:8080/api/v1/flows/flag
+		flows.POST("/flag", middleware.AuthMiddleware(h.authService), middleware.RequireWriteScope(), h.FlagFlow)
+
+		// 查询合约下的流程标记
+		// GET /api/v1/flows/flags
+		// http://localhost:8080/api/v1/flows/flags?standard=compound&chain_id=1&contract_address=0x...&page=1&page_size=20
+		flows.GET("/flags", middleware.AuthMiddleware(h.authService), h.GetFlowFlags)
 	}
 }
 
 // GetFlowList 获取与用户相关的流程列表
 // @Summary 获取与用户相关的流程列表
-// @Description 获取与用户相关的timelock流程列表，包括发起的和有权限管理的
+// @Description 获取与用户相关的timelock流程列表，包括发起的和有权限管理的；initiator 可按发起人地址过滤（不区分大小写），与其他条件同时生效
 // @Tags Flow
 // @Accept json
 // @Produce json
@@ -94,6 +144,17 @@ func (h *FlowHandler) GetFlowList(c *gin.Context) {
 	// 调用服务层
 	response, err := h.flowService.GetCompoundFlowList(c.Request.Context(), userAddressStr, &req)
 	if err != nil {
+		if errors.Is(err, flow.ErrInvalidFlowFilter) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_PARAMS",
+					Message: "Invalid query parameters",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
 		logger.Error("Failed to get flow list", err, "user", userAddressStr)
 		c.JSON(http.StatusInternalServerError, types.APIResponse{
 			Success: false,
@@ -222,7 +283,7 @@ func (h *FlowHandler) GetTransactionDetail(c *gin.Context) {
 		// 调用服务层
 		response, err := h.flowService.GetCompoundTransactionDetail(c.Request.Context(), &req)
 		if err != nil {
-			if err.Error() == "transaction not found" {
+			if errors.Is(err, flow.ErrTransactionNotFound) {
 				c.JSON(http.StatusNotFound, types.APIResponse{
 					Success: false,
 					Error: &types.APIError{
@@ -255,3 +316,638 @@ func (h *FlowHandler) GetTransactionDetail(c *gin.Context) {
 		return
 	}
 }
+
+// GetFlowStatusHistory 获取流程状态变更历史
+// @Summary 获取流程状态变更历史
+// @Description 获取单个timelock流程的状态变更时间线（按时间升序），仅流程发起人或合约相关角色可查看
+// @Tags Flow
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.GetFlowStatusHistoryRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.GetFlowStatusHistoryResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/history [post]
+func (h *FlowHandler) GetFlowStatusHistory(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetFlowStatusHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetFlowStatusHistory(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to get flow status history")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetFlowGoldskyTx 获取流程交易的 Goldsky 原始数据
+// @Summary 获取流程交易的 Goldsky 原始数据
+// @Description 按流程的队列（OpenZeppelin 为 schedule）、执行或取消交易哈希查询 Goldsky subgraph 中索引的原始交易，用于排查流程问题。按标准返回 compound 或 openzeppelin 详情；流程尚无该类型交易返回 404 FLOW_TX_NOT_FOUND，subgraph 尚未索引该交易（或该链未配置 subgraph）返回 404 TRANSACTION_NOT_INDEXED。仅流程发起人或合约相关角色可查看
+// @Tags Flow
+// @Produce json
+// @Security BearerAuth
+// @Param standard query string true "标准compound, openzeppelin"
+// @Param chain_id query int true "链ID"
+// @Param contract_address query string true "合约地址"
+// @Param flow_id query string true "流程ID"
+// @Param tx_type query string false "交易类型 queue, execute, cancel，默认为queue"
+// @Success 200 {object} types.APIResponse{data=types.GetFlowGoldskyTxResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在、流程没有该类型交易或交易尚未被索引"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/goldsky-tx [get]
+func (h *FlowHandler) GetFlowGoldskyTx(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetFlowGoldskyTxRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetFlowGoldskyTx(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to get flow goldsky transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetFlowWouldNotify 查询流程状态变化会触发哪些通知
+// @Summary 查询流程状态变化会触发哪些通知
+// @Description 排查"为什么没收到通知"：按与实际发送相同的筛选逻辑（合约相关用户、配置启用状态、渠道总开关、邮箱验证状态与 notify_statuses、发送去重）判定流程进入 status_to 时当前用户的每个渠道配置与邮箱是否会收到通知，不会收到时给出原因。只返回当前用户自己的配置与邮箱，不发送也不写任何日志。仅流程发起人或合约相关角色可查询
+// @Tags Flow
+// @Produce json
+// @Security BearerAuth
+// @Param standard query string true "标准compound, openzeppelin"
+// @Param chain_id query int true "链ID"
+// @Param contract_address query string true "合约地址"
+// @Param flow_id query string true "流程ID"
+// @Param status_to query string true "目标状态 waiting, ready, executed, cancelled, expired"
+// @Success 200 {object} types.APIResponse{data=types.GetFlowWouldNotifyResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/would-notify [get]
+func (h *FlowHandler) GetFlowWouldNotify(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetFlowWouldNotifyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetFlowWouldNotify(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to check flow notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetFlowCalls 获取流程调用列表
+// @Summary 获取流程调用列表
+// @Description 获取单个timelock流程的调用列表。OpenZeppelin 批量操作（scheduleBatch）按子调用序号升序返回全部 (target, value, calldata)，普通流程只有一个调用；仅流程发起人或合约相关角色可查看
+// @Tags Flow
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.GetFlowCallsRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.GetFlowCallsResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/calls [post]
+func (h *FlowHandler) GetFlowCalls(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetFlowCallsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetFlowCalls(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to get flow calls")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SetFlowNote 设置流程备注
+// @Summary 设置流程备注
+// @Description 为流程附加一条链下备注（如取消原因），每个流程一条，后写覆盖；note 为空时清除。仅流程发起人或合约相关角色可设置，备注随流程列表返回
+// @Tags Flow
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.SetFlowNoteRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.SetFlowNoteResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权操作该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/note [post]
+func (h *FlowHandler) SetFlowNote(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.SetFlowNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.SetFlowNote(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to set flow note")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// FlagFlow 标记可疑流程
+// @Summary 标记可疑流程
+// @Description 将流程标记为可疑（如非预期的管理员变更）并记录原因，同一流程可被多次标记。仅流程发起人或合约相关角色可标记；notify=true 时向自己所有激活的通知渠道发送高优先级告警，告警发送失败不影响标记，响应中 notified 为 false
+// @Tags Flow
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.FlagFlowRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.FlowFlag}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权操作该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/flag [post]
+func (h *FlowHandler) FlagFlow(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.FlagFlowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.FlagFlow(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to flag flow")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetFlowFlags 查询合约下的流程标记
+// @Summary 查询流程标记
+// @Description 查询合约下的可疑流程标记，按标记时间倒序分页；flow_id 非空时只查询该流程。仅合约相关角色或合约下任一流程的发起人可查询
+// @Tags Flow
+// @Produce json
+// @Security BearerAuth
+// @Param standard query string true "标准compound, openzeppelin"
+// @Param chain_id query int true "链ID"
+// @Param contract_address query string true "合约地址"
+// @Param flow_id query string false "流程ID"
+// @Param page query int false "页码，默认为1"
+// @Param page_size query int false "每页大小，默认为20，最大100"
+// @Success 200 {object} types.APIResponse{data=types.GetFlowFlagsResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该合约"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/flags [get]
+func (h *FlowHandler) GetFlowFlags(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetFlowFlagsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetFlowFlags(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to get flow flags")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetActionableFlows 获取需要用户关注的流程
+// @Summary 获取需要用户关注的流程
+// @Description 返回与当前用户相关、需要处理的流程：ready（可立即执行），或 waiting 且 eta 在 24 小时内。合并 Compound 与 OpenZeppelin 两种标准，按 eta 升序（最紧急的在前），并附带解码后的函数摘要
+// @Tags Flow
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "返回数量，默认为50，最大100"
+// @Success 200 {object} types.APIResponse{data=types.GetActionableFlowsResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/actionable [get]
+func (h *FlowHandler) GetActionableFlows(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetActionableFlowsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetActionableFlows(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		logger.Error("Failed to get actionable flows", err, "user", userAddressStr)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get actionable flows",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetFlowCalendar 获取按 eta 日期分组的流程
+// @Summary 获取按 eta 日期分组的流程
+// @Description 返回与当前用户相关、eta 在 [from, to] 内的 waiting/ready 流程（Compound 与 OpenZeppelin），按 eta 在 tz 时区下的日期分组，附带每日数量统计与流程摘要。日期范围最多 92 天，流程数超过 2000 时只返回最早的部分并标记 truncated
+// @Tags Flow
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "开始日期（含），YYYY-MM-DD"
+// @Param to query string true "结束日期（含），YYYY-MM-DD"
+// @Param tz query string false "时区（IANA 名称），默认 UTC"
+// @Success 200 {object} types.APIResponse{data=types.GetFlowCalendarResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_PARAMS: 日期格式、范围或时区不合法"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/calendar [get]
+func (h *FlowHandler) GetFlowCalendar(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.GetFlowCalendarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.GetFlowCalendar(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		if errors.Is(err, flow.ErrInvalidCalendarRange) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_PARAMS",
+					Message: "Invalid calendar range",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		logger.Error("Failed to get flow calendar", err, "user", userAddressStr)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get flow calendar",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// PreviewFlowNotification 预览流程通知消息
+// @Summary 预览流程通知消息
+// @Description 按指定的状态变更渲染该流程的通知消息，与实际发送的内容一致，但不会发送也不会写入通知日志；configs 为当前用户各激活配置会收到的消息（含配置的前后缀，已排除关闭的渠道类型）；仅流程发起人或合约相关角色可预览
+// @Tags Flow
+// @Produce json
+// @Security BearerAuth
+// @Param standard query string true "标准 compound, openzeppelin"
+// @Param chain_id query int true "链ID"
+// @Param contract_address query string true "合约地址"
+// @Param flow_id query string true "流程ID"
+// @Param status_from query string false "变更前状态 waiting, ready, executed, cancelled, expired，新建流程时为空"
+// @Param status_to query string true "变更后状态 waiting, ready, executed, cancelled, expired"
+// @Success 200 {object} types.APIResponse{data=types.PreviewFlowNotificationResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该流程"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/flows/preview-notification [get]
+func (h *FlowHandler) PreviewFlowNotification(c *gin.Context) {
+	// 从鉴权中间件获取用户地址
+	_, userAddressStr, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User address not found in token",
+			},
+		})
+		return
+	}
+
+	var req types.PreviewFlowNotificationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.flowService.PreviewFlowNotification(c.Request.Context(), userAddressStr, &req)
+	if err != nil {
+		h.writeFlowAccessError(c, err, "Failed to preview flow notification")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// writeFlowAccessError 将单个流程查询的错误映射为HTTP响应
+func (h *FlowHandler) writeFlowAccessError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, flow.ErrFlowNotFound):
+		c.JSON(http.StatusNotFound, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "FLOW_NOT_FOUND",
+				Message: "Flow not found",
+			},
+		})
+	case errors.Is(err, flow.ErrFlowAccessDenied):
+		c.JSON(http.StatusForbidden, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "ACCESS_DENIED",
+				Message: "You have no permission to access this flow",
+			},
+		})
+	case errors.Is(err, flow.ErrFlowTxNotFound):
+		c.JSON(http.StatusNotFound, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "FLOW_TX_NOT_FOUND",
+				Message: "Flow has no transaction of the requested type",
+				Details: err.Error(),
+			},
+		})
+	case errors.Is(err, flow.ErrTransactionNotFound):
+		c.JSON(http.StatusNotFound, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "TRANSACTION_NOT_INDEXED",
+				Message: "Transaction has not been indexed by Goldsky yet",
+				Details: err.Error(),
+			},
+		})
+	case errors.Is(err, flow.ErrInvalidFlowFlag):
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+	default:
+		logger.Error(message, err)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: message,
+				Details: err.Error(),
+			},
+		})
+	}
+}
@@ -42,7 +42,7 @@ func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
 // @Param payload body types.GraphQLWebhookPayload true "Webhook Payload"
 // @Success 200 {object} types.APIResponse
 // @Failure 401 {object} types.APIResponse "Invalid webhook"
-// @Failure 400 {object} types.APIResponse "Invalid payload"
+// @Failure 400 {object} types.APIResponse "Invalid payload（结构校验失败，请求记录到 goldsky_webhook_dead_letters）"
 // @Failure 500 {object} types.APIResponse "Internal error"
 // @Router /api/v1/goldsky/webhook [post]
 func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
@@ -52,10 +52,10 @@ func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
 	secret := c.GetHeader("goldsky-webhook-secret")
 	logger.Info("Webhook headers", "goldsky-webhook-secret", secret)
 
-	// 2. 解析 Payload
-	var payload types.GraphQLWebhookPayload
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		logger.Error("Failed to parse webhook payload", err)
+	// 2. 读取并校验 Payload 结构，不合法的请求写入 dead-letter 表后直接返回 400
+	raw, err := c.GetRawData()
+	if err != nil {
+		logger.Error("Failed to read webhook payload", err)
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
@@ -66,6 +66,20 @@ func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
 		})
 		return
 	}
+	parsed, problems := goldsky.ValidateWebhookPayload(raw)
+	if len(problems) > 0 {
+		h.processor.RecordRejectedPayload(c.Request.Context(), c.ClientIP(), raw, problems)
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PAYLOAD",
+				Message: "Invalid webhook payload",
+				Details: strings.Join(problems, "; "),
+			},
+		})
+		return
+	}
+	payload := *parsed
 
 	logger.Info("Parsed webhook payload", "webhook_id", payload.WebhookID, "entity", payload.Entity)
 
@@ -129,20 +143,51 @@ func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
 	// 不能使用 c.Request.Context()，因为HTTP响应后该context会被取消
 	bgCtx := context.Background()
 
-	// 7. 根据事件类型处理交易
-	var processErr error
-	switch txData.EventType {
-	case "QueueTransaction":
-		processErr = h.processQueueTransaction(bgCtx, txData, chainID, standard)
-	case "ExecuteTransaction":
-		processErr = h.processExecuteTransaction(bgCtx, txData, chainID, standard)
-	case "CancelTransaction":
-		processErr = h.processCancelTransaction(bgCtx, txData, chainID, standard)
-	default:
-		logger.Warn("Unknown event type", "event_type", txData.EventType)
-		processErr = fmt.Errorf("unknown event type: %s", txData.EventType)
+	// 7. 按事件ID去重：Goldsky 重试投递已处理过的事件时直接确认，不重复更新状态与发送通知
+	eventKey := goldsky.WebhookEventKey(chainID, standard, txData)
+	if !h.processor.ClaimEvent(bgCtx, eventKey, chainID, standard, txData) {
+		c.JSON(http.StatusOK, types.APIResponse{
+			Success: true,
+			Data: gin.H{
+				"message":    "Duplicate event, already processed",
+				"chain_id":   chainID,
+				"standard":   standard,
+				"webhook_id": payload.WebhookID,
+				"event_type": txData.EventType,
+				"tx_hash":    txData.TxHash,
+				"duplicate":  true,
+			},
+		})
+		return
 	}
 
+	// 8. 链配置了确认深度且区块确认数不足时暂存事件，确认后由后台任务处理
+	deferred, processErr := h.processor.DeferUntilConfirmed(bgCtx, eventKey, chainID, chain.ConfirmationDepth, txData, func(ctx context.Context) error {
+		return h.processTransaction(ctx, txData, chainID, standard)
+	})
+	if deferred {
+		c.JSON(http.StatusOK, types.APIResponse{
+			Success: true,
+			Data: gin.H{
+				"message":            "Event deferred until confirmed",
+				"chain_id":           chainID,
+				"standard":           standard,
+				"webhook_id":         payload.WebhookID,
+				"event_type":         txData.EventType,
+				"tx_hash":            txData.TxHash,
+				"deferred":           true,
+				"confirmation_depth": chain.ConfirmationDepth,
+			},
+		})
+		return
+	}
+
+	// 9. 根据事件类型处理交易
+	if processErr == nil {
+		processErr = h.processTransaction(bgCtx, txData, chainID, standard)
+	}
+	h.processor.FinishEvent(bgCtx, eventKey, processErr)
+
 	if processErr != nil {
 		logger.Error("Failed to process transaction", processErr,
 			"chain_id", chainID,
@@ -172,6 +217,21 @@ func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
 	})
 }
 
+// processTransaction 根据事件类型处理交易
+func (h *WebhookHandler) processTransaction(ctx context.Context, txData *types.GraphQLTransactionData, chainID int, standard string) error {
+	switch txData.EventType {
+	case "QueueTransaction":
+		return h.processQueueTransaction(ctx, txData, chainID, standard)
+	case "ExecuteTransaction":
+		return h.processExecuteTransaction(ctx, txData, chainID, standard)
+	case "CancelTransaction":
+		return h.processCancelTransaction(ctx, txData, chainID, standard)
+	default:
+		logger.Warn("Unknown event type", "event_type", txData.EventType)
+		return fmt.Errorf("unknown event type: %s", txData.EventType)
+	}
+}
+
 // processQueueTransaction 处理排队交易
 func (h *WebhookHandler) processQueueTransaction(ctx context.Context, txData *types.GraphQLTransactionData, chainID int, standard string) error {
 	// 转换为对应的webhook交易格式并处理
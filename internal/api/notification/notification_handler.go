@@ -2,13 +2,16 @@ package notification
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 	"timelocker-backend/internal/middleware"
 	"timelocker-backend/internal/service/auth"
 	"timelocker-backend/internal/service/notification"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -18,13 +21,15 @@ import (
 type NotificationHandler struct {
 	notificationService notification.NotificationService
 	authService         auth.Service
+	reauthMaxAge        time.Duration // 查看敏感字段明文要求的最近签名登录时间
 }
 
 // NewNotificationHandler 创建通知处理器实例
-func NewNotificationHandler(notificationService notification.NotificationService, authService auth.Service) *NotificationHandler {
+func NewNotificationHandler(notificationService notification.NotificationService, authService auth.Service, reauthMaxAge time.Duration) *NotificationHandler {
 	return &NotificationHandler{
 		notificationService: notificationService,
 		authService:         authService,
+		reauthMaxAge:        reauthMaxAge,
 	}
 }
 
@@ -33,25 +38,75 @@ func (h *NotificationHandler) RegisterRoutes(router *gin.RouterGroup) {
 	// 通知API组 - 需要认证
 	notificationGroup := router.Group("/notifications", middleware.AuthMiddleware(h.authService))
 	{
-		// 获取所有通知配置
-		// POST /api/v1/notifications/configs
+		// 获取所有通知配置，reveal=true 返回敏感字段明文（需最近重新签名登录）
+		// POST /api/v1/notifications/configs?reveal=true
 		// http://localhost:8080/api/v1/notifications/configs
 		notificationGroup.POST("/configs", h.GetAllNotificationConfigs)
 
+		// 按渠道与名称获取单个通知配置
+		// GET /api/v1/notifications/config?channel=telegram&name=xxx
+		// http://localhost:8080/api/v1/notifications/config?channel=telegram&name=xxx
+		notificationGroup.GET("/config", h.GetNotificationConfig)
+
 		// 创建通知配置
 		// POST /api/v1/notifications/create
 		// http://localhost:8080/api/v1/notifications/create
-		notificationGroup.POST("/create", h.CreateNotificationConfig)
+		notificationGroup.POST("/create", middleware.RequireWriteScope(), h.CreateNotificationConfig)
 
 		// 更新通知配置
 		// POST /api/v1/notifications/update
 		// http://localhost:8080/api/v1/notifications/update
-		notificationGroup.POST("/update", h.UpdateNotificationConfig)
+		notificationGroup.POST("/update", middleware.RequireWriteScope(), h.UpdateNotificationConfig)
 
 		// 删除通知配置
 		// POST /api/v1/notifications/delete
 		// http://localhost:8080/api/v1/notifications/delete
-		notificationGroup.POST("/delete", h.DeleteNotificationConfig)
+		notificationGroup.POST("/delete", middleware.RequireWriteScope(), h.DeleteNotificationConfig)
+
+		// 获取各渠道总开关状态
+		// GET /api/v1/notifications/channels
+		// http://localhost:8080/api/v1/notifications/channels
+		notificationGroup.GET("/channels", h.GetNotificationChannelSettings)
+
+		// 获取各渠道配置字段（必填/可选、校验提示）
+		// GET /api/v1/notifications/channels/schema
+		// http://localhost:8080/api/v1/notifications/channels/schema
+		notificationGroup.GET("/channels/schema", h.GetNotificationChannelSchemas)
+
+		// 开关整个通知渠道
+		// POST /api/v1/notifications/channels/set
+		// http://localhost:8080/api/v1/notifications/channels/set
+		notificationGroup.POST("/channels/set", middleware.RequireWriteScope(), h.SetNotificationChannelEnabled)
+
+		// 导出通知配置
+		// POST /api/v1/notifications/export
+		// http://localhost:8080/api/v1/notifications/export
+		notificationGroup.POST("/export", h.ExportNotificationConfigs)
+
+		// 导入通知配置
+		// POST /api/v1/notifications/import
+		// http://localhost:8080/api/v1/notifications/import
+		notificationGroup.POST("/import", middleware.RequireWriteScope(), h.ImportNotificationConfigs)
+
+		// 获取通知发送日志
+		// POST /api/v1/notifications/logs
+		// http://localhost:8080/api/v1/notifications/logs
+		notificationGroup.POST("/logs", h.GetNotificationLogs)
+
+		// 获取通知发送统计
+		// GET /api/v1/notifications/stats?days=7
+		// http://localhost:8080/api/v1/notifications/stats?days=7
+		notificationGroup.GET("/stats", h.GetNotificationStats)
+
+		// 校验 webhook URL 可达性
+		// POST /api/v1/notifications/validate-url
+		// http://localhost:8080/api/v1/notifications/validate-url
+		notificationGroup.POST("/validate-url", h.ValidateWebhookURL)
+
+		// 校验并试渲染通知消息模板
+		// POST /api/v1/notifications/template/preview
+		// http://localhost:8080/api/v1/notifications/template/preview
+		notificationGroup.POST("/template/preview", h.PreviewNotificationTemplate)
 	}
 }
 
@@ -59,12 +114,15 @@ func (h *NotificationHandler) RegisterRoutes(router *gin.RouterGroup) {
 
 // GetAllNotificationConfigs 获取所有通知配置
 // @Summary 获取所有通知配置
-// @Description 获取当前用户的所有通知渠道配置，如果用户没有任何配置则返回空列表
+// @Description 获取当前用户的所有通知渠道配置，如果用户没有任何配置则返回空列表。bot_token、webhook_url、secret、access_token 默认只返回末尾 4 位，reveal=true 返回明文，要求在 jwt.reauth_max_age 内重新签名登录（刷新令牌不计入，API令牌不可用）
 // @Tags Notification
 // @Accept json
 // @Produce json
+// @Param reveal query bool false "是否返回敏感字段明文"
 // @Success 200 {object} types.APIResponse{data=types.NotificationConfigListResponse} "获取成功，返回所有配置或空列表"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "需要重新认证 - REAUTH_REQUIRED: 查看明文需最近重新签名登录"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 获取配置失败; DATABASE_ERROR: 数据库访问失败"
 // @Router /api/v1/notifications/configs [post]
 func (h *NotificationHandler) GetAllNotificationConfigs(c *gin.Context) {
@@ -82,8 +140,25 @@ func (h *NotificationHandler) GetAllNotificationConfigs(c *gin.Context) {
 		return
 	}
 
+	var req types.GetAllNotificationConfigsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetAllNotificationConfigs error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+	if req.Reveal && !h.checkRecentAuth(c, "GetAllNotificationConfigs", userAddress) {
+		return
+	}
+
 	// 调用service层
-	response, err := h.notificationService.GetAllNotificationConfigs(c.Request.Context(), userAddress)
+	response, err := h.notificationService.GetAllNotificationConfigs(c.Request.Context(), userAddress, req.Reveal)
 	if err != nil {
 		// 处理特定错误类型
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -94,6 +169,7 @@ func (h *NotificationHandler) GetAllNotificationConfigs(c *gin.Context) {
 				FeishuConfigs:   []*types.FeishuConfig{},
 				DiscordConfigs:  []*types.DiscordConfig{},
 				SlackConfigs:    []*types.SlackConfig{},
+				MatrixConfigs:   []*types.MatrixConfig{},
 			}
 			c.JSON(http.StatusOK, types.APIResponse{
 				Success: true,
@@ -129,13 +205,124 @@ func (h *NotificationHandler) GetAllNotificationConfigs(c *gin.Context) {
 		return
 	}
 
-	logger.Info("GetAllNotificationConfigs success", "user_address", userAddress)
+	logger.Info("GetAllNotificationConfigs success", "user_address", userAddress, "reveal", req.Reveal)
 	c.JSON(http.StatusOK, types.APIResponse{
 		Success: true,
 		Data:    response,
 	})
 }
 
+// GetNotificationConfig 获取单个通知配置
+// @Summary 获取单个通知配置
+// @Description 按渠道与名称获取当前用户的单个通知配置，用于编辑表单回显。bot_token、webhook_url、secret、access_token 默认只返回末尾 4 位（原样回传给更新接口视为不修改），reveal=true 返回明文，要求最近重新签名登录
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param channel query string true "渠道: telegram, lark, feishu, discord, slack, matrix"
+// @Param name query string true "配置名称"
+// @Param reveal query bool false "是否返回敏感字段明文"
+// @Success 200 {object} types.APIResponse{data=types.NotificationConfig} "获取成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_NAME: 名称不能为空; INVALID_CHANNEL: 无效的通知渠道"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "需要重新认证 - REAUTH_REQUIRED: 查看明文需最近重新签名登录"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "配置不存在 - CONFIG_NOT_FOUND: 指定的通知配置不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 获取配置失败"
+// @Router /api/v1/notifications/config [get]
+func (h *NotificationHandler) GetNotificationConfig(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetNotificationConfig error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.GetNotificationConfigRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationConfig error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	// 标准化名称
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_NAME",
+				Message: "Name cannot be empty",
+				Details: "Name field is required and cannot be empty",
+			},
+		})
+		return
+	}
+
+	if req.Reveal && !h.checkRecentAuth(c, "GetNotificationConfig", userAddress) {
+		return
+	}
+
+	// 调用service层
+	config, err := h.notificationService.GetNotificationConfig(c.Request.Context(), userAddress, req.Channel, req.Name, req.Reveal)
+	if err != nil {
+		if errors.Is(err, notification.ErrNotificationConfigNotFound) {
+			c.JSON(http.StatusNotFound, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "CONFIG_NOT_FOUND",
+					Message: "Notification config not found",
+					Details: err.Error(),
+				},
+			})
+			logger.Warn("GetNotificationConfig config not found", "user_address", userAddress, "name", req.Name, "channel", req.Channel)
+			return
+		}
+
+		if errors.Is(err, notification.ErrInvalidNotificationChannel) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_CHANNEL",
+					Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack, matrix",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+
+		// 通用内部错误
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get notification config",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationConfig error", err, "user_address", userAddress, "name", req.Name, "channel", req.Channel)
+		return
+	}
+
+	logger.Info("GetNotificationConfig success", "user_address", userAddress, "name", req.Name, "channel", req.Channel, "reveal", req.Reveal)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    config,
+	})
+}
+
 // CreateNotificationConfig 创建通知配置
 // @Summary 创建通知配置
 // @Description 为当前用户创建新的通知配置, 名字的空格会被自动去除, 防止攻击者通过空格来绕过名称验证
@@ -144,9 +331,9 @@ func (h *NotificationHandler) GetAllNotificationConfigs(c *gin.Context) {
 // @Produce json
 // @Param request body types.CreateNotificationRequest true "创建请求"
 // @Success 200 {object} types.APIResponse{data=object} "创建成功"
-// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_NAME: 名称不能为空; INVALID_CHANNEL: 无效的通知渠道; MISSING_TELEGRAM_FIELDS: 缺少telegram必填字段; MISSING_WEBHOOK_URL: 缺少webhook_url字段; MISSING_REQUIRED_FIELDS: 缺少必填字段"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_NAME: 名称不能为空; INVALID_CHANNEL: 无效的通知渠道; MISSING_TELEGRAM_FIELDS: 缺少telegram必填字段; MISSING_WEBHOOK_URL: 缺少webhook_url字段; MISSING_REQUIRED_FIELDS: 缺少必填字段; UNSAFE_URL: webhook_url/homeserver_url 不合法或指向内网/本机地址; INVALID_MESSAGE_AFFIX: prefix/suffix 超过 32 个字符或包含换行等控制字符"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
-// @Failure 409 {object} types.APIResponse{error=types.APIError} "配置冲突 - CONFIG_ALREADY_EXISTS: 同名配置已存在"
+// @Failure 409 {object} types.APIResponse{error=types.APIError} "配置冲突 - CONFIG_ALREADY_EXISTS: 同名配置已存在; DUPLICATE_DESTINATION: 已有目标相同的激活配置（可设置 allow_duplicate 跳过）"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 创建配置失败"
 // @Router /api/v1/notifications/create [post]
 func (h *NotificationHandler) CreateNotificationConfig(c *gin.Context) {
@@ -193,13 +380,13 @@ func (h *NotificationHandler) CreateNotificationConfig(c *gin.Context) {
 
 	// 验证渠道类型
 	req.Channel = strings.ToLower(req.Channel)
-	if req.Channel != "telegram" && req.Channel != "lark" && req.Channel != "feishu" && req.Channel != "discord" && req.Channel != "slack" {
+	if req.Channel != "telegram" && req.Channel != "lark" && req.Channel != "feishu" && req.Channel != "discord" && req.Channel != "slack" && req.Channel != "matrix" {
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
 				Code:    "INVALID_CHANNEL",
-				Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack",
-				Details: "channel must be one of: telegram, lark, feishu, discord, slack",
+				Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack, matrix",
+				Details: "channel must be one of: telegram, lark, feishu, discord, slack, matrix",
 			},
 		})
 		return
@@ -254,12 +441,61 @@ func (h *NotificationHandler) CreateNotificationConfig(c *gin.Context) {
 			})
 			return
 		}
+	} else if req.Channel == "matrix" {
+		if req.HomeserverURL == "" || req.AccessToken == "" || req.RoomID == "" {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "MISSING_MATRIX_FIELDS",
+					Message: "homeserver_url, access_token and room_id are required for matrix channel",
+					Details: "Please provide homeserver_url, access_token and room_id",
+				},
+			})
+			return
+		}
 	}
 
 	// 调用service层
 	err := h.notificationService.CreateNotificationConfig(c.Request.Context(), userAddress, &req)
 	if err != nil {
 		// 处理特定错误类型
+		if errors.Is(err, utils.ErrUnsafeURL) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "UNSAFE_URL",
+					Message: "URL is invalid or points to a disallowed address",
+					Details: err.Error(),
+				},
+			})
+			logger.Warn("CreateNotificationConfig rejected unsafe url", "user_address", userAddress, "channel", req.Channel, "error", err)
+			return
+		}
+		if errors.Is(err, notification.ErrInvalidMessageAffix) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_MESSAGE_AFFIX",
+					Message: "Message prefix/suffix is too long or contains control characters",
+					Details: err.Error(),
+				},
+			})
+			logger.Warn("CreateNotificationConfig rejected message prefix/suffix", "user_address", userAddress, "channel", req.Channel, "error", err)
+			return
+		}
+		if errors.Is(err, notification.ErrDuplicateDestination) {
+			c.JSON(http.StatusConflict, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "DUPLICATE_DESTINATION",
+					Message: "An active notification config with the same destination already exists, set allow_duplicate to create it anyway",
+					Details: err.Error(),
+				},
+			})
+			logger.Error("CreateNotificationConfig error", err, "user_address", userAddress, "name", req.Name, "channel", req.Channel)
+			return
+		}
+
 		if strings.Contains(err.Error(), "already exists") {
 			c.JSON(http.StatusConflict, types.APIResponse{
 				Success: false,
@@ -328,7 +564,7 @@ func (h *NotificationHandler) CreateNotificationConfig(c *gin.Context) {
 // @Produce json
 // @Param request body types.UpdateNotificationRequest true "更新请求"
 // @Success 200 {object} types.APIResponse{data=object} "更新成功"
-// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_NAME: 名称不能为空; INVALID_CHANNEL: 无效的通知渠道; NO_FIELDS_TO_UPDATE: 至少需要提供一个字段进行更新"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_NAME: 名称不能为空; INVALID_CHANNEL: 无效的通知渠道; NO_FIELDS_TO_UPDATE: 至少需要提供一个字段进行更新; UNSAFE_URL: webhook_url/homeserver_url 不合法或指向内网/本机地址; INVALID_MESSAGE_AFFIX: prefix/suffix 超过 32 个字符或包含换行等控制字符"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
 // @Failure 404 {object} types.APIResponse{error=types.APIError} "配置不存在 - CONFIG_NOT_FOUND: 指定的通知配置不存在"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 更新配置失败"
@@ -391,13 +627,13 @@ func (h *NotificationHandler) UpdateNotificationConfig(c *gin.Context) {
 	}
 
 	*req.Channel = strings.ToLower(*req.Channel)
-	if *req.Channel != "telegram" && *req.Channel != "lark" && *req.Channel != "feishu" && *req.Channel != "discord" && *req.Channel != "slack" {
+	if *req.Channel != "telegram" && *req.Channel != "lark" && *req.Channel != "feishu" && *req.Channel != "discord" && *req.Channel != "slack" && *req.Channel != "matrix" {
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
 				Code:    "INVALID_CHANNEL",
-				Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack",
-				Details: "channel must be one of: telegram, lark, feishu, discord, slack",
+				Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack, matrix",
+				Details: "channel must be one of: telegram, lark, feishu, discord, slack, matrix",
 			},
 		})
 		return
@@ -413,6 +649,8 @@ func (h *NotificationHandler) UpdateNotificationConfig(c *gin.Context) {
 		hasUpdate = req.WebhookURL != nil || req.IsActive != nil
 	} else if *req.Channel == "slack" {
 		hasUpdate = req.WebhookURL != nil || req.IsActive != nil
+	} else if *req.Channel == "matrix" {
+		hasUpdate = req.HomeserverURL != nil || req.AccessToken != nil || req.RoomID != nil || req.IsActive != nil
 	}
 
 	if !hasUpdate {
@@ -431,6 +669,30 @@ func (h *NotificationHandler) UpdateNotificationConfig(c *gin.Context) {
 	err := h.notificationService.UpdateNotificationConfig(c.Request.Context(), userAddress, &req)
 	if err != nil {
 		// 处理特定错误类型
+		if errors.Is(err, utils.ErrUnsafeURL) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "UNSAFE_URL",
+					Message: "URL is invalid or points to a disallowed address",
+					Details: err.Error(),
+				},
+			})
+			logger.Warn("UpdateNotificationConfig rejected unsafe url", "user_address", userAddress, "channel", *req.Channel, "error", err)
+			return
+		}
+		if errors.Is(err, notification.ErrInvalidMessageAffix) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_MESSAGE_AFFIX",
+					Message: "Message prefix/suffix is too long or contains control characters",
+					Details: err.Error(),
+				},
+			})
+			logger.Warn("UpdateNotificationConfig rejected message prefix/suffix", "user_address", userAddress, "channel", *req.Channel, "error", err)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, types.APIResponse{
 				Success: false,
@@ -549,13 +811,13 @@ func (h *NotificationHandler) DeleteNotificationConfig(c *gin.Context) {
 
 	// 验证渠道类型
 	req.Channel = strings.ToLower(req.Channel)
-	if req.Channel != "telegram" && req.Channel != "lark" && req.Channel != "feishu" && req.Channel != "discord" && req.Channel != "slack" {
+	if req.Channel != "telegram" && req.Channel != "lark" && req.Channel != "feishu" && req.Channel != "discord" && req.Channel != "slack" && req.Channel != "matrix" {
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
 				Code:    "INVALID_CHANNEL",
-				Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack",
-				Details: "channel must be one of: telegram, lark, feishu, discord, slack",
+				Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack, matrix",
+				Details: "channel must be one of: telegram, lark, feishu, discord, slack, matrix",
 			},
 		})
 		return
@@ -610,3 +872,578 @@ func (h *NotificationHandler) DeleteNotificationConfig(c *gin.Context) {
 		Data:    gin.H{"message": "Notification config deleted successfully"},
 	})
 }
+
+// GetNotificationChannelSettings 获取各渠道总开关状态
+// @Summary 获取各渠道总开关状态
+// @Description 返回当前用户全部通知渠道的开关状态，未设置过的渠道为开启
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.APIResponse{data=types.NotificationChannelSettingsResponse} "获取成功"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 获取渠道开关失败"
+// @Router /api/v1/notifications/channels [get]
+func (h *NotificationHandler) GetNotificationChannelSettings(c *gin.Context) {
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetNotificationChannelSettings error", nil, "message", "user not authenticated")
+		return
+	}
+
+	response, err := h.notificationService.GetNotificationChannelSettings(c.Request.Context(), userAddress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get notification channel settings",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationChannelSettings error", err, "user_address", userAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetNotificationChannelSchemas 获取各渠道配置字段
+// @Summary 获取各渠道配置字段
+// @Description 返回每个支持渠道创建配置时的必填/可选字段、字段类型、是否敏感及长度限制，前端据此动态渲染配置表单
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.APIResponse{data=types.NotificationChannelSchemaResponse} "获取成功"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Router /api/v1/notifications/channels/schema [get]
+func (h *NotificationHandler) GetNotificationChannelSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    h.notificationService.GetNotificationChannelSchemas(),
+	})
+}
+
+// SetNotificationChannelEnabled 开关整个通知渠道
+// @Summary 开关整个通知渠道
+// @Description 一次性关闭/开启某个渠道类型下的全部配置（如关闭所有 Telegram 通知），各配置自身的 is_active 保持不变；关闭后流程通知与合约告警都不再通过该渠道发送
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body types.SetNotificationChannelEnabledRequest true "渠道开关请求"
+// @Success 200 {object} types.APIResponse{data=types.NotificationChannelSettingsResponse} "设置成功，返回最新的全部渠道状态"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_CHANNEL: 无效的通知渠道"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 保存渠道开关失败"
+// @Router /api/v1/notifications/channels/set [post]
+func (h *NotificationHandler) SetNotificationChannelEnabled(c *gin.Context) {
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("SetNotificationChannelEnabled error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.SetNotificationChannelEnabledRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("SetNotificationChannelEnabled error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.notificationService.SetNotificationChannelEnabled(c.Request.Context(), userAddress, req.Channel, *req.Enabled)
+	if err != nil {
+		if errors.Is(err, notification.ErrInvalidNotificationChannel) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_CHANNEL",
+					Message: "Invalid notification channel. Supported channels: telegram, lark, feishu, discord, slack, matrix",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to save notification channel setting",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("SetNotificationChannelEnabled error", err, "user_address", userAddress, "channel", req.Channel)
+		return
+	}
+
+	logger.Info("SetNotificationChannelEnabled success", "user_address", userAddress, "channel", req.Channel, "enabled", *req.Enabled)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ExportNotificationConfigs 导出通知配置
+// @Summary 导出通知配置
+// @Description 导出当前用户的全部通知配置用于备份/迁移。默认对 bot_token、webhook_url、secret、access_token 脱敏（只保留末尾 4 位），include_secrets=true 时返回明文，要求最近重新签名登录；请求体可为空
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body types.ExportNotificationConfigsRequest false "导出请求"
+// @Success 200 {object} types.APIResponse{data=types.ExportNotificationConfigsResponse} "导出成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "需要重新认证 - REAUTH_REQUIRED: 导出明文需最近重新签名登录"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 导出配置失败"
+// @Router /api/v1/notifications/export [post]
+func (h *NotificationHandler) ExportNotificationConfigs(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("ExportNotificationConfigs error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.ExportNotificationConfigsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ExportNotificationConfigs error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	if req.IncludeSecrets && !h.checkRecentAuth(c, "ExportNotificationConfigs", userAddress) {
+		return
+	}
+
+	response, err := h.notificationService.ExportNotificationConfigs(c.Request.Context(), userAddress, req.IncludeSecrets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to export notification configs",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ExportNotificationConfigs error", err, "user_address", userAddress)
+		return
+	}
+
+	logger.Info("ExportNotificationConfigs success", "user_address", userAddress, "count", len(response.Configs), "include_secrets", req.IncludeSecrets)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ImportNotificationConfigs 导入通知配置
+// @Summary 导入通知配置
+// @Description 根据导出的配置重新创建通知配置，同渠道下同名的配置会被跳过。任一条配置的渠道或必填字段不合法时整批拒绝；全部配置在同一事务中写入，任一条写入失败时整批回滚；包含 [REDACTED] 占位值的配置无法导入
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body types.ImportNotificationConfigsRequest true "导入请求"
+// @Success 200 {object} types.APIResponse{data=types.ImportNotificationConfigsResponse} "导入成功，返回已创建和已跳过的配置"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; INVALID_CONFIG: 配置渠道、必填字段或 URL 不合法（URL 禁止指向内网/本机地址）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 导入配置失败"
+// @Router /api/v1/notifications/import [post]
+func (h *NotificationHandler) ImportNotificationConfigs(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("ImportNotificationConfigs error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.ImportNotificationConfigsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ImportNotificationConfigs error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.notificationService.ImportNotificationConfigs(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		if errors.Is(err, notification.ErrInvalidImportConfig) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_CONFIG",
+					Message: "Imported notification config is invalid",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to import notification configs",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ImportNotificationConfigs error", err, "user_address", userAddress)
+		return
+	}
+
+	logger.Info("ImportNotificationConfigs success", "user_address", userAddress, "created", len(response.Created), "skipped", len(response.Skipped))
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetNotificationLogs 获取通知发送日志
+// @Summary 获取通知发送日志
+// @Description 分页获取当前用户各渠道的通知发送记录（按发送时间倒序），可按渠道、流程ID、发送状态过滤。provider_message_id 为服务商返回的消息ID（Telegram message_id、Discord 消息ID、Matrix event_id），可用于与服务商侧核对投递情况；Lark/飞书/Slack webhook 不返回消息ID；请求体可为空
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body types.GetNotificationLogsRequest false "查询条件"
+// @Success 200 {object} types.APIResponse{data=types.GetNotificationLogsResponse} "获取成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 获取日志失败"
+// @Router /api/v1/notifications/logs [post]
+func (h *NotificationHandler) GetNotificationLogs(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetNotificationLogs error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.GetNotificationLogsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationLogs error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.notificationService.GetNotificationLogs(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get notification logs",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationLogs error", err, "user_address", userAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetNotificationStats 获取通知发送统计
+// @Summary 获取通知发送统计
+// @Description 按渠道汇总当前用户最近 days 天（默认7，最大90）的通知发送记录，返回成功数、失败数与总数
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param days query int false "统计最近多少天，默认7，最大90"
+// @Success 200 {object} types.APIResponse{data=types.GetNotificationStatsResponse} "获取成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 获取统计失败"
+// @Router /api/v1/notifications/stats [get]
+func (h *NotificationHandler) GetNotificationStats(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetNotificationStats error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.GetNotificationStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationStats error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.notificationService.GetNotificationStats(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get notification stats",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetNotificationStats error", err, "user_address", userAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ValidateWebhookURL 校验 webhook URL 可达性
+// @Summary 校验 webhook URL 可达性
+// @Description 保存 Lark/飞书/Discord/Slack 等 webhook 配置前确认 URL 可达：先发送 HEAD 请求，服务端返回 404/405 时改发空 JSON 的测试 POST，返回是否可达及 HTTP 状态码。仅支持 http/https，解析后指向回环、私有、链路本地等内网地址的 URL 会被拒绝；网络不可达时返回 reachable=false 与错误信息
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body types.ValidateWebhookURLRequest true "待校验的 URL"
+// @Success 200 {object} types.APIResponse{data=types.ValidateWebhookURLResponse} "校验完成"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误; UNSAFE_URL: URL 不合法或指向内网/本机地址"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 校验失败"
+// @Router /api/v1/notifications/validate-url [post]
+func (h *NotificationHandler) ValidateWebhookURL(c *gin.Context) {
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("ValidateWebhookURL error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.ValidateWebhookURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ValidateWebhookURL error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.notificationService.ValidateWebhookURL(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsafeURL) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "UNSAFE_URL",
+					Message: "URL is invalid or points to a disallowed address",
+					Details: err.Error(),
+				},
+			})
+			logger.Warn("ValidateWebhookURL rejected unsafe url", "user_address", userAddress, "error", err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to validate webhook url",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ValidateWebhookURL error", err, "user_address", userAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// PreviewNotificationTemplate 校验并试渲染通知消息模板
+// @Summary 校验并试渲染通知消息模板
+// @Description 保存自定义消息模板前试渲染：模板使用 Go text/template 语法，模板数据为 NotificationData（.StatusFrom、.Network、.CalldataParams 等）。指定 flow 时使用真实流程数据（需与该流程相关），否则使用 sample 或内置示例数据。模板有语法错误、使用被禁用的用法（call、template/define/block、对非数据字段的 range、超过两层的 range 嵌套）或渲染失败（含输出超过 64KB、渲染超时）时返回 valid=false 及出错行列号；sample 的 calls 与每组 calldata_params 最多 50 项
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body types.PreviewNotificationTemplateRequest true "模板与渲染数据"
+// @Success 200 {object} types.APIResponse{data=types.PreviewNotificationTemplateResponse} "校验完成"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误 - INVALID_REQUEST: 请求参数格式错误或示例数据过大"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证 - UNAUTHORIZED: 用户未认证"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权限 - ACCESS_DENIED: 用户与该流程无关"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "流程不存在 - FLOW_NOT_FOUND: 指定的流程不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误 - INTERNAL_ERROR: 渲染失败"
+// @Router /api/v1/notifications/template/preview [post]
+func (h *NotificationHandler) PreviewNotificationTemplate(c *gin.Context) {
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("PreviewNotificationTemplate error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.PreviewNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("PreviewNotificationTemplate error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.notificationService.PreviewNotificationTemplate(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, notification.ErrTemplateSampleTooLarge):
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_REQUEST",
+					Message: "Invalid request parameters",
+					Details: err.Error(),
+				},
+			})
+		case errors.Is(err, notification.ErrTemplateFlowNotFound):
+			c.JSON(http.StatusNotFound, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "FLOW_NOT_FOUND",
+					Message: "Flow not found",
+				},
+			})
+		case errors.Is(err, notification.ErrTemplateFlowAccessDenied):
+			c.JSON(http.StatusForbidden, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "ACCESS_DENIED",
+					Message: "You have no permission to access this flow",
+				},
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INTERNAL_ERROR",
+					Message: "Failed to preview notification template",
+					Details: err.Error(),
+				},
+			})
+			logger.Error("PreviewNotificationTemplate error", err, "user_address", userAddress)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// checkRecentAuth 查看敏感字段明文前校验最近是否重新签名登录，未通过时写入 403 并返回 false
+func (h *NotificationHandler) checkRecentAuth(c *gin.Context, action, userAddress string) bool {
+	if middleware.HasRecentAuth(c, h.reauthMaxAge) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, types.APIResponse{
+		Success: false,
+		Error: &types.APIError{
+			Code:    "REAUTH_REQUIRED",
+			Message: "Revealing secrets requires a recent wallet signature login",
+			Details: "sign in again with your wallet (token refresh and API keys do not count)",
+		},
+	})
+	logger.Warn(action+" reveal rejected, re-authentication required", "user_address", userAddress)
+	return false
+}
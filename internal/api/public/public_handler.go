@@ -1,6 +1,7 @@
 package public
 
 import (
+	"errors"
 	"net/http"
 	"timelocker-backend/internal/service/public"
 	"timelocker-backend/internal/types"
@@ -30,6 +31,11 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		// POST /api/v1/public/stats
 		// http://localhost:8080/api/v1/public/stats
 		publicGroup.POST("/stats", h.GetStats)
+
+		// 获取统计历史（趋势图）
+		// GET /api/v1/public/stats/history
+		// http://localhost:8080/api/v1/public/stats/history?from=2026-10-01T00:00:00Z&to=2026-11-01T00:00:00Z&page=1&page_size=100
+		publicGroup.GET("/stats/history", h.GetStatsHistory)
 	}
 }
 
@@ -83,3 +89,61 @@ func (h *Handler) GetStats(c *gin.Context) {
 		Data:    response,
 	})
 }
+
+// GetStatsHistory 获取统计历史
+// @Summary 获取首页统计历史
+// @Description 获取定时任务记录的全局统计快照（链数量、timelock合约数量、交易数量），按快照时间升序分页，用于趋势图，无需认证
+// @Tags Public
+// @Produce json
+// @Param from query string false "时间下限（含），RFC3339"
+// @Param to query string false "时间上限（不含），RFC3339"
+// @Param page query int false "页码，默认为1"
+// @Param page_size query int false "每页大小，默认为100，最大100"
+// @Success 200 {object} types.APIResponse{data=types.GetStatsHistoryResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/public/stats/history [get]
+func (h *Handler) GetStatsHistory(c *gin.Context) {
+	var req types.GetStatsHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid query parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.publicService.GetStatsHistory(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, public.ErrInvalidStatsRange) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_REQUEST",
+					Message: "Invalid query parameters",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		logger.Error("GetStatsHistory Service Error: ", err)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get stats history",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -1,7 +1,9 @@
 package timelock
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"timelocker-backend/internal/middleware"
@@ -37,7 +39,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		// 创建或导入timelock合约
 		// POST /api/v1/timelock/create-or-import
 		// http://localhost:8080/api/v1/timelock/create-or-import
-		timeLockGroup.POST("/create-or-import", h.CreateOrImportTimeLock)
+		timeLockGroup.POST("/create-or-import", middleware.RequireWriteScope(), h.CreateOrImportTimeLock)
 
 		// 获取timelock列表（根据用户权限筛选）
 		// POST /api/v1/timelock/list
@@ -49,26 +51,76 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 		// http://localhost:8080/api/v1/timelock/detail
 		timeLockGroup.POST("/detail", h.GetTimeLockDetail)
 
+		// 获取用户有合约的链（前端链筛选下拉框）
+		// GET /api/v1/timelock/chains
+		// http://localhost:8080/api/v1/timelock/chains
+		timeLockGroup.GET("/chains", h.GetTimeLockChains)
+
+		// 检查合约是否已被当前用户导入（前端用于置灰导入按钮）
+		// GET /api/v1/timelock/exists?chain_id=&contract_address=
+		// http://localhost:8080/api/v1/timelock/exists?chain_id=1&contract_address=0x...
+		timeLockGroup.GET("/exists", h.CheckTimeLockExists)
+
+		// 实时读取合约链上参数并与数据库对比
+		// GET /api/v1/timelock/:id/onchain?standard=
+		// http://localhost:8080/api/v1/timelock/1/onchain?standard=compound
+		timeLockGroup.GET("/:id/onchain", h.GetTimeLockOnchainParams)
+
+		// 批量查询地址在合约中的角色
+		// POST /api/v1/timelock/:id/check-roles
+		// http://localhost:8080/api/v1/timelock/1/check-roles
+		timeLockGroup.POST("/:id/check-roles", h.CheckTimeLockRoles)
+
+		// 导出可分享的合约配置清单（仅导入者）
+		// GET /api/v1/timelock/:id/manifest?standard=
+		// http://localhost:8080/api/v1/timelock/1/manifest?standard=compound
+		timeLockGroup.GET("/:id/manifest", h.GetTimeLockManifest)
+
+		// 导入他人分享的合约配置清单
+		// POST /api/v1/timelock/import-manifest
+		// http://localhost:8080/api/v1/timelock/import-manifest
+		timeLockGroup.POST("/import-manifest", middleware.RequireWriteScope(), h.ImportTimeLockManifest)
+
 		// 更新timelock备注
 		// POST /api/v1/timelock/update
 		// http://localhost:8080/api/v1/timelock/update
-		timeLockGroup.POST("/update", h.UpdateTimeLock)
+		timeLockGroup.POST("/update", middleware.RequireWriteScope(), h.UpdateTimeLock)
+
+		// 批量更新timelock备注/昵称
+		// POST /api/v1/timelock/update-remarks
+		// http://localhost:8080/api/v1/timelock/update-remarks
+		timeLockGroup.POST("/update-remarks", middleware.RequireWriteScope(), h.BulkUpdateTimeLockRemarks)
 
 		// 删除timelock
 		// POST /api/v1/timelock/delete
 		// http://localhost:8080/api/v1/timelock/delete
-		timeLockGroup.POST("/delete", h.DeleteTimeLock)
+		timeLockGroup.POST("/delete", middleware.RequireWriteScope(), h.DeleteTimeLock)
 
 		// 刷新用户所有timelock合约权限
 		// POST /api/v1/timelock/refresh-permissions
 		// http://localhost:8080/api/v1/timelock/refresh-permissions
-		timeLockGroup.POST("/refresh-permissions", h.RefreshTimeLockPermissions)
+		timeLockGroup.POST("/refresh-permissions", middleware.RequireWriteScope(), h.RefreshTimeLockPermissions)
+
+		// 计算 Compound queueTransaction 将产生的 txHash
+		// POST /api/v1/timelock/compound-txhash
+		// http://localhost:8080/api/v1/timelock/compound-txhash
+		timeLockGroup.POST("/compound-txhash", h.ComputeCompoundTxHash)
+
+		// 计算 OpenZeppelin 单笔操作 id
+		// POST /api/v1/timelock/oz-operation-id
+		// http://localhost:8080/api/v1/timelock/oz-operation-id
+		timeLockGroup.POST("/oz-operation-id", h.ComputeOzOperationId)
+
+		// 计算 OpenZeppelin 批量操作 id
+		// POST /api/v1/timelock/oz-operation-id/batch
+		// http://localhost:8080/api/v1/timelock/oz-operation-id/batch
+		timeLockGroup.POST("/oz-operation-id/batch", h.ComputeOzOperationBatchId)
 	}
 }
 
 // CreateOrImportTimeLock 创建或导入timelock合约
 // @Summary 创建或导入timelock合约记录
-// @Description 创建新的或导入已存在的timelock合约记录。系统会从链上读取合约数据并验证其是否为有效的timelock合约。支持Compound和OpenZeppelin两种标准。合约地址必须为有效以太坊地址（0x + 40位十六进制）。
+// @Description 创建新的或导入已存在的timelock合约记录。系统会从链上读取合约数据并验证其是否为有效的timelock合约。支持Compound和OpenZeppelin两种标准。合约地址必须为有效以太坊地址（0x + 40位十六进制）；也可只提供部署交易哈希 creation_tx_hash，由系统从交易回执（或工厂合约的 CREATE 调用）解析合约地址。链上 delay 低于配置的安全阈值时仍会导入，但响应中的 delay_warning 非空，列表与详情中同样返回该标记。
 // @Tags Timelock
 // @Accept json
 // @Produce json
@@ -77,6 +129,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 // @Success 200 {object} types.APIResponse{data=object} "成功创建或导入timelock合约记录"
 // @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或标准/地址无效（INVALID_STANDARD / INVALID_CONTRACT_ADDRESS）"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "部署交易不存在（CREATION_TX_NOT_FOUND）"
 // @Failure 409 {object} types.APIResponse{error=types.APIError} "timelock合约已存在"
 // @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
@@ -113,7 +166,9 @@ func (h *Handler) CreateOrImportTimeLock(c *gin.Context) {
 	// 标准化
 	req.Standard = strings.ToLower(strings.TrimSpace(req.Standard))
 	req.ContractAddress = strings.TrimSpace(req.ContractAddress)
-	if !crypto.ValidateEthereumAddress(req.ContractAddress) {
+	req.CreationTxHash = strings.TrimSpace(req.CreationTxHash)
+	// 通过部署交易导入时合约地址可为空，由 service 层从交易回执解析
+	if (req.ContractAddress != "" || req.CreationTxHash == "") && !crypto.ValidateEthereumAddress(req.ContractAddress) {
 		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_CONTRACT_ADDRESS", Message: "Invalid contract address"}})
 		return
 	}
@@ -124,28 +179,34 @@ func (h *Handler) CreateOrImportTimeLock(c *gin.Context) {
 		var statusCode int
 		var errorCode string
 
-		switch err {
-		case timelock.ErrTimeLockExists:
+		switch {
+		case errors.Is(err, timelock.ErrTimeLockExists):
 			statusCode = http.StatusConflict
 			errorCode = "TIMELOCK_EXISTS"
-		case timelock.ErrInvalidContractParams:
+		case errors.Is(err, timelock.ErrInvalidContractParams):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_PARAMETERS"
-		case timelock.ErrInvalidStandard:
+		case errors.Is(err, timelock.ErrInvalidStandard):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_STANDARD"
-		case timelock.ErrInvalidRemark:
+		case errors.Is(err, timelock.ErrInvalidRemark):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_REMARK"
-		case timelock.ErrChainNotSupported:
+		case errors.Is(err, timelock.ErrInvalidNickname):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_NICKNAME"
+		case errors.Is(err, timelock.ErrChainNotSupported):
 			statusCode = http.StatusBadRequest
 			errorCode = "CHAIN_NOT_SUPPORTED"
-		case timelock.ErrRPCConnection:
+		case errors.Is(err, timelock.ErrRPCConnection):
 			statusCode = http.StatusServiceUnavailable
 			errorCode = "RPC_CONNECTION_ERROR"
-		case timelock.ErrContractNotTimelock:
+		case errors.Is(err, timelock.ErrContractNotTimelock):
 			statusCode = http.StatusBadRequest
 			errorCode = "CONTRACT_NOT_TIMELOCK"
+		case errors.Is(err, timelock.ErrCreationTxNotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "CREATION_TX_NOT_FOUND"
 		default:
 			statusCode = http.StatusInternalServerError
 			errorCode = "INTERNAL_ERROR"
@@ -353,23 +414,23 @@ func (h *Handler) GetTimeLockDetail(c *gin.Context) {
 	})
 }
 
-// UpdateTimeLock 更新timelock备注
-// @Summary 更新timelock合约备注
-// @Description 更新指定timelock合约的备注信息。只有合约的创建者/导入者才能更新备注。备注信息用于帮助用户管理和识别不同的timelock合约。合约地址必须为有效以太坊地址（0x + 40位十六进制）。
+// GetTimeLockOnchainParams 实时读取合约链上参数
+// @Summary 实时读取timelock合约链上参数
+// @Description 通过 RPC 实时读取合约当前的链上参数，并与数据库中保存的值逐项对比，用于确认数据库是否与链上同步。Compound 返回 delay、admin、pending_admin、grace_period、minimum_delay、maximum_delay；OpenZeppelin 返回 delay 以及 admin、proposers、executors（TimelockController 不支持枚举角色成员，链上值为数据库中仍持有该角色的成员）。仅合约导入者可查看。
 // @Tags Timelock
-// @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body types.UpdateTimeLockRequest true "更新请求体（地址从鉴权获取）"
-// @Success 200 {object} types.APIResponse{data=object} "成功更新timelock合约备注"
-// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或标准/地址无效（INVALID_STANDARD / INVALID_CONTRACT_ADDRESS）"
+// @Param id path int true "timelock记录ID"
+// @Param standard query string true "合约标准（compound/openzeppelin）"
+// @Success 200 {object} types.APIResponse{data=types.GetTimeLockOnchainParamsResponse} "读取成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或合约不再响应 timelock 接口（CONTRACT_NOT_TIMELOCK）"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
-// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权访问此timelock合约"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该合约"
 // @Failure 404 {object} types.APIResponse{error=types.APIError} "timelock合约不存在"
-// @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
+// @Failure 503 {object} types.APIResponse{error=types.APIError} "RPC 不可用（RPC_CONNECTION_ERROR）"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
-// @Router /api/v1/timelock/update [post]
-func (h *Handler) UpdateTimeLock(c *gin.Context) {
+// @Router /api/v1/timelock/{id}/onchain [get]
+func (h *Handler) GetTimeLockOnchainParams(c *gin.Context) {
 	// 从上下文获取用户信息
 	_, userAddress, ok := middleware.GetUserFromContext(c)
 	if !ok {
@@ -380,64 +441,156 @@ func (h *Handler) UpdateTimeLock(c *gin.Context) {
 				Message: "User not authenticated",
 			},
 		})
-		logger.Error("UpdateTimeLock error", nil, "message", "user not authenticated")
+		logger.Error("GetTimeLockOnchainParams error", nil, "message", "user not authenticated")
 		return
 	}
 
-	var req types.UpdateTimeLockRequest
-	// 绑定请求参数
-	if err := c.ShouldBindJSON(&req); err != nil {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_REQUEST", Message: "Invalid timelock id"}})
+		return
+	}
+
+	var req types.GetTimeLockOnchainParamsRequest
+	req.Standard = strings.ToLower(strings.TrimSpace(c.Query("standard")))
+	if req.Standard != "compound" && req.Standard != "openzeppelin" {
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
-				Code:    "INVALID_REQUEST",
-				Message: "Invalid request parameters",
-				Details: err.Error(),
+				Code:    "INVALID_STANDARD",
+				Message: "Invalid timelock standard",
 			},
 		})
-		logger.Error("UpdateTimeLock error", err, "message", "invalid request parameters", "user_address", userAddress)
+		logger.Error("GetTimeLockOnchainParams error", nil, "message", "invalid timelock standard", "standard", req.Standard, "user_address", userAddress)
 		return
 	}
-	// 标准化
-	req.Standard = strings.ToLower(strings.TrimSpace(req.Standard))
-	req.ContractAddress = strings.TrimSpace(req.ContractAddress)
-	if !crypto.ValidateEthereumAddress(req.ContractAddress) {
-		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_CONTRACT_ADDRESS", Message: "Invalid contract address"}})
+
+	response, err := h.timeLockService.GetTimeLockOnchainParams(c.Request.Context(), userAddress, id, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, timelock.ErrTimeLockNotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "TIMELOCK_NOT_FOUND"
+		case errors.Is(err, timelock.ErrUnauthorized):
+			statusCode = http.StatusForbidden
+			errorCode = "UNAUTHORIZED_ACCESS"
+		case errors.Is(err, timelock.ErrInvalidStandard):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_STANDARD"
+		case errors.Is(err, timelock.ErrContractNotTimelock):
+			statusCode = http.StatusBadRequest
+			errorCode = "CONTRACT_NOT_TIMELOCK"
+		case errors.Is(err, timelock.ErrRPCConnection):
+			statusCode = http.StatusServiceUnavailable
+			errorCode = "RPC_CONNECTION_ERROR"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("GetTimeLockOnchainParams error", err, "user_address", userAddress, "id", id, "standard", req.Standard, "error_code", errorCode)
 		return
 	}
 
-	// 验证标准
-	if req.Standard != "compound" && req.Standard != "openzeppelin" {
+	logger.Info("GetTimeLockOnchainParams success", "user_address", userAddress, "id", id, "standard", req.Standard, "in_sync", response.InSync)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// CheckTimeLockRoles 批量查询地址在合约中的角色
+// @Summary 批量查询地址在timelock合约中的角色
+// @Description 传入一组地址，返回每个地址在指定合约中的角色：Compound 为 creator/admin/pending_admin，OpenZeppelin 为 creator/proposer/executor/admin。默认基于数据库数据计算；live=true 时额外通过 RPC 实时读取链上角色（onchain_roles）。单次最多 100 个地址，需对该合约有查看权限。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "timelock记录ID"
+// @Param request body types.CheckTimeLockRolesRequest true "标准与地址列表"
+// @Success 200 {object} types.APIResponse{data=types.CheckTimeLockRolesResponse} "查询成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或地址无效（INVALID_ADDRESS）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权查看该合约"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "timelock合约不存在"
+// @Failure 503 {object} types.APIResponse{error=types.APIError} "RPC 不可用（RPC_CONNECTION_ERROR）"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/{id}/check-roles [post]
+func (h *Handler) CheckTimeLockRoles(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("CheckTimeLockRoles error", nil, "message", "user not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_REQUEST", Message: "Invalid timelock id"}})
+		return
+	}
+
+	var req types.CheckTimeLockRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, types.APIResponse{
 			Success: false,
 			Error: &types.APIError{
-				Code:    "INVALID_STANDARD",
-				Message: "Invalid timelock standard",
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
 			},
 		})
-		logger.Error("UpdateTimeLock error", nil, "message", "invalid timelock standard", "standard", req.Standard, "user_address", userAddress)
+		logger.Error("CheckTimeLockRoles error", err, "message", "invalid request parameters", "user_address", userAddress)
 		return
 	}
+	req.Standard = strings.ToLower(strings.TrimSpace(req.Standard))
+	for i, addr := range req.Addresses {
+		addr = strings.TrimSpace(addr)
+		if !crypto.ValidateEthereumAddress(addr) {
+			c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_ADDRESS", Message: "Invalid address", Details: addr}})
+			return
+		}
+		req.Addresses[i] = addr
+	}
 
-	// 调用service层（地址从鉴权中获取）
-	err := h.timeLockService.UpdateTimeLock(c.Request.Context(), userAddress, &req)
+	response, err := h.timeLockService.CheckTimeLockRoles(c.Request.Context(), userAddress, id, &req)
 	if err != nil {
 		var statusCode int
 		var errorCode string
 
-		switch err {
-		case timelock.ErrTimeLockNotFound:
+		switch {
+		case errors.Is(err, timelock.ErrTimeLockNotFound):
 			statusCode = http.StatusNotFound
 			errorCode = "TIMELOCK_NOT_FOUND"
-		case timelock.ErrUnauthorized:
+		case errors.Is(err, timelock.ErrUnauthorized):
 			statusCode = http.StatusForbidden
 			errorCode = "UNAUTHORIZED_ACCESS"
-		case timelock.ErrInvalidStandard:
+		case errors.Is(err, timelock.ErrInvalidStandard):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_STANDARD"
-		case timelock.ErrInvalidRemark:
+		case errors.Is(err, timelock.ErrContractNotTimelock):
 			statusCode = http.StatusBadRequest
-			errorCode = "INVALID_REMARK"
+			errorCode = "CONTRACT_NOT_TIMELOCK"
+		case errors.Is(err, timelock.ErrRPCConnection):
+			statusCode = http.StatusServiceUnavailable
+			errorCode = "RPC_CONNECTION_ERROR"
 		default:
 			statusCode = http.StatusInternalServerError
 			errorCode = "INTERNAL_ERROR"
@@ -450,34 +603,146 @@ func (h *Handler) UpdateTimeLock(c *gin.Context) {
 				Message: err.Error(),
 			},
 		})
-		logger.Error("UpdateTimeLock error", err, "user_address", userAddress, "standard", req.Standard, "error_code", errorCode)
+		logger.Error("CheckTimeLockRoles error", err, "user_address", userAddress, "id", id, "standard", req.Standard, "error_code", errorCode)
 		return
 	}
 
-	logger.Info("UpdateTimeLock success", "user_address", userAddress, "standard", req.Standard)
+	logger.Info("CheckTimeLockRoles success", "user_address", userAddress, "id", id, "standard", req.Standard, "address_count", len(req.Addresses), "live", req.Live)
 	c.JSON(http.StatusOK, types.APIResponse{
 		Success: true,
-		Data:    gin.H{"message": "Timelock updated successfully"},
+		Data:    response,
 	})
 }
 
-// DeleteTimeLock 删除timelock
-// @Summary 删除timelock合约记录
-// @Description 硬删除指定的timelock合约记录。只有合约的创建者/导入者才能删除合约记录。删除操作是硬删除，数据从数据库中删除。合约地址必须为有效以太坊地址（0x + 40位十六进制）。
+// GetTimeLockChains 获取用户有合约的链
+// @Summary 获取用户有合约的链
+// @Description 返回当前用户有timelock合约的链（去重，按链ID升序），范围与合约列表一致：用户为创建者、管理员、待定管理员、提议者或执行者，且合约未删除。展示名称与Logo来自支持链配置，并附带每条链上的合约数，用于前端链筛选下拉框。
+// @Tags Timelock
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.GetTimeLockChainsResponse} "获取成功"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/chains [get]
+func (h *Handler) GetTimeLockChains(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetTimeLockChains error", nil, "message", "user not authenticated")
+		return
+	}
+
+	response, err := h.timeLockService.GetTimeLockChains(c.Request.Context(), userAddress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get timelock chains",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("GetTimeLockChains error", err, "user_address", userAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// CheckTimeLockExists 检查合约是否已被当前用户导入
+// @Summary 检查timelock合约是否已导入
+// @Description 检查当前用户是否已导入指定链上的合约（Compound 与 OpenZeppelin 都会检查），已导入时返回记录ID、标准与状态（active/inactive）；已删除的合约不保留记录，返回 exists=false，前端可据此置灰导入按钮，避免导入失败的往返请求。
+// @Tags Timelock
+// @Produce json
+// @Security BearerAuth
+// @Param chain_id query int true "链ID"
+// @Param contract_address query string true "合约地址"
+// @Success 200 {object} types.APIResponse{data=types.CheckTimeLockExistsResponse} "检查成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或地址无效（INVALID_CONTRACT_ADDRESS）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/exists [get]
+func (h *Handler) CheckTimeLockExists(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("CheckTimeLockExists error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.CheckTimeLockExistsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid query parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("CheckTimeLockExists error", err, "message", "invalid query parameters", "user_address", userAddress)
+		return
+	}
+	req.ContractAddress = strings.TrimSpace(req.ContractAddress)
+	if !crypto.ValidateEthereumAddress(req.ContractAddress) {
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_CONTRACT_ADDRESS", Message: "Invalid contract address"}})
+		return
+	}
+
+	response, err := h.timeLockService.CheckTimeLockExists(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to check timelock existence",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("CheckTimeLockExists error", err, "user_address", userAddress, "chain_id", req.ChainID, "contract_address", req.ContractAddress)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// UpdateTimeLock 更新timelock备注
+// @Summary 更新timelock合约备注
+// @Description 更新指定timelock合约的备注信息。只有合约的创建者/导入者才能更新备注。备注信息用于帮助用户管理和识别不同的timelock合约。合约地址必须为有效以太坊地址（0x + 40位十六进制）。
 // @Tags Timelock
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body types.DeleteTimeLockRequest true "删除请求体（地址从鉴权获取）"
-// @Success 200 {object} types.APIResponse{data=object} "成功删除timelock合约记录"
+// @Param request body types.UpdateTimeLockRequest true "更新请求体（地址从鉴权获取）"
+// @Success 200 {object} types.APIResponse{data=object} "成功更新timelock合约备注"
 // @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或标准/地址无效（INVALID_STANDARD / INVALID_CONTRACT_ADDRESS）"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
 // @Failure 403 {object} types.APIResponse{error=types.APIError} "无权访问此timelock合约"
 // @Failure 404 {object} types.APIResponse{error=types.APIError} "timelock合约不存在"
 // @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
-// @Router /api/v1/timelock/delete [post]
-func (h *Handler) DeleteTimeLock(c *gin.Context) {
+// @Router /api/v1/timelock/update [post]
+func (h *Handler) UpdateTimeLock(c *gin.Context) {
 	// 从上下文获取用户信息
 	_, userAddress, ok := middleware.GetUserFromContext(c)
 	if !ok {
@@ -488,11 +753,11 @@ func (h *Handler) DeleteTimeLock(c *gin.Context) {
 				Message: "User not authenticated",
 			},
 		})
-		logger.Error("DeleteTimeLock error", nil, "message", "user not authenticated")
+		logger.Error("UpdateTimeLock error", nil, "message", "user not authenticated")
 		return
 	}
 
-	var req types.DeleteTimeLockRequest
+	var req types.UpdateTimeLockRequest
 	// 绑定请求参数
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, types.APIResponse{
@@ -503,7 +768,7 @@ func (h *Handler) DeleteTimeLock(c *gin.Context) {
 				Details: err.Error(),
 			},
 		})
-		logger.Error("DeleteTimeLock error", err, "message", "invalid request parameters", "user_address", userAddress)
+		logger.Error("UpdateTimeLock error", err, "message", "invalid request parameters", "user_address", userAddress)
 		return
 	}
 	// 标准化
@@ -523,26 +788,32 @@ func (h *Handler) DeleteTimeLock(c *gin.Context) {
 				Message: "Invalid timelock standard",
 			},
 		})
-		logger.Error("DeleteTimeLock error", nil, "message", "invalid timelock standard", "standard", req.Standard, "user_address", userAddress)
+		logger.Error("UpdateTimeLock error", nil, "message", "invalid timelock standard", "standard", req.Standard, "user_address", userAddress)
 		return
 	}
 
 	// 调用service层（地址从鉴权中获取）
-	err := h.timeLockService.DeleteTimeLock(c.Request.Context(), userAddress, &req)
+	err := h.timeLockService.UpdateTimeLock(c.Request.Context(), userAddress, &req)
 	if err != nil {
 		var statusCode int
 		var errorCode string
 
-		switch err {
-		case timelock.ErrTimeLockNotFound:
+		switch {
+		case errors.Is(err, timelock.ErrTimeLockNotFound):
 			statusCode = http.StatusNotFound
 			errorCode = "TIMELOCK_NOT_FOUND"
-		case timelock.ErrUnauthorized:
+		case errors.Is(err, timelock.ErrUnauthorized):
 			statusCode = http.StatusForbidden
 			errorCode = "UNAUTHORIZED_ACCESS"
-		case timelock.ErrInvalidStandard:
+		case errors.Is(err, timelock.ErrInvalidStandard):
 			statusCode = http.StatusBadRequest
 			errorCode = "INVALID_STANDARD"
+		case errors.Is(err, timelock.ErrInvalidRemark):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_REMARK"
+		case errors.Is(err, timelock.ErrInvalidNickname):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_NICKNAME"
 		default:
 			statusCode = http.StatusInternalServerError
 			errorCode = "INTERNAL_ERROR"
@@ -555,28 +826,184 @@ func (h *Handler) DeleteTimeLock(c *gin.Context) {
 				Message: err.Error(),
 			},
 		})
-		logger.Error("DeleteTimeLock error", err, "user_address", userAddress, "standard", req.Standard, "error_code", errorCode)
+		logger.Error("UpdateTimeLock error", err, "user_address", userAddress, "standard", req.Standard, "error_code", errorCode)
 		return
 	}
 
-	logger.Info("DeleteTimeLock success", "user_address", userAddress, "standard", req.Standard)
+	logger.Info("UpdateTimeLock success", "user_address", userAddress, "standard", req.Standard)
 	c.JSON(http.StatusOK, types.APIResponse{
 		Success: true,
-		Data:    gin.H{"message": "Timelock deleted successfully"},
+		Data:    gin.H{"message": "Timelock updated successfully"},
 	})
 }
 
-// RefreshTimeLockPermissions 刷新用户所有timelock合约权限
-// @Summary 刷新用户所有timelock合约权限
-// @Description 刷新该用户在库中所有timelock合约的权限，将合约中所有角色获取一遍然后更新数据库。这个操作会从区块链上重新读取用户在各个timelock合约中的最新权限信息。
+// BulkUpdateTimeLockRemarks 批量更新timelock备注/昵称
+// @Summary 批量更新timelock合约备注/昵称
+// @Description 按合约ID批量更新备注与昵称，每项单独校验所有权（只有合约的创建者/导入者才能更新），单项失败不影响其他项。remark/nickname 不传时保持不变，nickname 传空字符串时清除。每次最多100项，结果与请求顺序一致。
 // @Tags Timelock
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} types.APIResponse{data=object} "成功刷新权限"
+// @Param request body types.BulkUpdateTimeLockRemarksRequest true "批量更新请求体（地址从鉴权获取）"
+// @Success 200 {object} types.APIResponse{data=types.BulkUpdateTimeLockRemarksResponse} "逐项更新结果"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
 // @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
 // @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
-// @Router /api/v1/timelock/refresh-permissions [post]
+// @Router /api/v1/timelock/update-remarks [post]
+func (h *Handler) BulkUpdateTimeLockRemarks(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("BulkUpdateTimeLockRemarks error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.BulkUpdateTimeLockRemarksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("BulkUpdateTimeLockRemarks error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response := h.timeLockService.BulkUpdateTimeLockRemarks(c.Request.Context(), userAddress, &req)
+
+	logger.Info("BulkUpdateTimeLockRemarks success", "user_address", userAddress, "updated", response.Updated, "failed", response.Failed)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// DeleteTimeLock 删除timelock
+// @Summary 删除timelock合约记录
+// @Description 硬删除指定的timelock合约记录。只有合约的创建者/导入者才能删除合约记录。删除操作是硬删除，数据从数据库中删除。合约地址必须为有效以太坊地址（0x + 40位十六进制）。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.DeleteTimeLockRequest true "删除请求体（地址从鉴权获取）"
+// @Success 200 {object} types.APIResponse{data=object} "成功删除timelock合约记录"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或标准/地址无效（INVALID_STANDARD / INVALID_CONTRACT_ADDRESS）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "无权访问此timelock合约"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "timelock合约不存在"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/delete [post]
+func (h *Handler) DeleteTimeLock(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("DeleteTimeLock error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.DeleteTimeLockRequest
+	// 绑定请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("DeleteTimeLock error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+	// 标准化
+	req.Standard = strings.ToLower(strings.TrimSpace(req.Standard))
+	req.ContractAddress = strings.TrimSpace(req.ContractAddress)
+	if !crypto.ValidateEthereumAddress(req.ContractAddress) {
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_CONTRACT_ADDRESS", Message: "Invalid contract address"}})
+		return
+	}
+
+	// 验证标准
+	if req.Standard != "compound" && req.Standard != "openzeppelin" {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_STANDARD",
+				Message: "Invalid timelock standard",
+			},
+		})
+		logger.Error("DeleteTimeLock error", nil, "message", "invalid timelock standard", "standard", req.Standard, "user_address", userAddress)
+		return
+	}
+
+	// 调用service层（地址从鉴权中获取）
+	err := h.timeLockService.DeleteTimeLock(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch err {
+		case timelock.ErrTimeLockNotFound:
+			statusCode = http.StatusNotFound
+			errorCode = "TIMELOCK_NOT_FOUND"
+		case timelock.ErrUnauthorized:
+			statusCode = http.StatusForbidden
+			errorCode = "UNAUTHORIZED_ACCESS"
+		case timelock.ErrInvalidStandard:
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_STANDARD"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("DeleteTimeLock error", err, "user_address", userAddress, "standard", req.Standard, "error_code", errorCode)
+		return
+	}
+
+	logger.Info("DeleteTimeLock success", "user_address", userAddress, "standard", req.Standard)
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    gin.H{"message": "Timelock deleted successfully"},
+	})
+}
+
+// RefreshTimeLockPermissions 刷新用户所有timelock合约权限
+// @Summary 刷新用户所有timelock合约权限
+// @Description 刷新该用户在库中所有timelock合约的权限，将合约中所有角色获取一遍然后更新数据库。这个操作会从区块链上重新读取用户在各个timelock合约中的最新权限信息。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=object} "成功刷新权限"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/refresh-permissions [post]
 func (h *Handler) RefreshTimeLockPermissions(c *gin.Context) {
 	// 从上下文获取用户信息
 	_, userAddress, ok := middleware.GetUserFromContext(c)
@@ -612,3 +1039,324 @@ func (h *Handler) RefreshTimeLockPermissions(c *gin.Context) {
 		Data:    gin.H{"message": "Permissions refreshed successfully"},
 	})
 }
+
+// ComputeCompoundTxHash 计算 Compound txHash
+// @Summary 计算 Compound queueTransaction 的 txHash
+// @Description 按 Compound Timelock 的算法 keccak256(abi.encode(target, value, signature, data, eta)) 计算 txHash，结果与流程的 flow_id 一致，前端可在索引完成前关联待处理交易与流程。value 为十进制 wei 字符串，data 为不含函数选择器的 0x 十六进制参数。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.ComputeCompoundTxHashRequest true "交易参数"
+// @Success 200 {object} types.APIResponse{data=types.ComputeCompoundTxHashResponse} "计算成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误（INVALID_HASH_PARAMS）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Router /api/v1/timelock/compound-txhash [post]
+func (h *Handler) ComputeCompoundTxHash(c *gin.Context) {
+	var req types.ComputeCompoundTxHashRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.timeLockService.ComputeCompoundTxHash(c.Request.Context(), &req)
+	if err != nil {
+		writeHashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ComputeOzOperationId 计算 OpenZeppelin 单笔操作 id
+// @Summary 计算 OpenZeppelin 单笔操作 id
+// @Description 按 TimelockController.hashOperation 计算 keccak256(abi.encode(target, value, data, predecessor, salt))，结果与流程的 flow_id 一致。value 为十进制 wei 字符串，data 为完整 calldata，predecessor 与 salt 为 bytes32（为空时为全零）。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.ComputeOzOperationIdRequest true "操作参数"
+// @Success 200 {object} types.APIResponse{data=types.ComputeOzOperationIdResponse} "计算成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误（INVALID_HASH_PARAMS）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Router /api/v1/timelock/oz-operation-id [post]
+func (h *Handler) ComputeOzOperationId(c *gin.Context) {
+	var req types.ComputeOzOperationIdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.timeLockService.ComputeOzOperationId(c.Request.Context(), &req)
+	if err != nil {
+		writeHashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ComputeOzOperationBatchId 计算 OpenZeppelin 批量操作 id
+// @Summary 计算 OpenZeppelin 批量操作 id
+// @Description 按 TimelockController.hashOperationBatch 计算 keccak256(abi.encode(targets, values, payloads, predecessor, salt))，结果与流程的 flow_id 一致。targets、values、payloads 必须等长。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.ComputeOzOperationBatchIdRequest true "批量操作参数"
+// @Success 200 {object} types.APIResponse{data=types.ComputeOzOperationIdResponse} "计算成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误（INVALID_HASH_PARAMS）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Router /api/v1/timelock/oz-operation-id/batch [post]
+func (h *Handler) ComputeOzOperationBatchId(c *gin.Context) {
+	var req types.ComputeOzOperationBatchIdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.timeLockService.ComputeOzOperationBatchId(c.Request.Context(), &req)
+	if err != nil {
+		writeHashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// writeHashError 输出 txHash / operation id 计算错误
+func writeHashError(c *gin.Context, err error) {
+	if errors.Is(err, timelock.ErrInvalidHashParams) {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_HASH_PARAMS",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	logger.Error("Compute hash error", err)
+	c.JSON(http.StatusInternalServerError, types.APIResponse{
+		Success: false,
+		Error: &types.APIError{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to compute hash",
+			Details: err.Error(),
+		},
+	})
+}
+
+// GetTimeLockManifest 导出合约配置清单
+// @Summary 导出可分享的timelock合约配置清单
+// @Description 导出合约地址、链、标准、备注、导出者解码该合约流程时使用过的 ABI 以及通知渠道开关，供团队新成员通过 /timelock/import-manifest 导入。清单不包含导出者地址、通知配置（bot token、webhook 等凭证）等用户私有信息。仅合约导入者可导出。
+// @Tags Timelock
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "timelock记录ID"
+// @Param standard query string true "合约标准compound, openzeppelin"
+// @Success 200 {object} types.APIResponse{data=types.TimeLockManifest} "导出成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或标准无效"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 403 {object} types.APIResponse{error=types.APIError} "非合约导入者"
+// @Failure 404 {object} types.APIResponse{error=types.APIError} "timelock合约不存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/{id}/manifest [get]
+func (h *Handler) GetTimeLockManifest(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("GetTimeLockManifest error", nil, "message", "user not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, types.APIResponse{Success: false, Error: &types.APIError{Code: "INVALID_REQUEST", Message: "Invalid timelock id"}})
+		return
+	}
+
+	var req types.GetTimeLockManifestRequest
+	req.Standard = strings.ToLower(strings.TrimSpace(c.Query("standard")))
+	if req.Standard != "compound" && req.Standard != "openzeppelin" {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_STANDARD",
+				Message: "Invalid timelock standard",
+			},
+		})
+		return
+	}
+
+	manifest, err := h.timeLockService.GetTimeLockManifest(c.Request.Context(), userAddress, id, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, timelock.ErrTimeLockNotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "TIMELOCK_NOT_FOUND"
+		case errors.Is(err, timelock.ErrUnauthorized):
+			statusCode = http.StatusForbidden
+			errorCode = "UNAUTHORIZED_ACCESS"
+		case errors.Is(err, timelock.ErrInvalidStandard):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_STANDARD"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("GetTimeLockManifest error", err, "user_address", userAddress, "id", id, "standard", req.Standard, "error_code", errorCode)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    manifest,
+	})
+}
+
+// ImportTimeLockManifest 导入合约配置清单
+// @Summary 导入timelock合约配置清单
+// @Description 按清单导入合约（与 create-or-import 相同的链上校验，合约已导入时返回 409），然后为当前用户创建清单中的 ABI（已能访问内容相同的 ABI 时复用，同名冲突时记为 failed）。apply_notification_defaults 为 true 时用清单中的渠道开关覆盖自己的设置。
+// @Tags Timelock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.ImportTimeLockManifestRequest true "清单与导入选项"
+// @Success 200 {object} types.APIResponse{data=types.ImportTimeLockManifestResponse} "导入成功"
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误或清单无效（INVALID_MANIFEST）"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 409 {object} types.APIResponse{error=types.APIError} "timelock合约已存在"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/timelock/import-manifest [post]
+func (h *Handler) ImportTimeLockManifest(c *gin.Context) {
+	// 从上下文获取用户信息
+	_, userAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		logger.Error("ImportTimeLockManifest error", nil, "message", "user not authenticated")
+		return
+	}
+
+	var req types.ImportTimeLockManifestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		logger.Error("ImportTimeLockManifest error", err, "message", "invalid request parameters", "user_address", userAddress)
+		return
+	}
+
+	response, err := h.timeLockService.ImportTimeLockManifest(c.Request.Context(), userAddress, &req)
+	if err != nil {
+		var statusCode int
+		var errorCode string
+
+		switch {
+		case errors.Is(err, timelock.ErrInvalidManifest):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_MANIFEST"
+		case errors.Is(err, timelock.ErrTimeLockExists):
+			statusCode = http.StatusConflict
+			errorCode = "TIMELOCK_EXISTS"
+		case errors.Is(err, timelock.ErrInvalidContractParams):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_PARAMETERS"
+		case errors.Is(err, timelock.ErrInvalidStandard):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_STANDARD"
+		case errors.Is(err, timelock.ErrInvalidRemark):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_REMARK"
+		case errors.Is(err, timelock.ErrInvalidNickname):
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_NICKNAME"
+		case errors.Is(err, timelock.ErrChainNotSupported):
+			statusCode = http.StatusBadRequest
+			errorCode = "CHAIN_NOT_SUPPORTED"
+		case errors.Is(err, timelock.ErrRPCConnection):
+			statusCode = http.StatusServiceUnavailable
+			errorCode = "RPC_CONNECTION_ERROR"
+		case errors.Is(err, timelock.ErrContractNotTimelock):
+			statusCode = http.StatusBadRequest
+			errorCode = "CONTRACT_NOT_TIMELOCK"
+		default:
+			statusCode = http.StatusInternalServerError
+			errorCode = "INTERNAL_ERROR"
+		}
+
+		c.JSON(statusCode, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    errorCode,
+				Message: err.Error(),
+			},
+		})
+		logger.Error("ImportTimeLockManifest error", err, "user_address", userAddress, "error_code", errorCode)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
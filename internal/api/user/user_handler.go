@@ -0,0 +1,169 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"timelocker-backend/internal/middleware"
+	"timelocker-backend/internal/service/auth"
+	"timelocker-backend/internal/service/email"
+	"timelocker-backend/internal/service/user"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 用户设置处理器
+type Handler struct {
+	userService user.Service
+	authService auth.Service
+}
+
+// NewHandler 创建用户设置处理器
+func NewHandler(userService user.Service, authService auth.Service) *Handler {
+	return &Handler{
+		userService: userService,
+		authService: authService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	userGroup := router.Group("/user", middleware.AuthMiddleware(h.authService))
+	{
+		// 获取用户设置汇总
+		// GET /api/v1/user/settings
+		// http://localhost:8080/api/v1/user/settings
+		userGroup.GET("/settings", h.GetSettings)
+		// 更新用户设置
+		// PATCH /api/v1/user/settings
+		// http://localhost:8080/api/v1/user/settings
+		userGroup.PATCH("/settings", middleware.RequireWriteScope(), h.UpdateSettings)
+	}
+}
+
+// GetSettings 获取用户设置汇总
+// @Summary 获取用户设置汇总
+// @Description 一次返回已验证邮箱（含通知状态过滤）、各通知渠道配置概况与通知邮件偏好，减少前端请求次数
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.APIResponse{data=types.UserSettingsResponse}
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/user/settings [get]
+func (h *Handler) GetSettings(c *gin.Context) {
+	userID, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	response, err := h.userService.GetSettings(c.Request.Context(), userID, walletAddress)
+	if err != nil {
+		logger.Error("GetSettings Error: ", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to get user settings",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// UpdateSettings 更新用户设置
+// @Summary 更新用户设置
+// @Description 一次更新多个标量设置（目前为通知邮件的抄送、密送与发件人名称），未传的字段保持不变，返回更新后的设置汇总
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body types.UpdateUserSettingsRequest true "请求体"
+// @Success 200 {object} types.APIResponse{data=types.UserSettingsResponse}
+// @Failure 400 {object} types.APIResponse{error=types.APIError} "请求参数错误"
+// @Failure 401 {object} types.APIResponse{error=types.APIError} "未认证或令牌无效"
+// @Failure 422 {object} types.APIResponse{error=types.APIError} "参数校验失败"
+// @Failure 500 {object} types.APIResponse{error=types.APIError} "服务器内部错误"
+// @Router /api/v1/user/settings [patch]
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	userID, walletAddress, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "UNAUTHORIZED",
+				Message: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	var req types.UpdateUserSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INVALID_PARAMS",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.userService.UpdateSettings(c.Request.Context(), userID, walletAddress, &req)
+	if err != nil {
+		var code, message string
+		switch {
+		case errors.Is(err, email.ErrInvalidCcEmail):
+			code, message = "INVALID_CC_EMAIL", "Invalid cc email format"
+		case errors.Is(err, email.ErrInvalidBccEmail):
+			code, message = "INVALID_BCC_EMAIL", "Invalid bcc email format"
+		case errors.Is(err, email.ErrInvalidFromName):
+			code, message = "INVALID_FROM_NAME", "Invalid from name"
+		}
+		if code != "" {
+			c.JSON(http.StatusUnprocessableEntity, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    code,
+					Message: message,
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+
+		logger.Error("UpdateSettings Error: ", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to update user settings",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
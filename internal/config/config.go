@@ -16,24 +16,53 @@ import (
 func bindEnvKeys() {
 	keys := []string{
 		// server
-		"server.port", "server.mode",
+		"server.port", "server.mode", "server.maintenance_mode", "server.maintenance_message",
+		"server.gzip_enabled", "server.gzip_min_size", "server.gzip_level", "server.gzip_exclude_paths",
+		"server.max_body_size", "server.max_large_body_size", "server.large_body_paths",
+		"server.shutdown_timeout", "server.shutdown_wait_timeout", "server.db_log_level",
 		// database
 		"database.host", "database.port", "database.user", "database.password", "database.dbname", "database.sslmode",
+		"database.statement_timeout", "database.statement_timeout_paths",
 		// redis
 		"redis.host", "redis.port", "redis.password", "redis.db",
 		// jwt
 		"jwt.secret", "jwt.access_expiry", "jwt.refresh_expiry",
+		"jwt.key_id", "jwt.verification_keys", "jwt.issuer", "jwt.audience", "jwt.reauth_max_age",
 		// rpc
-		"rpc.alchemy_api_key", "rpc.infura_api_key", "rpc.provider", "rpc.include_testnets",
+		"rpc.alchemy_api_key", "rpc.infura_api_key", "rpc.provider", "rpc.include_testnets", "rpc.logs_probe_interval",
 		// email
 		"email.smtp_host", "email.smtp_port", "email.smtp_username", "email.smtp_password",
 		"email.from_name", "email.from_email", "email.verification_code_expiry", "email.email_url",
+		"email.send_max_attempts", "email.send_retry_base_delay", "email.retry_interval", "email.retry_max_count", "email.retry_batch_size",
 		// timelock 调度
-		"timelock.refresh_interval", "timelock.refresh_concurrency",
+		"timelock.refresh_interval", "timelock.refresh_concurrency", "timelock.refresh_chain_rate_limit",
+		"timelock.compound_default_grace_period", "timelock.compound_default_minimum_delay", "timelock.compound_default_maximum_delay",
+		"timelock.min_delay_warning",
 		// goldsky 调度
-		"goldsky.sync_interval", "goldsky.status_check_interval", "goldsky.sync_page_size",
+		"goldsky.sync_interval", "goldsky.status_check_interval", "goldsky.sync_page_size", "goldsky.rpc_fallback_lookback_blocks",
+		"goldsky.query_max_retries", "goldsky.query_retry_base_delay", "goldsky.query_retry_max_delay",
+		"goldsky.circuit_breaker_threshold", "goldsky.circuit_breaker_cooldown",
+		"goldsky.request_timeout", "goldsky.slow_query_threshold", "goldsky.reconcile_interval",
+		"goldsky.confirmation_check_interval", "goldsky.confirmation_max_wait", "goldsky.confirmation_max_pending",
+		"goldsky.tx_not_found_cache_ttl",
 		// notification worker 池
-		"notification.worker_count", "notification.queue_buffer",
+		"notification.worker_count", "notification.queue_buffer", "notification.drain_timeout", "notification.enqueue_timeout",
+		// flow 归档任务
+		"flow_archive.retention_months", "flow_archive.interval", "flow_archive.batch_size",
+		// error_logs 清理任务
+		"error_log_cleanup.retention_days", "error_log_cleanup.interval", "error_log_cleanup.batch_size",
+		// 孤立 flow 检测任务
+		"orphaned_flows.interval", "orphaned_flows.archive_after", "orphaned_flows.batch_size",
+		// 统计快照任务
+		"stats_history.interval",
+		// 管理员
+		"admin.wallet_addresses",
+		// explorer
+		"explorer.default_api_key", "explorer.rate_limit_cooldown", "explorer.api_url", "explorer.request_timeout",
+		// ABI 解码
+		"abi.signature_lookup_url", "abi.signature_lookup_timeout",
+		// 安全
+		"security.outbound_url_allowlist",
 	}
 	for _, k := range keys {
 		_ = viper.BindEnv(k)
@@ -42,15 +71,92 @@ func bindEnvKeys() {
 
 // Config 应用配置
 type Config struct {
-	Server       ServerConfig       `mapstructure:"server"`
-	Database     DatabaseConfig     `mapstructure:"database"`
-	Redis        RedisConfig        `mapstructure:"redis"`
-	JWT          JWTConfig          `mapstructure:"jwt"`
-	RPC          RPCConfig          `mapstructure:"rpc"`
-	Email        EmailConfig        `mapstructure:"email"`
-	Timelock     TimelockConfig     `mapstructure:"timelock"`
-	Goldsky      GoldskyConfig      `mapstructure:"goldsky"`
-	Notification NotificationConfig `mapstructure:"notification"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Database        DatabaseConfig        `mapstructure:"database"`
+	Redis           RedisConfig           `mapstructure:"redis"`
+	JWT             JWTConfig             `mapstructure:"jwt"`
+	RPC             RPCConfig             `mapstructure:"rpc"`
+	Email           EmailConfig           `mapstructure:"email"`
+	Timelock        TimelockConfig        `mapstructure:"timelock"`
+	Goldsky         GoldskyConfig         `mapstructure:"goldsky"`
+	Notification    NotificationConfig    `mapstructure:"notification"`
+	FlowArchive     FlowArchiveConfig     `mapstructure:"flow_archive"`
+	ErrorLogCleanup ErrorLogCleanupConfig `mapstructure:"error_log_cleanup"`
+	OrphanedFlows   OrphanedFlowsConfig   `mapstructure:"orphaned_flows"`
+	StatsHistory    StatsHistoryConfig    `mapstructure:"stats_history"`
+	Admin           AdminConfig           `mapstructure:"admin"`
+	Explorer        ExplorerConfig        `mapstructure:"explorer"`
+	Security        SecurityConfig        `mapstructure:"security"`
+	ABI             ABIConfig             `mapstructure:"abi"`
+}
+
+// FlowArchiveConfig 终态 flow 归档任务相关配置
+type FlowArchiveConfig struct {
+	// executed/cancelled/expired 的 flow 超过多少个月未更新后移入归档表，<= 0 表示关闭归档（默认关闭）
+	RetentionMonths int `mapstructure:"retention_months"`
+	// 归档任务执行间隔
+	Interval time.Duration `mapstructure:"interval"`
+	// 单批移动的最大行数
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// ErrorLogCleanupConfig error_logs 定期清理任务相关配置
+type ErrorLogCleanupConfig struct {
+	// 保留最近多少天的 error_logs，更早的记录被删除，<= 0 表示关闭清理
+	RetentionDays int `mapstructure:"retention_days"`
+	// 清理任务执行间隔
+	Interval time.Duration `mapstructure:"interval"`
+	// 单批删除的最大行数
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// OrphanedFlowsConfig 孤立 flow（合约已被所有导入者删除）检测与清理任务相关配置
+type OrphanedFlowsConfig struct {
+	// 检测任务执行间隔，<= 0 表示关闭
+	Interval time.Duration `mapstructure:"interval"`
+	// 孤立 flow 超过多久未更新后移入归档表，<= 0 表示只检测并记录日志
+	ArchiveAfter time.Duration `mapstructure:"archive_after"`
+	// 单批移动的最大行数
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// StatsHistoryConfig 全局统计快照任务相关配置
+type StatsHistoryConfig struct {
+	// 快照写入间隔，<= 0 表示关闭
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// AdminConfig 管理员相关配置
+type AdminConfig struct {
+	// 允许访问 /admin 接口的钱包地址（环境变量使用逗号分隔）
+	WalletAddresses []string `mapstructure:"wallet_addresses"`
+}
+
+// ExplorerConfig 区块浏览器 API 相关配置
+type ExplorerConfig struct {
+	// 链未单独配置 explorer_api_key 时使用的默认 key（如 Etherscan V2 多链通用 key），为空表示不回退
+	DefaultAPIKey string `mapstructure:"default_api_key"`
+	// key 被限流后暂停使用的时长
+	RateLimitCooldown time.Duration `mapstructure:"rate_limit_cooldown"`
+	// Etherscan V2 兼容的多链 API 地址（按 chainid 参数区分链），为空表示不调用区块浏览器
+	APIURL string `mapstructure:"api_url"`
+	// 单次区块浏览器请求超时
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+}
+
+// ABIConfig ABI 解码相关配置
+type ABIConfig struct {
+	// 用户 ABI 与共享 ABI 都无法解码时查询的公共函数签名库（4byte 风格 API，按 hex_signature 查询），为空表示不查询
+	SignatureLookupURL string `mapstructure:"signature_lookup_url"`
+	// 单次签名库查询超时
+	SignatureLookupTimeout time.Duration `mapstructure:"signature_lookup_timeout"`
+}
+
+// SecurityConfig 安全相关配置
+type SecurityConfig struct {
+	// 用户提供的 webhook 等 URL 默认禁止指向内网/本机地址（SSRF 防护），
+	// 自建服务可在此放行：主机名、IP 或 CIDR（环境变量使用逗号分隔）
+	OutboundURLAllowlist []string `mapstructure:"outbound_url_allowlist"`
 }
 
 // TimelockConfig Timelock 刷新任务相关配置
@@ -59,6 +165,18 @@ type TimelockConfig struct {
 	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
 	// 刷新时每个链上最多的并发 RPC 调用数
 	RefreshConcurrency int `mapstructure:"refresh_concurrency"`
+	// 每条链每秒最多刷新的合约数，避免全量刷新压垮单链 RPC，0 表示不限速
+	RefreshChainRateLimit float64 `mapstructure:"refresh_chain_rate_limit"`
+	// Compound 合约未实现 GRACE_PERIOD / MINIMUM_DELAY / MAXIMUM_DELAY 时使用的默认值（唯一来源，0 表示不填充）；
+	// 填充的上下限不满足时只给出 delay_warning，不拒绝导入
+	CompoundDefaultGracePeriod  time.Duration `mapstructure:"compound_default_grace_period"`
+	CompoundDefaultMinimumDelay time.Duration `mapstructure:"compound_default_minimum_delay"`
+	CompoundDefaultMaximumDelay time.Duration `mapstructure:"compound_default_maximum_delay"`
+	// 按链配置的 Compound 宽限期兜底值（key 为 chain_id），读取 GRACE_PERIOD 失败时优先于 compound_default_grace_period 使用，
+	// 由此计算的 expired_at 标记为估算值，后续刷新读到链上值后自动修正
+	CompoundGracePeriodByChain map[string]time.Duration `mapstructure:"compound_grace_period_by_chain"`
+	// 创建/导入时链上 delay 低于该值仍允许导入，但在响应中返回警告并标记合约（delay_warning），0 表示不检查
+	MinDelayWarning time.Duration `mapstructure:"min_delay_warning"`
 }
 
 // GoldskyConfig Goldsky 同步 / 状态检查相关配置
@@ -69,6 +187,32 @@ type GoldskyConfig struct {
 	StatusCheckInterval time.Duration `mapstructure:"status_check_interval"`
 	// 单次同步 flow 时分页大小
 	SyncPageSize int `mapstructure:"sync_page_size"`
+	// 无 subgraph 的链改用 RPC 扫日志时，尚无扫描游标的合约（首次扫描或新导入）回溯的区块数
+	RPCFallbackLookbackBlocks uint64 `mapstructure:"rpc_fallback_lookback_blocks"`
+	// 单次 subgraph 查询遇到 429/5xx/网络错误时的最大重试次数，< 0 表示不重试
+	QueryMaxRetries int `mapstructure:"query_max_retries"`
+	// 重试指数退避的初始间隔
+	QueryRetryBaseDelay time.Duration `mapstructure:"query_retry_base_delay"`
+	// 单次退避（含 Retry-After）的最大间隔
+	QueryRetryMaxDelay time.Duration `mapstructure:"query_retry_max_delay"`
+	// 连续多少次查询最终失败后熔断该链的 subgraph
+	CircuitBreakerThreshold int `mapstructure:"circuit_breaker_threshold"`
+	// 熔断持续时间，期间同步直接跳过该链
+	CircuitBreakerCooldown time.Duration `mapstructure:"circuit_breaker_cooldown"`
+	// 单次 subgraph 请求超时
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// 单次请求超过该耗时时记录慢查询日志
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+	// 本地 flow 状态与 subgraph 对账的间隔（纠正漏掉事件导致的状态漂移），<= 0 表示关闭
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	// 配置了 confirmation_depth 的链，暂存 webhook 事件的确认数检查间隔
+	ConfirmationCheckInterval time.Duration `mapstructure:"confirmation_check_interval"`
+	// webhook 事件最长暂存时间，超时仍未确认则放弃，由定时同步补齐
+	ConfirmationMaxWait time.Duration `mapstructure:"confirmation_max_wait"`
+	// 暂存 webhook 事件数上限，超出时返回错误让 Goldsky 稍后重投
+	ConfirmationMaxPending int `mapstructure:"confirmation_max_pending"`
+	// 交易详情查询结果为“不存在”时的缓存时长，期间相同交易哈希不再请求 subgraph，<= 0 表示关闭
+	TxNotFoundCacheTTL time.Duration `mapstructure:"tx_not_found_cache_ttl"`
 }
 
 // NotificationConfig 通知发送相关配置
@@ -77,11 +221,36 @@ type NotificationConfig struct {
 	WorkerCount int `mapstructure:"worker_count"`
 	// 状态变化通知队列 buffer 大小
 	QueueBuffer int `mapstructure:"queue_buffer"`
+	// 关闭时等待队列中剩余通知发送完成的最长时间，应小于 server.shutdown_wait_timeout
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+	// 队列满时投递最长等待时间，超时后丢弃该通知并计入 dropped，避免阻塞 webhook/同步处理
+	EnqueueTimeout time.Duration `mapstructure:"enqueue_timeout"`
+	// 各渠道单条消息最大长度（telegram 按 UTF-16 码元，其他渠道按字符；telegram/lark/feishu/discord/slack），未配置时使用内置默认值
+	MessageMaxLength map[string]int `mapstructure:"message_max_length"`
+	// 各渠道单次 HTTP 发送超时（telegram/lark/feishu/discord/slack），未配置时为 30s
+	SendTimeout map[string]time.Duration `mapstructure:"send_timeout"`
 }
 
 type ServerConfig struct {
 	Port string `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
+	// 维护模式：开启后写接口返回 503，读接口、健康检查与管理员接口不受影响；运行中可通过管理员接口切换
+	MaintenanceMode    bool   `mapstructure:"maintenance_mode"`
+	MaintenanceMessage string `mapstructure:"maintenance_message"` // 维护模式下返回给客户端的提示，为空时使用默认提示
+	// 响应压缩：客户端声明 Accept-Encoding: gzip 且响应体超过阈值时启用 gzip
+	GzipEnabled      bool     `mapstructure:"gzip_enabled"`
+	GzipMinSize      int      `mapstructure:"gzip_min_size"`      // 启用压缩的最小响应体字节数
+	GzipLevel        int      `mapstructure:"gzip_level"`         // 压缩级别 1-9，-1 为默认级别
+	GzipExcludePaths []string `mapstructure:"gzip_exclude_paths"` // 不压缩的路径前缀（WebSocket/流式接口）
+	// 请求体大小限制：超出时返回 413，<=0 表示不限制
+	MaxBodySize      int64    `mapstructure:"max_body_size"`       // 默认上限（字节）
+	MaxLargeBodySize int64    `mapstructure:"max_large_body_size"` // ABI 上传、批量导入等路径的上限（字节）
+	LargeBodyPaths   []string `mapstructure:"large_body_paths"`    // 使用较大上限的路径前缀
+	// 优雅关闭：HTTP 服务器等待进行中请求结束的时间，以及之后等待后台服务（通知队列、Goldsky、RPC、定时任务）停止的总预算
+	ShutdownTimeout     time.Duration `mapstructure:"shutdown_timeout"`
+	ShutdownWaitTimeout time.Duration `mapstructure:"shutdown_wait_timeout"`
+	// 写入 error_logs 表的最低日志级别：error（默认，只记录错误）或 warn（同时记录警告）
+	DBLogLevel string `mapstructure:"db_log_level"`
 }
 
 type DatabaseConfig struct {
@@ -91,6 +260,9 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
+	// 查询超时：以下接口的数据库查询超过该时长后取消并返回 503，<=0 表示不限制
+	StatementTimeout      time.Duration `mapstructure:"statement_timeout"`
+	StatementTimeoutPaths []string      `mapstructure:"statement_timeout_paths"` // 启用查询超时的接口路径（完全匹配）
 }
 
 type RedisConfig struct {
@@ -104,6 +276,15 @@ type JWTConfig struct {
 	Secret        string        `mapstructure:"secret"`
 	AccessExpiry  time.Duration `mapstructure:"access_expiry"`
 	RefreshExpiry time.Duration `mapstructure:"refresh_expiry"`
+	// 当前签名密钥（secret）的 kid，写入令牌头；轮换时换新 secret 与 key_id，并把旧的加入 verification_keys
+	KeyID string `mapstructure:"key_id"`
+	// 轮换下来的旧密钥，格式 kid:secret（环境变量使用逗号分隔），其签发的令牌在过期前仍然有效
+	VerificationKeys []string `mapstructure:"verification_keys"`
+	// 非空时签发并校验 iss/aud 声明（开启后之前签发的不带该声明的令牌会失效）
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// 查看敏感字段明文（如通知配置 reveal）要求最近一次钱包签名登录不早于该时长，刷新令牌不计入
+	ReauthMaxAge time.Duration `mapstructure:"reauth_max_age"`
 }
 
 // RPCConfig RPC配置
@@ -112,6 +293,8 @@ type RPCConfig struct {
 	InfuraAPIKey    string `mapstructure:"infura_api_key"`
 	Provider        string `mapstructure:"provider"`
 	IncludeTestnets bool   `mapstructure:"include_testnets"`
+	// eth_getLogs 区块跨度能力重新探测间隔
+	LogsProbeInterval time.Duration `mapstructure:"logs_probe_interval"`
 }
 
 // EmailConfig 邮件配置
@@ -124,6 +307,16 @@ type EmailConfig struct {
 	FromEmail              string        `mapstructure:"from_email"`
 	VerificationCodeExpiry time.Duration `mapstructure:"verification_code_expiry"`
 	EmailURL               string        `mapstructure:"email_url"`
+	// 单次发送遇到临时性 SMTP 错误（4xx/网络错误）时的最大尝试次数（含首次）
+	SendMaxAttempts int `mapstructure:"send_max_attempts"`
+	// 单次发送重试的初始退避时间，每次重试翻倍
+	SendRetryBaseDelay time.Duration `mapstructure:"send_retry_base_delay"`
+	// 后台重发任务的执行间隔，也是失败日志首次重发的等待时间
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+	// 发送日志累计重试次数上限，超过后标记为永久失败（死信），<= 0 表示关闭后台重发
+	RetryMaxCount int `mapstructure:"retry_max_count"`
+	// 后台重发任务单批处理的最大日志数
+	RetryBatchSize int `mapstructure:"retry_batch_size"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -135,12 +328,30 @@ func LoadConfig() (*Config, error) {
 	// Set defaults
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.maintenance_mode", false)
+	viper.SetDefault("server.maintenance_message", "")
+	viper.SetDefault("server.gzip_enabled", true)
+	viper.SetDefault("server.gzip_min_size", 1024)
+	viper.SetDefault("server.gzip_level", -1)
+	viper.SetDefault("server.gzip_exclude_paths", []string{})
+	viper.SetDefault("server.max_body_size", 1<<20)
+	viper.SetDefault("server.max_large_body_size", 8<<20)
+	viper.SetDefault("server.shutdown_timeout", 10*time.Second)
+	viper.SetDefault("server.shutdown_wait_timeout", 15*time.Second)
+	viper.SetDefault("server.db_log_level", "error")
+	viper.SetDefault("server.large_body_paths", []string{"/api/v1/abi", "/api/v1/notifications/import", "/api/v1/goldsky/webhook"})
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.user", "timelocker")
 	viper.SetDefault("database.password", "timelocker")
 	viper.SetDefault("database.dbname", "timelocker_db")
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.statement_timeout", "10s")
+	viper.SetDefault("database.statement_timeout_paths", []string{
+		"/api/v1/flows/list", "/api/v1/flows/list/count", "/api/v1/flows/history", "/api/v1/flows/actionable", "/api/v1/flows/calendar",
+		"/api/v1/abi/list", "/api/v1/abi/shared/search", "/api/v1/admin/flows", "/api/v1/admin/error-logs", "/api/v1/audit",
+		"/api/v1/notifications/logs",
+	})
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.password", "")
@@ -148,6 +359,11 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("jwt.secret", "timelocker-jwt-secret-v1")
 	viper.SetDefault("jwt.access_expiry", time.Hour*24)
 	viper.SetDefault("jwt.refresh_expiry", time.Hour*24*7)
+	viper.SetDefault("jwt.key_id", "")
+	viper.SetDefault("jwt.verification_keys", []string{})
+	viper.SetDefault("jwt.issuer", "")
+	viper.SetDefault("jwt.audience", "")
+	viper.SetDefault("jwt.reauth_max_age", 5*time.Minute)
 
 	// Email defaults
 	viper.SetDefault("email.smtp_host", "smtp.gmail.com")
@@ -158,19 +374,75 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("email.from_email", "")
 	viper.SetDefault("email.verification_code_expiry", time.Minute*10)
 	viper.SetDefault("email.email_url", "http://localhost:8080")
+	viper.SetDefault("email.send_max_attempts", 3)
+	viper.SetDefault("email.send_retry_base_delay", 2*time.Second)
+	viper.SetDefault("email.retry_interval", 5*time.Minute)
+	viper.SetDefault("email.retry_max_count", 8)
+	viper.SetDefault("email.retry_batch_size", 100)
+
+	// RPC defaults
+	viper.SetDefault("rpc.logs_probe_interval", 6*time.Hour)
 
 	// Timelock refresh defaults
 	viper.SetDefault("timelock.refresh_interval", 2*time.Hour)
 	viper.SetDefault("timelock.refresh_concurrency", 5)
+	viper.SetDefault("timelock.refresh_chain_rate_limit", 0)
+	viper.SetDefault("timelock.compound_default_grace_period", 14*24*time.Hour)
+	viper.SetDefault("timelock.compound_default_minimum_delay", 2*24*time.Hour)
+	viper.SetDefault("timelock.compound_default_maximum_delay", 30*24*time.Hour)
+	viper.SetDefault("timelock.min_delay_warning", time.Hour)
 
 	// Goldsky defaults
 	viper.SetDefault("goldsky.sync_interval", 10*time.Minute)
 	viper.SetDefault("goldsky.status_check_interval", 30*time.Second)
 	viper.SetDefault("goldsky.sync_page_size", 500)
+	viper.SetDefault("goldsky.rpc_fallback_lookback_blocks", 50000)
+	viper.SetDefault("goldsky.query_max_retries", 3)
+	viper.SetDefault("goldsky.query_retry_base_delay", 500*time.Millisecond)
+	viper.SetDefault("goldsky.query_retry_max_delay", 30*time.Second)
+	viper.SetDefault("goldsky.circuit_breaker_threshold", 5)
+	viper.SetDefault("goldsky.circuit_breaker_cooldown", 5*time.Minute)
+	viper.SetDefault("goldsky.request_timeout", 30*time.Second)
+	viper.SetDefault("goldsky.slow_query_threshold", 5*time.Second)
+	viper.SetDefault("goldsky.reconcile_interval", 6*time.Hour)
+	viper.SetDefault("goldsky.confirmation_check_interval", 5*time.Second)
+	viper.SetDefault("goldsky.confirmation_max_wait", 30*time.Minute)
+	viper.SetDefault("goldsky.confirmation_max_pending", 10000)
+	viper.SetDefault("goldsky.tx_not_found_cache_ttl", 30*time.Second)
 
 	// Notification defaults
 	viper.SetDefault("notification.worker_count", 4)
 	viper.SetDefault("notification.queue_buffer", 1024)
+	viper.SetDefault("notification.drain_timeout", 10*time.Second)
+	viper.SetDefault("notification.enqueue_timeout", 2*time.Second)
+
+	// Flow archive defaults
+	viper.SetDefault("flow_archive.retention_months", 0)
+	viper.SetDefault("flow_archive.interval", 24*time.Hour)
+	viper.SetDefault("flow_archive.batch_size", 1000)
+
+	// Error log cleanup defaults
+	viper.SetDefault("error_log_cleanup.retention_days", 30)
+	viper.SetDefault("error_log_cleanup.interval", 24*time.Hour)
+	viper.SetDefault("error_log_cleanup.batch_size", 5000)
+
+	// Orphaned flows defaults
+	viper.SetDefault("orphaned_flows.interval", 24*time.Hour)
+	viper.SetDefault("orphaned_flows.archive_after", 0)
+	viper.SetDefault("orphaned_flows.batch_size", 1000)
+
+	// Stats history defaults
+	viper.SetDefault("stats_history.interval", time.Hour)
+
+	// Admin defaults
+	viper.SetDefault("admin.wallet_addresses", []string{})
+	viper.SetDefault("explorer.default_api_key", "")
+	viper.SetDefault("explorer.rate_limit_cooldown", time.Minute)
+	viper.SetDefault("explorer.api_url", "https://api.etherscan.io/v2/api")
+	viper.SetDefault("explorer.request_timeout", 10*time.Second)
+	viper.SetDefault("abi.signature_lookup_url", "")
+	viper.SetDefault("abi.signature_lookup_timeout", 5*time.Second)
+	viper.SetDefault("security.outbound_url_allowlist", []string{})
 
 	// 让嵌套 key 能从环境变量读取：database.host -> DATABASE_HOST 等。
 	// 这样 .env / docker-compose 注入的环境变量会自动覆盖 config.yaml 里的同名字段。
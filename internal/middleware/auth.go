@@ -4,7 +4,9 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
+	"timelocker-backend/internal/repository/audit"
 	"timelocker-backend/internal/service/auth"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
@@ -19,8 +21,36 @@ import (
 // 4. 验证token
 // 5. 将用户信息存储到上下文中
 // 6. 继续处理请求
+// 若携带 X-API-Key 请求头，则走API令牌鉴权（只读作用域）
 func AuthMiddleware(authService auth.Service) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
+		// API令牌鉴权
+		if apiKey := strings.TrimSpace(c.GetHeader(APIKeyHeader)); apiKey != "" {
+			claims, err := authService.VerifyAPIToken(c.Request.Context(), apiKey)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, types.APIResponse{
+					Success: false,
+					Error: &types.APIError{
+						Code:    "INVALID_API_KEY",
+						Message: "Invalid, revoked or expired API key",
+						Details: err.Error(),
+					},
+				})
+				logger.Error("AuthMiddleware Error: ", errors.New("invalid api key"), "error: ", err)
+				c.Abort()
+				return
+			}
+
+			c.Set("user_id", claims.UserID)
+			c.Set("wallet_address", claims.WalletAddress)
+			c.Set("jwt_claims", claims)
+			c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), claims.WalletAddress))
+
+			logger.Info("AuthMiddleware: ", "api key auth success", "user_id: ", claims.UserID, "wallet_address: ", claims.WalletAddress)
+			c.Next()
+			return
+		}
+
 		// 从请求头获取Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -86,6 +116,8 @@ func AuthMiddleware(authService auth.Service) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("wallet_address", claims.WalletAddress)
 		c.Set("jwt_claims", claims)
+		// 审计日志从请求 ctx 中取操作人
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), claims.WalletAddress))
 
 		logger.Info("AuthMiddleware: ", "auth middleware success", "user_id: ", claims.UserID, "wallet_address: ", claims.WalletAddress)
 		// 继续处理请求
@@ -93,6 +125,61 @@ func AuthMiddleware(authService auth.Service) gin.HandlerFunc {
 	})
 }
 
+// APIKeyHeader API令牌请求头
+const APIKeyHeader = "X-API-Key"
+
+// RequireWriteScope 写操作作用域校验，需放在 AuthMiddleware 之后
+// 只读API令牌访问写接口时返回 403；维护模式下写接口返回 503
+func RequireWriteScope() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if checkMaintenance(c) {
+			return
+		}
+		claims, ok := GetClaimsFromContext(c)
+		if ok && claims.Scope == types.APITokenScopeRead {
+			c.JSON(http.StatusForbidden, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INSUFFICIENT_SCOPE",
+					Message: "API key is read-only",
+				},
+			})
+			logger.Error("RequireWriteScope Error: ", errors.New("read-only api key used on write endpoint"), "user_id: ", claims.UserID, "path: ", c.FullPath())
+			c.Abort()
+			return
+		}
+		c.Next()
+	})
+}
+
+// RequireAdmin 管理员校验，需放在 AuthMiddleware 之后
+// 当前钱包不在管理员地址列表中时返回 403
+func RequireAdmin(adminAddresses []string) gin.HandlerFunc {
+	admins := make(map[string]struct{}, len(adminAddresses))
+	for _, addr := range adminAddresses {
+		if addr = strings.ToLower(strings.TrimSpace(addr)); addr != "" {
+			admins[addr] = struct{}{}
+		}
+	}
+
+	return gin.HandlerFunc(func(c *gin.Context) {
+		userID, walletAddress, ok := GetUserFromContext(c)
+		if _, isAdmin := admins[strings.ToLower(walletAddress)]; !ok || !isAdmin {
+			c.JSON(http.StatusForbidden, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "FORBIDDEN",
+					Message: "Admin access required",
+				},
+			})
+			logger.Error("RequireAdmin Error: ", errors.New("non-admin wallet used on admin endpoint"), "user_id: ", userID, "wallet_address: ", walletAddress, "path: ", c.FullPath())
+			c.Abort()
+			return
+		}
+		c.Next()
+	})
+}
+
 // GetUserFromContext 从gin上下文获取用户信息
 func GetUserFromContext(c *gin.Context) (int64, string, bool) {
 	userID, exists := c.Get("user_id")
@@ -122,6 +209,16 @@ func GetUserFromContext(c *gin.Context) (int64, string, bool) {
 	return userIDInt64, walletAddressStr, true
 }
 
+// HasRecentAuth 判断当前会话是否在 maxAge 内完成过钱包签名登录，需放在 AuthMiddleware 之后
+// API令牌与不带 auth_time 的旧令牌一律视为未重新认证
+func HasRecentAuth(c *gin.Context, maxAge time.Duration) bool {
+	claims, ok := GetClaimsFromContext(c)
+	if !ok || claims.Type != "access" || claims.AuthTime <= 0 {
+		return false
+	}
+	return time.Since(time.Unix(claims.AuthTime, 0)) <= maxAge
+}
+
 // GetClaimsFromContext 从gin上下文获取JWT claims
 func GetClaimsFromContext(c *gin.Context) (*types.JWTClaims, bool) {
 	claims, exists := c.Get("jwt_claims")
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitOptions 请求体大小限制配置
+type BodyLimitOptions struct {
+	MaxBytes      int64    // 默认上限
+	LargeMaxBytes int64    // LargePaths 下的上限（ABI 上传、批量导入、Goldsky webhook）
+	LargePaths    []string // 使用较大上限的路径前缀
+}
+
+// BodyLimit 请求体大小限制中间件，需全局注册
+// 在绑定参数之前读取请求体：声明的 Content-Length 超限时直接返回 413；
+// 未声明长度（chunked）时最多读取上限字节，超出同样返回 413，未超出则放回请求体供后续绑定
+func BodyLimit(opts BodyLimitOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := opts.MaxBytes
+		for _, prefix := range opts.LargePaths {
+			if prefix != "" && strings.HasPrefix(c.Request.URL.Path, prefix) {
+				limit = opts.LargeMaxBytes
+				break
+			}
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			abortRequestTooLarge(c, limit)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+		_ = c.Request.Body.Close()
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				abortRequestTooLarge(c, limit)
+				return
+			}
+			c.JSON(http.StatusBadRequest, types.APIResponse{
+				Success: false,
+				Error: &types.APIError{
+					Code:    "INVALID_REQUEST",
+					Message: "Failed to read request body",
+					Details: err.Error(),
+				},
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// abortRequestTooLarge 返回 413
+func abortRequestTooLarge(c *gin.Context, limit int64) {
+	logger.Warn("Request body too large", "path", c.Request.URL.Path, "content_length", c.Request.ContentLength, "limit", limit, "client_ip", c.ClientIP())
+	c.JSON(http.StatusRequestEntityTooLarge, types.APIResponse{
+		Success: false,
+		Error: &types.APIError{
+			Code:    "REQUEST_TOO_LARGE",
+			Message: fmt.Sprintf("Request body exceeds the limit of %d bytes", limit),
+		},
+	})
+	c.Abort()
+}
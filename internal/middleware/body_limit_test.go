@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newBodyLimitRouter 注册 BodyLimit，处理函数回显读到的请求体长度
+func newBodyLimitRouter(opts BodyLimitOptions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(BodyLimit(opts))
+	r.Any("/*path", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	})
+	return r
+}
+
+func TestBodyLimit(t *testing.T) {
+	opts := BodyLimitOptions{MaxBytes: 16, LargeMaxBytes: 64, LargePaths: []string{"/api/v1/abi", ""}}
+	tests := []struct {
+		name     string
+		opts     BodyLimitOptions
+		path     string
+		size     int
+		chunked  bool // 不声明 Content-Length
+		wantCode int
+	}{
+		{"within default limit", opts, "/api/v1/flows", 16, false, http.StatusOK},
+		{"declared length over default limit", opts, "/api/v1/flows", 17, false, http.StatusRequestEntityTooLarge},
+		{"chunked body over default limit", opts, "/api/v1/flows", 17, true, http.StatusRequestEntityTooLarge},
+		{"chunked body within limit is replayed", opts, "/api/v1/flows", 10, true, http.StatusOK},
+		{"large path uses larger limit", opts, "/api/v1/abi/add", 64, false, http.StatusOK},
+		{"large path over larger limit", opts, "/api/v1/abi/add", 65, true, http.StatusRequestEntityTooLarge},
+		{"empty prefix does not match everything", opts, "/api/v1/flows", 40, false, http.StatusRequestEntityTooLarge},
+		{"zero limit disables the check", BodyLimitOptions{}, "/api/v1/flows", 1024, true, http.StatusOK},
+		{"empty body", opts, "/api/v1/flows", 0, false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := strings.Repeat("a", tt.size)
+			var req *http.Request
+			if tt.chunked {
+				// 包一层 Reader 使 httptest 无法推断长度
+				req = httptest.NewRequest(http.MethodPost, tt.path, io.MultiReader(strings.NewReader(body)))
+				req.ContentLength = -1
+			} else {
+				req = httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+			}
+			w := httptest.NewRecorder()
+			newBodyLimitRouter(tt.opts).ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode == http.StatusOK && w.Body.String() != strconv.Itoa(tt.size) {
+				t.Fatalf("handler read %s bytes, want %d", w.Body.String(), tt.size)
+			}
+			if tt.wantCode == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), "REQUEST_TOO_LARGE") {
+				t.Fatalf("413 body = %s, want REQUEST_TOO_LARGE", w.Body.String())
+			}
+		})
+	}
+}
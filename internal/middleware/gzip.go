@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipOptions 响应压缩配置
+type GzipOptions struct {
+	MinSize      int      // 启用压缩的最小响应体字节数
+	Level        int      // 压缩级别，取值同 compress/gzip
+	ExcludePaths []string // 不压缩的路径前缀
+}
+
+// Gzip 对声明了 Accept-Encoding: gzip 的请求压缩响应体
+// 响应体先缓冲到 MinSize，未达到阈值时原样输出；WebSocket 升级、SSE 以及排除路径下的请求不压缩
+func Gzip(opts GzipOptions) gin.HandlerFunc {
+	level := opts.Level
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	pool := &sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, level)
+		return w
+	}}
+
+	return func(c *gin.Context) {
+		if !shouldCompress(c.Request, opts.ExcludePaths) {
+			c.Next()
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: c.Writer, minSize: opts.MinSize, pool: pool}
+		c.Writer = gw
+		c.Header("Vary", "Accept-Encoding")
+		defer gw.finish()
+
+		c.Next()
+	}
+}
+
+// shouldCompress 判断请求是否需要压缩
+func shouldCompress(req *http.Request, excludePaths []string) bool {
+	if req.Method == http.MethodHead || !strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") {
+		return false
+	}
+	if strings.EqualFold(req.Header.Get("Connection"), "upgrade") || req.Header.Get("Upgrade") != "" {
+		return false
+	}
+	if strings.Contains(req.Header.Get("Accept"), "text/event-stream") {
+		return false
+	}
+	for _, prefix := range excludePaths {
+		if prefix != "" && strings.HasPrefix(req.URL.Path, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// gzipResponseWriter 缓冲响应体直到确定是否压缩
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	pool    *sync.Pool
+	buf     []byte
+	gz      *gzip.Writer
+	decided bool // 已确定是否压缩（压缩时 gz 非空）
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) < w.minSize {
+		return len(data), nil
+	}
+	if err := w.decide(true); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 流式输出时不再等待阈值，按当前缓冲量决定是否压缩
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(len(w.buf) >= w.minSize)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide 确定是否压缩并输出已缓冲的数据
+func (w *gzipResponseWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+	status := w.Status()
+	// 已自行编码、分段响应或无响应体的状态码不压缩
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" ||
+		status == http.StatusNoContent || status == http.StatusNotModified || status < http.StatusOK {
+		compress = false
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if !compress {
+		if len(buf) == 0 {
+			return nil
+		}
+		_, err := w.ResponseWriter.Write(buf)
+		return err
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.gz = w.pool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+	_, err := w.gz.Write(buf)
+	return err
+}
+
+// finish 请求结束时输出剩余数据并归还 gzip writer
+func (w *gzipResponseWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
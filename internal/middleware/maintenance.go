@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMaintenanceMessage 未配置提示时的默认维护提示
+const defaultMaintenanceMessage = "Service is under maintenance, write operations are temporarily disabled"
+
+// maintenanceContextKey 维护模式下标记写请求需要拦截的上下文键，由 RequireWriteScope 消费
+const maintenanceContextKey = "maintenance_mode"
+
+// maintenanceExemptPrefixes 维护模式下不拦截的路径前缀（健康检查与管理员接口）
+var maintenanceExemptPrefixes = []string{"/api/v1/health", "/api/v1/admin"}
+
+// MaintenanceState 维护模式开关（进程内共享，可在运行中切换）
+type MaintenanceState struct {
+	mu        sync.RWMutex
+	enabled   bool
+	message   string
+	updatedBy string
+	updatedAt time.Time
+}
+
+// NewMaintenanceState 创建维护模式开关，初始状态来自配置
+func NewMaintenanceState(enabled bool, message string) *MaintenanceState {
+	state := &MaintenanceState{
+		enabled:   enabled,
+		message:   strings.TrimSpace(message),
+		updatedBy: "config",
+		updatedAt: time.Now(),
+	}
+	if enabled {
+		logger.Warn("Maintenance mode enabled at startup, write endpoints will return 503", "message", state.Message())
+	}
+	return state
+}
+
+// Enabled 是否处于维护模式
+func (m *MaintenanceState) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+// Message 维护提示
+func (m *MaintenanceState) Message() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.message == "" {
+		return defaultMaintenanceMessage
+	}
+	return m.message
+}
+
+// Set 切换维护模式并记录进入/退出日志
+func (m *MaintenanceState) Set(enabled bool, message, updatedBy string) types.MaintenanceStatus {
+	m.mu.Lock()
+	previous := m.enabled
+	m.enabled = enabled
+	m.message = strings.TrimSpace(message)
+	m.updatedBy = updatedBy
+	m.updatedAt = time.Now()
+	m.mu.Unlock()
+
+	switch {
+	case enabled && !previous:
+		logger.Warn("Maintenance mode entered, write endpoints will return 503", "updated_by", updatedBy, "message", m.Message())
+	case !enabled && previous:
+		logger.Info("Maintenance mode exited, write endpoints restored", "updated_by", updatedBy)
+	}
+	return m.Status()
+}
+
+// Status 当前维护模式状态
+func (m *MaintenanceState) Status() types.MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status := types.MaintenanceStatus{
+		Enabled:   m.enabled,
+		UpdatedBy: m.updatedBy,
+		UpdatedAt: m.updatedAt,
+	}
+	if m.enabled {
+		status.Message = m.message
+		if status.Message == "" {
+			status.Message = defaultMaintenanceMessage
+		}
+	}
+	return status
+}
+
+// MaintenanceMode 维护模式中间件，需全局注册
+// 维护模式下 PUT/PATCH/DELETE 直接返回 503；POST 接口大多为查询，只标记上下文，
+// 由写接口上的 RequireWriteScope 拦截。健康检查与管理员接口不受影响
+func MaintenanceMode(state *MaintenanceState) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if !state.Enabled() || isMaintenanceExempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			abortMaintenance(c, state)
+			return
+		case http.MethodPost:
+			c.Set(maintenanceContextKey, state)
+		}
+		c.Next()
+	})
+}
+
+// isMaintenanceExempt 判断路径是否不受维护模式影响
+func isMaintenanceExempt(path string) bool {
+	for _, prefix := range maintenanceExemptPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMaintenance 写接口上检查维护模式标记，已拦截时返回 true
+func checkMaintenance(c *gin.Context) bool {
+	value, exists := c.Get(maintenanceContextKey)
+	if !exists {
+		return false
+	}
+	state, ok := value.(*MaintenanceState)
+	if !ok || !state.Enabled() {
+		return false
+	}
+	abortMaintenance(c, state)
+	return true
+}
+
+// abortMaintenance 返回 503 维护提示
+func abortMaintenance(c *gin.Context, state *MaintenanceState) {
+	c.JSON(http.StatusServiceUnavailable, types.APIResponse{
+		Success: false,
+		Error: &types.APIError{
+			Code:    "MAINTENANCE_MODE",
+			Message: state.Message(),
+		},
+	})
+	c.Abort()
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeoutOptions 读接口查询超时配置
+type QueryTimeoutOptions struct {
+	Timeout time.Duration // 超时时长，<=0 表示不限制
+	Paths   []string      // 启用超时的接口路径（完全匹配）
+}
+
+// QueryTimeout 读接口查询超时中间件，需全局注册（在 Gzip 之后）
+// 为匹配路径的请求上下文设置截止时间，查询经 GORM WithContext 继承该截止时间，超时后由驱动取消；
+// 超时后处理器返回的 5xx 响应统一改写为 503 QUERY_TIMEOUT
+func QueryTimeout(opts QueryTimeoutOptions) gin.HandlerFunc {
+	paths := make(map[string]struct{}, len(opts.Paths))
+	for _, path := range opts.Paths {
+		paths[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if opts.Timeout <= 0 {
+			c.Next()
+			return
+		}
+		if _, ok := paths[c.Request.URL.Path]; !ok {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), opts.Timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		tw := &queryTimeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = tw
+		c.Next()
+
+		if tw.replaced {
+			logger.Warn("Database query timed out", "path", c.Request.URL.Path, "timeout", opts.Timeout.String(), "client_ip", c.ClientIP())
+		}
+	}
+}
+
+// queryTimeoutWriter 在请求超时后把 5xx 响应替换为 503 QUERY_TIMEOUT
+type queryTimeoutWriter struct {
+	gin.ResponseWriter
+	ctx         context.Context
+	replaced    bool // 已替换为超时响应
+	bodyWritten bool
+}
+
+func (w *queryTimeoutWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && !w.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.replaced = true
+		code = http.StatusServiceUnavailable
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *queryTimeoutWriter) Write(data []byte) (int, error) {
+	if !w.replaced {
+		return w.ResponseWriter.Write(data)
+	}
+	if !w.bodyWritten {
+		w.bodyWritten = true
+		body, _ := json.Marshal(types.APIResponse{
+			Success: false,
+			Error: &types.APIError{
+				Code:    "QUERY_TIMEOUT",
+				Message: "Database query timed out, please narrow the query and retry",
+			},
+		})
+		if _, err := w.ResponseWriter.Write(body); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *queryTimeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newQueryTimeoutRouter 注册 QueryTimeout（可选在 Gzip 之后），处理函数模拟查询：
+// /slow 阻塞到上下文结束后按处理器的常规做法返回 500；/fail 立即返回 500；/deadline 回显是否带截止时间
+func newQueryTimeoutRouter(withGzip bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if withGzip {
+		r.Use(Gzip(GzipOptions{MinSize: 0, Level: gzip.DefaultCompression}))
+	}
+	r.Use(QueryTimeout(QueryTimeoutOptions{Timeout: 20 * time.Millisecond, Paths: []string{"/slow", "/fail", "/deadline"}}))
+
+	internalError := func(c *gin.Context, err error) {
+		c.JSON(http.StatusInternalServerError, types.APIResponse{
+			Success: false,
+			Error:   &types.APIError{Code: "INTERNAL_ERROR", Message: "query failed", Details: err.Error()},
+		})
+	}
+	r.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		internalError(c, c.Request.Context().Err())
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		internalError(c, io.ErrUnexpectedEOF)
+	})
+	deadline := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.String(http.StatusOK, "%v", ok)
+	}
+	r.GET("/deadline", deadline)
+	r.GET("/other", deadline)
+	return r
+}
+
+func TestQueryTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		gzip     bool
+		wantCode int
+		wantBody string
+	}{
+		{"slow query replaced with 503", "/slow", false, http.StatusServiceUnavailable, `"code":"QUERY_TIMEOUT"`},
+		{"slow query replaced with 503 under gzip", "/slow", true, http.StatusServiceUnavailable, `"code":"QUERY_TIMEOUT"`},
+		{"error before deadline kept", "/fail", false, http.StatusInternalServerError, `"code":"INTERNAL_ERROR"`},
+		{"listed path gets a deadline", "/deadline", false, http.StatusOK, "true"},
+		{"other path untouched", "/other", false, http.StatusOK, "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.gzip {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			w := httptest.NewRecorder()
+			newQueryTimeoutRouter(tt.gzip).ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body.String())
+			}
+			var body io.Reader = w.Body
+			if w.Header().Get("Content-Encoding") == "gzip" {
+				gr, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("gzip.NewReader: %v", err)
+				}
+				body = gr
+			} else if tt.gzip {
+				t.Fatal("response not gzip encoded")
+			}
+			data, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if !strings.Contains(string(data), tt.wantBody) {
+				t.Fatalf("body = %s, want it to contain %s", data, tt.wantBody)
+			}
+			// 替换后只输出超时响应，不拼接处理器原本的错误内容
+			if tt.wantCode == http.StatusServiceUnavailable && strings.Contains(string(data), "INTERNAL_ERROR") {
+				t.Fatalf("body = %s, handler error leaked into the timeout response", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package abi
+
+import (
+	"context"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// compoundABIMatch Compound 流程调用了 ABI 中的函数：有函数签名时按去空格后的签名匹配，
+// 签名为空（calldata 自带选择器）时按 calldata 前 4 字节匹配
+const compoundABIMatch = `(
+	REPLACE(f.function_signature, ' ', '') = af.signature
+	OR (COALESCE(f.function_signature, '') = '' AND '0x' || encode(substring(f.call_data from 1 for 4), 'hex') = af.selector)
+)`
+
+// compoundUserFlowCondition 用户与 Compound 流程相关（与 GetUserActionableCompoundFlows 一致）
+const compoundUserFlowCondition = `(LOWER(f.initiator_address) = @user OR EXISTS (
+	SELECT 1 FROM compound_timelocks t
+	WHERE t.chain_id = f.chain_id
+	AND LOWER(t.contract_address) = LOWER(f.contract_address)
+	AND (LOWER(t.admin) = @user OR LOWER(t.pending_admin) = @user OR LOWER(t.creator_address) = @user)
+	AND t.status = 'active'
+))`
+
+// openzeppelinABIMatch OpenZeppelin 流程调用了 ABI 中的函数：
+// 单笔操作的 calldata 在流程表中，批量操作的每笔调用在 openzeppelin_flow_calls 中
+const openzeppelinABIMatch = `(
+	'0x' || encode(substring(f.call_data from 1 for 4), 'hex') = af.selector
+	OR EXISTS (
+		SELECT 1 FROM openzeppelin_flow_calls c
+		WHERE c.flow_id = f.flow_id
+		AND c.chain_id = f.chain_id
+		AND c.contract_address = LOWER(f.contract_address)
+		AND '0x' || encode(substring(c.call_data from 1 for 4), 'hex') = af.selector
+	)
+)`
+
+// openzeppelinUserFlowCondition 用户与 OpenZeppelin 流程相关（与 GetUserActionableOpenzeppelinFlows 一致）
+const openzeppelinUserFlowCondition = `(LOWER(f.initiator_address) = @user OR EXISTS (
+	SELECT 1 FROM openzeppelin_timelocks t
+	WHERE t.chain_id = f.chain_id
+	AND LOWER(t.contract_address) = LOWER(f.contract_address)
+	AND (LOWER(t.creator_address) = @user OR LOWER(t.proposers) LIKE @like OR LOWER(t.executors) LIKE @like)
+	AND t.status = 'active'
+))`
+
+// compoundABIUsageSQL 统计用户相关的 Compound 流程中调用了各 ABI 函数的流程数
+const compoundABIUsageSQL = `
+	SELECT af.abi_id, COUNT(DISTINCT f.id) AS count
+	FROM abi_functions af
+	JOIN compound_timelock_flows f ON ` + compoundABIMatch + `
+	WHERE af.abi_id IN @ids AND ` + compoundUserFlowCondition + `
+	GROUP BY af.abi_id`
+
+// openzeppelinABIUsageSQL 统计用户相关的 OpenZeppelin 流程中调用了各 ABI 函数的流程数
+const openzeppelinABIUsageSQL = `
+	SELECT af.abi_id, COUNT(DISTINCT f.id) AS count
+	FROM abi_functions af
+	JOIN openzeppelin_timelock_flows f ON ` + openzeppelinABIMatch + `
+	WHERE af.abi_id IN @ids AND ` + openzeppelinUserFlowCondition + `
+	GROUP BY af.abi_id`
+
+// userFlowArgs 用户相关流程条件的命名参数
+func userFlowArgs(walletAddress string) map[string]interface{} {
+	normalizedWalletAddress := strings.ToLower(walletAddress)
+	return map[string]interface{}{
+		"user": normalizedWalletAddress,
+		"like": "%" + normalizedWalletAddress + "%",
+	}
+}
+
+// abiCountRow 按 ABI 分组的计数
+type abiCountRow struct {
+	ABIID int64 `gorm:"column:abi_id"`
+	Count int64 `gorm:"column:count"`
+}
+
+// ReplaceABIFunctions 重建 ABI 的函数选择器索引
+func (r *repository) ReplaceABIFunctions(ctx context.Context, abiID int64, functions []types.ABIFunction) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("abi_id = ?", abiID).Delete(&types.ABIFunction{}).Error; err != nil {
+			return err
+		}
+		if len(functions) == 0 {
+			return nil
+		}
+		rows := make([]types.ABIFunction, len(functions))
+		for i, fn := range functions {
+			rows[i] = types.ABIFunction{ABIID: abiID, Selector: strings.ToLower(fn.Selector), Signature: fn.Signature}
+		}
+		return tx.CreateInBatches(&rows, 200).Error
+	})
+	if err != nil {
+		logger.Error("ReplaceABIFunctions Error:", err, "abi_id", abiID, "count", len(functions))
+		return err
+	}
+	return nil
+}
+
+// GetABIPage 分页查询用户的ABI（可选包含共享ABI），不加载 abi_content
+func (r *repository) GetABIPage(ctx context.Context, walletAddress, name string, includeShared bool, offset, limit int) ([]types.ABI, int64, error) {
+	normalizedWalletAddress := strings.ToLower(walletAddress)
+	query := r.db.WithContext(ctx).Model(&types.ABI{})
+	if includeShared {
+		query = query.Where("(LOWER(owner) = ? OR (owner = ? AND is_shared = ?))", normalizedWalletAddress, SharedABIOwner, true)
+	} else {
+		query = query.Where("LOWER(owner) = ?", normalizedWalletAddress)
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(name)+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("GetABIPage count Error:", err, "wallet_address", walletAddress)
+		return nil, 0, err
+	}
+
+	var abis []types.ABI
+	if err := query.
+		Select("id, name, owner, description, is_shared, created_at, updated_at").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&abis).Error; err != nil {
+		logger.Error("GetABIPage Error:", err, "wallet_address", walletAddress)
+		return nil, 0, err
+	}
+	return abis, total, nil
+}
+
+// CountABIFunctions 统计各 ABI 的函数数量
+func (r *repository) CountABIFunctions(ctx context.Context, abiIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(abiIDs))
+	if len(abiIDs) == 0 {
+		return counts, nil
+	}
+	var rows []abiCountRow
+	if err := r.db.WithContext(ctx).Model(&types.ABIFunction{}).
+		Select("abi_id, COUNT(*) AS count").
+		Where("abi_id IN ?", abiIDs).
+		Group("abi_id").
+		Scan(&rows).Error; err != nil {
+		logger.Error("CountABIFunctions Error:", err, "abi_count", len(abiIDs))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ABIID] = int(row.Count)
+	}
+	return counts, nil
+}
+
+// CountABIUsage 统计用户相关流程中调用了各 ABI 函数的流程数（Compound 与 OpenZeppelin 合计，只统计未归档的流程）
+func (r *repository) CountABIUsage(ctx context.Context, walletAddress string, abiIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(abiIDs))
+	if len(abiIDs) == 0 {
+		return counts, nil
+	}
+	args := userFlowArgs(walletAddress)
+	args["ids"] = abiIDs
+
+	var compoundRows []abiCountRow
+	if err := r.db.WithContext(ctx).Raw(compoundABIUsageSQL, args).Scan(&compoundRows).Error; err != nil {
+		logger.Error("CountABIUsage compound Error:", err, "wallet_address", walletAddress)
+		return nil, err
+	}
+	var openzeppelinRows []abiCountRow
+	if err := r.db.WithContext(ctx).Raw(openzeppelinABIUsageSQL, args).Scan(&openzeppelinRows).Error; err != nil {
+		logger.Error("CountABIUsage openzeppelin Error:", err, "wallet_address", walletAddress)
+		return nil, err
+	}
+
+	for _, row := range append(compoundRows, openzeppelinRows...) {
+		counts[row.ABIID] += row.Count
+	}
+	return counts, nil
+}
+
+// SearchSharedABIFunctions 在共享ABI的函数索引中按函数名（不区分大小写的模糊匹配）或选择器（精确匹配）查找函数
+func (r *repository) SearchSharedABIFunctions(ctx context.Context, functionName, selector string, limit int) ([]types.ABIFunctionMatch, error) {
+	query := r.db.WithContext(ctx).Table("abi_functions af").
+		Select("af.abi_id, a.name, a.description, af.selector, af.signature").
+		Joins("JOIN abis a ON a.id = af.abi_id").
+		Where("a.owner = ? AND a.is_shared = ?", SharedABIOwner, true)
+	if selector != "" {
+		query = query.Where("af.selector = ?", strings.ToLower(selector))
+	} else {
+		query = query.Where("split_part(af.signature, '(', 1) ILIKE ?", "%"+escapeLike(functionName)+"%")
+	}
+
+	var matches []types.ABIFunctionMatch
+	if err := query.Order("a.id ASC, af.signature ASC").Limit(limit).Scan(&matches).Error; err != nil {
+		logger.Error("SearchSharedABIFunctions Error:", err, "function", functionName, "selector", selector)
+		return nil, err
+	}
+	return matches, nil
+}
+
+// GetABIsBySelector 获取包含该函数选择器的用户ABI与共享ABI（按函数索引查找），用户自己的在前
+func (r *repository) GetABIsBySelector(ctx context.Context, walletAddress, selector string) ([]types.ABI, error) {
+	normalizedWalletAddress := strings.ToLower(walletAddress)
+	var abis []types.ABI
+	err := r.db.WithContext(ctx).
+		Where("(LOWER(owner) = ? OR (owner = ? AND is_shared = ?))", normalizedWalletAddress, SharedABIOwner, true).
+		Where("EXISTS (SELECT 1 FROM abi_functions af WHERE af.abi_id = abis.id AND af.selector = ?)", strings.ToLower(selector)).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "CASE WHEN owner = ? THEN 1 ELSE 0 END, updated_at DESC, id DESC", Vars: []interface{}{SharedABIOwner}}}).
+		Find(&abis).Error
+	if err != nil {
+		logger.Error("GetABIsBySelector Error:", err, "wallet_address", walletAddress, "selector", selector)
+		return nil, err
+	}
+	return abis, nil
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// GetABIMatchedCompoundFlows 获取用户相关、调用了 ABI 函数的 Compound 流程（按 id 倒序）
+func (r *repository) GetABIMatchedCompoundFlows(ctx context.Context, walletAddress string, abiID int64, limit int) ([]types.CompoundTimelockFlowDB, error) {
+	args := userFlowArgs(walletAddress)
+	args["abi"] = abiID
+	args["limit"] = limit
+
+	var flows []types.CompoundTimelockFlowDB
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT f.* FROM compound_timelock_flows f
+		WHERE EXISTS (SELECT 1 FROM abi_functions af WHERE af.abi_id = @abi AND `+compoundABIMatch+`)
+		AND `+compoundUserFlowCondition+`
+		ORDER BY f.id DESC
+		LIMIT @limit`, args).Scan(&flows).Error; err != nil {
+		logger.Error("GetABIMatchedCompoundFlows Error:", err, "abi_id", abiID, "wallet_address", walletAddress)
+		return nil, err
+	}
+	return flows, nil
+}
+
+// GetABIMatchedOpenzeppelinFlows 获取用户相关、调用了 ABI 函数的 OpenZeppelin 流程及其子调用（按 id 倒序）
+func (r *repository) GetABIMatchedOpenzeppelinFlows(ctx context.Context, walletAddress string, abiID int64, limit int) ([]types.OpenzeppelinTimelockFlowDB, []types.OpenzeppelinFlowCallDB, error) {
+	args := userFlowArgs(walletAddress)
+	args["abi"] = abiID
+	args["limit"] = limit
+
+	var flows []types.OpenzeppelinTimelockFlowDB
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT f.* FROM openzeppelin_timelock_flows f
+		WHERE EXISTS (SELECT 1 FROM abi_functions af WHERE af.abi_id = @abi AND `+openzeppelinABIMatch+`)
+		AND `+openzeppelinUserFlowCondition+`
+		ORDER BY f.id DESC
+		LIMIT @limit`, args).Scan(&flows).Error; err != nil {
+		logger.Error("GetABIMatchedOpenzeppelinFlows Error:", err, "abi_id", abiID, "wallet_address", walletAddress)
+		return nil, nil, err
+	}
+	if len(flows) == 0 {
+		return flows, nil, nil
+	}
+
+	flowIDs := make([]string, len(flows))
+	for i, flow := range flows {
+		flowIDs[i] = flow.FlowID
+	}
+	var calls []types.OpenzeppelinFlowCallDB
+	if err := r.db.WithContext(ctx).
+		Where("flow_id IN ?", flowIDs).
+		Order("call_index ASC").
+		Find(&calls).Error; err != nil {
+		logger.Error("GetABIMatchedOpenzeppelinFlows calls Error:", err, "abi_id", abiID, "flows", len(flows))
+		return nil, nil, err
+	}
+	return flows, calls, nil
+}
+
+// UpsertFlowDecodedCalls 写入流程调用解码缓存（同一用户同一调用覆盖旧结果）
+func (r *repository) UpsertFlowDecodedCalls(ctx context.Context, rows []types.FlowDecodedCall) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for i := range rows {
+		rows[i].UserAddress = strings.ToLower(rows[i].UserAddress)
+		rows[i].ContractAddress = strings.ToLower(rows[i].ContractAddress)
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "user_address"}, {Name: "standard"}, {Name: "chain_id"},
+			{Name: "contract_address"}, {Name: "flow_id"}, {Name: "call_index"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"abi_id", "function_name", "function_signature", "params", "decoded_at"}),
+	}).CreateInBatches(&rows, 200).Error; err != nil {
+		logger.Error("UpsertFlowDecodedCalls Error:", err, "count", len(rows))
+		return err
+	}
+	return nil
+}
+
+// GetContractDecodeABIs 获取用户解码该合约流程时使用过的 ABI（仅限用户自己的与共享的，按 id 升序）
+func (r *repository) GetContractDecodeABIs(ctx context.Context, walletAddress, standard string, chainID int, contractAddress string) ([]types.ABI, error) {
+	normalizedWallet := strings.ToLower(walletAddress)
+	var abis []types.ABI
+	if err := r.db.WithContext(ctx).
+		Where(`id IN (
+			SELECT DISTINCT abi_id FROM flow_decoded_calls
+			WHERE user_address = ? AND standard = ? AND chain_id = ? AND contract_address = ?
+		)`, normalizedWallet, standard, chainID, strings.ToLower(contractAddress)).
+		Where("(LOWER(owner) = ? OR is_shared = ?)", normalizedWallet, true).
+		Order("id ASC").
+		Find(&abis).Error; err != nil {
+		logger.Error("GetContractDecodeABIs Error:", err, "wallet_address", walletAddress, "chain_id", chainID, "contract_address", contractAddress)
+		return nil, err
+	}
+	return abis, nil
+}
@@ -25,6 +25,16 @@ type Repository interface {
 	DeleteABI(ctx context.Context, id int64, walletAddress string) error
 	CheckABIOwnership(ctx context.Context, id int64, walletAddress string) (bool, error)
 	GetABIByNameAndOwner(ctx context.Context, name string, owner string) (*types.ABI, error)
+	GetABIPage(ctx context.Context, walletAddress, name string, includeShared bool, offset, limit int) ([]types.ABI, int64, error)
+	ReplaceABIFunctions(ctx context.Context, abiID int64, functions []types.ABIFunction) error
+	CountABIFunctions(ctx context.Context, abiIDs []int64) (map[int64]int, error)
+	CountABIUsage(ctx context.Context, walletAddress string, abiIDs []int64) (map[int64]int64, error)
+	GetABIMatchedCompoundFlows(ctx context.Context, walletAddress string, abiID int64, limit int) ([]types.CompoundTimelockFlowDB, error)
+	GetABIMatchedOpenzeppelinFlows(ctx context.Context, walletAddress string, abiID int64, limit int) ([]types.OpenzeppelinTimelockFlowDB, []types.OpenzeppelinFlowCallDB, error)
+	UpsertFlowDecodedCalls(ctx context.Context, rows []types.FlowDecodedCall) error
+	GetContractDecodeABIs(ctx context.Context, walletAddress, standard string, chainID int, contractAddress string) ([]types.ABI, error)
+	SearchSharedABIFunctions(ctx context.Context, functionName, selector string, limit int) ([]types.ABIFunctionMatch, error)
+	GetABIsBySelector(ctx context.Context, walletAddress, selector string) ([]types.ABI, error)
 }
 
 type repository struct {
@@ -139,22 +149,22 @@ func (r *repository) UpdateABI(ctx context.Context, abi *types.ABI) error {
 	return nil
 }
 
-// DeleteABI 删除ABI（硬删除）
+// DeleteABI 删除ABI（硬删除，同时删除其函数选择器索引）
 func (r *repository) DeleteABI(ctx context.Context, id int64, walletAddress string) error {
 	logger.Info("DeleteABI:", "id", id, "wallet_address", walletAddress)
 	normalizedWalletAddress := strings.ToLower(walletAddress)
-	// 确保只能删除自己的ABI
-	result := r.db.WithContext(ctx).
-		Where("id = ? AND LOWER(owner) = ?", id, normalizedWalletAddress).
-		Delete(&types.ABI{})
-
-	if result.Error != nil {
-		logger.Error("DeleteABI Error:", result.Error, "id", id, "wallet_address", walletAddress)
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		err := fmt.Errorf("ABI not found or access denied")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 确保只能删除自己的ABI
+		result := tx.Where("id = ? AND LOWER(owner) = ?", id, normalizedWalletAddress).Delete(&types.ABI{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("ABI not found or access denied")
+		}
+		return tx.Where("abi_id = ?", id).Delete(&types.ABIFunction{}).Error
+	})
+	if err != nil {
 		logger.Error("DeleteABI Error:", err, "id", id, "wallet_address", walletAddress)
 		return err
 	}
@@ -0,0 +1,92 @@
+package apitoken
+
+import (
+	"context"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// Repository API令牌仓库接口
+type Repository interface {
+	CreateAPIToken(ctx context.Context, token *types.APIToken) error
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*types.APIToken, error)
+	GetAPITokensByUser(ctx context.Context, userID int64) ([]types.APIToken, error)
+	RevokeAPIToken(ctx context.Context, id int64, userID int64) (bool, error)
+	UpdateLastUsed(ctx context.Context, id int64) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository 创建API令牌仓库实例
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// CreateAPIToken 创建API令牌
+func (r *repository) CreateAPIToken(ctx context.Context, token *types.APIToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		logger.Error("CreateAPIToken error", err, "user_id", token.UserID, "name", token.Name)
+		return err
+	}
+
+	logger.Info("CreateAPIToken success", "token_id", token.ID, "user_id", token.UserID, "name", token.Name)
+	return nil
+}
+
+// GetAPITokenByHash 根据令牌哈希获取API令牌
+func (r *repository) GetAPITokenByHash(ctx context.Context, tokenHash string) (*types.APIToken, error) {
+	var token types.APIToken
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetAPITokensByUser 获取用户的所有API令牌（含已吊销）
+func (r *repository) GetAPITokensByUser(ctx context.Context, userID int64) ([]types.APIToken, error) {
+	var tokens []types.APIToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		logger.Error("GetAPITokensByUser error", err, "user_id", userID)
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken 吊销API令牌，返回是否命中记录
+func (r *repository) RevokeAPIToken(ctx context.Context, id int64, userID int64) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&types.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", &now)
+	if result.Error != nil {
+		logger.Error("RevokeAPIToken error", result.Error, "token_id", id, "user_id", userID)
+		return false, result.Error
+	}
+
+	logger.Info("RevokeAPIToken success", "token_id", id, "user_id", userID, "rows", result.RowsAffected)
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateLastUsed 更新最近使用时间
+func (r *repository) UpdateLastUsed(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).
+		Model(&types.APIToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"timelocker-backend/internal/types"
+
+	"gorm.io/gorm"
+)
+
+type actorContextKey struct{}
+
+type entryContextKey struct{}
+
+// pendingEntry 挂在 ctx 上的待写审计日志，只在第一条命中行的写语句中写入一次
+type pendingEntry struct {
+	log     types.AuditLog
+	written atomic.Bool
+}
+
+// WithActor 在 ctx 中记录当前操作人钱包地址（由认证中间件设置）
+func WithActor(ctx context.Context, walletAddress string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, strings.ToLower(walletAddress))
+}
+
+// ActorFromContext 获取 ctx 中的操作人钱包地址
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
+
+// WithLog 为下一次写操作挂上一条审计日志，ctx 只应传给被审计的那一次仓库调用
+// 该写语句命中行时，审计日志在同一事务中写入；写入失败时整个变更回滚
+// targetID 为空时，create 使用新建记录的主键
+func WithLog(ctx context.Context, action, targetType, targetID, summary string) context.Context {
+	return context.WithValue(ctx, entryContextKey{}, &pendingEntry{log: types.AuditLog{
+		ActorAddress: ActorFromContext(ctx),
+		Action:       action,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		Summary:      summary,
+	}})
+}
+
+// RegisterCallbacks 注册 gorm 回调：create/update/delete 语句执行后、默认事务提交前写入 ctx 上挂的审计日志
+func RegisterCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("audit:create", writeAuditLog); err != nil {
+		return fmt.Errorf("failed to register audit create callback: %w", err)
+	}
+	if err := db.Callback().Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("audit:update", writeAuditLog); err != nil {
+		return fmt.Errorf("failed to register audit update callback: %w", err)
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("audit:delete", writeAuditLog); err != nil {
+		return fmt.Errorf("failed to register audit delete callback: %w", err)
+	}
+	return nil
+}
+
+// writeAuditLog 在当前语句所在的连接（默认事务）上写入审计日志
+func writeAuditLog(db *gorm.DB) {
+	if db.Error != nil || db.RowsAffected == 0 || db.Statement.Context == nil {
+		return
+	}
+	entry, ok := db.Statement.Context.Value(entryContextKey{}).(*pendingEntry)
+	if !ok || !entry.written.CompareAndSwap(false, true) {
+		return
+	}
+
+	log := entry.log
+	if log.TargetID == "" {
+		log.TargetID = primaryKeyOf(db)
+	}
+	// NewDB 会话沿用当前语句的 ConnPool（即事务），写入失败时让原语句回滚
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&log).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("failed to write audit log: %w", err))
+	}
+}
+
+// primaryKeyOf 获取 create 语句新建记录的主键
+func primaryKeyOf(db *gorm.DB) string {
+	if db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil || db.Statement.ReflectValue.Kind() != reflect.Struct {
+		return ""
+	}
+	value, isZero := db.Statement.Schema.PrioritizedPrimaryField.ValueOf(db.Statement.Context, db.Statement.ReflectValue)
+	if isZero {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"context"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// Repository 审计日志仓库接口
+type Repository interface {
+	ListAuditLogs(ctx context.Context, filter types.AuditLogFilter, offset, limit int) ([]types.AuditLog, int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository 创建审计日志仓库实例
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// ListAuditLogs 按条件分页查询审计日志，按创建时间倒序
+func (r *repository) ListAuditLogs(ctx context.Context, filter types.AuditLogFilter, offset, limit int) ([]types.AuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&types.AuditLog{})
+	if filter.ActorAddress != "" {
+		query = query.Where("actor_address = ?", filter.ActorAddress)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("ListAuditLogs count error", err, "actor", filter.ActorAddress)
+		return nil, 0, err
+	}
+
+	logs := []types.AuditLog{}
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&logs).Error; err != nil {
+		logger.Error("ListAuditLogs error", err, "actor", filter.ActorAddress)
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
@@ -27,6 +27,11 @@ type Repository interface {
 
 	// Goldsky Webhook 相关
 	GetChainByWebhookSecret(ctx context.Context, secret string) (*types.SupportChain, string, error) // 返回 chain, standard (compound/openzeppelin), error
+
+	// 区块浏览器 API Key，apiKey 为空表示清除；链不存在时返回 gorm.ErrRecordNotFound
+	UpdateExplorerAPIKey(ctx context.Context, chainID int64, apiKey string) error
+	// Webhook 事件确认深度；链不存在时返回 gorm.ErrRecordNotFound
+	UpdateConfirmationDepth(ctx context.Context, chainID int64, depth int) error
 }
 
 // repository 支持链仓库实现
@@ -227,3 +232,35 @@ func (r *repository) GetChainByWebhookSecret(ctx context.Context, secret string)
 	logger.Error("GetChainByWebhookSecret Error (OpenZeppelin): ", err)
 	return nil, "", err
 }
+
+// UpdateExplorerAPIKey 设置或清除链的区块浏览器 API Key
+func (r *repository) UpdateExplorerAPIKey(ctx context.Context, chainID int64, apiKey string) error {
+	result := r.db.WithContext(ctx).
+		Model(&types.SupportChain{}).
+		Where("chain_id = ?", chainID).
+		Update("explorer_api_key", apiKey)
+	if result.Error != nil {
+		logger.Error("UpdateExplorerAPIKey Error: ", result.Error, "chain_id", chainID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateConfirmationDepth 设置链的 Webhook 事件确认深度
+func (r *repository) UpdateConfirmationDepth(ctx context.Context, chainID int64, depth int) error {
+	result := r.db.WithContext(ctx).
+		Model(&types.SupportChain{}).
+		Where("chain_id = ?", chainID).
+		Update("confirmation_depth", depth)
+	if result.Error != nil {
+		logger.Error("UpdateConfirmationDepth Error: ", result.Error, "chain_id", chainID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"timelocker-backend/internal/types"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EmailRepository 邮箱仓储接口
@@ -21,9 +22,14 @@ type EmailRepository interface {
 	AddUserEmail(ctx context.Context, userID int64, emailID int64, remark *string) (*types.UserEmail, error)
 	GetUserEmails(ctx context.Context, userID int64, offset, limit int) ([]types.UserEmail, int64, error)
 	GetUserEmailByID(ctx context.Context, userEmailID int64, userID int64) (*types.UserEmail, error)
+	// 获取用户全部未验证的邮箱
+	GetUnverifiedUserEmails(ctx context.Context, userID int64) ([]types.UserEmail, error)
+	// 按钱包地址获取用户绑定的全部邮箱（含未验证）
+	GetUserEmailsByWalletAddress(ctx context.Context, walletAddress string) ([]types.UserEmail, error)
 	// 通过 userID + emailID 查询用户邮箱关系
 	GetUserEmailByUserAndEmailID(ctx context.Context, userID int64, emailID int64) (*types.UserEmail, error)
 	UpdateUserEmailRemark(ctx context.Context, userEmailID int64, userID int64, remark *string) error
+	UpdateUserEmailNotifyStatuses(ctx context.Context, userEmailID int64, userID int64, notifyStatuses *string) error
 	DeleteUserEmail(ctx context.Context, userEmailID int64, userID int64) error
 	VerifyUserEmail(ctx context.Context, userEmailID int64, userID int64) error
 	CheckUserEmailExists(ctx context.Context, userID int64, emailID int64) (bool, error)
@@ -35,11 +41,21 @@ type EmailRepository interface {
 	CleanExpiredCodes(ctx context.Context) error
 
 	// 通知查询相关（按合约相关用户的已验证邮箱）
-	GetContractRelatedVerifiedEmailIDs(ctx context.Context, standard string, chainID int, contractAddress string) ([]int64, error)
+	GetContractRelatedVerifiedEmailIDs(ctx context.Context, standard string, chainID int, contractAddress string, statusTo string) ([]int64, error)
+
+	// UserEmailPreference 相关
+	GetUserEmailPreference(ctx context.Context, userID int64) (*types.UserEmailPreference, error)
+	UpsertUserEmailPreference(ctx context.Context, pref *types.UserEmailPreference) error
+	// 获取已验证该邮箱的用户中最近更新的邮件偏好，无则返回 nil
+	GetEmailPreferenceByEmailID(ctx context.Context, emailID int64) (*types.UserEmailPreference, error)
 
 	// EmailSendLog 相关
 	CreateSendLog(ctx context.Context, log *types.EmailSendLog) error
 	CheckSendLogExists(ctx context.Context, emailID int64, flowID string, statusTo string) (bool, error)
+	DeleteSendLogs(ctx context.Context, standard string, chainID int, contractAddress, flowID, statusTo string) (int64, error)
+	// 临时失败重发
+	GetRetryableSendLogs(ctx context.Context, now time.Time, limit int) ([]types.EmailSendLog, error)
+	UpdateSendLogResult(ctx context.Context, log *types.EmailSendLog) error
 }
 
 // emailRepository 邮箱仓储实现
@@ -140,6 +156,31 @@ func (r *emailRepository) GetUserEmails(ctx context.Context, userID int64, offse
 	return userEmails, total, nil
 }
 
+// GetUnverifiedUserEmails 获取用户全部未验证的邮箱，按添加时间排序
+func (r *emailRepository) GetUnverifiedUserEmails(ctx context.Context, userID int64) ([]types.UserEmail, error) {
+	var userEmails []types.UserEmail
+	if err := r.db.WithContext(ctx).Preload("Email").
+		Where("user_id = ? AND is_verified = ?", userID, false).
+		Order("created_at ASC").
+		Find(&userEmails).Error; err != nil {
+		return nil, fmt.Errorf("failed to get unverified user emails: %w", err)
+	}
+	return userEmails, nil
+}
+
+// GetUserEmailsByWalletAddress 按钱包地址获取用户绑定的全部邮箱（含未验证），按绑定时间排序
+func (r *emailRepository) GetUserEmailsByWalletAddress(ctx context.Context, walletAddress string) ([]types.UserEmail, error) {
+	var userEmails []types.UserEmail
+	if err := r.db.WithContext(ctx).Preload("Email").
+		Joins("JOIN users u ON u.id = user_emails.user_id").
+		Where("LOWER(u.wallet_address) = ?", strings.ToLower(walletAddress)).
+		Order("user_emails.created_at ASC").
+		Find(&userEmails).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user emails by wallet address: %w", err)
+	}
+	return userEmails, nil
+}
+
 // GetUserEmailByID 根据用户邮箱ID获取用户邮箱
 func (r *emailRepository) GetUserEmailByID(ctx context.Context, userEmailID int64, userID int64) (*types.UserEmail, error) {
 	var userEmail types.UserEmail
@@ -180,6 +221,26 @@ func (r *emailRepository) UpdateUserEmailRemark(ctx context.Context, userEmailID
 	return nil
 }
 
+// UpdateUserEmailNotifyStatuses 更新用户邮箱的通知状态过滤，nil 表示接收全部状态
+func (r *emailRepository) UpdateUserEmailNotifyStatuses(ctx context.Context, userEmailID int64, userID int64, notifyStatuses *string) error {
+	result := r.db.WithContext(ctx).Model(&types.UserEmail{}).
+		Where("id = ? AND user_id = ?", userEmailID, userID).
+		Updates(map[string]interface{}{
+			"notify_statuses": notifyStatuses,
+			"updated_at":      time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user email notify statuses: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
 // DeleteUserEmail 删除用户邮箱
 func (r *emailRepository) DeleteUserEmail(ctx context.Context, userEmailID int64, userID int64) error {
 	result := r.db.WithContext(ctx).
@@ -298,10 +359,13 @@ func (r *emailRepository) CleanExpiredCodes(ctx context.Context) error {
 
 // ===== 通知查询相关方法 =====
 // GetContractRelatedVerifiedEmailIDs 获取与指定合约相关用户的已验证邮箱ID列表
-func (r *emailRepository) GetContractRelatedVerifiedEmailIDs(ctx context.Context, standard string, chainID int, contractAddress string) ([]int64, error) {
+// 只返回 notify_statuses 为空或包含 statusTo 的邮箱；同一邮箱被多个用户绑定时任一用户接收即返回
+func (r *emailRepository) GetContractRelatedVerifiedEmailIDs(ctx context.Context, standard string, chainID int, contractAddress string, statusTo string) ([]int64, error) {
 	var emailIDs []int64
 
 	normalizedContractAddress := strings.ToLower(contractAddress)
+	// notify_statuses 存储为 JSON 字符串数组，按带引号的状态名匹配
+	statusPattern := "%\"" + strings.ToLower(statusTo) + "\"%"
 	switch strings.ToLower(standard) {
 	case "compound":
 		// 用户是该合约的 admin 或 pending_admin
@@ -309,12 +373,13 @@ func (r *emailRepository) GetContractRelatedVerifiedEmailIDs(ctx context.Context
             SELECT DISTINCT e.id
             FROM users u
             JOIN user_emails ue ON ue.user_id = u.id AND ue.is_verified = TRUE
+                AND (ue.notify_statuses IS NULL OR ue.notify_statuses LIKE ?)
             JOIN emails e ON e.id = ue.email_id
             JOIN compound_timelocks t ON t.chain_id = ? AND LOWER(t.contract_address) = ?
             WHERE LOWER(u.wallet_address) = LOWER(t.admin)
                OR (t.pending_admin IS NOT NULL AND LOWER(u.wallet_address) = LOWER(t.pending_admin))
         `
-		if err := r.db.WithContext(ctx).Raw(sql, chainID, normalizedContractAddress).Pluck("id", &emailIDs).Error; err != nil {
+		if err := r.db.WithContext(ctx).Raw(sql, statusPattern, chainID, normalizedContractAddress).Pluck("id", &emailIDs).Error; err != nil {
 			return nil, fmt.Errorf("failed to query compound related emails: %w", err)
 		}
 	case "openzeppelin":
@@ -323,12 +388,13 @@ func (r *emailRepository) GetContractRelatedVerifiedEmailIDs(ctx context.Context
             SELECT DISTINCT e.id
             FROM users u
             JOIN user_emails ue ON ue.user_id = u.id AND ue.is_verified = TRUE
+                AND (ue.notify_statuses IS NULL OR ue.notify_statuses LIKE ?)
             JOIN emails e ON e.id = ue.email_id
             JOIN openzeppelin_timelocks t ON t.chain_id = ? AND LOWER(t.contract_address) = ?
             WHERE LOWER(t.proposers) LIKE ('%' || LOWER(u.wallet_address) || '%')
                OR LOWER(t.executors) LIKE ('%' || LOWER(u.wallet_address) || '%')
         `
-		if err := r.db.WithContext(ctx).Raw(sql, chainID, normalizedContractAddress).Pluck("id", &emailIDs).Error; err != nil {
+		if err := r.db.WithContext(ctx).Raw(sql, statusPattern, chainID, normalizedContractAddress).Pluck("id", &emailIDs).Error; err != nil {
 			return nil, fmt.Errorf("failed to query openzeppelin related emails: %w", err)
 		}
 	default:
@@ -338,6 +404,53 @@ func (r *emailRepository) GetContractRelatedVerifiedEmailIDs(ctx context.Context
 	return emailIDs, nil
 }
 
+// ===== UserEmailPreference 相关方法 =====
+// GetUserEmailPreference 获取用户邮件偏好，不存在时返回 nil
+func (r *emailRepository) GetUserEmailPreference(ctx context.Context, userID int64) (*types.UserEmailPreference, error) {
+	var pref types.UserEmailPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&pref).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user email preference: %w", err)
+	}
+	return &pref, nil
+}
+
+// UpsertUserEmailPreference 创建或更新用户邮件偏好
+func (r *emailRepository) UpsertUserEmailPreference(ctx context.Context, pref *types.UserEmailPreference) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cc_email", "bcc_email", "from_name", "updated_at"}),
+	}).Create(pref).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert user email preference: %w", err)
+	}
+	return nil
+}
+
+// GetEmailPreferenceByEmailID 获取已验证该邮箱的用户的邮件偏好
+// 同一邮箱可能被多个用户绑定，取最近更新的一条
+func (r *emailRepository) GetEmailPreferenceByEmailID(ctx context.Context, emailID int64) (*types.UserEmailPreference, error) {
+	var prefs []types.UserEmailPreference
+	err := r.db.WithContext(ctx).
+		Table("user_email_preferences p").
+		Select("p.*").
+		Joins("JOIN user_emails ue ON ue.user_id = p.user_id AND ue.is_verified = TRUE").
+		Where("ue.email_id = ?", emailID).
+		Order("p.updated_at DESC").
+		Limit(1).
+		Find(&prefs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email preference by email id: %w", err)
+	}
+	if len(prefs) == 0 {
+		return nil, nil
+	}
+	return &prefs[0], nil
+}
+
 // ===== EmailSendLog 相关方法 =====
 // CreateSendLog 创建发送日志
 func (r *emailRepository) CreateSendLog(ctx context.Context, log *types.EmailSendLog) error {
@@ -358,3 +471,48 @@ func (r *emailRepository) CheckSendLogExists(ctx context.Context, emailID int64,
 	}
 	return count > 0, nil
 }
+
+// DeleteSendLogs 删除指定 flow 某一状态的发送日志（用于管理员手动重发时清除去重记录）
+func (r *emailRepository) DeleteSendLogs(ctx context.Context, standard string, chainID int, contractAddress, flowID, statusTo string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("timelock_standard = ? AND chain_id = ? AND LOWER(contract_address) = ? AND flow_id = ? AND status_to = ?",
+			standard, chainID, strings.ToLower(contractAddress), flowID, statusTo).
+		Delete(&types.EmailSendLog{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete send logs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetRetryableSendLogs 获取已到重发时间的临时失败发送日志（附带邮箱地址）
+func (r *emailRepository) GetRetryableSendLogs(ctx context.Context, now time.Time, limit int) ([]types.EmailSendLog, error) {
+	var logs []types.EmailSendLog
+	err := r.db.WithContext(ctx).
+		Preload("Email").
+		Where("send_status = ? AND permanent_failure = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", "failed", false, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&logs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retryable send logs: %w", err)
+	}
+	return logs, nil
+}
+
+// UpdateSendLogResult 更新发送日志的重发结果
+func (r *emailRepository) UpdateSendLogResult(ctx context.Context, log *types.EmailSendLog) error {
+	err := r.db.WithContext(ctx).Model(&types.EmailSendLog{}).
+		Where("id = ?", log.ID).
+		Updates(map[string]interface{}{
+			"send_status":       log.SendStatus,
+			"error_message":     log.ErrorMessage,
+			"retry_count":       log.RetryCount,
+			"permanent_failure": log.PermanentFailure,
+			"next_retry_at":     log.NextRetryAt,
+			"sent_at":           log.SentAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update send log result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package errorlog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// Repository error_logs 查询仓库接口（写入由 logger.DBErrorWriter 完成）
+type Repository interface {
+	ListErrorLogs(ctx context.Context, filter types.AdminErrorLogFilter, offset, limit int) ([]types.AdminErrorLog, int64, error)
+	// 删除 before 之前的记录（单批最多 limit 条），返回删除行数
+	DeleteErrorLogsBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	// VACUUM ANALYZE error_logs，回收删除后的空间并清理索引中的死元组
+	VacuumErrorLogs(ctx context.Context) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository 创建 error_logs 查询仓库实例
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// ListErrorLogs 按条件分页查询 error_logs，按时间倒序
+func (r *repository) ListErrorLogs(ctx context.Context, filter types.AdminErrorLogFilter, offset, limit int) ([]types.AdminErrorLog, int64, error) {
+	query := r.db.WithContext(ctx).Table("error_logs")
+	if filter.Level != "" {
+		query = query.Where("level = ?", filter.Level)
+	}
+	if filter.Caller != "" {
+		query = query.Where("caller LIKE ?", escapeLike(filter.Caller)+"%")
+	}
+	if filter.Function != "" {
+		query = query.Where("function = ?", filter.Function)
+	}
+	if filter.Keyword != "" {
+		like := "%" + escapeLike(strings.ToLower(filter.Keyword)) + "%"
+		query = query.Where("(LOWER(message) LIKE ? OR LOWER(error) LIKE ?)", like, like)
+	}
+	if filter.From != nil {
+		query = query.Where("timestamp >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("timestamp < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("ListErrorLogs count error", err, "level", filter.Level)
+		return nil, 0, err
+	}
+
+	logs := []types.AdminErrorLog{}
+	if err := query.Select("id, timestamp, level, caller, function, message, COALESCE(error, '') AS error, COALESCE(context, '') AS context").
+		Order("timestamp DESC, id DESC").Offset(offset).Limit(limit).Scan(&logs).Error; err != nil {
+		logger.Error("ListErrorLogs error", err, "level", filter.Level)
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// DeleteErrorLogsBefore 删除 before 之前的 error_logs（单批最多 limit 条），分批删除避免长时间锁表
+func (r *repository) DeleteErrorLogsBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+        DELETE FROM error_logs
+        WHERE id IN (
+            SELECT id FROM error_logs
+            WHERE timestamp < ?
+            ORDER BY id
+            LIMIT ?
+        )`, before, limit)
+	if result.Error != nil {
+		logger.Error("DeleteErrorLogsBefore error", result.Error, "before", before)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// VacuumErrorLogs 回收 error_logs 的死元组（含 GIN 全文索引），不能在事务中执行
+func (r *repository) VacuumErrorLogs(ctx context.Context) error {
+	if err := r.db.WithContext(ctx).Exec("VACUUM (ANALYZE) error_logs").Error; err != nil {
+		logger.Error("VacuumErrorLogs error", err)
+		return err
+	}
+	return nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,188 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// adminFlowRow 管理员流程列表的查询结果行
+type adminFlowRow struct {
+	Standard          string
+	FlowID            string
+	ChainID           int
+	ContractAddress   string
+	ContractRemark    *string
+	ContractCreator   *string
+	Status            string
+	QueueTxHash       *string
+	ExecuteTxHash     *string
+	CancelTxHash      *string
+	InitiatorAddress  *string
+	TargetAddress     *string
+	FunctionSignature *string
+	Value             string
+	Eta               *time.Time
+	ExecutedAt        *time.Time
+	CancelledAt       *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// ListAllFlows 管理员查询全部流程：直接查询两张 flow 表，合约备注与创建者取最早导入该合约的记录
+func (r *flowRepository) ListAllFlows(ctx context.Context, filter types.AdminFlowFilter, offset int, limit int) ([]types.AdminFlowResponse, int64, error) {
+	var branches []string
+	var args []interface{}
+
+	if filter.Standard == "" || filter.Standard == "compound" {
+		where, whereArgs := adminFlowWhere(compoundFlowsTable, filter)
+		branches = append(branches, fmt.Sprintf(`
+            SELECT 'compound' AS standard, %[1]s.flow_id, %[1]s.chain_id, %[1]s.contract_address,
+                   t.remark AS contract_remark, t.creator_address AS contract_creator, %[1]s.status,
+                   %[1]s.queue_tx_hash, %[1]s.execute_tx_hash, %[1]s.cancel_tx_hash,
+                   %[1]s.initiator_address, %[1]s.target_address, %[1]s.function_signature,
+                   %[1]s.value::text AS value, %[1]s.eta, %[1]s.executed_at, %[1]s.cancelled_at,
+                   %[1]s.created_at, %[1]s.updated_at
+            FROM %[2]s
+            LEFT JOIN LATERAL (
+                SELECT remark, creator_address FROM compound_timelocks
+                WHERE chain_id = %[1]s.chain_id AND LOWER(contract_address) = LOWER(%[1]s.contract_address)
+                ORDER BY created_at ASC LIMIT 1
+            ) t ON TRUE
+            WHERE %[3]s`, compoundFlowsTable, compoundFlowsSource(filter.IncludeArchived), where))
+		args = append(args, whereArgs...)
+	}
+	if filter.Standard == "" || filter.Standard == "openzeppelin" {
+		where, whereArgs := adminFlowWhere(openzeppelinFlowsTable, filter)
+		branches = append(branches, fmt.Sprintf(`
+            SELECT 'openzeppelin' AS standard, %[1]s.flow_id, %[1]s.chain_id, %[1]s.contract_address,
+                   t.remark AS contract_remark, t.creator_address AS contract_creator, %[1]s.status,
+                   %[1]s.schedule_tx_hash AS queue_tx_hash, %[1]s.execute_tx_hash, %[1]s.cancel_tx_hash,
+                   %[1]s.initiator_address, %[1]s.target_address, NULL AS function_signature,
+                   %[1]s.value::text AS value, %[1]s.eta, %[1]s.executed_at, %[1]s.cancelled_at,
+                   %[1]s.created_at, %[1]s.updated_at
+            FROM %[2]s
+            LEFT JOIN LATERAL (
+                SELECT remark, creator_address FROM openzeppelin_timelocks
+                WHERE chain_id = %[1]s.chain_id AND LOWER(contract_address) = LOWER(%[1]s.contract_address)
+                ORDER BY created_at ASC LIMIT 1
+            ) t ON TRUE
+            WHERE %[3]s`, openzeppelinFlowsTable, openzeppelinFlowsSource(filter.IncludeArchived), where))
+		args = append(args, whereArgs...)
+	}
+
+	union := strings.Join(branches, "\n            UNION ALL")
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM ("+union+") AS flows", args...).Scan(&total).Error; err != nil {
+		logger.Error("ListAllFlows count error", err, "standard", filter.Standard, "chain_id", filter.ChainID)
+		return nil, 0, err
+	}
+
+	var rows []adminFlowRow
+	pageArgs := append(append([]interface{}{}, args...), limit, offset)
+	if err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM ("+union+") AS flows ORDER BY created_at DESC, flow_id ASC LIMIT ? OFFSET ?", pageArgs...).
+		Scan(&rows).Error; err != nil {
+		logger.Error("ListAllFlows query error", err, "standard", filter.Standard, "chain_id", filter.ChainID)
+		return nil, 0, err
+	}
+
+	responses := make([]types.AdminFlowResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = types.AdminFlowResponse{
+			Standard:          row.Standard,
+			FlowID:            row.FlowID,
+			ChainID:           row.ChainID,
+			ContractAddress:   row.ContractAddress,
+			Status:            row.Status,
+			QueueTxHash:       row.QueueTxHash,
+			ExecuteTxHash:     row.ExecuteTxHash,
+			CancelTxHash:      row.CancelTxHash,
+			InitiatorAddress:  row.InitiatorAddress,
+			TargetAddress:     row.TargetAddress,
+			FunctionSignature: row.FunctionSignature,
+			Value:             row.Value,
+			Eta:               row.Eta,
+			ExecutedAt:        row.ExecutedAt,
+			CancelledAt:       row.CancelledAt,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+		}
+		if row.ContractRemark != nil {
+			responses[i].ContractRemark = *row.ContractRemark
+		}
+		if row.ContractCreator != nil {
+			responses[i].ContractCreator = *row.ContractCreator
+		} else {
+			// 没有任何用户的合约记录：合约已被删除，flow 仅作历史保留
+			responses[i].ContractRemoved = true
+		}
+	}
+	return responses, total, nil
+}
+
+// adminFlowWhere 构建单张 flow 表的过滤条件
+func adminFlowWhere(table string, filter types.AdminFlowFilter) (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	var args []interface{}
+
+	if filter.ChainID > 0 {
+		conditions = append(conditions, table+".chain_id = ?")
+		args = append(args, filter.ChainID)
+	}
+	if filter.Status != "" && filter.Status != "all" {
+		conditions = append(conditions, table+".status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.ContractAddress != "" {
+		conditions = append(conditions, "LOWER("+table+".contract_address) = ?")
+		args = append(args, strings.ToLower(filter.ContractAddress))
+	}
+	if filter.Initiator != "" {
+		condition, conditionArgs := initiatorAddressCondition(table+".initiator_address", filter.Initiator)
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, table+".created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, table+".created_at < ?")
+		args = append(args, *filter.CreatedTo)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetCompoundFlowsNeedStatusUpdateByScope 获取指定范围内需要更新状态的 Compound Flows
+// userAddress 非空时取与该用户相关的 flow（权限判断与 IsUserRelatedToFlow 一致），否则取 chainID + contractAddress 下的 flow
+func (r *flowRepository) GetCompoundFlowsNeedStatusUpdateByScope(ctx context.Context, userAddress string, chainID int, contractAddress string, now time.Time, limit int) ([]types.CompoundTimelockFlowDB, error) {
+	query := r.db.WithContext(ctx).Where(
+		"((status = ? AND eta IS NOT NULL AND eta <= ?) OR (status = ? AND expired_at IS NOT NULL AND expired_at <= ?))",
+		"waiting", now, "ready", now,
+	)
+	if userAddress != "" {
+		normalizedUserAddress := strings.ToLower(userAddress)
+		query = query.Where(`(LOWER(initiator_address) = ? OR EXISTS (
+			SELECT 1 FROM compound_timelocks
+			WHERE chain_id = compound_timelock_flows.chain_id
+			AND LOWER(contract_address) = LOWER(compound_timelock_flows.contract_address)
+			AND (LOWER(admin) = ? OR LOWER(pending_admin) = ? OR LOWER(creator_address) = ?)
+			AND status = ?
+		))`, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, "active")
+	} else {
+		query = query.Where("chain_id = ? AND LOWER(contract_address) = ?", chainID, strings.ToLower(contractAddress))
+	}
+
+	var flows []types.CompoundTimelockFlowDB
+	if err := query.Order("eta ASC, id ASC").Limit(limit).Find(&flows).Error; err != nil {
+		logger.Error("Failed to get compound flows need status update by scope", err, "user", userAddress, "chain_id", chainID, "contract_address", contractAddress)
+		return nil, err
+	}
+	return flows, nil
+}
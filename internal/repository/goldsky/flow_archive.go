@@ -0,0 +1,108 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/pkg/logger"
+)
+
+const (
+	compoundFlowsTable            = "compound_timelock_flows"
+	compoundFlowsArchiveTable     = "compound_timelock_flows_archive"
+	openzeppelinFlowsTable        = "openzeppelin_timelock_flows"
+	openzeppelinFlowsArchiveTable = "openzeppelin_timelock_flows_archive"
+)
+
+// archivableFlowStatuses 可归档的终态
+var archivableFlowStatuses = []string{"executed", "cancelled", "expired"}
+
+// compoundFlowsSource 查询 Compound flow 的数据源，包含归档时合并归档表
+// 合并后的子查询仍命名为 compound_timelock_flows，已有的关联子查询条件无需修改
+func compoundFlowsSource(includeArchived bool) string {
+	if !includeArchived {
+		return compoundFlowsTable
+	}
+	return fmt.Sprintf("(SELECT * FROM %s UNION ALL SELECT * FROM %s) AS %s", compoundFlowsTable, compoundFlowsArchiveTable, compoundFlowsTable)
+}
+
+// openzeppelinFlowsSource 查询 OpenZeppelin flow 的数据源，规则与 compoundFlowsSource 相同
+func openzeppelinFlowsSource(includeArchived bool) string {
+	if !includeArchived {
+		return openzeppelinFlowsTable
+	}
+	return fmt.Sprintf("(SELECT * FROM %s UNION ALL SELECT * FROM %s) AS %s", openzeppelinFlowsTable, openzeppelinFlowsArchiveTable, openzeppelinFlowsTable)
+}
+
+// ArchiveTerminalFlows 将 before 之前最后更新的终态 flow 从热表移入归档表（单批最多 limit 条），返回按状态统计的移动数量
+func (r *flowRepository) ArchiveTerminalFlows(ctx context.Context, standard string, before time.Time, limit int) (map[string]int64, error) {
+	var hotTable, archiveTable string
+	switch strings.ToLower(standard) {
+	case "compound":
+		hotTable, archiveTable = compoundFlowsTable, compoundFlowsArchiveTable
+	case "openzeppelin":
+		hotTable, archiveTable = openzeppelinFlowsTable, openzeppelinFlowsArchiveTable
+	default:
+		return nil, fmt.Errorf("invalid standard: %s", standard)
+	}
+
+	// 删除与插入在同一条语句中完成，保证不会丢失或重复；归档表已存在的记录直接丢弃热表副本
+	sql := fmt.Sprintf(`
+        WITH moved AS (
+            DELETE FROM %[1]s
+            WHERE id IN (
+                SELECT id FROM %[1]s
+                WHERE status IN ? AND updated_at < ?
+                ORDER BY id
+                LIMIT ?
+            )
+            RETURNING *
+        )
+        INSERT INTO %[2]s SELECT * FROM moved
+        ON CONFLICT DO NOTHING
+        RETURNING status`, hotTable, archiveTable)
+
+	var statuses []string
+	if err := r.db.WithContext(ctx).Raw(sql, archivableFlowStatuses, before, limit).Scan(&statuses).Error; err != nil {
+		logger.Error("ArchiveTerminalFlows error", err, "standard", standard, "before", before)
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, status := range statuses {
+		counts[status]++
+	}
+	return counts, nil
+}
+
+// GetArchivedCompoundFlowKeys 获取一批合约下已归档 flow 的 key 集合（key 与 CompoundFlowKey 一致），同步时用于跳过已归档的 flow
+func (r *flowRepository) GetArchivedCompoundFlowKeys(ctx context.Context, chainID int, contractAddresses []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if len(contractAddresses) == 0 {
+		return result, nil
+	}
+	lowered := make([]string, len(contractAddresses))
+	for i, a := range contractAddresses {
+		lowered[i] = strings.ToLower(a)
+	}
+
+	var rows []struct {
+		FlowID          string
+		ContractAddress string
+	}
+	if err := r.db.WithContext(ctx).
+		Table(compoundFlowsArchiveTable).
+		Select("flow_id, contract_address").
+		Where("chain_id = ? AND LOWER(contract_address) IN ?", chainID, lowered).
+		Scan(&rows).Error; err != nil {
+		logger.Error("GetArchivedCompoundFlowKeys error", err, "chain_id", chainID, "contracts", len(contractAddresses))
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[compoundFlowKey(row.FlowID, row.ContractAddress)] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package goldsky
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// capturedStatement 记录 DryRun 模式下生成的 SQL 与参数
+type capturedStatement struct {
+	sql  string
+	vars []interface{}
+}
+
+// newDryRunRepository 返回不连接数据库的 flowRepository，执行的语句记录到返回的切片中
+// DryRun 模式下 Raw().Scan 只生成语句，随后返回 gorm.ErrDryRunModeUnsupported
+func newDryRunRepository(t *testing.T) (*flowRepository, *[]capturedStatement) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	var captured []capturedStatement
+	if err := db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		captured = append(captured, capturedStatement{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return &flowRepository{db: db}, &captured
+}
+
+func TestArchiveTerminalFlowsMovesOnlyTerminalRows(t *testing.T) {
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		standard     string
+		hotTable     string
+		archiveTable string
+	}{
+		{"compound", compoundFlowsTable, compoundFlowsArchiveTable},
+		{"OpenZeppelin", openzeppelinFlowsTable, openzeppelinFlowsArchiveTable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.standard, func(t *testing.T) {
+			r, captured := newDryRunRepository(t)
+			if _, err := r.ArchiveTerminalFlows(context.Background(), tt.standard, before, 100); err != nil && !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+				t.Fatalf("ArchiveTerminalFlows: %v", err)
+			}
+			if len(*captured) != 1 {
+				t.Fatalf("captured %d statements, want 1", len(*captured))
+			}
+			stmt := (*captured)[0]
+			sql := strings.Join(strings.Fields(stmt.sql), " ")
+			for _, want := range []string{
+				"DELETE FROM " + tt.hotTable,
+				"WHERE status IN ($1,$2,$3) AND updated_at < $4",
+				"LIMIT $5",
+				"INSERT INTO " + tt.archiveTable + " SELECT * FROM moved",
+			} {
+				if !strings.Contains(sql, want) {
+					t.Fatalf("sql %q does not contain %q", sql, want)
+				}
+			}
+
+			// 只有终态参与归档，waiting/ready 留在热表
+			if len(stmt.vars) != 5 {
+				t.Fatalf("vars = %v, want 5 values", stmt.vars)
+			}
+			statuses := map[interface{}]bool{stmt.vars[0]: true, stmt.vars[1]: true, stmt.vars[2]: true}
+			for _, status := range []string{"executed", "cancelled", "expired"} {
+				if !statuses[status] {
+					t.Fatalf("status %q not archived, vars = %v", status, stmt.vars)
+				}
+			}
+			for _, status := range []string{"waiting", "ready"} {
+				if statuses[status] {
+					t.Fatalf("non-terminal status %q archived", status)
+				}
+			}
+			if stmt.vars[3] != before || stmt.vars[4] != 100 {
+				t.Fatalf("vars = %v, want before=%s limit=100", stmt.vars, before)
+			}
+		})
+	}
+
+	r, captured := newDryRunRepository(t)
+	if _, err := r.ArchiveTerminalFlows(context.Background(), "unknown", before, 100); err == nil {
+		t.Fatal("expected error for unknown standard")
+	}
+	if len(*captured) != 0 {
+		t.Fatalf("unknown standard executed %d statements", len(*captured))
+	}
+}
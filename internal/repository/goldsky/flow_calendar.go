@@ -0,0 +1,62 @@
+package goldsky
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// flowCalendarSQL 用户相关的 waiting/ready 流程，按 eta 在指定时区的日期分桶
+// 权限判断与 IsUserRelatedToFlow 一致
+const flowCalendarSQL = `
+SELECT * FROM (
+	SELECT 'compound' AS standard, f.flow_id, f.chain_id, f.contract_address, f.status, f.eta,
+		f.target_address, f.function_signature,
+		to_char(date_trunc('day', f.eta AT TIME ZONE CAST(@tz AS TEXT)), 'YYYY-MM-DD') AS day
+	FROM compound_timelock_flows f
+	WHERE f.status IN ('waiting', 'ready') AND f.eta >= @from AND f.eta < @to
+	AND (LOWER(f.initiator_address) = @user OR EXISTS (
+		SELECT 1 FROM compound_timelocks t
+		WHERE t.chain_id = f.chain_id
+		AND LOWER(t.contract_address) = LOWER(f.contract_address)
+		AND (LOWER(t.admin) = @user OR LOWER(t.pending_admin) = @user OR LOWER(t.creator_address) = @user)
+		AND t.status = 'active'
+	))
+	UNION ALL
+	SELECT 'openzeppelin' AS standard, f.flow_id, f.chain_id, f.contract_address, f.status, f.eta,
+		f.target_address, NULL AS function_signature,
+		to_char(date_trunc('day', f.eta AT TIME ZONE CAST(@tz AS TEXT)), 'YYYY-MM-DD') AS day
+	FROM openzeppelin_timelock_flows f
+	WHERE f.status IN ('waiting', 'ready') AND f.eta >= @from AND f.eta < @to
+	AND (LOWER(f.initiator_address) = @user OR EXISTS (
+		SELECT 1 FROM openzeppelin_timelocks t
+		WHERE t.chain_id = f.chain_id
+		AND LOWER(t.contract_address) = LOWER(f.contract_address)
+		AND (LOWER(t.creator_address) = @user OR LOWER(t.proposers) LIKE @like OR LOWER(t.executors) LIKE @like)
+		AND t.status = 'active'
+	))
+) calendar
+ORDER BY eta ASC, standard ASC, flow_id ASC
+LIMIT @limit`
+
+// GetUserFlowCalendar 获取用户相关、eta 在 [from, to) 内的 waiting/ready 流程，day 为 eta 在 timezone 下的日期
+func (r *flowRepository) GetUserFlowCalendar(ctx context.Context, userAddress string, from, to time.Time, timezone string, limit int) ([]types.FlowCalendarEntry, error) {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	var entries []types.FlowCalendarEntry
+	err := r.db.WithContext(ctx).Raw(flowCalendarSQL, map[string]interface{}{
+		"user":  normalizedUserAddress,
+		"like":  "%" + normalizedUserAddress + "%",
+		"tz":    timezone,
+		"from":  from,
+		"to":    to,
+		"limit": limit,
+	}).Scan(&entries).Error
+	if err != nil {
+		logger.Error("Failed to get user flow calendar", err, "user", normalizedUserAddress, "from", from, "to", to, "tz", timezone)
+		return nil, err
+	}
+	return entries, nil
+}
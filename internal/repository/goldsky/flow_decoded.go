@@ -0,0 +1,50 @@
+package goldsky
+
+import (
+	"context"
+	"encoding/json"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// attachDecodedCalls 为流程列表附上当前用户通过 ABI 重新解码得到的调用信息（一次查询，失败只记录日志）
+func (r *flowRepository) attachDecodedCalls(ctx context.Context, normalizedUserAddress, standard string, responses []types.CompoundFlowResponse) {
+	if len(responses) == 0 {
+		return
+	}
+	flowIDs := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		flowIDs = append(flowIDs, resp.FlowID)
+	}
+
+	var rows []types.FlowDecodedCall
+	if err := r.db.WithContext(ctx).
+		Where("user_address = ? AND standard = ? AND flow_id IN ?", normalizedUserAddress, standard, flowIDs).
+		Order("call_index ASC").
+		Find(&rows).Error; err != nil {
+		logger.Error("Failed to query flow decoded calls", err, "standard", standard, "flows", len(flowIDs))
+		return
+	}
+
+	byKey := make(map[string][]types.DecodedFlowCall, len(rows))
+	for _, row := range rows {
+		params := []types.CalldataParam{}
+		if err := json.Unmarshal([]byte(row.Params), &params); err != nil {
+			logger.Warn("Invalid decoded call params, skipping", "flow_id", row.FlowID, "call_index", row.CallIndex, "error", err)
+			continue
+		}
+		key := flowNoteKey(row.ChainID, row.ContractAddress, row.FlowID)
+		byKey[key] = append(byKey[key], types.DecodedFlowCall{
+			CallIndex:         row.CallIndex,
+			ABIID:             row.ABIID,
+			FunctionName:      row.FunctionName,
+			FunctionSignature: row.FunctionSignature,
+			Params:            params,
+			DecodedAt:         row.DecodedAt,
+		})
+	}
+	for i := range responses {
+		responses[i].DecodedCalls = byKey[flowNoteKey(responses[i].ChainID, responses[i].ContractAddress, responses[i].FlowID)]
+	}
+}
@@ -0,0 +1,97 @@
+package goldsky
+
+import (
+	"context"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// CreateFlowFlag 记录流程标记
+func (r *flowRepository) CreateFlowFlag(ctx context.Context, flag *types.FlowFlag) error {
+	flag.ContractAddress = strings.ToLower(flag.ContractAddress)
+	flag.FlaggedBy = strings.ToLower(flag.FlaggedBy)
+	if err := r.db.WithContext(ctx).Create(flag).Error; err != nil {
+		logger.Error("CreateFlowFlag error", err, "standard", flag.Standard, "chain_id", flag.ChainID, "flow_id", flag.FlowID)
+		return err
+	}
+	return nil
+}
+
+// MarkFlowFlagNotified 标记告警已发送
+func (r *flowRepository) MarkFlowFlagNotified(ctx context.Context, id int64) error {
+	if err := r.db.WithContext(ctx).Model(&types.FlowFlag{}).Where("id = ?", id).Update("notified", true).Error; err != nil {
+		logger.Error("MarkFlowFlagNotified error", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+// ListFlowFlags 分页查询合约下的流程标记（flowID 为空时查询全部流程），按标记时间倒序
+func (r *flowRepository) ListFlowFlags(ctx context.Context, standard string, chainID int, contractAddress, flowID string, offset, limit int) ([]types.FlowFlag, int64, error) {
+	query := r.db.WithContext(ctx).Model(&types.FlowFlag{}).
+		Where("standard = ? AND chain_id = ? AND contract_address = ?", standard, chainID, strings.ToLower(contractAddress))
+	if flowID != "" {
+		query = query.Where("flow_id = ?", flowID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("ListFlowFlags count error", err, "standard", standard, "chain_id", chainID, "contract_address", contractAddress)
+		return nil, 0, err
+	}
+
+	flags := []types.FlowFlag{}
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&flags).Error; err != nil {
+		logger.Error("ListFlowFlags error", err, "standard", standard, "chain_id", chainID, "contract_address", contractAddress)
+		return nil, 0, err
+	}
+	return flags, total, nil
+}
+
+// IsUserRelatedToContract 判断用户是否与合约相关：合约相关角色（与 IsUserRelatedToFlow 一致），或合约下任一 flow 的发起人
+func (r *flowRepository) IsUserRelatedToContract(ctx context.Context, userAddress string, standard string, chainID int, contractAddress string) (bool, error) {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	var related bool
+
+	switch standard {
+	case "compound":
+		err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (
+				SELECT 1 FROM compound_timelocks
+				WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?)
+				AND (LOWER(admin) = ? OR LOWER(pending_admin) = ? OR LOWER(creator_address) = ?)
+				AND status = ?
+			) OR EXISTS (
+				SELECT 1 FROM compound_timelock_flows
+				WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?) AND LOWER(initiator_address) = ?
+			)`, chainID, contractAddress, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, "active",
+			chainID, contractAddress, normalizedUserAddress).
+			Scan(&related).Error
+		if err != nil {
+			logger.Error("Failed to check compound contract relation", err, "chain_id", chainID, "contract_address", contractAddress, "user", normalizedUserAddress)
+			return false, err
+		}
+	case "openzeppelin":
+		likePattern := "%" + normalizedUserAddress + "%"
+		err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (
+				SELECT 1 FROM openzeppelin_timelocks
+				WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?)
+				AND (LOWER(creator_address) = ? OR LOWER(proposers) LIKE ? OR LOWER(executors) LIKE ?)
+				AND status = ?
+			) OR EXISTS (
+				SELECT 1 FROM openzeppelin_timelock_flows
+				WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?) AND LOWER(initiator_address) = ?
+			)`, chainID, contractAddress, normalizedUserAddress, likePattern, likePattern, "active",
+			chainID, contractAddress, normalizedUserAddress).
+			Scan(&related).Error
+		if err != nil {
+			logger.Error("Failed to check openzeppelin contract relation", err, "chain_id", chainID, "contract_address", contractAddress, "user", normalizedUserAddress)
+			return false, err
+		}
+	default:
+		return false, nil
+	}
+
+	return related, nil
+}
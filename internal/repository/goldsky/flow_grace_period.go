@@ -0,0 +1,24 @@
+package goldsky
+
+import (
+	"context"
+
+	"timelocker-backend/pkg/logger"
+)
+
+// ApplyCompoundGracePeriod 按合约宽限期补算未终结 flow 的过期时间
+// 只处理数据源未提供过期时间或此前为估算值的 flow：estimated=true 时写入兜底值并标记估算，estimated=false 时用链上值修正并清除标记
+func (r *flowRepository) ApplyCompoundGracePeriod(ctx context.Context, chainID int, contractAddress string, gracePeriod int64, estimated bool) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE compound_timelock_flows
+		SET grace_period = ?, expired_at = eta + ? * INTERVAL '1 second', grace_period_estimated = ?, updated_at = NOW()
+		WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?)
+		AND eta IS NOT NULL AND status IN ('waiting', 'ready')
+		AND (expired_at IS NULL OR grace_period_estimated = TRUE)`,
+		gracePeriod, gracePeriod, estimated, chainID, contractAddress)
+	if result.Error != nil {
+		logger.Error("ApplyCompoundGracePeriod error", result.Error, "chain_id", chainID, "contract_address", contractAddress, "estimated", estimated)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
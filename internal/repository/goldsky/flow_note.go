@@ -0,0 +1,87 @@
+package goldsky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetFlowNote 获取流程备注，不存在时返回 nil
+func (r *flowRepository) GetFlowNote(ctx context.Context, standard string, chainID int, contractAddress, flowID string) (*types.FlowNote, error) {
+	var note types.FlowNote
+	err := r.db.WithContext(ctx).
+		Where("standard = ? AND chain_id = ? AND contract_address = ? AND flow_id = ?", standard, chainID, strings.ToLower(contractAddress), flowID).
+		First(&note).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("GetFlowNote error", err, "standard", standard, "chain_id", chainID, "flow_id", flowID)
+		return nil, err
+	}
+	return &note, nil
+}
+
+// UpsertFlowNote 创建或覆盖流程备注
+func (r *flowRepository) UpsertFlowNote(ctx context.Context, note *types.FlowNote) error {
+	note.ContractAddress = strings.ToLower(note.ContractAddress)
+	note.UpdatedBy = strings.ToLower(note.UpdatedBy)
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "standard"}, {Name: "chain_id"}, {Name: "contract_address"}, {Name: "flow_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"note", "updated_by", "updated_at"}),
+	}).Create(note).Error; err != nil {
+		logger.Error("UpsertFlowNote error", err, "standard", note.Standard, "chain_id", note.ChainID, "flow_id", note.FlowID)
+		return err
+	}
+	return nil
+}
+
+// DeleteFlowNote 删除流程备注（不存在时不报错）
+func (r *flowRepository) DeleteFlowNote(ctx context.Context, standard string, chainID int, contractAddress, flowID string) error {
+	if err := r.db.WithContext(ctx).
+		Where("standard = ? AND chain_id = ? AND contract_address = ? AND flow_id = ?", standard, chainID, strings.ToLower(contractAddress), flowID).
+		Delete(&types.FlowNote{}).Error; err != nil {
+		logger.Error("DeleteFlowNote error", err, "standard", standard, "chain_id", chainID, "flow_id", flowID)
+		return err
+	}
+	return nil
+}
+
+// attachFlowNotes 为流程列表附上备注（一次查询，失败只记录日志）
+func (r *flowRepository) attachFlowNotes(ctx context.Context, standard string, responses []types.CompoundFlowResponse) {
+	if len(responses) == 0 {
+		return
+	}
+	flowIDs := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		flowIDs = append(flowIDs, resp.FlowID)
+	}
+
+	var notes []types.FlowNote
+	if err := r.db.WithContext(ctx).
+		Where("standard = ? AND flow_id IN ?", standard, flowIDs).
+		Find(&notes).Error; err != nil {
+		logger.Error("Failed to query flow notes", err, "standard", standard, "flows", len(flowIDs))
+		return
+	}
+
+	byKey := make(map[string]*types.FlowNote, len(notes))
+	for i := range notes {
+		byKey[flowNoteKey(notes[i].ChainID, notes[i].ContractAddress, notes[i].FlowID)] = &notes[i]
+	}
+	for i := range responses {
+		responses[i].Note = byKey[flowNoteKey(responses[i].ChainID, responses[i].ContractAddress, responses[i].FlowID)]
+	}
+}
+
+// flowNoteKey 备注匹配键
+func flowNoteKey(chainID int, contractAddress, flowID string) string {
+	return fmt.Sprintf("%d:%s:%s", chainID, strings.ToLower(contractAddress), flowID)
+}
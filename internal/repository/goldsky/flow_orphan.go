@@ -0,0 +1,87 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// flowContractExistsCondition flow 所属合约仍有 timelock 记录（至少一个用户导入了该合约）
+func flowContractExistsCondition(flowTable, timelockTable string) string {
+	return fmt.Sprintf(`EXISTS (
+                SELECT 1 FROM %[2]s
+                WHERE chain_id = %[1]s.chain_id AND LOWER(contract_address) = LOWER(%[1]s.contract_address)
+            )`, flowTable, timelockTable)
+}
+
+// orphanedFlowCondition 合约已不在 timelock 表中（所有导入该合约的用户都已删除）的 flow
+func orphanedFlowCondition(flowTable, timelockTable string) string {
+	return "NOT " + flowContractExistsCondition(flowTable, timelockTable)
+}
+
+// GetOrphanedFlowContracts 按合约汇总热表中合约已被删除的 flow，按 flow 数量倒序
+func (r *flowRepository) GetOrphanedFlowContracts(ctx context.Context) ([]types.OrphanedFlowContract, error) {
+	sql := fmt.Sprintf(`
+        SELECT 'compound' AS standard, chain_id, LOWER(contract_address) AS contract_address,
+               COUNT(*) AS flow_count, MAX(updated_at) AS last_updated_at
+        FROM %[1]s
+        WHERE %[2]s
+        GROUP BY chain_id, LOWER(contract_address)
+        UNION ALL
+        SELECT 'openzeppelin' AS standard, chain_id, LOWER(contract_address) AS contract_address,
+               COUNT(*) AS flow_count, MAX(updated_at) AS last_updated_at
+        FROM %[3]s
+        WHERE %[4]s
+        GROUP BY chain_id, LOWER(contract_address)
+        ORDER BY flow_count DESC, chain_id ASC, contract_address ASC`,
+		compoundFlowsTable, orphanedFlowCondition(compoundFlowsTable, "compound_timelocks"),
+		openzeppelinFlowsTable, orphanedFlowCondition(openzeppelinFlowsTable, "openzeppelin_timelocks"))
+
+	var contracts []types.OrphanedFlowContract
+	if err := r.db.WithContext(ctx).Raw(sql).Scan(&contracts).Error; err != nil {
+		logger.Error("GetOrphanedFlowContracts error", err)
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// ArchiveOrphanedFlows 将 before 之前最后更新、合约已被删除的 flow 从热表移入归档表（单批最多 limit 条），返回移动数量
+// 不限状态：合约删除后状态检查与同步都不再覆盖这些 flow；用户重新导入合约时同步会跳过已归档的 flow
+func (r *flowRepository) ArchiveOrphanedFlows(ctx context.Context, standard string, before time.Time, limit int) (int64, error) {
+	var hotTable, archiveTable, timelockTable string
+	switch strings.ToLower(standard) {
+	case "compound":
+		hotTable, archiveTable, timelockTable = compoundFlowsTable, compoundFlowsArchiveTable, "compound_timelocks"
+	case "openzeppelin":
+		hotTable, archiveTable, timelockTable = openzeppelinFlowsTable, openzeppelinFlowsArchiveTable, "openzeppelin_timelocks"
+	default:
+		return 0, fmt.Errorf("invalid standard: %s", standard)
+	}
+
+	// 与 ArchiveTerminalFlows 相同：删除与插入在同一条语句中完成，归档表已存在的记录直接丢弃热表副本
+	sql := fmt.Sprintf(`
+        WITH moved AS (
+            DELETE FROM %[1]s
+            WHERE id IN (
+                SELECT id FROM %[1]s
+                WHERE updated_at < ? AND %[3]s
+                ORDER BY id
+                LIMIT ?
+            )
+            RETURNING *
+        )
+        INSERT INTO %[2]s SELECT * FROM moved
+        ON CONFLICT DO NOTHING
+        RETURNING id`, hotTable, archiveTable, orphanedFlowCondition(hotTable, timelockTable))
+
+	var ids []int64
+	if err := r.db.WithContext(ctx).Raw(sql, before, limit).Scan(&ids).Error; err != nil {
+		logger.Error("ArchiveOrphanedFlows error", err, "standard", standard, "before", before)
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
@@ -0,0 +1,52 @@
+package goldsky
+
+import (
+	"context"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// openzeppelinPredecessorExecutedSQL 前置操作已执行：热表或归档表中同链同合约的前置操作状态为 executed
+// 外层表为 openzeppelin_timelock_flows，两个占位符均为 "executed"
+const openzeppelinPredecessorExecutedSQL = `(EXISTS (
+	SELECT 1 FROM openzeppelin_timelock_flows p
+	WHERE p.chain_id = openzeppelin_timelock_flows.chain_id
+	AND LOWER(p.contract_address) = LOWER(openzeppelin_timelock_flows.contract_address)
+	AND LOWER(p.flow_id) = openzeppelin_timelock_flows.predecessor
+	AND p.status = ?
+) OR EXISTS (
+	SELECT 1 FROM openzeppelin_timelock_flows_archive p
+	WHERE p.chain_id = openzeppelin_timelock_flows.chain_id
+	AND LOWER(p.contract_address) = LOWER(openzeppelin_timelock_flows.contract_address)
+	AND LOWER(p.flow_id) = openzeppelin_timelock_flows.predecessor
+	AND p.status = ?
+))`
+
+// GetOpenzeppelinBlockingPredecessor 获取阻塞该操作的前置操作 id
+// 操作未终结、设置了前置操作且前置操作尚未执行时返回前置操作 id，否则返回空
+func (r *flowRepository) GetOpenzeppelinBlockingPredecessor(ctx context.Context, flow *types.OpenzeppelinTimelockFlowDB) (string, error) {
+	if flow == nil || flow.Predecessor == nil || flow.Status == "executed" || flow.Status == "cancelled" {
+		return "", nil
+	}
+	predecessor := strings.ToLower(*flow.Predecessor)
+
+	var executed bool
+	err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (
+		SELECT 1 FROM openzeppelin_timelock_flows
+		WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?) AND LOWER(flow_id) = ? AND status = ?
+	) OR EXISTS (
+		SELECT 1 FROM openzeppelin_timelock_flows_archive
+		WHERE chain_id = ? AND LOWER(contract_address) = LOWER(?) AND LOWER(flow_id) = ? AND status = ?
+	)`, flow.ChainID, flow.ContractAddress, predecessor, "executed",
+		flow.ChainID, flow.ContractAddress, predecessor, "executed").Scan(&executed).Error
+	if err != nil {
+		logger.Error("Failed to check openzeppelin predecessor", err, "flow_id", flow.FlowID, "chain_id", flow.ChainID, "predecessor", predecessor)
+		return "", err
+	}
+	if executed {
+		return "", nil
+	}
+	return predecessor, nil
+}
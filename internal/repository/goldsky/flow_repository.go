@@ -3,12 +3,15 @@ package goldsky
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"strings"
 	"time"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
 
+	"github.com/ethereum/go-ethereum/common"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // FlowRepository Goldsky Flow 数据库操作接口
@@ -19,19 +22,64 @@ type FlowRepository interface {
 	UpdateCompoundFlowStatus(ctx context.Context, flowID string, chainID int, contractAddress string, status string) error
 	GetCompoundFlowsNeedStatusUpdate(ctx context.Context, now time.Time, limit int) ([]types.CompoundTimelockFlowDB, error)
 	GetCompoundFlowsByContract(ctx context.Context, chainID int, contractAddress string) ([]types.CompoundTimelockFlowDB, error)
+	// 按用户（与 IsUserRelatedToFlow 一致）或合约范围获取需要更新状态的 flow，条件与 GetCompoundFlowsNeedStatusUpdate 一致
+	GetCompoundFlowsNeedStatusUpdateByScope(ctx context.Context, userAddress string, chainID int, contractAddress string, now time.Time, limit int) ([]types.CompoundTimelockFlowDB, error)
 	// 批量按 (chainID, contractAddresses) 拉现有 flow，返回 flowID -> flow 映射，避免 N+1
 	GetCompoundFlowsMapByContracts(ctx context.Context, chainID int, contractAddresses []string) (map[string]*types.CompoundTimelockFlowDB, error)
+	// 按合约宽限期补算/修正未终结 flow 的过期时间（数据源未提供或此前为估算值），返回更新行数
+	ApplyCompoundGracePeriod(ctx context.Context, chainID int, contractAddress string, gracePeriod int64, estimated bool) (int64, error)
 
 	// OpenZeppelin Flow 操作
 	CreateOrUpdateOpenzeppelinFlow(ctx context.Context, flow *types.OpenzeppelinTimelockFlowDB) error
 	GetOpenzeppelinFlowByID(ctx context.Context, flowID string, chainID int, contractAddress string) (*types.OpenzeppelinTimelockFlowDB, error)
 	UpdateOpenzeppelinFlowStatus(ctx context.Context, flowID string, chainID int, contractAddress string, status string) error
 	GetOpenzeppelinFlowsNeedStatusUpdate(ctx context.Context, now time.Time, limit int) ([]types.OpenzeppelinTimelockFlowDB, error)
+	// 获取阻塞该操作的前置操作 id（前置操作未执行时），没有阻塞时为空
+	GetOpenzeppelinBlockingPredecessor(ctx context.Context, flow *types.OpenzeppelinTimelockFlowDB) (string, error)
 	GetOpenzeppelinFlowsByContract(ctx context.Context, chainID int, contractAddress string) ([]types.OpenzeppelinTimelockFlowDB, error)
+	// 批量操作子调用（按 call_index 幂等写入）
+	UpsertOpenzeppelinFlowCalls(ctx context.Context, calls []types.OpenzeppelinFlowCallDB) error
+	GetOpenzeppelinFlowCalls(ctx context.Context, flowID string, chainID int, contractAddress string) ([]types.OpenzeppelinFlowCallDB, error)
 
 	// 用户相关查询（用于 API）
-	GetUserRelatedCompoundFlows(ctx context.Context, userAddress string, status *string, standard *string, offset int, limit int) ([]types.CompoundFlowResponse, int64, error)
+	// includeArchived 为 true 时同时查询归档表；数量统计始终包含归档的 flow
+	GetUserRelatedCompoundFlows(ctx context.Context, userAddress string, status *string, standard *string, initiator string, includeArchived bool, offset int, limit int) ([]types.CompoundFlowResponse, int64, error)
 	GetUserRelatedCompoundFlowsCount(ctx context.Context, userAddress string, standard *string) (*types.FlowStatusCount, error)
+	// 判断用户是否有权查看某个 flow（发起人或合约相关角色）
+	IsUserRelatedToFlow(ctx context.Context, userAddress string, standard string, chainID int, contractAddress string, flowID string) (bool, error)
+	// 获取用户相关的待处理 flow：ready，或 waiting 且 eta 不晚于 etaBefore，按 eta 升序
+	GetUserActionableCompoundFlows(ctx context.Context, userAddress string, etaBefore time.Time, limit int) ([]types.CompoundTimelockFlowDB, error)
+	GetUserActionableOpenzeppelinFlows(ctx context.Context, userAddress string, etaBefore time.Time, limit int) ([]types.OpenzeppelinTimelockFlowDB, error)
+	// 获取用户相关、eta 在 [from, to) 内的 waiting/ready flow，按 eta 在 timezone 下的日期分桶，按 eta 升序
+	GetUserFlowCalendar(ctx context.Context, userAddress string, from, to time.Time, timezone string, limit int) ([]types.FlowCalendarEntry, error)
+
+	// 管理员查询全部流程（不关联用户与合约的关系），合并两种标准并按创建时间倒序分页
+	ListAllFlows(ctx context.Context, filter types.AdminFlowFilter, offset int, limit int) ([]types.AdminFlowResponse, int64, error)
+
+	// 状态历史
+	GetFlowStatusHistory(ctx context.Context, standard string, chainID int, contractAddress string, flowID string) ([]types.FlowStatusHistory, error)
+	// 管理员手动设置 flow 状态：仅当当前状态仍为 from 时更新，并写入带操作人与原因的状态历史；返回是否更新
+	SetFlowStatusManually(ctx context.Context, standard string, chainID int, contractAddress, flowID, from, to, changedBy, reason string) (bool, error)
+
+	// 流程备注（每个流程一条，相关用户可设置）
+	GetFlowNote(ctx context.Context, standard string, chainID int, contractAddress, flowID string) (*types.FlowNote, error)
+	UpsertFlowNote(ctx context.Context, note *types.FlowNote) error
+	DeleteFlowNote(ctx context.Context, standard string, chainID int, contractAddress, flowID string) error
+
+	// 可疑流程标记（同一流程可多次标记）
+	CreateFlowFlag(ctx context.Context, flag *types.FlowFlag) error
+	MarkFlowFlagNotified(ctx context.Context, id int64) error
+	ListFlowFlags(ctx context.Context, standard string, chainID int, contractAddress, flowID string, offset, limit int) ([]types.FlowFlag, int64, error)
+	// 判断用户是否与合约相关（合约相关角色或合约下任一 flow 的发起人）
+	IsUserRelatedToContract(ctx context.Context, userAddress string, standard string, chainID int, contractAddress string) (bool, error)
+
+	// 归档
+	ArchiveTerminalFlows(ctx context.Context, standard string, before time.Time, limit int) (map[string]int64, error)
+	GetArchivedCompoundFlowKeys(ctx context.Context, chainID int, contractAddresses []string) (map[string]bool, error)
+
+	// 合约已被删除的 flow
+	GetOrphanedFlowContracts(ctx context.Context) ([]types.OrphanedFlowContract, error)
+	ArchiveOrphanedFlows(ctx context.Context, standard string, before time.Time, limit int) (int64, error)
 }
 
 type flowRepository struct {
@@ -53,15 +101,34 @@ func (r *flowRepository) CreateOrUpdateCompoundFlow(ctx context.Context, flow *t
 
 		if err == gorm.ErrRecordNotFound {
 			// 创建新记录
-			return tx.Create(flow).Error
+			if err := tx.Create(flow).Error; err != nil {
+				return err
+			}
+			return recordFlowStatusChange(tx, flow.FlowID, "compound", flow.ChainID, flow.ContractAddress, "", flow.Status)
 		} else if err != nil {
 			return err
 		}
 
-		// 更新现有记录
+		// 更新现有记录（数据源未提供取消者地址时保留已有值）
 		flow.ID = existing.ID
 		flow.CreatedAt = existing.CreatedAt
-		return tx.Save(flow).Error
+		if flow.CancellerAddress == nil {
+			flow.CancellerAddress = existing.CancellerAddress
+		}
+		// 数据源未提供过期时间时保留已有的估算值，等待合约刷新修正
+		if flow.ExpiredAt == nil && existing.GracePeriodEstimated {
+			flow.GracePeriod = existing.GracePeriod
+			flow.ExpiredAt = existing.ExpiredAt
+			flow.GracePeriodEstimated = true
+		}
+		// 数据源未提供执行交易状态时，同一笔执行交易保留已有值
+		if flow.ExecuteTxStatus == nil && sameTxHash(flow.ExecuteTxHash, existing.ExecuteTxHash) {
+			flow.ExecuteTxStatus = existing.ExecuteTxStatus
+		}
+		if err := tx.Save(flow).Error; err != nil {
+			return err
+		}
+		return recordFlowStatusChange(tx, flow.FlowID, "compound", flow.ChainID, flow.ContractAddress, existing.Status, flow.Status)
 	})
 }
 
@@ -86,18 +153,34 @@ func (r *flowRepository) GetCompoundFlowByID(ctx context.Context, flowID string,
 
 // UpdateCompoundFlowStatus 更新 Compound Flow 状态
 func (r *flowRepository) UpdateCompoundFlowStatus(ctx context.Context, flowID string, chainID int, contractAddress string, status string) error {
-	result := r.db.WithContext(ctx).
-		Model(&types.CompoundTimelockFlowDB{}).
-		Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
-			flowID, chainID, contractAddress).
-		Updates(map[string]interface{}{
-			"status":     status,
-			"updated_at": time.Now(),
-		})
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing types.CompoundTimelockFlowDB
+		err := tx.Select("status").
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
+				flowID, chainID, contractAddress).
+			First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&types.CompoundTimelockFlowDB{}).
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
+				flowID, chainID, contractAddress).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
 
-	if result.Error != nil {
-		logger.Error("Failed to update compound flow status", result.Error, "flow_id", flowID, "status", status)
-		return result.Error
+		return recordFlowStatusChange(tx, flowID, "compound", chainID, contractAddress, existing.Status, status)
+	})
+
+	if err != nil {
+		logger.Error("Failed to update compound flow status", err, "flow_id", flowID, "status", status)
+		return err
 	}
 
 	return nil
@@ -136,15 +219,31 @@ func (r *flowRepository) CreateOrUpdateOpenzeppelinFlow(ctx context.Context, flo
 
 		if err == gorm.ErrRecordNotFound {
 			// 创建新记录
-			return tx.Create(flow).Error
+			if err := tx.Create(flow).Error; err != nil {
+				return err
+			}
+			return recordFlowStatusChange(tx, flow.FlowID, "openzeppelin", flow.ChainID, flow.ContractAddress, "", flow.Status)
 		} else if err != nil {
 			return err
 		}
 
-		// 更新现有记录
+		// 更新现有记录（数据源未提供取消者地址时保留已有值）
 		flow.ID = existing.ID
 		flow.CreatedAt = existing.CreatedAt
-		return tx.Save(flow).Error
+		if flow.CancellerAddress == nil {
+			flow.CancellerAddress = existing.CancellerAddress
+		}
+		if flow.Predecessor == nil {
+			flow.Predecessor = existing.Predecessor
+		}
+		// 数据源未提供执行交易状态时，同一笔执行交易保留已有值
+		if flow.ExecuteTxStatus == nil && sameTxHash(flow.ExecuteTxHash, existing.ExecuteTxHash) {
+			flow.ExecuteTxStatus = existing.ExecuteTxStatus
+		}
+		if err := tx.Save(flow).Error; err != nil {
+			return err
+		}
+		return recordFlowStatusChange(tx, flow.FlowID, "openzeppelin", flow.ChainID, flow.ContractAddress, existing.Status, flow.Status)
 	})
 }
 
@@ -169,32 +268,84 @@ func (r *flowRepository) GetOpenzeppelinFlowByID(ctx context.Context, flowID str
 
 // UpdateOpenzeppelinFlowStatus 更新 OpenZeppelin Flow 状态
 func (r *flowRepository) UpdateOpenzeppelinFlowStatus(ctx context.Context, flowID string, chainID int, contractAddress string, status string) error {
-	result := r.db.WithContext(ctx).
-		Model(&types.OpenzeppelinTimelockFlowDB{}).
-		Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
-			flowID, chainID, contractAddress).
-		Updates(map[string]interface{}{
-			"status":     status,
-			"updated_at": time.Now(),
-		})
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing types.OpenzeppelinTimelockFlowDB
+		err := tx.Select("status").
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
+				flowID, chainID, contractAddress).
+			First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&types.OpenzeppelinTimelockFlowDB{}).
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
+				flowID, chainID, contractAddress).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		return recordFlowStatusChange(tx, flowID, "openzeppelin", chainID, contractAddress, existing.Status, status)
+	})
+
+	if err != nil {
+		logger.Error("Failed to update openzeppelin flow status", err, "flow_id", flowID, "status", status)
+		return err
+	}
+
+	return nil
+}
 
-	if result.Error != nil {
-		logger.Error("Failed to update openzeppelin flow status", result.Error, "flow_id", flowID, "status", status)
-		return result.Error
+// UpsertOpenzeppelinFlowCalls 写入 OpenZeppelin 操作子调用，同一 (flow, call_index) 重复写入时覆盖
+func (r *flowRepository) UpsertOpenzeppelinFlowCalls(ctx context.Context, calls []types.OpenzeppelinFlowCallDB) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	for i := range calls {
+		calls[i].ContractAddress = strings.ToLower(calls[i].ContractAddress)
 	}
 
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "flow_id"}, {Name: "chain_id"}, {Name: "contract_address"}, {Name: "call_index"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_address", "value", "call_data"}),
+		}).
+		Create(&calls).Error
+	if err != nil {
+		logger.Error("Failed to upsert openzeppelin flow calls", err, "flow_id", calls[0].FlowID, "chain_id", calls[0].ChainID, "count", len(calls))
+		return err
+	}
 	return nil
 }
 
+// GetOpenzeppelinFlowCalls 获取 OpenZeppelin 操作的全部子调用，按 call_index 升序
+func (r *flowRepository) GetOpenzeppelinFlowCalls(ctx context.Context, flowID string, chainID int, contractAddress string) ([]types.OpenzeppelinFlowCallDB, error) {
+	var calls []types.OpenzeppelinFlowCallDB
+	err := r.db.WithContext(ctx).
+		Where("flow_id = ? AND chain_id = ? AND contract_address = ?", flowID, chainID, strings.ToLower(contractAddress)).
+		Order("call_index ASC").
+		Find(&calls).Error
+	if err != nil {
+		logger.Error("Failed to get openzeppelin flow calls", err, "flow_id", flowID, "chain_id", chainID)
+		return nil, err
+	}
+	return calls, nil
+}
+
 // GetOpenzeppelinFlowsNeedStatusUpdate 获取需要更新状态的 OpenZeppelin Flows
-// 返回：waiting -> ready (eta <= now)
+// 返回：waiting -> ready (eta <= now，且没有前置操作或前置操作已执行)
 func (r *flowRepository) GetOpenzeppelinFlowsNeedStatusUpdate(ctx context.Context, now time.Time, limit int) ([]types.OpenzeppelinTimelockFlowDB, error) {
 	var flows []types.OpenzeppelinTimelockFlowDB
 
 	query := r.db.WithContext(ctx).Where(
 		"status = ? AND eta IS NOT NULL AND eta <= ?",
 		"waiting", now,
-	).Order("eta ASC")
+	).Where("(predecessor IS NULL OR "+openzeppelinPredecessorExecutedSQL+")", "executed", "executed").Order("eta ASC")
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -210,14 +361,14 @@ func (r *flowRepository) GetOpenzeppelinFlowsNeedStatusUpdate(ctx context.Contex
 }
 
 // GetUserRelatedCompoundFlows 获取用户相关的 Compound Flows（用于 API）
-func (r *flowRepository) GetUserRelatedCompoundFlows(ctx context.Context, userAddress string, status *string, standard *string, offset int, limit int) ([]types.CompoundFlowResponse, int64, error) {
+func (r *flowRepository) GetUserRelatedCompoundFlows(ctx context.Context, userAddress string, status *string, standard *string, initiator string, includeArchived bool, offset int, limit int) ([]types.CompoundFlowResponse, int64, error) {
 	normalizedUserAddress := strings.ToLower(userAddress)
 
 	var responses []types.CompoundFlowResponse
 	var total int64
 
 	// 查询 Compound Flows
-	compoundFlows, compoundTotal, err := r.queryCompoundFlowsWithPermission(ctx, normalizedUserAddress, status, offset, limit)
+	compoundFlows, compoundTotal, err := r.queryCompoundFlowsWithPermission(ctx, normalizedUserAddress, status, initiator, includeArchived, offset, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -228,7 +379,7 @@ func (r *flowRepository) GetUserRelatedCompoundFlows(ctx context.Context, userAd
 }
 
 // queryCompoundFlowsWithPermission 使用子查询方式查询用户有权限的 Compound Flows
-func (r *flowRepository) queryCompoundFlowsWithPermission(ctx context.Context, normalizedUserAddress string, status *string, offset int, limit int) ([]types.CompoundFlowResponse, int64, error) {
+func (r *flowRepository) queryCompoundFlowsWithPermission(ctx context.Context, normalizedUserAddress string, status *string, initiator string, includeArchived bool, offset int, limit int) ([]types.CompoundFlowResponse, int64, error) {
 	var flows []types.CompoundTimelockFlowDB
 	var total int64
 
@@ -263,8 +414,17 @@ func (r *flowRepository) queryCompoundFlowsWithPermission(ctx context.Context, n
 		args = append(args, *status)
 	}
 
+	// 发起人过滤
+	if initiator != "" {
+		condition, conditionArgs := initiatorAddressCondition("initiator_address", initiator)
+		finalWhere += " AND " + condition
+		args = append(args, conditionArgs...)
+	}
+
+	source := compoundFlowsSource(includeArchived)
+
 	// 计算总数
-	if err := r.db.WithContext(ctx).Model(&types.CompoundTimelockFlowDB{}).
+	if err := r.db.WithContext(ctx).Table(source).
 		Where(finalWhere, args...).
 		Count(&total).Error; err != nil {
 		logger.Error("Failed to count compound flows with permission", err, "user", normalizedUserAddress)
@@ -272,7 +432,7 @@ func (r *flowRepository) queryCompoundFlowsWithPermission(ctx context.Context, n
 	}
 
 	// 分页查询
-	if err := r.db.WithContext(ctx).
+	if err := r.db.WithContext(ctx).Table(source).
 		Where(finalWhere, args...).
 		Order("created_at DESC").
 		Offset(offset).
@@ -287,10 +447,74 @@ func (r *flowRepository) queryCompoundFlowsWithPermission(ctx context.Context, n
 	for i, flow := range flows {
 		responses[i] = r.convertCompoundFlowToResponse(ctx, flow)
 	}
+	r.attachRelatedContracts(ctx, normalizedUserAddress, responses)
+	r.attachFlowNotes(ctx, "compound", responses)
+	r.attachDecodedCalls(ctx, normalizedUserAddress, "compound", responses)
 
 	return responses, total, nil
 }
 
+// initiatorAddressCondition 按发起人地址过滤（不区分大小写）
+// 不对列做 LOWER，而是同时匹配小写与 EIP-55 校验和两种写法，从而命中 idx_*_flows_initiator 索引
+func initiatorAddressCondition(column, initiator string) (string, []interface{}) {
+	lower := strings.ToLower(initiator)
+	return column + " IN (?, ?)", []interface{}{lower, common.HexToAddress(lower).Hex()}
+}
+
+// attachRelatedContracts 目标地址是用户管理的其他 timelock 时附上该合约信息（一次查询，失败只记录日志）
+func (r *flowRepository) attachRelatedContracts(ctx context.Context, normalizedUserAddress string, responses []types.CompoundFlowResponse) {
+	targets := make([]string, 0, len(responses))
+	seen := make(map[string]bool)
+	for _, resp := range responses {
+		if resp.TargetAddress == nil {
+			continue
+		}
+		target := strings.ToLower(*resp.TargetAddress)
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	var related []types.RelatedContract
+	likePattern := "%" + normalizedUserAddress + "%"
+	if err := r.db.WithContext(ctx).Raw(`
+        SELECT 'compound' AS standard, id, chain_id, LOWER(contract_address) AS contract_address, remark
+        FROM compound_timelocks
+        WHERE LOWER(contract_address) IN ? AND status != 'deleted'
+          AND (LOWER(creator_address) = ? OR LOWER(admin) = ? OR LOWER(pending_admin) = ?)
+        UNION ALL
+        SELECT 'openzeppelin' AS standard, id, chain_id, LOWER(contract_address) AS contract_address, remark
+        FROM openzeppelin_timelocks
+        WHERE LOWER(contract_address) IN ? AND status != 'deleted'
+          AND (LOWER(creator_address) = ? OR LOWER(proposers) LIKE ? OR LOWER(executors) LIKE ?)`,
+		targets, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress,
+		targets, normalizedUserAddress, likePattern, likePattern,
+	).Scan(&related).Error; err != nil {
+		logger.Error("Failed to query related contracts", err, "user", normalizedUserAddress, "targets", len(targets))
+		return
+	}
+
+	byKey := make(map[string][]types.RelatedContract)
+	for _, rc := range related {
+		key := fmt.Sprintf("%d:%s", rc.ChainID, rc.ContractAddress)
+		byKey[key] = append(byKey[key], rc)
+	}
+	for i := range responses {
+		if responses[i].TargetAddress == nil {
+			continue
+		}
+		target := strings.ToLower(*responses[i].TargetAddress)
+		if target == strings.ToLower(responses[i].ContractAddress) {
+			continue // 以自身为目标的流程不属于“其他合约”
+		}
+		responses[i].RelatedContracts = byKey[fmt.Sprintf("%d:%s", responses[i].ChainID, target)]
+	}
+}
+
 // convertCompoundFlowToResponse 转换 Compound Flow 为响应格式
 func (r *flowRepository) convertCompoundFlowToResponse(ctx context.Context, flow types.CompoundTimelockFlowDB) types.CompoundFlowResponse {
 	// 获取合约备注
@@ -315,6 +539,7 @@ func (r *flowRepository) convertCompoundFlowToResponse(ctx context.Context, flow
 		ExecuteTxHash:     flow.ExecuteTxHash,
 		CancelTxHash:      flow.CancelTxHash,
 		InitiatorAddress:  flow.InitiatorAddress,
+		CancellerAddress:  flow.CancellerAddress,
 		TargetAddress:     flow.TargetAddress,
 		FunctionSignature: flow.FunctionSignature,
 		CallDataHex:       &callDataHex,
@@ -325,6 +550,9 @@ func (r *flowRepository) convertCompoundFlowToResponse(ctx context.Context, flow
 		CancelledAt:       flow.CancelledAt,
 		CreatedAt:         flow.CreatedAt,
 		UpdatedAt:         flow.UpdatedAt,
+
+		ExpiredAtEstimated: flow.GracePeriodEstimated,
+		ExecuteTxStatus:    flow.ExecuteTxStatus,
 	}
 }
 
@@ -430,15 +658,18 @@ func (r *flowRepository) countCompoundFlowsWithPermission(ctx context.Context, n
 	// 添加过滤条件：确保对应的合约记录仍然存在于compound_timelocks表中
 	finalWhere += " AND EXISTS (SELECT 1 FROM compound_timelocks WHERE chain_id = compound_timelock_flows.chain_id AND LOWER(contract_address) = LOWER(compound_timelock_flows.contract_address))"
 
+	// 数量统计包含已归档的 flow
+	source := compoundFlowsSource(true)
+
 	// 总数
-	if err := r.db.WithContext(ctx).Model(&types.CompoundTimelockFlowDB{}).
+	if err := r.db.WithContext(ctx).Table(source).
 		Where(finalWhere, args...).
 		Count(&count.Count).Error; err != nil {
 		return nil, err
 	}
 
 	// 按状态统计
-	rows, err := r.db.WithContext(ctx).Model(&types.CompoundTimelockFlowDB{}).
+	rows, err := r.db.WithContext(ctx).Table(source).
 		Select("status, COUNT(*) as count").
 		Where(finalWhere, args...).
 		Group("status").
@@ -471,3 +702,183 @@ func (r *flowRepository) countCompoundFlowsWithPermission(ctx context.Context, n
 
 	return count, nil
 }
+
+// sameTxHash 两个交易哈希均存在且相同（不区分大小写）
+func sameTxHash(a, b *string) bool {
+	return a != nil && b != nil && strings.EqualFold(*a, *b)
+}
+
+// recordFlowStatusChange 在同一事务中记录 flow 状态变更，状态未变化时不记录
+func recordFlowStatusChange(tx *gorm.DB, flowID string, standard string, chainID int, contractAddress string, from string, to string) error {
+	if from == to {
+		return nil
+	}
+	return tx.Create(&types.FlowStatusHistory{
+		FlowID:           flowID,
+		TimelockStandard: standard,
+		ChainID:          chainID,
+		ContractAddress:  strings.ToLower(contractAddress),
+		StatusFrom:       from,
+		StatusTo:         to,
+		ChangedAt:        time.Now(),
+	}).Error
+}
+
+// GetFlowStatusHistory 获取 flow 的状态变更历史（按时间升序）
+func (r *flowRepository) GetFlowStatusHistory(ctx context.Context, standard string, chainID int, contractAddress string, flowID string) ([]types.FlowStatusHistory, error) {
+	var history []types.FlowStatusHistory
+	err := r.db.WithContext(ctx).
+		Where("flow_id = ? AND timelock_standard = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)",
+			flowID, standard, chainID, contractAddress).
+		Order("changed_at ASC, id ASC").
+		Find(&history).Error
+	if err != nil {
+		logger.Error("Failed to get flow status history", err, "flow_id", flowID, "chain_id", chainID)
+		return nil, err
+	}
+	return history, nil
+}
+
+// SetFlowStatusManually 管理员手动设置 flow 状态（条件更新，避免覆盖并发的状态推进）
+func (r *flowRepository) SetFlowStatusManually(ctx context.Context, standard string, chainID int, contractAddress, flowID, from, to, changedBy, reason string) (bool, error) {
+	var model interface{}
+	switch strings.ToLower(standard) {
+	case "compound":
+		model = &types.CompoundTimelockFlowDB{}
+	case "openzeppelin":
+		model = &types.OpenzeppelinTimelockFlowDB{}
+	default:
+		return false, fmt.Errorf("invalid standard: %s", standard)
+	}
+
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model).
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?) AND status = ?",
+				flowID, chainID, contractAddress, from).
+			Updates(map[string]interface{}{
+				"status":     to,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+
+		lowerChangedBy := strings.ToLower(changedBy)
+		return tx.Create(&types.FlowStatusHistory{
+			FlowID:           flowID,
+			TimelockStandard: strings.ToLower(standard),
+			ChainID:          chainID,
+			ContractAddress:  strings.ToLower(contractAddress),
+			StatusFrom:       from,
+			StatusTo:         to,
+			ChangedAt:        time.Now(),
+			ChangedBy:        &lowerChangedBy,
+			Reason:           &reason,
+		}).Error
+	})
+	if err != nil {
+		logger.Error("Failed to set flow status manually", err, "standard", standard, "flow_id", flowID, "from", from, "to", to)
+		return false, err
+	}
+	return updated, nil
+}
+
+// GetUserActionableCompoundFlows 获取用户相关的待处理 Compound flow（权限判断与 IsUserRelatedToFlow 一致）
+func (r *flowRepository) GetUserActionableCompoundFlows(ctx context.Context, userAddress string, etaBefore time.Time, limit int) ([]types.CompoundTimelockFlowDB, error) {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	var flows []types.CompoundTimelockFlowDB
+	err := r.db.WithContext(ctx).
+		Where("(status = ? OR (status = ? AND eta <= ?))", "ready", "waiting", etaBefore).
+		Where(`(LOWER(initiator_address) = ? OR EXISTS (
+			SELECT 1 FROM compound_timelocks
+			WHERE chain_id = compound_timelock_flows.chain_id
+			AND LOWER(contract_address) = LOWER(compound_timelock_flows.contract_address)
+			AND (LOWER(admin) = ? OR LOWER(pending_admin) = ? OR LOWER(creator_address) = ?)
+			AND status = ?
+		))`, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, "active").
+		Where(flowContractExistsCondition(compoundFlowsTable, "compound_timelocks")). // 与流程列表一致，隐藏合约已删除的 flow
+		Order("eta ASC NULLS LAST, id ASC").
+		Limit(limit).
+		Find(&flows).Error
+	if err != nil {
+		logger.Error("Failed to get actionable compound flows", err, "user", normalizedUserAddress)
+		return nil, err
+	}
+	return flows, nil
+}
+
+// GetUserActionableOpenzeppelinFlows 获取用户相关的待处理 OpenZeppelin flow（权限判断与 IsUserRelatedToFlow 一致）
+func (r *flowRepository) GetUserActionableOpenzeppelinFlows(ctx context.Context, userAddress string, etaBefore time.Time, limit int) ([]types.OpenzeppelinTimelockFlowDB, error) {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	likePattern := "%" + normalizedUserAddress + "%"
+	var flows []types.OpenzeppelinTimelockFlowDB
+	err := r.db.WithContext(ctx).
+		Where("(status = ? OR (status = ? AND eta <= ?))", "ready", "waiting", etaBefore).
+		Where(`(LOWER(initiator_address) = ? OR EXISTS (
+			SELECT 1 FROM openzeppelin_timelocks
+			WHERE chain_id = openzeppelin_timelock_flows.chain_id
+			AND LOWER(contract_address) = LOWER(openzeppelin_timelock_flows.contract_address)
+			AND (LOWER(creator_address) = ? OR LOWER(proposers) LIKE ? OR LOWER(executors) LIKE ?)
+			AND status = ?
+		))`, normalizedUserAddress, normalizedUserAddress, likePattern, likePattern, "active").
+		Where(flowContractExistsCondition(openzeppelinFlowsTable, "openzeppelin_timelocks")).
+		Order("eta ASC NULLS LAST, id ASC").
+		Limit(limit).
+		Find(&flows).Error
+	if err != nil {
+		logger.Error("Failed to get actionable openzeppelin flows", err, "user", normalizedUserAddress)
+		return nil, err
+	}
+	return flows, nil
+}
+
+// IsUserRelatedToFlow 判断用户是否与 flow 相关
+// compound：发起人，或合约的 admin、pending_admin、creator
+// openzeppelin：发起人，或合约的 creator、proposers、executors
+func (r *flowRepository) IsUserRelatedToFlow(ctx context.Context, userAddress string, standard string, chainID int, contractAddress string, flowID string) (bool, error) {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	var count int64
+
+	switch standard {
+	case "compound":
+		err := r.db.WithContext(ctx).Model(&types.CompoundTimelockFlowDB{}).
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)", flowID, chainID, contractAddress).
+			Where(`(LOWER(initiator_address) = ? OR EXISTS (
+				SELECT 1 FROM compound_timelocks
+				WHERE chain_id = compound_timelock_flows.chain_id
+				AND LOWER(contract_address) = LOWER(compound_timelock_flows.contract_address)
+				AND (LOWER(admin) = ? OR LOWER(pending_admin) = ? OR LOWER(creator_address) = ?)
+				AND status = ?
+			))`, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, "active").
+			Count(&count).Error
+		if err != nil {
+			logger.Error("Failed to check compound flow relation", err, "flow_id", flowID, "user", normalizedUserAddress)
+			return false, err
+		}
+	case "openzeppelin":
+		likePattern := "%" + normalizedUserAddress + "%"
+		err := r.db.WithContext(ctx).Model(&types.OpenzeppelinTimelockFlowDB{}).
+			Where("flow_id = ? AND chain_id = ? AND LOWER(contract_address) = LOWER(?)", flowID, chainID, contractAddress).
+			Where(`(LOWER(initiator_address) = ? OR EXISTS (
+				SELECT 1 FROM openzeppelin_timelocks
+				WHERE chain_id = openzeppelin_timelock_flows.chain_id
+				AND LOWER(contract_address) = LOWER(openzeppelin_timelock_flows.contract_address)
+				AND (LOWER(creator_address) = ? OR LOWER(proposers) LIKE ? OR LOWER(executors) LIKE ?)
+				AND status = ?
+			))`, normalizedUserAddress, normalizedUserAddress, likePattern, likePattern, "active").
+			Count(&count).Error
+		if err != nil {
+			logger.Error("Failed to check openzeppelin flow relation", err, "flow_id", flowID, "user", normalizedUserAddress)
+			return false, err
+		}
+	default:
+		return false, nil
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,71 @@
+package goldsky
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RPCFallbackCursorRepository 无 subgraph 链的 RPC 日志扫描游标
+type RPCFallbackCursorRepository interface {
+	// GetRPCFallbackCursors 获取指定链所有合约的扫描游标（合约地址小写 -> 已扫描到的区块）
+	GetRPCFallbackCursors(ctx context.Context, chainID int) (map[string]uint64, error)
+	// SaveRPCFallbackCursors 将一组合约的扫描游标设置为 lastBlock
+	SaveRPCFallbackCursors(ctx context.Context, chainID int, contractAddresses []string, lastBlock uint64) error
+}
+
+type rpcFallbackCursorRepository struct {
+	db *gorm.DB
+}
+
+// NewRPCFallbackCursorRepository 创建 RPC 日志扫描游标仓库
+func NewRPCFallbackCursorRepository(db *gorm.DB) RPCFallbackCursorRepository {
+	return &rpcFallbackCursorRepository{db: db}
+}
+
+// GetRPCFallbackCursors 获取指定链所有合约的扫描游标
+func (r *rpcFallbackCursorRepository) GetRPCFallbackCursors(ctx context.Context, chainID int) (map[string]uint64, error) {
+	var cursors []types.RPCFallbackCursor
+	if err := r.db.WithContext(ctx).Where("chain_id = ?", chainID).Find(&cursors).Error; err != nil {
+		logger.Error("GetRPCFallbackCursors error", err, "chain_id", chainID)
+		return nil, err
+	}
+	result := make(map[string]uint64, len(cursors))
+	for _, cursor := range cursors {
+		if cursor.LastBlock >= 0 {
+			result[strings.ToLower(cursor.ContractAddress)] = uint64(cursor.LastBlock)
+		}
+	}
+	return result, nil
+}
+
+// SaveRPCFallbackCursors 按 (chain_id, contract_address) upsert 扫描游标
+func (r *rpcFallbackCursorRepository) SaveRPCFallbackCursors(ctx context.Context, chainID int, contractAddresses []string, lastBlock uint64) error {
+	if len(contractAddresses) == 0 {
+		return nil
+	}
+	now := time.Now()
+	cursors := make([]types.RPCFallbackCursor, len(contractAddresses))
+	for i, address := range contractAddresses {
+		cursors[i] = types.RPCFallbackCursor{
+			ChainID:         chainID,
+			ContractAddress: strings.ToLower(address),
+			LastBlock:       int64(lastBlock),
+			UpdatedAt:       now,
+		}
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "contract_address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_block", "updated_at"}),
+	}).Create(&cursors).Error
+	if err != nil {
+		logger.Error("SaveRPCFallbackCursors error", err, "chain_id", chainID, "contracts", len(contractAddresses), "last_block", lastBlock)
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package goldsky
+
+import (
+	"context"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEventRepository Goldsky webhook 事件去重记录
+type WebhookEventRepository interface {
+	// ClaimWebhookEvent 登记事件并取得处理权：事件首次出现、上次处理失败、上次处理超过 staleBefore 仍未完成，
+	// 或暂存等待确认超过 deferredStaleBefore（进程重启导致内存中的暂存丢失）时返回 true；
+	// 已处理成功、正在处理中或暂存等待确认中的重复投递返回 false
+	ClaimWebhookEvent(ctx context.Context, event *types.GoldskyWebhookEvent, staleBefore, deferredStaleBefore time.Time) (bool, error)
+	// MarkWebhookEventDeferred 将事件标记为暂存等待确认
+	MarkWebhookEventDeferred(ctx context.Context, eventKey string) error
+	// FinishWebhookEvent 记录事件处理结果，errMsg 为空表示处理成功
+	FinishWebhookEvent(ctx context.Context, eventKey string, errMsg *string) error
+	// SaveWebhookDeadLetter 记录未通过结构校验的 webhook 请求
+	SaveWebhookDeadLetter(ctx context.Context, letter *types.GoldskyWebhookDeadLetter) error
+}
+
+type webhookEventRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookEventRepository 创建 webhook 事件仓库
+func NewWebhookEventRepository(db *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepository{db: db}
+}
+
+// ClaimWebhookEvent 依赖 event_key 唯一索引原子地登记事件，并发的重复投递只有一个能取得处理权
+func (r *webhookEventRepository) ClaimWebhookEvent(ctx context.Context, event *types.GoldskyWebhookEvent, staleBefore, deferredStaleBefore time.Time) (bool, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO goldsky_webhook_events (event_key, chain_id, standard, event_type, tx_hash, status, attempts, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, NOW(), NOW())
+		ON CONFLICT (event_key) DO UPDATE
+		SET status = EXCLUDED.status,
+		    attempts = goldsky_webhook_events.attempts + 1,
+		    error_message = NULL,
+		    updated_at = NOW()
+		WHERE goldsky_webhook_events.status = ?
+		   OR (goldsky_webhook_events.status = ? AND goldsky_webhook_events.updated_at < ?)
+		   OR (goldsky_webhook_events.status = ? AND goldsky_webhook_events.updated_at < ?)
+		RETURNING id`,
+		event.EventKey, event.ChainID, event.Standard, event.EventType, event.TxHash, types.WebhookEventStatusProcessing,
+		types.WebhookEventStatusFailed, types.WebhookEventStatusProcessing, staleBefore,
+		types.WebhookEventStatusDeferred, deferredStaleBefore,
+	).Scan(&ids).Error
+	if err != nil {
+		logger.Error("ClaimWebhookEvent error", err, "event_key", event.EventKey)
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+// FinishWebhookEvent 记录事件处理结果
+func (r *webhookEventRepository) FinishWebhookEvent(ctx context.Context, eventKey string, errMsg *string) error {
+	status := types.WebhookEventStatusProcessed
+	if errMsg != nil {
+		status = types.WebhookEventStatusFailed
+	}
+	err := r.db.WithContext(ctx).Model(&types.GoldskyWebhookEvent{}).
+		Where("event_key = ?", eventKey).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"error_message": errMsg,
+			"updated_at":    time.Now(),
+		}).Error
+	if err != nil {
+		logger.Error("FinishWebhookEvent error", err, "event_key", eventKey, "status", status)
+	}
+	return err
+}
+
+// MarkWebhookEventDeferred 将事件标记为暂存等待确认，updated_at 作为暂存开始时间
+func (r *webhookEventRepository) MarkWebhookEventDeferred(ctx context.Context, eventKey string) error {
+	err := r.db.WithContext(ctx).Model(&types.GoldskyWebhookEvent{}).
+		Where("event_key = ?", eventKey).
+		Updates(map[string]interface{}{
+			"status":     types.WebhookEventStatusDeferred,
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		logger.Error("MarkWebhookEventDeferred error", err, "event_key", eventKey)
+	}
+	return err
+}
+
+// SaveWebhookDeadLetter 记录未通过结构校验的 webhook 请求
+func (r *webhookEventRepository) SaveWebhookDeadLetter(ctx context.Context, letter *types.GoldskyWebhookDeadLetter) error {
+	if err := r.db.WithContext(ctx).Create(letter).Error; err != nil {
+		logger.Error("SaveWebhookDeadLetter error", err, "webhook_id", letter.WebhookID, "remote_addr", letter.RemoteAddr)
+		return err
+	}
+	return nil
+}
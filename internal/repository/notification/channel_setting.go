@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm/clause"
+)
+
+// GetNotificationChannelSettings 获取用户已设置过的渠道开关，未设置的渠道不返回（视为开启）
+func (r *notificationRepository) GetNotificationChannelSettings(ctx context.Context, userAddress string) ([]types.NotificationChannelSetting, error) {
+	var settings []types.NotificationChannelSetting
+	if err := r.db.WithContext(ctx).
+		Where("user_address = ?", strings.ToLower(userAddress)).
+		Find(&settings).Error; err != nil {
+		logger.Error("GetNotificationChannelSettings error", err, "user_address", userAddress)
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpsertNotificationChannelSetting 创建或更新用户的渠道开关
+func (r *notificationRepository) UpsertNotificationChannelSetting(ctx context.Context, setting *types.NotificationChannelSetting) error {
+	setting.UserAddress = strings.ToLower(setting.UserAddress)
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_address"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(setting).Error; err != nil {
+		logger.Error("UpsertNotificationChannelSetting error", err, "user_address", setting.UserAddress, "channel", setting.Channel)
+		return err
+	}
+	logger.Info("UpsertNotificationChannelSetting success", "user_address", setting.UserAddress, "channel", setting.Channel, "enabled", setting.Enabled)
+	return nil
+}
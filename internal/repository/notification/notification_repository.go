@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
 
@@ -47,15 +48,32 @@ type NotificationRepository interface {
 	UpdateSlackConfig(ctx context.Context, userAddress, name string, updates map[string]interface{}) error
 	DeleteSlackConfig(ctx context.Context, userAddress, name string) error
 
+	// Matrix配置管理
+	CreateMatrixConfig(ctx context.Context, config *types.MatrixConfig) error
+	GetMatrixConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.MatrixConfig, error)
+	GetMatrixConfigByUserAddressAndName(ctx context.Context, userAddress, name string) (*types.MatrixConfig, error)
+	UpdateMatrixConfig(ctx context.Context, userAddress, name string, updates map[string]interface{}) error
+	DeleteMatrixConfig(ctx context.Context, userAddress, name string) error
+
 	// 通知日志管理
 	CreateNotificationLog(ctx context.Context, log *types.NotificationLog) error
 	CheckNotificationLogExists(ctx context.Context, channel types.NotificationChannel, userAddress string, configID uint, flowID, statusTo string) (bool, error)
+	DeleteNotificationLogs(ctx context.Context, standard string, chainID int, contractAddress, flowID, statusTo string) (int64, error)
+	GetUserNotificationLogs(ctx context.Context, userAddress string, channel, flowID, sendStatus string, offset, limit int) ([]types.NotificationLog, int64, error)
+	CountUserNotificationLogsByStatus(ctx context.Context, userAddress string, since time.Time) ([]types.NotificationSendStatusCount, error)
 
 	// 获取用户的所有激活通知配置
 	GetUserActiveNotificationConfigs(ctx context.Context, userAddress string) (*types.UserNotificationConfigs, error)
 
+	// 渠道类型总开关
+	GetNotificationChannelSettings(ctx context.Context, userAddress string) ([]types.NotificationChannelSetting, error)
+	UpsertNotificationChannelSetting(ctx context.Context, setting *types.NotificationChannelSetting) error
+
 	// 获取与合约相关的用户地址
 	GetContractRelatedUserAddresses(ctx context.Context, standard string, chainID int, contractAddress string) ([]string, error)
+
+	// Transaction 在同一事务中执行 fn，fn 中的读写需通过传入的 repo 进行，fn 返回错误时整体回滚
+	Transaction(ctx context.Context, fn func(repo NotificationRepository) error) error
 }
 
 // notificationRepository 通知渠道仓库实现
@@ -68,6 +86,13 @@ func NewRepository(db *gorm.DB) NotificationRepository {
 	return &notificationRepository{db: db}
 }
 
+// Transaction 在同一事务中执行 fn
+func (r *notificationRepository) Transaction(ctx context.Context, fn func(repo NotificationRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&notificationRepository{db: tx})
+	})
+}
+
 // ===== Telegram配置管理 =====
 // CreateTelegramConfig 创建Telegram配置
 func (r *notificationRepository) CreateTelegramConfig(ctx context.Context, config *types.TelegramConfig) error {
@@ -403,6 +428,73 @@ func (r *notificationRepository) DeleteSlackConfig(ctx context.Context, userAddr
 	return nil
 }
 
+// ===== Matrix配置管理 =====
+// CreateMatrixConfig 创建Matrix配置
+func (r *notificationRepository) CreateMatrixConfig(ctx context.Context, config *types.MatrixConfig) error {
+	if err := r.db.WithContext(ctx).Create(config).Error; err != nil {
+		logger.Error("CreateMatrixConfig error", err, "user_address", config.UserAddress, "name", config.Name)
+		return err
+	}
+	logger.Info("CreateMatrixConfig success", "user_address", config.UserAddress, "name", config.Name)
+	return nil
+}
+
+// GetMatrixConfigsByUserAddress 根据用户地址获取Matrix配置
+func (r *notificationRepository) GetMatrixConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.MatrixConfig, error) {
+	var configs []*types.MatrixConfig
+	normalizedUserAddress := strings.ToLower(userAddress)
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(user_address) = ?", normalizedUserAddress).
+		Order("created_at DESC").
+		Find(&configs).Error; err != nil {
+		logger.Error("GetMatrixConfigsByUserAddress error", err, "user_address", userAddress)
+		return nil, err
+	}
+	logger.Info("GetMatrixConfigsByUserAddress success", "user_address", userAddress)
+	return configs, nil
+}
+
+// GetMatrixConfigByUserAddressAndName 根据用户地址和名称获取Matrix配置
+func (r *notificationRepository) GetMatrixConfigByUserAddressAndName(ctx context.Context, userAddress, name string) (*types.MatrixConfig, error) {
+	var config types.MatrixConfig
+	normalizedUserAddress := strings.ToLower(userAddress)
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(user_address) = ? AND name = ?", normalizedUserAddress, name).
+		First(&config).Error; err != nil {
+		logger.Error("GetMatrixConfigByUserAddressAndName error", err, "user_address", userAddress, "name", name)
+		return nil, err
+	}
+	logger.Info("GetMatrixConfigByUserAddressAndName success", "user_address", userAddress, "name", name)
+	return &config, nil
+}
+
+// UpdateMatrixConfig 更新Matrix配置
+func (r *notificationRepository) UpdateMatrixConfig(ctx context.Context, userAddress, name string, updates map[string]interface{}) error {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	if err := r.db.WithContext(ctx).
+		Model(&types.MatrixConfig{}).
+		Where("LOWER(user_address) = ? AND name = ?", normalizedUserAddress, name).
+		Updates(updates).Error; err != nil {
+		logger.Error("UpdateMatrixConfig error", err, "user_address", userAddress, "name", name)
+		return err
+	}
+	logger.Info("UpdateMatrixConfig success", "user_address", userAddress, "name", name)
+	return nil
+}
+
+// DeleteMatrixConfig 删除Matrix配置
+func (r *notificationRepository) DeleteMatrixConfig(ctx context.Context, userAddress, name string) error {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(user_address) = ? AND name = ?", normalizedUserAddress, name).
+		Delete(&types.MatrixConfig{}).Error; err != nil {
+		logger.Error("DeleteMatrixConfig error", err, "user_address", userAddress, "name", name)
+		return err
+	}
+	logger.Info("DeleteMatrixConfig success", "user_address", userAddress, "name", name)
+	return nil
+}
+
 // ===== 通知日志管理 =====
 // CreateNotificationLog 创建通知日志
 func (r *notificationRepository) CreateNotificationLog(ctx context.Context, log *types.NotificationLog) error {
@@ -429,6 +521,65 @@ func (r *notificationRepository) CheckNotificationLogExists(ctx context.Context,
 	return count > 0, nil
 }
 
+// DeleteNotificationLogs 删除指定 flow 某一状态的通知日志（用于管理员手动重发时清除去重记录）
+func (r *notificationRepository) DeleteNotificationLogs(ctx context.Context, standard string, chainID int, contractAddress, flowID, statusTo string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("timelock_standard = ? AND chain_id = ? AND LOWER(contract_address) = ? AND flow_id = ? AND status_to = ?",
+			standard, chainID, strings.ToLower(contractAddress), flowID, statusTo).
+		Delete(&types.NotificationLog{})
+	if result.Error != nil {
+		logger.Error("DeleteNotificationLogs error", result.Error, "standard", standard, "chain_id", chainID, "contract_address", contractAddress, "flow_id", flowID, "status_to", statusTo)
+		return 0, result.Error
+	}
+	logger.Info("DeleteNotificationLogs success", "standard", standard, "chain_id", chainID, "contract_address", contractAddress, "flow_id", flowID, "status_to", statusTo, "rows", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
+// GetUserNotificationLogs 分页获取用户的通知日志（按发送时间倒序），channel/flowID/sendStatus 为空时不过滤
+func (r *notificationRepository) GetUserNotificationLogs(ctx context.Context, userAddress string, channel, flowID, sendStatus string, offset, limit int) ([]types.NotificationLog, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&types.NotificationLog{}).
+		Where("LOWER(user_address) = ?", strings.ToLower(userAddress))
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if flowID != "" {
+		query = query.Where("flow_id = ?", flowID)
+	}
+	if sendStatus != "" {
+		query = query.Where("send_status = ?", sendStatus)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("GetUserNotificationLogs count error", err, "user_address", userAddress)
+		return nil, 0, err
+	}
+
+	var logs []types.NotificationLog
+	if err := query.Order("sent_at DESC, id DESC").Offset(offset).Limit(limit).Find(&logs).Error; err != nil {
+		logger.Error("GetUserNotificationLogs error", err, "user_address", userAddress)
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// CountUserNotificationLogsByStatus 统计用户 since 之后的通知日志，按渠道与发送状态分组
+func (r *notificationRepository) CountUserNotificationLogsByStatus(ctx context.Context, userAddress string, since time.Time) ([]types.NotificationSendStatusCount, error) {
+	var counts []types.NotificationSendStatusCount
+	if err := r.db.WithContext(ctx).
+		Model(&types.NotificationLog{}).
+		Select("channel, send_status, COUNT(*) AS count").
+		Where("LOWER(user_address) = ? AND sent_at >= ?", strings.ToLower(userAddress), since).
+		Group("channel, send_status").
+		Order("channel").
+		Scan(&counts).Error; err != nil {
+		logger.Error("CountUserNotificationLogsByStatus error", err, "user_address", userAddress)
+		return nil, err
+	}
+	return counts, nil
+}
+
 // ===== 获取用户的所有激活通知配置 =====
 // GetUserActiveNotificationConfigs 获取用户的所有激活通知配置
 func (r *notificationRepository) GetUserActiveNotificationConfigs(ctx context.Context, userAddress string) (*types.UserNotificationConfigs, error) {
@@ -474,6 +625,14 @@ func (r *notificationRepository) GetUserActiveNotificationConfigs(ctx context.Co
 		return nil, err
 	}
 
+	// 获取激活的Matrix配置
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(user_address) = ? AND is_active = ?", normalizedUserAddress, true).
+		Find(&configs.MatrixConfigs).Error; err != nil {
+		logger.Error("GetUserActiveNotificationConfigs error", err, "user_address", userAddress, "is_active", true)
+		return nil, err
+	}
+
 	logger.Info("GetUserActiveNotificationConfigs success", "user_address", userAddress)
 	return configs, nil
 }
@@ -2,6 +2,8 @@ package public
 
 import (
 	"context"
+	"time"
+	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
 
 	"gorm.io/gorm"
@@ -13,6 +15,8 @@ type Repository interface {
 	GetTotalContractCount(ctx context.Context) (int64, error)
 	GetTotalTransactionCount(ctx context.Context) (int64, error)
 	UpdateChainStatistics(ctx context.Context, chainID int, chainName string, contractCount, transactionCount int64) error
+	CreateStatsSnapshot(ctx context.Context, snapshot *types.GlobalStatsSnapshot) error
+	ListStatsSnapshots(ctx context.Context, from, to *time.Time, offset, limit int) ([]types.GlobalStatsSnapshot, int64, error)
 }
 
 // repository 公共数据仓库实现
@@ -88,3 +92,36 @@ func (r *repository) UpdateChainStatistics(ctx context.Context, chainID int, cha
 	logger.Info("UpdateChainStatistics: ", "chain_id", chainID, "chain_name", chainName, "contracts", contractCount, "transactions", transactionCount)
 	return nil
 }
+
+// CreateStatsSnapshot 写入一条全局统计快照
+func (r *repository) CreateStatsSnapshot(ctx context.Context, snapshot *types.GlobalStatsSnapshot) error {
+	if err := r.db.WithContext(ctx).Create(snapshot).Error; err != nil {
+		logger.Error("CreateStatsSnapshot Error: ", err)
+		return err
+	}
+	return nil
+}
+
+// ListStatsSnapshots 按时间范围分页查询全局统计快照，按快照时间升序
+func (r *repository) ListStatsSnapshots(ctx context.Context, from, to *time.Time, offset, limit int) ([]types.GlobalStatsSnapshot, int64, error) {
+	query := r.db.WithContext(ctx).Model(&types.GlobalStatsSnapshot{})
+	if from != nil {
+		query = query.Where("snapshot_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("snapshot_at < ?", *to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("ListStatsSnapshots count Error: ", err)
+		return nil, 0, err
+	}
+
+	snapshots := []types.GlobalStatsSnapshot{}
+	if err := query.Order("snapshot_at ASC, id ASC").Offset(offset).Limit(limit).Find(&snapshots).Error; err != nil {
+		logger.Error("ListStatsSnapshots Error: ", err)
+		return nil, 0, err
+	}
+	return snapshots, total, nil
+}
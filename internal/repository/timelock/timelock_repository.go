@@ -20,22 +20,31 @@ type Repository interface {
 	GetCompoundTimeLockByChainAndAddress(ctx context.Context, chainID int, contractAddress string) (*types.CompoundTimeLock, error)
 	UpdateCompoundTimeLock(ctx context.Context, timeLock *types.CompoundTimeLock) error
 	DeleteCompoundTimeLock(ctx context.Context, chainID int, contractAddress string, userAddress string) error
-	UpdateCompoundTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string) error
+	UpdateCompoundTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string, nickname *string) error
 
 	// OpenZeppelin Timelock操作
 	CreateOpenzeppelinTimeLock(ctx context.Context, timeLock *types.OpenzeppelinTimeLock) error
 	GetOpenzeppelinTimeLockByChainAndAddress(ctx context.Context, chainID int, contractAddress string) (*types.OpenzeppelinTimeLock, error)
 	UpdateOpenzeppelinTimeLock(ctx context.Context, timeLock *types.OpenzeppelinTimeLock) error
 	DeleteOpenzeppelinTimeLock(ctx context.Context, chainID int, contractAddress string, userAddress string) error
-	UpdateOpenzeppelinTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string) error
+	UpdateOpenzeppelinTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string, nickname *string) error
 
 	// 查询操作
 	CheckCompoundTimeLockExists(ctx context.Context, chainID int, contractAddress string, userAddress string) (bool, error)
 	CheckOpenzeppelinTimeLockExists(ctx context.Context, chainID int, contractAddress string, userAddress string) (bool, error)
+	// 按用户获取已导入的合约，不存在时返回 nil
+	GetCompoundTimeLockByCreator(ctx context.Context, chainID int, contractAddress string, userAddress string) (*types.CompoundTimeLock, error)
+	GetOpenzeppelinTimeLockByCreator(ctx context.Context, chainID int, contractAddress string, userAddress string) (*types.OpenzeppelinTimeLock, error)
+	// 按主键获取合约（不含已删除状态），不存在时返回 nil
+	GetCompoundTimeLockByID(ctx context.Context, id int64) (*types.CompoundTimeLock, error)
+	GetOpenzeppelinTimeLockByID(ctx context.Context, id int64) (*types.OpenzeppelinTimeLock, error)
 
 	// 权限相关查询
 	GetTimeLocksByUserPermissions(ctx context.Context, userAddress string, req *types.GetTimeLockListRequest) ([]types.CompoundTimeLockWithPermission, []types.OpenzeppelinTimeLockWithPermission, int64, error)
 
+	// 用户有合约（未删除，范围与列表相同）的链，合约数按链汇总
+	GetUserTimeLockChains(ctx context.Context, userAddress string) ([]types.TimeLockChain, error)
+
 	// 验证操作
 	ValidateCompoundOwnership(ctx context.Context, chainID int, contractAddress string, userAddress string) (bool, error)
 	ValidateOpenzeppelinOwnership(ctx context.Context, chainID int, contractAddress string, userAddress string) (bool, error)
@@ -44,9 +53,9 @@ type Repository interface {
 	GetAllCompoundTimeLocksByUser(ctx context.Context, userAddress string) ([]types.CompoundTimeLock, error)
 	GetAllOpenzeppelinTimeLocksByUser(ctx context.Context, userAddress string) ([]types.OpenzeppelinTimeLock, error)
 
-	// 获取所有活跃timelock合约（用于定时刷新）
-	GetAllActiveCompoundTimeLocks(ctx context.Context) ([]types.CompoundTimeLock, error)
-	GetAllActiveOpenzeppelinTimeLocks(ctx context.Context) ([]types.OpenzeppelinTimeLock, error)
+	// 获取所有需要定时刷新的timelock合约（active 与 inactive，inactive 合约复核通过后恢复）
+	GetAllRefreshableCompoundTimeLocks(ctx context.Context) ([]types.CompoundTimeLock, error)
+	GetAllRefreshableOpenzeppelinTimeLocks(ctx context.Context) ([]types.OpenzeppelinTimeLock, error)
 
 	// 获取指定链的所有活跃timelock合约
 	GetAllActiveCompoundTimelocks(ctx context.Context, chainID int) ([]types.CompoundTimeLock, error)
@@ -123,14 +132,18 @@ func (r *repository) DeleteCompoundTimeLock(ctx context.Context, chainID int, co
 	return nil
 }
 
-// UpdateCompoundTimeLockRemark 更新compound timelock备注
-func (r *repository) UpdateCompoundTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string) error {
+// UpdateCompoundTimeLockRemark 更新compound timelock备注，nickname 非 nil 时同时更新昵称
+func (r *repository) UpdateCompoundTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string, nickname *string) error {
 	normalizedContractAddress := strings.ToLower(contractAddress)
 	normalizedUserAddress := strings.ToLower(userAddress)
+	updates := map[string]interface{}{"remark": remark}
+	if nickname != nil {
+		updates["nickname"] = *nickname
+	}
 	if err := r.db.WithContext(ctx).
 		Model(&types.CompoundTimeLock{}).
 		Where("chain_id = ? AND LOWER(contract_address) = ? AND LOWER(creator_address) = ?", chainID, normalizedContractAddress, normalizedUserAddress).
-		Update("remark", remark).Error; err != nil {
+		Updates(updates).Error; err != nil {
 		logger.Error("UpdateCompoundTimeLockRemark error", err, "chain_id", chainID, "contract_address", contractAddress, "user_address", userAddress)
 		return err
 	}
@@ -195,14 +208,18 @@ func (r *repository) DeleteOpenzeppelinTimeLock(ctx context.Context, chainID int
 	return nil
 }
 
-// UpdateOpenzeppelinTimeLockRemark 更新openzeppelin timelock备注
-func (r *repository) UpdateOpenzeppelinTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string) error {
+// UpdateOpenzeppelinTimeLockRemark 更新openzeppelin timelock备注，nickname 非 nil 时同时更新昵称
+func (r *repository) UpdateOpenzeppelinTimeLockRemark(ctx context.Context, chainID int, contractAddress string, userAddress string, remark string, nickname *string) error {
 	normalizedContractAddress := strings.ToLower(contractAddress)
 	normalizedUserAddress := strings.ToLower(userAddress)
+	updates := map[string]interface{}{"remark": remark}
+	if nickname != nil {
+		updates["nickname"] = *nickname
+	}
 	if err := r.db.WithContext(ctx).
 		Model(&types.OpenzeppelinTimeLock{}).
 		Where("chain_id = ? AND LOWER(contract_address) = ? AND LOWER(creator_address) = ?", chainID, normalizedContractAddress, normalizedUserAddress).
-		Update("remark", remark).Error; err != nil {
+		Updates(updates).Error; err != nil {
 		logger.Error("UpdateOpenzeppelinTimeLockRemark error", err, "chain_id", chainID, "contract_address", contractAddress, "user_address", userAddress)
 		return err
 	}
@@ -251,6 +268,72 @@ func (r *repository) CheckOpenzeppelinTimeLockExists(ctx context.Context, chainI
 	return exists, nil
 }
 
+// GetCompoundTimeLockByCreator 获取用户导入的compound timelock合约（命中 creator+chain+address 唯一索引），不存在时返回 nil
+// 删除合约为物理删除，删除后即查不到；与其他查询一致排除 status=deleted
+func (r *repository) GetCompoundTimeLockByCreator(ctx context.Context, chainID int, contractAddress string, userAddress string) (*types.CompoundTimeLock, error) {
+	var timeLock types.CompoundTimeLock
+	err := r.db.WithContext(ctx).
+		Where("creator_address = ? AND chain_id = ? AND contract_address = ? AND status != ?", strings.ToLower(userAddress), chainID, strings.ToLower(contractAddress), "deleted").
+		First(&timeLock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("GetCompoundTimeLockByCreator error", err, "chain_id", chainID, "contract_address", contractAddress, "user_address", userAddress)
+		return nil, err
+	}
+	return &timeLock, nil
+}
+
+// GetOpenzeppelinTimeLockByCreator 获取用户导入的openzeppelin timelock合约（命中 creator+chain+address 唯一索引），不存在时返回 nil
+// 删除合约为物理删除，删除后即查不到；与其他查询一致排除 status=deleted
+func (r *repository) GetOpenzeppelinTimeLockByCreator(ctx context.Context, chainID int, contractAddress string, userAddress string) (*types.OpenzeppelinTimeLock, error) {
+	var timeLock types.OpenzeppelinTimeLock
+	err := r.db.WithContext(ctx).
+		Where("creator_address = ? AND chain_id = ? AND contract_address = ? AND status != ?", strings.ToLower(userAddress), chainID, strings.ToLower(contractAddress), "deleted").
+		First(&timeLock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("GetOpenzeppelinTimeLockByCreator error", err, "chain_id", chainID, "contract_address", contractAddress, "user_address", userAddress)
+		return nil, err
+	}
+	return &timeLock, nil
+}
+
+// GetCompoundTimeLockByID 根据主键获取compound timelock合约（不含已删除状态），不存在时返回 nil
+func (r *repository) GetCompoundTimeLockByID(ctx context.Context, id int64) (*types.CompoundTimeLock, error) {
+	var timeLock types.CompoundTimeLock
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND status != ?", id, "deleted").
+		First(&timeLock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("GetCompoundTimeLockByID error", err, "id", id)
+		return nil, err
+	}
+	return &timeLock, nil
+}
+
+// GetOpenzeppelinTimeLockByID 根据主键获取openzeppelin timelock合约（不含已删除状态），不存在时返回 nil
+func (r *repository) GetOpenzeppelinTimeLockByID(ctx context.Context, id int64) (*types.OpenzeppelinTimeLock, error) {
+	var timeLock types.OpenzeppelinTimeLock
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND status != ?", id, "deleted").
+		First(&timeLock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logger.Error("GetOpenzeppelinTimeLockByID error", err, "id", id)
+		return nil, err
+	}
+	return &timeLock, nil
+}
+
 // GetTimeLocksByUserPermissions 根据用户权限获取timelock列表
 func (r *repository) GetTimeLocksByUserPermissions(ctx context.Context, userAddress string, req *types.GetTimeLockListRequest) ([]types.CompoundTimeLockWithPermission, []types.OpenzeppelinTimeLockWithPermission, int64, error) {
 	var compoundTimeLocks []types.CompoundTimeLock
@@ -329,6 +412,35 @@ func (r *repository) GetTimeLocksByUserPermissions(ctx context.Context, userAddr
 	return compoundWithPermissions, openzeppelinWithPermissions, totalCount, nil
 }
 
+// GetUserTimeLockChains 获取用户有合约的链（用户为创建者或持有角色，与 GetTimeLocksByUserPermissions 范围一致），链展示信息来自 support_chains
+func (r *repository) GetUserTimeLockChains(ctx context.Context, userAddress string) ([]types.TimeLockChain, error) {
+	normalizedUserAddress := strings.ToLower(userAddress)
+	like := "%" + normalizedUserAddress + "%"
+
+	chains := []types.TimeLockChain{}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT t.chain_id, COALESCE(MAX(sc.chain_name), MAX(t.chain_name)) AS chain_name,
+			COALESCE(MAX(sc.display_name), '') AS display_name, COALESCE(MAX(sc.logo_url), '') AS logo_url,
+			COALESCE(BOOL_OR(sc.is_testnet), false) AS is_testnet, COUNT(*) AS contract_count
+		FROM (
+			SELECT chain_id, chain_name FROM compound_timelocks
+			WHERE status != 'deleted' AND (LOWER(creator_address) = ? OR LOWER(admin) = ? OR LOWER(pending_admin) = ?)
+			UNION ALL
+			SELECT chain_id, chain_name FROM openzeppelin_timelocks
+			WHERE status != 'deleted' AND (LOWER(creator_address) = ? OR LOWER(proposers) LIKE ? OR LOWER(executors) LIKE ?)
+		) t
+		LEFT JOIN support_chains sc ON sc.chain_id = t.chain_id
+		GROUP BY t.chain_id
+		ORDER BY t.chain_id`,
+		normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, normalizedUserAddress, like, like).
+		Scan(&chains).Error
+	if err != nil {
+		logger.Error("GetUserTimeLockChains error", err, "user_address", userAddress)
+		return nil, err
+	}
+	return chains, nil
+}
+
 // ValidateCompoundOwnership 验证compound timelock合约的所有权
 func (r *repository) ValidateCompoundOwnership(ctx context.Context, chainID int, contractAddress string, userAddress string) (bool, error) {
 	normalizedContractAddress := strings.ToLower(contractAddress)
@@ -403,37 +515,37 @@ func (r *repository) GetAllOpenzeppelinTimeLocksByUser(ctx context.Context, user
 	return timelocks, nil
 }
 
-// GetAllActiveCompoundTimeLocks 获取所有活跃的compound timelock合约
-func (r *repository) GetAllActiveCompoundTimeLocks(ctx context.Context) ([]types.CompoundTimeLock, error) {
+// GetAllRefreshableCompoundTimeLocks 获取所有需要定时刷新的compound timelock合约（active 与 inactive）
+func (r *repository) GetAllRefreshableCompoundTimeLocks(ctx context.Context) ([]types.CompoundTimeLock, error) {
 	var timelocks []types.CompoundTimeLock
 
 	err := r.db.WithContext(ctx).
-		Where("status = ?", "active").
+		Where("status IN ?", []string{"active", "inactive"}).
 		Find(&timelocks).Error
 
 	if err != nil {
-		logger.Error("GetAllActiveCompoundTimeLocks error", err)
+		logger.Error("GetAllRefreshableCompoundTimeLocks error", err)
 		return nil, err
 	}
 
-	logger.Info("GetAllActiveCompoundTimeLocks success", "count", len(timelocks))
+	logger.Info("GetAllRefreshableCompoundTimeLocks success", "count", len(timelocks))
 	return timelocks, nil
 }
 
-// GetAllActiveOpenzeppelinTimeLocks 获取所有活跃的openzeppelin timelock合约
-func (r *repository) GetAllActiveOpenzeppelinTimeLocks(ctx context.Context) ([]types.OpenzeppelinTimeLock, error) {
+// GetAllRefreshableOpenzeppelinTimeLocks 获取所有需要定时刷新的openzeppelin timelock合约（active 与 inactive）
+func (r *repository) GetAllRefreshableOpenzeppelinTimeLocks(ctx context.Context) ([]types.OpenzeppelinTimeLock, error) {
 	var timelocks []types.OpenzeppelinTimeLock
 
 	err := r.db.WithContext(ctx).
-		Where("status = ?", "active").
+		Where("status IN ?", []string{"active", "inactive"}).
 		Find(&timelocks).Error
 
 	if err != nil {
-		logger.Error("GetAllActiveOpenzeppelinTimeLocks error", err)
+		logger.Error("GetAllRefreshableOpenzeppelinTimeLocks error", err)
 		return nil, err
 	}
 
-	logger.Info("GetAllActiveOpenzeppelinTimeLocks success", "count", len(timelocks))
+	logger.Info("GetAllRefreshableOpenzeppelinTimeLocks success", "count", len(timelocks))
 	return timelocks, nil
 }
 
@@ -0,0 +1,71 @@
+package abi
+
+import (
+	"context"
+	"fmt"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+)
+
+// defaultABIPageSize ABI 分页列表默认每页条数
+const defaultABIPageSize = 20
+
+// GetABIPage 分页查询用户的ABI（可选包含共享ABI），附带函数数量与用户相关流程中的使用次数
+func (s *service) GetABIPage(ctx context.Context, walletAddress string, req *types.GetABIPageRequest) (*types.GetABIPageResponse, error) {
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, defaultABIPageSize)
+	offset := (page - 1) * pageSize
+
+	abis, total, err := s.abiRepo.GetABIPage(ctx, walletAddress, req.Name, req.IncludeShared, offset, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ABI page: %w", err)
+	}
+
+	ids := make([]int64, len(abis))
+	for i, abi := range abis {
+		ids[i] = abi.ID
+	}
+	functionCounts, err := s.abiRepo.CountABIFunctions(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ABI functions: %w", err)
+	}
+	usage, err := s.abiRepo.CountABIUsage(ctx, walletAddress, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ABI usage: %w", err)
+	}
+
+	items := make([]types.ABISummary, len(abis))
+	for i, abi := range abis {
+		items[i] = types.ABISummary{
+			ID:            abi.ID,
+			Name:          abi.Name,
+			Owner:         abi.Owner,
+			Description:   abi.Description,
+			IsShared:      abi.IsShared,
+			FunctionCount: functionCounts[abi.ID],
+			UsedInFlows:   usage[abi.ID],
+			CreatedAt:     abi.CreatedAt,
+			UpdatedAt:     abi.UpdatedAt,
+		}
+	}
+
+	logger.Info("GetABIPage Success:", "wallet_address", walletAddress, "page", page, "page_size", pageSize, "total", total)
+	return &types.GetABIPageResponse{
+		ABIs:           items,
+		Total:          total,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
+
+// indexABIFunctions 重建 ABI 的函数选择器索引；ABI 已通过校验，失败只记录日志（只影响使用统计与按选择器查找）
+func (s *service) indexABIFunctions(ctx context.Context, abi *types.ABI) {
+	functions, err := utils.ExtractABIFunctions(abi.ABIContent)
+	if err != nil {
+		logger.Error("Failed to parse ABI functions for index", err, "abi_id", abi.ID)
+		return
+	}
+	if err := s.abiRepo.ReplaceABIFunctions(ctx, abi.ID, functions); err != nil {
+		logger.Error("Failed to index ABI functions", err, "abi_id", abi.ID)
+	}
+}
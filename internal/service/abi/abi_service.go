@@ -2,9 +2,12 @@ package abi
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
+	"timelocker-backend/internal/config"
 	abiRepo "timelocker-backend/internal/repository/abi"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
@@ -19,33 +22,67 @@ var (
 	ErrInvalidABI         = errors.New("invalid ABI format")
 	ErrABINameExists      = errors.New("ABI name already exists")
 	ErrCannotDeleteShared = errors.New("cannot delete shared ABI")
+	ErrInvalidCalldata    = errors.New("invalid calldata")
+	ErrSelectorNotInABI   = errors.New("function not found in ABI")
+	ErrABITooLarge        = errors.New("ABI content too large")
+	ErrABIInUse           = errors.New("ABI is in use by existing flows")
+	ErrInvalidABISearch   = errors.New("invalid ABI search")
 )
 
+// maxABIContentSize ABI 内容的最大字节数（请求体大小由中间件限制，此处防止绕过 HTTP 层的调用写入超大 ABI）
+const maxABIContentSize = 2 << 20
+
+// checkABISize 校验 ABI 内容大小
+func checkABISize(abiContent string) error {
+	if len(abiContent) > maxABIContentSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrABITooLarge, len(abiContent), maxABIContentSize)
+	}
+	return nil
+}
+
 // Service ABI服务接口
 type Service interface {
 	CreateABI(ctx context.Context, walletAddress string, req *types.CreateABIRequest) (*types.ABIResponse, error)
 	GetABIList(ctx context.Context, walletAddress string) (*types.ABIListResponse, error)
+	GetABIPage(ctx context.Context, walletAddress string, req *types.GetABIPageRequest) (*types.GetABIPageResponse, error)
 	GetABIByID(ctx context.Context, id int64, walletAddress string) (*types.ABIResponse, error)
 	UpdateABI(ctx context.Context, id int64, walletAddress string, req *types.UpdateABIRequest) (*types.ABIResponse, error)
-	DeleteABI(ctx context.Context, id int64, walletAddress string) error
+	DeleteABI(ctx context.Context, id int64, walletAddress string, force bool) error
 	ValidateABI(ctx context.Context, abiContent string) (*types.ABIValidationResult, error)
+	DecodeCalldata(ctx context.Context, walletAddress string, req *types.DecodeCalldataRequest) (*types.DecodedCalldata, error)
+	RedecodeFlows(ctx context.Context, id int64, walletAddress string) (*types.RedecodeFlowsResponse, error)
+	GetContractDecodeABIs(ctx context.Context, walletAddress, standard string, chainID int, contractAddress string) ([]types.ABI, error)
+	SearchSharedABIs(ctx context.Context, req *types.SearchSharedABIsRequest) (*types.SearchSharedABIsResponse, error)
+	DecodeBestEffort(ctx context.Context, walletAddress string, req *types.DecodeBestEffortRequest) (*types.DecodeBestEffortResponse, error)
+	VerifyABIAgainstContract(ctx context.Context, walletAddress string, req *types.VerifyABIAgainstContractRequest) (*types.VerifyABIAgainstContractResponse, error)
 }
 
 type service struct {
-	abiRepo abiRepo.Repository
+	abiRepo         abiRepo.Repository
+	signatureLookup SignatureLookup    // 公共函数签名库，未配置时为 nil
+	codeSource      ContractCodeSource // 链上合约字节码，用于校验ABI与已部署合约
 }
 
-func NewService(abiRepo abiRepo.Repository) Service {
-	return &service{
-		abiRepo: abiRepo,
+func NewService(abiRepo abiRepo.Repository, codeSource ContractCodeSource, cfg config.ABIConfig) Service {
+	s := &service{
+		abiRepo:    abiRepo,
+		codeSource: codeSource,
+	}
+	if cfg.SignatureLookupURL != "" {
+		s.signatureLookup = newFourByteLookup(cfg.SignatureLookupURL, cfg.SignatureLookupTimeout)
 	}
+	return s
 }
 
 // CreateABI 创建新的ABI
 func (s *service) CreateABI(ctx context.Context, walletAddress string, req *types.CreateABIRequest) (*types.ABIResponse, error) {
 	logger.Info("CreateABI:", "wallet_address", walletAddress, "name", req.Name)
 
-	// 1. 验证ABI格式
+	// 1. 验证ABI大小与格式
+	if err := checkABISize(req.ABIContent); err != nil {
+		logger.Error("CreateABI ABI too large:", err, "wallet_address", walletAddress, "name", req.Name)
+		return nil, err
+	}
 	validation, err := utils.ValidateABI(req.ABIContent)
 	if err != nil {
 		logger.Error("CreateABI validation error:", err, "wallet_address", walletAddress, "name", req.Name)
@@ -95,10 +132,21 @@ func (s *service) CreateABI(ctx context.Context, walletAddress string, req *type
 		UpdatedAt:   newABI.UpdatedAt,
 	}
 
+	s.indexABIFunctions(ctx, newABI)
+
 	logger.Info("CreateABI Success:", "id", newABI.ID, "wallet_address", walletAddress, "name", req.Name)
 	return response, nil
 }
 
+// GetContractDecodeABIs 获取用户解码该合约流程时使用过的 ABI（用户自己的与共享的）
+func (s *service) GetContractDecodeABIs(ctx context.Context, walletAddress, standard string, chainID int, contractAddress string) ([]types.ABI, error) {
+	abis, err := s.abiRepo.GetContractDecodeABIs(ctx, walletAddress, standard, chainID, contractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract ABIs: %w", err)
+	}
+	return abis, nil
+}
+
 // GetABIList 获取ABI列表（用户的+共享的）
 func (s *service) GetABIList(ctx context.Context, walletAddress string) (*types.ABIListResponse, error) {
 	logger.Info("GetABIList:", "wallet_address", walletAddress)
@@ -182,7 +230,11 @@ func (s *service) UpdateABI(ctx context.Context, id int64, walletAddress string,
 		return nil, ErrAccessDenied
 	}
 
-	// 3. 验证新的ABI格式
+	// 3. 验证新的ABI大小与格式
+	if err := checkABISize(req.ABIContent); err != nil {
+		logger.Error("UpdateABI ABI too large:", err, "id", id, "wallet_address", walletAddress)
+		return nil, err
+	}
 	validation, err := utils.ValidateABI(req.ABIContent)
 	if err != nil {
 		logger.Error("UpdateABI validation error:", err, "id", id, "wallet_address", walletAddress)
@@ -218,6 +270,8 @@ func (s *service) UpdateABI(ctx context.Context, id int64, walletAddress string,
 		return nil, fmt.Errorf("failed to update ABI: %w", err)
 	}
 
+	s.indexABIFunctions(ctx, existingABI)
+
 	// 7. 返回响应
 	response := &types.ABIResponse{
 		ID:          existingABI.ID,
@@ -235,8 +289,9 @@ func (s *service) UpdateABI(ctx context.Context, id int64, walletAddress string,
 }
 
 // DeleteABI 删除ABI
-func (s *service) DeleteABI(ctx context.Context, id int64, walletAddress string) error {
-	logger.Info("DeleteABI:", "id", id, "wallet_address", walletAddress)
+// 用户相关流程中仍有调用该 ABI 函数的流程时拒绝删除（删除后这些流程的 calldata 无法再按该 ABI 解码），force=true 时仍然删除
+func (s *service) DeleteABI(ctx context.Context, id int64, walletAddress string, force bool) error {
+	logger.Info("DeleteABI:", "id", id, "wallet_address", walletAddress, "force", force)
 
 	// 1. 获取ABI并检查权限
 	existingABI, err := s.abiRepo.GetABIByID(ctx, id)
@@ -249,19 +304,32 @@ func (s *service) DeleteABI(ctx context.Context, id int64, walletAddress string)
 		return fmt.Errorf("failed to get ABI: %w", err)
 	}
 
-	// 2. 检查所有权
+	// 2. 不允许删除共享ABI
+	if existingABI.Owner == abiRepo.SharedABIOwner || existingABI.IsShared {
+		logger.Error("DeleteABI cannot delete shared:", ErrCannotDeleteShared, "id", id, "wallet_address", walletAddress)
+		return ErrCannotDeleteShared
+	}
+
+	// 3. 检查所有权
 	if existingABI.Owner != walletAddress {
 		logger.Error("DeleteABI access denied:", ErrAccessDenied, "id", id, "wallet_address", walletAddress, "owner", existingABI.Owner)
 		return ErrAccessDenied
 	}
 
-	// 3. 不允许删除共享ABI
-	if existingABI.Owner == abiRepo.SharedABIOwner {
-		logger.Error("DeleteABI cannot delete shared:", ErrCannotDeleteShared, "id", id, "wallet_address", walletAddress)
-		return ErrCannotDeleteShared
+	// 4. 检查是否仍被流程使用
+	if !force {
+		usage, err := s.abiRepo.CountABIUsage(ctx, walletAddress, []int64{id})
+		if err != nil {
+			logger.Error("DeleteABI usage check error:", err, "id", id, "wallet_address", walletAddress)
+			return fmt.Errorf("failed to check ABI usage: %w", err)
+		}
+		if usage[id] > 0 {
+			logger.Warn("DeleteABI refused, ABI in use", "id", id, "wallet_address", walletAddress, "used_in_flows", usage[id])
+			return fmt.Errorf("%w: referenced by %d flows, set force=true to delete anyway", ErrABIInUse, usage[id])
+		}
 	}
 
-	// 4. 执行删除
+	// 5. 执行删除（同时删除函数选择器索引）
 	if err := s.abiRepo.DeleteABI(ctx, id, walletAddress); err != nil {
 		logger.Error("DeleteABI database error:", err, "id", id, "wallet_address", walletAddress)
 		return fmt.Errorf("failed to delete ABI: %w", err)
@@ -275,6 +343,10 @@ func (s *service) DeleteABI(ctx context.Context, id int64, walletAddress string)
 func (s *service) ValidateABI(ctx context.Context, abiContent string) (*types.ABIValidationResult, error) {
 	logger.Info("ValidateABI: start validation")
 
+	if err := checkABISize(abiContent); err != nil {
+		return nil, err
+	}
+
 	validation, err := utils.ValidateABI(abiContent)
 	if err != nil {
 		logger.Error("ValidateABI error:", err)
@@ -284,3 +356,49 @@ func (s *service) ValidateABI(ctx context.Context, abiContent string) (*types.AB
 	logger.Info("ValidateABI Success:", "is_valid", validation.IsValid, "function_count", validation.FunctionCount, "event_count", validation.EventCount)
 	return validation, nil
 }
+
+// DecodeCalldata 使用指定ABI解码任意calldata
+func (s *service) DecodeCalldata(ctx context.Context, walletAddress string, req *types.DecodeCalldataRequest) (*types.DecodedCalldata, error) {
+	calldataHex := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(req.CalldataHex), "0x"), "0X")
+	calldata, err := hex.DecodeString(calldataHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: calldata_hex is not valid hex", ErrInvalidCalldata)
+	}
+
+	// 解析ABI来源：abi_id 优先（复用访问权限检查），否则使用请求中的 abi_content
+	abiContent := strings.TrimSpace(req.ABIContent)
+	if err := checkABISize(abiContent); err != nil {
+		return nil, err
+	}
+	if req.ABIID > 0 {
+		abiResp, err := s.GetABIByID(ctx, req.ABIID, walletAddress)
+		if err != nil {
+			return nil, err
+		}
+		abiContent = abiResp.ABIContent
+	}
+
+	functionSig := strings.TrimSpace(req.FunctionSignature)
+	var decoded *types.DecodedCalldata
+	if functionSig != "" {
+		decoded, err = utils.DecodeCalldataWithSignature(abiContent, functionSig, calldata)
+	} else {
+		if abiContent == "" {
+			return nil, fmt.Errorf("%w: abi_id or abi_content is required when function_signature is empty", ErrInvalidABI)
+		}
+		decoded, err = utils.DecodeCalldataWithSelector(abiContent, calldata)
+	}
+	if err != nil {
+		if errors.Is(err, utils.ErrSelectorNotInABI) || errors.Is(err, utils.ErrFunctionNotInABI) {
+			return nil, fmt.Errorf("%w: %s", ErrSelectorNotInABI, err.Error())
+		}
+		if errors.Is(err, utils.ErrABIParseFailed) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidABI, err.Error())
+		}
+		logger.Error("DecodeCalldata error:", err, "wallet_address", walletAddress, "abi_id", req.ABIID)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCalldata, err.Error())
+	}
+
+	logger.Info("DecodeCalldata Success:", "wallet_address", walletAddress, "function", decoded.FunctionSignature)
+	return decoded, nil
+}
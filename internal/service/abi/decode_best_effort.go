@@ -0,0 +1,103 @@
+package abi
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	abiRepo "timelocker-backend/internal/repository/abi"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+)
+
+// maxDecodeAlternatives 最多返回的备选解码数
+const maxDecodeAlternatives = 10
+
+// decodeResult 一次成功的解码
+type decodeResult struct {
+	candidate types.DecodeCandidate
+	decoded   *types.DecodedCalldata
+}
+
+// DecodeBestEffort 依次尝试用户ABI、共享ABI（按函数选择器索引查找），都无法解码时查询公共签名库，返回优先级最高的解码结果
+// 用户ABI与共享ABI能解码时不查询签名库；签名库查询失败只记录日志
+func (s *service) DecodeBestEffort(ctx context.Context, walletAddress string, req *types.DecodeBestEffortRequest) (*types.DecodeBestEffortResponse, error) {
+	calldataHex := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(req.CalldataHex), "0x"), "0X")
+	calldata, err := hex.DecodeString(calldataHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: calldata_hex is not valid hex", ErrInvalidCalldata)
+	}
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("%w: calldata must include a 4-byte function selector", ErrInvalidCalldata)
+	}
+	selector := "0x" + hex.EncodeToString(calldata[:4])
+
+	abis, err := s.abiRepo.GetABIsBySelector(ctx, walletAddress, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate ABIs: %w", err)
+	}
+	var results []decodeResult
+	for _, item := range abis {
+		decoded, err := utils.DecodeCalldataWithSelector(item.ABIContent, calldata)
+		if err != nil {
+			logger.Warn("Candidate ABI failed to decode calldata", "abi_id", item.ID, "selector", selector, "error", err)
+			continue
+		}
+		source, confidence := types.DecodeSourceUserABI, "high"
+		if item.Owner == abiRepo.SharedABIOwner {
+			source, confidence = types.DecodeSourceSharedABI, "medium"
+		}
+		results = append(results, decodeResult{
+			candidate: types.DecodeCandidate{
+				Source:            source,
+				Confidence:        confidence,
+				ABIID:             item.ID,
+				ABIName:           item.Name,
+				FunctionSignature: decoded.FunctionSignature,
+			},
+			decoded: decoded,
+		})
+	}
+
+	if len(results) == 0 && s.signatureLookup != nil {
+		signatures, err := s.signatureLookup.LookupSignatures(ctx, selector)
+		if err != nil {
+			logger.Warn("Signature lookup failed", "selector", selector, "error", err)
+		}
+		for _, signature := range signatures {
+			decoded, err := utils.DecodeCalldataWithSignature("", signature, calldata[4:])
+			if err != nil || decoded.Selector != selector {
+				continue
+			}
+			results = append(results, decodeResult{
+				candidate: types.DecodeCandidate{
+					Source:            types.DecodeSourceSignatureDatabase,
+					Confidence:        "low",
+					FunctionSignature: decoded.FunctionSignature,
+				},
+				decoded: decoded,
+			})
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%w: no candidate ABI or signature decodes selector %s", ErrSelectorNotInABI, selector)
+	}
+
+	response := &types.DecodeBestEffortResponse{
+		Decoded:         results[0].decoded,
+		DecodeCandidate: results[0].candidate,
+		Alternatives:    []types.DecodeCandidate{},
+	}
+	for _, result := range results[1:] {
+		if len(response.Alternatives) >= maxDecodeAlternatives {
+			break
+		}
+		response.Alternatives = append(response.Alternatives, result.candidate)
+	}
+
+	logger.Info("DecodeBestEffort Success:", "wallet_address", walletAddress, "selector", selector, "source", response.Source, "candidates", len(results))
+	return response, nil
+}
@@ -0,0 +1,135 @@
+package abi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+)
+
+// maxRedecodeFlows 单次重新解码每种标准最多处理的流程数（按 id 倒序，优先最近的流程）
+const maxRedecodeFlows = 1000
+
+// RedecodeFlows 使用指定 ABI 重新解码用户相关的历史流程，结果写入按用户隔离的解码缓存
+// 只处理调用了该 ABI 中函数的流程；解码失败的调用跳过，不覆盖已有结果
+func (s *service) RedecodeFlows(ctx context.Context, id int64, walletAddress string) (*types.RedecodeFlowsResponse, error) {
+	logger.Info("RedecodeFlows:", "id", id, "wallet_address", walletAddress)
+
+	abi, err := s.GetABIByID(ctx, id, walletAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	compoundFlows, err := s.abiRepo.GetABIMatchedCompoundFlows(ctx, walletAddress, id, maxRedecodeFlows+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matched compound flows: %w", err)
+	}
+	ozFlows, ozCalls, err := s.abiRepo.GetABIMatchedOpenzeppelinFlows(ctx, walletAddress, id, maxRedecodeFlows+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matched openzeppelin flows: %w", err)
+	}
+
+	response := &types.RedecodeFlowsResponse{ABIID: id}
+	if len(compoundFlows) > maxRedecodeFlows {
+		compoundFlows = compoundFlows[:maxRedecodeFlows]
+		response.Truncated = true
+	}
+	if len(ozFlows) > maxRedecodeFlows {
+		ozFlows = ozFlows[:maxRedecodeFlows]
+		response.Truncated = true
+	}
+	response.FlowsMatched = len(compoundFlows) + len(ozFlows)
+
+	now := time.Now()
+	var rows []types.FlowDecodedCall
+
+	for _, flow := range compoundFlows {
+		var decoded *types.DecodedCalldata
+		var err error
+		if flow.FunctionSignature != nil && strings.TrimSpace(*flow.FunctionSignature) != "" {
+			decoded, err = utils.DecodeCalldataWithSignature(abi.ABIContent, *flow.FunctionSignature, flow.CallData)
+		} else {
+			decoded, err = utils.DecodeCalldataWithSelector(abi.ABIContent, flow.CallData)
+		}
+		if err != nil {
+			logger.Warn("RedecodeFlows skip compound flow", "flow_id", flow.FlowID, "chain_id", flow.ChainID, "abi_id", id, "error", err)
+			continue
+		}
+		row, err := newFlowDecodedCall(walletAddress, "compound", flow.ChainID, flow.ContractAddress, flow.FlowID, 0, id, decoded, now)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		response.FlowsDecoded++
+		response.CallsDecoded++
+	}
+
+	callsByFlow := make(map[string][]types.OpenzeppelinFlowCallDB)
+	for _, call := range ozCalls {
+		key := ozFlowKey(call.ChainID, call.ContractAddress, call.FlowID)
+		callsByFlow[key] = append(callsByFlow[key], call)
+	}
+	for _, flow := range ozFlows {
+		calls := callsByFlow[ozFlowKey(flow.ChainID, flow.ContractAddress, flow.FlowID)]
+		if len(calls) == 0 {
+			// 没有子调用记录的历史数据退化为 flow 自身的单个调用
+			calls = []types.OpenzeppelinFlowCallDB{{CallIndex: 0, CallData: flow.CallData}}
+		}
+
+		decodedCalls := 0
+		for _, call := range calls {
+			decoded, err := utils.DecodeCalldataWithSelector(abi.ABIContent, call.CallData)
+			if err != nil {
+				continue
+			}
+			row, err := newFlowDecodedCall(walletAddress, "openzeppelin", flow.ChainID, flow.ContractAddress, flow.FlowID, call.CallIndex, id, decoded, now)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+			decodedCalls++
+		}
+		if decodedCalls > 0 {
+			response.FlowsDecoded++
+			response.CallsDecoded += decodedCalls
+		}
+	}
+
+	if err := s.abiRepo.UpsertFlowDecodedCalls(ctx, rows); err != nil {
+		return nil, fmt.Errorf("failed to save decoded calls: %w", err)
+	}
+
+	logger.Info("RedecodeFlows Success:", "id", id, "wallet_address", walletAddress, "flows_matched", response.FlowsMatched, "flows_decoded", response.FlowsDecoded, "calls_decoded", response.CallsDecoded, "truncated", response.Truncated)
+	return response, nil
+}
+
+// newFlowDecodedCall 构造解码缓存行
+func newFlowDecodedCall(walletAddress, standard string, chainID int, contractAddress, flowID string, callIndex int, abiID int64, decoded *types.DecodedCalldata, decodedAt time.Time) (types.FlowDecodedCall, error) {
+	params, err := json.Marshal(decoded.Params)
+	if err != nil {
+		return types.FlowDecodedCall{}, fmt.Errorf("failed to marshal decoded params: %w", err)
+	}
+	return types.FlowDecodedCall{
+		UserAddress:       walletAddress,
+		Standard:          standard,
+		ChainID:           chainID,
+		ContractAddress:   contractAddress,
+		FlowID:            flowID,
+		CallIndex:         callIndex,
+		ABIID:             abiID,
+		FunctionName:      decoded.FunctionName,
+		FunctionSignature: decoded.FunctionSignature,
+		Params:            string(params),
+		DecodedAt:         decodedAt,
+	}, nil
+}
+
+// ozFlowKey OpenZeppelin 流程在内存中的关联键
+func ozFlowKey(chainID int, contractAddress, flowID string) string {
+	return fmt.Sprintf("%d:%s:%s", chainID, strings.ToLower(contractAddress), flowID)
+}
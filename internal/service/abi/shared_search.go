@@ -0,0 +1,56 @@
+package abi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// sharedABISearchLimit 单次搜索最多返回的匹配函数数
+const sharedABISearchLimit = 200
+
+var selectorPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{8}$`)
+
+// SearchSharedABIs 按函数名在平台共享ABI中查找（基于 abi_functions 函数索引），结果按 ABI 分组
+// function 为 0x 开头的 4 字节选择器时按选择器精确匹配，便于为无法解码的调用找到合适的 ABI
+func (s *service) SearchSharedABIs(ctx context.Context, req *types.SearchSharedABIsRequest) (*types.SearchSharedABIsResponse, error) {
+	function := strings.TrimSpace(req.Function)
+	if function == "" {
+		return nil, fmt.Errorf("%w: function is required", ErrInvalidABISearch)
+	}
+	var selector string
+	if selectorPattern.MatchString(function) {
+		selector = function
+	}
+
+	matches, err := s.abiRepo.SearchSharedABIFunctions(ctx, function, selector, sharedABISearchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search shared ABIs: %w", err)
+	}
+
+	response := &types.SearchSharedABIsResponse{ABIs: []types.SharedABISearchResult{}}
+	index := make(map[int64]int)
+	for _, match := range matches {
+		i, ok := index[match.ABIID]
+		if !ok {
+			i = len(response.ABIs)
+			index[match.ABIID] = i
+			response.ABIs = append(response.ABIs, types.SharedABISearchResult{
+				ID:          match.ABIID,
+				Name:        match.Name,
+				Description: match.Description,
+			})
+		}
+		response.ABIs[i].Functions = append(response.ABIs[i].Functions, types.SharedABIFunction{
+			Selector:  match.Selector,
+			Signature: match.Signature,
+		})
+	}
+
+	logger.Info("SearchSharedABIs Success:", "function", function, "abi_count", len(response.ABIs), "function_count", len(matches))
+	return response, nil
+}
@@ -0,0 +1,78 @@
+package abi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// SignatureLookup 公共函数签名库，按 4 字节选择器查询可能的函数签名
+type SignatureLookup interface {
+	LookupSignatures(ctx context.Context, selector string) ([]string, error)
+}
+
+// fourByteLookup 4byte 风格的签名库：GET {url}?hex_signature=0x12345678，返回 results[].text_signature
+type fourByteLookup struct {
+	url    string
+	client *http.Client
+}
+
+// newFourByteLookup 创建签名库客户端，timeout <= 0 时使用 5s
+func newFourByteLookup(lookupURL string, timeout time.Duration) *fourByteLookup {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &fourByteLookup{
+		url:    lookupURL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// LookupSignatures 查询选择器对应的函数签名，按登记先后排序（先登记的通常是真实函数，后登记的多为碰撞）
+func (l *fourByteLookup) LookupSignatures(ctx context.Context, selector string) ([]string, error) {
+	endpoint, err := url.Parse(l.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature lookup url: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("hex_signature", selector)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("signature lookup request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signature lookup returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Results []struct {
+			ID            int64  `json:"id"`
+			TextSignature string `json:"text_signature"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse signature lookup response: %w", err)
+	}
+	sort.SliceStable(body.Results, func(i, j int) bool { return body.Results[i].ID < body.Results[j].ID })
+
+	signatures := make([]string, 0, len(body.Results))
+	for _, result := range body.Results {
+		if result.TextSignature != "" {
+			signatures = append(signatures, result.TextSignature)
+		}
+	}
+	return signatures, nil
+}
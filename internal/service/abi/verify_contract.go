@@ -0,0 +1,124 @@
+package abi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidContractAddress = errors.New("invalid contract address")
+	ErrContractNotDeployed    = errors.New("no contract code at address")
+	ErrRPCConnection          = errors.New("failed to connect to RPC")
+)
+
+// contractCodeLookupTimeout 校验ABI时读取链上字节码与存储槽的总超时
+const contractCodeLookupTimeout = 20 * time.Second
+
+// eip1967ImplementationSlot EIP-1967 实现合约地址存储槽：bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)
+var eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// ContractCodeSource 读取链上合约字节码与存储槽（由 scanner.RPCManager 实现）
+type ContractCodeSource interface {
+	ContractCode(ctx context.Context, chainID int, address common.Address) ([]byte, error)
+	StorageAt(ctx context.Context, chainID int, address common.Address, slot common.Hash) ([]byte, error)
+}
+
+// VerifyABIAgainstContract 校验ABI中的函数是否在已部署合约的字节码中实现
+// 合约为 EIP-1167 最小代理或 EIP-1967 代理时扫描实现合约的字节码
+func (s *service) VerifyABIAgainstContract(ctx context.Context, walletAddress string, req *types.VerifyABIAgainstContractRequest) (*types.VerifyABIAgainstContractResponse, error) {
+	contractAddress := strings.TrimSpace(req.ContractAddress)
+	if !crypto.ValidateEthereumAddress(contractAddress) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidContractAddress, req.ContractAddress)
+	}
+
+	abiResp, err := s.GetABIByID(ctx, req.ABIID, walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	functions, err := utils.ExtractABIFunctions(abiResp.ABIContent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidABI, err.Error())
+	}
+
+	if s.codeSource == nil {
+		return nil, fmt.Errorf("%w: contract code source not configured", ErrRPCConnection)
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, contractCodeLookupTimeout)
+	defer cancel()
+
+	address := common.HexToAddress(contractAddress)
+	code, err := s.codeSource.ContractCode(lookupCtx, req.ChainID, address)
+	if err != nil {
+		logger.Error("VerifyABIAgainstContract get code error:", err, "chain_id", req.ChainID, "contract_address", contractAddress)
+		return nil, fmt.Errorf("%w: %v", ErrRPCConnection, err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%w: %s on chain %d", ErrContractNotDeployed, contractAddress, req.ChainID)
+	}
+
+	response := &types.VerifyABIAgainstContractResponse{
+		ABIID:           req.ABIID,
+		ChainID:         req.ChainID,
+		ContractAddress: crypto.NormalizeAddress(contractAddress),
+		Implemented:     []types.ABIFunctionPresence{},
+		Missing:         []types.ABIFunctionPresence{},
+	}
+	codeAddress, proxyType := s.resolveImplementation(lookupCtx, req.ChainID, address, code)
+	if proxyType != "" {
+		implCode, err := s.codeSource.ContractCode(lookupCtx, req.ChainID, codeAddress)
+		if err != nil {
+			logger.Error("VerifyABIAgainstContract get implementation code error:", err, "chain_id", req.ChainID, "implementation", codeAddress.Hex())
+			return nil, fmt.Errorf("%w: %v", ErrRPCConnection, err)
+		}
+		// 实现合约未部署时退回扫描代理本身
+		if len(implCode) > 0 {
+			code = implCode
+			response.ProxyType = proxyType
+		} else {
+			codeAddress = address
+		}
+	}
+	response.CodeAddress = crypto.NormalizeAddress(codeAddress.Hex())
+	response.CodeSize = len(code)
+
+	selectors := utils.ExtractBytecodeSelectors(code)
+	for _, function := range functions {
+		presence := types.ABIFunctionPresence{Signature: function.Signature, Selector: function.Selector}
+		if utils.HasBytecodeSelector(selectors, common.FromHex(function.Selector)) {
+			response.Implemented = append(response.Implemented, presence)
+		} else {
+			response.Missing = append(response.Missing, presence)
+		}
+	}
+
+	logger.Info("VerifyABIAgainstContract Success:", "wallet_address", walletAddress, "abi_id", req.ABIID, "chain_id", req.ChainID,
+		"contract_address", response.ContractAddress, "proxy_type", response.ProxyType, "implemented", len(response.Implemented), "missing", len(response.Missing))
+	return response, nil
+}
+
+// resolveImplementation 识别代理合约并返回实现合约地址；非代理或读取存储槽失败时返回原地址与空类型
+func (s *service) resolveImplementation(ctx context.Context, chainID int, address common.Address, code []byte) (common.Address, string) {
+	if impl, ok := utils.ParseMinimalProxy(code); ok {
+		return impl, types.ContractProxyTypeEIP1167
+	}
+
+	value, err := s.codeSource.StorageAt(ctx, chainID, address, eip1967ImplementationSlot)
+	if err != nil {
+		logger.Warn("Failed to read EIP-1967 implementation slot, scanning contract code directly", "chain_id", chainID, "contract_address", address.Hex(), "error", err)
+		return address, ""
+	}
+	if impl := common.BytesToAddress(value); impl != (common.Address{}) {
+		return impl, types.ContractProxyTypeEIP1967
+	}
+	return address, ""
+}
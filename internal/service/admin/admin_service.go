@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	emailRepo "timelocker-backend/internal/repository/email"
+	errorLogRepo "timelocker-backend/internal/repository/errorlog"
+	goldskyRepo "timelocker-backend/internal/repository/goldsky"
+	notificationRepo "timelocker-backend/internal/repository/notification"
+	"timelocker-backend/internal/service/chain"
+	"timelocker-backend/internal/service/email"
+	"timelocker-backend/internal/service/goldsky"
+	"timelocker-backend/internal/service/notification"
+	"timelocker-backend/internal/service/scanner"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+var (
+	ErrFlowNotFound       = errors.New("flow not found")
+	ErrGoldskyUnavailable = errors.New("goldsky service not configured")
+)
+
+// AdminService 管理员服务接口
+type AdminService interface {
+	// 清除流程某一状态的通知去重记录并重新发送通知
+	ResendFlowNotification(ctx context.Context, adminAddress string, req *types.ResendFlowNotificationRequest) (*types.ResendFlowNotificationResponse, error)
+	// 模拟流程状态变化并走完整通知流程（消息标记为模拟，去重与发送记录不写入真实日志）
+	SimulateFlowTransition(ctx context.Context, adminAddress string, req *types.SimulateFlowTransitionRequest) (*types.SimulateFlowTransitionResponse, error)
+	// 手动设置卡住流程的状态（可选先向 Goldsky/链上确认）
+	SetFlowStatus(ctx context.Context, adminAddress string, req *types.SetFlowStatusRequest) (*types.SetFlowStatusResponse, error)
+	// 查询全部流程（不限于与管理员相关的合约）
+	ListFlows(ctx context.Context, req *types.GetAdminFlowListRequest) (*types.GetAdminFlowListResponse, error)
+	// 设置/轮换链的区块浏览器 API Key
+	SetExplorerAPIKey(ctx context.Context, adminAddress string, req *types.SetExplorerAPIKeyRequest) (*types.ExplorerAPIKeyStatus, error)
+	// 设置链的 Webhook 事件确认深度
+	SetConfirmationDepth(ctx context.Context, adminAddress string, req *types.SetConfirmationDepthRequest) (*types.ConfirmationDepthStatus, error)
+	// 立即自检所有链的 subgraph
+	CheckSubgraphs(ctx context.Context, adminAddress string) (*types.CheckSubgraphsResponse, error)
+	// 分页查询 error_logs（可按级别过滤）
+	ListErrorLogs(ctx context.Context, req *types.GetAdminErrorLogListRequest) (*types.GetAdminErrorLogListResponse, error)
+	// 删除 before 之前的 error_logs（定时任务）
+	CleanupErrorLogs(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	// 汇总合约已被删除的孤立 flow
+	GetOrphanedFlows(ctx context.Context) (*types.GetOrphanedFlowsResponse, error)
+	// 检测孤立 flow，archiveBefore 非零时归档该时间之前最后更新的孤立 flow（定时任务）
+	CleanupOrphanedFlows(ctx context.Context, archiveBefore time.Time, batchSize int) (int64, error)
+	// 按用户或合约范围立即重算流程状态并通知变更
+	RecalcFlowStatus(ctx context.Context, adminAddress string, req *types.RecalcFlowStatusRequest) (*types.RecalcFlowStatusResponse, error)
+}
+
+// adminService 管理员服务实现
+type adminService struct {
+	flowRepo         goldskyRepo.FlowRepository
+	notificationRepo notificationRepo.NotificationRepository
+	emailRepo        emailRepo.EmailRepository
+	errorLogRepo     errorLogRepo.Repository
+	notificationSvc  notification.NotificationService
+	emailSvc         email.EmailService
+	goldskySvc       *goldsky.GoldskyService
+	rpcManager       *scanner.RPCManager
+	chainSvc         chain.Service
+}
+
+// NewAdminService 创建管理员服务实例
+func NewAdminService(
+	flowRepo goldskyRepo.FlowRepository,
+	notificationRepo notificationRepo.NotificationRepository,
+	emailRepo emailRepo.EmailRepository,
+	errorLogRepo errorLogRepo.Repository,
+	notificationSvc notification.NotificationService,
+	emailSvc email.EmailService,
+	goldskySvc *goldsky.GoldskyService,
+	rpcManager *scanner.RPCManager,
+	chainSvc chain.Service,
+) AdminService {
+	return &adminService{
+		flowRepo:         flowRepo,
+		notificationRepo: notificationRepo,
+		emailRepo:        emailRepo,
+		errorLogRepo:     errorLogRepo,
+		notificationSvc:  notificationSvc,
+		emailSvc:         emailSvc,
+		goldskySvc:       goldskySvc,
+		rpcManager:       rpcManager,
+		chainSvc:         chainSvc,
+	}
+}
+
+// ResendFlowNotification 清除流程某一状态的通知去重记录并重新走通知发送流程
+func (s *adminService) ResendFlowNotification(ctx context.Context, adminAddress string, req *types.ResendFlowNotificationRequest) (*types.ResendFlowNotificationResponse, error) {
+	standard := strings.ToLower(strings.TrimSpace(req.Standard))
+	contractAddress := strings.ToLower(strings.TrimSpace(req.ContractAddress))
+	flowID := strings.TrimSpace(req.FlowID)
+
+	txHash, initiator, err := s.getFlowTxInfo(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusTo)
+	if err != nil {
+		return nil, err
+	}
+
+	clearedNotification, err := s.notificationRepo.DeleteNotificationLogs(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear notification logs: %w", err)
+	}
+	clearedEmail, err := s.emailRepo.DeleteSendLogs(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear email send logs: %w", err)
+	}
+
+	response := &types.ResendFlowNotificationResponse{
+		ClearedNotificationLogs: clearedNotification,
+		ClearedEmailLogs:        clearedEmail,
+	}
+
+	if err := s.emailSvc.SendFlowNotification(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusFrom, req.StatusTo, txHash, initiator); err != nil {
+		logger.Error("Admin resend email notification failed", err, "flow_id", flowID, "status_to", req.StatusTo)
+		response.Errors = append(response.Errors, fmt.Sprintf("email: %v", err))
+	}
+	if err := s.notificationSvc.SendFlowNotification(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusFrom, req.StatusTo, txHash, initiator); err != nil {
+		logger.Error("Admin resend channel notification failed", err, "flow_id", flowID, "status_to", req.StatusTo)
+		response.Errors = append(response.Errors, fmt.Sprintf("channel: %v", err))
+	}
+
+	logger.Info("Admin manual notification resend",
+		"admin", strings.ToLower(adminAddress),
+		"standard", standard,
+		"chain_id", req.ChainID,
+		"contract_address", contractAddress,
+		"flow_id", flowID,
+		"status_from", req.StatusFrom,
+		"status_to", req.StatusTo,
+		"cleared_notification_logs", clearedNotification,
+		"cleared_email_logs", clearedEmail,
+		"errors", len(response.Errors),
+	)
+	return response, nil
+}
+
+// getFlowTxInfo 获取流程目标状态对应的交易哈希与发起人
+func (s *adminService) getFlowTxInfo(ctx context.Context, standard string, chainID int, contractAddress, flowID, status string) (*string, string, error) {
+	var txHash, initiator *string
+	switch standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, "", ErrFlowNotFound
+		}
+		txHash, initiator = flow.TxHashForStatus(status), flow.InitiatorAddress
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, "", ErrFlowNotFound
+		}
+		txHash, initiator = flow.TxHashForStatus(status), flow.InitiatorAddress
+	default:
+		return nil, "", ErrFlowNotFound
+	}
+
+	if initiator == nil {
+		return txHash, "", nil
+	}
+	return txHash, *initiator, nil
+}
+
+// SetExplorerAPIKey 设置/轮换链的区块浏览器 API Key
+func (s *adminService) SetExplorerAPIKey(ctx context.Context, adminAddress string, req *types.SetExplorerAPIKeyRequest) (*types.ExplorerAPIKeyStatus, error) {
+	status, err := s.chainSvc.SetExplorerAPIKey(ctx, req.ChainID, req.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Admin updated explorer api key", "admin", strings.ToLower(adminAddress), "chain_id", req.ChainID, "configured", status.Configured)
+	return status, nil
+}
+
+// SetConfirmationDepth 设置链的 Webhook 事件确认深度
+func (s *adminService) SetConfirmationDepth(ctx context.Context, adminAddress string, req *types.SetConfirmationDepthRequest) (*types.ConfirmationDepthStatus, error) {
+	status, err := s.chainSvc.SetConfirmationDepth(ctx, req.ChainID, *req.ConfirmationDepth)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Admin updated chain confirmation depth", "admin", strings.ToLower(adminAddress), "chain_id", req.ChainID, "confirmation_depth", status.ConfirmationDepth)
+	return status, nil
+}
+
+// CheckSubgraphs 立即自检所有链的 subgraph，结果同时更新同步循环使用的可用状态
+func (s *adminService) CheckSubgraphs(ctx context.Context, adminAddress string) (*types.CheckSubgraphsResponse, error) {
+	if s.goldskySvc == nil {
+		return nil, ErrGoldskyUnavailable
+	}
+	results := s.goldskySvc.CheckSubgraphs(ctx)
+	response := &types.CheckSubgraphsResponse{Results: results}
+	for _, result := range results {
+		if result.Healthy() {
+			response.Healthy++
+		} else {
+			response.Broken++
+		}
+	}
+	logger.Info("Admin ran subgraph self-check", "admin", strings.ToLower(adminAddress), "healthy", response.Healthy, "broken", response.Broken)
+	return response, nil
+}
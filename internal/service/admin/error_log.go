@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+var (
+	ErrInvalidErrorLogFilter = errors.New("invalid error log filter")
+)
+
+// ListErrorLogs 管理员分页查询 error_logs，可按级别、调用位置、函数、关键字与时间范围过滤
+func (s *adminService) ListErrorLogs(ctx context.Context, req *types.GetAdminErrorLogListRequest) (*types.GetAdminErrorLogListResponse, error) {
+	filter := types.AdminErrorLogFilter{
+		Level:    strings.ToLower(strings.TrimSpace(req.Level)),
+		Caller:   strings.TrimSpace(req.Caller),
+		Function: strings.TrimSpace(req.Function),
+		Keyword:  strings.TrimSpace(req.Keyword),
+		From:     req.From,
+		To:       req.To,
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidErrorLogFilter)
+	}
+
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, 20)
+	logs, total, err := s.errorLogRepo.ListErrorLogs(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list error logs: %w", err)
+	}
+
+	return &types.GetAdminErrorLogListResponse{
+		Logs:           logs,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
+
+// CleanupErrorLogs 分批删除 before 之前的 error_logs，删除后 VACUUM 回收空间与索引，返回删除总行数
+func (s *adminService) CleanupErrorLogs(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		deleted, err := s.errorLogRepo.DeleteErrorLogsBefore(ctx, before, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to delete error logs: %w", err)
+		}
+		total += deleted
+		if deleted < int64(batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		if err := s.errorLogRepo.VacuumErrorLogs(ctx); err != nil {
+			logger.Warn("Failed to vacuum error_logs after cleanup", "error", err)
+		}
+	}
+	logger.Info("Cleaned up error logs", "before", before.Format(time.RFC3339), "deleted", total)
+	return total, nil
+}
@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidFlowListFilter = errors.New("invalid flow list filter")
+)
+
+// ListFlows 管理员查询全部流程，不受用户与合约关系的限制
+func (s *adminService) ListFlows(ctx context.Context, req *types.GetAdminFlowListRequest) (*types.GetAdminFlowListResponse, error) {
+	filter := types.AdminFlowFilter{
+		Standard:        strings.ToLower(strings.TrimSpace(req.Standard)),
+		ChainID:         req.ChainID,
+		Status:          strings.ToLower(strings.TrimSpace(req.Status)),
+		ContractAddress: strings.ToLower(strings.TrimSpace(req.ContractAddress)),
+		Initiator:       strings.ToLower(strings.TrimSpace(req.Initiator)),
+		CreatedFrom:     req.CreatedFrom,
+		CreatedTo:       req.CreatedTo,
+		IncludeArchived: req.IncludeArchived,
+	}
+	if filter.ContractAddress != "" && !common.IsHexAddress(filter.ContractAddress) {
+		return nil, fmt.Errorf("%w: invalid contract_address", ErrInvalidFlowListFilter)
+	}
+	if filter.Initiator != "" && !common.IsHexAddress(filter.Initiator) {
+		return nil, fmt.Errorf("%w: invalid initiator", ErrInvalidFlowListFilter)
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, fmt.Errorf("%w: created_from must be before created_to", ErrInvalidFlowListFilter)
+	}
+
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, 20)
+	flows, total, err := s.flowRepo.ListAllFlows(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flows: %w", err)
+	}
+
+	return &types.GetAdminFlowListResponse{
+		Flows:          flows,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
@@ -0,0 +1,224 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	ErrIllegalStatusTransition = errors.New("illegal flow status transition")
+	ErrFlowStatusMismatch      = errors.New("flow current status does not match status_from")
+	ErrStatusNotConfirmed      = errors.New("target status not confirmed by data source")
+	ErrVerificationUnavailable = errors.New("status verification unavailable")
+)
+
+// manualStatusTransitions 允许管理员手动执行的状态变更（from -> to）
+// 只允许沿正常生命周期前进，终态 executed/cancelled 不可再变更；OpenZeppelin 没有 expired 状态
+var manualStatusTransitions = map[string]map[string][]string{
+	"compound": {
+		"waiting": {"ready", "cancelled"},
+		"ready":   {"executed", "cancelled", "expired"},
+		"expired": {"executed", "cancelled"}, // 宽限期内执行/取消但事件被漏掉
+	},
+	"openzeppelin": {
+		"waiting": {"ready", "cancelled"},
+		"ready":   {"executed", "cancelled"},
+	},
+}
+
+// openzeppelinGetTimestampABI OpenZeppelin TimelockController.getTimestamp(bytes32)
+// 0 表示未调度（已取消），1 表示已执行，其余为可执行时间
+const openzeppelinGetTimestampABI = `[{"inputs":[{"internalType":"bytes32","name":"id","type":"bytes32"}],"name":"getTimestamp","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// isLegalManualTransition 判断手动状态变更是否合法
+func isLegalManualTransition(standard, from, to string) bool {
+	for _, allowed := range manualStatusTransitions[standard][from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFlowStatus 管理员手动设置流程状态，用于恢复因漏掉事件而卡住的流程
+// 仅写入状态与带操作人/原因的状态历史，不发送通知
+func (s *adminService) SetFlowStatus(ctx context.Context, adminAddress string, req *types.SetFlowStatusRequest) (*types.SetFlowStatusResponse, error) {
+	standard := strings.ToLower(strings.TrimSpace(req.Standard))
+	contractAddress := strings.ToLower(strings.TrimSpace(req.ContractAddress))
+	flowID := strings.TrimSpace(req.FlowID)
+	reason := strings.TrimSpace(req.Reason)
+
+	if !isLegalManualTransition(standard, req.StatusFrom, req.StatusTo) {
+		return nil, fmt.Errorf("%w: %s %s -> %s", ErrIllegalStatusTransition, standard, req.StatusFrom, req.StatusTo)
+	}
+
+	currentStatus, eta, expiredAt, err := s.getFlowStatusInfo(ctx, standard, req.ChainID, contractAddress, flowID)
+	if err != nil {
+		return nil, err
+	}
+	if currentStatus != req.StatusFrom {
+		return nil, fmt.Errorf("%w: current status is %s", ErrFlowStatusMismatch, currentStatus)
+	}
+
+	response := &types.SetFlowStatusResponse{StatusFrom: req.StatusFrom, StatusTo: req.StatusTo}
+	if req.Verify {
+		verifiedStatus, err := s.verifyFlowStatus(ctx, standard, req.ChainID, contractAddress, flowID, eta, expiredAt)
+		if err != nil {
+			return nil, err
+		}
+		response.VerifiedStatus = verifiedStatus
+		if verifiedStatus != req.StatusTo {
+			return nil, fmt.Errorf("%w: data source reports %s", ErrStatusNotConfirmed, verifiedStatus)
+		}
+		response.Verified = true
+	}
+
+	updated, err := s.flowRepo.SetFlowStatusManually(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusFrom, req.StatusTo, adminAddress, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set flow status: %w", err)
+	}
+	if !updated {
+		// 查询与更新之间状态被同步任务推进
+		return nil, fmt.Errorf("%w: status changed concurrently", ErrFlowStatusMismatch)
+	}
+
+	logger.Info("Admin manual flow status change",
+		"admin", strings.ToLower(adminAddress),
+		"standard", standard,
+		"chain_id", req.ChainID,
+		"contract_address", contractAddress,
+		"flow_id", flowID,
+		"status_from", req.StatusFrom,
+		"status_to", req.StatusTo,
+		"verified", response.Verified,
+		"reason", reason,
+	)
+	return response, nil
+}
+
+// getFlowStatusInfo 获取流程当前状态与时间信息
+func (s *adminService) getFlowStatusInfo(ctx context.Context, standard string, chainID int, contractAddress, flowID string) (string, *time.Time, *time.Time, error) {
+	switch standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return "", nil, nil, ErrFlowNotFound
+		}
+		return flow.Status, flow.Eta, flow.ExpiredAt, nil
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return "", nil, nil, ErrFlowNotFound
+		}
+		return flow.Status, flow.Eta, nil, nil
+	default:
+		return "", nil, nil, ErrFlowNotFound
+	}
+}
+
+// verifyFlowStatus 向外部数据源确认流程的实际状态
+// Compound 查询 Goldsky subgraph（subgraph 只记录事件，ready/expired 结合本地 eta/expired_at 推导）；OpenZeppelin 直接读链上 getTimestamp
+func (s *adminService) verifyFlowStatus(ctx context.Context, standard string, chainID int, contractAddress, flowID string, eta, expiredAt *time.Time) (string, error) {
+	switch standard {
+	case "compound":
+		return s.verifyCompoundFlowStatus(ctx, chainID, contractAddress, flowID, eta, expiredAt)
+	case "openzeppelin":
+		return s.verifyOpenzeppelinFlowStatus(ctx, chainID, contractAddress, flowID)
+	default:
+		return "", ErrVerificationUnavailable
+	}
+}
+
+// verifyCompoundFlowStatus 通过 Goldsky 确认 Compound 流程状态
+func (s *adminService) verifyCompoundFlowStatus(ctx context.Context, chainID int, contractAddress, flowID string, eta, expiredAt *time.Time) (string, error) {
+	if s.goldskySvc == nil {
+		return "", fmt.Errorf("%w: goldsky service not configured", ErrVerificationUnavailable)
+	}
+	flow, err := s.goldskySvc.GetCompoundFlowDetail(ctx, chainID, flowID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrVerificationUnavailable, err)
+	}
+	if flow == nil || !strings.EqualFold(flow.ContractAddress, contractAddress) {
+		return "", fmt.Errorf("%w: flow not found in subgraph", ErrVerificationUnavailable)
+	}
+
+	switch {
+	case flow.ExecuteTransaction != nil || flow.Status == "executed":
+		return "executed", nil
+	case flow.CancelTransaction != nil || flow.Status == "cancelled":
+		return "cancelled", nil
+	}
+
+	now := time.Now()
+	if expiredAt != nil && !now.Before(*expiredAt) {
+		return "expired", nil
+	}
+	if eta != nil && !now.Before(*eta) {
+		return "ready", nil
+	}
+	return "waiting", nil
+}
+
+// verifyOpenzeppelinFlowStatus 通过链上 getTimestamp 确认 OpenZeppelin 操作状态
+func (s *adminService) verifyOpenzeppelinFlowStatus(ctx context.Context, chainID int, contractAddress, flowID string) (string, error) {
+	if s.rpcManager == nil {
+		return "", fmt.Errorf("%w: rpc manager not configured", ErrVerificationUnavailable)
+	}
+	if !common.IsHexAddress(contractAddress) {
+		return "", fmt.Errorf("%w: invalid contract address", ErrVerificationUnavailable)
+	}
+	idBytes := common.FromHex(flowID)
+	if len(idBytes) != 32 {
+		return "", fmt.Errorf("%w: invalid operation id", ErrVerificationUnavailable)
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(openzeppelinGetTimestampABI))
+	if err != nil {
+		return "", err
+	}
+	callData, err := parsedABI.Pack("getTimestamp", common.BytesToHash(idBytes))
+	if err != nil {
+		return "", err
+	}
+
+	contract := common.HexToAddress(contractAddress)
+	var timestamp *big.Int
+	if err := s.rpcManager.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		result, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: callData}, nil)
+		if err != nil {
+			return err
+		}
+		return parsedABI.UnpackIntoInterface(&timestamp, "getTimestamp", result)
+	}); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrVerificationUnavailable, err)
+	}
+
+	switch {
+	case timestamp == nil || timestamp.Sign() == 0:
+		return "cancelled", nil // 本地存在的操作在链上未调度，只可能已被取消
+	case timestamp.Cmp(big.NewInt(1)) == 0:
+		return "executed", nil
+	case timestamp.IsInt64() && time.Now().Unix() >= timestamp.Int64():
+		return "ready", nil
+	default:
+		return "waiting", nil
+	}
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"timelocker-backend/internal/types"
+)
+
+func TestIsLegalManualTransition(t *testing.T) {
+	tests := []struct {
+		standard, from, to string
+		want               bool
+	}{
+		// 沿生命周期前进
+		{"compound", "waiting", "ready", true},
+		{"compound", "waiting", "cancelled", true},
+		{"compound", "ready", "executed", true},
+		{"compound", "ready", "cancelled", true},
+		{"compound", "ready", "expired", true},
+		{"compound", "expired", "executed", true},
+		{"compound", "expired", "cancelled", true},
+		{"openzeppelin", "waiting", "ready", true},
+		{"openzeppelin", "waiting", "cancelled", true},
+		{"openzeppelin", "ready", "executed", true},
+		{"openzeppelin", "ready", "cancelled", true},
+		// 跳过 ready 或回退
+		{"compound", "waiting", "executed", false},
+		{"compound", "waiting", "expired", false},
+		{"compound", "ready", "waiting", false},
+		{"compound", "expired", "ready", false},
+		{"openzeppelin", "waiting", "executed", false},
+		{"openzeppelin", "ready", "waiting", false},
+		// 终态不可变更
+		{"compound", "executed", "cancelled", false},
+		{"compound", "cancelled", "executed", false},
+		{"openzeppelin", "executed", "ready", false},
+		{"openzeppelin", "cancelled", "waiting", false},
+		// OpenZeppelin 没有 expired 状态
+		{"openzeppelin", "ready", "expired", false},
+		{"openzeppelin", "expired", "executed", false},
+		// 同状态、未知标准或状态
+		{"compound", "ready", "ready", false},
+		{"unknown", "waiting", "ready", false},
+		{"", "waiting", "ready", false},
+		{"compound", "", "ready", false},
+		{"Compound", "waiting", "ready", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.standard+"/"+tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := isLegalManualTransition(tt.standard, tt.from, tt.to); got != tt.want {
+				t.Fatalf("isLegalManualTransition(%q, %q, %q) = %v, want %v", tt.standard, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSetFlowStatusRejectsIllegalTransition 非法变更在查询流程之前拒绝（service 未注入仓库）
+func TestSetFlowStatusRejectsIllegalTransition(t *testing.T) {
+	s := &adminService{}
+	_, err := s.SetFlowStatus(context.Background(), "0x0000000000000000000000000000000000000001", &types.SetFlowStatusRequest{
+		FlowIdentifier: types.FlowIdentifier{Standard: " OpenZeppelin "},
+		StatusFrom:     "ready",
+		StatusTo:       "expired",
+	})
+	if !errors.Is(err, ErrIllegalStatusTransition) {
+		t.Fatalf("SetFlowStatus error = %v, want ErrIllegalStatusTransition", err)
+	}
+}
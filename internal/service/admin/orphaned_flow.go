@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// GetOrphanedFlows 汇总合约已被删除、仍留在热表中的 flow
+func (s *adminService) GetOrphanedFlows(ctx context.Context) (*types.GetOrphanedFlowsResponse, error) {
+	contracts, err := s.flowRepo.GetOrphanedFlowContracts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orphaned flows: %w", err)
+	}
+
+	response := &types.GetOrphanedFlowsResponse{Contracts: contracts}
+	if response.Contracts == nil {
+		response.Contracts = []types.OrphanedFlowContract{}
+	}
+	for _, contract := range contracts {
+		response.TotalFlows += contract.FlowCount
+	}
+	return response, nil
+}
+
+// CleanupOrphanedFlows 检测孤立 flow 并记录日志；archiveBefore 非零时分批将该时间之前最后更新的孤立 flow 移入归档表，返回归档总数
+func (s *adminService) CleanupOrphanedFlows(ctx context.Context, archiveBefore time.Time, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	report, err := s.GetOrphanedFlows(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if report.TotalFlows == 0 {
+		logger.Info("No orphaned flows found")
+		return 0, nil
+	}
+	for _, contract := range report.Contracts {
+		logger.Warn("Found flows whose contract no longer exists", "standard", contract.Standard, "chain_id", contract.ChainID,
+			"contract_address", contract.ContractAddress, "flow_count", contract.FlowCount, "last_updated_at", contract.LastUpdatedAt.Format(time.RFC3339))
+	}
+	if archiveBefore.IsZero() {
+		logger.Warn("Orphaned flows detected, archiving disabled", "contracts", len(report.Contracts), "flows", report.TotalFlows)
+		return 0, nil
+	}
+
+	var total int64
+	for _, standard := range []string{"compound", "openzeppelin"} {
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+			moved, err := s.flowRepo.ArchiveOrphanedFlows(ctx, standard, archiveBefore, batchSize)
+			if err != nil {
+				return total, fmt.Errorf("failed to archive orphaned %s flows: %w", standard, err)
+			}
+			total += moved
+			if moved < int64(batchSize) {
+				break
+			}
+		}
+	}
+	logger.Info("Archived orphaned flows", "before", archiveBefore.Format(time.RFC3339), "detected", report.TotalFlows, "archived", total)
+	return total, nil
+}
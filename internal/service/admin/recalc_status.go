@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidRecalcScope = errors.New("invalid recalc scope")
+)
+
+// recalcStatusLimit 单次重算的流程上限，与定时状态检查的批量一致
+const recalcStatusLimit = 100
+
+// RecalcFlowStatus 管理员按用户或合约范围立即重算流程状态（waiting->ready、ready->expired），
+// 复用定时状态检查的逻辑，变更会写库并投递通知。OpenZeppelin 流程由对账任务更新，不在此范围
+func (s *adminService) RecalcFlowStatus(ctx context.Context, adminAddress string, req *types.RecalcFlowStatusRequest) (*types.RecalcFlowStatusResponse, error) {
+	if s.goldskySvc == nil {
+		return nil, ErrGoldskyUnavailable
+	}
+
+	userAddress := strings.ToLower(strings.TrimSpace(req.UserAddress))
+	contractAddress := strings.ToLower(strings.TrimSpace(req.ContractAddress))
+	switch {
+	case userAddress != "" && (contractAddress != "" || req.ChainID != 0):
+		return nil, fmt.Errorf("%w: specify either user_address or chain_id + contract_address", ErrInvalidRecalcScope)
+	case userAddress != "":
+		if !common.IsHexAddress(userAddress) {
+			return nil, fmt.Errorf("%w: invalid user_address", ErrInvalidRecalcScope)
+		}
+	case contractAddress != "" && req.ChainID > 0:
+		if !common.IsHexAddress(contractAddress) {
+			return nil, fmt.Errorf("%w: invalid contract_address", ErrInvalidRecalcScope)
+		}
+	default:
+		return nil, fmt.Errorf("%w: user_address or chain_id + contract_address is required", ErrInvalidRecalcScope)
+	}
+
+	flows, err := s.flowRepo.GetCompoundFlowsNeedStatusUpdateByScope(ctx, userAddress, req.ChainID, contractAddress, time.Now(), recalcStatusLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flows need status update: %w", err)
+	}
+
+	changes := s.goldskySvc.RecalculateCompoundFlowStatuses(ctx, flows, "admin_recalc")
+	if changes == nil {
+		changes = []types.FlowStatusChange{}
+	}
+
+	logger.Info("Admin recalculated flow status",
+		"admin", strings.ToLower(adminAddress),
+		"user_address", userAddress,
+		"chain_id", req.ChainID,
+		"contract_address", contractAddress,
+		"checked", len(flows),
+		"changed", len(changes),
+	)
+	return &types.RecalcFlowStatusResponse{
+		Checked:   len(flows),
+		Changes:   changes,
+		Truncated: len(flows) >= recalcStatusLimit,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/service/notification"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// SimulateFlowTransition 按指定状态变化走一遍完整的通知发送流程，不修改流程状态
+// 消息标记为模拟发送；去重与发送记录落在本次请求独立的沙箱里，不影响真实的 notification_logs / email_send_logs
+func (s *adminService) SimulateFlowTransition(ctx context.Context, adminAddress string, req *types.SimulateFlowTransitionRequest) (*types.SimulateFlowTransitionResponse, error) {
+	standard := strings.ToLower(strings.TrimSpace(req.Standard))
+	contractAddress := strings.ToLower(strings.TrimSpace(req.ContractAddress))
+	flowID := strings.TrimSpace(req.FlowID)
+
+	txHash, initiator, err := s.getFlowTxInfo(ctx, standard, req.ChainID, contractAddress, flowID, req.StatusTo)
+	if err != nil {
+		return nil, err
+	}
+
+	simCtx, sandbox := notification.WithSimulation(ctx)
+	response := &types.SimulateFlowTransitionResponse{
+		StatusFrom: req.StatusFrom,
+		StatusTo:   req.StatusTo,
+	}
+
+	if err := s.emailSvc.SendFlowNotification(simCtx, standard, req.ChainID, contractAddress, flowID, req.StatusFrom, req.StatusTo, txHash, initiator); err != nil {
+		logger.Error("Admin simulated email notification failed", err, "flow_id", flowID, "status_to", req.StatusTo)
+		response.Errors = append(response.Errors, fmt.Sprintf("email: %v", err))
+	}
+	if err := s.notificationSvc.SendFlowNotification(simCtx, standard, req.ChainID, contractAddress, flowID, req.StatusFrom, req.StatusTo, txHash, initiator); err != nil {
+		logger.Error("Admin simulated channel notification failed", err, "flow_id", flowID, "status_to", req.StatusTo)
+		response.Errors = append(response.Errors, fmt.Sprintf("channel: %v", err))
+	}
+	response.Deliveries = sandbox.Deliveries()
+
+	logger.Info("Admin simulated flow transition notification",
+		"admin", strings.ToLower(adminAddress),
+		"standard", standard,
+		"chain_id", req.ChainID,
+		"contract_address", contractAddress,
+		"flow_id", flowID,
+		"status_from", req.StatusFrom,
+		"status_to", req.StatusTo,
+		"deliveries", len(response.Deliveries),
+		"errors", len(response.Errors),
+	)
+	return response, nil
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	auditRepo "timelocker-backend/internal/repository/audit"
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrInvalidAuditFilter 审计日志过滤条件不合法
+	ErrInvalidAuditFilter = errors.New("invalid audit log filter")
+	// ErrAuditAccessDenied 非管理员查询他人的审计日志
+	ErrAuditAccessDenied = errors.New("audit log access denied")
+)
+
+// Service 审计日志服务接口
+type Service interface {
+	ListAuditLogs(ctx context.Context, callerAddress string, isAdmin bool, req *types.GetAuditLogsRequest) (*types.GetAuditLogsResponse, error)
+}
+
+type service struct {
+	auditRepo auditRepo.Repository
+}
+
+// NewService 创建审计日志服务实例
+func NewService(auditRepo auditRepo.Repository) Service {
+	return &service{
+		auditRepo: auditRepo,
+	}
+}
+
+// ListAuditLogs 查询审计日志
+// 普通用户只能查询自己的操作记录；管理员可指定 actor，不指定时查询全部
+func (s *service) ListAuditLogs(ctx context.Context, callerAddress string, isAdmin bool, req *types.GetAuditLogsRequest) (*types.GetAuditLogsResponse, error) {
+	caller := strings.ToLower(callerAddress)
+	actor := strings.ToLower(strings.TrimSpace(req.Actor))
+	if actor != "" && !common.IsHexAddress(actor) {
+		return nil, fmt.Errorf("%w: invalid actor", ErrInvalidAuditFilter)
+	}
+	if !isAdmin {
+		if actor != "" && actor != caller {
+			return nil, ErrAuditAccessDenied
+		}
+		actor = caller
+	}
+
+	filter := types.AuditLogFilter{
+		ActorAddress: actor,
+		TargetType:   req.TargetType,
+		Action:       req.Action,
+	}
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, 20)
+	logs, total, err := s.auditRepo.ListAuditLogs(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	return &types.GetAuditLogsResponse{
+		Logs:           logs,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/repository/audit"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	apiTokenPrefix    = "tlk_"      // API令牌明文前缀
+	apiTokenType      = "api_token" // JWTClaims.Type 中标识API令牌
+	apiTokenByteCount = 32
+)
+
+// CreateAPIToken 创建API令牌，明文只在创建时返回一次
+func (s *service) CreateAPIToken(ctx context.Context, userID int64, walletAddress string, req *types.CreateAPITokenRequest) (*types.CreateAPITokenResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("token name is required")
+	}
+
+	raw := make([]byte, apiTokenByteCount)
+	if _, err := rand.Read(raw); err != nil {
+		logger.Error("CreateAPIToken Error: ", errors.New("failed to generate random token"), "error: ", err)
+		return nil, fmt.Errorf("failed to generate api token: %w", err)
+	}
+	plain := apiTokenPrefix + hex.EncodeToString(raw)
+
+	token := &types.APIToken{
+		UserID:        userID,
+		WalletAddress: strings.ToLower(walletAddress),
+		Name:          name,
+		TokenHash:     hashAPIToken(plain),
+		TokenPrefix:   plain[:len(apiTokenPrefix)+8],
+		Scope:         types.APITokenScopeRead,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour)
+		token.ExpiresAt = &expiresAt
+	}
+
+	auditCtx := audit.WithLog(ctx, types.AuditActionCreate, types.AuditTargetAPIToken, "", fmt.Sprintf("created api token %q scope=%s", name, token.Scope))
+	if err := s.apiTokenRepo.CreateAPIToken(auditCtx, token); err != nil {
+		return nil, fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	logger.Info("CreateAPIToken: ", "user_id", userID, "token_id", token.ID, "scope", token.Scope)
+	return &types.CreateAPITokenResponse{
+		Token:    plain,
+		APIToken: *token,
+	}, nil
+}
+
+// ListAPITokens 获取用户的API令牌列表（不含明文）
+func (s *service) ListAPITokens(ctx context.Context, userID int64) (*types.APITokenListResponse, error) {
+	tokens, err := s.apiTokenRepo.GetAPITokensByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	return &types.APITokenListResponse{
+		Tokens: tokens,
+		Total:  int64(len(tokens)),
+	}, nil
+}
+
+// RevokeAPIToken 吊销API令牌
+func (s *service) RevokeAPIToken(ctx context.Context, userID int64, tokenID int64) error {
+	auditCtx := audit.WithLog(ctx, types.AuditActionDelete, types.AuditTargetAPIToken, strconv.FormatInt(tokenID, 10), "revoked api token")
+	revoked, err := s.apiTokenRepo.RevokeAPIToken(auditCtx, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	if !revoked {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// VerifyAPIToken 校验API令牌并解析出所属钱包（只读作用域）
+func (s *service) VerifyAPIToken(ctx context.Context, rawToken string) (*types.JWTClaims, error) {
+	if !strings.HasPrefix(rawToken, apiTokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := s.apiTokenRepo.GetAPITokenByHash(ctx, hashAPIToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		logger.Error("VerifyAPIToken Error: ", errors.New("database error"), "error: ", err)
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if token.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: api token revoked", ErrInvalidToken)
+	}
+	if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	// 验证用户是否存在且有效
+	user, err := s.userRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if user.Status != 1 {
+		return nil, errors.New("user account is disabled")
+	}
+
+	if err := s.apiTokenRepo.UpdateLastUsed(ctx, token.ID); err != nil {
+		// 使用时间更新失败不影响鉴权
+		logger.Warn("Failed to update api token last used", "token_id", token.ID, "error", err)
+	}
+
+	return &types.JWTClaims{
+		UserID:        user.ID,
+		WalletAddress: user.WalletAddress,
+		Type:          apiTokenType,
+		Scope:         token.Scope,
+	}, nil
+}
+
+// hashAPIToken 计算API令牌的sha256哈希
+func hashAPIToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"timelocker-backend/internal/repository/apitoken"
 	"timelocker-backend/internal/repository/safe"
 	"timelocker-backend/internal/repository/user"
 	"timelocker-backend/internal/service/scanner"
@@ -20,7 +21,6 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"gorm.io/gorm"
 )
 
@@ -33,6 +33,8 @@ var (
 	ErrSignatureRecovery = errors.New("failed to recover address from signature")
 	ErrInvalidNonce      = errors.New("invalid or expired nonce")
 	ErrNonceUsed         = errors.New("nonce already used")
+	ErrAPITokenNotFound  = errors.New("api token not found")
+	ErrNotSafeOwner      = errors.New("signer is not a current owner of the safe")
 )
 
 // Service 认证服务接口 - 支持链切换
@@ -42,21 +44,36 @@ type Service interface {
 	RefreshToken(ctx context.Context, req *types.RefreshTokenRequest) (*types.WalletConnectResponse, error)
 	GetProfile(ctx context.Context, walletAddress string) (*types.UserProfile, error)
 	VerifyToken(ctx context.Context, tokenString string) (*types.JWTClaims, error)
+
+	// API令牌（脚本/集成方的只读访问）
+	CreateAPIToken(ctx context.Context, userID int64, walletAddress string, req *types.CreateAPITokenRequest) (*types.CreateAPITokenResponse, error)
+	ListAPITokens(ctx context.Context, userID int64) (*types.APITokenListResponse, error)
+	RevokeAPIToken(ctx context.Context, userID int64, tokenID int64) error
+	VerifyAPIToken(ctx context.Context, rawToken string) (*types.JWTClaims, error)
+}
+
+// contractReader 校验合约钱包签名、读取 Safe 信息所需的链上只读调用（由 RPCManager 实现，测试中可替换）
+type contractReader interface {
+	ContractCode(ctx context.Context, chainID int, address common.Address) ([]byte, error)
+	CallContract(ctx context.Context, chainID int, msg ethereum.CallMsg) ([]byte, error)
+	BalanceAt(ctx context.Context, chainID int, address common.Address) (*big.Int, error)
 }
 
 type service struct {
-	userRepo   user.Repository
-	safeRepo   safe.Repository
-	rpcManager *scanner.RPCManager
-	jwtManager *utils.JWTManager
+	userRepo     user.Repository
+	safeRepo     safe.Repository
+	apiTokenRepo apitoken.Repository
+	contracts    contractReader
+	jwtManager   *utils.JWTManager
 }
 
-func NewService(userRepo user.Repository, safeRepo safe.Repository, rpcManager *scanner.RPCManager, jwtManager *utils.JWTManager) Service {
+func NewService(userRepo user.Repository, safeRepo safe.Repository, apiTokenRepo apitoken.Repository, rpcManager *scanner.RPCManager, jwtManager *utils.JWTManager) Service {
 	return &service{
-		userRepo:   userRepo,
-		safeRepo:   safeRepo,
-		rpcManager: rpcManager,
-		jwtManager: jwtManager,
+		userRepo:     userRepo,
+		safeRepo:     safeRepo,
+		apiTokenRepo: apiTokenRepo,
+		contracts:    rpcManager,
+		jwtManager:   jwtManager,
 	}
 }
 
@@ -125,14 +142,46 @@ func (s *service) WalletConnect(ctx context.Context, req *types.WalletConnectReq
 	var safeOwners *string
 
 	if req.WalletType == "safe" {
-		// Safe钱包验证：只需要验证地址是否为Safe合约
+		// Safe钱包验证：必须提供登录签名（Safe 合约签名按 EIP-1271 校验，或由当前所有者签名），不再接受未签名登录
 		logger.Info("Verifying Safe wallet", "safe_address", normalizedAddress, "chain_id", req.ChainID)
 
-		// 验证是否为Safe合约并获取Safe信息
-		safeInfo, err := s.getSafeInfo(ctx, normalizedAddress, req.ChainID)
-		if err != nil {
-			logger.Error("Failed to verify Safe contract or get Safe info", err)
-			return nil, fmt.Errorf("address is not a valid Safe contract: %w", err)
+		if req.Signature == "" || req.Nonce == "" || req.Message == "" {
+			return nil, fmt.Errorf("signed Safe login requires signature, nonce and message")
+		}
+		if err := s.validateAndUseNonce(ctx, normalizedAddress, req.Nonce, req.Message); err != nil {
+			logger.Error("WalletConnect nonce validation failed", err)
+			return nil, err
+		}
+
+		var safeInfo *types.SafeInfo
+		if req.SignerAddress != "" {
+			// 由 Safe 所有者签名登录：校验所有者签名，并从链上读取最新的所有者集合确认其仍为所有者
+			if !crypto.ValidateEthereumAddress(req.SignerAddress) {
+				return nil, ErrInvalidAddress
+			}
+			if err := s.verifyWalletSignature(ctx, req.ChainID, req.SignerAddress, req.Message, req.Signature); err != nil {
+				logger.Error("WalletConnect Error: ", err, "safe_address", normalizedAddress, "signer", req.SignerAddress)
+				return nil, err
+			}
+			info, err := s.verifySafeOwner(ctx, normalizedAddress, req.ChainID, req.SignerAddress)
+			if err != nil {
+				return nil, err
+			}
+			safeInfo = info
+		} else {
+			// 按 EIP-1271 校验 Safe 对登录消息的签名（由 Safe 合约按当前所有者与阈值判定）
+			if err := s.verifyWalletSignature(ctx, req.ChainID, req.WalletAddress, req.Message, req.Signature); err != nil {
+				logger.Error("WalletConnect Error: ", err, "safe_address", normalizedAddress)
+				return nil, err
+			}
+
+			// 验证是否为Safe合约并获取Safe信息
+			info, err := s.getSafeInfo(ctx, normalizedAddress, req.ChainID)
+			if err != nil {
+				logger.Error("Failed to verify Safe contract or get Safe info", err)
+				return nil, fmt.Errorf("address is not a valid Safe contract: %w", err)
+			}
+			safeInfo = info
 		}
 
 		isSafeWallet = true
@@ -158,20 +207,10 @@ func (s *service) WalletConnect(ctx context.Context, req *types.WalletConnectReq
 			return nil, err
 		}
 
-		// 验证签名
-		err := crypto.VerifySignature(req.Message, req.Signature, req.WalletAddress)
-		if err != nil {
-			// 尝试从签名中恢复地址进行二次验证
-			recoveredAddress, recoverErr := crypto.RecoverAddress(req.Message, req.Signature)
-			if recoverErr != nil {
-				logger.Error("WalletConnect Error: ", ErrSignatureRecovery, recoverErr)
-				return nil, fmt.Errorf("%w: %v", ErrSignatureRecovery, recoverErr)
-			}
-
-			if strings.ToLower(recoveredAddress) != normalizedAddress {
-				logger.Error("WalletConnect Error: ", ErrInvalidSignature)
-				return nil, fmt.Errorf("%w: signature does not match wallet address", ErrInvalidSignature)
-			}
+		// 验证签名（EOA 使用 ECDSA 恢复地址；指定 chain_id 时智能合约钱包走 EIP-1271）
+		if err := s.verifyWalletSignature(ctx, req.ChainID, req.WalletAddress, req.Message, req.Signature); err != nil {
+			logger.Error("WalletConnect Error: ", err, "wallet_address", normalizedAddress)
+			return nil, err
 		}
 	}
 
@@ -225,6 +264,7 @@ func (s *service) WalletConnect(ctx context.Context, req *types.WalletConnectReq
 	accessToken, refreshToken, expiresAt, err := s.jwtManager.GenerateTokens(
 		currentUser.ID,
 		currentUser.WalletAddress,
+		time.Now(),
 	)
 	if err != nil {
 		logger.Error("WalletConnect Error: ", errors.New("failed to generate jwt tokens"), "error: ", err)
@@ -265,10 +305,15 @@ func (s *service) RefreshToken(ctx context.Context, req *types.RefreshTokenReque
 		return nil, errors.New("user account is disabled")
 	}
 
-	// 4. 生成新的令牌对
+	// 4. 生成新的令牌对（沿用原登录时间，刷新不视为重新认证）
+	var authTime time.Time
+	if claims.AuthTime > 0 {
+		authTime = time.Unix(claims.AuthTime, 0)
+	}
 	accessToken, refreshToken, expiresAt, err := s.jwtManager.GenerateTokens(
 		user.ID,
 		user.WalletAddress,
+		authTime,
 	)
 	if err != nil {
 		logger.Error("RefreshToken Error: ", errors.New("failed to generate jwt tokens"), "error: ", err)
@@ -373,17 +418,7 @@ func (s *service) getSafeInfo(ctx context.Context, safeAddress string, chainID i
 
 	// 从链上获取Safe信息，复用Safe服务的方法
 	logger.Info("Fetching Safe info from blockchain", "safe_address", normalizedAddress, "chain_id", chainID)
-	var safeInfo *types.SafeInfo
-
-	err = s.rpcManager.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
-		info, err := s.getSafeInfoFromContract(ctx, client, normalizedAddress, chainID)
-		if err != nil {
-			return err
-		}
-		safeInfo = info
-		return nil
-	})
-
+	safeInfo, err := s.getSafeInfoFromContract(ctx, normalizedAddress, chainID)
 	if err != nil {
 		logger.Error("Failed to get Safe info from contract", err)
 		return nil, fmt.Errorf("failed to get Safe info from contract: %w", err)
@@ -399,10 +434,10 @@ func (s *service) getSafeInfo(ctx context.Context, safeAddress string, chainID i
 }
 
 // getSafeInfoFromContract 从合约获取Safe信息
-func (s *service) getSafeInfoFromContract(ctx context.Context, client *ethclient.Client, address string, chainID int) (*types.SafeInfo, error) {
+func (s *service) getSafeInfoFromContract(ctx context.Context, address string, chainID int) (*types.SafeInfo, error) {
 	contractAddr := common.HexToAddress(address)
 	// 检查合约代码
-	code, err := client.CodeAt(ctx, contractAddr, nil)
+	code, err := s.contracts.ContractCode(ctx, chainID, contractAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -430,10 +465,10 @@ func (s *service) getSafeInfoFromContract(ctx context.Context, client *ethclient
 		return nil, err
 	}
 
-	thresholdResult, err := client.CallContract(ctx, ethereum.CallMsg{
+	thresholdResult, err := s.contracts.CallContract(ctx, chainID, ethereum.CallMsg{
 		To:   &contractAddr,
 		Data: thresholdData,
-	}, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -449,10 +484,10 @@ func (s *service) getSafeInfoFromContract(ctx context.Context, client *ethclient
 		return nil, err
 	}
 
-	ownersResult, err := client.CallContract(ctx, ethereum.CallMsg{
+	ownersResult, err := s.contracts.CallContract(ctx, chainID, ethereum.CallMsg{
 		To:   &contractAddr,
 		Data: ownersData,
-	}, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -468,10 +503,10 @@ func (s *service) getSafeInfoFromContract(ctx context.Context, client *ethclient
 		return nil, err
 	}
 
-	nonceResult, err := client.CallContract(ctx, ethereum.CallMsg{
+	nonceResult, err := s.contracts.CallContract(ctx, chainID, ethereum.CallMsg{
 		To:   &contractAddr,
 		Data: nonceData,
-	}, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -485,17 +520,17 @@ func (s *service) getSafeInfoFromContract(ctx context.Context, client *ethclient
 	var version string = "unknown"
 	versionData, err := parsedABI.Pack("VERSION")
 	if err == nil {
-		versionResult, err := client.CallContract(ctx, ethereum.CallMsg{
+		versionResult, err := s.contracts.CallContract(ctx, chainID, ethereum.CallMsg{
 			To:   &contractAddr,
 			Data: versionData,
-		}, nil)
+		})
 		if err == nil {
 			parsedABI.UnpackIntoInterface(&version, "VERSION", versionResult)
 		}
 	}
 
 	// 获取余额
-	balance, err := client.BalanceAt(ctx, contractAddr, nil)
+	balance, err := s.contracts.BalanceAt(ctx, chainID, contractAddr)
 	if err != nil {
 		balance = big.NewInt(0)
 	}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// eip1271MagicValue isValidSignature(bytes32,bytes) 校验通过时返回的值
+var eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+// eip1271ABI EIP-1271 标准接口
+const eip1271ABI = `[{"constant":true,"inputs":[{"name":"_hash","type":"bytes32"},{"name":"_signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"name":"","type":"bytes4"}],"type":"function"}]`
+
+// verifyWalletSignature 校验登录签名：先按 EOA 的 ECDSA 签名恢复地址，
+// 不匹配且指定了链时，若钱包地址是合约（Safe 等智能合约钱包）则调用 EIP-1271 isValidSignature 校验
+func (s *service) verifyWalletSignature(ctx context.Context, chainID int, walletAddress, message, signature string) error {
+	normalizedAddress := crypto.NormalizeAddress(walletAddress)
+
+	recoveredAddress, recoverErr := crypto.RecoverAddress(message, signature)
+	if recoverErr == nil && strings.ToLower(recoveredAddress) == normalizedAddress {
+		return nil
+	}
+
+	if chainID <= 0 {
+		if recoverErr != nil {
+			return fmt.Errorf("%w: %v", ErrSignatureRecovery, recoverErr)
+		}
+		return fmt.Errorf("%w: signature does not match wallet address", ErrInvalidSignature)
+	}
+
+	valid, isContract, err := s.isValidContractSignature(ctx, chainID, normalizedAddress, message, signature)
+	if err != nil {
+		return err
+	}
+	if !isContract {
+		if recoverErr != nil {
+			return fmt.Errorf("%w: %v", ErrSignatureRecovery, recoverErr)
+		}
+		return fmt.Errorf("%w: signature does not match wallet address", ErrInvalidSignature)
+	}
+	if !valid {
+		return fmt.Errorf("%w: contract wallet rejected signature (EIP-1271)", ErrInvalidSignature)
+	}
+
+	logger.Info("Contract wallet signature verified via EIP-1271", "wallet_address", normalizedAddress, "chain_id", chainID)
+	return nil
+}
+
+// isValidContractSignature 通过 EIP-1271 校验合约钱包签名，返回 (签名是否有效, 地址是否为合约)
+// 消息哈希与 EOA 签名一致（EIP-191 personal_sign），isValidSignature revert 视为签名无效
+func (s *service) isValidContractSignature(ctx context.Context, chainID int, walletAddress, message, signature string) (bool, bool, error) {
+	signatureBytes, err := hexutil.Decode(signature)
+	if err != nil {
+		return false, false, fmt.Errorf("%w: invalid signature hex", ErrInvalidSignature)
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(eip1271ABI))
+	if err != nil {
+		return false, false, err
+	}
+	var hash [32]byte
+	copy(hash[:], accounts.TextHash([]byte(message)))
+	callData, err := parsedABI.Pack("isValidSignature", hash, signatureBytes)
+	if err != nil {
+		return false, false, err
+	}
+
+	contractAddr := common.HexToAddress(walletAddress)
+	code, err := s.contracts.ContractCode(ctx, chainID, contractAddr)
+	if err != nil {
+		logger.Error("EIP-1271 signature check failed", err, "wallet_address", walletAddress, "chain_id", chainID)
+		return false, false, fmt.Errorf("failed to verify contract wallet signature: %w", err)
+	}
+	if len(code) == 0 {
+		return false, false, nil
+	}
+
+	result, err := s.contracts.CallContract(ctx, chainID, ethereum.CallMsg{To: &contractAddr, Data: callData})
+	if err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return false, true, nil // revert 视为签名无效
+		}
+		logger.Error("EIP-1271 signature check failed", err, "wallet_address", walletAddress, "chain_id", chainID)
+		return false, true, fmt.Errorf("failed to verify contract wallet signature: %w", err)
+	}
+	// bytes4 返回值按 32 字节左对齐编码
+	valid := len(result) >= 4 && bytes.Equal(result[:4], eip1271MagicValue)
+	return valid, true, nil
+}
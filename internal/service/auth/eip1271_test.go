@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const testLoginMessage = "Sign in to TimeLocker\nNonce: 42"
+
+// fakeContractReader 按地址返回合约代码，isValidSignature 调用返回固定结果或错误
+type fakeContractReader struct {
+	code      map[common.Address][]byte
+	result    []byte
+	callErr   error
+	codeErr   error
+	callCount int
+	lastCall  ethereum.CallMsg
+}
+
+func (r *fakeContractReader) ContractCode(ctx context.Context, chainID int, address common.Address) ([]byte, error) {
+	if r.codeErr != nil {
+		return nil, r.codeErr
+	}
+	return r.code[address], nil
+}
+
+func (r *fakeContractReader) CallContract(ctx context.Context, chainID int, msg ethereum.CallMsg) ([]byte, error) {
+	r.callCount++
+	r.lastCall = msg
+	return r.result, r.callErr
+}
+
+func (r *fakeContractReader) BalanceAt(ctx context.Context, chainID int, address common.Address) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+// fakeRevertError 模拟节点返回的 execution reverted（实现 rpc.Error）
+type fakeRevertError struct{}
+
+func (fakeRevertError) Error() string  { return "execution reverted" }
+func (fakeRevertError) ErrorCode() int { return 3 }
+
+// signLogin 以 personal_sign 方式签名，v 使用 27/28
+func signLogin(t *testing.T, message string) (string, string) {
+	t.Helper()
+	key, err := ethCrypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	sig, err := ethCrypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	sig[64] += 27
+	return ethCrypto.PubkeyToAddress(key.PublicKey).Hex(), hexutil.Encode(sig)
+}
+
+func TestVerifyWalletSignature(t *testing.T) {
+	eoa, eoaSig := signLogin(t, testLoginMessage)
+	_, otherSig := signLogin(t, testLoginMessage)
+	wallet := common.HexToAddress("0x5afe5afe5afe5afe5afe5afe5afe5afe5afe5afe")
+	magic := common.RightPadBytes(eip1271MagicValue, 32)
+
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		chainID   int
+		reader    *fakeContractReader
+		wantErr   error
+		wantCalls int
+	}{
+		{"eoa signature recovers without rpc", eoa, eoaSig, 1, &fakeContractReader{}, nil, 0},
+		{"eoa mismatch without chain", eoa, otherSig, 0, &fakeContractReader{}, ErrInvalidSignature, 0},
+		{"contract wallet returns magic value", wallet.Hex(), otherSig, 1, &fakeContractReader{code: map[common.Address][]byte{wallet: {0x60}}, result: magic}, nil, 1},
+		{"contract wallet returns other value", wallet.Hex(), otherSig, 1, &fakeContractReader{code: map[common.Address][]byte{wallet: {0x60}}, result: make([]byte, 32)}, ErrInvalidSignature, 1},
+		{"contract wallet reverts", wallet.Hex(), otherSig, 1, &fakeContractReader{code: map[common.Address][]byte{wallet: {0x60}}, callErr: fakeRevertError{}}, ErrInvalidSignature, 1},
+		{"address without code is not a contract", wallet.Hex(), otherSig, 1, &fakeContractReader{}, ErrInvalidSignature, 0},
+		{"unrecoverable signature without code", wallet.Hex(), "0x1234", 1, &fakeContractReader{}, ErrSignatureRecovery, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{contracts: tt.reader}
+			err := s.verifyWalletSignature(context.Background(), tt.chainID, tt.address, testLoginMessage, tt.signature)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("verifyWalletSignature: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.reader.callCount != tt.wantCalls {
+				t.Fatalf("isValidSignature called %d times, want %d", tt.reader.callCount, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIsValidContractSignatureCallData(t *testing.T) {
+	wallet := common.HexToAddress("0x5afe5afe5afe5afe5afe5afe5afe5afe5afe5afe")
+	reader := &fakeContractReader{code: map[common.Address][]byte{wallet: {0x60}}, result: common.RightPadBytes(eip1271MagicValue, 32)}
+	s := &service{contracts: reader}
+
+	valid, isContract, err := s.isValidContractSignature(context.Background(), 1, wallet.Hex(), testLoginMessage, "0xabcd")
+	if err != nil || !valid || !isContract {
+		t.Fatalf("isValidContractSignature = %v, %v, %v", valid, isContract, err)
+	}
+	// isValidSignature(bytes32,bytes) 的 selector（即 magic value 0x1626ba7e）后接 EIP-191 消息哈希
+	data := reader.lastCall.Data
+	if *reader.lastCall.To != wallet || !bytes.Equal(data[:4], eip1271MagicValue) || !bytes.Equal(data[4:36], accounts.TextHash([]byte(testLoginMessage))) {
+		t.Fatalf("unexpected call to %s data %x", reader.lastCall.To.Hex(), data)
+	}
+
+	// 节点连接失败等非 revert 错误向上返回，不当作签名无效
+	reader.callErr = errors.New("connection refused")
+	if _, _, err := s.isValidContractSignature(context.Background(), 1, wallet.Hex(), testLoginMessage, "0xabcd"); err == nil || errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("error = %v, want rpc failure", err)
+	}
+	reader.codeErr = errors.New("connection refused")
+	if _, _, err := s.isValidContractSignature(context.Background(), 1, wallet.Hex(), testLoginMessage, "0xabcd"); err == nil {
+		t.Fatal("expected error when eth_getCode fails")
+	}
+}
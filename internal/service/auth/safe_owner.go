@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+)
+
+// verifySafeOwner 从链上读取 Safe 当前的所有者与阈值（忽略数据库缓存）并写回 safe_wallets，
+// 签名者不在当前所有者中时拒绝登录
+func (s *service) verifySafeOwner(ctx context.Context, safeAddress string, chainID int, signerAddress string) (*types.SafeInfo, error) {
+	safeInfo, err := s.getSafeInfoFromContract(ctx, safeAddress, chainID)
+	if err != nil {
+		logger.Error("Failed to get Safe info from contract", err, "safe_address", safeAddress, "chain_id", chainID)
+		return nil, fmt.Errorf("address is not a valid Safe contract: %w", err)
+	}
+
+	// 无论是否通过都刷新缓存，保证已移除的所有者不会继续出现在数据库中
+	if err := s.syncSafeInfoToDB(ctx, safeInfo); err != nil {
+		logger.Error("Failed to sync Safe info to database", err, "safe_address", safeAddress)
+	}
+
+	if !isSafeOwner(safeInfo, signerAddress) {
+		logger.Warn("Safe login rejected: signer is not a current owner", "safe_address", safeAddress, "chain_id", chainID, "signer", signerAddress)
+		return nil, ErrNotSafeOwner
+	}
+	return safeInfo, nil
+}
+
+// isSafeOwner 判断地址是否在 Safe 的所有者列表中
+func isSafeOwner(safeInfo *types.SafeInfo, address string) bool {
+	normalized := crypto.NormalizeAddress(address)
+	for _, owner := range safeInfo.Owners {
+		if strings.ToLower(owner.Address) == normalized {
+			return true
+		}
+	}
+	return false
+}
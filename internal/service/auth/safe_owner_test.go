@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"timelocker-backend/internal/repository/safe"
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testSafe         = common.HexToAddress("0x5afe000000000000000000000000000000000001")
+	testOwnerA       = common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	testOwnerB       = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	testRemovedOwner = common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+)
+
+// fakeSafeReader 模拟 Safe 合约的 getThreshold/getOwners/nonce/VERSION 调用
+type fakeSafeReader struct {
+	code      []byte
+	owners    []common.Address
+	threshold int64
+}
+
+func (r *fakeSafeReader) ContractCode(ctx context.Context, chainID int, address common.Address) ([]byte, error) {
+	return r.code, nil
+}
+
+func (r *fakeSafeReader) CallContract(ctx context.Context, chainID int, msg ethereum.CallMsg) ([]byte, error) {
+	newType := func(t string) abi.Type {
+		typ, _ := abi.NewType(t, "", nil)
+		return typ
+	}
+	switch common.Bytes2Hex(msg.Data[:4]) {
+	case "e75235b8": // getThreshold()
+		return abi.Arguments{{Type: newType("uint256")}}.Pack(big.NewInt(r.threshold))
+	case "a0e67e2b": // getOwners()
+		return abi.Arguments{{Type: newType("address[]")}}.Pack(r.owners)
+	case "affed0e0": // nonce()
+		return abi.Arguments{{Type: newType("uint256")}}.Pack(big.NewInt(7))
+	case "ffa1ad74": // VERSION()
+		return abi.Arguments{{Type: newType("string")}}.Pack("1.3.0")
+	}
+	return nil, errors.New("unexpected call")
+}
+
+func (r *fakeSafeReader) BalanceAt(ctx context.Context, chainID int, address common.Address) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+// fakeSafeRepo 记录写回 safe_wallets 的内容
+type fakeSafeRepo struct {
+	safe.Repository
+	saved *types.SafeWallet
+}
+
+func (r *fakeSafeRepo) CreateOrUpdateSafe(ctx context.Context, wallet *types.SafeWallet) error {
+	r.saved = wallet
+	return nil
+}
+
+func TestIsSafeOwner(t *testing.T) {
+	info := &types.SafeInfo{Owners: []types.SafeOwner{{Address: testOwnerA.Hex()}, {Address: testOwnerB.Hex()}}}
+	tests := []struct {
+		name    string
+		address string
+		want    bool
+	}{
+		{"checksummed owner", testOwnerA.Hex(), true},
+		{"lowercase owner", strings.ToLower(testOwnerB.Hex()), true},
+		{"uppercase hex owner", "0x" + strings.ToUpper(testOwnerB.Hex()[2:]), true},
+		{"not an owner", testRemovedOwner.Hex(), false},
+		{"empty address", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSafeOwner(info, tt.address); got != tt.want {
+				t.Fatalf("isSafeOwner(%q) = %v, want %v", tt.address, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySafeOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		signer  common.Address
+		code    []byte
+		wantErr error
+	}{
+		{"current owner", testOwnerB, []byte{0x60}, nil},
+		{"removed owner", testRemovedOwner, []byte{0x60}, ErrNotSafeOwner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSafeRepo{}
+			s := &service{
+				safeRepo:  repo,
+				contracts: &fakeSafeReader{code: tt.code, owners: []common.Address{testOwnerA, testOwnerB}, threshold: 2},
+			}
+			info, err := s.verifySafeOwner(context.Background(), strings.ToLower(testSafe.Hex()), 1, strings.ToLower(tt.signer.Hex()))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || info != nil {
+					t.Fatalf("verifySafeOwner = %v, %v, want %v", info, err, tt.wantErr)
+				}
+			} else if err != nil || info.Threshold != 2 || len(info.Owners) != 2 || info.Version != "1.3.0" {
+				t.Fatalf("verifySafeOwner = %+v, %v", info, err)
+			}
+
+			// 无论是否通过都以链上当前所有者刷新缓存，已移除的所有者不再保留
+			if repo.saved == nil {
+				t.Fatal("safe info not synced to database")
+			}
+			var owners []types.SafeOwner
+			if err := json.Unmarshal([]byte(repo.saved.Owners), &owners); err != nil {
+				t.Fatalf("unmarshal saved owners: %v", err)
+			}
+			if len(owners) != 2 || owners[0].Address != testOwnerA.Hex() || owners[1].Address != testOwnerB.Hex() || repo.saved.Threshold != 2 {
+				t.Fatalf("saved safe = %+v", repo.saved)
+			}
+		})
+	}
+
+	// 地址没有合约代码时不是 Safe，不写缓存
+	repo := &fakeSafeRepo{}
+	s := &service{safeRepo: repo, contracts: &fakeSafeReader{}}
+	if _, err := s.verifySafeOwner(context.Background(), strings.ToLower(testSafe.Hex()), 1, testOwnerA.Hex()); err == nil || errors.Is(err, ErrNotSafeOwner) {
+		t.Fatalf("error = %v, want not a Safe contract", err)
+	}
+	if repo.saved != nil {
+		t.Fatal("non-contract address should not be synced")
+	}
+}
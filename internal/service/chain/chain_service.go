@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"timelocker-backend/internal/config"
 	"timelocker-backend/internal/repository/chain"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
@@ -15,17 +19,35 @@ type Service interface {
 	GetChainByChainID(ctx context.Context, chainID int64) (*types.SupportChainResponse, error)
 	GetWalletChainConfig(ctx context.Context, chainID int64) (*types.WalletChainConfig, error)
 	GetSubgraphURL(ctx context.Context, chainName string) (string, error)
+
+	// 区块浏览器 API Key（按链配置，未配置时回退默认 key）
+	GetExplorerAPIKey(ctx context.Context, chainID int64) (string, error)
+	ReportExplorerRateLimited(chainID int64, apiKey string)
+	LookupContractCreationTx(ctx context.Context, chainID int64, contractAddress string) (string, error)
+	SetExplorerAPIKey(ctx context.Context, chainID int64, apiKey string) (*types.ExplorerAPIKeyStatus, error)
+
+	// Webhook 事件确认深度（按链配置，0 表示收到即处理）
+	SetConfirmationDepth(ctx context.Context, chainID int64, depth int) (*types.ConfirmationDepthStatus, error)
 }
 
 // service 支持链服务实现
 type service struct {
-	chainRepo chain.Repository
+	chainRepo       chain.Repository
+	explorerConfig  config.ExplorerConfig
+	explorerClient  *http.Client
+	rateLimitedKeys sync.Map // api key -> 限流冷却截止时间
 }
 
 // NewService 创建新的支持链服务
-func NewService(chainRepo chain.Repository) Service {
+func NewService(chainRepo chain.Repository, explorerConfig config.ExplorerConfig) Service {
+	timeout := explorerConfig.RequestTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
 	return &service{
-		chainRepo: chainRepo,
+		chainRepo:      chainRepo,
+		explorerConfig: explorerConfig,
+		explorerClient: &http.Client{Timeout: timeout},
 	}
 }
 
@@ -0,0 +1,25 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// SetConfirmationDepth 设置链的 Webhook 事件确认深度；新值从下一条 webhook 事件开始生效
+func (s *service) SetConfirmationDepth(ctx context.Context, chainID int64, depth int) (*types.ConfirmationDepthStatus, error) {
+	if err := s.chainRepo.UpdateConfirmationDepth(ctx, chainID, depth); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChainNotFound
+		}
+		return nil, fmt.Errorf("failed to update confirmation depth: %w", err)
+	}
+
+	logger.Info("Chain confirmation depth updated", "chain_id", chainID, "confirmation_depth", depth)
+	return &types.ConfirmationDepthStatus{ChainID: chainID, ConfirmationDepth: depth}, nil
+}
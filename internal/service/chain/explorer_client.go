@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"timelocker-backend/pkg/logger"
+)
+
+// ErrExplorerAPINotConfigured 未配置区块浏览器 API 地址
+var ErrExplorerAPINotConfigured = errors.New("explorer api url not configured")
+
+// explorerResponse Etherscan 风格 API 的通用响应：status 为 "1" 表示成功，失败时 result 为错误说明
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// LookupContractCreationTx 通过区块浏览器（module=contract&action=getcontractcreation）查询合约的创建交易哈希
+// 浏览器没有该合约的记录时返回空字符串；key 缺失或处于限流冷却期时返回对应错误，收到限流响应时上报并暂停该 key
+func (s *service) LookupContractCreationTx(ctx context.Context, chainID int64, contractAddress string) (string, error) {
+	if s.explorerConfig.APIURL == "" {
+		return "", ErrExplorerAPINotConfigured
+	}
+	apiKey, err := s.GetExplorerAPIKey(ctx, chainID)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, err := url.Parse(s.explorerConfig.APIURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid explorer api url: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("chainid", strconv.FormatInt(chainID, 10))
+	query.Set("module", "contract")
+	query.Set("action", "getcontractcreation")
+	query.Set("contractaddresses", contractAddress)
+	query.Set("apikey", apiKey)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.explorerClient.Do(req)
+	if err != nil {
+		// url.Error 含带 apikey 的完整 URL，只保留底层错误
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("explorer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		s.ReportExplorerRateLimited(chainID, apiKey)
+		return "", ErrExplorerAPIKeyRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("explorer returned status %d", resp.StatusCode)
+	}
+
+	var body explorerResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse explorer response: %w", err)
+	}
+	if body.Status != "1" {
+		var reason string
+		_ = json.Unmarshal(body.Result, &reason)
+		if isExplorerRateLimitMessage(reason) || isExplorerRateLimitMessage(body.Message) {
+			s.ReportExplorerRateLimited(chainID, apiKey)
+			return "", ErrExplorerAPIKeyRateLimited
+		}
+		if strings.Contains(strings.ToLower(body.Message), "no data found") {
+			return "", nil
+		}
+		logger.Warn("Explorer getcontractcreation failed", "chain_id", chainID, "message", body.Message, "result", reason)
+		return "", fmt.Errorf("explorer error: %s %s", body.Message, reason)
+	}
+
+	var results []struct {
+		ContractAddress string `json:"contractAddress"`
+		TxHash          string `json:"txHash"`
+	}
+	if err := json.Unmarshal(body.Result, &results); err != nil {
+		return "", fmt.Errorf("failed to parse explorer result: %w", err)
+	}
+	for _, r := range results {
+		if strings.EqualFold(r.ContractAddress, contractAddress) {
+			return strings.ToLower(r.TxHash), nil
+		}
+	}
+	return "", nil
+}
+
+// isExplorerRateLimitMessage 判断浏览器返回的错误说明是否为限流（如 "Max rate limit reached"、"Max calls per sec rate limit reached"）
+func isExplorerRateLimitMessage(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "rate limit")
+}
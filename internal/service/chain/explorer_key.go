@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrChainNotFound               = errors.New("chain not found")
+	ErrExplorerAPIKeyNotConfigured = errors.New("explorer api key not configured")
+	ErrExplorerAPIKeyRateLimited   = errors.New("explorer api key is rate limited")
+)
+
+// GetExplorerAPIKey 获取链的区块浏览器 API Key：优先使用链上单独配置的 key，未配置时回退到默认 key
+// 两者都没有时返回 ErrExplorerAPIKeyNotConfigured；key 处于限流冷却期时返回 ErrExplorerAPIKeyRateLimited，调用方应跳过本次浏览器请求
+func (s *service) GetExplorerAPIKey(ctx context.Context, chainID int64) (string, error) {
+	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain info: %w", err)
+	}
+
+	key := selectExplorerAPIKey(chainInfo.ExplorerAPIKey, s.explorerConfig.DefaultAPIKey)
+	if key == "" {
+		return "", ErrExplorerAPIKeyNotConfigured
+	}
+	if until, limited := s.explorerRateLimitedUntil(key); limited {
+		return "", fmt.Errorf("%w until %s", ErrExplorerAPIKeyRateLimited, until.Format(time.RFC3339))
+	}
+	return key, nil
+}
+
+// ReportExplorerRateLimited 调用方收到浏览器限流响应时上报，key 在冷却期内不再返回
+func (s *service) ReportExplorerRateLimited(chainID int64, apiKey string) {
+	cooldown := s.explorerConfig.RateLimitCooldown
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	until := time.Now().Add(cooldown)
+	s.rateLimitedKeys.Store(apiKey, until)
+	logger.Warn("Explorer API key rate limited, pausing usage", "chain_id", chainID, "key", utils.MaskSecret(apiKey), "until", until)
+}
+
+// SetExplorerAPIKey 设置/轮换链的区块浏览器 API Key，apiKey 为空时清除（回退到默认 key）
+func (s *service) SetExplorerAPIKey(ctx context.Context, chainID int64, apiKey string) (*types.ExplorerAPIKeyStatus, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if err := s.chainRepo.UpdateExplorerAPIKey(ctx, chainID, apiKey); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChainNotFound
+		}
+		return nil, fmt.Errorf("failed to update explorer api key: %w", err)
+	}
+	if apiKey != "" {
+		s.rateLimitedKeys.Delete(apiKey) // 新 key 不继承旧的限流状态
+	}
+
+	logger.Info("Explorer API key updated", "chain_id", chainID, "configured", apiKey != "")
+	return &types.ExplorerAPIKeyStatus{
+		ChainID:         chainID,
+		Configured:      apiKey != "",
+		MaskedKey:       utils.MaskSecret(apiKey),
+		UsingDefaultKey: apiKey == "" && s.explorerConfig.DefaultAPIKey != "",
+	}, nil
+}
+
+// explorerRateLimitedUntil 判断 key 是否处于限流冷却期
+func (s *service) explorerRateLimitedUntil(apiKey string) (time.Time, bool) {
+	value, ok := s.rateLimitedKeys.Load(apiKey)
+	if !ok {
+		return time.Time{}, false
+	}
+	until := value.(time.Time)
+	if time.Now().After(until) {
+		s.rateLimitedKeys.Delete(apiKey)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// selectExplorerAPIKey 链单独配置的 key 优先，其次默认 key
+func selectExplorerAPIKey(chainKey, defaultKey string) string {
+	if key := strings.TrimSpace(chainKey); key != "" {
+		return key
+	}
+	return strings.TrimSpace(defaultKey)
+}
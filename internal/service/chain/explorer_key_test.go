@@ -0,0 +1,157 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/chain"
+	"timelocker-backend/internal/types"
+)
+
+// fakeExplorerChainRepo 只实现 GetChainByChainID 与 UpdateExplorerAPIKey，按链返回单独配置的 key
+type fakeExplorerChainRepo struct {
+	chain.Repository
+	keys map[int64]string
+}
+
+func (r fakeExplorerChainRepo) GetChainByChainID(ctx context.Context, chainID int64) (*types.SupportChain, error) {
+	key, ok := r.keys[chainID]
+	if !ok {
+		return nil, fmt.Errorf("chain not found")
+	}
+	return &types.SupportChain{ChainID: chainID, ExplorerAPIKey: key}, nil
+}
+
+func (r fakeExplorerChainRepo) UpdateExplorerAPIKey(ctx context.Context, chainID int64, apiKey string) error {
+	return nil
+}
+
+func TestSelectExplorerAPIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		chainKey   string
+		defaultKey string
+		want       string
+	}{
+		{"chain key wins", "chain-key", "default-key", "chain-key"},
+		{"falls back to default", "", "default-key", "default-key"},
+		{"blank chain key falls back", "   ", "default-key", "default-key"},
+		{"keys are trimmed", " chain-key ", "", "chain-key"},
+		{"nothing configured", "", " ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectExplorerAPIKey(tt.chainKey, tt.defaultKey); got != tt.want {
+				t.Fatalf("selectExplorerAPIKey(%q, %q) = %q, want %q", tt.chainKey, tt.defaultKey, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetExplorerAPIKey(t *testing.T) {
+	repo := fakeExplorerChainRepo{keys: map[int64]string{1: "chain-key", 10: ""}}
+	tests := []struct {
+		name       string
+		chainID    int64
+		defaultKey string
+		want       string
+		wantErr    error
+	}{
+		{"per-chain key", 1, "default-key", "chain-key", nil},
+		{"default key", 10, "default-key", "default-key", nil},
+		{"not configured", 10, "", "", ErrExplorerAPIKeyNotConfigured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(repo, config.ExplorerConfig{DefaultAPIKey: tt.defaultKey}).(*service)
+			got, err := s.GetExplorerAPIKey(context.Background(), tt.chainID)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Fatalf("GetExplorerAPIKey = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewService(repo, config.ExplorerConfig{}).GetExplorerAPIKey(context.Background(), 999); err == nil {
+		t.Fatal("expected error for unknown chain")
+	}
+}
+
+func TestExplorerRateLimitCooldown(t *testing.T) {
+	repo := fakeExplorerChainRepo{keys: map[int64]string{1: "chain-key", 10: ""}}
+	s := NewService(repo, config.ExplorerConfig{DefaultAPIKey: "default-key", RateLimitCooldown: 50 * time.Millisecond}).(*service)
+	ctx := context.Background()
+
+	s.ReportExplorerRateLimited(1, "chain-key")
+	if _, err := s.GetExplorerAPIKey(ctx, 1); !errors.Is(err, ErrExplorerAPIKeyRateLimited) {
+		t.Fatalf("error = %v, want ErrExplorerAPIKeyRateLimited", err)
+	}
+	// 限流按 key 记录，其他链使用的默认 key 不受影响
+	if key, err := s.GetExplorerAPIKey(ctx, 10); err != nil || key != "default-key" {
+		t.Fatalf("GetExplorerAPIKey(10) = %q, %v", key, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if key, err := s.GetExplorerAPIKey(ctx, 1); err != nil || key != "chain-key" {
+		t.Fatalf("after cooldown GetExplorerAPIKey = %q, %v", key, err)
+	}
+
+	// 轮换为新 key 时清除其限流状态
+	s.ReportExplorerRateLimited(1, "new-key")
+	if _, err := s.SetExplorerAPIKey(ctx, 1, "new-key"); err != nil {
+		t.Fatalf("SetExplorerAPIKey: %v", err)
+	}
+	if _, limited := s.explorerRateLimitedUntil("new-key"); limited {
+		t.Fatal("rotated key still rate limited")
+	}
+}
+
+func TestLookupContractCreationTx(t *testing.T) {
+	const contract = "0x2222222222222222222222222222222222222222"
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		want        string
+		wantErr     error
+		wantLimited bool
+	}{
+		{"found", http.StatusOK, `{"status":"1","message":"OK","result":[{"contractAddress":"0x2222222222222222222222222222222222222222","contractCreator":"0x1111111111111111111111111111111111111111","txHash":"0xABCD"}]}`, "0xabcd", nil, false},
+		{"no data", http.StatusOK, `{"status":"0","message":"No data found","result":[]}`, "", nil, false},
+		{"rate limited response", http.StatusOK, `{"status":"0","message":"NOTOK","result":"Max calls per sec rate limit reached (5/sec)"}`, "", ErrExplorerAPIKeyRateLimited, true},
+		{"http 429", http.StatusTooManyRequests, ``, "", ErrExplorerAPIKeyRateLimited, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if q.Get("chainid") != "1" || q.Get("action") != "getcontractcreation" || q.Get("contractaddresses") != contract || q.Get("apikey") != "chain-key" {
+					t.Errorf("unexpected query %s", r.URL.RawQuery)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			repo := fakeExplorerChainRepo{keys: map[int64]string{1: "chain-key"}}
+			s := NewService(repo, config.ExplorerConfig{APIURL: srv.URL, RateLimitCooldown: time.Minute}).(*service)
+			got, err := s.LookupContractCreationTx(context.Background(), 1, contract)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Fatalf("LookupContractCreationTx = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+			if _, limited := s.explorerRateLimitedUntil("chain-key"); limited != tt.wantLimited {
+				t.Fatalf("key rate limited = %v, want %v", limited, tt.wantLimited)
+			}
+		})
+	}
+
+	s := NewService(fakeExplorerChainRepo{keys: map[int64]string{1: "chain-key"}}, config.ExplorerConfig{}).(*service)
+	if _, err := s.LookupContractCreationTx(context.Background(), 1, contract); !errors.Is(err, ErrExplorerAPINotConfigured) {
+		t.Fatalf("error = %v, want ErrExplorerAPINotConfigured", err)
+	}
+}
@@ -0,0 +1,142 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"timelocker-backend/internal/repository/audit"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserEmailNotFound   = errors.New("user email not found")
+	ErrInvalidNotifyStatus = errors.New("invalid notify status")
+)
+
+// notifyStatusOrder 可订阅的通知状态（按流程生命周期排序，存储时保持该顺序）
+var notifyStatusOrder = []string{"waiting", "ready", "executed", "cancelled", "expired"}
+
+// UpdateEmailNotifyStatuses 设置邮箱接收通知的目标状态，返回规范化后的列表（为空表示接收全部）
+func (s *emailService) UpdateEmailNotifyStatuses(ctx context.Context, userEmailID int64, userID int64, statuses []string) ([]string, error) {
+	normalized, err := normalizeNotifyStatuses(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *string
+	if len(normalized) > 0 {
+		data, err := json.Marshal(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode notify statuses: %w", err)
+		}
+		str := string(data)
+		stored = &str
+	}
+
+	auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetEmail, strconv.FormatInt(userEmailID, 10),
+		fmt.Sprintf("updated notify_statuses=%v", normalized))
+	if err := s.repo.UpdateUserEmailNotifyStatuses(auditCtx, userEmailID, userID, stored); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to update email notify statuses: %w", err)
+	}
+
+	logger.Info("Email notify statuses updated", "userEmailID", userEmailID, "userID", userID, "statuses", normalized)
+	return normalized, nil
+}
+
+// normalizeNotifyStatuses 校验、去重并按生命周期排序；包含全部状态时等同于不过滤
+func normalizeNotifyStatuses(statuses []string) ([]string, error) {
+	selected := make(map[string]bool, len(statuses))
+	for _, st := range statuses {
+		st = strings.ToLower(strings.TrimSpace(st))
+		valid := false
+		for _, known := range notifyStatusOrder {
+			if st == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNotifyStatus, st)
+		}
+		selected[st] = true
+	}
+	if len(selected) == len(notifyStatusOrder) {
+		return []string{}, nil
+	}
+
+	normalized := make([]string, 0, len(selected))
+	for _, st := range notifyStatusOrder {
+		if selected[st] {
+			normalized = append(normalized, st)
+		}
+	}
+	return normalized, nil
+}
+
+// decodeNotifyStatuses 解析存储的通知状态列表，NULL 或解析失败时返回空列表（接收全部）
+func decodeNotifyStatuses(stored *string) []string {
+	if stored == nil || *stored == "" {
+		return []string{}
+	}
+	var statuses []string
+	if err := json.Unmarshal([]byte(*stored), &statuses); err != nil {
+		logger.Warn("Failed to decode email notify statuses", "value", *stored, "error", err)
+		return []string{}
+	}
+	return statuses
+}
+
+// ExplainFlowNotification 按与 SendFlowNotification 相同的筛选逻辑（合约相关用户、邮箱已验证、notify_statuses、发送去重）
+// 判定用户自己的每个邮箱是否会收到该状态的通知，不发送也不写发送日志
+func (s *emailService) ExplainFlowNotification(ctx context.Context, userAddress, flowID, statusTo string, related bool) ([]types.WouldNotifyEmail, error) {
+	userEmails, err := s.repo.GetUserEmailsByWalletAddress(ctx, userAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]types.WouldNotifyEmail, 0, len(userEmails))
+	for _, ue := range userEmails {
+		result := types.WouldNotifyEmail{UserEmailID: ue.ID}
+		if ue.Email != nil {
+			result.Email = ue.Email.Email
+		}
+		switch {
+		case !related:
+			result.Reason = types.WouldNotifyReasonNotRelatedUser
+		case !ue.IsVerified:
+			result.Reason = types.WouldNotifyReasonEmailNotVerified
+		case !notifyStatusesAllow(ue.NotifyStatuses, statusTo):
+			result.Reason = types.WouldNotifyReasonStatusFiltered
+		default:
+			exists, err := s.repo.CheckSendLogExists(ctx, ue.EmailID, flowID, statusTo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check send log: %w", err)
+			}
+			if exists {
+				result.Reason = types.WouldNotifyReasonAlreadySent
+			} else {
+				result.WouldFire = true
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// notifyStatusesAllow 邮箱的 notify_statuses 是否接收该状态（与 GetContractRelatedVerifiedEmailIDs 的匹配规则一致）
+func notifyStatusesAllow(notifyStatuses *string, statusTo string) bool {
+	if notifyStatuses == nil {
+		return true
+	}
+	return strings.Contains(*notifyStatuses, "\""+strings.ToLower(statusTo)+"\"")
+}
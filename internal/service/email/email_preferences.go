@@ -0,0 +1,155 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"timelocker-backend/internal/repository/audit"
+	"timelocker-backend/internal/types"
+	emailPkg "timelocker-backend/pkg/email"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+)
+
+// maxFromNameLength 自定义发件人名称最大长度（字符数）
+const maxFromNameLength = 64
+
+var (
+	ErrInvalidCcEmail  = errors.New("invalid cc email")
+	ErrInvalidBccEmail = errors.New("invalid bcc email")
+	ErrInvalidFromName = errors.New("invalid from name")
+)
+
+// GetEmailPreferences 获取用户邮件偏好，未设置发件人名称时返回系统默认名称
+func (s *emailService) GetEmailPreferences(ctx context.Context, userID int64) (*types.EmailPreferencesResponse, error) {
+	pref, err := s.repo.GetUserEmailPreference(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get email preferences", err, "userID", userID)
+		return nil, err
+	}
+	return s.toEmailPreferencesResponse(pref), nil
+}
+
+// UpdateEmailPreferences 更新用户邮件偏好，字段为 nil 时保持不变，空字符串表示清除
+func (s *emailService) UpdateEmailPreferences(ctx context.Context, userID int64, req *types.UpdateEmailPreferencesRequest) (*types.EmailPreferencesResponse, error) {
+	pref, err := s.repo.GetUserEmailPreference(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get email preferences", err, "userID", userID)
+		return nil, err
+	}
+	if pref == nil {
+		pref = &types.UserEmailPreference{UserID: userID}
+	}
+
+	if req.CcEmail != nil {
+		cc, err := normalizeOptionalEmail(*req.CcEmail, ErrInvalidCcEmail)
+		if err != nil {
+			return nil, err
+		}
+		pref.CcEmail = cc
+	}
+	if req.BccEmail != nil {
+		bcc, err := normalizeOptionalEmail(*req.BccEmail, ErrInvalidBccEmail)
+		if err != nil {
+			return nil, err
+		}
+		pref.BccEmail = bcc
+	}
+	if req.FromName != nil {
+		name, err := normalizeFromName(*req.FromName)
+		if err != nil {
+			return nil, err
+		}
+		pref.FromName = name
+	}
+
+	auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetEmail, "preferences", "updated email preferences")
+	if err := s.repo.UpsertUserEmailPreference(auditCtx, pref); err != nil {
+		logger.Error("Failed to update email preferences", err, "userID", userID)
+		return nil, err
+	}
+
+	logger.Info("Email preferences updated", "userID", userID)
+	return s.toEmailPreferencesResponse(pref), nil
+}
+
+// buildSendOptions 根据收件邮箱所属用户的偏好构建发送参数
+// 同一事件中相同的抄送/密送地址只投递一次
+func (s *emailService) buildSendOptions(ctx context.Context, emailID int64, copied *sync.Map) emailPkg.SendOptions {
+	var opts emailPkg.SendOptions
+	pref, err := s.repo.GetEmailPreferenceByEmailID(ctx, emailID)
+	if err != nil {
+		logger.Warn("Failed to get email preference, using defaults", "emailID", emailID, "error", err)
+		return opts
+	}
+	if pref == nil {
+		return opts
+	}
+
+	if pref.FromName != nil {
+		opts.FromName = *pref.FromName
+	}
+	if pref.CcEmail != nil {
+		if _, loaded := copied.LoadOrStore(*pref.CcEmail, true); !loaded {
+			opts.Cc = *pref.CcEmail
+		}
+	}
+	if pref.BccEmail != nil {
+		if _, loaded := copied.LoadOrStore(*pref.BccEmail, true); !loaded {
+			opts.Bcc = *pref.BccEmail
+		}
+	}
+	return opts
+}
+
+// toEmailPreferencesResponse 转换为响应结构
+func (s *emailService) toEmailPreferencesResponse(pref *types.UserEmailPreference) *types.EmailPreferencesResponse {
+	resp := &types.EmailPreferencesResponse{FromName: s.config.Email.FromName}
+	if pref == nil {
+		return resp
+	}
+	if pref.CcEmail != nil {
+		resp.CcEmail = *pref.CcEmail
+	}
+	if pref.BccEmail != nil {
+		resp.BccEmail = *pref.BccEmail
+	}
+	if pref.FromName != nil {
+		resp.FromName = *pref.FromName
+	}
+	return resp
+}
+
+// normalizeOptionalEmail 校验并规范化可选邮箱，空字符串返回 nil
+func normalizeOptionalEmail(v string, invalidErr error) (*string, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v == "" {
+		return nil, nil
+	}
+	if !utils.IsValidEmail(v) {
+		return nil, fmt.Errorf("%w: %s", invalidErr, v)
+	}
+	return &v, nil
+}
+
+// normalizeFromName 校验发件人名称：不超过 maxFromNameLength 个字符且不含控制字符，空字符串返回 nil（使用默认名称）
+func normalizeFromName(v string) (*string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	if !utf8.ValidString(v) || utf8.RuneCountInString(v) > maxFromNameLength {
+		return nil, fmt.Errorf("%w: must be at most %d characters", ErrInvalidFromName, maxFromNameLength)
+	}
+	for _, r := range v {
+		if unicode.IsControl(r) {
+			return nil, fmt.Errorf("%w: must not contain control characters", ErrInvalidFromName)
+		}
+	}
+	return &v, nil
+}
@@ -6,16 +6,21 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"html"
 	"html/template"
 	"math/big"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/audit"
 	chainRepo "timelocker-backend/internal/repository/chain"
 	emailRepo "timelocker-backend/internal/repository/email"
 	goldskyRepo "timelocker-backend/internal/repository/goldsky"
 	timeLockRepo "timelocker-backend/internal/repository/timelock"
+	"timelocker-backend/internal/service/notification"
 	"timelocker-backend/internal/types"
 	emailPkg "timelocker-backend/pkg/email"
 	"timelocker-backend/pkg/logger"
@@ -33,18 +38,30 @@ type EmailService interface {
 	AddUserEmail(ctx context.Context, userID int64, emailAddr string, remark *string) (*types.UserEmailResponse, error)
 	GetUserEmails(ctx context.Context, userID int64, page, pageSize int) (*types.EmailListResponse, error)
 	UpdateEmailRemark(ctx context.Context, userEmailID int64, userID int64, remark *string) error
+	// 设置邮箱接收通知的目标状态，为空表示接收全部
+	UpdateEmailNotifyStatuses(ctx context.Context, userEmailID int64, userID int64, statuses []string) ([]string, error)
 	DeleteUserEmail(ctx context.Context, userEmailID int64, userID int64) error
 
 	// 邮箱验证
 	SendVerificationCode(ctx context.Context, userEmailID int64, userID int64) error
 	VerifyEmail(ctx context.Context, userEmailID int64, userID int64, code string) error
+	// 为用户全部未验证且可投递的邮箱重新发送验证码，返回每个邮箱的结果
+	ResendAllVerificationCodes(ctx context.Context, userID int64) (*types.ResendAllVerificationResponse, error)
 	// 基于 email 发送验证码（创建/复用未验证记录，允许备注更新）
 	SendVerificationCodeByEmail(ctx context.Context, userID int64, emailAddr string, remark *string) error
 	// 基于 email 校验验证码
 	VerifyEmailByEmail(ctx context.Context, userID int64, emailAddr string, code string) error
 
+	// 邮件偏好（抄送、密送与发件人名称）
+	GetEmailPreferences(ctx context.Context, userID int64) (*types.EmailPreferencesResponse, error)
+	UpdateEmailPreferences(ctx context.Context, userID int64, req *types.UpdateEmailPreferencesRequest) (*types.EmailPreferencesResponse, error)
+
 	// 通知发送
 	SendFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) error
+	// 按发送时的筛选逻辑判定用户自己的邮箱是否会收到通知（不发送），related 为用户是否为合约相关用户
+	ExplainFlowNotification(ctx context.Context, userAddress, flowID, statusTo string, related bool) ([]types.WouldNotifyEmail, error)
+	// 重发已到期的临时失败通知邮件，重试次数耗尽后标记为永久失败
+	RetryFailedSends(ctx context.Context) error
 
 	// 工具方法
 	CleanExpiredCodes(ctx context.Context) error
@@ -58,6 +75,10 @@ type emailService struct {
 	flowRepo     goldskyRepo.FlowRepository
 	config       *config.Config
 	sender       *emailPkg.SMTPSender
+
+	// resendAllAt 每个用户最近一次批量重发验证码的时间，用于接口限频
+	resendAllMu sync.Mutex
+	resendAllAt map[int64]time.Time
 }
 
 // NewEmailService 创建邮箱服务实例
@@ -69,6 +90,7 @@ func NewEmailService(repo emailRepo.EmailRepository, chainRepo chainRepo.Reposit
 		flowRepo:     flowRepo,
 		config:       cfg,
 		sender:       emailPkg.NewSMTPSender(&cfg.Email),
+		resendAllAt:  make(map[int64]time.Time),
 	}
 }
 
@@ -91,7 +113,8 @@ func (s *emailService) AddUserEmail(ctx context.Context, userID int64, emailAddr
 	}
 
 	// 添加用户邮箱关系
-	userEmail, err := s.repo.AddUserEmail(ctx, userID, emailRecord.ID, remark)
+	auditCtx := audit.WithLog(ctx, types.AuditActionCreate, types.AuditTargetEmail, "", "added email "+emailAddr)
+	userEmail, err := s.repo.AddUserEmail(auditCtx, userID, emailRecord.ID, remark)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add user email: %w", err)
 	}
@@ -102,16 +125,15 @@ func (s *emailService) AddUserEmail(ctx context.Context, userID int64, emailAddr
 		Remark:         remark,
 		IsVerified:     false,
 		LastVerifiedAt: nil,
+		NotifyStatuses: []string{},
 		CreatedAt:      userEmail.CreatedAt,
 	}, nil
 }
 
 // GetUserEmails 获取用户邮箱
 func (s *emailService) GetUserEmails(ctx context.Context, userID int64, page, pageSize int) (*types.EmailListResponse, error) {
+	page, pageSize = types.ClampPagination(page, pageSize, 10)
 	offset := (page - 1) * pageSize
-	if offset < 0 {
-		offset = 0
-	}
 
 	userEmails, total, err := s.repo.GetUserEmails(ctx, userID, offset, pageSize)
 	if err != nil {
@@ -127,13 +149,15 @@ func (s *emailService) GetUserEmails(ctx context.Context, userID int64, page, pa
 			Remark:         ue.Remark,
 			IsVerified:     ue.IsVerified,
 			LastVerifiedAt: ue.LastVerifiedAt,
+			NotifyStatuses: decodeNotifyStatuses(ue.NotifyStatuses),
 			CreatedAt:      ue.CreatedAt,
 		}
 	}
 
 	return &types.EmailListResponse{
-		Emails: emails,
-		Total:  total,
+		Emails:         emails,
+		Total:          total,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
 	}, nil
 }
 
@@ -146,7 +170,8 @@ func (s *emailService) UpdateEmailRemark(ctx context.Context, userEmailID int64,
 		}
 		remark = &trimmed
 	}
-	err := s.repo.UpdateUserEmailRemark(ctx, userEmailID, userID, remark)
+	auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetEmail, strconv.FormatInt(userEmailID, 10), "updated remark")
+	err := s.repo.UpdateUserEmailRemark(auditCtx, userEmailID, userID, remark)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fmt.Errorf("user email not found")
@@ -158,7 +183,8 @@ func (s *emailService) UpdateEmailRemark(ctx context.Context, userEmailID int64,
 
 // DeleteUserEmail 删除用户邮箱
 func (s *emailService) DeleteUserEmail(ctx context.Context, userEmailID int64, userID int64) error {
-	err := s.repo.DeleteUserEmail(ctx, userEmailID, userID)
+	auditCtx := audit.WithLog(ctx, types.AuditActionDelete, types.AuditTargetEmail, strconv.FormatInt(userEmailID, 10), "deleted email")
+	err := s.repo.DeleteUserEmail(auditCtx, userEmailID, userID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fmt.Errorf("user email not found")
@@ -241,7 +267,8 @@ func (s *emailService) SendVerificationCodeByEmail(ctx context.Context, userID i
 	userEmail, err := s.repo.GetUserEmailByUserAndEmailID(ctx, userID, emailRecord.ID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			if userEmail, err = s.repo.AddUserEmail(ctx, userID, emailRecord.ID, remark); err != nil {
+			auditCtx := audit.WithLog(ctx, types.AuditActionCreate, types.AuditTargetEmail, "", "added email "+emailAddr)
+			if userEmail, err = s.repo.AddUserEmail(auditCtx, userID, emailRecord.ID, remark); err != nil {
 				return fmt.Errorf("failed to add user email: %w", err)
 			}
 		} else {
@@ -251,7 +278,8 @@ func (s *emailService) SendVerificationCodeByEmail(ctx context.Context, userID i
 		if userEmail.IsVerified {
 			return fmt.Errorf("email already added by user")
 		}
-		if err := s.repo.UpdateUserEmailRemark(ctx, userEmail.ID, userID, remark); err != nil {
+		auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetEmail, strconv.FormatInt(userEmail.ID, 10), "updated remark")
+		if err := s.repo.UpdateUserEmailRemark(auditCtx, userEmail.ID, userID, remark); err != nil {
 			return fmt.Errorf("failed to update remark: %w", err)
 		}
 	}
@@ -293,7 +321,8 @@ func (s *emailService) VerifyEmail(ctx context.Context, userEmailID int64, userI
 	}
 
 	// 标记邮箱为已验证
-	if err := s.repo.VerifyUserEmail(ctx, userEmailID, userID); err != nil {
+	auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetEmail, strconv.FormatInt(userEmailID, 10), "verified email")
+	if err := s.repo.VerifyUserEmail(auditCtx, userEmailID, userID); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fmt.Errorf("user email not found")
 		}
@@ -310,8 +339,8 @@ func (s *emailService) VerifyEmail(ctx context.Context, userEmailID int64, userI
 func (s *emailService) SendFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) error {
 	start := time.Now()
 
-	// 获取与合约相关用户的已验证邮箱列表
-	emailIDs, err := s.repo.GetContractRelatedVerifiedEmailIDs(ctx, standard, chainID, contractAddress)
+	// 获取与合约相关用户的已验证邮箱列表（已按各邮箱的通知状态过滤）
+	emailIDs, err := s.repo.GetContractRelatedVerifiedEmailIDs(ctx, standard, chainID, contractAddress, statusTo)
 	if err != nil {
 		logger.Error("Failed to get related verified emails", err,
 			"standard", standard, "chainID", chainID, "contract", contractAddress,
@@ -330,11 +359,102 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 		"count", len(emailIDs), "standard", standard, "chainID", chainID,
 		"contract", contractAddress, "statusTo", statusTo, "initiator", initiatorAddress)
 
-	// 一次性构建模板上下文（跨收件人不变）
-	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(chainID))
+	// 一次性渲染主题与正文（对同一次事件所有收件人相同），一次渲染多次发送
+	subject, body, found, err := s.renderFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress)
 	if err != nil {
-		logger.Error("Failed to get chain info", err, "chainID", chainID)
-		return fmt.Errorf("failed to get chain info: %w", err)
+		return err
+	}
+	if !found {
+		return nil
+	}
+	// 模拟发送：标记邮件，去重与发送记录只落在本次模拟的沙箱里
+	sandbox := notification.SimulationFromContext(ctx)
+	if sandbox != nil {
+		subject, body = markSimulatedEmail(subject, body)
+	}
+
+	// 并发对每个邮箱发信；copied 记录本次事件已抄送/密送过的地址，避免共享邮箱收到多份
+	var copied sync.Map
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(8)
+	for _, id := range emailIDs {
+		emailID := id
+		g.Go(func() error {
+			if sandbox != nil {
+				if sandbox.Exists(simulatedEmailChannel, emailID, flowID, statusTo) {
+					return nil
+				}
+			} else {
+				exists, err := s.repo.CheckSendLogExists(gctx, emailID, flowID, statusTo)
+				if err != nil {
+					logger.Error("Failed to check send log", err, "emailID", emailID, "flowID", flowID)
+					return nil
+				}
+				if exists {
+					return nil
+				}
+			}
+
+			emailRecord, err := s.repo.GetEmailByID(gctx, emailID)
+			if err != nil {
+				logger.Error("Failed to get email by id", err, "emailID", emailID)
+				return nil
+			}
+
+			opts := s.buildSendOptions(gctx, emailID, &copied)
+			attempts, sendErr := s.sendWithRetry(gctx, emailRecord.Email, subject, body, opts)
+
+			sendLog := &types.EmailSendLog{
+				EmailID:          emailID,
+				FlowID:           flowID,
+				TimelockStandard: standard,
+				ChainID:          chainID,
+				ContractAddress:  contractAddress,
+				StatusFrom:       &statusFrom,
+				StatusTo:         statusTo,
+				TxHash:           txHash,
+				SendStatus:       "success",
+				RetryCount:       attempts - 1,
+			}
+			if sendErr != nil {
+				s.markSendFailure(sendLog, sendErr, emailPkg.IsTransientError(sendErr))
+				logger.Error("Failed to send notification email", sendErr, "emailID", emailID, "flowID", flowID,
+					"attempts", attempts, "permanent", sendLog.PermanentFailure)
+			}
+			if sandbox != nil {
+				errorMessage := ""
+				if sendLog.ErrorMessage != nil {
+					errorMessage = *sendLog.ErrorMessage
+				}
+				sandbox.Record(simulatedEmailChannel, emailID, flowID, statusTo, sendLog.SendStatus, errorMessage)
+			} else if err := s.repo.CreateSendLog(gctx, sendLog); err != nil {
+				logger.Error("Failed to create send log", err, "emailID", emailID, "flowID", flowID)
+			}
+
+			if sendLog.SendStatus == "success" {
+				logger.Info("Flow notification sent", "emailID", emailID, "flowID", flowID, "status", statusTo)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	logger.Info("Email flow notification completed",
+		"totalEmails", len(emailIDs),
+		"flowID", flowID,
+		"status", statusTo,
+		"elapsed_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// renderFlowNotification 渲染流程通知邮件的主题与正文；flow 不存在时 found 为 false
+func (s *emailService) renderFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) (subject, body string, found bool, err error) {
+	// 链名称与浏览器链接只用于展示，查询失败时以链ID代替并继续发送
+	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(chainID))
+	if err != nil || chainInfo == nil {
+		logger.Error("Failed to get chain info, falling back to chain id", err, "chainID", chainID)
+		chainInfo = types.FallbackSupportChain(chainID)
 	}
 
 	var explorerURLs []string
@@ -345,7 +465,7 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 
 	var txLink, txDisplay string
 	if txHash != nil && len(explorerURLs) > 0 {
-		txLink = fmt.Sprintf("%s/tx/%s", explorerURLs[0], *txHash)
+		txLink = utils.ExplorerTxURL(explorerURLs, *txHash)
 		if len(*txHash) > 10 {
 			txDisplay = fmt.Sprintf("%s...%s", (*txHash)[:10], (*txHash)[len(*txHash)-6:])
 		} else {
@@ -376,21 +496,22 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 	toBg, toText := getStatusColor(statusTo)
 
 	var baseData *types.NotificationData
+	var canceller string // 取消交易的发起地址，仅在流程被取消时展示
 	switch standard {
 	case "compound":
 		compoundTimeLock, err := s.timeLockRepo.GetCompoundTimeLockByChainAndAddress(ctx, chainID, contractAddress)
 		if err != nil {
 			logger.Error("Failed to get compound time lock", err, "chainID", chainID, "contractAddress", contractAddress)
-			return fmt.Errorf("failed to get compound timelock: %w", err)
+			return "", "", false, fmt.Errorf("failed to get compound timelock: %w", err)
 		}
 		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, flowID, chainID, contractAddress)
 		if err != nil {
 			logger.Error("Failed to get compound flow", err, "flowID", flowID)
-			return fmt.Errorf("failed to get compound flow: %w", err)
+			return "", "", false, fmt.Errorf("failed to get compound flow: %w", err)
 		}
 		if flow == nil {
 			logger.Warn("No compound flow found", "flowID", flowID, "chainID", chainID, "contractAddress", contractAddress)
-			return nil
+			return "", "", false, nil
 		}
 
 		var functionName string
@@ -401,6 +522,9 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 		} else if initiatorAddress != "" {
 			caller = initiatorAddress
 		}
+		if flow.CancellerAddress != nil {
+			canceller = *flow.CancellerAddress
+		}
 		target := "Unknown"
 		if flow.TargetAddress != nil {
 			target = *flow.TargetAddress
@@ -429,6 +553,7 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 			Standard:       strings.ToUpper(standard),
 			Contract:       contractAddress,
 			Remark:         compoundTimeLock.Remark,
+			Nickname:       compoundTimeLock.Nickname,
 			Caller:         caller,
 			Target:         target,
 			Function:       functionName,
@@ -436,10 +561,49 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 			CalldataParams: calldataParams,
 		}
 	case "openzeppelin":
-		// OZ 分支暂未实现，按原逻辑保留
-		return nil
+		ozTimeLock, err := s.timeLockRepo.GetOpenzeppelinTimeLockByChainAndAddress(ctx, chainID, contractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin time lock", err, "chainID", chainID, "contractAddress", contractAddress)
+			return "", "", false, fmt.Errorf("failed to get openzeppelin timelock: %w", err)
+		}
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin flow", err, "flowID", flowID)
+			return "", "", false, fmt.Errorf("failed to get openzeppelin flow: %w", err)
+		}
+		if flow == nil {
+			logger.Warn("No openzeppelin flow found", "flowID", flowID, "chainID", chainID, "contractAddress", contractAddress)
+			return "", "", false, nil
+		}
+
+		caller := "Unknown"
+		if flow.InitiatorAddress != nil {
+			caller = *flow.InitiatorAddress
+		} else if initiatorAddress != "" {
+			caller = initiatorAddress
+		}
+		if flow.CancellerAddress != nil {
+			canceller = *flow.CancellerAddress
+		}
+
+		calls, err := s.flowRepo.GetOpenzeppelinFlowCalls(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin flow calls", err, "flowID", flowID)
+		}
+
+		baseData = &types.NotificationData{
+			Standard: strings.ToUpper(standard),
+			Contract: contractAddress,
+			Remark:   ozTimeLock.Remark,
+			Nickname: ozTimeLock.Nickname,
+			Caller:   caller,
+		}
+		utils.FillOpenzeppelinCallsNotificationData(baseData, flow, calls, chainInfo.NativeCurrencySymbol)
+		if baseData.BlockedBy, err = s.flowRepo.GetOpenzeppelinBlockingPredecessor(ctx, flow); err != nil {
+			logger.Error("Failed to check openzeppelin flow predecessor", err, "flowID", flowID)
+		}
 	default:
-		return fmt.Errorf("invalid standard")
+		return "", "", false, fmt.Errorf("invalid standard")
 	}
 
 	baseData.BgColorFrom = template.CSS(fromBg)
@@ -448,90 +612,32 @@ func (s *emailService) SendFlowNotification(ctx context.Context, standard string
 	baseData.TextColorTo = template.CSS(toText)
 	baseData.StatusFrom = strings.ToUpper(statusFrom)
 	baseData.StatusTo = strings.ToUpper(statusTo)
+	if strings.EqualFold(statusTo, "cancelled") {
+		baseData.Canceller = canceller
+	}
 	baseData.Network = chainInfo.DisplayName
 	baseData.TxHash = txDisplay
 	baseData.TxUrl = txLink
 	baseData.DashboardUrl = s.config.Email.EmailURL
+	utils.FillExplorerLinks(baseData, explorerURLs)
+	utils.FillNotificationSeverity(baseData)
+	if baseData.SelfTargeting {
+		logger.Warn("Self-targeting timelock flow detected, sending critical email notification",
+			"standard", standard, "chainID", chainID, "contract", contractAddress, "flowID", flowID, "statusTo", statusTo)
+	}
 
 	// 模板也预解析一次
 	tmpl, err := template.ParseFiles("email_templates/FlowNotificationEmail.html")
 	if err != nil {
-		return fmt.Errorf("parse template: %w", err)
+		return "", "", false, fmt.Errorf("parse template: %w", err)
 	}
-	subject := fmt.Sprintf("Timelock Status Update: %s → %s",
-		cases.Title(language.English).String(baseData.StatusFrom),
-		cases.Title(language.English).String(baseData.StatusTo))
+	subject = flowNotificationSubject(baseData)
 
-	// 渲染正文（对同一次事件所有收件人相同），一次渲染多次发送
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, baseData); err != nil {
-		return fmt.Errorf("execute template: %w", err)
+		return "", "", false, fmt.Errorf("execute template: %w", err)
 	}
-	body := buf.String()
-
-	// 并发对每个邮箱发信
-	g, gctx := errgroup.WithContext(ctx)
-	g.SetLimit(8)
-	for _, id := range emailIDs {
-		emailID := id
-		g.Go(func() error {
-			exists, err := s.repo.CheckSendLogExists(gctx, emailID, flowID, statusTo)
-			if err != nil {
-				logger.Error("Failed to check send log", err, "emailID", emailID, "flowID", flowID)
-				return nil
-			}
-			if exists {
-				return nil
-			}
-
-			emailRecord, err := s.repo.GetEmailByID(gctx, emailID)
-			if err != nil {
-				logger.Error("Failed to get email by id", err, "emailID", emailID)
-				return nil
-			}
-
-			sendErr := s.sender.SendHTMLEmail(emailRecord.Email, subject, body)
-			sendStatus := "success"
-			var errMsg *string
-			if sendErr != nil {
-				sendStatus = "failed"
-				m := sendErr.Error()
-				errMsg = &m
-				logger.Error("Failed to send notification email", sendErr, "emailID", emailID, "flowID", flowID)
-			}
-
-			sendLog := &types.EmailSendLog{
-				EmailID:          emailID,
-				FlowID:           flowID,
-				TimelockStandard: standard,
-				ChainID:          chainID,
-				ContractAddress:  contractAddress,
-				StatusFrom:       &statusFrom,
-				StatusTo:         statusTo,
-				TxHash:           txHash,
-				SendStatus:       sendStatus,
-				ErrorMessage:     errMsg,
-				RetryCount:       0,
-			}
-			if err := s.repo.CreateSendLog(gctx, sendLog); err != nil {
-				logger.Error("Failed to create send log", err, "emailID", emailID, "flowID", flowID)
-			}
-
-			if sendStatus == "success" {
-				logger.Info("Flow notification sent", "emailID", emailID, "flowID", flowID, "status", statusTo)
-			}
-			return nil
-		})
-	}
-	_ = g.Wait()
-
-	logger.Info("Email flow notification completed",
-		"totalEmails", len(emailIDs),
-		"flowID", flowID,
-		"status", statusTo,
-		"elapsed_ms", time.Since(start).Milliseconds(),
-	)
-	return nil
+	return subject, buf.String(), true, nil
 }
 
 // ===== 工具方法 =====
@@ -577,9 +683,7 @@ func (s *emailService) sendFlowNotificationEmail(ctx context.Context, emailID in
 	if err != nil {
 		return fmt.Errorf("failed to get email: %w", err)
 	}
-	subject := fmt.Sprintf("Timelock Status Update: %s → %s",
-		cases.Title(language.English).String(emailData.StatusFrom),
-		cases.Title(language.English).String(emailData.StatusTo))
+	subject := flowNotificationSubject(emailData)
 
 	tmpl, err := template.ParseFiles("email_templates/FlowNotificationEmail.html")
 	if err != nil {
@@ -596,6 +700,34 @@ func (s *emailService) sendFlowNotificationEmail(ctx context.Context, emailID in
 	return s.sender.SendHTMLEmail(emailRecord.Email, subject, body)
 }
 
+// simulatedEmailChannel 模拟发送沙箱中邮件的渠道名
+const simulatedEmailChannel = "email"
+
+// markSimulatedEmail 为模拟发送的邮件加上标题前缀，并在正文顶部插入提示横幅
+func markSimulatedEmail(subject, body string) (string, string) {
+	subject = "[SIMULATED] " + subject
+	banner := `<div style="background-color:#fef3c7;color:#92400e;padding:12px 16px;font-family:Arial,sans-serif;font-size:14px;text-align:center;">` +
+		html.EscapeString(notification.SimulatedMessageMarker) + `</div>`
+	if idx := strings.Index(body, "<body"); idx >= 0 {
+		if end := strings.Index(body[idx:], ">"); end >= 0 {
+			insertAt := idx + end + 1
+			return subject, body[:insertAt] + banner + body[insertAt:]
+		}
+	}
+	return subject, banner + body
+}
+
+// flowNotificationSubject 生成流程通知邮件标题，以 timelock 自身为目标的治理变更加上醒目前缀
+func flowNotificationSubject(data *types.NotificationData) string {
+	subject := fmt.Sprintf("Timelock Status Update: %s → %s",
+		cases.Title(language.English).String(data.StatusFrom),
+		cases.Title(language.English).String(data.StatusTo))
+	if data.Severity == types.NotificationSeverityCritical {
+		subject = "[CRITICAL] Self-Admin Change · " + subject
+	}
+	return subject
+}
+
 // getEmailByID 根据ID获取邮箱记录
 func (s *emailService) getEmailByID(ctx context.Context, emailID int64) (*types.Email, error) {
 	return s.repo.GetEmailByID(ctx, emailID)
@@ -0,0 +1,80 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// ErrResendAllTooSoon 批量重发验证码过于频繁
+var ErrResendAllTooSoon = errors.New("verification codes resent recently, please wait")
+
+// resendAllCooldown 同一用户两次批量重发验证码的最小间隔（与单个邮箱的重发间隔一致）
+const resendAllCooldown = time.Minute
+
+// ResendAllVerificationCodes 为用户全部未验证且可投递的邮箱生成并发送新验证码
+// 每个邮箱仍受单邮箱的重发间隔限制，已验证的邮箱不在结果中
+func (s *emailService) ResendAllVerificationCodes(ctx context.Context, userID int64) (*types.ResendAllVerificationResponse, error) {
+	if !s.claimResendAll(userID) {
+		return nil, ErrResendAllTooSoon
+	}
+
+	userEmails, err := s.repo.GetUnverifiedUserEmails(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &types.ResendAllVerificationResponse{Results: make([]types.ResendVerificationResult, 0, len(userEmails))}
+	for _, ue := range userEmails {
+		result := types.ResendVerificationResult{UserEmailID: ue.ID}
+		if ue.Email != nil {
+			result.Email = ue.Email.Email
+		}
+
+		if ue.Email != nil && !ue.Email.IsDeliverable {
+			result.Status = types.ResendVerificationStatusUndeliverable
+		} else if err := s.SendVerificationCode(ctx, ue.ID, userID); err != nil {
+			result.Error = err.Error()
+			if err.Error() == "verification code sent recently, please wait" {
+				result.Status = types.ResendVerificationStatusRateLimited
+			} else {
+				result.Status = types.ResendVerificationStatusFailed
+			}
+		} else {
+			result.Success = true
+			result.Status = types.ResendVerificationStatusSent
+		}
+
+		if result.Success {
+			response.Sent++
+		} else {
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	logger.Info("Resent verification codes", "userID", userID, "total", len(response.Results), "sent", response.Sent, "failed", response.Failed)
+	return response, nil
+}
+
+// claimResendAll 检查并记录用户的批量重发时间，间隔不足 resendAllCooldown 时返回 false
+func (s *emailService) claimResendAll(userID int64) bool {
+	s.resendAllMu.Lock()
+	defer s.resendAllMu.Unlock()
+
+	now := time.Now()
+	if last, ok := s.resendAllAt[userID]; ok && now.Sub(last) < resendAllCooldown {
+		return false
+	}
+	// 顺带清理已过冷却期的记录，避免 map 无限增长
+	for id, last := range s.resendAllAt {
+		if now.Sub(last) >= resendAllCooldown {
+			delete(s.resendAllAt, id)
+		}
+	}
+	s.resendAllAt[userID] = now
+	return true
+}
@@ -0,0 +1,183 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"timelocker-backend/internal/types"
+	emailPkg "timelocker-backend/pkg/email"
+	"timelocker-backend/pkg/logger"
+)
+
+const (
+	// maxRetryBackoff 后台重发的最大退避时间
+	maxRetryBackoff = 6 * time.Hour
+	// defaultRetryBatchSize 后台重发任务默认单批处理数
+	defaultRetryBatchSize = 100
+)
+
+// errFlowGone 重发时 flow 已不存在
+var errFlowGone = errors.New("flow no longer exists")
+
+// renderedNotification 后台重发时同一事件的渲染结果，批内复用
+type renderedNotification struct {
+	subject string
+	body    string
+	err     error
+	copied  sync.Map // 本批内该事件已抄送/密送过的地址
+}
+
+// sendWithRetry 发送邮件，遇到临时性 SMTP 错误时按指数退避重试；返回实际尝试次数与最后一次错误
+func (s *emailService) sendWithRetry(ctx context.Context, to, subject, body string, opts emailPkg.SendOptions) (int, error) {
+	maxAttempts := s.config.Email.SendMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	delay := s.config.Email.SendRetryBaseDelay
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = s.sender.SendHTMLEmailWithOptions(to, subject, body, opts)
+		if err == nil || attempt >= maxAttempts || !emailPkg.IsTransientError(err) {
+			return attempt, err
+		}
+
+		logger.Warn("Transient SMTP error, retrying", "to", to, "attempt", attempt, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// markSendFailure 把发送日志标记为失败：临时错误且未超过重试上限时安排后台重发，否则标记为永久失败
+func (s *emailService) markSendFailure(sendLog *types.EmailSendLog, sendErr error, transient bool) {
+	msg := sendErr.Error()
+	sendLog.SendStatus = "failed"
+	sendLog.ErrorMessage = &msg
+
+	maxCount := s.config.Email.RetryMaxCount
+	if !transient || maxCount <= 0 || sendLog.RetryCount >= maxCount {
+		sendLog.PermanentFailure = true
+		sendLog.NextRetryAt = nil
+		return
+	}
+
+	next := time.Now().Add(retryBackoff(s.config.Email.RetryInterval, sendLog.RetryCount))
+	sendLog.PermanentFailure = false
+	sendLog.NextRetryAt = &next
+}
+
+// retryBackoff 计算后台重发的等待时间：base * 2^retryCount，上限 maxRetryBackoff
+func retryBackoff(base time.Duration, retryCount int) time.Duration {
+	if base <= 0 {
+		base = 5 * time.Minute
+	}
+	delay := base
+	for i := 0; i < retryCount && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+// RetryFailedSends 重发已到期的临时失败通知邮件
+// 每条日志每轮只尝试一次；成功后更新为 success，失败则累加 retry_count 并重新安排或标记为永久失败
+func (s *emailService) RetryFailedSends(ctx context.Context) error {
+	if s.config.Email.RetryMaxCount <= 0 {
+		return nil
+	}
+	batchSize := s.config.Email.RetryBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRetryBatchSize
+	}
+
+	logs, err := s.repo.GetRetryableSendLogs(ctx, time.Now(), batchSize)
+	if err != nil {
+		return err
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+
+	rendered := make(map[string]*renderedNotification)
+	var succeeded, rescheduled, deadLettered int
+	for i := range logs {
+		if ctx.Err() != nil {
+			break
+		}
+		sendLog := &logs[i]
+		if sendLog.Email == nil {
+			continue // 邮箱记录已删除（级联删除日志前的短暂窗口）
+		}
+
+		statusFrom := ""
+		if sendLog.StatusFrom != nil {
+			statusFrom = *sendLog.StatusFrom
+		}
+		key := fmt.Sprintf("%s|%d|%s|%s|%s", sendLog.TimelockStandard, sendLog.ChainID, sendLog.ContractAddress, sendLog.FlowID, sendLog.StatusTo)
+		r, ok := rendered[key]
+		if !ok {
+			r = &renderedNotification{}
+			subject, body, found, renderErr := s.renderFlowNotification(ctx, sendLog.TimelockStandard, sendLog.ChainID, sendLog.ContractAddress,
+				sendLog.FlowID, statusFrom, sendLog.StatusTo, sendLog.TxHash, "")
+			switch {
+			case renderErr != nil:
+				r.err = renderErr
+			case !found:
+				r.err = errFlowGone
+			default:
+				r.subject, r.body = subject, body
+			}
+			rendered[key] = r
+		}
+
+		sendLog.RetryCount++
+		if r.err != nil {
+			// flow 已不存在无需再重发；渲染失败（如数据库暂时不可用）按临时错误处理
+			s.markSendFailure(sendLog, r.err, !errors.Is(r.err, errFlowGone))
+		} else {
+			opts := s.buildSendOptions(ctx, sendLog.EmailID, &r.copied)
+			if sendErr := s.sender.SendHTMLEmailWithOptions(sendLog.Email.Email, r.subject, r.body, opts); sendErr != nil {
+				s.markSendFailure(sendLog, sendErr, emailPkg.IsTransientError(sendErr))
+			} else {
+				sendLog.SendStatus = "success"
+				sendLog.ErrorMessage = nil
+				sendLog.PermanentFailure = false
+				sendLog.NextRetryAt = nil
+				sendLog.SentAt = time.Now()
+			}
+		}
+
+		switch {
+		case sendLog.SendStatus == "success":
+			succeeded++
+			logger.Info("Flow notification resent", "emailID", sendLog.EmailID, "flowID", sendLog.FlowID, "status", sendLog.StatusTo, "retry_count", sendLog.RetryCount)
+		case sendLog.PermanentFailure:
+			deadLettered++
+			logger.Warn("Flow notification email permanently failed", "emailID", sendLog.EmailID, "flowID", sendLog.FlowID,
+				"status", sendLog.StatusTo, "retry_count", sendLog.RetryCount, "error", *sendLog.ErrorMessage)
+		default:
+			rescheduled++
+		}
+
+		if err := s.repo.UpdateSendLogResult(ctx, sendLog); err != nil {
+			logger.Error("Failed to update send log", err, "id", sendLog.ID, "emailID", sendLog.EmailID, "flowID", sendLog.FlowID)
+		}
+	}
+
+	logger.Info("Email send retry completed",
+		"total", len(logs),
+		"succeeded", succeeded,
+		"rescheduled", rescheduled,
+		"dead_lettered", deadLettered,
+	)
+	return nil
+}
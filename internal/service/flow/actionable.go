@@ -0,0 +1,160 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+)
+
+const (
+	// actionableEtaWindow waiting 流程的 eta 在该时间窗口内视为即将可执行
+	actionableEtaWindow = 24 * time.Hour
+	// 待处理流程默认/最大返回数量
+	defaultActionableLimit = 50
+	maxActionableLimit     = 100
+)
+
+// GetActionableFlows 获取用户需要关注的流程：ready（可立即执行）或 waiting 且 eta 在 24 小时内，
+// 合并两种标准后按 eta 升序返回（已到期的在前）
+func (s *flowService) GetActionableFlows(ctx context.Context, userAddress string, req *types.GetActionableFlowsRequest) (*types.GetActionableFlowsResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultActionableLimit
+	}
+	if limit > maxActionableLimit {
+		limit = maxActionableLimit
+	}
+	etaBefore := time.Now().Add(actionableEtaWindow)
+
+	compoundFlows, err := s.flowRepo.GetUserActionableCompoundFlows(ctx, userAddress, etaBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actionable compound flows: %w", err)
+	}
+	ozFlows, err := s.flowRepo.GetUserActionableOpenzeppelinFlows(ctx, userAddress, etaBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actionable openzeppelin flows: %w", err)
+	}
+
+	nativeTokens := make(map[int]string)
+	flows := make([]types.ActionableFlowResponse, 0, len(compoundFlows)+len(ozFlows))
+	for i := range compoundFlows {
+		flows = append(flows, s.newCompoundActionableFlow(&compoundFlows[i], s.nativeTokenSymbol(ctx, compoundFlows[i].ChainID, nativeTokens)))
+	}
+	for i := range ozFlows {
+		flow := &ozFlows[i]
+		calls, err := s.flowRepo.GetOpenzeppelinFlowCalls(ctx, flow.FlowID, flow.ChainID, flow.ContractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin flow calls", err, "flow_id", flow.FlowID, "chain_id", flow.ChainID)
+		}
+		resp := newOpenzeppelinActionableFlow(flow, calls, s.nativeTokenSymbol(ctx, flow.ChainID, nativeTokens))
+		blockedBy, err := s.flowRepo.GetOpenzeppelinBlockingPredecessor(ctx, flow)
+		if err != nil {
+			logger.Error("Failed to check openzeppelin flow predecessor", err, "flow_id", flow.FlowID, "chain_id", flow.ChainID)
+		}
+		resp.BlockedByPredecessor = blockedBy != ""
+		flows = append(flows, resp)
+	}
+
+	sortActionableFlows(flows)
+	if len(flows) > limit {
+		flows = flows[:limit]
+	}
+	return &types.GetActionableFlowsResponse{Flows: flows}, nil
+}
+
+// sortActionableFlows 按 eta 升序排序，没有 eta 的排在最后
+func sortActionableFlows(flows []types.ActionableFlowResponse) {
+	sort.SliceStable(flows, func(i, j int) bool {
+		a, b := flows[i].Eta, flows[j].Eta
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+}
+
+// nativeTokenSymbol 获取链的原生代币符号（同一请求内按链缓存）
+func (s *flowService) nativeTokenSymbol(ctx context.Context, chainID int, cache map[int]string) string {
+	if symbol, ok := cache[chainID]; ok {
+		return symbol
+	}
+	symbol := "ETH"
+	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(chainID))
+	if err != nil {
+		logger.Warn("Failed to get chain info for actionable flows", "chain_id", chainID, "error", err)
+	} else if chainInfo != nil {
+		symbol = chainInfo.NativeCurrencySymbol
+	}
+	cache[chainID] = symbol
+	return symbol
+}
+
+// newCompoundActionableFlow 构建 Compound 待处理流程，函数参数按 function_signature 解码
+func (s *flowService) newCompoundActionableFlow(flow *types.CompoundTimelockFlowDB, nativeToken string) types.ActionableFlowResponse {
+	resp := types.ActionableFlowResponse{
+		Standard:         "compound",
+		FlowID:           flow.FlowID,
+		ChainID:          flow.ChainID,
+		ContractAddress:  flow.ContractAddress,
+		Status:           flow.Status,
+		Eta:              flow.Eta,
+		ExpiredAt:        flow.ExpiredAt,
+		InitiatorAddress: flow.InitiatorAddress,
+		Target:           "Unknown",
+		Function:         "No Function Call",
+		CalldataParams:   []types.CalldataParam{},
+
+		ExpiredAtEstimated: flow.GracePeriodEstimated,
+	}
+	if flow.TargetAddress != nil {
+		resp.Target = *flow.TargetAddress
+	}
+	if flow.FunctionSignature != nil && *flow.FunctionSignature != "" {
+		resp.Function = *flow.FunctionSignature
+		if len(flow.CallData) > 0 {
+			params, err := utils.ParseCalldataNoSelector(*flow.FunctionSignature, flow.CallData)
+			if err != nil {
+				logger.Warn("Failed to parse actionable flow calldata", "flow_id", flow.FlowID, "function", *flow.FunctionSignature, "error", err)
+			} else {
+				resp.CalldataParams = params
+			}
+		}
+	}
+
+	value, err := utils.WeiToEth(flow.Value, nativeToken)
+	if err != nil {
+		value = fmt.Sprintf("0 %s", nativeToken)
+	}
+	resp.Value = value
+	return resp
+}
+
+// newOpenzeppelinActionableFlow 构建 OpenZeppelin 待处理流程，函数摘要与通知消息一致
+func newOpenzeppelinActionableFlow(flow *types.OpenzeppelinTimelockFlowDB, calls []types.OpenzeppelinFlowCallDB, nativeToken string) types.ActionableFlowResponse {
+	data := &types.NotificationData{}
+	utils.FillOpenzeppelinCallsNotificationData(data, flow, calls, nativeToken)
+
+	resp := types.ActionableFlowResponse{
+		Standard:         "openzeppelin",
+		FlowID:           flow.FlowID,
+		ChainID:          flow.ChainID,
+		ContractAddress:  flow.ContractAddress,
+		Status:           flow.Status,
+		Eta:              flow.Eta,
+		InitiatorAddress: flow.InitiatorAddress,
+		Target:           data.Target,
+		Function:         data.Function,
+		Value:            data.Value,
+		CalldataParams:   data.CalldataParams,
+		Calls:            data.Calls,
+	}
+	if flow.Predecessor != nil {
+		resp.Predecessor = *flow.Predecessor
+	}
+	return resp
+}
@@ -0,0 +1,49 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/types"
+)
+
+func TestSortActionableFlows(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) *time.Time {
+		eta := base.Add(time.Duration(h) * time.Hour)
+		return &eta
+	}
+	flow := func(id string, eta *time.Time) types.ActionableFlowResponse {
+		return types.ActionableFlowResponse{FlowID: id, Eta: eta}
+	}
+
+	tests := []struct {
+		name  string
+		flows []types.ActionableFlowResponse
+		want  []string
+	}{
+		{"empty", nil, []string{}},
+		{"ascending eta", []types.ActionableFlowResponse{flow("c", at(3)), flow("a", at(1)), flow("b", at(2))}, []string{"a", "b", "c"}},
+		{"past eta first", []types.ActionableFlowResponse{flow("future", at(5)), flow("past", at(-5))}, []string{"past", "future"}},
+		{"missing eta last", []types.ActionableFlowResponse{flow("none", nil), flow("a", at(1)), flow("none2", nil), flow("b", at(2))}, []string{"a", "b", "none", "none2"}},
+		{"equal eta keeps merge order", []types.ActionableFlowResponse{flow("compound", at(1)), flow("openzeppelin", at(1)), flow("earlier", at(0))}, []string{"earlier", "compound", "openzeppelin"}},
+		{"all missing eta keeps order", []types.ActionableFlowResponse{flow("x", nil), flow("y", nil)}, []string{"x", "y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortActionableFlows(tt.flows)
+			got := make([]string, len(tt.flows))
+			for i, f := range tt.flows {
+				got[i] = f.FlowID
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("sortActionableFlows = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("sortActionableFlows = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+)
+
+const (
+	// calendarDateLayout 日历请求与响应中的日期格式
+	calendarDateLayout = "2006-01-02"
+	// maxCalendarDays 单次查询的最大天数
+	maxCalendarDays = 92
+	// maxCalendarFlows 单次查询返回的最大流程数
+	maxCalendarFlows = 2000
+)
+
+// ErrInvalidCalendarRange 日历查询的日期范围或时区不合法
+var ErrInvalidCalendarRange = errors.New("invalid calendar range")
+
+// GetFlowCalendar 获取用户相关的 waiting/ready 流程，按 eta 在请求时区下的日期分组
+func (s *flowService) GetFlowCalendar(ctx context.Context, userAddress string, req *types.GetFlowCalendarRequest) (*types.GetFlowCalendarResponse, error) {
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidCalendarRange, timezone)
+	}
+
+	from, err := time.ParseInLocation(calendarDateLayout, strings.TrimSpace(req.From), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidCalendarRange)
+	}
+	to, err := time.ParseInLocation(calendarDateLayout, strings.TrimSpace(req.To), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidCalendarRange)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", ErrInvalidCalendarRange)
+	}
+	// to 为包含的最后一天，查询到次日零点（按日历天数推进，兼容夏令时）
+	end := to.AddDate(0, 0, 1)
+	if end.After(from.AddDate(0, 0, maxCalendarDays)) {
+		return nil, fmt.Errorf("%w: range exceeds %d days", ErrInvalidCalendarRange, maxCalendarDays)
+	}
+
+	entries, err := s.flowRepo.GetUserFlowCalendar(ctx, userAddress, from, end, timezone, maxCalendarFlows+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow calendar: %w", err)
+	}
+
+	response := &types.GetFlowCalendarResponse{
+		From:     from.Format(calendarDateLayout),
+		To:       to.Format(calendarDateLayout),
+		Timezone: timezone,
+		Days:     []types.FlowCalendarDay{},
+	}
+	if len(entries) > maxCalendarFlows {
+		entries = entries[:maxCalendarFlows]
+		response.Truncated = true
+	}
+	// 结果已按 eta 升序，日期相同的流程相邻
+	for _, entry := range entries {
+		if n := len(response.Days); n == 0 || response.Days[n-1].Date != entry.Day {
+			response.Days = append(response.Days, types.FlowCalendarDay{Date: entry.Day, Flows: []types.FlowCalendarItem{}})
+		}
+		day := &response.Days[len(response.Days)-1]
+		day.Total++
+		switch entry.Status {
+		case "waiting":
+			day.Waiting++
+		case "ready":
+			day.Ready++
+		}
+		day.Flows = append(day.Flows, entry.FlowCalendarItem)
+	}
+	return response, nil
+}
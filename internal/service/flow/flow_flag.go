@@ -0,0 +1,73 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// FlagFlow 标记可疑流程（仅与该流程相关的用户可标记），notify=true 时向标记人的通知渠道发送告警
+// 告警发送失败不影响标记结果，响应中 notified 为 false
+func (s *flowService) FlagFlow(ctx context.Context, userAddress string, req *types.FlagFlowRequest) (*types.FlowFlag, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return nil, fmt.Errorf("%w: note is required", ErrInvalidFlowFlag)
+	}
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+
+	flag := &types.FlowFlag{
+		Standard:        req.Standard,
+		ChainID:         req.ChainID,
+		ContractAddress: req.ContractAddress,
+		FlowID:          req.FlowID,
+		FlaggedBy:       userAddress,
+		Note:            note,
+	}
+	if err := s.flowRepo.CreateFlowFlag(ctx, flag); err != nil {
+		return nil, fmt.Errorf("failed to save flow flag: %w", err)
+	}
+	logger.Info("Flow flagged", "user", flag.FlaggedBy, "standard", flag.Standard, "chain_id", flag.ChainID, "flow_id", flag.FlowID, "notify", req.Notify)
+
+	if req.Notify && s.notificationSvc != nil {
+		sent, err := s.notificationSvc.SendFlowFlagAlert(ctx, flag.FlaggedBy, flag)
+		if err != nil {
+			logger.Error("Failed to send flow flag alert", err, "flag_id", flag.ID, "user", flag.FlaggedBy)
+		} else if sent > 0 {
+			if err := s.flowRepo.MarkFlowFlagNotified(ctx, flag.ID); err != nil {
+				logger.Error("Failed to mark flow flag notified", err, "flag_id", flag.ID)
+			}
+			flag.Notified = true
+		}
+	}
+	return flag, nil
+}
+
+// GetFlowFlags 查询合约下的流程标记（仅与合约相关的用户可查询）
+func (s *flowService) GetFlowFlags(ctx context.Context, userAddress string, req *types.GetFlowFlagsRequest) (*types.GetFlowFlagsResponse, error) {
+	standard := strings.ToLower(strings.TrimSpace(req.Standard))
+	contractAddress := strings.TrimSpace(req.ContractAddress)
+	flowID := strings.TrimSpace(req.FlowID)
+
+	related, err := s.flowRepo.IsUserRelatedToContract(ctx, userAddress, standard, req.ChainID, contractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check contract access: %w", err)
+	}
+	if !related {
+		return nil, ErrFlowAccessDenied
+	}
+
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, 20)
+	flags, total, err := s.flowRepo.ListFlowFlags(ctx, standard, req.ChainID, contractAddress, flowID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flow flags: %w", err)
+	}
+	return &types.GetFlowFlagsResponse{
+		Flags:          flags,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
@@ -0,0 +1,52 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// SetFlowNote 设置流程的链下备注（仅与该流程相关的用户可设置），note 为空时清除
+func (s *flowService) SetFlowNote(ctx context.Context, userAddress string, req *types.SetFlowNoteRequest) (*types.SetFlowNoteResponse, error) {
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+
+	response := &types.SetFlowNoteResponse{
+		Standard:        req.Standard,
+		ChainID:         req.ChainID,
+		ContractAddress: strings.ToLower(req.ContractAddress),
+		FlowID:          req.FlowID,
+	}
+
+	content := strings.TrimSpace(req.Note)
+	if content == "" {
+		if err := s.flowRepo.DeleteFlowNote(ctx, req.Standard, req.ChainID, req.ContractAddress, req.FlowID); err != nil {
+			return nil, fmt.Errorf("failed to delete flow note: %w", err)
+		}
+		logger.Info("Flow note cleared", "user", strings.ToLower(userAddress), "standard", req.Standard, "chain_id", req.ChainID, "flow_id", req.FlowID)
+		return response, nil
+	}
+
+	if err := s.flowRepo.UpsertFlowNote(ctx, &types.FlowNote{
+		Standard:        req.Standard,
+		ChainID:         req.ChainID,
+		ContractAddress: req.ContractAddress,
+		FlowID:          req.FlowID,
+		Note:            content,
+		UpdatedBy:       userAddress,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save flow note: %w", err)
+	}
+
+	note, err := s.flowRepo.GetFlowNote(ctx, req.Standard, req.ChainID, req.ContractAddress, req.FlowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow note: %w", err)
+	}
+	logger.Info("Flow note updated", "user", strings.ToLower(userAddress), "standard", req.Standard, "chain_id", req.ChainID, "flow_id", req.FlowID)
+	response.Note = note
+	return response, nil
+}
@@ -2,15 +2,31 @@ package flow
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	chainRepo "timelocker-backend/internal/repository/chain"
 	goldskyRepo "timelocker-backend/internal/repository/goldsky"
+	"timelocker-backend/internal/service/email"
 	"timelocker-backend/internal/service/goldsky"
+	"timelocker-backend/internal/service/notification"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
 	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrFlowNotFound        = errors.New("flow not found")
+	ErrFlowAccessDenied    = errors.New("flow access denied")
+	ErrInvalidFlowFilter   = errors.New("invalid flow list filter")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrInvalidFlowFlag     = errors.New("invalid flow flag")
+	ErrFlowTxNotFound      = errors.New("flow has no transaction of the requested type")
 )
 
 // FlowService 流程服务接口
@@ -23,21 +39,55 @@ type FlowService interface {
 
 	// 获取交易详情
 	GetCompoundTransactionDetail(ctx context.Context, req *types.GetTransactionDetailRequest) (*types.GetTransactionDetailResponse, error)
+
+	// 获取流程队列/执行/取消交易在 Goldsky 中的原始索引数据（仅与该流程相关的用户可查看）
+	GetFlowGoldskyTx(ctx context.Context, userAddress string, req *types.GetFlowGoldskyTxRequest) (*types.GetFlowGoldskyTxResponse, error)
+
+	// 获取流程状态变更历史
+	GetFlowStatusHistory(ctx context.Context, userAddress string, req *types.GetFlowStatusHistoryRequest) (*types.GetFlowStatusHistoryResponse, error)
+
+	// 获取流程的调用列表（OpenZeppelin 批量操作包含多个子调用）
+	GetFlowCalls(ctx context.Context, userAddress string, req *types.GetFlowCallsRequest) (*types.GetFlowCallsResponse, error)
+
+	// 获取用户需要关注的流程（ready 或即将到达 eta 的 waiting 流程）
+	GetActionableFlows(ctx context.Context, userAddress string, req *types.GetActionableFlowsRequest) (*types.GetActionableFlowsResponse, error)
+	// 获取用户相关的 waiting/ready 流程，按 eta 日期分组（日历视图）
+	GetFlowCalendar(ctx context.Context, userAddress string, req *types.GetFlowCalendarRequest) (*types.GetFlowCalendarResponse, error)
+
+	// 预览流程状态变更的通知消息
+	PreviewFlowNotification(ctx context.Context, userAddress string, req *types.PreviewFlowNotificationRequest) (*types.PreviewFlowNotificationResponse, error)
+	// 判定流程状态变化会触发当前用户的哪些通知配置与邮箱（不发送）
+	GetFlowWouldNotify(ctx context.Context, userAddress string, req *types.GetFlowWouldNotifyRequest) (*types.GetFlowWouldNotifyResponse, error)
+
+	// 设置流程的链下备注（如取消原因），note 为空时清除
+	SetFlowNote(ctx context.Context, userAddress string, req *types.SetFlowNoteRequest) (*types.SetFlowNoteResponse, error)
+
+	// 标记可疑流程，可选向自己的通知渠道发送高优先级告警
+	FlagFlow(ctx context.Context, userAddress string, req *types.FlagFlowRequest) (*types.FlowFlag, error)
+	// 查询合约下的流程标记（仅与合约相关的用户可查询）
+	GetFlowFlags(ctx context.Context, userAddress string, req *types.GetFlowFlagsRequest) (*types.GetFlowFlagsResponse, error)
+
+	// 将 before 之前最后更新的终态流程移入归档表（定时任务）
+	ArchiveTerminalFlows(ctx context.Context, before time.Time, batchSize int) error
 }
 
 // flowService 流程服务实现
 type flowService struct {
-	flowRepo   goldskyRepo.FlowRepository
-	chainRepo  chainRepo.Repository
-	goldskySvc *goldsky.GoldskyService
+	flowRepo        goldskyRepo.FlowRepository
+	chainRepo       chainRepo.Repository
+	goldskySvc      *goldsky.GoldskyService
+	notificationSvc notification.NotificationService
+	emailSvc        email.EmailService
 }
 
 // NewFlowService 创建流程服务实例
-func NewFlowService(flowRepo goldskyRepo.FlowRepository, chainRepo chainRepo.Repository, goldskySvc *goldsky.GoldskyService) FlowService {
+func NewFlowService(flowRepo goldskyRepo.FlowRepository, chainRepo chainRepo.Repository, goldskySvc *goldsky.GoldskyService, notificationSvc notification.NotificationService, emailSvc email.EmailService) FlowService {
 	return &flowService{
-		flowRepo:   flowRepo,
-		chainRepo:  chainRepo,
-		goldskySvc: goldskySvc,
+		flowRepo:        flowRepo,
+		chainRepo:       chainRepo,
+		goldskySvc:      goldskySvc,
+		notificationSvc: notificationSvc,
+		emailSvc:        emailSvc,
 	}
 }
 
@@ -58,29 +108,26 @@ func (s *flowService) GetCompoundFlowList(ctx context.Context, userAddress strin
 		}
 	}
 
-	// 计算分页
-	page := req.Page
-	pageSize := req.PageSize
-	if page <= 0 {
-		page = 1
-	}
-	if pageSize <= 0 {
-		pageSize = 10
-	}
-	if pageSize > 100 {
-		pageSize = 100
+	// 验证发起人地址
+	initiator := strings.ToLower(strings.TrimSpace(req.Initiator))
+	if initiator != "" && !common.IsHexAddress(initiator) {
+		return nil, fmt.Errorf("%w: invalid initiator address", ErrInvalidFlowFilter)
 	}
+
+	// 计算分页（超大 page_size 截断为 MaxPageSize）
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, 10)
 	offset := (page - 1) * pageSize
 
-	flows, total, err := s.flowRepo.GetUserRelatedCompoundFlows(ctx, userAddress, req.Status, req.Standard, offset, pageSize)
+	flows, total, err := s.flowRepo.GetUserRelatedCompoundFlows(ctx, userAddress, req.Status, req.Standard, initiator, req.IncludeArchived, offset, pageSize)
 	if err != nil {
 		logger.Error("Failed to get user related compound flows", err, "user", userAddress)
 		return nil, fmt.Errorf("failed to get user related compound flows: %w", err)
 	}
 
 	return &types.GetCompoundFlowListResponse{
-		Flows: flows,
-		Total: total,
+		Flows:          flows,
+		Total:          total,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
 	}, nil
 }
 
@@ -117,16 +164,309 @@ func (s *flowService) GetCompoundTransactionDetail(ctx context.Context, req *typ
 	}
 
 	detail, err := s.goldskySvc.GetTransactionDetail(ctx, req.ChainID, req.Standard, req.TxHash)
+	if errors.Is(err, goldsky.ErrTransactionNotFound) {
+		return nil, ErrTransactionNotFound
+	}
 	if err != nil {
 		logger.Error("Failed to get transaction detail", err, "standard", req.Standard, "tx_hash", req.TxHash, "chain_id", req.ChainID)
 		return nil, fmt.Errorf("failed to get transaction detail: %w", err)
 	}
 
 	if detail == nil {
-		return nil, fmt.Errorf("transaction not found")
+		return nil, ErrTransactionNotFound
 	}
 
 	return &types.GetTransactionDetailResponse{
 		Detail: *detail,
 	}, nil
 }
+
+// GetFlowGoldskyTx 获取流程交易在 Goldsky 中的原始索引数据（仅与该流程相关的用户可查看）
+// 流程没有该类型的交易时返回 ErrFlowTxNotFound；subgraph 尚未索引该交易（或链未配置 subgraph）时返回 ErrTransactionNotFound
+func (s *flowService) GetFlowGoldskyTx(ctx context.Context, userAddress string, req *types.GetFlowGoldskyTxRequest) (*types.GetFlowGoldskyTxResponse, error) {
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+	if req.TxType == "" {
+		req.TxType = "queue"
+	}
+
+	var txHash *string
+	switch req.Standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, req.FlowID, req.ChainID, req.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrFlowNotFound
+		}
+		txHash = map[string]*string{"queue": flow.QueueTxHash, "execute": flow.ExecuteTxHash, "cancel": flow.CancelTxHash}[req.TxType]
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, req.FlowID, req.ChainID, req.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrFlowNotFound
+		}
+		txHash = map[string]*string{"queue": flow.ScheduleTxHash, "execute": flow.ExecuteTxHash, "cancel": flow.CancelTxHash}[req.TxType]
+	}
+	if txHash == nil || *txHash == "" {
+		return nil, fmt.Errorf("%w: %s", ErrFlowTxNotFound, req.TxType)
+	}
+
+	response := &types.GetFlowGoldskyTxResponse{Standard: req.Standard, TxType: req.TxType, TxHash: *txHash}
+	var err error
+	if req.Standard == "compound" {
+		response.Compound, err = s.goldskySvc.GetTransactionDetail(ctx, req.ChainID, req.Standard, *txHash)
+	} else {
+		response.Openzeppelin, err = s.goldskySvc.GetOpenzeppelinTransactionDetail(ctx, req.ChainID, *txHash)
+	}
+	if errors.Is(err, goldsky.ErrTransactionNotFound) {
+		return nil, fmt.Errorf("%w: %s has not been indexed by Goldsky yet", ErrTransactionNotFound, *txHash)
+	}
+	if err != nil {
+		if errors.Is(err, goldsky.ErrNoGoldskyClient) {
+			return nil, fmt.Errorf("%w: chain %d has no Goldsky subgraph", ErrTransactionNotFound, req.ChainID)
+		}
+		logger.Error("Failed to get flow goldsky transaction", err, "standard", req.Standard, "chain_id", req.ChainID, "tx_hash", *txHash)
+		return nil, fmt.Errorf("failed to get goldsky transaction: %w", err)
+	}
+	return response, nil
+}
+
+// GetFlowStatusHistory 获取流程状态变更历史（仅与该流程相关的用户可查看）
+func (s *flowService) GetFlowStatusHistory(ctx context.Context, userAddress string, req *types.GetFlowStatusHistoryRequest) (*types.GetFlowStatusHistoryResponse, error) {
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+
+	history, err := s.flowRepo.GetFlowStatusHistory(ctx, req.Standard, req.ChainID, req.ContractAddress, req.FlowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow status history: %w", err)
+	}
+	if history == nil {
+		history = []types.FlowStatusHistory{}
+	}
+
+	return &types.GetFlowStatusHistoryResponse{
+		History: history,
+	}, nil
+}
+
+// GetFlowCalls 获取流程的调用列表（仅与该流程相关的用户可查看）
+// Compound 流程固定只有一个调用；OpenZeppelin 流程按子调用序号返回，没有子调用记录时退化为流程自身的调用
+func (s *flowService) GetFlowCalls(ctx context.Context, userAddress string, req *types.GetFlowCallsRequest) (*types.GetFlowCallsResponse, error) {
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+
+	calls := []types.FlowCallResponse{}
+	var predecessor, blockedBy string
+	switch req.Standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, req.FlowID, req.ChainID, req.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrFlowNotFound
+		}
+		calls = append(calls, newFlowCallResponse(0, flow.TargetAddress, flow.Value, flow.CallData))
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, req.FlowID, req.ChainID, req.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrFlowNotFound
+		}
+		subCalls, err := s.flowRepo.GetOpenzeppelinFlowCalls(ctx, req.FlowID, req.ChainID, req.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow calls: %w", err)
+		}
+		for _, call := range subCalls {
+			target := call.TargetAddress
+			calls = append(calls, newFlowCallResponse(call.CallIndex, &target, call.Value, call.CallData))
+		}
+		if len(calls) == 0 {
+			calls = append(calls, newFlowCallResponse(0, flow.TargetAddress, flow.Value, flow.CallData))
+		}
+		if flow.Predecessor != nil {
+			predecessor = *flow.Predecessor
+		}
+		if blockedBy, err = s.flowRepo.GetOpenzeppelinBlockingPredecessor(ctx, flow); err != nil {
+			return nil, fmt.Errorf("failed to check flow predecessor: %w", err)
+		}
+	}
+
+	return &types.GetFlowCallsResponse{
+		IsBatch:              len(calls) > 1,
+		Calls:                calls,
+		Predecessor:          predecessor,
+		BlockedByPredecessor: blockedBy != "",
+	}, nil
+}
+
+// newFlowCallResponse 构建单个调用的响应
+func newFlowCallResponse(index int, target *string, value string, callData []byte) types.FlowCallResponse {
+	call := types.FlowCallResponse{
+		Index: index,
+		Value: value,
+	}
+	if target != nil {
+		call.TargetAddress = *target
+	}
+	if len(callData) > 0 {
+		call.CallDataHex = "0x" + hex.EncodeToString(callData)
+	}
+	return call
+}
+
+// PreviewFlowNotification 预览流程状态变更的通知消息（仅与该流程相关的用户可预览）
+func (s *flowService) PreviewFlowNotification(ctx context.Context, userAddress string, req *types.PreviewFlowNotificationRequest) (*types.PreviewFlowNotificationResponse, error) {
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+
+	txHash, err := s.flowTxHashForStatus(ctx, &req.FlowIdentifier, req.StatusTo)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.notificationSvc.PreviewFlowNotification(ctx, userAddress, req.Standard, req.ChainID, req.ContractAddress, req.FlowID, req.StatusFrom, req.StatusTo, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview notification: %w", err)
+	}
+	if preview == nil {
+		return nil, ErrFlowNotFound
+	}
+	return preview, nil
+}
+
+// GetFlowWouldNotify 按与实际发送相同的筛选逻辑判定流程进入目标状态时会触发当前用户的哪些通知（仅与该流程相关的用户可查询）
+// 只返回当前用户自己的渠道配置与邮箱，不发送也不写任何日志
+func (s *flowService) GetFlowWouldNotify(ctx context.Context, userAddress string, req *types.GetFlowWouldNotifyRequest) (*types.GetFlowWouldNotifyResponse, error) {
+	if err := s.checkFlowAccess(ctx, userAddress, &req.FlowIdentifier); err != nil {
+		return nil, err
+	}
+
+	related, configs, err := s.notificationSvc.ExplainFlowNotification(ctx, userAddress, req.Standard, req.ChainID, req.ContractAddress, req.FlowID, req.StatusTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain channel notifications: %w", err)
+	}
+	emails, err := s.emailSvc.ExplainFlowNotification(ctx, userAddress, req.FlowID, req.StatusTo, related)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain email notifications: %w", err)
+	}
+
+	return &types.GetFlowWouldNotifyResponse{
+		StatusTo:    req.StatusTo,
+		RelatedUser: related,
+		Configs:     configs,
+		Emails:      emails,
+	}, nil
+}
+
+// flowTxHashForStatus 获取与目标状态对应的交易哈希（与实际通知一致：ready/expired 由定时任务触发，没有交易）
+func (s *flowService) flowTxHashForStatus(ctx context.Context, ref *types.FlowIdentifier, status string) (*string, error) {
+	switch ref.Standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, ref.FlowID, ref.ChainID, ref.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrFlowNotFound
+		}
+		return flow.TxHashForStatus(status), nil
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, ref.FlowID, ref.ChainID, ref.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrFlowNotFound
+		}
+		return flow.TxHashForStatus(status), nil
+	}
+	return nil, nil
+}
+
+// checkFlowAccess 校验流程存在且用户与之相关
+func (s *flowService) checkFlowAccess(ctx context.Context, userAddress string, ref *types.FlowIdentifier) error {
+	ref.Standard = strings.ToLower(strings.TrimSpace(ref.Standard))
+	ref.ContractAddress = strings.TrimSpace(ref.ContractAddress)
+	ref.FlowID = strings.TrimSpace(ref.FlowID)
+
+	var exists bool
+	switch ref.Standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, ref.FlowID, ref.ChainID, ref.ContractAddress)
+		if err != nil {
+			return fmt.Errorf("failed to get flow: %w", err)
+		}
+		exists = flow != nil
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, ref.FlowID, ref.ChainID, ref.ContractAddress)
+		if err != nil {
+			return fmt.Errorf("failed to get flow: %w", err)
+		}
+		exists = flow != nil
+	default:
+		return fmt.Errorf("invalid standard: %s", ref.Standard)
+	}
+	if !exists {
+		return ErrFlowNotFound
+	}
+
+	related, err := s.flowRepo.IsUserRelatedToFlow(ctx, userAddress, ref.Standard, ref.ChainID, ref.ContractAddress, ref.FlowID)
+	if err != nil {
+		return fmt.Errorf("failed to check flow access: %w", err)
+	}
+	if !related {
+		return ErrFlowAccessDenied
+	}
+	return nil
+}
+
+// ArchiveTerminalFlows 分批将终态流程移入归档表，直到没有可归档的流程
+func (s *flowService) ArchiveTerminalFlows(ctx context.Context, before time.Time, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	for _, standard := range []string{"compound", "openzeppelin"} {
+		summary := make(map[string]int64)
+		var total int64
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			counts, err := s.flowRepo.ArchiveTerminalFlows(ctx, standard, before, batchSize)
+			if err != nil {
+				return fmt.Errorf("failed to archive %s flows: %w", standard, err)
+			}
+			var moved int64
+			for status, n := range counts {
+				summary[status] += n
+				moved += n
+			}
+			total += moved
+			if moved < int64(batchSize) {
+				break
+			}
+		}
+
+		logger.Info("Archived terminal flows",
+			"standard", standard,
+			"before", before.Format(time.RFC3339),
+			"total", total,
+			"executed", summary["executed"],
+			"cancelled", summary["cancelled"],
+			"expired", summary["expired"],
+		)
+	}
+	return nil
+}
@@ -5,14 +5,20 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // GoldskyClient Goldsky GraphQL 客户端
@@ -20,19 +26,34 @@ type GoldskyClient struct {
 	httpClient  *http.Client
 	subgraphURL string
 	chainID     int
+	options     GoldskyClientOptions
+	breaker     *circuitBreaker
 }
 
+// ErrQueryTimeout 单次 subgraph 请求超过 RequestTimeout
+var ErrQueryTimeout = errors.New("goldsky query timed out")
+
+// ErrCompoundFlowIDMismatch subgraph 返回的 compound flow_id 与按 queueTransaction 参数计算出的 txHash 不一致
+var ErrCompoundFlowIDMismatch = errors.New("compound flow_id does not match computed tx hash")
+
 // NewGoldskyClient 创建新的 Goldsky 客户端
-func NewGoldskyClient(subgraphURL string, chainID int) *GoldskyClient {
+func NewGoldskyClient(subgraphURL string, chainID int, opts GoldskyClientOptions) *GoldskyClient {
+	opts = opts.withDefaults()
 	return &GoldskyClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// 超时由每次请求的 context 控制（见 doQuery），这里不再设置 http.Client.Timeout
+		httpClient:  &http.Client{},
 		subgraphURL: subgraphURL,
 		chainID:     chainID,
+		options:     opts,
+		breaker:     newCircuitBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
 	}
 }
 
+// CircuitOpen subgraph 是否因连续失败被熔断
+func (c *GoldskyClient) CircuitOpen() bool {
+	return c.breaker.isOpen(time.Now())
+}
+
 // QueryCompoundFlows 查询 Compound Flows（单页，调用方可借助 skip/分页游标拉多页）
 func (c *GoldskyClient) QueryCompoundFlows(ctx context.Context, contractAddresses []string, limit int) ([]types.GoldskyCompoundFlow, error) {
 	return c.QueryCompoundFlowsPage(ctx, contractAddresses, limit, 0)
@@ -110,7 +131,7 @@ func (c *GoldskyClient) QueryCompoundFlowsPage(ctx context.Context, contractAddr
 	}
 
 	var response types.GoldskyCompoundFlowsResponse
-	if err := c.executeQuery(ctx, query, variables, &response); err != nil {
+	if err := c.executeQuery(ctx, "QueryCompoundFlowsPage", query, variables, &response); err != nil {
 		return nil, err
 	}
 
@@ -186,7 +207,163 @@ func (c *GoldskyClient) QueryOpenzeppelinFlows(ctx context.Context, contractAddr
 	}
 
 	var response types.GoldskyOpenzeppelinFlowsResponse
-	if err := c.executeQuery(ctx, query, variables, &response); err != nil {
+	if err := c.executeQuery(ctx, "QueryOpenzeppelinFlows", query, variables, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data.OpenzeppelinTimelockFlows, nil
+}
+
+// QueryCompoundFlowsAfterID 按 id 游标拉一页 Compound Flows（id 升序），不受 graph-node skip 上限（默认 5000）限制；
+// afterID 为空表示从头开始，下一页传入本页最后一条的 id
+func (c *GoldskyClient) QueryCompoundFlowsAfterID(ctx context.Context, contractAddresses []string, limit int, afterID string) ([]types.GoldskyCompoundFlow, error) {
+	query := `
+		query($contractAddresses: [Bytes!], $limit: Int!, $afterId: ID!) {
+			compoundTimelockFlows(
+				where: { contractAddress_in: $contractAddresses, id_gt: $afterId }
+				first: $limit
+				orderBy: id
+				orderDirection: asc
+			) {
+				id
+				flowId
+				timelockStandard
+				contractAddress
+				status
+				queueTransaction {
+					id
+					txHash
+					logIndex
+					blockNumber
+					blockTimestamp
+					contractAddress
+					fromAddress
+					eventType
+					eventTxHash
+					eventTarget
+					eventValue
+					eventSignature
+					eventData
+					eventEta
+				}
+				executeTransaction {
+					id
+					txHash
+					blockNumber
+					blockTimestamp
+					fromAddress
+					eventType
+				}
+				cancelTransaction {
+					id
+					txHash
+					blockNumber
+					blockTimestamp
+					fromAddress
+					eventType
+				}
+				initiatorAddress
+				targetAddress
+				value
+				callData
+				functionSignature
+				queuedAt
+				eta
+				gracePeriod
+				expiredAt
+				executedAt
+				cancelledAt
+				createdAt
+				updatedAt
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"contractAddresses": contractAddresses,
+		"limit":             limit,
+		"afterId":           afterID,
+	}
+
+	var response types.GoldskyCompoundFlowsResponse
+	if err := c.executeQuery(ctx, "QueryCompoundFlowsAfterID", query, variables, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data.CompoundTimelockFlows, nil
+}
+
+// QueryOpenzeppelinFlowsAfterID 按 id 游标拉一页 OpenZeppelin Flows（id 升序），用法同 QueryCompoundFlowsAfterID
+func (c *GoldskyClient) QueryOpenzeppelinFlowsAfterID(ctx context.Context, contractAddresses []string, limit int, afterID string) ([]types.GoldskyOpenzeppelinFlow, error) {
+	query := `
+		query($contractAddresses: [Bytes!], $limit: Int!, $afterId: ID!) {
+			openzeppelinTimelockFlows(
+				where: { contractAddress_in: $contractAddresses, id_gt: $afterId }
+				first: $limit
+				orderBy: id
+				orderDirection: asc
+			) {
+				id
+				flowId
+				timelockStandard
+				contractAddress
+				status
+				scheduleTransaction {
+					id
+					txHash
+					logIndex
+					blockNumber
+					blockTimestamp
+					contractAddress
+					fromAddress
+					eventType
+					eventId
+					eventIndex
+					eventTarget
+					eventValue
+					eventData
+					eventPredecessor
+					eventDelay
+				}
+				executeTransaction {
+					id
+					txHash
+					blockNumber
+					blockTimestamp
+					fromAddress
+					eventType
+				}
+				cancelTransaction {
+					id
+					txHash
+					blockNumber
+					blockTimestamp
+					fromAddress
+					eventType
+				}
+				initiatorAddress
+				targetAddress
+				value
+				callData
+				queuedAt
+				delay
+				eta
+				executedAt
+				cancelledAt
+				createdAt
+				updatedAt
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"contractAddresses": contractAddresses,
+		"limit":             limit,
+		"afterId":           afterID,
+	}
+
+	var response types.GoldskyOpenzeppelinFlowsResponse
+	if err := c.executeQuery(ctx, "QueryOpenzeppelinFlowsAfterID", query, variables, &response); err != nil {
 		return nil, err
 	}
 
@@ -265,7 +442,7 @@ func (c *GoldskyClient) QueryCompoundFlowByFlowID(ctx context.Context, flowID st
 		} `json:"data"`
 	}
 
-	if err := c.executeQuery(ctx, query, variables, &response); err != nil {
+	if err := c.executeQuery(ctx, "QueryCompoundFlowByFlowID", query, variables, &response); err != nil {
 		return nil, err
 	}
 
@@ -307,7 +484,7 @@ func (c *GoldskyClient) QueryCompoundTransactionByTxHash(ctx context.Context, tx
 	}
 
 	var response types.GoldskyCompoundTransactionResponse
-	if err := c.executeQuery(ctx, query, variables, &response); err != nil {
+	if err := c.executeQuery(ctx, "QueryCompoundTransactionByTxHash", query, variables, &response); err != nil {
 		return nil, err
 	}
 
@@ -350,7 +527,7 @@ func (c *GoldskyClient) QueryOpenzeppelinTransactionByTxHash(ctx context.Context
 	}
 
 	var response types.GoldskyOpenzeppelinTransactionResponse
-	if err := c.executeQuery(ctx, query, variables, &response); err != nil {
+	if err := c.executeQuery(ctx, "QueryOpenzeppelinTransactionByTxHash", query, variables, &response); err != nil {
 		return nil, err
 	}
 
@@ -361,8 +538,55 @@ func (c *GoldskyClient) QueryOpenzeppelinTransactionByTxHash(ctx context.Context
 	return &response.Data.OpenzeppelinTimelockTransactions[0], nil
 }
 
-// executeQuery 执行 GraphQL 查询
-func (c *GoldskyClient) executeQuery(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+// QueryOpenzeppelinScheduledCalls 查询某个 OpenZeppelin 操作的全部 CallScheduled 事件（scheduleBatch 每个子调用一条）
+func (c *GoldskyClient) QueryOpenzeppelinScheduledCalls(ctx context.Context, contractAddress string, operationID string) ([]types.GoldskyOpenzeppelinTransaction, error) {
+	query := `
+		query($contractAddress: Bytes!, $operationId: Bytes!) {
+			openzeppelinTimelockTransactions(
+				where: { contractAddress: $contractAddress, eventId: $operationId, eventType: "CallScheduled" }
+				first: 1000
+				orderBy: logIndex
+				orderDirection: asc
+			) {
+				id
+				txHash
+				logIndex
+				blockNumber
+				blockTimestamp
+				contractAddress
+				fromAddress
+				eventType
+				eventId
+				eventIndex
+				eventTarget
+				eventValue
+				eventData
+				eventPredecessor
+				eventDelay
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"contractAddress": strings.ToLower(contractAddress),
+		"operationId":     operationID,
+	}
+
+	var response types.GoldskyOpenzeppelinTransactionResponse
+	if err := c.executeQuery(ctx, "QueryOpenzeppelinScheduledCalls", query, variables, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data.OpenzeppelinTimelockTransactions, nil
+}
+
+// executeQuery 执行 GraphQL 查询，name 用于日志标识查询类型
+// 429/5xx、网络错误与单次请求超时按指数退避重试（优先遵循 Retry-After），最终失败计入熔断器
+func (c *GoldskyClient) executeQuery(ctx context.Context, name string, query string, variables map[string]interface{}, result interface{}) error {
+	if !c.breaker.allow(time.Now()) {
+		return c.breaker.circuitOpenError(time.Now())
+	}
+
 	requestBody := map[string]interface{}{
 		"query":     query,
 		"variables": variables,
@@ -374,39 +598,105 @@ func (c *GoldskyClient) executeQuery(ctx context.Context, query string, variable
 		return fmt.Errorf("failed to marshal query: %w", err)
 	}
 
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		body, err := c.doQuery(ctx, jsonData)
+		c.logSlowQuery(name, variables, attempt, time.Since(start), err)
+		if err == nil {
+			c.breaker.recordSuccess()
+			if err := json.Unmarshal(body, result); err != nil {
+				logger.Error("Failed to unmarshal GraphQL response", err, "body", string(body))
+				return fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			return nil
+		}
+		lastErr = err
+
+		var qErr *queryError
+		retryable := errors.As(err, &qErr) && qErr.retryable
+		if !retryable || attempt >= c.options.MaxRetries || ctx.Err() != nil {
+			break
+		}
+
+		delay := backoffDelay(attempt, c.options.RetryBaseDelay, c.options.RetryMaxDelay, qErr.retryAfter)
+		logger.Warn("GraphQL query failed, retrying", "chain_id", c.chainID, "attempt", attempt+1, "max_retries", c.options.MaxRetries, "delay", delay.String(), "error", err)
+		if err := sleepContext(ctx, delay); err != nil {
+			lastErr = err
+			break
+		}
+	}
+
+	if ctx.Err() == nil {
+		if c.breaker.recordFailure(time.Now()) {
+			logger.Warn("Goldsky circuit breaker opened", "chain_id", c.chainID, "url", c.subgraphURL, "cooldown", c.options.BreakerCooldown.String())
+		}
+	}
+	logger.Error("GraphQL query failed", lastErr, "chain_id", c.chainID, "url", c.subgraphURL)
+	return lastErr
+}
+
+// logSlowQuery 单次请求耗时超过 SlowQueryThreshold 时记录慢查询
+func (c *GoldskyClient) logSlowQuery(name string, variables map[string]interface{}, attempt int, elapsed time.Duration, err error) {
+	if elapsed < c.options.SlowQueryThreshold {
+		return
+	}
+	contractCount := 0
+	if addresses, ok := variables["contractAddresses"].([]string); ok {
+		contractCount = len(addresses)
+	} else if _, ok := variables["contractAddress"]; ok {
+		contractCount = 1
+	}
+	logger.Warn("Slow Goldsky query",
+		"chain_id", c.chainID,
+		"query", name,
+		"contract_count", contractCount,
+		"attempt", attempt+1,
+		"duration_ms", elapsed.Milliseconds(),
+		"failed", err != nil,
+	)
+}
+
+// doQuery 发送一次 GraphQL 请求并返回响应体，单次请求受 RequestTimeout 限制
+func (c *GoldskyClient) doQuery(parent context.Context, jsonData []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(parent, c.options.RequestTimeout)
+	defer cancel()
+
+	body, err := c.sendQuery(ctx, jsonData)
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, &queryError{err: fmt.Errorf("%w after %s", ErrQueryTimeout, c.options.RequestTimeout), retryable: true}
+	}
+	return body, err
+}
+
+// sendQuery 发送 HTTP 请求并读取响应
+func (c *GoldskyClient) sendQuery(ctx context.Context, jsonData []byte) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, "POST", c.subgraphURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		logger.Error("Failed to create HTTP request", err)
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		logger.Error("Failed to execute GraphQL query", err, "url", c.subgraphURL)
-		return fmt.Errorf("failed to execute query: %w", err)
+		return nil, &queryError{err: fmt.Errorf("failed to execute query: %w", err), retryable: ctx.Err() == nil}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		logger.Error("GraphQL query failed", fmt.Errorf("status code: %d", resp.StatusCode), "body", string(body))
-		return fmt.Errorf("query failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		logger.Error("Failed to read response body", err)
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, &queryError{err: fmt.Errorf("failed to read response: %w", err), retryable: ctx.Err() == nil}
 	}
 
-	if err := json.Unmarshal(body, result); err != nil {
-		logger.Error("Failed to unmarshal GraphQL response", err, "body", string(body))
-		return fmt.Errorf("failed to unmarshal response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &queryError{
+			err:        fmt.Errorf("query failed with status %d: %s", resp.StatusCode, string(body)),
+			retryable:  isRetryableStatus(resp.StatusCode),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
-
-	return nil
+	return body, nil
 }
 
 // ConvertGoldskyCompoundFlowToDB 转换 Goldsky Compound Flow 为数据库模型
@@ -435,6 +725,9 @@ func ConvertGoldskyCompoundFlowToDB(goldskyFlow types.GoldskyCompoundFlow, chain
 	if goldskyFlow.CancelTransaction != nil {
 		txHash := goldskyFlow.CancelTransaction.TxHash
 		flow.CancelTxHash = &txHash
+		if goldskyFlow.CancelTransaction.FromAddress != "" {
+			flow.CancellerAddress = &goldskyFlow.CancelTransaction.FromAddress
+		}
 	}
 
 	// 处理地址
@@ -493,9 +786,40 @@ func ConvertGoldskyCompoundFlowToDB(goldskyFlow types.GoldskyCompoundFlow, chain
 		}
 	}
 
+	if err := verifyCompoundFlowID(goldskyFlow, flow); err != nil {
+		return nil, err
+	}
+
 	return flow, nil
 }
 
+// verifyCompoundFlowID 校验 subgraph 给出的 flow_id 是否等于按合约规则计算出的 txHash
+// 不一致时返回 ErrCompoundFlowIDMismatch，由调用方跳过该 flow 并记录错误日志；字段不全无法校验时放行
+func verifyCompoundFlowID(goldskyFlow types.GoldskyCompoundFlow, flow *types.CompoundTimelockFlowDB) error {
+	if flow.TargetAddress == nil || flow.FunctionSignature == nil || goldskyFlow.Eta == nil {
+		return nil // 字段不全，无法校验
+	}
+	value, ok := new(big.Int).SetString(flow.Value, 10)
+	if !ok {
+		return nil
+	}
+	eta, ok := new(big.Int).SetString(*goldskyFlow.Eta, 10)
+	if !ok {
+		return nil
+	}
+
+	computed, err := utils.ComputeCompoundTxHash(common.HexToAddress(*flow.TargetAddress), value, *flow.FunctionSignature, flow.CallData, eta)
+	if err != nil {
+		logger.Warn("Failed to compute compound tx hash", "flow_id", flow.FlowID, "error", err)
+		return nil
+	}
+
+	if !strings.EqualFold(computed.Hex(), flow.FlowID) {
+		return fmt.Errorf("%w: flow_id %s, computed %s, contract %s", ErrCompoundFlowIDMismatch, flow.FlowID, computed.Hex(), flow.ContractAddress)
+	}
+	return nil
+}
+
 // ConvertGoldskyOpenzeppelinFlowToDB 转换 Goldsky OpenZeppelin Flow 为数据库模型
 func ConvertGoldskyOpenzeppelinFlowToDB(goldskyFlow types.GoldskyOpenzeppelinFlow, chainID int) (*types.OpenzeppelinTimelockFlowDB, error) {
 	flow := &types.OpenzeppelinTimelockFlowDB{
@@ -514,6 +838,7 @@ func ConvertGoldskyOpenzeppelinFlowToDB(goldskyFlow types.GoldskyOpenzeppelinFlo
 		txHash := goldskyFlow.ScheduleTransaction.TxHash
 		flow.ScheduleTxHash = &txHash
 		flow.InitiatorAddress = &goldskyFlow.ScheduleTransaction.FromAddress
+		flow.Predecessor = types.NormalizeOpenzeppelinPredecessor(goldskyFlow.ScheduleTransaction.EventPredecessor)
 	}
 	if goldskyFlow.ExecuteTransaction != nil {
 		txHash := goldskyFlow.ExecuteTransaction.TxHash
@@ -522,6 +847,9 @@ func ConvertGoldskyOpenzeppelinFlowToDB(goldskyFlow types.GoldskyOpenzeppelinFlo
 	if goldskyFlow.CancelTransaction != nil {
 		txHash := goldskyFlow.CancelTransaction.TxHash
 		flow.CancelTxHash = &txHash
+		if goldskyFlow.CancelTransaction.FromAddress != "" {
+			flow.CancellerAddress = &goldskyFlow.CancelTransaction.FromAddress
+		}
 	}
 
 	// 处理地址
@@ -573,6 +901,68 @@ func ConvertGoldskyOpenzeppelinFlowToDB(goldskyFlow types.GoldskyOpenzeppelinFlo
 	return flow, nil
 }
 
+// AggregateOpenzeppelinScheduledCalls 将 CallScheduled 事件按操作ID聚合为有序的子调用列表
+// 同一操作内按 index 去重（保留先出现的一条）并升序排列
+func AggregateOpenzeppelinScheduledCalls(txs []types.GoldskyOpenzeppelinTransaction, chainID int) map[string][]types.OpenzeppelinFlowCallDB {
+	result := make(map[string][]types.OpenzeppelinFlowCallDB)
+	seen := make(map[string]bool)
+	for _, tx := range txs {
+		if tx.EventType != "CallScheduled" || tx.EventId == nil {
+			continue
+		}
+		call, err := convertOpenzeppelinScheduledCall(tx, chainID)
+		if err != nil {
+			logger.Error("Failed to convert openzeppelin scheduled call", err, "tx_hash", tx.TxHash, "log_index", tx.LogIndex)
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%d", call.FlowID, call.CallIndex)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result[call.FlowID] = append(result[call.FlowID], *call)
+	}
+
+	for flowID := range result {
+		calls := result[flowID]
+		sort.Slice(calls, func(i, j int) bool { return calls[i].CallIndex < calls[j].CallIndex })
+	}
+	return result
+}
+
+// convertOpenzeppelinScheduledCall 将单个 CallScheduled 事件转换为子调用记录
+func convertOpenzeppelinScheduledCall(tx types.GoldskyOpenzeppelinTransaction, chainID int) (*types.OpenzeppelinFlowCallDB, error) {
+	call := &types.OpenzeppelinFlowCallDB{
+		FlowID:          *tx.EventId,
+		ChainID:         chainID,
+		ContractAddress: strings.ToLower(tx.ContractAddress),
+		Value:           tx.EventValue,
+		CreatedAt:       time.Now(),
+	}
+	if call.Value == "" {
+		call.Value = "0"
+	}
+	if tx.EventIndex != nil && *tx.EventIndex != "" {
+		index, err := strconv.Atoi(*tx.EventIndex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event index %q: %w", *tx.EventIndex, err)
+		}
+		call.CallIndex = index
+	}
+	if tx.EventTarget != nil {
+		call.TargetAddress = strings.ToLower(*tx.EventTarget)
+	}
+	if tx.EventData != nil && *tx.EventData != "" {
+		data, err := hex.DecodeString(strings.TrimPrefix(*tx.EventData, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid event data: %w", err)
+		}
+		call.CallData = data
+	}
+	return call, nil
+}
+
 // QueryGlobalStatistics 查询全局统计数据
 func (c *GoldskyClient) QueryGlobalStatistics(ctx context.Context) (*GlobalStatistics, error) {
 	query := `
@@ -602,7 +992,7 @@ func (c *GoldskyClient) QueryGlobalStatistics(ctx context.Context) (*GlobalStati
 		} `json:"data"`
 	}{}
 
-	if err := c.executeQuery(ctx, query, nil, &resp); err != nil {
+	if err := c.executeQuery(ctx, "QueryGlobalStatistics", query, nil, &resp); err != nil {
 		return nil, fmt.Errorf("failed to query global statistics: %w", err)
 	}
 
@@ -0,0 +1,71 @@
+package goldsky
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestConvertGoldskyCompoundFlowToDBVerifiesFlowID(t *testing.T) {
+	target := "0xc00e94cb662c3520282e6f5717214004a7f26888"
+	signature := "_setPendingAdmin(address)"
+	callData := "0x0000000000000000000000006d903f6003cca6255d85cca4d3b5e5146dc33925"
+	eta := "1700000000"
+	computed, err := utils.ComputeCompoundTxHash(common.HexToAddress(target), big.NewInt(0), signature, hexutil.MustDecode(callData), big.NewInt(1700000000))
+	if err != nil {
+		t.Fatalf("ComputeCompoundTxHash: %v", err)
+	}
+
+	flowWith := func(flowID string) types.GoldskyCompoundFlow {
+		return types.GoldskyCompoundFlow{
+			FlowID:            flowID,
+			ContractAddress:   "0x1111111111111111111111111111111111111111",
+			Status:            "waiting",
+			Value:             "0",
+			TargetAddress:     &target,
+			FunctionSignature: &signature,
+			CallData:          &callData,
+			Eta:               &eta,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		flow    func() types.GoldskyCompoundFlow
+		wantErr bool
+	}{
+		{"matching flow_id", func() types.GoldskyCompoundFlow { return flowWith(computed.Hex()) }, false},
+		{"flow_id comparison is case-insensitive", func() types.GoldskyCompoundFlow {
+			return flowWith("0x" + strings.ToUpper(computed.Hex()[2:]))
+		}, false},
+		{"mismatched flow_id", func() types.GoldskyCompoundFlow {
+			return flowWith("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		}, true},
+		{"incomplete fields are not verified", func() types.GoldskyCompoundFlow {
+			f := flowWith("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+			f.Eta = nil
+			return f
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow, err := ConvertGoldskyCompoundFlowToDB(tt.flow(), 1)
+			if tt.wantErr {
+				if !errors.Is(err, ErrCompoundFlowIDMismatch) || flow != nil {
+					t.Fatalf("got flow %v, error %v, want ErrCompoundFlowIDMismatch", flow, err)
+				}
+				return
+			}
+			if err != nil || flow == nil {
+				t.Fatalf("ConvertGoldskyCompoundFlowToDB: flow %v, error %v", flow, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package goldsky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQueryMaxRetries          = 3
+	defaultQueryRetryBaseDelay      = 500 * time.Millisecond
+	defaultQueryRetryMaxDelay       = 30 * time.Second
+	defaultCircuitBreakerThreshold  = 5
+	defaultCircuitBreakerCooldown   = 5 * time.Minute
+	defaultQueryRequestTimeout      = 30 * time.Second
+	defaultSlowQueryThreshold       = 5 * time.Second
+	circuitBreakerHalfOpenProbeSpan = time.Minute
+)
+
+// ErrCircuitOpen subgraph 连续失败、熔断器处于打开状态，查询被直接拒绝
+var ErrCircuitOpen = errors.New("goldsky circuit breaker is open")
+
+// GoldskyClientOptions 客户端超时、重试与熔断配置，零值字段使用默认值
+type GoldskyClientOptions struct {
+	MaxRetries         int           // 单次查询失败后的最大重试次数（< 0 表示不重试）
+	RetryBaseDelay     time.Duration // 指数退避的初始间隔
+	RetryMaxDelay      time.Duration // 单次退避（含 Retry-After）的最大间隔
+	BreakerThreshold   int           // 连续多少次查询最终失败后打开熔断器
+	BreakerCooldown    time.Duration // 熔断器打开后多久允许试探请求
+	RequestTimeout     time.Duration // 单次 HTTP 请求超时（每次重试单独计时）
+	SlowQueryThreshold time.Duration // 单次请求超过该耗时记录慢查询日志
+}
+
+// withDefaults 填充未配置的字段
+func (o GoldskyClientOptions) withDefaults() GoldskyClientOptions {
+	if o.MaxRetries == 0 {
+		o.MaxRetries = defaultQueryMaxRetries
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	if o.RetryBaseDelay <= 0 {
+		o.RetryBaseDelay = defaultQueryRetryBaseDelay
+	}
+	if o.RetryMaxDelay <= 0 {
+		o.RetryMaxDelay = defaultQueryRetryMaxDelay
+	}
+	if o.BreakerThreshold <= 0 {
+		o.BreakerThreshold = defaultCircuitBreakerThreshold
+	}
+	if o.BreakerCooldown <= 0 {
+		o.BreakerCooldown = defaultCircuitBreakerCooldown
+	}
+	if o.RequestTimeout <= 0 {
+		o.RequestTimeout = defaultQueryRequestTimeout
+	}
+	if o.SlowQueryThreshold <= 0 {
+		o.SlowQueryThreshold = defaultSlowQueryThreshold
+	}
+	return o
+}
+
+// queryError 单次 HTTP 查询失败，记录是否可重试以及服务端要求的等待时间
+type queryError struct {
+	err        error
+	retryable  bool
+	retryAfter time.Duration
+}
+
+func (e *queryError) Error() string { return e.err.Error() }
+func (e *queryError) Unwrap() error { return e.err }
+
+// isRetryableStatus 429 与 5xx 视为临时错误
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter 解析 Retry-After 头（秒数或 HTTP 日期），无效时返回 0
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// backoffDelay 第 attempt 次重试（从 0 开始）前的等待时间；Retry-After 优先，均不超过 maxDelay
+func backoffDelay(attempt int, base, maxDelay, retryAfter time.Duration) time.Duration {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = base << uint(attempt)
+		if delay <= 0 { // 溢出
+			delay = maxDelay
+		}
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// circuitBreaker 按连续失败次数熔断的简单断路器
+// 打开后在 cooldown 内拒绝请求；cooldown 结束后放行一个试探请求，成功则关闭，失败则重新打开
+type circuitBreaker struct {
+	mu               sync.Mutex
+	threshold        int
+	cooldown         time.Duration
+	consecutiveFails int
+	openUntil        time.Time
+	probing          bool
+	probeStarted     time.Time
+}
+
+// newCircuitBreaker 创建断路器
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow 判断当前是否允许发起请求
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.consecutiveFails < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) {
+		return false
+	}
+	// 半开：同一时间只放行一个试探请求，试探请求异常退出未回报时超时后重新放行
+	if b.probing && now.Sub(b.probeStarted) < circuitBreakerHalfOpenProbeSpan {
+		return false
+	}
+	b.probing = true
+	b.probeStarted = now
+	return true
+}
+
+// isOpen 熔断器是否处于打开状态（不会占用试探名额）
+func (b *circuitBreaker) isOpen(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consecutiveFails >= b.threshold && now.Before(b.openUntil)
+}
+
+// recordSuccess 查询成功，关闭熔断器
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFails = 0
+	b.probing = false
+	b.openUntil = time.Time{}
+}
+
+// recordFailure 查询最终失败，达到阈值时打开熔断器；返回熔断器是否因此打开
+func (b *circuitBreaker) recordFailure(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFails++
+	b.probing = false
+	if b.consecutiveFails >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+		return true
+	}
+	return false
+}
+
+// circuitOpenError 构造带剩余时间的熔断错误
+func (b *circuitBreaker) circuitOpenError(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	remaining := b.openUntil.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Errorf("%w: retry in %s", ErrCircuitOpen, remaining.Round(time.Second))
+}
@@ -3,6 +3,7 @@ package goldsky
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -36,9 +37,29 @@ type GoldskyService struct {
 	wg                  sync.WaitGroup
 	syncInterval        time.Duration
 	statusCheckInterval time.Duration
+	reconcileInterval   time.Duration
 	syncPageSize        int
+	rpcLogSource        RPCLogSource                            // 无 subgraph 的链使用的 RPC 日志数据源
+	rpcFallbackChains   []int                                   // 没有 subgraph、改用 RPC 扫日志的链
+	rpcCursorRepo       goldskyRepo.RPCFallbackCursorRepository // RPC 日志扫描游标（按合约持久化）
+	rpcFallbackLookback uint64
+	clientOptions       GoldskyClientOptions              // subgraph 查询超时、重试与熔断配置
+	subgraphHealth      map[int]types.SubgraphCheckResult // chainID -> 最近一次 subgraph 自检结果
+	txNotFound          *txNotFoundCache                  // 交易详情查询的负缓存
+
+	// lifecycleMu 串行化 Start/Stop/Restart；loopCancel 只取消同步、状态检查、对账循环，
+	// s.ctx 为服务生命周期 context，仅 Stop 时取消
+	lifecycleMu sync.Mutex
+	started     bool
+	stopped     bool
+	loopCancel  context.CancelFunc
 }
 
+var (
+	ErrGoldskyServiceNotStarted = errors.New("goldsky service not started")
+	ErrGoldskyServiceStopped    = errors.New("goldsky service already stopped")
+)
+
 // NewGoldskyService 创建新的 Goldsky 服务
 func NewGoldskyService(
 	chainRepo chainRepo.Repository,
@@ -53,8 +74,13 @@ func NewGoldskyService(
 
 	syncInterval := 10 * time.Minute
 	statusCheckInterval := 30 * time.Second
+	reconcileInterval := time.Duration(0)
 	syncPageSize := 500
+	rpcFallbackLookback := defaultRPCFallbackLookbackBlocks
 	var workers, buffer int
+	var drainTimeout, enqueueTimeout time.Duration
+	var clientOptions GoldskyClientOptions
+	txNotFoundTTL := defaultTxNotFoundCacheTTL
 	if cfg != nil {
 		if cfg.Goldsky.SyncInterval > 0 {
 			syncInterval = cfg.Goldsky.SyncInterval
@@ -62,14 +88,36 @@ func NewGoldskyService(
 		if cfg.Goldsky.StatusCheckInterval > 0 {
 			statusCheckInterval = cfg.Goldsky.StatusCheckInterval
 		}
+		reconcileInterval = cfg.Goldsky.ReconcileInterval
+		txNotFoundTTL = cfg.Goldsky.TxNotFoundCacheTTL
 		if cfg.Goldsky.SyncPageSize > 0 {
 			syncPageSize = cfg.Goldsky.SyncPageSize
 		}
+		if cfg.Goldsky.RPCFallbackLookbackBlocks > 0 {
+			rpcFallbackLookback = cfg.Goldsky.RPCFallbackLookbackBlocks
+		}
+		clientOptions = GoldskyClientOptions{
+			MaxRetries:         cfg.Goldsky.QueryMaxRetries,
+			RetryBaseDelay:     cfg.Goldsky.QueryRetryBaseDelay,
+			RetryMaxDelay:      cfg.Goldsky.QueryRetryMaxDelay,
+			BreakerThreshold:   cfg.Goldsky.CircuitBreakerThreshold,
+			BreakerCooldown:    cfg.Goldsky.CircuitBreakerCooldown,
+			RequestTimeout:     cfg.Goldsky.RequestTimeout,
+			SlowQueryThreshold: cfg.Goldsky.SlowQueryThreshold,
+		}
 		workers = cfg.Notification.WorkerCount
 		buffer = cfg.Notification.QueueBuffer
+		drainTimeout = cfg.Notification.DrainTimeout
+		enqueueTimeout = cfg.Notification.EnqueueTimeout
 	}
 
 	dispatcher := NewNotificationDispatcher(emailSvc, notificationSvc, workers, buffer)
+	if drainTimeout > 0 {
+		dispatcher.drainTimeout = drainTimeout
+	}
+	if enqueueTimeout > 0 {
+		dispatcher.enqueueTimeout = enqueueTimeout
+	}
 
 	return &GoldskyService{
 		chainRepo:           chainRepo,
@@ -84,7 +132,12 @@ func NewGoldskyService(
 		cancel:              cancel,
 		syncInterval:        syncInterval,
 		statusCheckInterval: statusCheckInterval,
+		reconcileInterval:   reconcileInterval,
 		syncPageSize:        syncPageSize,
+		rpcFallbackLookback: rpcFallbackLookback,
+		clientOptions:       clientOptions,
+		subgraphHealth:      make(map[int]types.SubgraphCheckResult),
+		txNotFound:          newTxNotFoundCache(txNotFoundTTL),
 	}
 }
 
@@ -93,11 +146,31 @@ func (s *GoldskyService) Dispatcher() *NotificationDispatcher {
 	return s.dispatcher
 }
 
-// Start 启动 Goldsky 服务
+// NotificationQueueStats 获取通知分发队列统计（含最近一次关闭排空结果）
+func (s *GoldskyService) NotificationQueueStats() types.NotificationQueueStats {
+	if s.dispatcher == nil {
+		return types.NotificationQueueStats{}
+	}
+	return s.dispatcher.Stats()
+}
+
+// Start 启动 Goldsky 服务；重复调用不会重复启动循环任务，Stop 之后不能再启动
 func (s *GoldskyService) Start() error {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.stopped {
+		return ErrGoldskyServiceStopped
+	}
+	if s.started {
+		logger.Warn("Goldsky service already started, ignoring Start")
+		return nil
+	}
+
 	logger.Info("Starting Goldsky service...",
 		"sync_interval", s.syncInterval.String(),
 		"status_check_interval", s.statusCheckInterval.String(),
+		"reconcile_interval", s.reconcileInterval.String(),
 		"sync_page_size", s.syncPageSize,
 	)
 
@@ -105,59 +178,146 @@ func (s *GoldskyService) Start() error {
 	if err := s.initializeClients(); err != nil {
 		return fmt.Errorf("failed to initialize Goldsky clients: %w", err)
 	}
+	s.checkAllSubgraphs()
 
 	// 启动通知分发器 worker 池
 	if s.dispatcher != nil {
 		s.dispatcher.Start(s.ctx)
 	}
 
-	// 启动同步任务
-	s.wg.Add(1)
-	go s.syncFlowsLoop()
-
-	// 启动状态检查任务
-	s.wg.Add(1)
-	go s.checkFlowStatusLoop()
+	s.startLoops()
+	s.started = true
 
 	logger.Info("Goldsky service started successfully")
 	return nil
 }
 
-// Stop 停止 Goldsky 服务
+// Restart 停止循环任务、重新加载链配置初始化客户端后再启动循环任务
+// 通知分发器保持运行（webhook 处理器共用），已排队的通知不受影响；
+// 重新初始化失败时沿用原有客户端恢复循环任务并返回错误
+func (s *GoldskyService) Restart() error {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.stopped {
+		return ErrGoldskyServiceStopped
+	}
+	if !s.started {
+		return ErrGoldskyServiceNotStarted
+	}
+
+	logger.Info("Restarting Goldsky service...")
+	s.stopLoops()
+
+	err := s.initializeClients()
+	if err != nil {
+		logger.Error("Failed to reinitialize Goldsky clients, keeping previous clients", err)
+	} else {
+		s.checkAllSubgraphs()
+	}
+
+	s.startLoops()
+	if err != nil {
+		return fmt.Errorf("failed to initialize Goldsky clients: %w", err)
+	}
+
+	logger.Info("Goldsky service restarted successfully")
+	return nil
+}
+
+// Stop 停止 Goldsky 服务；未启动或重复调用时同样安全
 func (s *GoldskyService) Stop() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+
 	logger.Info("Stopping Goldsky service...")
 	s.cancel()
-	s.wg.Wait()
+	s.stopLoops()
+	// 启动失败时 webhook 仍可能已向分发器投递任务，统一关闭以记录排空结果
 	if s.dispatcher != nil {
 		s.dispatcher.Stop()
 	}
 	logger.Info("Goldsky service stopped")
 }
 
-// initializeClients 初始化所有链的 Goldsky 客户端
+// startLoops 启动同步、状态检查与对账循环任务（调用方持有 lifecycleMu）
+func (s *GoldskyService) startLoops() {
+	loopCtx, cancel := context.WithCancel(s.ctx)
+	s.loopCancel = cancel
+
+	// 启动同步任务
+	s.wg.Add(1)
+	go s.syncFlowsLoop(loopCtx)
+
+	// 启动状态检查任务
+	s.wg.Add(1)
+	go s.checkFlowStatusLoop(loopCtx)
+
+	// 启动状态对账任务（频率低于常规同步）
+	if s.reconcileInterval > 0 {
+		s.wg.Add(1)
+		go s.reconcileFlowsLoop(loopCtx)
+	}
+}
+
+// stopLoops 取消循环任务并等待其退出（调用方持有 lifecycleMu）
+// 进行中的一轮同步跑完后循环才会退出
+func (s *GoldskyService) stopLoops() {
+	if s.loopCancel != nil {
+		s.loopCancel()
+		s.loopCancel = nil
+	}
+	s.wg.Wait()
+}
+
+// checkAllSubgraphs 自检所有 subgraph，配置错误的链在日志中明确标出，同步时跳过
+func (s *GoldskyService) checkAllSubgraphs() {
+	results := s.CheckSubgraphs(s.ctx)
+	broken := 0
+	for _, result := range results {
+		if !result.Healthy() {
+			broken++
+		}
+	}
+	logger.Info("Subgraph self-check completed", "total", len(results), "broken", broken)
+}
+
+// initializeClients 初始化所有链的 Goldsky 客户端；重复调用时整体替换已有客户端
 func (s *GoldskyService) initializeClients() error {
 	chains, err := s.chainRepo.GetAllActiveChains()
 	if err != nil {
 		return fmt.Errorf("failed to get active chains: %w", err)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
+	clients := make(map[int]*GoldskyClient)
+	var fallbackChains []int
 	for _, chain := range chains {
 		if chain.SubgraphURL != "" {
-			client := NewGoldskyClient(chain.SubgraphURL, int(chain.ChainID))
-			s.clients[int(chain.ChainID)] = client
+			client := NewGoldskyClient(chain.SubgraphURL, int(chain.ChainID), s.clientOptions)
+			clients[int(chain.ChainID)] = client
 			logger.Info("Initialized Goldsky client", "chain_id", chain.ChainID, "chain_name", chain.ChainName)
+		} else if s.rpcLogSource != nil {
+			fallbackChains = append(fallbackChains, int(chain.ChainID))
+			logger.Info("No subgraph for chain, using RPC log scanning", "chain_id", chain.ChainID, "chain_name", chain.ChainName)
 		}
 	}
 
-	logger.Info("Initialized Goldsky clients", "count", len(s.clients))
+	s.mu.Lock()
+	s.clients = clients
+	s.rpcFallbackChains = fallbackChains
+	s.mu.Unlock()
+
+	logger.Info("Initialized Goldsky clients", "count", len(clients), "rpc_fallback_count", len(fallbackChains))
 	return nil
 }
 
 // syncFlowsLoop 同步 Flows 的循环任务
-func (s *GoldskyService) syncFlowsLoop() {
+func (s *GoldskyService) syncFlowsLoop(ctx context.Context) {
 	defer s.wg.Done()
 	defer logger.Info("Goldsky sync flows loop stopped")
 
@@ -169,7 +329,7 @@ func (s *GoldskyService) syncFlowsLoop() {
 
 	for {
 		select {
-		case <-s.ctx.Done():
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			s.syncAllFlows()
@@ -186,6 +346,8 @@ func (s *GoldskyService) syncAllFlows() {
 	for chainID, client := range s.clients {
 		clients[chainID] = client
 	}
+	fallbackChains := append([]int(nil), s.rpcFallbackChains...)
+	source := s.rpcLogSource
 	s.mu.RUnlock()
 
 	var wg sync.WaitGroup
@@ -199,12 +361,33 @@ func (s *GoldskyService) syncAllFlows() {
 		}(chainID, client)
 	}
 
+	// 没有 subgraph 的链直接扫描 RPC 日志
+	for _, chainID := range fallbackChains {
+		wg.Add(1)
+		go func(cid int) {
+			defer wg.Done()
+			if err := s.syncCompoundFlowsFromRPC(cid, source); err != nil {
+				logger.Error("Failed to sync flows from RPC for chain", err, "chain_id", cid)
+			}
+		}(chainID)
+	}
+
 	wg.Wait()
 	logger.Info("Finished syncing flows from Goldsky")
 }
 
 // syncFlowsForChain 同步指定链的 Flows 和统计数据
 func (s *GoldskyService) syncFlowsForChain(chainID int, client *GoldskyClient) error {
+	// subgraph 持续失败时跳过本轮，避免每轮都耗在重试上
+	if client.CircuitOpen() {
+		logger.Warn("Skipping flow sync for chain, Goldsky circuit breaker is open", "chain_id", chainID)
+		return nil
+	}
+	if ok, reason := s.subgraphUsable(chainID, client); !ok {
+		logger.Warn("Skipping flow sync for chain, subgraph self-check failed", "chain_id", chainID, "reason", reason)
+		return nil
+	}
+
 	// 获取该链上所有激活的合约地址
 	compoundContracts, err := s.timelockRepo.GetAllActiveCompoundTimelocks(s.ctx, chainID)
 	if err != nil {
@@ -218,7 +401,10 @@ func (s *GoldskyService) syncFlowsForChain(chainID int, client *GoldskyClient) e
 			compoundAddresses[i] = contract.ContractAddress
 		}
 
-		if err := s.syncCompoundFlows(chainID, client, compoundAddresses); err != nil {
+		// 与 webhook 一致，只写入达到链确认深度的事件，未确认的留到后续轮次
+		if blockLimit, err := s.confirmedBlockLimit(chainID); err != nil {
+			logger.Warn("Skipping compound flow sync, confirmation check failed", "chain_id", chainID, "error", err)
+		} else if err := s.syncCompoundFlows(chainID, client, compoundAddresses, blockLimit); err != nil {
 			logger.Error("Failed to sync compound flows", err, "chain_id", chainID)
 		}
 	}
@@ -232,7 +418,8 @@ func (s *GoldskyService) syncFlowsForChain(chainID int, client *GoldskyClient) e
 }
 
 // syncCompoundFlows 同步 Compound Flows（游标分页 + 批量读本地 DB 避免 N+1）
-func (s *GoldskyService) syncCompoundFlows(chainID int, client *GoldskyClient, contractAddresses []string) error {
+// 最新交易在 blockLimit 之后的 flow 尚未达到确认深度，本轮跳过
+func (s *GoldskyService) syncCompoundFlows(chainID int, client *GoldskyClient, contractAddresses []string, blockLimit uint64) error {
 	start := time.Now()
 	pageSize := s.syncPageSize
 	if pageSize <= 0 {
@@ -246,9 +433,15 @@ func (s *GoldskyService) syncCompoundFlows(chainID int, client *GoldskyClient, c
 		// 失败也不阻塞，让后面每条自己走 GetCompoundFlowByID 兜底
 		localMap = map[string]*types.CompoundTimelockFlowDB{}
 	}
+	// 已归档的终态 flow 不再写回热表
+	archivedKeys, err := s.flowRepo.GetArchivedCompoundFlowKeys(s.ctx, chainID, contractAddresses)
+	if err != nil {
+		return fmt.Errorf("failed to load archived compound flows: %w", err)
+	}
 
 	var totalFetched int
 	var totalUpserted int
+	var totalUnconfirmed int
 	skip := 0
 	for {
 		flows, err := client.QueryCompoundFlowsPage(s.ctx, contractAddresses, pageSize, skip)
@@ -261,6 +454,10 @@ func (s *GoldskyService) syncCompoundFlows(chainID int, client *GoldskyClient, c
 		totalFetched += len(flows)
 
 		for _, goldskyFlow := range flows {
+			if compoundFlowLatestBlock(goldskyFlow) > blockLimit {
+				totalUnconfirmed++
+				continue
+			}
 			dbFlow, err := ConvertGoldskyCompoundFlowToDB(goldskyFlow, chainID)
 			if err != nil {
 				logger.Error("Failed to convert compound flow", err, "flow_id", goldskyFlow.FlowID)
@@ -268,6 +465,9 @@ func (s *GoldskyService) syncCompoundFlows(chainID int, client *GoldskyClient, c
 			}
 
 			key := goldskyRepo.CompoundFlowKey(dbFlow.FlowID, dbFlow.ContractAddress)
+			if archivedKeys[key] {
+				continue
+			}
 			if oldFlow, ok := localMap[key]; ok && oldFlow != nil {
 				// 保护本地状态：ready/expired 不被 goldsky 的 waiting 覆盖
 				if (oldFlow.Status == "ready" || oldFlow.Status == "expired") && dbFlow.Status == "waiting" {
@@ -301,13 +501,14 @@ func (s *GoldskyService) syncCompoundFlows(chainID int, client *GoldskyClient, c
 		"contracts", len(contractAddresses),
 		"fetched", totalFetched,
 		"upserted", totalUpserted,
+		"unconfirmed", totalUnconfirmed,
 		"elapsed_ms", time.Since(start).Milliseconds(),
 	)
 	return nil
 }
 
 // checkFlowStatusLoop 检查 Flow 状态的循环任务（每30秒）
-func (s *GoldskyService) checkFlowStatusLoop() {
+func (s *GoldskyService) checkFlowStatusLoop(ctx context.Context) {
 	defer s.wg.Done()
 	defer logger.Info("Goldsky check flow status loop stopped")
 
@@ -316,7 +517,7 @@ func (s *GoldskyService) checkFlowStatusLoop() {
 
 	for {
 		select {
-		case <-s.ctx.Done():
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			s.checkAndUpdateFlowStatus()
@@ -333,19 +534,61 @@ func (s *GoldskyService) checkAndUpdateFlowStatus() {
 	if err != nil {
 		logger.Error("Failed to get compound flows need status update", err)
 	} else {
-		for _, flow := range compoundFlows {
-			newStatus := s.calculateNewStatus(flow.Status, flow.Eta, flow.ExpiredAt, now)
-			if newStatus != flow.Status {
-				if err := s.flowRepo.UpdateCompoundFlowStatus(s.ctx, flow.FlowID, flow.ChainID, flow.ContractAddress, newStatus); err != nil {
-					logger.Error("Failed to update compound flow status", err, "flow_id", flow.FlowID, "new_status", newStatus)
-					continue
-				}
+		s.applyCompoundFlowStatuses(s.ctx, compoundFlows, now, "status_check")
+	}
 
-				logger.Info("Updated compound flow status", "flow_id", flow.FlowID, "old_status", flow.Status, "new_status", newStatus)
-				s.enqueueFlowNotification(flow.ChainID, flow.ContractAddress, flow.FlowID, "compound", flow.Status, newStatus, nil, "", "status_check")
-			}
+	// 检查 OpenZeppelin Flows（查询已排除前置操作尚未执行的操作，它们保持 waiting）
+	ozFlows, err := s.flowRepo.GetOpenzeppelinFlowsNeedStatusUpdate(s.ctx, now, 100)
+	if err != nil {
+		logger.Error("Failed to get openzeppelin flows need status update", err)
+		return
+	}
+	for _, flow := range ozFlows {
+		newStatus := s.calculateNewStatus(flow.Status, flow.Eta, nil, now)
+		if newStatus == flow.Status {
+			continue
+		}
+		if err := s.flowRepo.UpdateOpenzeppelinFlowStatus(s.ctx, flow.FlowID, flow.ChainID, flow.ContractAddress, newStatus); err != nil {
+			logger.Error("Failed to update openzeppelin flow status", err, "flow_id", flow.FlowID, "new_status", newStatus)
+			continue
+		}
+
+		logger.Info("Updated openzeppelin flow status", "flow_id", flow.FlowID, "old_status", flow.Status, "new_status", newStatus)
+		s.enqueueFlowNotification(flow.ChainID, flow.ContractAddress, flow.FlowID, "openzeppelin", flow.Status, newStatus, nil, "", "status_check")
+	}
+}
+
+// RecalculateCompoundFlowStatuses 立即按 calculateNewStatus 重新计算给定 Compound Flows 的状态，
+// 与定时检查共用同一逻辑：状态变化时写库并投递通知，返回实际发生的变更
+func (s *GoldskyService) RecalculateCompoundFlowStatuses(ctx context.Context, flows []types.CompoundTimelockFlowDB, source string) []types.FlowStatusChange {
+	return s.applyCompoundFlowStatuses(ctx, flows, time.Now(), source)
+}
+
+// applyCompoundFlowStatuses 计算并应用 Compound Flows 的状态变化
+func (s *GoldskyService) applyCompoundFlowStatuses(ctx context.Context, flows []types.CompoundTimelockFlowDB, now time.Time, source string) []types.FlowStatusChange {
+	var changes []types.FlowStatusChange
+	for _, flow := range flows {
+		newStatus := s.calculateNewStatus(flow.Status, flow.Eta, flow.ExpiredAt, now)
+		if newStatus == flow.Status {
+			continue
+		}
+		if err := s.flowRepo.UpdateCompoundFlowStatus(ctx, flow.FlowID, flow.ChainID, flow.ContractAddress, newStatus); err != nil {
+			logger.Error("Failed to update compound flow status", err, "flow_id", flow.FlowID, "new_status", newStatus)
+			continue
 		}
+
+		logger.Info("Updated compound flow status", "flow_id", flow.FlowID, "old_status", flow.Status, "new_status", newStatus, "source", source)
+		s.enqueueFlowNotification(flow.ChainID, flow.ContractAddress, flow.FlowID, "compound", flow.Status, newStatus, nil, "", source)
+		changes = append(changes, types.FlowStatusChange{
+			Standard:        "compound",
+			FlowID:          flow.FlowID,
+			ChainID:         flow.ChainID,
+			ContractAddress: flow.ContractAddress,
+			StatusFrom:      flow.Status,
+			StatusTo:        newStatus,
+		})
 	}
+	return changes
 }
 
 // calculateNewStatus 计算新的状态
@@ -393,7 +636,9 @@ func (s *GoldskyService) enqueueFlowNotification(chainID int, contractAddress, f
 
 // SyncFlowsForContract 同步特定合约的flows
 func (s *GoldskyService) SyncFlowsForContract(ctx context.Context, chainID int, standard, contractAddress string) error {
+	s.mu.RLock()
 	client, exists := s.clients[chainID]
+	s.mu.RUnlock()
 	if !exists {
 		return fmt.Errorf("no goldsky client for chain %d", chainID)
 	}
@@ -406,6 +651,22 @@ func (s *GoldskyService) SyncFlowsForContract(ctx context.Context, chainID int,
 	}
 }
 
+// ApplyCompoundGracePeriod 按合约宽限期补算数据源未提供过期时间的 flow，并修正此前的估算值
+func (s *GoldskyService) ApplyCompoundGracePeriod(ctx context.Context, chainID int, contractAddress string, gracePeriod int64, estimated bool) error {
+	if gracePeriod <= 0 {
+		return nil
+	}
+	updated, err := s.flowRepo.ApplyCompoundGracePeriod(ctx, chainID, contractAddress, gracePeriod, estimated)
+	if err != nil {
+		return fmt.Errorf("failed to apply compound grace period: %w", err)
+	}
+	if updated > 0 {
+		logger.Info("Applied compound grace period to flows", "chain_id", chainID, "contract_address", contractAddress,
+			"grace_period", gracePeriod, "estimated", estimated, "updated", updated)
+	}
+	return nil
+}
+
 // syncCompoundFlowsForContract 同步特定Compound合约的flows（游标分页 + 批量读本地 DB）
 func (s *GoldskyService) syncCompoundFlowsForContract(ctx context.Context, chainID int, client *GoldskyClient, contractAddress string) error {
 	start := time.Now()
@@ -419,8 +680,18 @@ func (s *GoldskyService) syncCompoundFlowsForContract(ctx context.Context, chain
 		logger.Error("Failed to batch load local compound flows", err, "chain_id", chainID, "contract_address", contractAddress)
 		localMap = map[string]*types.CompoundTimelockFlowDB{}
 	}
+	archivedKeys, err := s.flowRepo.GetArchivedCompoundFlowKeys(ctx, chainID, []string{contractAddress})
+	if err != nil {
+		return fmt.Errorf("failed to load archived compound flows for contract %s: %w", contractAddress, err)
+	}
 
-	var totalFetched, totalUpserted int
+	// 与 webhook 一致，只写入达到链确认深度的事件，未确认的由定时同步补齐
+	blockLimit, err := s.confirmedBlockLimit(chainID)
+	if err != nil {
+		return fmt.Errorf("failed to check confirmation depth for contract %s: %w", contractAddress, err)
+	}
+
+	var totalFetched, totalUpserted, totalUnconfirmed int
 	skip := 0
 	for {
 		flows, err := client.QueryCompoundFlowsPage(ctx, []string{contractAddress}, pageSize, skip)
@@ -433,6 +704,10 @@ func (s *GoldskyService) syncCompoundFlowsForContract(ctx context.Context, chain
 		totalFetched += len(flows)
 
 		for _, goldskyFlow := range flows {
+			if compoundFlowLatestBlock(goldskyFlow) > blockLimit {
+				totalUnconfirmed++
+				continue
+			}
 			dbFlow, err := ConvertGoldskyCompoundFlowToDB(goldskyFlow, chainID)
 			if err != nil {
 				logger.Error("Failed to convert compound flow", err, "flow_id", goldskyFlow.FlowID, "contract_address", contractAddress)
@@ -440,6 +715,9 @@ func (s *GoldskyService) syncCompoundFlowsForContract(ctx context.Context, chain
 			}
 
 			key := goldskyRepo.CompoundFlowKey(dbFlow.FlowID, dbFlow.ContractAddress)
+			if archivedKeys[key] {
+				continue
+			}
 			if oldFlow, ok := localMap[key]; ok && oldFlow != nil {
 				if (oldFlow.Status == "ready" || oldFlow.Status == "expired") && dbFlow.Status == "waiting" {
 					dbFlow.Status = oldFlow.Status
@@ -468,6 +746,7 @@ func (s *GoldskyService) syncCompoundFlowsForContract(ctx context.Context, chain
 		"contract_address", contractAddress,
 		"fetched", totalFetched,
 		"upserted", totalUpserted,
+		"unconfirmed", totalUnconfirmed,
 		"elapsed_ms", time.Since(start).Milliseconds(),
 	)
 
@@ -515,7 +794,7 @@ func (s *GoldskyService) GetCompoundFlowDetail(ctx context.Context, chainID int,
 	s.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("no Goldsky client for chain %d", chainID)
+		return nil, fmt.Errorf("%w %d", ErrNoGoldskyClient, chainID)
 	}
 
 	flow, err := client.QueryCompoundFlowByFlowID(ctx, flowID)
@@ -526,6 +805,27 @@ func (s *GoldskyService) GetCompoundFlowDetail(ctx context.Context, chainID int,
 	return flow, nil
 }
 
+// GetOpenzeppelinScheduledCalls 从 Goldsky 获取 OpenZeppelin 操作的全部子调用（按 index 升序）
+func (s *GoldskyService) GetOpenzeppelinScheduledCalls(ctx context.Context, chainID int, contractAddress, operationID string) ([]types.OpenzeppelinFlowCallDB, error) {
+	s.mu.RLock()
+	client, exists := s.clients[chainID]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w %d", ErrNoGoldskyClient, chainID)
+	}
+
+	txs, err := client.QueryOpenzeppelinScheduledCalls(ctx, contractAddress, operationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query openzeppelin scheduled calls: %w", err)
+	}
+
+	return AggregateOpenzeppelinScheduledCalls(txs, chainID)[operationID], nil
+}
+
+// ErrNoGoldskyClient 该链未配置 subgraph（使用 RPC 扫描日志或未启用），无法查询 Goldsky 索引数据
+var ErrNoGoldskyClient = errors.New("no Goldsky client for chain")
+
 // GetTransactionDetail 获取交易详情（用于 API）
 func (s *GoldskyService) GetTransactionDetail(ctx context.Context, chainID int, standard, txHash string) (*types.CompoundTimelockTransactionDetail, error) {
 	s.mu.RLock()
@@ -533,16 +833,23 @@ func (s *GoldskyService) GetTransactionDetail(ctx context.Context, chainID int,
 	s.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("no Goldsky client for chain %d", chainID)
+		return nil, fmt.Errorf("%w %d", ErrNoGoldskyClient, chainID)
 	}
 
 	if standard == "compound" {
+		// 短时间内已确认不存在的交易直接返回，不再请求 subgraph
+		cacheKey := txNotFoundCacheKey(chainID, standard, txHash)
+		if s.txNotFound.Hit(cacheKey, time.Now()) {
+			return nil, ErrTransactionNotFound
+		}
+
 		tx, err := client.QueryCompoundTransactionByTxHash(ctx, txHash)
 		if err != nil {
 			return nil, fmt.Errorf("failed to query compound transaction: %w", err)
 		}
 		if tx == nil {
-			return nil, fmt.Errorf("transaction not found")
+			s.txNotFound.Add(cacheKey, time.Now())
+			return nil, ErrTransactionNotFound
 		}
 
 		// 转换为响应格式
@@ -600,17 +907,108 @@ func (s *GoldskyService) convertCompoundTransactionToDetail(tx *types.GoldskyCom
 		}
 	}
 
-	// 从 chain repository 获取 chain_name
+	detail.ChainName = s.transactionChainName(chainID)
+	return detail, nil
+}
+
+// GetOpenzeppelinTransactionDetail 获取 OpenZeppelin 交易详情（批量操作的交易返回首个事件）
+func (s *GoldskyService) GetOpenzeppelinTransactionDetail(ctx context.Context, chainID int, txHash string) (*types.OpenzeppelinTimelockTransactionDetail, error) {
+	s.mu.RLock()
+	client, exists := s.clients[chainID]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w %d", ErrNoGoldskyClient, chainID)
+	}
+
+	// 短时间内已确认不存在的交易直接返回，不再请求 subgraph
+	cacheKey := txNotFoundCacheKey(chainID, "openzeppelin", txHash)
+	if s.txNotFound.Hit(cacheKey, time.Now()) {
+		return nil, ErrTransactionNotFound
+	}
+
+	tx, err := client.QueryOpenzeppelinTransactionByTxHash(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query openzeppelin transaction: %w", err)
+	}
+	if tx == nil {
+		s.txNotFound.Add(cacheKey, time.Now())
+		return nil, ErrTransactionNotFound
+	}
+
+	return s.convertOpenzeppelinTransactionToDetail(tx, chainID)
+}
+
+// convertOpenzeppelinTransactionToDetail 转换 OpenZeppelin Transaction 为详情格式
+func (s *GoldskyService) convertOpenzeppelinTransactionToDetail(tx *types.GoldskyOpenzeppelinTransaction, chainID int) (*types.OpenzeppelinTimelockTransactionDetail, error) {
+	blockNumber, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block number: %w", err)
+	}
+
+	blockTimestamp, err := parseTimestamp(tx.BlockTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block timestamp: %w", err)
+	}
+
+	detail := &types.OpenzeppelinTimelockTransactionDetail{
+		TxHash:           tx.TxHash,
+		BlockNumber:      blockNumber,
+		BlockTimestamp:   blockTimestamp,
+		ChainID:          chainID,
+		ContractAddress:  tx.ContractAddress,
+		FromAddress:      tx.FromAddress,
+		ToAddress:        tx.ContractAddress,
+		TxStatus:         "success",
+		EventType:        tx.EventType,
+		EventID:          tx.EventId,
+		EventTarget:      tx.EventTarget,
+		EventValue:       tx.EventValue,
+		EventPredecessor: tx.EventPredecessor,
+	}
+
+	if tx.EventIndex != nil {
+		if index, err := strconv.Atoi(*tx.EventIndex); err == nil {
+			detail.EventIndex = index
+		}
+	}
+	if tx.EventDelay != nil {
+		if delay, err := strconv.ParseInt(*tx.EventDelay, 10, 64); err == nil {
+			detail.EventDelay = &delay
+		}
+	}
+
+	// 解析 EventData (hex string -> bytes)
+	if tx.EventData != nil && *tx.EventData != "" {
+		detail.EventData = *tx.EventData
+		if callDataBytes, err := hex.DecodeString(strings.TrimPrefix(*tx.EventData, "0x")); err == nil {
+			detail.EventCallData = callDataBytes
+		}
+	}
+
+	detail.ChainName = s.transactionChainName(chainID)
+	return detail, nil
+}
+
+// transactionChainName 交易详情中的链名称，查询失败时留空
+func (s *GoldskyService) transactionChainName(chainID int) string {
 	chain, err := s.chainRepo.GetChainByChainID(s.ctx, int64(chainID))
 	if err != nil {
 		logger.Warn("Failed to get chain info", "chain_id", chainID, "error", err)
-		// 设置默认值或者留空
-		detail.ChainName = ""
-	} else {
-		detail.ChainName = chain.ChainName
+		return ""
 	}
+	return chain.ChainName
+}
 
-	return detail, nil
+// clientForChain 优先复用已初始化的客户端（共享熔断状态），不存在时按 subgraphURL 新建
+func (s *GoldskyService) clientForChain(chainID int, subgraphURL string) *GoldskyClient {
+	s.mu.RLock()
+	client, ok := s.clients[chainID]
+	s.mu.RUnlock()
+	if ok && client.subgraphURL == subgraphURL {
+		return client
+	}
+	return NewGoldskyClient(subgraphURL, chainID, s.clientOptions)
 }
 
 // GetGlobalContractCount 获取全局合约数量（从Goldsky GlobalStatistics获取）
@@ -627,7 +1025,7 @@ func (s *GoldskyService) GetGlobalContractCount(ctx context.Context) (int64, err
 			continue
 		}
 
-		client := NewGoldskyClient(chain.SubgraphURL, int(chain.ChainID))
+		client := s.clientForChain(int(chain.ChainID), chain.SubgraphURL)
 		stats, err := client.QueryGlobalStatistics(ctx)
 		if err != nil {
 			logger.Warn("Failed to query global statistics for chain", "chain_id", chain.ChainID, "chain_name", chain.ChainName, "error", err)
@@ -662,7 +1060,7 @@ func (s *GoldskyService) GetGlobalTransactionCount(ctx context.Context) (int64,
 			continue
 		}
 
-		client := NewGoldskyClient(chain.SubgraphURL, int(chain.ChainID))
+		client := s.clientForChain(int(chain.ChainID), chain.SubgraphURL)
 		stats, err := client.QueryGlobalStatistics(ctx)
 		if err != nil {
 			logger.Warn("Failed to query global statistics for chain", "chain_id", chain.ChainID, "chain_name", chain.ChainName, "error", err)
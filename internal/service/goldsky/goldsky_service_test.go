@@ -0,0 +1,45 @@
+package goldsky
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	chainRepo "timelocker-backend/internal/repository/chain"
+	"timelocker-backend/internal/types"
+)
+
+// fakeActiveChainRepo 只实现 GetAllActiveChains，供 initializeClients 重建客户端
+type fakeActiveChainRepo struct {
+	chainRepo.Repository
+}
+
+func (fakeActiveChainRepo) GetAllActiveChains() ([]*types.SupportChain, error) {
+	return []*types.SupportChain{{ChainID: 1, ChainName: "test", SubgraphURL: "http://127.0.0.1:1/subgraph"}}, nil
+}
+
+// TestSyncFlowsForContractConcurrentWithRestart Restart 替换 clients 的同时按合约同步，
+// 在 go test -race 下验证 SyncFlowsForContract 读取 clients 时持有锁
+func TestSyncFlowsForContractConcurrentWithRestart(t *testing.T) {
+	s := &GoldskyService{chainRepo: fakeActiveChainRepo{}, clients: map[int]*GoldskyClient{}}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if err := s.initializeClients(); err != nil {
+				t.Errorf("initializeClients: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			// 不支持的 standard 在取到客户端后直接返回，不会发起请求
+			_ = s.SyncFlowsForContract(context.Background(), 1, "unsupported", "0x0000000000000000000000000000000000000001")
+		}
+	}()
+	wg.Wait()
+}
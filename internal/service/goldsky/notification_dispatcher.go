@@ -2,11 +2,16 @@ package goldsky
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"timelocker-backend/internal/service/email"
 	"timelocker-backend/internal/service/notification"
+	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
 )
 
@@ -23,21 +28,47 @@ type flowNotificationJob struct {
 	Source           string // 日志用：status_check / webhook
 }
 
+// defaultDrainTimeout 关闭时等待队列中剩余通知发送完成的默认最长时间（notification.drain_timeout 未配置时使用，需在 main 的优雅关闭窗口内）
+const defaultDrainTimeout = 10 * time.Second
+
+// defaultEnqueueTimeout 队列满时投递的默认最长等待时间（notification.enqueue_timeout 未配置时使用）
+const defaultEnqueueTimeout = 2 * time.Second
+
+// flowKey 同一 flow 的任务会落到同一个 worker，保证通知按状态变化顺序发送
+func (j flowNotificationJob) flowKey() string {
+	return fmt.Sprintf("%s:%d:%s:%s", j.Standard, j.ChainID, strings.ToLower(j.ContractAddress), j.FlowID)
+}
+
 // NotificationDispatcher 用固定数量的 worker 消费通知队列，避免瞬时大量 goroutine
-// 打爆外部 SMTP / Webhook。
+// 打爆外部 SMTP / Webhook。每个 worker 独占一个队列，任务按 flow 分片，同一 flow 内保持顺序。
 type NotificationDispatcher struct {
 	emailSvc        email.EmailService
 	notificationSvc notification.NotificationService
-	jobs            chan flowNotificationJob
-	workers         int
-	wg              sync.WaitGroup
-	startOnce       sync.Once
-	stopOnce        sync.Once
-	stopped         chan struct{}
+	shards          []chan flowNotificationJob
+	drainTimeout    time.Duration
+	enqueueTimeout  time.Duration
+
+	// ctx 为发送任务的父 context，仅在关闭超时后取消，正常关闭时队列可以完整排空
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu 保护 closed 与分片 channel 的关闭：Enqueue 持读锁发送，Stop 持写锁关闭
+	mu        sync.RWMutex
+	closed    bool
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	// 累计统计：enqueued 投递成功数，sent 处理完成数，dropped 丢弃数
+	enqueued  atomic.Int64
+	sent      atomic.Int64
+	dropped   atomic.Int64
+	lastDrain atomic.Pointer[types.NotificationDrainSummary]
 }
 
 // NewNotificationDispatcher 创建一个通知分发器
-// workers <= 0 时兜底为 4；buffer <= 0 时兜底为 1024
+// workers <= 0 时兜底为 4；buffer <= 0 时兜底为 1024（buffer 为全部 worker 队列的总容量）
 func NewNotificationDispatcher(
 	emailSvc email.EmailService,
 	notificationSvc notification.NotificationService,
@@ -50,61 +81,193 @@ func NewNotificationDispatcher(
 	if buffer <= 0 {
 		buffer = 1024
 	}
+	perShard := buffer / workers
+	if perShard < 1 {
+		perShard = 1
+	}
+
+	shards := make([]chan flowNotificationJob, workers)
+	for i := range shards {
+		shards[i] = make(chan flowNotificationJob, perShard)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	return &NotificationDispatcher{
 		emailSvc:        emailSvc,
 		notificationSvc: notificationSvc,
-		jobs:            make(chan flowNotificationJob, buffer),
-		workers:         workers,
-		stopped:         make(chan struct{}),
+		shards:          shards,
+		drainTimeout:    defaultDrainTimeout,
+		enqueueTimeout:  defaultEnqueueTimeout,
+		ctx:             ctx,
+		cancel:          cancel,
+		quit:            make(chan struct{}),
 	}
 }
 
 // Start 启动 worker 池
+// worker 不跟随调用方 context 退出，而是在 Stop 关闭队列后处理完剩余任务再退出
 func (d *NotificationDispatcher) Start(ctx context.Context) {
 	d.startOnce.Do(func() {
-		for i := 0; i < d.workers; i++ {
+		for i := range d.shards {
 			d.wg.Add(1)
-			go d.run(ctx, i)
+			go d.run(i)
 		}
-		logger.Info("NotificationDispatcher started", "workers", d.workers, "buffer", cap(d.jobs))
+		logger.Info("NotificationDispatcher started", "workers", len(d.shards), "buffer_per_worker", cap(d.shards[0]))
 	})
 }
 
-// Stop 优雅关闭 worker 池（等待队列里剩余任务处理完）
+// Stop 优雅关闭 worker 池：停止接收新任务，在 drainTimeout 内等待队列里剩余任务处理完，
+// 超时后取消进行中的发送并放弃剩余任务
 func (d *NotificationDispatcher) Stop() {
 	d.stopOnce.Do(func() {
-		close(d.jobs)
-		d.wg.Wait()
-		close(d.stopped)
-		logger.Info("NotificationDispatcher stopped")
+		// 记录排空前的计数，结束后按差值统计排空窗口内的发送与丢弃
+		startedAt := time.Now()
+		sentBefore, droppedBefore := d.sent.Load(), d.dropped.Load()
+
+		// 先唤醒因队列满而阻塞的 Enqueue，再关闭队列
+		close(d.quit)
+		d.mu.Lock()
+		d.closed = true
+		pending := 0
+		for _, ch := range d.shards {
+			pending += len(ch)
+			close(ch)
+		}
+		d.mu.Unlock()
+		logger.Info("NotificationDispatcher draining", "pending", pending, "timeout", d.drainTimeout.String())
+
+		done := make(chan struct{})
+		go func() {
+			d.wg.Wait()
+			close(done)
+		}()
+
+		timedOut := false
+		select {
+		case <-done:
+		case <-time.After(d.drainTimeout):
+			timedOut = true
+			remaining := 0
+			for _, ch := range d.shards {
+				remaining += len(ch)
+			}
+			logger.Warn("NotificationDispatcher drain timeout, dropping remaining notifications", "remaining", remaining)
+			d.cancel()
+			<-done
+		}
+		d.cancel()
+
+		summary := &types.NotificationDrainSummary{
+			StartedAt:  startedAt,
+			DurationMs: time.Since(startedAt).Milliseconds(),
+			Pending:    pending,
+			Sent:       d.sent.Load() - sentBefore,
+			Dropped:    d.dropped.Load() - droppedBefore,
+			TimedOut:   timedOut,
+		}
+		d.lastDrain.Store(summary)
+		if summary.Dropped > 0 {
+			logger.Warn("NotificationDispatcher stopped, notifications dropped during drain",
+				"pending", summary.Pending, "sent", summary.Sent, "dropped", summary.Dropped,
+				"duration_ms", summary.DurationMs, "timed_out", summary.TimedOut)
+		} else {
+			logger.Info("NotificationDispatcher stopped",
+				"pending", summary.Pending, "sent", summary.Sent, "dropped", summary.Dropped,
+				"duration_ms", summary.DurationMs)
+		}
 	})
 }
 
-// Enqueue 尝试入队一条通知任务。队列满时会起临时 goroutine 兜底，确保不阻塞调用方。
+// Stats 返回通知队列累计统计与最近一次排空结果
+func (d *NotificationDispatcher) Stats() types.NotificationQueueStats {
+	pending, capacity := 0, 0
+	for _, ch := range d.shards {
+		pending += len(ch)
+		capacity += cap(ch)
+	}
+	return types.NotificationQueueStats{
+		Workers:   len(d.shards),
+		Capacity:  capacity,
+		Pending:   pending,
+		Enqueued:  d.enqueued.Load(),
+		Sent:      d.sent.Load(),
+		Dropped:   d.dropped.Load(),
+		LastDrain: d.lastDrain.Load(),
+	}
+}
+
+// Enqueue 投递一条通知任务到该 flow 对应的 worker 队列。
+// 队列满时最多等待 enqueueTimeout，超时丢弃并计入 dropped，调用方（webhook/同步处理）不会被外部发送拖住；
+// 分发器关闭后的任务会被丢弃。
 func (d *NotificationDispatcher) Enqueue(job flowNotificationJob) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		d.dropped.Add(1)
+		logger.Warn("NotificationDispatcher stopped, dropping notification",
+			"flow_id", job.FlowID,
+			"status_to", job.StatusTo,
+			"source", job.Source,
+		)
+		return
+	}
+
+	ch := d.shards[d.shardIndex(job)]
 	select {
-	case d.jobs <- job:
+	case ch <- job:
+		d.enqueued.Add(1)
+		return
 	default:
-		logger.Warn("NotificationDispatcher queue full, spawning overflow goroutine",
+	}
+
+	timer := time.NewTimer(d.enqueueTimeout)
+	defer timer.Stop()
+	select {
+	case ch <- job:
+		d.enqueued.Add(1)
+	case <-timer.C:
+		d.dropped.Add(1)
+		// 队列满丢弃意味着用户收不到该状态的通知，按错误记录以便告警
+		logger.Error("NotificationDispatcher queue full, dropping notification",
+			fmt.Errorf("notification queue full after %s", d.enqueueTimeout),
+			"chain_id", job.ChainID,
+			"contract_address", job.ContractAddress,
+			"flow_id", job.FlowID,
+			"status_from", job.StatusFrom,
+			"status_to", job.StatusTo,
+			"source", job.Source,
+		)
+	case <-d.quit:
+		d.dropped.Add(1)
+		logger.Warn("NotificationDispatcher stopping, dropping notification",
 			"flow_id", job.FlowID,
 			"status_to", job.StatusTo,
 			"source", job.Source,
 		)
-		go d.process(context.Background(), job)
 	}
 }
 
-func (d *NotificationDispatcher) run(ctx context.Context, idx int) {
+// shardIndex 按 flow key 哈希选择 worker
+func (d *NotificationDispatcher) shardIndex(job flowNotificationJob) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(job.flowKey()))
+	return int(h.Sum32() % uint32(len(d.shards)))
+}
+
+func (d *NotificationDispatcher) run(idx int) {
 	defer d.wg.Done()
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case job, ok := <-d.jobs:
-			if !ok {
-				return
-			}
-			d.process(ctx, job)
+	for job := range d.shards[idx] {
+		if d.ctx.Err() != nil {
+			d.dropped.Add(1)
+			continue // 关闭超时后丢弃剩余任务，仅排空 channel
+		}
+		d.process(d.ctx, job)
+		// 处理中因关闭超时被取消的任务计为丢弃
+		if d.ctx.Err() != nil {
+			d.dropped.Add(1)
+		} else {
+			d.sent.Add(1)
 		}
 	}
 }
@@ -0,0 +1,205 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/service/email"
+	"timelocker-backend/internal/service/notification"
+)
+
+// fakeFlowSender 记录收到的 flow 通知；block 非空时发送阻塞到 block 关闭或 ctx 取消
+type fakeFlowSender struct {
+	mu    sync.Mutex
+	calls []string
+	block chan struct{}
+}
+
+func (f *fakeFlowSender) SendFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) error {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, flowID+":"+statusTo)
+	return nil
+}
+
+func (f *fakeFlowSender) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+type fakeEmailService struct {
+	email.EmailService
+	*fakeFlowSender
+}
+
+func (f fakeEmailService) SendFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) error {
+	return f.fakeFlowSender.SendFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress)
+}
+
+type fakeNotificationService struct {
+	notification.NotificationService
+	*fakeFlowSender
+}
+
+func (f fakeNotificationService) SendFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) error {
+	return f.fakeFlowSender.SendFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress)
+}
+
+func newTestDispatcher(sender *fakeFlowSender, workers, buffer int) *NotificationDispatcher {
+	return NewNotificationDispatcher(fakeEmailService{fakeFlowSender: sender}, nil, workers, buffer)
+}
+
+func testJob(flowID, statusTo string) flowNotificationJob {
+	return flowNotificationJob{
+		Standard:        "compound",
+		ChainID:         1,
+		ContractAddress: "0x0000000000000000000000000000000000000001",
+		FlowID:          flowID,
+		StatusTo:        statusTo,
+		Source:          "test",
+	}
+}
+
+func TestNotificationDispatcherDeliversInFlowOrder(t *testing.T) {
+	emailSender, channelSender := &fakeFlowSender{}, &fakeFlowSender{}
+	d := NewNotificationDispatcher(fakeEmailService{fakeFlowSender: emailSender}, fakeNotificationService{fakeFlowSender: channelSender}, 4, 64)
+	d.Start(context.Background())
+
+	statuses := []string{"waiting", "ready", "executed"}
+	for i := 0; i < 5; i++ {
+		for _, status := range statuses {
+			d.Enqueue(testJob(fmt.Sprintf("flow-%d", i), status))
+		}
+	}
+	d.Stop()
+
+	for name, sender := range map[string]*fakeFlowSender{"email": emailSender, "channel": channelSender} {
+		calls := sender.Calls()
+		if len(calls) != 15 {
+			t.Fatalf("%s sender got %d calls, want 15", name, len(calls))
+		}
+		// 同一 flow 的通知按投递顺序发送
+		next := map[string]int{}
+		for _, call := range calls {
+			flowID, status, _ := strings.Cut(call, ":")
+			if want := statuses[next[flowID]]; status != want {
+				t.Fatalf("%s sender: %s got status %s, want %s", name, flowID, status, want)
+			}
+			next[flowID]++
+		}
+	}
+
+	stats := d.Stats()
+	if stats.Enqueued != 15 || stats.Sent != 15 || stats.Dropped != 0 {
+		t.Fatalf("stats = enqueued %d sent %d dropped %d, want 15/15/0", stats.Enqueued, stats.Sent, stats.Dropped)
+	}
+}
+
+func TestNotificationDispatcherEnqueueFullQueueTimesOut(t *testing.T) {
+	d := newTestDispatcher(&fakeFlowSender{}, 1, 1)
+	d.enqueueTimeout = 20 * time.Millisecond
+
+	// worker 未启动，第一条占满队列，第二条应在超时后被丢弃而不是一直阻塞
+	d.Enqueue(testJob("flow-1", "waiting"))
+	start := time.Now()
+	d.Enqueue(testJob("flow-1", "ready"))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Enqueue blocked for %s on a full queue", elapsed)
+	}
+
+	stats := d.Stats()
+	if stats.Enqueued != 1 || stats.Dropped != 1 || stats.Pending != 1 {
+		t.Fatalf("stats = enqueued %d dropped %d pending %d, want 1/1/1", stats.Enqueued, stats.Dropped, stats.Pending)
+	}
+}
+
+func TestNotificationDispatcherStopWakesBlockedEnqueue(t *testing.T) {
+	d := newTestDispatcher(&fakeFlowSender{}, 1, 1)
+	d.enqueueTimeout = time.Minute
+	d.drainTimeout = 50 * time.Millisecond
+
+	d.Enqueue(testJob("flow-1", "waiting"))
+	done := make(chan struct{})
+	go func() {
+		d.Enqueue(testJob("flow-1", "ready"))
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	d.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Enqueue still blocked after Stop")
+	}
+	if dropped := d.Stats().Dropped; dropped < 1 {
+		t.Fatalf("dropped = %d, want at least 1", dropped)
+	}
+}
+
+func TestNotificationDispatcherStopDrainsPending(t *testing.T) {
+	sender := &fakeFlowSender{}
+	d := newTestDispatcher(sender, 2, 16)
+
+	// 先入队再启动，Stop 需要把队列中的任务全部发完
+	for i := 0; i < 6; i++ {
+		d.Enqueue(testJob(fmt.Sprintf("flow-%d", i), "waiting"))
+	}
+	d.Start(context.Background())
+	d.Stop()
+
+	if calls := len(sender.Calls()); calls != 6 {
+		t.Fatalf("sent %d notifications, want 6", calls)
+	}
+	summary := d.Stats().LastDrain
+	if summary == nil {
+		t.Fatal("missing drain summary")
+	}
+	if summary.TimedOut || summary.Dropped != 0 {
+		t.Fatalf("drain summary = timed_out %v dropped %d, want false/0", summary.TimedOut, summary.Dropped)
+	}
+
+	// 关闭后的投递直接丢弃
+	d.Enqueue(testJob("flow-late", "waiting"))
+	if dropped := d.Stats().Dropped; dropped != 1 {
+		t.Fatalf("dropped = %d after enqueue on stopped dispatcher, want 1", dropped)
+	}
+}
+
+func TestNotificationDispatcherDrainTimeoutDropsRemaining(t *testing.T) {
+	sender := &fakeFlowSender{block: make(chan struct{})}
+	defer close(sender.block)
+	d := newTestDispatcher(sender, 1, 8)
+	d.drainTimeout = 50 * time.Millisecond
+
+	d.Start(context.Background())
+	for i := 0; i < 3; i++ {
+		d.Enqueue(testJob("flow-1", fmt.Sprintf("status-%d", i)))
+	}
+
+	start := time.Now()
+	d.Stop()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Stop took %s, drain timeout not honoured", elapsed)
+	}
+
+	summary := d.Stats().LastDrain
+	if summary == nil || !summary.TimedOut {
+		t.Fatalf("drain summary = %+v, want timed out", summary)
+	}
+	if summary.Dropped != 3 || summary.Sent != 0 {
+		t.Fatalf("drain summary = sent %d dropped %d, want 0/3", summary.Sent, summary.Dropped)
+	}
+}
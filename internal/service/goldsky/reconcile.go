@@ -0,0 +1,274 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	goldskyRepo "timelocker-backend/internal/repository/goldsky"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// reconcileCorrection 对账纠正的一条 flow；compoundFlow/openzeppelinFlow 为 subgraph 侧的完整记录，
+// 连同执行/取消交易哈希与时间一起写入
+type reconcileCorrection struct {
+	standard         string
+	contractAddress  string
+	flowID           string
+	from             string
+	to               string
+	txHash           *string
+	compoundFlow     *types.CompoundTimelockFlowDB
+	openzeppelinFlow *types.OpenzeppelinTimelockFlowDB
+}
+
+// reconciledStatus 判断本地状态是否需要按 subgraph 纠正，返回纠正后的状态，无需纠正时返回空字符串
+// subgraph 只记录事件，只有执行/取消是可信的外部终态；本地已是终态的 flow 不回退
+func reconciledStatus(localStatus, remoteStatus string) string {
+	switch remoteStatus {
+	case "executed", "cancelled":
+	default:
+		return ""
+	}
+	switch localStatus {
+	case "waiting", "ready", "expired":
+		return remoteStatus
+	default:
+		return ""
+	}
+}
+
+// reconcileFlowsLoop 定期对账本地 flow 状态与 subgraph
+func (s *GoldskyService) reconcileFlowsLoop(ctx context.Context) {
+	defer s.wg.Done()
+	defer logger.Info("Goldsky reconcile flows loop stopped")
+
+	ticker := time.NewTicker(s.reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reconcileAllFlows()
+		}
+	}
+}
+
+// reconcileAllFlows 逐链对账（低频任务，不并发以减少对 subgraph 的压力）
+func (s *GoldskyService) reconcileAllFlows() {
+	start := time.Now()
+
+	s.mu.RLock()
+	clients := make(map[int]*GoldskyClient)
+	for chainID, client := range s.clients {
+		clients[chainID] = client
+	}
+	s.mu.RUnlock()
+
+	var corrected int
+	for chainID, client := range clients {
+		if s.ctx.Err() != nil {
+			return
+		}
+		if client.CircuitOpen() {
+			logger.Warn("Skipping flow reconciliation for chain, Goldsky circuit breaker is open", "chain_id", chainID)
+			continue
+		}
+		n, err := s.reconcileChain(chainID, client)
+		if err != nil {
+			logger.Error("Failed to reconcile flows for chain", err, "chain_id", chainID)
+		}
+		corrected += n
+	}
+
+	logger.Info("Finished reconciling flow status with Goldsky",
+		"chains", len(clients),
+		"corrected", corrected,
+		"elapsed_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// reconcileChain 对账指定链上所有激活合约的 flow，返回纠正数量
+func (s *GoldskyService) reconcileChain(chainID int, client *GoldskyClient) (int, error) {
+	// 与 webhook 一致，只按达到链确认深度的事件纠正状态
+	blockLimit, err := s.confirmedBlockLimit(chainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check confirmation depth: %w", err)
+	}
+	corrections, err := s.findCompoundCorrections(chainID, client, blockLimit)
+	if err != nil {
+		return 0, err
+	}
+	ozCorrections, err := s.findOpenzeppelinCorrections(chainID, client, blockLimit)
+	if err != nil {
+		logger.Error("Failed to reconcile openzeppelin flows", err, "chain_id", chainID)
+	}
+	corrections = append(corrections, ozCorrections...)
+
+	var corrected int
+	for _, c := range corrections {
+		var updateErr error
+		switch c.standard {
+		case "compound":
+			updateErr = s.flowRepo.CreateOrUpdateCompoundFlow(s.ctx, c.compoundFlow)
+		case "openzeppelin":
+			updateErr = s.flowRepo.CreateOrUpdateOpenzeppelinFlow(s.ctx, c.openzeppelinFlow)
+		}
+		if updateErr != nil {
+			logger.Error("Failed to correct flow status", updateErr, "standard", c.standard, "chain_id", chainID, "flow_id", c.flowID, "new_status", c.to)
+			continue
+		}
+
+		corrected++
+		logger.Warn("Reconciled drifted flow status",
+			"standard", c.standard,
+			"chain_id", chainID,
+			"contract_address", c.contractAddress,
+			"flow_id", c.flowID,
+			"old_status", c.from,
+			"new_status", c.to,
+		)
+		s.enqueueFlowNotification(chainID, c.contractAddress, c.flowID, c.standard, c.from, c.to, c.txHash, "", "reconcile")
+	}
+	return corrected, nil
+}
+
+// findCompoundCorrections 按 id 游标分页拉取 subgraph 中的 Compound flow，与本地状态比较
+func (s *GoldskyService) findCompoundCorrections(chainID int, client *GoldskyClient, blockLimit uint64) ([]reconcileCorrection, error) {
+	contracts, err := s.timelockRepo.GetAllActiveCompoundTimelocks(s.ctx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compound contracts: %w", err)
+	}
+	if len(contracts) == 0 {
+		return nil, nil
+	}
+	addresses := make([]string, len(contracts))
+	for i, contract := range contracts {
+		addresses[i] = contract.ContractAddress
+	}
+
+	localMap, err := s.flowRepo.GetCompoundFlowsMapByContracts(s.ctx, chainID, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local compound flows: %w", err)
+	}
+
+	pageSize := s.syncPageSize
+	if pageSize <= 0 {
+		pageSize = 500
+	}
+	var corrections []reconcileCorrection
+	afterID := ""
+	for {
+		flows, err := client.QueryCompoundFlowsAfterID(s.ctx, addresses, pageSize, afterID)
+		if err != nil {
+			return corrections, fmt.Errorf("failed to query compound flows (after_id=%s): %w", afterID, err)
+		}
+		if len(flows) == 0 {
+			break
+		}
+		afterID = flows[len(flows)-1].ID
+
+		for _, remote := range flows {
+			if compoundFlowLatestBlock(remote) > blockLimit {
+				continue // 尚未达到确认深度，下一轮再对账
+			}
+			dbFlow, err := ConvertGoldskyCompoundFlowToDB(remote, chainID)
+			if err != nil {
+				continue
+			}
+			local, ok := localMap[goldskyRepo.CompoundFlowKey(dbFlow.FlowID, dbFlow.ContractAddress)]
+			if !ok || local == nil {
+				continue // 本地尚未同步的 flow 由常规同步处理
+			}
+			if to := reconciledStatus(local.Status, dbFlow.Status); to != "" {
+				corrections = append(corrections, reconcileCorrection{
+					standard:        "compound",
+					contractAddress: strings.ToLower(local.ContractAddress),
+					flowID:          local.FlowID,
+					from:            local.Status,
+					to:              to,
+					txHash:          dbFlow.TxHashForStatus(to),
+					compoundFlow:    dbFlow,
+				})
+			}
+		}
+
+		if len(flows) < pageSize {
+			break
+		}
+	}
+	return corrections, nil
+}
+
+// findOpenzeppelinCorrections 按 id 游标分页拉取 subgraph 中已执行/取消的 OpenZeppelin flow，与本地状态比较
+func (s *GoldskyService) findOpenzeppelinCorrections(chainID int, client *GoldskyClient, blockLimit uint64) ([]reconcileCorrection, error) {
+	contracts, err := s.timelockRepo.GetAllActiveOpenzeppelinTimelocks(s.ctx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get openzeppelin contracts: %w", err)
+	}
+	if len(contracts) == 0 {
+		return nil, nil
+	}
+	addresses := make([]string, len(contracts))
+	for i, contract := range contracts {
+		addresses[i] = contract.ContractAddress
+	}
+
+	pageSize := s.syncPageSize
+	if pageSize <= 0 {
+		pageSize = 500
+	}
+	var corrections []reconcileCorrection
+	afterID := ""
+	for {
+		flows, err := client.QueryOpenzeppelinFlowsAfterID(s.ctx, addresses, pageSize, afterID)
+		if err != nil {
+			return corrections, fmt.Errorf("failed to query openzeppelin flows (after_id=%s): %w", afterID, err)
+		}
+		if len(flows) == 0 {
+			break
+		}
+		afterID = flows[len(flows)-1].ID
+
+		for _, remote := range flows {
+			if remote.Status != "executed" && remote.Status != "cancelled" {
+				continue
+			}
+			if openzeppelinFlowLatestBlock(remote) > blockLimit {
+				continue // 尚未达到确认深度，下一轮再对账
+			}
+			local, err := s.flowRepo.GetOpenzeppelinFlowByID(s.ctx, remote.FlowID, chainID, remote.ContractAddress)
+			if err != nil {
+				logger.Error("Failed to get local openzeppelin flow", err, "chain_id", chainID, "flow_id", remote.FlowID)
+				continue
+			}
+			if local == nil {
+				continue
+			}
+			if to := reconciledStatus(local.Status, remote.Status); to != "" {
+				dbFlow, err := ConvertGoldskyOpenzeppelinFlowToDB(remote, chainID)
+				if err != nil {
+					continue
+				}
+				corrections = append(corrections, reconcileCorrection{
+					standard:         "openzeppelin",
+					contractAddress:  strings.ToLower(local.ContractAddress),
+					flowID:           local.FlowID,
+					from:             local.Status,
+					to:               to,
+					txHash:           dbFlow.TxHashForStatus(to),
+					openzeppelinFlow: dbFlow,
+				})
+			}
+		}
+
+		if len(flows) < pageSize {
+			break
+		}
+	}
+	return corrections, nil
+}
@@ -0,0 +1,41 @@
+package goldsky
+
+import "testing"
+
+func TestReconciledStatus(t *testing.T) {
+	tests := []struct {
+		local  string
+		remote string
+		want   string
+	}{
+		// 外部终态纠正本地的非终态
+		{"waiting", "executed", "executed"},
+		{"ready", "executed", "executed"},
+		{"expired", "executed", "executed"},
+		{"waiting", "cancelled", "cancelled"},
+		{"ready", "cancelled", "cancelled"},
+		{"expired", "cancelled", "cancelled"},
+		// 本地已是终态不回退、不互相覆盖
+		{"executed", "executed", ""},
+		{"cancelled", "cancelled", ""},
+		{"executed", "cancelled", ""},
+		{"cancelled", "executed", ""},
+		// subgraph 的非终态不可信，不纠正
+		{"waiting", "waiting", ""},
+		{"waiting", "ready", ""},
+		{"ready", "waiting", ""},
+		{"executed", "waiting", ""},
+		{"ready", "expired", ""},
+		{"waiting", "", ""},
+		// 未知本地状态不处理
+		{"", "executed", ""},
+		{"unknown", "cancelled", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.local+"->"+tt.remote, func(t *testing.T) {
+			if got := reconciledStatus(tt.local, tt.remote); got != tt.want {
+				t.Fatalf("reconciledStatus(%q, %q) = %q, want %q", tt.local, tt.remote, got, tt.want)
+			}
+		})
+	}
+}
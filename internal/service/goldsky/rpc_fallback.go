@@ -0,0 +1,369 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	goldskyRepo "timelocker-backend/internal/repository/goldsky"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// RPCLogSource 直接从链上读取日志的数据源（由 scanner.RPCManager 实现）
+// 用于没有部署 subgraph 的链
+type RPCLogSource interface {
+	LatestBlockNumber(ctx context.Context, chainID int) (uint64, error)
+	FilterLogsChunked(ctx context.Context, chainID int, query ethereum.FilterQuery) ([]ethTypes.Log, error)
+	BlockTimestamp(ctx context.Context, chainID int, blockNumber uint64) (time.Time, error)
+	TransactionSender(ctx context.Context, chainID int, txHash common.Hash, blockHash common.Hash, txIndex uint) (common.Address, error)
+}
+
+const (
+	// defaultRPCFallbackLookbackBlocks 首次扫描回溯的区块数
+	defaultRPCFallbackLookbackBlocks uint64 = 50000
+	// compoundGracePeriod Compound Timelock 默认的 GRACE_PERIOD（14 天），仅在合约表缺失宽限期时作为估算值
+	compoundGracePeriod = int64(14 * 24 * 60 * 60)
+)
+
+// compoundTimelockEventsABI Compound Timelock 的三个事件，参数结构一致
+const compoundTimelockEventsABI = `[
+	{"anonymous":false,"type":"event","name":"QueueTransaction","inputs":[
+		{"indexed":true,"name":"txHash","type":"bytes32"},{"indexed":true,"name":"target","type":"address"},
+		{"indexed":false,"name":"value","type":"uint256"},{"indexed":false,"name":"signature","type":"string"},
+		{"indexed":false,"name":"data","type":"bytes"},{"indexed":false,"name":"eta","type":"uint256"}]},
+	{"anonymous":false,"type":"event","name":"ExecuteTransaction","inputs":[
+		{"indexed":true,"name":"txHash","type":"bytes32"},{"indexed":true,"name":"target","type":"address"},
+		{"indexed":false,"name":"value","type":"uint256"},{"indexed":false,"name":"signature","type":"string"},
+		{"indexed":false,"name":"data","type":"bytes"},{"indexed":false,"name":"eta","type":"uint256"}]},
+	{"anonymous":false,"type":"event","name":"CancelTransaction","inputs":[
+		{"indexed":true,"name":"txHash","type":"bytes32"},{"indexed":true,"name":"target","type":"address"},
+		{"indexed":false,"name":"value","type":"uint256"},{"indexed":false,"name":"signature","type":"string"},
+		{"indexed":false,"name":"data","type":"bytes"},{"indexed":false,"name":"eta","type":"uint256"}]}
+]`
+
+var compoundEventsABI = mustParseABI(compoundTimelockEventsABI)
+
+func mustParseABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in ABI: %v", err))
+	}
+	return parsed
+}
+
+// compoundEventData Compound 事件的非 indexed 字段
+type compoundEventData struct {
+	Value     *big.Int
+	Signature string
+	Data      []byte
+	Eta       *big.Int
+}
+
+// SetRPCLogSource 设置 RPC 日志数据源与扫描游标仓库，需在 Start 之前调用；未设置时无 subgraph 的链不会被同步
+func (s *GoldskyService) SetRPCLogSource(source RPCLogSource, cursorRepo goldskyRepo.RPCFallbackCursorRepository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rpcLogSource = source
+	s.rpcCursorRepo = cursorRepo
+}
+
+// rpcScanGroup 起始区块相同、一起扫描的一组合约
+type rpcScanGroup struct {
+	fromBlock uint64
+	backfill  bool // 尚无游标的合约：回溯 lookback 做历史回填，不发送通知
+	addresses []string
+}
+
+// syncCompoundFlowsFromRPC 对没有 subgraph 的链直接扫描 Compound 事件日志构建 flow
+// 游标按合约持久化：已有游标的合约从游标继续并对状态变化发送通知（重启后同样如此）；
+// 尚无游标的合约（首次扫描或游标建立后才导入）回溯 lookback 个区块回填历史，不发送通知
+func (s *GoldskyService) syncCompoundFlowsFromRPC(chainID int, source RPCLogSource) error {
+	start := time.Now()
+
+	s.mu.RLock()
+	cursorRepo := s.rpcCursorRepo
+	s.mu.RUnlock()
+	if cursorRepo == nil {
+		return fmt.Errorf("rpc fallback cursor repository not configured")
+	}
+
+	compoundContracts, err := s.timelockRepo.GetAllActiveCompoundTimelocks(s.ctx, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get compound contracts: %w", err)
+	}
+	if len(compoundContracts) == 0 {
+		return nil
+	}
+	contracts := make(map[string]*types.CompoundTimeLock, len(compoundContracts))
+	for i := range compoundContracts {
+		contracts[strings.ToLower(compoundContracts[i].ContractAddress)] = &compoundContracts[i]
+	}
+
+	latest, err := source.LatestBlockNumber(s.ctx, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+	// 与 webhook 一致，只扫描达到链确认深度的区块
+	depth, err := s.chainConfirmationDepth(chainID)
+	if err != nil {
+		return err
+	}
+	latest = confirmedBlock(latest, depth)
+
+	cursors, err := cursorRepo.GetRPCFallbackCursors(s.ctx, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get rpc fallback cursors: %w", err)
+	}
+	backfillFrom := uint64(0)
+	if latest > s.rpcFallbackLookback {
+		backfillFrom = latest - s.rpcFallbackLookback
+	}
+	type groupKey struct {
+		fromBlock uint64
+		backfill  bool
+	}
+	groupsByKey := make(map[groupKey]*rpcScanGroup)
+	var groups []*rpcScanGroup
+	for address := range contracts {
+		key := groupKey{fromBlock: backfillFrom, backfill: true}
+		if cursor, ok := cursors[address]; ok {
+			key = groupKey{fromBlock: cursor + 1}
+		}
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &rpcScanGroup{fromBlock: key.fromBlock, backfill: key.backfill}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.addresses = append(group.addresses, address)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].fromBlock < groups[j].fromBlock })
+
+	var totalLogs, totalApplied int
+	for _, group := range groups {
+		if s.ctx.Err() != nil {
+			break
+		}
+		logs, applied, err := s.scanCompoundLogGroup(chainID, source, cursorRepo, contracts, group, latest)
+		totalLogs += logs
+		totalApplied += applied
+		if err != nil {
+			logger.Error("Failed to sync compound logs from RPC", err, "chain_id", chainID, "from_block", group.fromBlock, "to_block", latest, "contracts", len(group.addresses), "backfill", group.backfill)
+		}
+	}
+
+	logger.Info("Synced compound flows from RPC logs",
+		"chain_id", chainID,
+		"contracts", len(contracts),
+		"groups", len(groups),
+		"to_block", latest,
+		"logs", totalLogs,
+		"applied", totalApplied,
+		"elapsed_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// scanCompoundLogGroup 扫描一组合约 [fromBlock, toBlock] 的日志并推进游标，返回日志数与写入数
+// 某条日志写入失败时停止，增量扫描的游标只推进到该日志所在区块之前，下一轮从该区块重试；
+// 回填扫描失败时不保存游标，下一轮重新回填（写入幂等，回填不发送通知）
+func (s *GoldskyService) scanCompoundLogGroup(chainID int, source RPCLogSource, cursorRepo goldskyRepo.RPCFallbackCursorRepository, contracts map[string]*types.CompoundTimeLock, group *rpcScanGroup, toBlock uint64) (int, int, error) {
+	if group.fromBlock > toBlock {
+		return 0, 0, nil
+	}
+	addresses := make([]common.Address, len(group.addresses))
+	for i, address := range group.addresses {
+		addresses[i] = common.HexToAddress(address)
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(group.fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+		Topics: [][]common.Hash{{
+			compoundEventsABI.Events["QueueTransaction"].ID,
+			compoundEventsABI.Events["ExecuteTransaction"].ID,
+			compoundEventsABI.Events["CancelTransaction"].ID,
+		}},
+	}
+	logs, err := source.FilterLogsChunked(s.ctx, chainID, query)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to filter compound logs: %w", err)
+	}
+	// 按区块与日志顺序处理，失败时游标才能安全地停在失败区块之前
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	var applied int
+	scannedTo := toBlock
+	var applyErr error
+	for _, lg := range logs {
+		if err := s.applyCompoundLog(chainID, source, contracts, lg, !group.backfill); err != nil {
+			applyErr = fmt.Errorf("failed to apply compound log (tx %s, log %d, block %d): %w", lg.TxHash.Hex(), lg.Index, lg.BlockNumber, err)
+			if lg.BlockNumber <= group.fromBlock {
+				return len(logs), applied, applyErr // 首个区块即失败，游标不前进
+			}
+			scannedTo = lg.BlockNumber - 1
+			break
+		}
+		applied++
+	}
+
+	if applyErr != nil && group.backfill {
+		return len(logs), applied, applyErr // 游标不前进，下一轮重新回填
+	}
+	if err := cursorRepo.SaveRPCFallbackCursors(s.ctx, chainID, group.addresses, scannedTo); err != nil {
+		return len(logs), applied, fmt.Errorf("failed to save rpc fallback cursors: %w", err)
+	}
+	return len(logs), applied, applyErr
+}
+
+// applyCompoundLog 将单条 Compound 事件日志写入 flow 表
+func (s *GoldskyService) applyCompoundLog(chainID int, source RPCLogSource, contracts map[string]*types.CompoundTimeLock, lg ethTypes.Log, notify bool) error {
+	if lg.Removed || len(lg.Topics) < 3 {
+		return nil
+	}
+
+	event, err := compoundEventsABI.EventByID(lg.Topics[0])
+	if err != nil {
+		return nil // 非关注事件
+	}
+
+	var data compoundEventData
+	if err := compoundEventsABI.UnpackIntoInterface(&data, event.Name, lg.Data); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", event.Name, err)
+	}
+
+	flowID := lg.Topics[1].Hex() // 与 subgraph 一致：使用事件中的 txHash 作为 flowId
+	contractAddress := strings.ToLower(lg.Address.Hex())
+	txHash := lg.TxHash.Hex()
+
+	blockTime, err := source.BlockTimestamp(s.ctx, chainID, lg.BlockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get block timestamp: %w", err)
+	}
+
+	existing, err := s.flowRepo.GetCompoundFlowByID(s.ctx, flowID, chainID, contractAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get existing flow: %w", err)
+	}
+
+	flow := existing
+	oldStatus := ""
+	if flow != nil {
+		oldStatus = flow.Status
+	} else {
+		// Queue 事件在扫描窗口之外时，Execute/Cancel 事件同样携带完整参数，可直接构建 flow
+		gracePeriod, estimated := compoundFlowGracePeriod(contracts[contractAddress])
+		flow = buildCompoundFlowFromEvent(chainID, contractAddress, flowID, common.BytesToAddress(lg.Topics[2].Bytes()), data, gracePeriod, estimated)
+	}
+
+	var initiator string
+	switch event.Name {
+	case "QueueTransaction":
+		if existing != nil {
+			return nil // 已存在，幂等跳过
+		}
+		flow.Status = "waiting"
+		flow.QueueTxHash = &txHash
+		flow.QueuedAt = &blockTime
+		if sender, err := source.TransactionSender(s.ctx, chainID, lg.TxHash, lg.BlockHash, lg.TxIndex); err == nil {
+			initiator = strings.ToLower(sender.Hex())
+			flow.InitiatorAddress = &initiator
+		} else {
+			logger.Warn("Failed to get queue tx sender", "chain_id", chainID, "tx_hash", txHash, "error", err)
+		}
+	case "ExecuteTransaction":
+		if flow.Status == "executed" {
+			return nil
+		}
+		// 回滚的交易不会产生日志，扫描到的执行事件均来自成功的交易
+		executeTxStatus := types.ExecuteTxStatusSuccess
+		flow.Status = "executed"
+		flow.ExecuteTxHash = &txHash
+		flow.ExecuteTxStatus = &executeTxStatus
+		flow.ExecutedAt = &blockTime
+	case "CancelTransaction":
+		if flow.Status == "cancelled" {
+			return nil
+		}
+		flow.Status = "cancelled"
+		flow.CancelTxHash = &txHash
+		flow.CancelledAt = &blockTime
+		if sender, err := source.TransactionSender(s.ctx, chainID, lg.TxHash, lg.BlockHash, lg.TxIndex); err == nil {
+			canceller := strings.ToLower(sender.Hex())
+			flow.CancellerAddress = &canceller
+		} else {
+			logger.Warn("Failed to get cancel tx sender", "chain_id", chainID, "tx_hash", txHash, "error", err)
+		}
+	default:
+		return nil
+	}
+	flow.UpdatedAt = time.Now()
+
+	if err := s.flowRepo.CreateOrUpdateCompoundFlow(s.ctx, flow); err != nil {
+		return fmt.Errorf("failed to save flow: %w", err)
+	}
+
+	if notify {
+		if initiator == "" && flow.InitiatorAddress != nil {
+			initiator = *flow.InitiatorAddress
+		}
+		s.enqueueFlowNotification(chainID, contractAddress, flowID, "compound", oldStatus, flow.Status, &txHash, initiator, "rpc_fallback")
+	}
+	return nil
+}
+
+// compoundFlowGracePeriod flow 使用的宽限期：合约表中记录的 GRACE_PERIOD（读取失败时为按链/默认兜底值并标记估算），
+// 合约表缺失宽限期时按 Compound 默认的 14 天估算
+func compoundFlowGracePeriod(contract *types.CompoundTimeLock) (int64, bool) {
+	if contract != nil && contract.GracePeriod > 0 {
+		return contract.GracePeriod, contract.GracePeriodEstimated
+	}
+	return compoundGracePeriod, true
+}
+
+// buildCompoundFlowFromEvent 使用事件参数构建 flow 基础数据，estimated 表示宽限期为估算值
+func buildCompoundFlowFromEvent(chainID int, contractAddress, flowID string, target common.Address, data compoundEventData, gracePeriod int64, estimated bool) *types.CompoundTimelockFlowDB {
+	targetAddress := strings.ToLower(target.Hex())
+	signature := data.Signature
+
+	flow := &types.CompoundTimelockFlowDB{
+		FlowID:            flowID,
+		TimelockStandard:  "compound",
+		ChainID:           chainID,
+		ContractAddress:   contractAddress,
+		TargetAddress:     &targetAddress,
+		FunctionSignature: &signature,
+		CallData:          data.Data,
+		Value:             "0",
+		GracePeriod:       &gracePeriod,
+		// 估算的宽限期由合约刷新读到链上值后通过 ApplyCompoundGracePeriod 修正
+		GracePeriodEstimated: estimated,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+	if data.Value != nil {
+		flow.Value = data.Value.String()
+	}
+	if data.Eta != nil && data.Eta.IsInt64() {
+		eta := time.Unix(data.Eta.Int64(), 0)
+		expiredAt := eta.Add(time.Duration(gracePeriod) * time.Second)
+		flow.Eta = &eta
+		flow.ExpiredAt = &expiredAt
+	}
+	return flow
+}
@@ -0,0 +1,164 @@
+package goldsky
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// subgraphCheckTimeout 每条链自检的超时
+const subgraphCheckTimeout = 10 * time.Second
+
+// subgraphCheckQuery 自检查询：_meta 确认可达与索引进度，两类 flow 实体确认 schema 与同步查询一致
+const subgraphCheckQuery = `
+	query {
+		_meta {
+			block { number }
+			hasIndexingErrors
+		}
+		compoundTimelockFlows(first: 1) { id flowId status }
+		openzeppelinTimelockFlows(first: 1) { id flowId status }
+	}
+`
+
+// subgraphCheckResponse 自检查询响应
+type subgraphCheckResponse struct {
+	Data *struct {
+		Meta *struct {
+			Block struct {
+				Number int64 `json:"number"`
+			} `json:"block"`
+			HasIndexingErrors bool `json:"hasIndexingErrors"`
+		} `json:"_meta"`
+		CompoundTimelockFlows     *[]json.RawMessage `json:"compoundTimelockFlows"`
+		OpenzeppelinTimelockFlows *[]json.RawMessage `json:"openzeppelinTimelockFlows"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// CheckSubgraph 对 subgraph 执行一次轻量自检查询
+// 只请求一次，不重试、不计入熔断器，避免自检本身影响正常同步
+func (c *GoldskyClient) CheckSubgraph(ctx context.Context) types.SubgraphCheckResult {
+	result := types.SubgraphCheckResult{
+		ChainID:     c.chainID,
+		SubgraphURL: c.subgraphURL,
+		CheckedAt:   time.Now(),
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{"query": subgraphCheckQuery})
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to marshal query: %v", err)
+		return result
+	}
+
+	start := time.Now()
+	body, err := c.doQuery(ctx, jsonData)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var resp subgraphCheckResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		result.Error = fmt.Sprintf("invalid GraphQL response: %v", err)
+		return result
+	}
+	result.Reachable = true
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		result.Error = "schema mismatch: " + strings.Join(messages, "; ")
+		return result
+	}
+	if resp.Data == nil || resp.Data.CompoundTimelockFlows == nil || resp.Data.OpenzeppelinTimelockFlows == nil {
+		result.Error = "schema mismatch: missing timelock flow entities"
+		return result
+	}
+	if resp.Data.Meta != nil {
+		block := resp.Data.Meta.Block.Number
+		result.LatestBlock = &block
+		result.HasIndexingErrors = resp.Data.Meta.HasIndexingErrors
+	}
+	result.SchemaCompatible = true
+	return result
+}
+
+// CheckSubgraphs 并发自检所有已配置 subgraph 的链并记录结果，不可用的链在同步时跳过
+func (s *GoldskyService) CheckSubgraphs(ctx context.Context) []types.SubgraphCheckResult {
+	s.mu.RLock()
+	clients := make(map[int]*GoldskyClient, len(s.clients))
+	for chainID, client := range s.clients {
+		clients[chainID] = client
+	}
+	s.mu.RUnlock()
+
+	results := make([]types.SubgraphCheckResult, 0, len(clients))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(c *GoldskyClient) {
+			defer wg.Done()
+			result := s.checkSubgraph(ctx, c)
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(client)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].ChainID < results[j].ChainID })
+	return results
+}
+
+// checkSubgraph 自检单条链并更新记录的状态
+func (s *GoldskyService) checkSubgraph(ctx context.Context, client *GoldskyClient) types.SubgraphCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, subgraphCheckTimeout)
+	defer cancel()
+	result := client.CheckSubgraph(checkCtx)
+
+	s.mu.Lock()
+	s.subgraphHealth[result.ChainID] = result
+	s.mu.Unlock()
+
+	if result.Healthy() {
+		var latestBlock int64
+		if result.LatestBlock != nil {
+			latestBlock = *result.LatestBlock
+		}
+		logger.Info("Subgraph self-check passed", "chain_id", result.ChainID, "latest_block", latestBlock, "has_indexing_errors", result.HasIndexingErrors, "latency_ms", result.LatencyMs)
+	} else {
+		logger.Warn("Subgraph self-check failed, flow sync will skip this chain until it recovers",
+			"chain_id", result.ChainID, "url", result.SubgraphURL, "reachable", result.Reachable, "reason", result.Error)
+	}
+	return result
+}
+
+// subgraphUsable 判断链的 subgraph 是否可用于同步；上次自检失败的链先重新自检，恢复后继续同步
+func (s *GoldskyService) subgraphUsable(chainID int, client *GoldskyClient) (bool, string) {
+	s.mu.RLock()
+	last, checked := s.subgraphHealth[chainID]
+	s.mu.RUnlock()
+	if !checked || last.Healthy() {
+		return true, ""
+	}
+
+	result := s.checkSubgraph(s.ctx, client)
+	if result.Healthy() {
+		return true, ""
+	}
+	return false, result.Error
+}
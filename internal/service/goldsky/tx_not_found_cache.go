@@ -0,0 +1,67 @@
+package goldsky
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTransactionNotFound subgraph 中查不到该交易（不存在或尚未索引）
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// defaultTxNotFoundCacheTTL 交易不存在结果的默认缓存时长
+const defaultTxNotFoundCacheTTL = 30 * time.Second
+
+// txNotFoundCache 交易哈希查询的短期负缓存：查不到的交易在 TTL 内不再请求 subgraph，过期后重新查询，以便新索引的交易能被查到
+type txNotFoundCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]time.Time // key -> 过期时间
+}
+
+// newTxNotFoundCache 创建负缓存，ttl <= 0 表示关闭
+func newTxNotFoundCache(ttl time.Duration) *txNotFoundCache {
+	return &txNotFoundCache{
+		ttl:     ttl,
+		entries: make(map[string]time.Time),
+	}
+}
+
+func txNotFoundCacheKey(chainID int, standard, txHash string) string {
+	return fmt.Sprintf("%d:%s:%s", chainID, standard, strings.ToLower(txHash))
+}
+
+// Hit 判断该交易是否在 TTL 内被确认为不存在，过期条目顺带删除
+func (c *txNotFoundCache) Hit(key string, now time.Time) bool {
+	if c == nil || c.ttl <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expireAt, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if !now.Before(expireAt) {
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+// Add 记录交易不存在；写入时清理已过期条目，避免缓存无限增长
+func (c *txNotFoundCache) Add(key string, now time.Time) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, expireAt := range c.entries {
+		if !now.Before(expireAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
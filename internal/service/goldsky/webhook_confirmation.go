@@ -0,0 +1,363 @@
+package goldsky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// defaultConfirmationMaxWait 事件默认最长暂存时间
+	defaultConfirmationMaxWait = 30 * time.Minute
+	// defaultConfirmationMaxPending 默认暂存事件数上限
+	defaultConfirmationMaxPending = 10000
+	// confirmationLookupTimeout 单次查询最新区块的超时
+	confirmationLookupTimeout = 5 * time.Second
+)
+
+// ErrConfirmationBufferFull 暂存事件数达到上限
+var ErrConfirmationBufferFull = errors.New("webhook confirmation buffer is full")
+
+// ConfirmationBlockSource 查询链最新区块高度与交易回执，用于确认数与规范链检查（由 scanner.RPCManager 实现）
+type ConfirmationBlockSource interface {
+	LatestBlockNumber(ctx context.Context, chainID int) (uint64, error)
+	// TransactionReceipt 交易不在当前规范链上时返回 nil
+	TransactionReceipt(ctx context.Context, chainID int, txHash common.Hash) (*ethTypes.Receipt, error)
+	CanonicalBlockHash(ctx context.Context, chainID int, blockNumber uint64) (common.Hash, error)
+}
+
+// pendingWebhookEvent 等待确认的 webhook 事件
+type pendingWebhookEvent struct {
+	eventKey    string
+	chainID     int
+	eventType   string
+	txHash      string
+	logIndex    string
+	blockNumber uint64
+	depth       int
+	receivedAt  time.Time
+	process     func(ctx context.Context) error
+}
+
+// SetConfirmationSource 设置确认数检查使用的区块高度数据源与暂存上限，需在接收 webhook 之前调用
+// 未设置时配置了 confirmation_depth 的链也收到即处理
+func (p *WebhookProcessor) SetConfirmationSource(source ConfirmationBlockSource, maxWait time.Duration, maxPending int) {
+	if maxWait <= 0 {
+		maxWait = defaultConfirmationMaxWait
+	}
+	if maxPending <= 0 {
+		maxPending = defaultConfirmationMaxPending
+	}
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	p.blockSource = source
+	p.confirmationMaxWait = maxWait
+	p.confirmationMaxPending = maxPending
+}
+
+// DeferUntilConfirmed 事件所在区块之上不足 depth 个区块时暂存事件，返回 true 表示已暂存，
+// 确认后由 ProcessPendingConfirmations 调用 process 并记录处理结果；返回 false 时调用方应立即处理
+// 暂存事件数达到上限时返回 ErrConfirmationBufferFull
+func (p *WebhookProcessor) DeferUntilConfirmed(ctx context.Context, eventKey string, chainID, depth int, txData *types.GraphQLTransactionData, process func(ctx context.Context) error) (bool, error) {
+	if depth <= 0 {
+		return false, nil
+	}
+
+	p.pendingMu.Lock()
+	source := p.blockSource
+	p.pendingMu.Unlock()
+	if source == nil {
+		return false, nil
+	}
+
+	blockNumber, err := strconv.ParseUint(strings.TrimSpace(txData.BlockNumber), 10, 64)
+	if err != nil {
+		logger.Warn("Invalid webhook block number, processing without confirmation check", "event_key", eventKey, "block_number", txData.BlockNumber)
+		return false, nil
+	}
+
+	// 收到时已满足确认数则直接处理；查询失败时暂存，由后台任务重试
+	lookupCtx, cancel := context.WithTimeout(ctx, confirmationLookupTimeout)
+	latest, err := source.LatestBlockNumber(lookupCtx, chainID)
+	cancel()
+	if err == nil && isConfirmed(blockNumber, latest, depth) {
+		return false, nil
+	}
+	if err != nil {
+		logger.Warn("Failed to get latest block for confirmation check, deferring event", "chain_id", chainID, "event_key", eventKey, "error", err)
+	}
+
+	p.pendingMu.Lock()
+	pendingCount, maxPending := len(p.pendingEvents), p.confirmationMaxPending
+	p.pendingMu.Unlock()
+	if pendingCount >= maxPending {
+		return false, fmt.Errorf("%w (%d events)", ErrConfirmationBufferFull, pendingCount)
+	}
+
+	// 先标记为 deferred 再入队，避免后台任务处理完成后被覆盖；暂存期间 Goldsky 的重复投递不会再次登记与处理
+	p.markEventDeferred(ctx, eventKey)
+
+	p.pendingMu.Lock()
+	p.pendingEvents = append(p.pendingEvents, &pendingWebhookEvent{
+		eventKey:    eventKey,
+		chainID:     chainID,
+		eventType:   txData.EventType,
+		txHash:      txData.TxHash,
+		logIndex:    txData.LogIndex,
+		blockNumber: blockNumber,
+		depth:       depth,
+		receivedAt:  time.Now(),
+		process:     process,
+	})
+	p.pendingMu.Unlock()
+	logger.Info("Webhook event deferred until confirmed",
+		"event_key", eventKey,
+		"chain_id", chainID,
+		"event_type", txData.EventType,
+		"block_number", blockNumber,
+		"latest_block", latest,
+		"confirmation_depth", depth)
+	return true, nil
+}
+
+// ProcessPendingConfirmations 处理已达到确认数的暂存事件（按接收顺序），超过最长暂存时间的事件放弃
+// 每条链只查询一次最新区块；查询失败的链本轮跳过
+func (p *WebhookProcessor) ProcessPendingConfirmations(ctx context.Context) {
+	p.pendingMu.Lock()
+	pending := p.pendingEvents
+	p.pendingEvents = nil
+	source := p.blockSource
+	maxWait := p.confirmationMaxWait
+	p.pendingMu.Unlock()
+	if len(pending) == 0 || source == nil {
+		p.requeuePending(pending)
+		return
+	}
+
+	latestByChain := make(map[int]uint64)
+	failedChains := make(map[int]bool)
+	remaining := make([]*pendingWebhookEvent, 0, len(pending))
+	for i, event := range pending {
+		if ctx.Err() != nil {
+			remaining = append(remaining, pending[i:]...)
+			break
+		}
+
+		if time.Since(event.receivedAt) > maxWait {
+			logger.Warn("Webhook event not confirmed within max wait, giving up (periodic sync will catch up)",
+				"event_key", event.eventKey,
+				"chain_id", event.chainID,
+				"event_type", event.eventType,
+				"tx_hash", event.txHash,
+				"block_number", event.blockNumber)
+			p.FinishEvent(ctx, event.eventKey, fmt.Errorf("not confirmed within %s", maxWait))
+			continue
+		}
+
+		if failedChains[event.chainID] {
+			remaining = append(remaining, event)
+			continue
+		}
+		latest, ok := latestByChain[event.chainID]
+		if !ok {
+			lookupCtx, cancel := context.WithTimeout(ctx, confirmationLookupTimeout)
+			var err error
+			latest, err = source.LatestBlockNumber(lookupCtx, event.chainID)
+			cancel()
+			if err != nil {
+				logger.Warn("Failed to get latest block for pending webhook events", "chain_id", event.chainID, "error", err)
+				failedChains[event.chainID] = true
+				remaining = append(remaining, event)
+				continue
+			}
+			latestByChain[event.chainID] = latest
+		}
+
+		if !isConfirmed(event.blockNumber, latest, event.depth) {
+			remaining = append(remaining, event)
+			continue
+		}
+
+		// 达到确认数后确认事件仍在规范链上，被重组移出的事件不再处理
+		canonical, err := p.isEventCanonical(ctx, source, event)
+		if err != nil {
+			logger.Warn("Failed to verify pending webhook event is canonical, retrying later", "event_key", event.eventKey, "chain_id", event.chainID, "tx_hash", event.txHash, "error", err)
+			remaining = append(remaining, event)
+			continue
+		}
+		if !canonical {
+			logger.Warn("Webhook event no longer on canonical chain, dropping",
+				"event_key", event.eventKey,
+				"chain_id", event.chainID,
+				"event_type", event.eventType,
+				"tx_hash", event.txHash,
+				"block_number", event.blockNumber)
+			p.FinishEvent(ctx, event.eventKey, fmt.Errorf("event removed by chain reorg (tx %s, block %d)", event.txHash, event.blockNumber))
+			continue
+		}
+
+		processErr := event.process(ctx)
+		p.FinishEvent(ctx, event.eventKey, processErr)
+		if processErr != nil {
+			logger.Error("Failed to process confirmed webhook event", processErr,
+				"event_key", event.eventKey,
+				"chain_id", event.chainID,
+				"event_type", event.eventType,
+				"tx_hash", event.txHash)
+			continue
+		}
+		logger.Info("Processed confirmed webhook event",
+			"event_key", event.eventKey,
+			"chain_id", event.chainID,
+			"event_type", event.eventType,
+			"block_number", event.blockNumber,
+			"latest_block", latest)
+	}
+
+	p.requeuePending(remaining)
+}
+
+// PendingConfirmationCount 当前暂存等待确认的事件数
+func (p *WebhookProcessor) PendingConfirmationCount() int {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	return len(p.pendingEvents)
+}
+
+// requeuePending 将未处理的事件放回队首，保持接收顺序
+func (p *WebhookProcessor) requeuePending(events []*pendingWebhookEvent) {
+	if len(events) == 0 {
+		return
+	}
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	p.pendingEvents = append(events, p.pendingEvents...)
+}
+
+// isEventCanonical 确认事件仍在规范链上：交易回执存在且位于 webhook 报告的区块，
+// 该高度的规范区块哈希与回执一致，且回执中包含该事件的日志
+func (p *WebhookProcessor) isEventCanonical(ctx context.Context, source ConfirmationBlockSource, event *pendingWebhookEvent) (bool, error) {
+	if !utils.IsValidTxHash(event.txHash) {
+		return false, fmt.Errorf("invalid tx hash %q", event.txHash)
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, confirmationLookupTimeout)
+	defer cancel()
+
+	receipt, err := source.TransactionReceipt(lookupCtx, event.chainID, common.HexToHash(event.txHash))
+	if err != nil {
+		return false, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	if receipt == nil || receipt.BlockNumber == nil || receipt.BlockNumber.Uint64() != event.blockNumber {
+		return false, nil
+	}
+	canonicalHash, err := source.CanonicalBlockHash(lookupCtx, event.chainID, event.blockNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get canonical block hash: %w", err)
+	}
+	if canonicalHash != receipt.BlockHash {
+		return false, nil
+	}
+	if logIndex, err := strconv.ParseUint(strings.TrimSpace(event.logIndex), 10, 64); err == nil {
+		for _, log := range receipt.Logs {
+			if uint64(log.Index) == logIndex {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// isConfirmed 事件所在区块之上至少有 depth 个区块
+func isConfirmed(blockNumber, latest uint64, depth int) bool {
+	return latest >= blockNumber && latest-blockNumber >= uint64(depth)
+}
+
+// chainConfirmationDepth 链配置的 webhook 事件确认深度，未配置时为 0
+func (s *GoldskyService) chainConfirmationDepth(chainID int) (uint64, error) {
+	chainInfo, err := s.chainRepo.GetChainByChainID(s.ctx, int64(chainID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain: %w", err)
+	}
+	if chainInfo == nil || chainInfo.ConfirmationDepth <= 0 {
+		return 0, nil
+	}
+	return uint64(chainInfo.ConfirmationDepth), nil
+}
+
+// confirmedBlockLimit 定时同步只写入已达到链确认深度的事件，返回允许写入的最高区块
+// 链未配置 confirmation_depth 时返回 math.MaxUint64；配置了但无法获取最新区块时返回错误，调用方应跳过本轮
+func (s *GoldskyService) confirmedBlockLimit(chainID int) (uint64, error) {
+	depth, err := s.chainConfirmationDepth(chainID)
+	if err != nil {
+		return 0, err
+	}
+	if depth == 0 {
+		return math.MaxUint64, nil
+	}
+
+	s.mu.RLock()
+	source := s.rpcLogSource
+	s.mu.RUnlock()
+	if source == nil {
+		return 0, fmt.Errorf("no block source to check confirmation depth %d", depth)
+	}
+	lookupCtx, cancel := context.WithTimeout(s.ctx, confirmationLookupTimeout)
+	latest, err := source.LatestBlockNumber(lookupCtx, chainID)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	return confirmedBlock(latest, depth), nil
+}
+
+// confirmedBlock 最新区块为 latest 时达到 depth 个确认的最高区块
+func confirmedBlock(latest, depth uint64) uint64 {
+	if latest < depth {
+		return 0
+	}
+	return latest - depth
+}
+
+// compoundFlowLatestBlock flow 中最新一笔交易所在区块，无法解析时返回 0
+func compoundFlowLatestBlock(flow types.GoldskyCompoundFlow) uint64 {
+	var blocks []string
+	for _, tx := range []*types.GoldskyCompoundTransaction{flow.QueueTransaction, flow.ExecuteTransaction, flow.CancelTransaction} {
+		if tx != nil {
+			blocks = append(blocks, tx.BlockNumber)
+		}
+	}
+	return maxBlockNumber(blocks)
+}
+
+// openzeppelinFlowLatestBlock flow 中最新一笔交易所在区块，无法解析时返回 0
+func openzeppelinFlowLatestBlock(flow types.GoldskyOpenzeppelinFlow) uint64 {
+	var blocks []string
+	for _, tx := range []*types.GoldskyOpenzeppelinTransaction{flow.ScheduleTransaction, flow.ExecuteTransaction, flow.CancelTransaction} {
+		if tx != nil {
+			blocks = append(blocks, tx.BlockNumber)
+		}
+	}
+	return maxBlockNumber(blocks)
+}
+
+func maxBlockNumber(blocks []string) uint64 {
+	var latest uint64
+	for _, block := range blocks {
+		if blockNumber, err := strconv.ParseUint(strings.TrimSpace(block), 10, 64); err == nil && blockNumber > latest {
+			latest = blockNumber
+		}
+	}
+	return latest
+}
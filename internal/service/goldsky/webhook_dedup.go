@@ -0,0 +1,86 @@
+package goldsky
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// webhookEventStaleAfter 事件处于 processing 超过该时长视为上次处理中断（进程崩溃等），允许重新处理
+const webhookEventStaleAfter = 10 * time.Minute
+
+// WebhookEventKey 生成事件去重键：chain_id:standard:实体ID；实体ID缺失时退化为 tx_hash-log_index，都缺失时返回空（不去重）
+func WebhookEventKey(chainID int, standard string, txData *types.GraphQLTransactionData) string {
+	id := txData.ID
+	if id == "" && txData.TxHash != "" {
+		id = txData.TxHash + "-" + txData.LogIndex
+	}
+	if id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d:%s:%s", chainID, standard, id)
+}
+
+// ClaimEvent 登记 webhook 事件并判断是否需要处理；返回 false 表示重复投递，应直接确认而不再处理
+// 去重表不可用时放行处理，避免丢事件（状态更新本身按 flow 幂等，重复处理最多多发通知）
+func (p *WebhookProcessor) ClaimEvent(ctx context.Context, eventKey string, chainID int, standard string, txData *types.GraphQLTransactionData) bool {
+	if eventKey == "" || p.eventRepo == nil {
+		return true
+	}
+	now := time.Now()
+	claimed, err := p.eventRepo.ClaimWebhookEvent(ctx, &types.GoldskyWebhookEvent{
+		EventKey:  eventKey,
+		ChainID:   chainID,
+		Standard:  standard,
+		EventType: txData.EventType,
+		TxHash:    txData.TxHash,
+	}, now.Add(-webhookEventStaleAfter), now.Add(-p.deferredEventStaleAfter()))
+	if err != nil {
+		logger.Error("Failed to claim webhook event, processing without dedup", err, "event_key", eventKey)
+		return true
+	}
+	if !claimed {
+		logger.Info("Duplicate webhook event, skipping", "event_key", eventKey, "event_type", txData.EventType, "tx_hash", txData.TxHash)
+	}
+	return claimed
+}
+
+// deferredEventStaleAfter 暂存事件超过最长暂存时间仍未处理完才允许重投递重新登记，
+// 暂存期间的重复投递不会被再次暂存与处理
+func (p *WebhookProcessor) deferredEventStaleAfter() time.Duration {
+	p.pendingMu.Lock()
+	maxWait := p.confirmationMaxWait
+	p.pendingMu.Unlock()
+	if maxWait <= 0 {
+		maxWait = defaultConfirmationMaxWait
+	}
+	return maxWait + webhookEventStaleAfter
+}
+
+// markEventDeferred 记录事件已暂存等待确认，失败只记录日志
+func (p *WebhookProcessor) markEventDeferred(ctx context.Context, eventKey string) {
+	if eventKey == "" || p.eventRepo == nil {
+		return
+	}
+	if err := p.eventRepo.MarkWebhookEventDeferred(ctx, eventKey); err != nil {
+		logger.Error("Failed to mark webhook event deferred", err, "event_key", eventKey)
+	}
+}
+
+// FinishEvent 记录事件处理结果；处理失败的事件保留错误信息，Goldsky 重试时可重新处理
+func (p *WebhookProcessor) FinishEvent(ctx context.Context, eventKey string, processErr error) {
+	if eventKey == "" || p.eventRepo == nil {
+		return
+	}
+	var errMsg *string
+	if processErr != nil {
+		msg := processErr.Error()
+		errMsg = &msg
+	}
+	if err := p.eventRepo.FinishWebhookEvent(ctx, eventKey, errMsg); err != nil {
+		logger.Error("Failed to record webhook event result", err, "event_key", eventKey)
+	}
+}
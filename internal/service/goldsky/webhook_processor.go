@@ -3,9 +3,11 @@ package goldsky
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	goldskyRepo "timelocker-backend/internal/repository/goldsky"
@@ -20,15 +22,24 @@ import (
 type WebhookProcessor struct {
 	timelockRepo    timelockRepo.Repository
 	flowRepo        goldskyRepo.FlowRepository
+	eventRepo       goldskyRepo.WebhookEventRepository
 	goldskySvc      *GoldskyService
 	emailSvc        email.EmailService
 	notificationSvc notification.NotificationService
+
+	// 确认深度：区块确认数不足的事件暂存在 pendingEvents，由后台任务确认后处理
+	pendingMu              sync.Mutex
+	pendingEvents          []*pendingWebhookEvent
+	blockSource            ConfirmationBlockSource
+	confirmationMaxWait    time.Duration
+	confirmationMaxPending int
 }
 
 // NewWebhookProcessor 创建 Webhook 处理器
 func NewWebhookProcessor(
 	timelockRepo timelockRepo.Repository,
 	flowRepo goldskyRepo.FlowRepository,
+	eventRepo goldskyRepo.WebhookEventRepository,
 	goldskySvc *GoldskyService,
 	emailSvc email.EmailService,
 	notificationSvc notification.NotificationService,
@@ -36,6 +47,7 @@ func NewWebhookProcessor(
 	return &WebhookProcessor{
 		timelockRepo:    timelockRepo,
 		flowRepo:        flowRepo,
+		eventRepo:       eventRepo,
 		goldskySvc:      goldskySvc,
 		emailSvc:        emailSvc,
 		notificationSvc: notificationSvc,
@@ -133,6 +145,11 @@ func (p *WebhookProcessor) handleCompoundQueue(ctx context.Context, tx types.Gol
 	if goldskyFlow != nil {
 		// 使用 Goldsky 的完整数据创建 Flow
 		flow, err = ConvertGoldskyCompoundFlowToDB(*goldskyFlow, chainID)
+		if errors.Is(err, ErrCompoundFlowIDMismatch) {
+			// flow_id 与参数不符时 webhook 数据同样不可信，跳过该 flow（重试不会改变结果）
+			logger.Error("Skipping compound flow with mismatched flow_id", err, "chain_id", chainID, "flow_id", flowID)
+			return nil
+		}
 		if err != nil {
 			logger.Error("Failed to convert Goldsky flow data", err, "flow_id", flowID)
 			// 继续使用 webhook 数据作为备用方案
@@ -191,7 +208,7 @@ func (p *WebhookProcessor) handleCompoundQueue(ctx context.Context, tx types.Gol
 	logger.Info("Created new Compound flow", "flow_id", flowID, "status", "waiting", "used_goldsky_data", goldskyFlow != nil)
 
 	// 异步发送通知
-	go p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "compound", "", "waiting", &tx.TxHash, tx.FromAddress)
+	p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "compound", "", "waiting", &tx.TxHash, tx.FromAddress)
 
 	return nil
 }
@@ -213,9 +230,13 @@ func (p *WebhookProcessor) handleCompoundExecute(ctx context.Context, tx types.G
 
 	oldStatus := existingFlow.Status
 
+	// 回滚的交易不会产生 ExecuteTransaction 日志，收到执行事件即说明交易成功
+	executeTxStatus := types.ExecuteTxStatusSuccess
+
 	// 更新 Flow 状态
 	existingFlow.Status = "executed"
 	existingFlow.ExecuteTxHash = &tx.TxHash
+	existingFlow.ExecuteTxStatus = &executeTxStatus
 
 	if blockTs, err := strconv.ParseInt(tx.BlockTimestamp, 10, 64); err == nil {
 		executedAt := time.Unix(blockTs, 0)
@@ -230,7 +251,7 @@ func (p *WebhookProcessor) handleCompoundExecute(ctx context.Context, tx types.G
 	logger.Info("Updated Compound flow to executed", "flow_id", flowID, "old_status", oldStatus)
 
 	// 异步发送通知
-	go p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "compound", oldStatus, "executed", &tx.TxHash, tx.FromAddress)
+	p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "compound", oldStatus, "executed", &tx.TxHash, tx.FromAddress)
 
 	return nil
 }
@@ -253,6 +274,9 @@ func (p *WebhookProcessor) handleCompoundCancel(ctx context.Context, tx types.Go
 
 	existingFlow.Status = "cancelled"
 	existingFlow.CancelTxHash = &tx.TxHash
+	if tx.FromAddress != "" {
+		existingFlow.CancellerAddress = &tx.FromAddress
+	}
 
 	if blockTs, err := strconv.ParseInt(tx.BlockTimestamp, 10, 64); err == nil {
 		cancelledAt := time.Unix(blockTs, 0)
@@ -267,7 +291,7 @@ func (p *WebhookProcessor) handleCompoundCancel(ctx context.Context, tx types.Go
 	logger.Info("Updated Compound flow to cancelled", "flow_id", flowID, "old_status", oldStatus)
 
 	// 异步发送通知
-	go p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "compound", oldStatus, "cancelled", &tx.TxHash, tx.FromAddress)
+	p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "compound", oldStatus, "cancelled", &tx.TxHash, tx.FromAddress)
 
 	return nil
 }
@@ -285,6 +309,9 @@ func (p *WebhookProcessor) handleOpenzeppelinSchedule(ctx context.Context, tx ty
 		return fmt.Errorf("failed to check existing flow: %w", err)
 	}
 
+	// scheduleBatch 的每个子调用都会单独推送一条 CallScheduled，均记录到子调用表
+	p.recordOpenzeppelinScheduledCall(ctx, tx, chainID)
+
 	if existingFlow != nil {
 		logger.Info("Flow already exists, skipping creation", "flow_id", flowID)
 		return nil
@@ -313,6 +340,7 @@ func (p *WebhookProcessor) handleOpenzeppelinSchedule(ctx context.Context, tx ty
 			flow.CallData = callDataBytes
 		}
 	}
+	flow.Predecessor = types.NormalizeOpenzeppelinPredecessor(tx.EventPredecessor)
 	if tx.EventDelay != nil {
 		if delay, err := strconv.ParseInt(*tx.EventDelay, 10, 64); err == nil {
 			flow.Delay = &delay
@@ -336,12 +364,39 @@ func (p *WebhookProcessor) handleOpenzeppelinSchedule(ctx context.Context, tx ty
 
 	logger.Info("Created new OpenZeppelin flow", "flow_id", flowID, "status", "waiting")
 
+	// 其余子调用的推送可能尚未到达，先从 Goldsky 补齐整个批量操作，保证通知中展示完整
+	if calls, err := p.goldskySvc.GetOpenzeppelinScheduledCalls(ctx, chainID, tx.ContractAddress, flowID); err != nil {
+		logger.Warn("Failed to backfill openzeppelin scheduled calls", "flow_id", flowID, "chain_id", chainID, "error", err)
+	} else if err := p.flowRepo.UpsertOpenzeppelinFlowCalls(ctx, calls); err != nil {
+		logger.Error("Failed to save openzeppelin scheduled calls", err, "flow_id", flowID, "chain_id", chainID)
+	}
+
 	// 异步发送通知
-	go p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "openzeppelin", "", "waiting", &tx.TxHash, tx.FromAddress)
+	p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "openzeppelin", "", "waiting", &tx.TxHash, tx.FromAddress)
 
 	return nil
 }
 
+// recordOpenzeppelinScheduledCall 记录单个 CallScheduled 事件对应的子调用
+func (p *WebhookProcessor) recordOpenzeppelinScheduledCall(ctx context.Context, tx types.GoldskyOpenzeppelinTransactionWebhook, chainID int) {
+	scheduled := types.GoldskyOpenzeppelinTransaction{
+		TxHash:          tx.TxHash,
+		LogIndex:        tx.LogIndex,
+		ContractAddress: tx.ContractAddress,
+		EventType:       tx.EventType,
+		EventId:         tx.EventId,
+		EventIndex:      tx.EventIndex,
+		EventTarget:     tx.EventTarget,
+		EventValue:      tx.EventValue,
+		EventData:       tx.EventData,
+	}
+	for _, calls := range AggregateOpenzeppelinScheduledCalls([]types.GoldskyOpenzeppelinTransaction{scheduled}, chainID) {
+		if err := p.flowRepo.UpsertOpenzeppelinFlowCalls(ctx, calls); err != nil {
+			logger.Error("Failed to save openzeppelin scheduled call", err, "flow_id", *tx.EventId, "chain_id", chainID, "tx_hash", tx.TxHash)
+		}
+	}
+}
+
 // handleOpenzeppelinExecute 处理 OpenZeppelin Execute 事件
 func (p *WebhookProcessor) handleOpenzeppelinExecute(ctx context.Context, tx types.GoldskyOpenzeppelinTransactionWebhook, chainID int) error {
 	if tx.EventId == nil {
@@ -358,8 +413,12 @@ func (p *WebhookProcessor) handleOpenzeppelinExecute(ctx context.Context, tx typ
 
 	oldStatus := existingFlow.Status
 
+	// 回滚的交易不会产生 CallExecuted 日志，收到执行事件即说明交易成功
+	executeTxStatus := types.ExecuteTxStatusSuccess
+
 	existingFlow.Status = "executed"
 	existingFlow.ExecuteTxHash = &tx.TxHash
+	existingFlow.ExecuteTxStatus = &executeTxStatus
 
 	if blockTs, err := strconv.ParseInt(tx.BlockTimestamp, 10, 64); err == nil {
 		executedAt := time.Unix(blockTs, 0)
@@ -374,7 +433,7 @@ func (p *WebhookProcessor) handleOpenzeppelinExecute(ctx context.Context, tx typ
 	logger.Info("Updated OpenZeppelin flow to executed", "flow_id", flowID, "old_status", oldStatus)
 
 	// 异步发送通知
-	go p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "openzeppelin", oldStatus, "executed", &tx.TxHash, tx.FromAddress)
+	p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "openzeppelin", oldStatus, "executed", &tx.TxHash, tx.FromAddress)
 
 	return nil
 }
@@ -397,6 +456,9 @@ func (p *WebhookProcessor) handleOpenzeppelinCancel(ctx context.Context, tx type
 
 	existingFlow.Status = "cancelled"
 	existingFlow.CancelTxHash = &tx.TxHash
+	if tx.FromAddress != "" {
+		existingFlow.CancellerAddress = &tx.FromAddress
+	}
 
 	if blockTs, err := strconv.ParseInt(tx.BlockTimestamp, 10, 64); err == nil {
 		cancelledAt := time.Unix(blockTs, 0)
@@ -411,7 +473,7 @@ func (p *WebhookProcessor) handleOpenzeppelinCancel(ctx context.Context, tx type
 	logger.Info("Updated OpenZeppelin flow to cancelled", "flow_id", flowID, "old_status", oldStatus)
 
 	// 异步发送通知
-	go p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "openzeppelin", oldStatus, "cancelled", &tx.TxHash, tx.FromAddress)
+	p.sendFlowNotification(chainID, tx.ContractAddress, flowID, "openzeppelin", oldStatus, "cancelled", &tx.TxHash, tx.FromAddress)
 
 	return nil
 }
@@ -451,13 +513,15 @@ func (p *WebhookProcessor) sendFlowNotification(chainID int, contractAddress, fl
 		p.goldskySvc.Dispatcher().Enqueue(job)
 		return
 	}
-	// fallback：dispatcher 不可用时直接执行，避免丢通知
-	ctx := context.Background()
-	logger.Warn("NotificationDispatcher unavailable, sending synchronously", "flow_id", flowID)
-	if err := p.emailSvc.SendFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress); err != nil {
-		logger.Error("Failed to send email notification", err, "flow_id", flowID)
-	}
-	if err := p.notificationSvc.SendFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress); err != nil {
-		logger.Error("Failed to send channel notification", err, "flow_id", flowID)
-	}
+	// fallback：dispatcher 不可用时异步直接执行，避免丢通知
+	logger.Warn("NotificationDispatcher unavailable, sending directly", "flow_id", flowID)
+	go func() {
+		ctx := context.Background()
+		if err := p.emailSvc.SendFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress); err != nil {
+			logger.Error("Failed to send email notification", err, "flow_id", flowID)
+		}
+		if err := p.notificationSvc.SendFlowNotification(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress); err != nil {
+			logger.Error("Failed to send channel notification", err, "flow_id", flowID)
+		}
+	}()
 }
@@ -0,0 +1,108 @@
+package goldsky
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goldskyRepo "timelocker-backend/internal/repository/goldsky"
+	"timelocker-backend/internal/types"
+)
+
+// fakeExecuteFlowRepo 只实现执行事件用到的读取与保存方法，保存的 flow 记录在 saved* 字段
+type fakeExecuteFlowRepo struct {
+	goldskyRepo.FlowRepository
+	compound          *types.CompoundTimelockFlowDB
+	openzeppelin      *types.OpenzeppelinTimelockFlowDB
+	savedCompound     *types.CompoundTimelockFlowDB
+	savedOpenzeppelin *types.OpenzeppelinTimelockFlowDB
+}
+
+func (r *fakeExecuteFlowRepo) GetCompoundFlowByID(ctx context.Context, flowID string, chainID int, contractAddress string) (*types.CompoundTimelockFlowDB, error) {
+	return r.compound, nil
+}
+
+func (r *fakeExecuteFlowRepo) CreateOrUpdateCompoundFlow(ctx context.Context, flow *types.CompoundTimelockFlowDB) error {
+	r.savedCompound = flow
+	return nil
+}
+
+func (r *fakeExecuteFlowRepo) GetOpenzeppelinFlowByID(ctx context.Context, flowID string, chainID int, contractAddress string) (*types.OpenzeppelinTimelockFlowDB, error) {
+	return r.openzeppelin, nil
+}
+
+func (r *fakeExecuteFlowRepo) CreateOrUpdateOpenzeppelinFlow(ctx context.Context, flow *types.OpenzeppelinTimelockFlowDB) error {
+	r.savedOpenzeppelin = flow
+	return nil
+}
+
+// newExecuteTestProcessor 未设置 goldskySvc，通知由 sendFlowNotification 直接异步发送到 sender
+func newExecuteTestProcessor(repo *fakeExecuteFlowRepo, sender *fakeFlowSender) *WebhookProcessor {
+	return &WebhookProcessor{
+		flowRepo:        repo,
+		emailSvc:        fakeEmailService{fakeFlowSender: sender},
+		notificationSvc: fakeNotificationService{fakeFlowSender: sender},
+	}
+}
+
+// waitForCalls 等待邮件与渠道通知都已发送
+func waitForCalls(t *testing.T, sender *fakeFlowSender, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(sender.Calls()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("notifications = %v, want two %q", sender.Calls(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for _, call := range sender.Calls() {
+		if call != want {
+			t.Fatalf("notification = %q, want %q", call, want)
+		}
+	}
+}
+
+// TestHandleExecuteMarksSuccessWithoutReceiptLookup 执行事件只来自成功的交易，直接标记 executed 与 execute_tx_status=success
+func TestHandleExecuteMarksSuccessWithoutReceiptLookup(t *testing.T) {
+	const (
+		flowID = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		txHash = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	eventID := flowID
+
+	t.Run("compound", func(t *testing.T) {
+		repo := &fakeExecuteFlowRepo{compound: &types.CompoundTimelockFlowDB{FlowID: flowID, Status: "ready"}}
+		sender := &fakeFlowSender{}
+		p := newExecuteTestProcessor(repo, sender)
+		tx := types.GoldskyCompoundTransactionWebhook{TxHash: txHash, BlockTimestamp: "1700000000", EventTxHash: &eventID}
+		if err := p.handleCompoundExecute(context.Background(), tx, 1); err != nil {
+			t.Fatalf("handleCompoundExecute: %v", err)
+		}
+		flow := repo.savedCompound
+		if flow == nil || flow.Status != "executed" || flow.ExecuteTxHash == nil || *flow.ExecuteTxHash != txHash {
+			t.Fatalf("saved flow = %+v, want executed with execute tx hash", flow)
+		}
+		if flow.ExecuteTxStatus == nil || *flow.ExecuteTxStatus != types.ExecuteTxStatusSuccess {
+			t.Fatalf("execute_tx_status = %v, want success", flow.ExecuteTxStatus)
+		}
+		if flow.ExecutedAt == nil || flow.ExecutedAt.Unix() != 1700000000 {
+			t.Fatalf("executed_at = %v, want block timestamp", flow.ExecutedAt)
+		}
+		waitForCalls(t, sender, flowID+":executed")
+	})
+
+	t.Run("openzeppelin", func(t *testing.T) {
+		repo := &fakeExecuteFlowRepo{openzeppelin: &types.OpenzeppelinTimelockFlowDB{FlowID: flowID, Status: "ready"}}
+		sender := &fakeFlowSender{}
+		p := newExecuteTestProcessor(repo, sender)
+		tx := types.GoldskyOpenzeppelinTransactionWebhook{TxHash: txHash, BlockTimestamp: "1700000000", EventId: &eventID}
+		if err := p.handleOpenzeppelinExecute(context.Background(), tx, 1); err != nil {
+			t.Fatalf("handleOpenzeppelinExecute: %v", err)
+		}
+		flow := repo.savedOpenzeppelin
+		if flow == nil || flow.Status != "executed" || flow.ExecuteTxStatus == nil || *flow.ExecuteTxStatus != types.ExecuteTxStatusSuccess {
+			t.Fatalf("saved flow = %+v, want executed with execute_tx_status=success", flow)
+		}
+		waitForCalls(t, sender, flowID+":executed")
+	})
+}
@@ -0,0 +1,188 @@
+package goldsky
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// deadLetterMaxPayload 写入 dead-letter 表的请求体最大字节数，超出部分截断
+const deadLetterMaxPayload = 16 * 1024
+
+// webhookPayloadStringFields 顶层必须为字符串（或 null）的字段
+var webhookPayloadStringFields = []string{"data_source", "entity", "id", "op", "webhook_id", "webhook_name"}
+
+// webhookTransactionStringFields 交易数据中必须为字符串（或 null）的字段
+var webhookTransactionStringFields = []string{
+	"block_number", "block_range", "block_timestamp", "contract_address", "event_data", "event_eta", "event_signature",
+	"event_target", "event_tx_hash", "event_type", "event_value", "flow", "from_address", "id", "log_index", "tx_hash", "vid",
+}
+
+// webhookTransactionRequiredFields data.new 中必填的字段
+var webhookTransactionRequiredFields = []string{"tx_hash", "contract_address", "event_type", "block_number", "block_timestamp"}
+
+// ValidateWebhookPayload 在交给 WebhookProcessor 之前校验 webhook 请求体结构（字段类型、必填字段、数值/哈希/地址格式）
+// 校验通过时返回解析后的 payload；否则返回全部问题描述
+func ValidateWebhookPayload(raw []byte) (*types.GraphQLWebhookPayload, []string) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &root); err != nil || root == nil {
+		return nil, []string{"payload must be a JSON object"}
+	}
+
+	var problems []string
+	for _, field := range webhookPayloadStringFields {
+		if value, ok := root[field]; ok && !isJSONStringOrNull(value) {
+			problems = append(problems, field+" must be a string")
+		}
+	}
+
+	dataRaw, ok := root["data"]
+	if !ok || isJSONNull(dataRaw) {
+		problems = append(problems, "data is required")
+	} else {
+		var data map[string]json.RawMessage
+		if err := json.Unmarshal(dataRaw, &data); err != nil || data == nil {
+			problems = append(problems, "data must be an object")
+		} else {
+			for _, key := range []string{"new", "old"} {
+				if value, ok := data[key]; ok && !isJSONNull(value) {
+					problems = append(problems, validateWebhookTransaction("data."+key, value, key == "new")...)
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	var payload types.GraphQLWebhookPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, []string{err.Error()}
+	}
+	return &payload, nil
+}
+
+// validateWebhookTransaction 校验单条交易数据；required 为 true 时（data.new）检查必填字段与格式
+func validateWebhookTransaction(path string, raw json.RawMessage, required bool) []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return []string{path + " must be an object"}
+	}
+
+	var problems []string
+	values := make(map[string]string, len(fields))
+	for _, field := range webhookTransactionStringFields {
+		value, ok := fields[field]
+		if !ok || isJSONNull(value) {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s must be a string", path, field))
+			continue
+		}
+		values[field] = s
+	}
+	if !required {
+		return problems
+	}
+
+	for _, field := range webhookTransactionRequiredFields {
+		if value, ok := fields[field]; ok && !isJSONStringOrNull(value) {
+			continue // 类型错误已记录
+		}
+		if strings.TrimSpace(values[field]) == "" {
+			problems = append(problems, fmt.Sprintf("%s.%s is required", path, field))
+		}
+	}
+	for _, field := range []string{"block_number", "block_timestamp", "log_index"} {
+		if v := values[field]; v != "" && !isDecimalString(v) {
+			problems = append(problems, fmt.Sprintf("%s.%s must be a decimal number", path, field))
+		}
+	}
+	if v := values["tx_hash"]; v != "" && !isHexOfLength(v, 64) {
+		problems = append(problems, path+".tx_hash must be a 32-byte hex string")
+	}
+	if v := values["contract_address"]; v != "" && !isHexOfLength(v, 40) {
+		problems = append(problems, path+".contract_address must be a 20-byte hex address")
+	}
+	return problems
+}
+
+// RecordRejectedPayload 将未通过结构校验的 webhook 请求写入 dead-letter 表；写入失败只记录日志
+func (p *WebhookProcessor) RecordRejectedPayload(ctx context.Context, remoteAddr string, raw []byte, problems []string) {
+	reason := strings.Join(problems, "; ")
+	logger.Warn("Rejected malformed webhook payload", "remote_addr", remoteAddr, "reason", reason, "size", len(raw))
+	if p.eventRepo == nil {
+		return
+	}
+
+	payload := raw
+	if len(payload) > deadLetterMaxPayload {
+		payload = payload[:deadLetterMaxPayload]
+	}
+	// PostgreSQL TEXT 不接受非法 UTF-8 与 NUL 字符
+	text := strings.ReplaceAll(strings.ToValidUTF8(string(payload), "�"), "\x00", "")
+
+	letter := &types.GoldskyWebhookDeadLetter{
+		WebhookID:  extractWebhookID(raw),
+		RemoteAddr: remoteAddr,
+		Reason:     reason,
+		Payload:    text,
+	}
+	if err := p.eventRepo.SaveWebhookDeadLetter(ctx, letter); err != nil {
+		logger.Error("Failed to save webhook dead letter", err, "remote_addr", remoteAddr)
+	}
+}
+
+// extractWebhookID 尽力从请求体中取出 webhook_id，取不到时返回空
+func extractWebhookID(raw []byte) string {
+	var probe struct {
+		WebhookID json.RawMessage `json:"webhook_id"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return ""
+	}
+	var id string
+	if err := json.Unmarshal(probe.WebhookID, &id); err != nil || len(id) > 256 {
+		return ""
+	}
+	return id
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+func isJSONStringOrNull(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return isJSONNull(trimmed) || (len(trimmed) > 0 && trimmed[0] == '"')
+}
+
+func isDecimalString(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isHexOfLength 校验十六进制字符串，允许 0x 或 PostgreSQL 字节数组 \x 前缀（与 webhook 处理时的地址归一化一致）
+func isHexOfLength(s string, length int) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), `\x`)
+	if len(s) != length {
+		return false
+	}
+	for _, r := range strings.ToLower(s) {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
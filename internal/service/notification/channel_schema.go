@@ -0,0 +1,49 @@
+package notification
+
+import "timelocker-backend/internal/types"
+
+// channelSchemas 各渠道创建配置时的字段，必填项与 CreateNotificationConfig 中的校验保持一致；新增渠道时需同步维护
+var channelSchemas = []types.NotificationChannelSchema{
+	{Channel: types.ChannelTelegram, Fields: []types.NotificationChannelField{
+		{Name: "bot_token", Type: types.NotificationFieldTypeString, Required: true, Sensitive: true, Description: "Telegram bot token issued by @BotFather"},
+		{Name: "chat_id", Type: types.NotificationFieldTypeString, Required: true, Description: "Target chat, group or channel ID"},
+	}},
+	{Channel: types.ChannelLark, Fields: []types.NotificationChannelField{
+		{Name: "webhook_url", Type: types.NotificationFieldTypeURL, Required: true, Sensitive: true, Description: "Lark custom bot webhook URL"},
+		{Name: "secret", Type: types.NotificationFieldTypeString, Sensitive: true, Description: "Signing secret, required when signature verification is enabled on the bot"},
+	}},
+	{Channel: types.ChannelFeishu, Fields: []types.NotificationChannelField{
+		{Name: "webhook_url", Type: types.NotificationFieldTypeURL, Required: true, Sensitive: true, Description: "Feishu custom bot webhook URL"},
+		{Name: "secret", Type: types.NotificationFieldTypeString, Sensitive: true, Description: "Signing secret, required when signature verification is enabled on the bot"},
+	}},
+	{Channel: types.ChannelDiscord, Fields: []types.NotificationChannelField{
+		{Name: "webhook_url", Type: types.NotificationFieldTypeURL, Required: true, Sensitive: true, Description: "Discord channel webhook URL"},
+	}},
+	{Channel: types.ChannelSlack, Fields: []types.NotificationChannelField{
+		{Name: "webhook_url", Type: types.NotificationFieldTypeURL, Required: true, Sensitive: true, Description: "Slack incoming webhook URL"},
+	}},
+	{Channel: types.ChannelMatrix, Fields: []types.NotificationChannelField{
+		{Name: "homeserver_url", Type: types.NotificationFieldTypeURL, Required: true, Description: "Matrix homeserver base URL, e.g. https://matrix.org"},
+		{Name: "access_token", Type: types.NotificationFieldTypeString, Required: true, Sensitive: true, Description: "Access token of the sending account"},
+		{Name: "room_id", Type: types.NotificationFieldTypeString, Required: true, Description: "Target room ID, e.g. !abc:matrix.org"},
+	}},
+}
+
+// commonChannelFields 所有渠道共用的字段
+var commonChannelFields = []types.NotificationChannelField{
+	{Name: "name", Type: types.NotificationFieldTypeString, Required: true, Description: "Config name, unique per channel"},
+	{Name: "prefix", Type: types.NotificationFieldTypeString, MaxLength: maxMessageAffixLength, Description: "Line prepended to every message (e.g. [PROD]), no line breaks"},
+	{Name: "suffix", Type: types.NotificationFieldTypeString, MaxLength: maxMessageAffixLength, Description: "Line appended to every message, no line breaks"},
+}
+
+// GetNotificationChannelSchemas 获取全部支持渠道的配置字段（通用字段在前）
+func (s *notificationService) GetNotificationChannelSchemas() *types.NotificationChannelSchemaResponse {
+	channels := make([]types.NotificationChannelSchema, 0, len(channelSchemas))
+	for _, schema := range channelSchemas {
+		fields := make([]types.NotificationChannelField, 0, len(commonChannelFields)+len(schema.Fields))
+		fields = append(fields, commonChannelFields...)
+		fields = append(fields, schema.Fields...)
+		channels = append(channels, types.NotificationChannelSchema{Channel: schema.Channel, Fields: fields})
+	}
+	return &types.NotificationChannelSchemaResponse{Channels: channels}
+}
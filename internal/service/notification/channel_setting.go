@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/repository/audit"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// allNotificationChannels 支持的全部通知渠道，按展示顺序排列
+var allNotificationChannels = []types.NotificationChannel{
+	types.ChannelTelegram,
+	types.ChannelLark,
+	types.ChannelFeishu,
+	types.ChannelDiscord,
+	types.ChannelSlack,
+	types.ChannelMatrix,
+}
+
+// GetNotificationChannelSettings 获取用户全部渠道的开关状态，未设置过的渠道为开启
+func (s *notificationService) GetNotificationChannelSettings(ctx context.Context, userAddress string) (*types.NotificationChannelSettingsResponse, error) {
+	disabled, err := s.disabledChannels(ctx, userAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel settings: %w", err)
+	}
+
+	channels := make([]types.NotificationChannelStatus, 0, len(allNotificationChannels))
+	for _, channel := range allNotificationChannels {
+		channels = append(channels, types.NotificationChannelStatus{Channel: channel, Enabled: !disabled[channel]})
+	}
+	return &types.NotificationChannelSettingsResponse{Channels: channels}, nil
+}
+
+// SetNotificationChannelEnabled 开关整个渠道，不修改各配置自身的 is_active
+func (s *notificationService) SetNotificationChannelEnabled(ctx context.Context, userAddress, channel string, enabled bool) (*types.NotificationChannelSettingsResponse, error) {
+	normalized := types.NotificationChannel(strings.ToLower(strings.TrimSpace(channel)))
+	if !isSupportedChannel(normalized) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNotificationChannel, channel)
+	}
+
+	auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetNotificationConfig,
+		notificationConfigAuditTarget(string(normalized), "*"), fmt.Sprintf("updated channel enabled=%t", enabled))
+	if err := s.repo.UpsertNotificationChannelSetting(auditCtx, &types.NotificationChannelSetting{
+		UserAddress: userAddress,
+		Channel:     normalized,
+		Enabled:     enabled,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save channel setting: %w", err)
+	}
+	return s.GetNotificationChannelSettings(ctx, userAddress)
+}
+
+// disabledChannels 返回用户已关闭的渠道集合
+func (s *notificationService) disabledChannels(ctx context.Context, userAddress string) (map[types.NotificationChannel]bool, error) {
+	settings, err := s.repo.GetNotificationChannelSettings(ctx, userAddress)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[types.NotificationChannel]bool)
+	for _, setting := range settings {
+		if !setting.Enabled {
+			disabled[setting.Channel] = true
+		}
+	}
+	return disabled, nil
+}
+
+// dropDisabledChannels 去掉用户已整体关闭的渠道下的配置（即使配置本身 is_active=true）
+// 读取开关失败时保留全部配置，宁可多发也不漏发
+func (s *notificationService) dropDisabledChannels(ctx context.Context, userAddress string, configs *types.UserNotificationConfigs) {
+	disabled, err := s.disabledChannels(ctx, userAddress)
+	if err != nil {
+		logger.Error("Failed to get notification channel settings, sending to all active configs", err, "userAddress", userAddress)
+		return
+	}
+	filterChannels(configs, disabled)
+}
+
+// filterChannels 清空 disabled 中渠道的配置列表
+func filterChannels(configs *types.UserNotificationConfigs, disabled map[types.NotificationChannel]bool) {
+	if disabled[types.ChannelTelegram] {
+		configs.TelegramConfigs = nil
+	}
+	if disabled[types.ChannelLark] {
+		configs.LarkConfigs = nil
+	}
+	if disabled[types.ChannelFeishu] {
+		configs.FeishuConfigs = nil
+	}
+	if disabled[types.ChannelDiscord] {
+		configs.DiscordConfigs = nil
+	}
+	if disabled[types.ChannelSlack] {
+		configs.SlackConfigs = nil
+	}
+	if disabled[types.ChannelMatrix] {
+		configs.MatrixConfigs = nil
+	}
+}
+
+// isSupportedChannel 是否为支持的通知渠道
+func isSupportedChannel(channel types.NotificationChannel) bool {
+	for _, c := range allNotificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
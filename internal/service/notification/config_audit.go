@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+)
+
+// notificationConfigAuditTarget 通知配置在审计日志中的目标标识，如 telegram:ops
+func notificationConfigAuditTarget(channel, name string) string {
+	return strings.ToLower(channel) + ":" + name
+}
+
+// notificationConfigUpdateSummary 通知配置更新的审计摘要
+// 凭证类字段（token、webhook、secret 等）只记录字段名，不记录值
+func notificationConfigUpdateSummary(req *types.UpdateNotificationRequest) string {
+	var fields []string
+	secretFields := []struct {
+		name  string
+		value *string
+	}{
+		{"bot_token", req.BotToken},
+		{"chat_id", req.ChatID},
+		{"webhook_url", req.WebhookURL},
+		{"secret", req.Secret},
+		{"homeserver_url", req.HomeserverURL},
+		{"access_token", req.AccessToken},
+		{"room_id", req.RoomID},
+	}
+	for _, field := range secretFields {
+		if field.value != nil {
+			fields = append(fields, field.name)
+		}
+	}
+	if req.Prefix != nil {
+		fields = append(fields, fmt.Sprintf("prefix=%q", *req.Prefix))
+	}
+	if req.Suffix != nil {
+		fields = append(fields, fmt.Sprintf("suffix=%q", *req.Suffix))
+	}
+	if req.IsActive != nil {
+		fields = append(fields, fmt.Sprintf("is_active=%t", *req.IsActive))
+	}
+	return "updated " + strings.Join(fields, ", ")
+}
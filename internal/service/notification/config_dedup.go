@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+)
+
+// ErrDuplicateDestination 用户已有目标相同的激活配置
+var ErrDuplicateDestination = errors.New("duplicate notification destination")
+
+// checkDuplicateDestination 检查用户是否已有目标相同的激活配置（telegram 按 bot_token+chat_id，matrix 按 homeserver_url+room_id，webhook 类渠道按 webhook_url 跨渠道比较）
+func (s *notificationService) checkDuplicateDestination(ctx context.Context, userAddress string, req *types.CreateNotificationRequest) error {
+	active, err := s.repo.GetUserActiveNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return fmt.Errorf("failed to check duplicate destination: %w", err)
+	}
+
+	channel, name := findDuplicateDestination(active, req)
+	if name == "" {
+		return nil
+	}
+	return fmt.Errorf("%w: active %s config '%s' already uses this destination", ErrDuplicateDestination, channel, name)
+}
+
+// findDuplicateDestination 在激活配置中查找与请求目标相同的配置，返回其渠道与名称，未找到时名称为空
+func findDuplicateDestination(active *types.UserNotificationConfigs, req *types.CreateNotificationRequest) (types.NotificationChannel, string) {
+	if strings.ToLower(req.Channel) == string(types.ChannelTelegram) {
+		botToken, chatID := strings.TrimSpace(req.BotToken), strings.TrimSpace(req.ChatID)
+		for _, c := range active.TelegramConfigs {
+			if strings.TrimSpace(c.BotToken) == botToken && strings.TrimSpace(c.ChatID) == chatID {
+				return types.ChannelTelegram, c.Name
+			}
+		}
+		return "", ""
+	}
+
+	if strings.ToLower(req.Channel) == string(types.ChannelMatrix) {
+		homeserverURL, roomID := normalizeWebhookURL(req.HomeserverURL), strings.TrimSpace(req.RoomID)
+		for _, c := range active.MatrixConfigs {
+			if normalizeWebhookURL(c.HomeserverURL) == homeserverURL && strings.TrimSpace(c.RoomID) == roomID {
+				return types.ChannelMatrix, c.Name
+			}
+		}
+		return "", ""
+	}
+
+	webhookURL := normalizeWebhookURL(req.WebhookURL)
+	if webhookURL == "" {
+		return "", ""
+	}
+	for _, c := range active.LarkConfigs {
+		if normalizeWebhookURL(c.WebhookURL) == webhookURL {
+			return types.ChannelLark, c.Name
+		}
+	}
+	for _, c := range active.FeishuConfigs {
+		if normalizeWebhookURL(c.WebhookURL) == webhookURL {
+			return types.ChannelFeishu, c.Name
+		}
+	}
+	for _, c := range active.DiscordConfigs {
+		if normalizeWebhookURL(c.WebhookURL) == webhookURL {
+			return types.ChannelDiscord, c.Name
+		}
+	}
+	for _, c := range active.SlackConfigs {
+		if normalizeWebhookURL(c.WebhookURL) == webhookURL {
+			return types.ChannelSlack, c.Name
+		}
+	}
+	return "", ""
+}
+
+// normalizeWebhookURL 去掉首尾空白和末尾的 /，便于比较
+func normalizeWebhookURL(raw string) string {
+	return strings.TrimRight(strings.TrimSpace(raw), "/")
+}
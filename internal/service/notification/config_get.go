@@ -0,0 +1,100 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotificationConfigNotFound 指定渠道与名称的通知配置不存在
+var ErrNotificationConfigNotFound = errors.New("notification config not found")
+
+// ErrInvalidNotificationChannel 不支持的通知渠道
+var ErrInvalidNotificationChannel = errors.New("invalid notification channel")
+
+// GetNotificationConfig 按渠道与名称获取单个通知配置，reveal=false 时敏感字段只保留末尾 4 位
+func (s *notificationService) GetNotificationConfig(ctx context.Context, userAddress, channel, name string, reveal bool) (*types.NotificationConfig, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	name = strings.TrimSpace(name)
+
+	config, err := s.getNotificationConfig(ctx, userAddress, channel, name, reveal)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s config '%s'", ErrNotificationConfigNotFound, channel, name)
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// getNotificationConfig 读取配置并转换为通用结构
+func (s *notificationService) getNotificationConfig(ctx context.Context, userAddress, channel, name string, reveal bool) (*types.NotificationConfig, error) {
+	switch types.NotificationChannel(channel) {
+	case types.ChannelTelegram:
+		c, err := s.repo.GetTelegramConfigByUserAddressAndName(ctx, userAddress, name)
+		if err != nil {
+			return nil, err
+		}
+		config := newNotificationConfig(c.ID, c.UserAddress, c.Name, channel, c.IsActive, c.CreatedAt, c.UpdatedAt)
+		config.BotToken = secretField(c.BotToken, reveal)
+		config.ChatID = &c.ChatID
+		config.Prefix, config.Suffix = c.Prefix, c.Suffix
+		return config, nil
+	case types.ChannelLark:
+		c, err := s.repo.GetLarkConfigByUserAddressAndName(ctx, userAddress, name)
+		if err != nil {
+			return nil, err
+		}
+		config := newNotificationConfig(c.ID, c.UserAddress, c.Name, channel, c.IsActive, c.CreatedAt, c.UpdatedAt)
+		config.WebhookURL = secretField(c.WebhookURL, reveal)
+		config.Secret = secretField(c.Secret, reveal)
+		config.Prefix, config.Suffix = c.Prefix, c.Suffix
+		return config, nil
+	case types.ChannelFeishu:
+		c, err := s.repo.GetFeishuConfigByUserAddressAndName(ctx, userAddress, name)
+		if err != nil {
+			return nil, err
+		}
+		config := newNotificationConfig(c.ID, c.UserAddress, c.Name, channel, c.IsActive, c.CreatedAt, c.UpdatedAt)
+		config.WebhookURL = secretField(c.WebhookURL, reveal)
+		config.Secret = secretField(c.Secret, reveal)
+		config.Prefix, config.Suffix = c.Prefix, c.Suffix
+		return config, nil
+	case types.ChannelDiscord:
+		c, err := s.repo.GetDiscordConfigByUserAddressAndName(ctx, userAddress, name)
+		if err != nil {
+			return nil, err
+		}
+		config := newNotificationConfig(c.ID, c.UserAddress, c.Name, channel, c.IsActive, c.CreatedAt, c.UpdatedAt)
+		config.WebhookURL = secretField(c.WebhookURL, reveal)
+		config.Prefix, config.Suffix = c.Prefix, c.Suffix
+		return config, nil
+	case types.ChannelSlack:
+		c, err := s.repo.GetSlackConfigByUserAddressAndName(ctx, userAddress, name)
+		if err != nil {
+			return nil, err
+		}
+		config := newNotificationConfig(c.ID, c.UserAddress, c.Name, channel, c.IsActive, c.CreatedAt, c.UpdatedAt)
+		config.WebhookURL = secretField(c.WebhookURL, reveal)
+		config.Prefix, config.Suffix = c.Prefix, c.Suffix
+		return config, nil
+	case types.ChannelMatrix:
+		c, err := s.repo.GetMatrixConfigByUserAddressAndName(ctx, userAddress, name)
+		if err != nil {
+			return nil, err
+		}
+		config := newNotificationConfig(c.ID, c.UserAddress, c.Name, channel, c.IsActive, c.CreatedAt, c.UpdatedAt)
+		config.HomeserverURL = &c.HomeserverURL
+		config.AccessToken = secretField(c.AccessToken, reveal)
+		config.RoomID = &c.RoomID
+		config.Prefix, config.Suffix = c.Prefix, c.Suffix
+		return config, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNotificationChannel, channel)
+	}
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+)
+
+// secretVisibleSuffix 脱敏后保留的末尾字符数
+const secretVisibleSuffix = 4
+
+// maskSecret 隐藏敏感字段，只保留末尾 4 位便于用户辨认；过短的值整体隐藏
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	runes := []rune(value)
+	if len(runes) <= secretVisibleSuffix*2 {
+		return types.NotificationSecretMask
+	}
+	return types.NotificationSecretMask + string(runes[len(runes)-secretVisibleSuffix:])
+}
+
+// isMaskedSecret 判断值是否为脱敏结果（含早期导出使用的 [REDACTED] 占位值）
+func isMaskedSecret(value string) bool {
+	return value == types.NotificationConfigRedacted || strings.HasPrefix(value, types.NotificationSecretMask)
+}
+
+// secretField 按 reveal 返回明文或脱敏值，用于 types.NotificationConfig 的可选字段
+func secretField(value string, reveal bool) *string {
+	if !reveal {
+		value = maskSecret(value)
+	}
+	return &value
+}
+
+// dropMaskedSecrets 编辑表单原样回传脱敏值时视为未修改该字段，避免用掩码覆盖真实密钥
+func dropMaskedSecrets(req *types.UpdateNotificationRequest) {
+	for _, field := range []**string{&req.BotToken, &req.WebhookURL, &req.Secret, &req.AccessToken} {
+		if *field != nil && isMaskedSecret(**field) {
+			*field = nil
+		}
+	}
+}
+
+// redactNotificationConfigs 返回敏感字段脱敏后的副本，不修改原数据
+func redactNotificationConfigs(list *types.NotificationConfigListResponse) *types.NotificationConfigListResponse {
+	redacted := &types.NotificationConfigListResponse{
+		TelegramConfigs: make([]*types.TelegramConfig, 0, len(list.TelegramConfigs)),
+		LarkConfigs:     make([]*types.LarkConfig, 0, len(list.LarkConfigs)),
+		FeishuConfigs:   make([]*types.FeishuConfig, 0, len(list.FeishuConfigs)),
+		DiscordConfigs:  make([]*types.DiscordConfig, 0, len(list.DiscordConfigs)),
+		SlackConfigs:    make([]*types.SlackConfig, 0, len(list.SlackConfigs)),
+		MatrixConfigs:   make([]*types.MatrixConfig, 0, len(list.MatrixConfigs)),
+	}
+	for _, c := range list.TelegramConfigs {
+		copied := *c
+		copied.BotToken = maskSecret(c.BotToken)
+		redacted.TelegramConfigs = append(redacted.TelegramConfigs, &copied)
+	}
+	for _, c := range list.LarkConfigs {
+		copied := *c
+		copied.WebhookURL = maskSecret(c.WebhookURL)
+		copied.Secret = maskSecret(c.Secret)
+		redacted.LarkConfigs = append(redacted.LarkConfigs, &copied)
+	}
+	for _, c := range list.FeishuConfigs {
+		copied := *c
+		copied.WebhookURL = maskSecret(c.WebhookURL)
+		copied.Secret = maskSecret(c.Secret)
+		redacted.FeishuConfigs = append(redacted.FeishuConfigs, &copied)
+	}
+	for _, c := range list.DiscordConfigs {
+		copied := *c
+		copied.WebhookURL = maskSecret(c.WebhookURL)
+		redacted.DiscordConfigs = append(redacted.DiscordConfigs, &copied)
+	}
+	for _, c := range list.SlackConfigs {
+		copied := *c
+		copied.WebhookURL = maskSecret(c.WebhookURL)
+		redacted.SlackConfigs = append(redacted.SlackConfigs, &copied)
+	}
+	for _, c := range list.MatrixConfigs {
+		copied := *c
+		copied.AccessToken = maskSecret(c.AccessToken)
+		redacted.MatrixConfigs = append(redacted.MatrixConfigs, &copied)
+	}
+	return redacted
+}
+
+// newNotificationConfig 构造通用通知配置的公共字段
+func newNotificationConfig(id uint, userAddress, name, channel string, isActive bool, createdAt, updatedAt time.Time) *types.NotificationConfig {
+	return &types.NotificationConfig{
+		ID:          id,
+		UserAddress: userAddress,
+		Name:        name,
+		Channel:     channel,
+		IsActive:    isActive,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
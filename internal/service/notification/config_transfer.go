@@ -0,0 +1,203 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/repository/notification"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// ErrInvalidImportConfig 导入的配置不合法（整批拒绝，不做部分导入）
+var ErrInvalidImportConfig = errors.New("invalid import config")
+
+// ExportNotificationConfigs 导出用户的全部通知配置，默认与读取接口一致对敏感字段脱敏
+func (s *notificationService) ExportNotificationConfigs(ctx context.Context, userAddress string, includeSecrets bool) (*types.ExportNotificationConfigsResponse, error) {
+	all, err := s.listNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := func(v string) string {
+		if includeSecrets {
+			return v
+		}
+		return maskSecret(v)
+	}
+
+	configs := make([]types.NotificationConfigItem, 0)
+	for _, c := range all.TelegramConfigs {
+		isActive := c.IsActive
+		configs = append(configs, types.NotificationConfigItem{Name: c.Name, Channel: string(types.ChannelTelegram), IsActive: &isActive, Prefix: c.Prefix, Suffix: c.Suffix, BotToken: secret(c.BotToken), ChatID: c.ChatID})
+	}
+	for _, c := range all.LarkConfigs {
+		isActive := c.IsActive
+		configs = append(configs, types.NotificationConfigItem{Name: c.Name, Channel: string(types.ChannelLark), IsActive: &isActive, Prefix: c.Prefix, Suffix: c.Suffix, WebhookURL: secret(c.WebhookURL), Secret: secret(c.Secret)})
+	}
+	for _, c := range all.FeishuConfigs {
+		isActive := c.IsActive
+		configs = append(configs, types.NotificationConfigItem{Name: c.Name, Channel: string(types.ChannelFeishu), IsActive: &isActive, Prefix: c.Prefix, Suffix: c.Suffix, WebhookURL: secret(c.WebhookURL), Secret: secret(c.Secret)})
+	}
+	for _, c := range all.DiscordConfigs {
+		isActive := c.IsActive
+		configs = append(configs, types.NotificationConfigItem{Name: c.Name, Channel: string(types.ChannelDiscord), IsActive: &isActive, Prefix: c.Prefix, Suffix: c.Suffix, WebhookURL: secret(c.WebhookURL)})
+	}
+	for _, c := range all.SlackConfigs {
+		isActive := c.IsActive
+		configs = append(configs, types.NotificationConfigItem{Name: c.Name, Channel: string(types.ChannelSlack), IsActive: &isActive, Prefix: c.Prefix, Suffix: c.Suffix, WebhookURL: secret(c.WebhookURL)})
+	}
+	for _, c := range all.MatrixConfigs {
+		isActive := c.IsActive
+		configs = append(configs, types.NotificationConfigItem{Name: c.Name, Channel: string(types.ChannelMatrix), IsActive: &isActive, Prefix: c.Prefix, Suffix: c.Suffix, HomeserverURL: c.HomeserverURL, AccessToken: secret(c.AccessToken), RoomID: c.RoomID})
+	}
+
+	return &types.ExportNotificationConfigsResponse{
+		ExportedAt:      time.Now(),
+		SecretsIncluded: includeSecrets,
+		Configs:         configs,
+	}, nil
+}
+
+// ImportNotificationConfigs 导入通知配置：先整体校验，再在同一事务中逐条创建，同渠道同名的配置跳过
+// 任一条写入失败时整批回滚，不会留下部分导入的配置
+func (s *notificationService) ImportNotificationConfigs(ctx context.Context, userAddress string, req *types.ImportNotificationConfigsRequest) (*types.ImportNotificationConfigsResponse, error) {
+	for i := range req.Configs {
+		if err := validateImportConfig(&req.Configs[i]); err != nil {
+			return nil, fmt.Errorf("%w: configs[%d]: %v", ErrInvalidImportConfig, i, err)
+		}
+		if err := validateDestinationURLs(ctx, req.Configs[i].WebhookURL, req.Configs[i].HomeserverURL); err != nil {
+			return nil, fmt.Errorf("%w: configs[%d]: %v", ErrInvalidImportConfig, i, err)
+		}
+	}
+
+	var response *types.ImportNotificationConfigsResponse
+	err := s.repo.Transaction(ctx, func(repo notification.NotificationRepository) error {
+		txService := *s
+		txService.repo = repo
+		var err error
+		response, err = txService.importNotificationConfigs(ctx, userAddress, req.Configs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("ImportNotificationConfigs", "user_address", userAddress, "created", len(response.Created), "skipped", len(response.Skipped))
+	return response, nil
+}
+
+// importNotificationConfigs 逐条创建已校验的配置，需在事务中调用（s.repo 为事务 repo）
+func (s *notificationService) importNotificationConfigs(ctx context.Context, userAddress string, configs []types.NotificationConfigItem) (*types.ImportNotificationConfigsResponse, error) {
+	all, err := s.listNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, c := range all.TelegramConfigs {
+		existing[string(types.ChannelTelegram)+"/"+c.Name] = true
+	}
+	for _, c := range all.LarkConfigs {
+		existing[string(types.ChannelLark)+"/"+c.Name] = true
+	}
+	for _, c := range all.FeishuConfigs {
+		existing[string(types.ChannelFeishu)+"/"+c.Name] = true
+	}
+	for _, c := range all.DiscordConfigs {
+		existing[string(types.ChannelDiscord)+"/"+c.Name] = true
+	}
+	for _, c := range all.SlackConfigs {
+		existing[string(types.ChannelSlack)+"/"+c.Name] = true
+	}
+	for _, c := range all.MatrixConfigs {
+		existing[string(types.ChannelMatrix)+"/"+c.Name] = true
+	}
+
+	response := &types.ImportNotificationConfigsResponse{
+		Created: []types.ImportedNotificationConfig{},
+		Skipped: []types.ImportedNotificationConfig{},
+	}
+	for _, item := range configs {
+		result := types.ImportedNotificationConfig{Name: item.Name, Channel: item.Channel}
+		key := item.Channel + "/" + item.Name
+		if existing[key] {
+			result.Reason = "config with the same name already exists"
+			response.Skipped = append(response.Skipped, result)
+			continue
+		}
+
+		createReq := &types.CreateNotificationRequest{
+			Name:          item.Name,
+			Channel:       item.Channel,
+			BotToken:      item.BotToken,
+			ChatID:        item.ChatID,
+			WebhookURL:    item.WebhookURL,
+			Secret:        item.Secret,
+			HomeserverURL: item.HomeserverURL,
+			AccessToken:   item.AccessToken,
+			RoomID:        item.RoomID,
+			Prefix:        item.Prefix,
+			Suffix:        item.Suffix,
+		}
+		if err := s.CreateNotificationConfig(ctx, userAddress, createReq); err != nil {
+			if errors.Is(err, ErrDuplicateDestination) {
+				result.Reason = "an active config with the same destination already exists"
+				response.Skipped = append(response.Skipped, result)
+				continue
+			}
+			return nil, fmt.Errorf("failed to import %s config '%s': %w", item.Channel, item.Name, err)
+		}
+		existing[key] = true
+
+		if item.IsActive != nil && !*item.IsActive {
+			updateReq := &types.UpdateNotificationRequest{Name: &item.Name, Channel: &item.Channel, IsActive: item.IsActive}
+			if err := s.UpdateNotificationConfig(ctx, userAddress, updateReq); err != nil {
+				return nil, fmt.Errorf("failed to deactivate imported %s config '%s': %w", item.Channel, item.Name, err)
+			}
+		}
+		response.Created = append(response.Created, result)
+	}
+
+	return response, nil
+}
+
+// validateImportConfig 校验并标准化单条导入配置
+func validateImportConfig(item *types.NotificationConfigItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+
+	item.Channel = strings.ToLower(strings.TrimSpace(item.Channel))
+	switch types.NotificationChannel(item.Channel) {
+	case types.ChannelTelegram:
+		if item.BotToken == "" || item.ChatID == "" {
+			return fmt.Errorf("bot_token and chat_id are required for telegram channel")
+		}
+	case types.ChannelLark, types.ChannelFeishu, types.ChannelDiscord, types.ChannelSlack:
+		if item.WebhookURL == "" {
+			return fmt.Errorf("webhook_url is required for %s channel", item.Channel)
+		}
+	case types.ChannelMatrix:
+		if item.HomeserverURL == "" || item.AccessToken == "" || item.RoomID == "" {
+			return fmt.Errorf("homeserver_url, access_token and room_id are required for matrix channel")
+		}
+	default:
+		return fmt.Errorf("invalid channel: %s", item.Channel)
+	}
+
+	if err := validateMessageAffix(item.Prefix, item.Suffix); err != nil {
+		return err
+	}
+
+	// 未包含敏感字段的导出文件无法直接恢复
+	for _, value := range []string{item.BotToken, item.WebhookURL, item.Secret, item.AccessToken} {
+		if isMaskedSecret(value) {
+			return fmt.Errorf("config '%s' contains redacted secrets, export with include_secrets to import", item.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"timelocker-backend/internal/repository/notification"
+	"timelocker-backend/internal/types"
+
+	"gorm.io/gorm"
+)
+
+// fakeTelegramRepo 只保存 Telegram 配置；Transaction 在副本上执行 fn，成功时才替换原数据，模拟事务提交与回滚
+type fakeTelegramRepo struct {
+	notification.NotificationRepository
+	configs      map[string]*types.TelegramConfig
+	failCreateOn string // 创建该名称的配置时返回错误
+	transactions int
+}
+
+func newFakeTelegramRepo(names ...string) *fakeTelegramRepo {
+	r := &fakeTelegramRepo{configs: map[string]*types.TelegramConfig{}}
+	for _, name := range names {
+		r.configs[name] = &types.TelegramConfig{Name: name, BotToken: "existing-" + name, ChatID: "1", IsActive: true}
+	}
+	return r
+}
+
+func (r *fakeTelegramRepo) Transaction(ctx context.Context, fn func(repo notification.NotificationRepository) error) error {
+	r.transactions++
+	staged := &fakeTelegramRepo{configs: map[string]*types.TelegramConfig{}, failCreateOn: r.failCreateOn}
+	for name, c := range r.configs {
+		copied := *c
+		staged.configs[name] = &copied
+	}
+	if err := fn(staged); err != nil {
+		return err
+	}
+	r.configs = staged.configs
+	return nil
+}
+
+func (r *fakeTelegramRepo) GetTelegramConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.TelegramConfig, error) {
+	result := make([]*types.TelegramConfig, 0, len(r.configs))
+	for _, c := range r.configs {
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func (r *fakeTelegramRepo) GetTelegramConfigByUserAddressAndName(ctx context.Context, userAddress, name string) (*types.TelegramConfig, error) {
+	if c, ok := r.configs[name]; ok {
+		return c, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeTelegramRepo) CreateTelegramConfig(ctx context.Context, config *types.TelegramConfig) error {
+	if config.Name == r.failCreateOn {
+		return errors.New("insert failed")
+	}
+	r.configs[config.Name] = config
+	return nil
+}
+
+func (r *fakeTelegramRepo) UpdateTelegramConfig(ctx context.Context, userAddress, name string, updates map[string]interface{}) error {
+	if isActive, ok := updates["is_active"].(bool); ok {
+		r.configs[name].IsActive = isActive
+	}
+	return nil
+}
+
+func (r *fakeTelegramRepo) GetUserActiveNotificationConfigs(ctx context.Context, userAddress string) (*types.UserNotificationConfigs, error) {
+	active := &types.UserNotificationConfigs{}
+	for _, c := range r.configs {
+		if c.IsActive {
+			active.TelegramConfigs = append(active.TelegramConfigs, c)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeTelegramRepo) GetLarkConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.LarkConfig, error) {
+	return nil, nil
+}
+
+func (r *fakeTelegramRepo) GetFeishuConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.FeishuConfig, error) {
+	return nil, nil
+}
+
+func (r *fakeTelegramRepo) GetDiscordConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.DiscordConfig, error) {
+	return nil, nil
+}
+
+func (r *fakeTelegramRepo) GetSlackConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.SlackConfig, error) {
+	return nil, nil
+}
+
+func (r *fakeTelegramRepo) GetMatrixConfigsByUserAddress(ctx context.Context, userAddress string) ([]*types.MatrixConfig, error) {
+	return nil, nil
+}
+
+func telegramItem(name, botToken string) types.NotificationConfigItem {
+	return types.NotificationConfigItem{Name: name, Channel: "telegram", BotToken: botToken, ChatID: "42"}
+}
+
+func TestImportNotificationConfigs(t *testing.T) {
+	tests := []struct {
+		name         string
+		existing     []string
+		failCreateOn string
+		configs      []types.NotificationConfigItem
+		wantErr      error // 非 nil 时用 errors.Is 比较
+		wantAnyErr   bool
+		wantCreated  int
+		wantSkipped  int
+		wantStored   []string
+		wantTx       int
+	}{
+		{
+			name:        "creates all and skips existing names",
+			existing:    []string{"ops"},
+			configs:     []types.NotificationConfigItem{telegramItem("ops", "a"), telegramItem("alerts", "b"), telegramItem("team", "c")},
+			wantCreated: 2,
+			wantSkipped: 1,
+			wantStored:  []string{"ops", "alerts", "team"},
+			wantTx:      1,
+		},
+		{
+			name:        "skips duplicate destination",
+			existing:    []string{"ops"},
+			configs:     []types.NotificationConfigItem{{Name: "copy", Channel: "telegram", BotToken: "existing-ops", ChatID: "1"}},
+			wantSkipped: 1,
+			wantStored:  []string{"ops"},
+			wantTx:      1,
+		},
+		{
+			name:         "write failure rolls back earlier configs",
+			existing:     []string{"ops"},
+			failCreateOn: "team",
+			configs:      []types.NotificationConfigItem{telegramItem("alerts", "b"), telegramItem("team", "c")},
+			wantAnyErr:   true,
+			wantStored:   []string{"ops"},
+			wantTx:       1,
+		},
+		{
+			name:       "invalid config rejects the batch before writing",
+			configs:    []types.NotificationConfigItem{telegramItem("alerts", "b"), {Name: "bad", Channel: "telegram"}},
+			wantErr:    ErrInvalidImportConfig,
+			wantStored: []string{},
+			wantTx:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeTelegramRepo(tt.existing...)
+			repo.failCreateOn = tt.failCreateOn
+			s := &notificationService{repo: repo}
+
+			resp, err := s.ImportNotificationConfigs(context.Background(), "0xuser", &types.ImportNotificationConfigsRequest{Configs: tt.configs})
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("ImportNotificationConfigs: %v", err)
+				}
+				if len(resp.Created) != tt.wantCreated || len(resp.Skipped) != tt.wantSkipped {
+					t.Fatalf("created %d skipped %d, want %d and %d", len(resp.Created), len(resp.Skipped), tt.wantCreated, tt.wantSkipped)
+				}
+			}
+
+			if repo.transactions != tt.wantTx {
+				t.Fatalf("transactions = %d, want %d", repo.transactions, tt.wantTx)
+			}
+			if len(repo.configs) != len(tt.wantStored) {
+				t.Fatalf("stored %d configs, want %v", len(repo.configs), tt.wantStored)
+			}
+			for _, name := range tt.wantStored {
+				if repo.configs[name] == nil {
+					t.Fatalf("config %q not stored", name)
+				}
+			}
+		})
+	}
+
+	// 导入时指定 is_active=false 的配置在同一事务中被停用
+	inactive := false
+	repo := newFakeTelegramRepo()
+	s := &notificationService{repo: repo}
+	if _, err := s.ImportNotificationConfigs(context.Background(), "0xuser", &types.ImportNotificationConfigsRequest{
+		Configs: []types.NotificationConfigItem{{Name: "muted", Channel: "telegram", BotToken: "m", ChatID: "1", IsActive: &inactive}},
+	}); err != nil {
+		t.Fatalf("ImportNotificationConfigs: %v", err)
+	}
+	if repo.configs["muted"].IsActive {
+		t.Fatal("imported config should be inactive")
+	}
+}
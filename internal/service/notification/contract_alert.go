@@ -0,0 +1,101 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// SendContractAlert 向用户所有激活的通知渠道发送合约告警（合约复核失败被标记为 inactive，或关键参数异常变化）
+// 告警与流程无关，不写 notification_logs；单个渠道发送失败只记录日志
+func (s *notificationService) SendContractAlert(ctx context.Context, userAddress, standard string, chainID int, contractAddress, reason string, deactivated bool) error {
+	chainName := s.alertChainName(ctx, chainID)
+	message := buildContractAlertMessage(standard, chainName, contractAddress, reason, deactivated)
+	// Telegram 使用 HTML parse mode，需要转义
+	telegramMessage := buildContractAlertMessage(standard, html.EscapeString(chainName), contractAddress, html.EscapeString(reason), deactivated)
+
+	sent, failed, err := s.sendUserAlert(ctx, userAddress, message, telegramMessage)
+	if err != nil {
+		return err
+	}
+	logger.Info("Contract alert sent", "userAddress", userAddress, "standard", standard, "chainID", chainID, "contract", contractAddress, "deactivated", deactivated, "sent", sent, "failed", failed)
+	return nil
+}
+
+// alertChainName 告警中展示的链名称，查询失败时只展示链ID
+func (s *notificationService) alertChainName(ctx context.Context, chainID int) string {
+	if chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(chainID)); err == nil && chainInfo != nil {
+		return fmt.Sprintf("%s (%d)", chainInfo.DisplayName, chainID)
+	}
+	return fmt.Sprintf("%d", chainID)
+}
+
+// sendUserAlert 向用户所有激活的通知渠道发送告警消息，返回发送成功/失败的渠道数
+// telegramMessage 为 HTML 转义后的消息；单个渠道发送失败只记录日志
+func (s *notificationService) sendUserAlert(ctx context.Context, userAddress, message, telegramMessage string) (int, int, error) {
+	configs, err := s.repo.GetUserActiveNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get user notification configs: %w", err)
+	}
+	s.dropDisabledChannels(ctx, userAddress, configs)
+
+	sent, failed := 0, 0
+	record := func(channel types.NotificationChannel, configID uint, err error) {
+		if err != nil {
+			failed++
+			logger.Error("Failed to send user alert", err, "channel", channel, "configID", configID, "userAddress", userAddress)
+			return
+		}
+		sent++
+	}
+
+	for _, config := range configs.TelegramConfigs {
+		_, err := s.telegramSender.SendMessage(ctx, config.BotToken, config.ChatID, configMessage(types.ChannelTelegram, telegramMessage, config.Prefix, config.Suffix))
+		record(types.ChannelTelegram, config.ID, err)
+	}
+	for _, config := range configs.LarkConfigs {
+		_, err := s.larkSender.SendMessage(ctx, config.WebhookURL, config.Secret, configMessage(types.ChannelLark, message, config.Prefix, config.Suffix))
+		record(types.ChannelLark, config.ID, err)
+	}
+	for _, config := range configs.FeishuConfigs {
+		_, err := s.feishuSender.SendMessage(ctx, config.WebhookURL, config.Secret, configMessage(types.ChannelFeishu, message, config.Prefix, config.Suffix))
+		record(types.ChannelFeishu, config.ID, err)
+	}
+	for _, config := range configs.DiscordConfigs {
+		_, err := s.discordSender.SendMessage(ctx, config.WebhookURL, configMessage(types.ChannelDiscord, message, config.Prefix, config.Suffix))
+		record(types.ChannelDiscord, config.ID, err)
+	}
+	for _, config := range configs.SlackConfigs {
+		_, err := s.slackSender.SendMessage(ctx, config.WebhookURL, configMessage(types.ChannelSlack, message, config.Prefix, config.Suffix))
+		record(types.ChannelSlack, config.ID, err)
+	}
+	for _, config := range configs.MatrixConfigs {
+		_, err := s.matrixSender.SendMessage(ctx, config.HomeserverURL, config.AccessToken, config.RoomID, configMessage(types.ChannelMatrix, message, config.Prefix, config.Suffix))
+		record(types.ChannelMatrix, config.ID, err)
+	}
+	return sent, failed, nil
+}
+
+// buildContractAlertMessage 构建合约告警消息
+func buildContractAlertMessage(standard, chainName, contractAddress, reason string, deactivated bool) string {
+	var b strings.Builder
+	if deactivated {
+		b.WriteString("⚠️ TimeLocker: timelock contract marked inactive\n\n")
+	} else {
+		b.WriteString("⚠️ TimeLocker: timelock contract parameters changed\n\n")
+	}
+	fmt.Fprintf(&b, "Standard: %s\n", standard)
+	fmt.Fprintf(&b, "Chain: %s\n", chainName)
+	fmt.Fprintf(&b, "Contract: %s\n", contractAddress)
+	fmt.Fprintf(&b, "Reason: %s\n\n", reason)
+	if deactivated {
+		b.WriteString("Monitoring is paused until the contract passes re-verification again. Please verify it on-chain.")
+	} else {
+		b.WriteString("The contract is still being monitored. Please verify on-chain that this change was expected.")
+	}
+	return b.String()
+}
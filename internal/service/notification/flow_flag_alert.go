@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// SendFlowFlagAlert 向标记人所有激活的通知渠道发送可疑流程告警（高优先级），单个渠道发送失败只记录日志
+func (s *notificationService) SendFlowFlagAlert(ctx context.Context, userAddress string, flag *types.FlowFlag) (int, error) {
+	chainName := s.alertChainName(ctx, flag.ChainID)
+	message := buildFlowFlagAlertMessage(flag, chainName, flag.Note)
+	// Telegram 使用 HTML parse mode，需要转义
+	telegramMessage := buildFlowFlagAlertMessage(flag, html.EscapeString(chainName), html.EscapeString(flag.Note))
+
+	sent, failed, err := s.sendUserAlert(ctx, userAddress, message, telegramMessage)
+	if err != nil {
+		return 0, err
+	}
+	logger.Info("Flow flag alert sent", "userAddress", userAddress, "standard", flag.Standard, "chainID", flag.ChainID, "contract", flag.ContractAddress, "flowID", flag.FlowID, "sent", sent, "failed", failed)
+	return sent, nil
+}
+
+// buildFlowFlagAlertMessage 构建可疑流程告警消息
+func buildFlowFlagAlertMessage(flag *types.FlowFlag, chainName, note string) string {
+	var b strings.Builder
+	b.WriteString("🚨 CRITICAL: TimeLocker flow flagged as suspicious\n\n")
+	fmt.Fprintf(&b, "Standard: %s\n", flag.Standard)
+	fmt.Fprintf(&b, "Chain: %s\n", chainName)
+	fmt.Fprintf(&b, "Contract: %s\n", flag.ContractAddress)
+	fmt.Fprintf(&b, "Flow: %s\n", flag.FlowID)
+	fmt.Fprintf(&b, "Flagged by: %s\n", flag.FlaggedBy)
+	fmt.Fprintf(&b, "Note: %s\n\n", note)
+	b.WriteString("Please review this flow on-chain and cancel it before its ETA if it was not expected.")
+	return b.String()
+}
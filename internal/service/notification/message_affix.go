@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"timelocker-backend/internal/types"
+)
+
+// maxMessageAffixLength 消息前缀/后缀的最大字符数
+// 生成渠道消息时按该长度预留前后缀空间（见 maxAffixOverhead）
+const maxMessageAffixLength = 32
+
+// ErrInvalidMessageAffix 消息前缀/后缀不合法
+var ErrInvalidMessageAffix = errors.New("invalid message prefix/suffix")
+
+// validateMessageAffix 校验消息前缀/后缀：最长 maxMessageAffixLength 个字符，不能包含换行等控制字符
+func validateMessageAffix(prefix, suffix string) error {
+	for _, field := range []struct{ name, value string }{{"prefix", prefix}, {"suffix", suffix}} {
+		if utf8.RuneCountInString(field.value) > maxMessageAffixLength {
+			return fmt.Errorf("%w: %s exceeds %d characters", ErrInvalidMessageAffix, field.name, maxMessageAffixLength)
+		}
+		if strings.ContainsFunc(field.value, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+			return fmt.Errorf("%w: %s contains control characters", ErrInvalidMessageAffix, field.name)
+		}
+	}
+	return nil
+}
+
+// configMessage 生成发送到某条渠道配置的最终消息：加上该配置的前缀/后缀（Telegram 使用 HTML 解析，前后缀需转义）
+// 实际发送与预览都通过该函数生成，保证预览内容与发送内容一致
+func configMessage(channel types.NotificationChannel, message, prefix, suffix string) string {
+	if channel == types.ChannelTelegram {
+		prefix, suffix = html.EscapeString(prefix), html.EscapeString(suffix)
+	}
+	return wrapMessage(message, prefix, suffix)
+}
+
+// maxAffixOverhead 前缀与后缀（各加一个换行）在该渠道下可能占用的最大长度
+// Telegram 前后缀经 HTML 转义后单个字符最多变为 5 个码元（如 "&" -> "&amp;"）；其他渠道按字符计数，不转义
+func maxAffixOverhead(channel types.NotificationChannel) int {
+	perAffix := maxMessageAffixLength
+	if channel == types.ChannelTelegram {
+		perAffix = maxMessageAffixLength * len("&amp;")
+	}
+	return 2 * (perAffix + 1)
+}
+
+// wrapMessage 在消息前后分别加上配置的前缀/后缀（各占一行），都为空时原样返回
+func wrapMessage(message, prefix, suffix string) string {
+	prefix = strings.TrimSpace(prefix)
+	suffix = strings.TrimSpace(suffix)
+	if prefix != "" {
+		message = prefix + "\n" + message
+	}
+	if suffix != "" {
+		message = strings.TrimRight(message, "\n") + "\n" + suffix + "\n"
+	}
+	return message
+}
@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+)
+
+// GetNotificationLogs 分页获取用户的通知发送日志
+func (s *notificationService) GetNotificationLogs(ctx context.Context, userAddress string, req *types.GetNotificationLogsRequest) (*types.GetNotificationLogsResponse, error) {
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, 20)
+	offset := (page - 1) * pageSize
+
+	logs, total, err := s.repo.GetUserNotificationLogs(ctx, userAddress, req.Channel, strings.TrimSpace(req.FlowID), req.SendStatus, offset, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification logs: %w", err)
+	}
+	if logs == nil {
+		logs = []types.NotificationLog{}
+	}
+
+	return &types.GetNotificationLogsResponse{
+		Logs:           logs,
+		Total:          total,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
+
+// defaultNotificationStatsDays 发送统计默认窗口（天）
+const defaultNotificationStatsDays = 7
+
+// GetNotificationStats 统计用户最近 days 天的通知发送情况，按渠道汇总成功/失败数
+func (s *notificationService) GetNotificationStats(ctx context.Context, userAddress string, req *types.GetNotificationStatsRequest) (*types.GetNotificationStatsResponse, error) {
+	days := req.Days
+	if days <= 0 {
+		days = defaultNotificationStatsDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	counts, err := s.repo.CountUserNotificationLogsByStatus(ctx, userAddress, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification stats: %w", err)
+	}
+
+	response := &types.GetNotificationStatsResponse{
+		Days:     days,
+		Since:    since,
+		Channels: []types.NotificationChannelStats{},
+	}
+	byChannel := make(map[types.NotificationChannel]int)
+	for _, row := range counts {
+		idx, ok := byChannel[row.Channel]
+		if !ok {
+			idx = len(response.Channels)
+			byChannel[row.Channel] = idx
+			response.Channels = append(response.Channels, types.NotificationChannelStats{Channel: row.Channel})
+		}
+		stats := &response.Channels[idx]
+		switch row.SendStatus {
+		case "success":
+			stats.Sent += row.Count
+			response.Sent += row.Count
+		case "failed":
+			stats.Failed += row.Count
+			response.Failed += row.Count
+		}
+		stats.Total += row.Count
+		response.Total += row.Count
+	}
+	return response, nil
+}
@@ -3,11 +3,13 @@ package notification
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync/atomic"
 	"time"
 	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/audit"
 	chainRepo "timelocker-backend/internal/repository/chain"
 	goldskyRepo "timelocker-backend/internal/repository/goldsky"
 	"timelocker-backend/internal/repository/notification"
@@ -16,6 +18,8 @@ import (
 	"timelocker-backend/pkg/logger"
 	notificationPkg "timelocker-backend/pkg/notification"
 	"timelocker-backend/pkg/utils"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
@@ -28,11 +32,41 @@ type NotificationService interface {
 	UpdateNotificationConfig(ctx context.Context, userAddress string, req *types.UpdateNotificationRequest) error
 	DeleteNotificationConfig(ctx context.Context, userAddress string, req *types.DeleteNotificationRequest) error
 
-	// 获取所有通知配置
-	GetAllNotificationConfigs(ctx context.Context, userAddress string) (*types.NotificationConfigListResponse, error)
+	// 获取所有通知配置，reveal=false 时敏感字段脱敏
+	GetAllNotificationConfigs(ctx context.Context, userAddress string, reveal bool) (*types.NotificationConfigListResponse, error)
+	// 按渠道与名称获取单个通知配置，reveal=false 时敏感字段脱敏
+	GetNotificationConfig(ctx context.Context, userAddress, channel, name string, reveal bool) (*types.NotificationConfig, error)
+
+	// 渠道类型总开关（关闭后该渠道下所有配置都不发送）
+	GetNotificationChannelSettings(ctx context.Context, userAddress string) (*types.NotificationChannelSettingsResponse, error)
+	SetNotificationChannelEnabled(ctx context.Context, userAddress, channel string, enabled bool) (*types.NotificationChannelSettingsResponse, error)
+	// 各渠道创建配置所需的字段，供前端动态渲染表单
+	GetNotificationChannelSchemas() *types.NotificationChannelSchemaResponse
+
+	// 导出/导入通知配置
+	ExportNotificationConfigs(ctx context.Context, userAddress string, includeSecrets bool) (*types.ExportNotificationConfigsResponse, error)
+	ImportNotificationConfigs(ctx context.Context, userAddress string, req *types.ImportNotificationConfigsRequest) (*types.ImportNotificationConfigsResponse, error)
+
+	// 校验 webhook URL 可达性（含 SSRF 防护）
+	ValidateWebhookURL(ctx context.Context, userAddress string, req *types.ValidateWebhookURLRequest) (*types.ValidateWebhookURLResponse, error)
+
+	// 获取通知发送日志
+	GetNotificationLogs(ctx context.Context, userAddress string, req *types.GetNotificationLogsRequest) (*types.GetNotificationLogsResponse, error)
+	// 获取通知发送统计
+	GetNotificationStats(ctx context.Context, userAddress string, req *types.GetNotificationStatsRequest) (*types.GetNotificationStatsResponse, error)
 
 	// 通知发送
 	SendFlowNotification(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) error
+	// 按发送时的筛选逻辑判定用户自己的渠道配置是否会收到通知（不发送），返回用户是否为合约相关用户
+	ExplainFlowNotification(ctx context.Context, userAddress, standard string, chainID int, contractAddress, flowID, statusTo string) (bool, []types.WouldNotifyConfig, error)
+	// 预览通知（只渲染消息，不发送也不写通知日志），流程不存在时返回 nil
+	PreviewFlowNotification(ctx context.Context, userAddress string, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string) (*types.PreviewFlowNotificationResponse, error)
+	// 校验并试渲染通知消息模板
+	PreviewNotificationTemplate(ctx context.Context, userAddress string, req *types.PreviewNotificationTemplateRequest) (*types.PreviewNotificationTemplateResponse, error)
+	// 发送合约告警（如合约复核失败被标记为 inactive），不写通知日志
+	SendContractAlert(ctx context.Context, userAddress, standard string, chainID int, contractAddress, reason string, deactivated bool) error
+	// 向标记人的通知渠道发送可疑流程告警，返回发送成功的渠道数，不写通知日志
+	SendFlowFlagAlert(ctx context.Context, userAddress string, flag *types.FlowFlag) (int, error)
 }
 
 // notificationService 通知服务实现
@@ -47,6 +81,7 @@ type notificationService struct {
 	feishuSender   *notificationPkg.FeishuSender
 	discordSender  *notificationPkg.DiscordSender
 	slackSender    *notificationPkg.SlackSender
+	matrixSender   *notificationPkg.MatrixSender
 }
 
 // NewNotificationService 创建通知服务实例
@@ -57,23 +92,46 @@ func NewNotificationService(repo notification.NotificationRepository, chainRepo
 		timelockRepo:   timelockRepo,
 		flowRepo:       flowRepo,
 		config:         config,
-		telegramSender: notificationPkg.NewTelegramSender(),
-		larkSender:     notificationPkg.NewLarkSender(),
-		feishuSender:   notificationPkg.NewFeishuSender(),
-		discordSender:  notificationPkg.NewDiscordSender(),
-		slackSender:    notificationPkg.NewSlackSender(),
+		telegramSender: notificationPkg.NewTelegramSender(channelSendTimeout(config, types.ChannelTelegram)),
+		larkSender:     notificationPkg.NewLarkSender(channelSendTimeout(config, types.ChannelLark)),
+		feishuSender:   notificationPkg.NewFeishuSender(channelSendTimeout(config, types.ChannelFeishu)),
+		discordSender:  notificationPkg.NewDiscordSender(channelSendTimeout(config, types.ChannelDiscord)),
+		slackSender:    notificationPkg.NewSlackSender(channelSendTimeout(config, types.ChannelSlack)),
+		matrixSender:   notificationPkg.NewMatrixSender(channelSendTimeout(config, types.ChannelMatrix)),
+	}
+}
+
+// channelSendTimeout 获取渠道发送超时配置，未配置时返回 0（由发送器使用默认值）
+func channelSendTimeout(cfg *config.Config, channel types.NotificationChannel) time.Duration {
+	if cfg == nil {
+		return 0
 	}
+	return cfg.Notification.SendTimeout[string(channel)]
 }
 
 // ===== 通用配置管理 =====
 // CreateNotificationConfig 创建通知配置
 func (s *notificationService) CreateNotificationConfig(ctx context.Context, userAddress string, req *types.CreateNotificationRequest) error {
+	if err := validateMessageAffix(req.Prefix, req.Suffix); err != nil {
+		return err
+	}
+	if err := validateDestinationURLs(ctx, req.WebhookURL, req.HomeserverURL); err != nil {
+		return err
+	}
+	if !req.AllowDuplicate {
+		if err := s.checkDuplicateDestination(ctx, userAddress, req); err != nil {
+			return err
+		}
+	}
+
+	ctx = audit.WithLog(ctx, types.AuditActionCreate, types.AuditTargetNotificationConfig,
+		notificationConfigAuditTarget(req.Channel, req.Name), fmt.Sprintf("created %s config", strings.ToLower(req.Channel)))
 	switch strings.ToLower(req.Channel) {
 	case "telegram":
 		if req.BotToken == "" || req.ChatID == "" {
 			return fmt.Errorf("bot_token and chat_id are required")
 		}
-		err := s.createTelegramConfig(ctx, userAddress, req.Name, req.BotToken, req.ChatID)
+		err := s.createTelegramConfig(ctx, userAddress, req.Name, req.BotToken, req.ChatID, req.Prefix, req.Suffix)
 		if err != nil {
 			return err
 		}
@@ -83,7 +141,7 @@ func (s *notificationService) CreateNotificationConfig(ctx context.Context, user
 		if req.WebhookURL == "" {
 			return fmt.Errorf("webhook_url are required")
 		}
-		err := s.createLarkConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret)
+		err := s.createLarkConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret, req.Prefix, req.Suffix)
 		if err != nil {
 			return err
 		}
@@ -93,7 +151,7 @@ func (s *notificationService) CreateNotificationConfig(ctx context.Context, user
 		if req.WebhookURL == "" {
 			return fmt.Errorf("webhook_url are required")
 		}
-		err := s.createFeishuConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret)
+		err := s.createFeishuConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret, req.Prefix, req.Suffix)
 		if err != nil {
 			return err
 		}
@@ -102,7 +160,7 @@ func (s *notificationService) CreateNotificationConfig(ctx context.Context, user
 		if req.WebhookURL == "" {
 			return fmt.Errorf("webhook_url are required")
 		}
-		err := s.createDiscordConfig(ctx, userAddress, req.Name, req.WebhookURL)
+		err := s.createDiscordConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Prefix, req.Suffix)
 		if err != nil {
 			return err
 		}
@@ -111,7 +169,16 @@ func (s *notificationService) CreateNotificationConfig(ctx context.Context, user
 		if req.WebhookURL == "" {
 			return fmt.Errorf("webhook_url are required")
 		}
-		err := s.createSlackConfig(ctx, userAddress, req.Name, req.WebhookURL)
+		err := s.createSlackConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Prefix, req.Suffix)
+		if err != nil {
+			return err
+		}
+		return nil
+	case "matrix":
+		if req.HomeserverURL == "" || req.AccessToken == "" || req.RoomID == "" {
+			return fmt.Errorf("homeserver_url, access_token and room_id are required")
+		}
+		err := s.createMatrixConfig(ctx, userAddress, req.Name, req.HomeserverURL, req.AccessToken, req.RoomID, req.Prefix, req.Suffix)
 		if err != nil {
 			return err
 		}
@@ -123,38 +190,54 @@ func (s *notificationService) CreateNotificationConfig(ctx context.Context, user
 // UpdateNotificationConfig 更新通知配置
 // 不需要更新的字段可以不填
 func (s *notificationService) UpdateNotificationConfig(ctx context.Context, userAddress string, req *types.UpdateNotificationRequest) error {
+	dropMaskedSecrets(req)
+	if err := validateMessageAffix(derefString(req.Prefix), derefString(req.Suffix)); err != nil {
+		return err
+	}
+	if err := validateDestinationURLs(ctx, derefString(req.WebhookURL), derefString(req.HomeserverURL)); err != nil {
+		return err
+	}
+	ctx = audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetNotificationConfig,
+		notificationConfigAuditTarget(*req.Channel, *req.Name), notificationConfigUpdateSummary(req))
 	switch strings.ToLower(*req.Channel) {
 	case "telegram":
-		if req.BotToken == nil && req.ChatID == nil && req.IsActive == nil {
+		if req.BotToken == nil && req.ChatID == nil && req.Prefix == nil && req.Suffix == nil && req.IsActive == nil {
 			return fmt.Errorf("at least one field must be provided")
 		}
-		return s.updateTelegramConfig(ctx, userAddress, req.Name, req.BotToken, req.ChatID, req.IsActive)
+		return s.updateTelegramConfig(ctx, userAddress, req.Name, req.BotToken, req.ChatID, req.Prefix, req.Suffix, req.IsActive)
 	case "lark":
-		if req.WebhookURL == nil && req.Secret == nil && req.IsActive == nil {
+		if req.WebhookURL == nil && req.Secret == nil && req.Prefix == nil && req.Suffix == nil && req.IsActive == nil {
 			return fmt.Errorf("at least one field must be provided")
 		}
-		return s.updateLarkConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret, req.IsActive)
+		return s.updateLarkConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret, req.Prefix, req.Suffix, req.IsActive)
 	case "feishu":
-		if req.WebhookURL == nil && req.Secret == nil && req.IsActive == nil {
+		if req.WebhookURL == nil && req.Secret == nil && req.Prefix == nil && req.Suffix == nil && req.IsActive == nil {
 			return fmt.Errorf("at least one field must be provided")
 		}
-		return s.updateFeishuConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret, req.IsActive)
+		return s.updateFeishuConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Secret, req.Prefix, req.Suffix, req.IsActive)
 	case "discord":
-		if req.WebhookURL == nil && req.IsActive == nil {
+		if req.WebhookURL == nil && req.Prefix == nil && req.Suffix == nil && req.IsActive == nil {
 			return fmt.Errorf("at least one field must be provided")
 		}
-		return s.updateDiscordConfig(ctx, userAddress, req.Name, req.WebhookURL, req.IsActive)
+		return s.updateDiscordConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Prefix, req.Suffix, req.IsActive)
 	case "slack":
-		if req.WebhookURL == nil && req.IsActive == nil {
+		if req.WebhookURL == nil && req.Prefix == nil && req.Suffix == nil && req.IsActive == nil {
 			return fmt.Errorf("at least one field must be provided")
 		}
-		return s.updateSlackConfig(ctx, userAddress, req.Name, req.WebhookURL, req.IsActive)
+		return s.updateSlackConfig(ctx, userAddress, req.Name, req.WebhookURL, req.Prefix, req.Suffix, req.IsActive)
+	case "matrix":
+		if req.HomeserverURL == nil && req.AccessToken == nil && req.RoomID == nil && req.Prefix == nil && req.Suffix == nil && req.IsActive == nil {
+			return fmt.Errorf("at least one field must be provided")
+		}
+		return s.updateMatrixConfig(ctx, userAddress, req.Name, req.HomeserverURL, req.AccessToken, req.RoomID, req.Prefix, req.Suffix, req.IsActive)
 	}
 	return fmt.Errorf("invalid channel: %s", *req.Channel)
 }
 
 // DeleteNotificationConfig 删除通知配置
 func (s *notificationService) DeleteNotificationConfig(ctx context.Context, userAddress string, req *types.DeleteNotificationRequest) error {
+	ctx = audit.WithLog(ctx, types.AuditActionDelete, types.AuditTargetNotificationConfig,
+		notificationConfigAuditTarget(req.Channel, req.Name), fmt.Sprintf("deleted %s config", strings.ToLower(req.Channel)))
 	switch strings.ToLower(req.Channel) {
 	case "telegram":
 		return s.deleteTelegramConfig(ctx, userAddress, req.Name)
@@ -166,13 +249,15 @@ func (s *notificationService) DeleteNotificationConfig(ctx context.Context, user
 		return s.deleteDiscordConfig(ctx, userAddress, req.Name)
 	case "slack":
 		return s.deleteSlackConfig(ctx, userAddress, req.Name)
+	case "matrix":
+		return s.deleteMatrixConfig(ctx, userAddress, req.Name)
 	}
 	return fmt.Errorf("invalid channel: %s", req.Channel)
 }
 
 // ===== 创建配置 =====
 // createTelegramConfig 创建Telegram配置
-func (s *notificationService) createTelegramConfig(ctx context.Context, userAddress string, name string, botToken string, chatID string) error {
+func (s *notificationService) createTelegramConfig(ctx context.Context, userAddress string, name string, botToken string, chatID string, prefix string, suffix string) error {
 	// 检查是否已存在同名配置
 	existing, err := s.repo.GetTelegramConfigByUserAddressAndName(ctx, userAddress, name)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -188,6 +273,8 @@ func (s *notificationService) createTelegramConfig(ctx context.Context, userAddr
 		BotToken:    botToken,
 		ChatID:      chatID,
 		IsActive:    true,
+		Prefix:      prefix,
+		Suffix:      suffix,
 	}
 
 	if err := s.repo.CreateTelegramConfig(ctx, config); err != nil {
@@ -198,7 +285,7 @@ func (s *notificationService) createTelegramConfig(ctx context.Context, userAddr
 }
 
 // createLarkConfig 创建Lark配置
-func (s *notificationService) createLarkConfig(ctx context.Context, userAddress string, name string, webhookURL string, secret string) error {
+func (s *notificationService) createLarkConfig(ctx context.Context, userAddress string, name string, webhookURL string, secret string, prefix string, suffix string) error {
 	// 检查是否已存在同名配置
 	existing, err := s.repo.GetLarkConfigByUserAddressAndName(ctx, userAddress, name)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -214,6 +301,8 @@ func (s *notificationService) createLarkConfig(ctx context.Context, userAddress
 		WebhookURL:  webhookURL,
 		Secret:      secret,
 		IsActive:    true,
+		Prefix:      prefix,
+		Suffix:      suffix,
 	}
 
 	if err := s.repo.CreateLarkConfig(ctx, config); err != nil {
@@ -224,7 +313,7 @@ func (s *notificationService) createLarkConfig(ctx context.Context, userAddress
 }
 
 // createFeishuConfig 创建Feishu配置
-func (s *notificationService) createFeishuConfig(ctx context.Context, userAddress string, name string, webhookURL string, secret string) error {
+func (s *notificationService) createFeishuConfig(ctx context.Context, userAddress string, name string, webhookURL string, secret string, prefix string, suffix string) error {
 	// 检查是否已存在同名配置
 	existing, err := s.repo.GetFeishuConfigByUserAddressAndName(ctx, userAddress, name)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -240,6 +329,8 @@ func (s *notificationService) createFeishuConfig(ctx context.Context, userAddres
 		WebhookURL:  webhookURL,
 		Secret:      secret,
 		IsActive:    true,
+		Prefix:      prefix,
+		Suffix:      suffix,
 	}
 
 	if err := s.repo.CreateFeishuConfig(ctx, config); err != nil {
@@ -250,7 +341,7 @@ func (s *notificationService) createFeishuConfig(ctx context.Context, userAddres
 }
 
 // createDiscordConfig 创建Discord配置
-func (s *notificationService) createDiscordConfig(ctx context.Context, userAddress string, name string, webhookURL string) error {
+func (s *notificationService) createDiscordConfig(ctx context.Context, userAddress string, name string, webhookURL string, prefix string, suffix string) error {
 	// 检查是否已存在同名配置
 	existing, err := s.repo.GetDiscordConfigByUserAddressAndName(ctx, userAddress, name)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -265,6 +356,8 @@ func (s *notificationService) createDiscordConfig(ctx context.Context, userAddre
 		Name:        name,
 		WebhookURL:  webhookURL,
 		IsActive:    true,
+		Prefix:      prefix,
+		Suffix:      suffix,
 	}
 
 	if err := s.repo.CreateDiscordConfig(ctx, config); err != nil {
@@ -275,7 +368,7 @@ func (s *notificationService) createDiscordConfig(ctx context.Context, userAddre
 }
 
 // createSlackConfig 创建Slack配置
-func (s *notificationService) createSlackConfig(ctx context.Context, userAddress string, name string, webhookURL string) error {
+func (s *notificationService) createSlackConfig(ctx context.Context, userAddress string, name string, webhookURL string, prefix string, suffix string) error {
 	// 检查是否已存在同名配置
 	existing, err := s.repo.GetSlackConfigByUserAddressAndName(ctx, userAddress, name)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -290,6 +383,8 @@ func (s *notificationService) createSlackConfig(ctx context.Context, userAddress
 		Name:        name,
 		WebhookURL:  webhookURL,
 		IsActive:    true,
+		Prefix:      prefix,
+		Suffix:      suffix,
 	}
 
 	if err := s.repo.CreateSlackConfig(ctx, config); err != nil {
@@ -299,9 +394,38 @@ func (s *notificationService) createSlackConfig(ctx context.Context, userAddress
 	return nil
 }
 
+// createMatrixConfig 创建Matrix配置
+func (s *notificationService) createMatrixConfig(ctx context.Context, userAddress string, name string, homeserverURL string, accessToken string, roomID string, prefix string, suffix string) error {
+	// 检查是否已存在同名配置
+	existing, err := s.repo.GetMatrixConfigByUserAddressAndName(ctx, userAddress, name)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to check existing matrix config: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("matrix config with name '%s' already exists", name)
+	}
+
+	config := &types.MatrixConfig{
+		UserAddress:   userAddress,
+		Name:          name,
+		HomeserverURL: homeserverURL,
+		AccessToken:   accessToken,
+		RoomID:        roomID,
+		IsActive:      true,
+		Prefix:        prefix,
+		Suffix:        suffix,
+	}
+
+	if err := s.repo.CreateMatrixConfig(ctx, config); err != nil {
+		return fmt.Errorf("failed to create matrix config: %w", err)
+	}
+
+	return nil
+}
+
 // ===== 更新配置 =====
 // updateTelegramConfig 更新Telegram配置
-func (s *notificationService) updateTelegramConfig(ctx context.Context, userAddress string, name *string, botToken *string, chatID *string, isActive *bool) error {
+func (s *notificationService) updateTelegramConfig(ctx context.Context, userAddress string, name *string, botToken *string, chatID *string, prefix *string, suffix *string, isActive *bool) error {
 	// 检查配置是否存在
 	_, err := s.repo.GetTelegramConfigByUserAddressAndName(ctx, userAddress, *name)
 	if err != nil {
@@ -319,6 +443,12 @@ func (s *notificationService) updateTelegramConfig(ctx context.Context, userAddr
 	if chatID != nil {
 		updates["chat_id"] = *chatID
 	}
+	if prefix != nil {
+		updates["prefix"] = *prefix
+	}
+	if suffix != nil {
+		updates["suffix"] = *suffix
+	}
 	if isActive != nil {
 		updates["is_active"] = *isActive
 	}
@@ -331,7 +461,7 @@ func (s *notificationService) updateTelegramConfig(ctx context.Context, userAddr
 }
 
 // updateLarkConfig 更新Lark配置
-func (s *notificationService) updateLarkConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, secret *string, isActive *bool) error {
+func (s *notificationService) updateLarkConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, secret *string, prefix *string, suffix *string, isActive *bool) error {
 	// 检查配置是否存在
 	_, err := s.repo.GetLarkConfigByUserAddressAndName(ctx, userAddress, *name)
 	if err != nil {
@@ -349,6 +479,12 @@ func (s *notificationService) updateLarkConfig(ctx context.Context, userAddress
 	if secret != nil {
 		updates["secret"] = *secret
 	}
+	if prefix != nil {
+		updates["prefix"] = *prefix
+	}
+	if suffix != nil {
+		updates["suffix"] = *suffix
+	}
 	if isActive != nil {
 		updates["is_active"] = *isActive
 	}
@@ -361,7 +497,7 @@ func (s *notificationService) updateLarkConfig(ctx context.Context, userAddress
 }
 
 // updateFeishuConfig 更新Feishu配置
-func (s *notificationService) updateFeishuConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, secret *string, isActive *bool) error {
+func (s *notificationService) updateFeishuConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, secret *string, prefix *string, suffix *string, isActive *bool) error {
 	// 检查配置是否存在
 	_, err := s.repo.GetFeishuConfigByUserAddressAndName(ctx, userAddress, *name)
 	if err != nil {
@@ -379,6 +515,12 @@ func (s *notificationService) updateFeishuConfig(ctx context.Context, userAddres
 	if secret != nil {
 		updates["secret"] = *secret
 	}
+	if prefix != nil {
+		updates["prefix"] = *prefix
+	}
+	if suffix != nil {
+		updates["suffix"] = *suffix
+	}
 	if isActive != nil {
 		updates["is_active"] = *isActive
 	}
@@ -391,7 +533,7 @@ func (s *notificationService) updateFeishuConfig(ctx context.Context, userAddres
 }
 
 // updateDiscordConfig 更新Discord配置
-func (s *notificationService) updateDiscordConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, isActive *bool) error {
+func (s *notificationService) updateDiscordConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, prefix *string, suffix *string, isActive *bool) error {
 	// 检查配置是否存在
 	_, err := s.repo.GetDiscordConfigByUserAddressAndName(ctx, userAddress, *name)
 	if err != nil {
@@ -406,6 +548,12 @@ func (s *notificationService) updateDiscordConfig(ctx context.Context, userAddre
 	if webhookURL != nil {
 		updates["webhook_url"] = *webhookURL
 	}
+	if prefix != nil {
+		updates["prefix"] = *prefix
+	}
+	if suffix != nil {
+		updates["suffix"] = *suffix
+	}
 	if isActive != nil {
 		updates["is_active"] = *isActive
 	}
@@ -418,7 +566,7 @@ func (s *notificationService) updateDiscordConfig(ctx context.Context, userAddre
 }
 
 // updateSlackConfig 更新Slack配置
-func (s *notificationService) updateSlackConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, isActive *bool) error {
+func (s *notificationService) updateSlackConfig(ctx context.Context, userAddress string, name *string, webhookURL *string, prefix *string, suffix *string, isActive *bool) error {
 	// 检查配置是否存在
 	_, err := s.repo.GetSlackConfigByUserAddressAndName(ctx, userAddress, *name)
 	if err != nil {
@@ -433,6 +581,12 @@ func (s *notificationService) updateSlackConfig(ctx context.Context, userAddress
 	if webhookURL != nil {
 		updates["webhook_url"] = *webhookURL
 	}
+	if prefix != nil {
+		updates["prefix"] = *prefix
+	}
+	if suffix != nil {
+		updates["suffix"] = *suffix
+	}
 	if isActive != nil {
 		updates["is_active"] = *isActive
 	}
@@ -444,6 +598,45 @@ func (s *notificationService) updateSlackConfig(ctx context.Context, userAddress
 	return s.repo.UpdateSlackConfig(ctx, userAddress, *name, updates)
 }
 
+// updateMatrixConfig 更新Matrix配置
+func (s *notificationService) updateMatrixConfig(ctx context.Context, userAddress string, name *string, homeserverURL *string, accessToken *string, roomID *string, prefix *string, suffix *string, isActive *bool) error {
+	// 检查配置是否存在
+	_, err := s.repo.GetMatrixConfigByUserAddressAndName(ctx, userAddress, *name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("matrix config not found")
+		}
+		return fmt.Errorf("failed to get matrix config: %w", err)
+	}
+
+	// 构建更新字段
+	updates := make(map[string]interface{})
+	if homeserverURL != nil {
+		updates["homeserver_url"] = *homeserverURL
+	}
+	if accessToken != nil {
+		updates["access_token"] = *accessToken
+	}
+	if roomID != nil {
+		updates["room_id"] = *roomID
+	}
+	if prefix != nil {
+		updates["prefix"] = *prefix
+	}
+	if suffix != nil {
+		updates["suffix"] = *suffix
+	}
+	if isActive != nil {
+		updates["is_active"] = *isActive
+	}
+
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	return s.repo.UpdateMatrixConfig(ctx, userAddress, *name, updates)
+}
+
 // ===== 删除配置 =====
 // deleteTelegramConfig 删除Telegram配置
 func (s *notificationService) deleteTelegramConfig(ctx context.Context, userAddress string, name string) error {
@@ -515,9 +708,35 @@ func (s *notificationService) deleteSlackConfig(ctx context.Context, userAddress
 	return s.repo.DeleteSlackConfig(ctx, userAddress, name)
 }
 
+// deleteMatrixConfig 删除Matrix配置
+func (s *notificationService) deleteMatrixConfig(ctx context.Context, userAddress string, name string) error {
+	// 检查配置是否存在
+	_, err := s.repo.GetMatrixConfigByUserAddressAndName(ctx, userAddress, name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("matrix config not found")
+		}
+		return fmt.Errorf("failed to get matrix config: %w", err)
+	}
+
+	return s.repo.DeleteMatrixConfig(ctx, userAddress, name)
+}
+
 // ===== 获取所有通知配置 =====
-// GetAllNotificationConfigs 获取所有通知配置
-func (s *notificationService) GetAllNotificationConfigs(ctx context.Context, userAddress string) (*types.NotificationConfigListResponse, error) {
+// GetAllNotificationConfigs 获取所有通知配置，reveal=false 时 bot_token/webhook_url/secret/access_token 只保留末尾 4 位
+func (s *notificationService) GetAllNotificationConfigs(ctx context.Context, userAddress string, reveal bool) (*types.NotificationConfigListResponse, error) {
+	response, err := s.listNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return nil, err
+	}
+	if reveal {
+		return response, nil
+	}
+	return redactNotificationConfigs(response), nil
+}
+
+// listNotificationConfigs 读取所有通知配置原始数据（含敏感字段明文），仅供服务内部使用
+func (s *notificationService) listNotificationConfigs(ctx context.Context, userAddress string) (*types.NotificationConfigListResponse, error) {
 	response := &types.NotificationConfigListResponse{}
 
 	// 获取Telegram配置
@@ -555,6 +774,13 @@ func (s *notificationService) GetAllNotificationConfigs(ctx context.Context, use
 	}
 	response.SlackConfigs = slackConfigs
 
+	// 获取Matrix配置
+	matrixConfigs, err := s.repo.GetMatrixConfigsByUserAddress(ctx, userAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matrix configs: %w", err)
+	}
+	response.MatrixConfigs = matrixConfigs
+
 	return response, nil
 }
 
@@ -575,12 +801,142 @@ func (s *notificationService) SendFlowNotification(ctx context.Context, standard
 
 	logger.Info("Found related users for notification", "count", len(userAddresses), "standard", standard, "chainID", chainID, "contract", contractAddress)
 
+	notificationData, err := s.buildNotificationData(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, initiatorAddress)
+	if err != nil {
+		return err
+	}
+	if notificationData == nil {
+		return nil
+	}
+
+	messages, err := s.generateChannelMessages(ctx, notificationData)
+	if err != nil {
+		logger.Error("Failed to generate notification message", err, "flowID", flowID)
+		return nil // 不阻塞流程，只记录错误
+	}
+
+	// 对每个相关用户并发发送通知（用户间并发，同用户内各渠道顺序发送）
+	start := time.Now()
+	var totalSent int64
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(8)
+	for _, ua := range userAddresses {
+		userAddress := ua
+		g.Go(func() error {
+			configs, err := s.repo.GetUserActiveNotificationConfigs(gctx, userAddress)
+			if err != nil {
+				logger.Error("Failed to get user notification configs", err, "userAddress", userAddress)
+				return nil
+			}
+			s.dropDisabledChannels(gctx, userAddress, configs)
+			totalConfigs := len(configs.TelegramConfigs) + len(configs.LarkConfigs) + len(configs.FeishuConfigs) + len(configs.DiscordConfigs) + len(configs.SlackConfigs) + len(configs.MatrixConfigs)
+			if totalConfigs == 0 {
+				return nil
+			}
+
+			for _, config := range configs.TelegramConfigs {
+				s.sendTelegramNotification(gctx, config, messages[types.ChannelTelegram], flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
+			}
+			for _, config := range configs.LarkConfigs {
+				s.sendLarkNotification(gctx, config, messages[types.ChannelLark], flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
+			}
+			for _, config := range configs.FeishuConfigs {
+				s.sendFeishuNotification(gctx, config, messages[types.ChannelFeishu], flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
+			}
+			for _, config := range configs.DiscordConfigs {
+				s.sendDiscordNotification(gctx, config, messages[types.ChannelDiscord], flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
+			}
+			for _, config := range configs.SlackConfigs {
+				s.sendSlackNotification(gctx, config, messages[types.ChannelSlack], flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
+			}
+			for _, config := range configs.MatrixConfigs {
+				s.sendMatrixNotification(gctx, config, messages[types.ChannelMatrix], flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
+			}
+
+			atomic.AddInt64(&totalSent, int64(totalConfigs))
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	logger.Info("Notification sending completed",
+		"totalUsers", len(userAddresses),
+		"totalNotificationsSent", atomic.LoadInt64(&totalSent),
+		"elapsed_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// PreviewFlowNotification 按与实际发送相同的流程渲染通知消息，并按 userAddress 的激活配置生成各配置会收到的消息
+func (s *notificationService) PreviewFlowNotification(ctx context.Context, userAddress string, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string) (*types.PreviewFlowNotificationResponse, error) {
+	notificationData, err := s.buildNotificationData(ctx, standard, chainID, contractAddress, flowID, statusFrom, statusTo, txHash, "")
+	if err != nil {
+		return nil, err
+	}
+	if notificationData == nil {
+		return nil, nil
+	}
+
+	message, err := s.generateNotificationMessage(ctx, notificationData, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate message: %w", err)
+	}
+	messages, err := s.generateChannelMessages(ctx, notificationData)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与 SendFlowNotification 相同：读取激活配置并排除关闭的渠道类型
+	configs, err := s.repo.GetUserActiveNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user notification configs: %w", err)
+	}
+	s.dropDisabledChannels(ctx, userAddress, configs)
+
+	return &types.PreviewFlowNotificationResponse{
+		Message:         message,
+		ChannelMessages: messages,
+		Configs:         previewConfigEntries(configs, messages),
+	}, nil
+}
+
+// previewConfigEntries 按发送顺序生成各配置会收到的消息
+func previewConfigEntries(configs *types.UserNotificationConfigs, messages map[types.NotificationChannel]string) []types.PreviewNotificationConfigEntry {
+	entries := make([]types.PreviewNotificationConfigEntry, 0)
+	add := func(channel types.NotificationChannel, name, prefix, suffix string) {
+		entries = append(entries, types.PreviewNotificationConfigEntry{Channel: channel, Name: name, Message: configMessage(channel, messages[channel], prefix, suffix)})
+	}
+	for _, c := range configs.TelegramConfigs {
+		add(types.ChannelTelegram, c.Name, c.Prefix, c.Suffix)
+	}
+	for _, c := range configs.LarkConfigs {
+		add(types.ChannelLark, c.Name, c.Prefix, c.Suffix)
+	}
+	for _, c := range configs.FeishuConfigs {
+		add(types.ChannelFeishu, c.Name, c.Prefix, c.Suffix)
+	}
+	for _, c := range configs.DiscordConfigs {
+		add(types.ChannelDiscord, c.Name, c.Prefix, c.Suffix)
+	}
+	for _, c := range configs.SlackConfigs {
+		add(types.ChannelSlack, c.Name, c.Prefix, c.Suffix)
+	}
+	for _, c := range configs.MatrixConfigs {
+		add(types.ChannelMatrix, c.Name, c.Prefix, c.Suffix)
+	}
+	return entries
+}
+
+// buildNotificationData 构建流程状态变更的通知数据，流程不存在时返回 nil
+func (s *notificationService) buildNotificationData(ctx context.Context, standard string, chainID int, contractAddress string, flowID string, statusFrom, statusTo string, txHash *string, initiatorAddress string) (*types.NotificationData, error) {
 	var notificationData *types.NotificationData
+	var canceller string // 取消交易的发起地址，仅在流程被取消时展示
 	// 获取链信息
+	// 链名称与浏览器链接只用于展示，查询失败时以链ID代替并继续发送
 	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(chainID))
-	if err != nil {
-		logger.Error("Failed to get chain info", err, "chainID", chainID)
-		return fmt.Errorf("failed to get chain info: %w", err)
+	if err != nil || chainInfo == nil {
+		logger.Error("Failed to get chain info, falling back to chain id", err, "chainID", chainID)
+		chainInfo = types.FallbackSupportChain(chainID)
 	}
 
 	// 解析区块浏览器URLs
@@ -594,7 +950,7 @@ func (s *notificationService) SendFlowNotification(ctx context.Context, standard
 	var txLink string
 	var txDisplay string
 	if txHash != nil && len(explorerURLs) > 0 {
-		txLink = fmt.Sprintf("%s/tx/%s", explorerURLs[0], *txHash)
+		txLink = utils.ExplorerTxURL(explorerURLs, *txHash)
 		// 简化显示的交易哈希（前10位...后6位）
 		if len(*txHash) > 10 {
 			txDisplay = fmt.Sprintf("%s...%s", (*txHash)[:10], (*txHash)[len(*txHash)-6:])
@@ -611,18 +967,18 @@ func (s *notificationService) SendFlowNotification(ctx context.Context, standard
 		compoundTimeLock, err := s.timelockRepo.GetCompoundTimeLockByChainAndAddress(ctx, chainID, contractAddress)
 		if err != nil {
 			logger.Error("Failed to get compound time lock", err, "chainID", chainID, "contractAddress", contractAddress)
-			return fmt.Errorf("failed to get compound timelock: %w", err)
+			return nil, fmt.Errorf("failed to get compound timelock: %w", err)
 		}
 
 		// 从 Goldsky Flow 表中获取 Flow 信息
 		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, flowID, chainID, contractAddress)
 		if err != nil {
 			logger.Error("Failed to get compound flow", err, "flowID", flowID, "chainID", chainID, "contractAddress", contractAddress)
-			return fmt.Errorf("failed to get compound flow: %w", err)
+			return nil, fmt.Errorf("failed to get compound flow: %w", err)
 		}
 		if flow == nil {
 			logger.Warn("No compound flow found", "flowID", flowID, "chainID", chainID, "contractAddress", contractAddress)
-			return nil
+			return nil, nil
 		}
 
 		var functionName string
@@ -638,6 +994,9 @@ func (s *notificationService) SendFlowNotification(ctx context.Context, standard
 		} else {
 			caller = "Unknown"
 		}
+		if flow.CancellerAddress != nil {
+			canceller = *flow.CancellerAddress
+		}
 
 		// 获取 target
 		if flow.TargetAddress != nil {
@@ -676,6 +1035,7 @@ func (s *notificationService) SendFlowNotification(ctx context.Context, standard
 			Standard:       strings.ToUpper(standard),
 			Contract:       contractAddress,
 			Remark:         compoundTimeLock.Remark,
+			Nickname:       compoundTimeLock.Nickname,
 			Caller:         caller,
 			Target:         target,
 			Function:       functionName,
@@ -683,79 +1043,94 @@ func (s *notificationService) SendFlowNotification(ctx context.Context, standard
 			CalldataParams: calldataParams,
 		}
 	} else if standard == "openzeppelin" {
-		// 拿合约信息
-		// 通过flowID去交易表中拿到交易信息
-		// 解析calldata(由于OZevent中直接是calldata带函数选择器，需要先识别functionSig，然后解析calldata)
-		// （functionSig可以新建一个functionSig表，用于存储functionSig和functionName的映射，计算用户导入的abi里的函数，然后存储到functionSig表中）
-		// 构建emailData
+		ozTimeLock, err := s.timelockRepo.GetOpenzeppelinTimeLockByChainAndAddress(ctx, chainID, contractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin time lock", err, "chainID", chainID, "contractAddress", contractAddress)
+			return nil, fmt.Errorf("failed to get openzeppelin timelock: %w", err)
+		}
+
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin flow", err, "flowID", flowID, "chainID", chainID, "contractAddress", contractAddress)
+			return nil, fmt.Errorf("failed to get openzeppelin flow: %w", err)
+		}
+		if flow == nil {
+			logger.Warn("No openzeppelin flow found", "flowID", flowID, "chainID", chainID, "contractAddress", contractAddress)
+			return nil, nil
+		}
+
+		caller := "Unknown"
+		if flow.InitiatorAddress != nil {
+			caller = *flow.InitiatorAddress
+		} else if initiatorAddress != "" {
+			caller = initiatorAddress
+		}
+		if flow.CancellerAddress != nil {
+			canceller = *flow.CancellerAddress
+		}
+
+		notificationData = &types.NotificationData{
+			Standard: strings.ToUpper(standard),
+			Contract: contractAddress,
+			Remark:   ozTimeLock.Remark,
+			Nickname: ozTimeLock.Nickname,
+			Caller:   caller,
+		}
+		calls, err := s.flowRepo.GetOpenzeppelinFlowCalls(ctx, flowID, chainID, contractAddress)
+		if err != nil {
+			logger.Error("Failed to get openzeppelin flow calls", err, "flowID", flowID, "chainID", chainID)
+		}
+		utils.FillOpenzeppelinCallsNotificationData(notificationData, flow, calls, chainInfo.NativeCurrencySymbol)
+		if notificationData.BlockedBy, err = s.flowRepo.GetOpenzeppelinBlockingPredecessor(ctx, flow); err != nil {
+			logger.Error("Failed to check openzeppelin flow predecessor", err, "flowID", flowID, "chainID", chainID)
+		}
 	} else {
-		return fmt.Errorf("invalid standard")
+		return nil, fmt.Errorf("invalid standard")
 	}
 
 	notificationData.StatusFrom = strings.ToUpper(statusFrom)
 	notificationData.StatusTo = strings.ToUpper(statusTo)
+	if strings.EqualFold(statusTo, "cancelled") {
+		notificationData.Canceller = canceller
+	}
 	notificationData.Network = chainInfo.DisplayName
 	notificationData.TxHash = txDisplay
 	notificationData.TxUrl = txLink
 	notificationData.DashboardUrl = s.config.Email.EmailURL
-
-	// 生成通知消息
-	message, err := s.generateNotificationMessage(ctx, notificationData)
-	if err != nil {
-		logger.Error("Failed to generate notification message", err, "flowID", flowID)
-		return nil // 不阻塞流程，只记录错误
+	utils.FillExplorerLinks(notificationData, explorerURLs)
+	utils.FillNotificationSeverity(notificationData)
+	if notificationData.SelfTargeting {
+		logger.Warn("Self-targeting timelock flow detected, sending critical notification",
+			"standard", standard, "chainID", chainID, "contractAddress", contractAddress, "flowID", flowID, "statusTo", statusTo)
 	}
 
-	// 对每个相关用户并发发送通知（用户间并发，同用户内各渠道顺序发送）
-	start := time.Now()
-	var totalSent int64
-	g, gctx := errgroup.WithContext(ctx)
-	g.SetLimit(8)
-	for _, ua := range userAddresses {
-		userAddress := ua
-		g.Go(func() error {
-			configs, err := s.repo.GetUserActiveNotificationConfigs(gctx, userAddress)
-			if err != nil {
-				logger.Error("Failed to get user notification configs", err, "userAddress", userAddress)
-				return nil
-			}
-			totalConfigs := len(configs.TelegramConfigs) + len(configs.LarkConfigs) + len(configs.FeishuConfigs) + len(configs.DiscordConfigs) + len(configs.SlackConfigs)
-			if totalConfigs == 0 {
-				return nil
-			}
-
-			for _, config := range configs.TelegramConfigs {
-				s.sendTelegramNotification(gctx, config, message, flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
-			}
-			for _, config := range configs.LarkConfigs {
-				s.sendLarkNotification(gctx, config, message, flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
-			}
-			for _, config := range configs.FeishuConfigs {
-				s.sendFeishuNotification(gctx, config, message, flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
-			}
-			for _, config := range configs.DiscordConfigs {
-				s.sendDiscordNotification(gctx, config, message, flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
-			}
-			for _, config := range configs.SlackConfigs {
-				s.sendSlackNotification(gctx, config, message, flowID, standard, chainID, contractAddress, statusFrom, statusTo, txHash)
-			}
+	return notificationData, nil
+}
 
-			atomic.AddInt64(&totalSent, int64(totalConfigs))
-			return nil
-		})
+// generateChannelMessages 按各渠道长度上限分别生成通知消息
+// 长度上限先扣除之后才会加上的内容（模拟发送标记、配置前后缀的最大长度），保证最终消息不超过渠道上限
+func (s *notificationService) generateChannelMessages(ctx context.Context, notificationData *types.NotificationData) (map[types.NotificationChannel]string, error) {
+	simulated := SimulationFromContext(ctx) != nil
+	messages := make(map[types.NotificationChannel]string, 6)
+	for _, channel := range []types.NotificationChannel{types.ChannelTelegram, types.ChannelLark, types.ChannelFeishu, types.ChannelDiscord, types.ChannelSlack, types.ChannelMatrix} {
+		maxLen := s.channelMessageLimit(channel) - maxAffixOverhead(channel)
+		if simulated {
+			maxLen -= channelMessageLength(channel, SimulatedMessageMarker+"\n")
+		}
+		message, err := s.generateNotificationMessage(ctx, notificationData, channel, maxLen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s message: %w", channel, err)
+		}
+		if simulated {
+			message = SimulatedMessageMarker + "\n" + message
+		}
+		messages[channel] = message
 	}
-	_ = g.Wait()
-
-	logger.Info("Notification sending completed",
-		"totalUsers", len(userAddresses),
-		"totalNotificationsSent", atomic.LoadInt64(&totalSent),
-		"elapsed_ms", time.Since(start).Milliseconds(),
-	)
-	return nil
+	return messages, nil
 }
 
-// generateNotificationMessage 生成通知消息
-func (s *notificationService) generateNotificationMessage(ctx context.Context, notificationData *types.NotificationData) (string, error) {
+// generateNotificationMessage 生成通知消息，maxLen > 0 时超长部分会截断calldata参数列表（长度按 channel 的计数方式计算）
+func (s *notificationService) generateNotificationMessage(ctx context.Context, notificationData *types.NotificationData, channel types.NotificationChannel, maxLen int) (string, error) {
 
 	// 获取状态表情符号
 	getStatusEmoji := func(status string) string {
@@ -777,31 +1152,133 @@ func (s *notificationService) generateNotificationMessage(ctx context.Context, n
 
 	// 构建简约消息
 	message := fmt.Sprintf("━━━━━━━━━━━━━━━━\n")
-	message += fmt.Sprintf("⚡ Timelock Notification\n")
+	if notificationData.Severity == types.NotificationSeverityCritical {
+		// 以 timelock 自身为目标的治理变更（如 setDelay、setPendingAdmin）使用醒目标题
+		message += fmt.Sprintf("🚨 CRITICAL: Timelock Self-Admin Change\n")
+	} else {
+		message += fmt.Sprintf("⚡ Timelock Notification\n")
+	}
 	message += fmt.Sprintf("━━━━━━━━━━━━━━━━\n")
 	message += fmt.Sprintf("[%s] %s    ➡️    [%s] %s\n", strings.ToUpper(notificationData.StatusFrom), getStatusEmoji(notificationData.StatusFrom), strings.ToUpper(notificationData.StatusTo), getStatusEmoji(notificationData.StatusTo))
 	message += fmt.Sprintf("🔗 Chain    : %s\n", notificationData.Network)
 	message += fmt.Sprintf("📄 Contract : %s\n", notificationData.Contract)
 	message += fmt.Sprintf("⚙️ Standard : %s\n", strings.ToUpper(notificationData.Standard))
-	message += fmt.Sprintf("💬 Remark   : %s\n", notificationData.Remark)
+	if notificationData.Nickname != "" {
+		message += fmt.Sprintf("🏷️ Nickname : %s\n", notificationData.Nickname)
+	} else {
+		message += fmt.Sprintf("💬 Remark   : %s\n", notificationData.Remark)
+	}
 	message += fmt.Sprintf("👤 Caller   : %s\n", notificationData.Caller)
+	if notificationData.Canceller != "" {
+		message += fmt.Sprintf("🛑 Cancelled By : %s\n", notificationData.Canceller)
+	}
 	message += fmt.Sprintf("🎯 Target   : %s\n", notificationData.Target)
 	message += fmt.Sprintf("💰 Value    : %s\n", notificationData.Value)
 	message += fmt.Sprintf("🔍 Function : %s\n", notificationData.Function)
+	if notificationData.BlockedBy != "" {
+		message += fmt.Sprintf("⛓️ Blocked By : %s (predecessor not executed)\n", notificationData.BlockedBy)
+	}
+
+	footer := fmt.Sprintf("🔍 Tx Hash  : %s\n", notificationData.TxHash)
+	footer += fmt.Sprintf("🔗 Tx URL  : %s\n", notificationData.TxUrl)
+	if notificationData.ContractUrl != "" {
+		footer += fmt.Sprintf("📄 Contract URL : %s\n", notificationData.ContractUrl)
+	}
+	if notificationData.TargetUrl != "" {
+		footer += fmt.Sprintf("🎯 Target URL : %s\n", notificationData.TargetUrl)
+	}
+
+	paramLines := make([]string, 0, len(notificationData.CalldataParams))
 	for _, param := range notificationData.CalldataParams {
-		message += fmt.Sprintf("    🔒 %s(%s) : %s\n", param.Name, param.Type, param.Value)
+		paramLines = append(paramLines, fmt.Sprintf("    🔒 %s(%s) : %s\n", param.Name, param.Type, param.Value))
+	}
+	// 批量操作：逐个列出子调用
+	for _, call := range notificationData.Calls {
+		paramLines = append(paramLines, fmt.Sprintf("  📦 Call #%d : %s → %s (%s)\n", call.Index, call.Function, call.Target, call.Value))
+		for _, param := range call.CalldataParams {
+			paramLines = append(paramLines, fmt.Sprintf("    🔒 %s(%s) : %s\n", param.Name, param.Type, param.Value))
+		}
+	}
+	if maxLen > 0 {
+		message += truncateParamLines(channel, paramLines, maxLen-channelMessageLength(channel, message)-channelMessageLength(channel, footer), notificationData.DashboardUrl)
+	} else {
+		message += strings.Join(paramLines, "")
 	}
-	message += fmt.Sprintf("🔍 Tx Hash  : %s\n", notificationData.TxHash)
-	message += fmt.Sprintf("🔗 Tx URL  : %s\n", notificationData.TxUrl)
+	message += footer
 
 	logger.Info("Generated notification message", "statusFrom", notificationData.StatusFrom, "statusTo", notificationData.StatusTo, "txHash", notificationData.TxHash)
 	return message, nil
 }
 
+// 各渠道单条消息的默认长度上限（Telegram 为 UTF-16 码元数，其他渠道为字符数，预留少量余量）
+var defaultChannelMessageLimits = map[types.NotificationChannel]int{
+	types.ChannelTelegram: 4000,  // Telegram 上限 4096 个 UTF-16 码元
+	types.ChannelDiscord:  1900,  // Discord content 上限 2000
+	types.ChannelSlack:    3900,  // Slack 单个 text 块建议 4000 以内
+	types.ChannelLark:     20000, // Lark/飞书 文本消息上限较大
+	types.ChannelFeishu:   20000,
+	types.ChannelMatrix:   20000, // Matrix 单个事件上限 64KB
+}
+
+// channelMessageLimit 获取渠道消息长度上限，配置优先
+func (s *notificationService) channelMessageLimit(channel types.NotificationChannel) int {
+	if s.config != nil {
+		if limit, ok := s.config.Notification.MessageMaxLength[string(channel)]; ok && limit > 0 {
+			return limit
+		}
+	}
+	return defaultChannelMessageLimits[channel]
+}
+
+// channelMessageLength 按渠道的计数方式计算消息长度：Telegram 按 UTF-16 码元计数（emoji 等补充平面字符占 2），其他渠道按字符计数
+func channelMessageLength(channel types.NotificationChannel, s string) int {
+	if channel != types.ChannelTelegram {
+		return utf8.RuneCountInString(s)
+	}
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// truncateParamLines 在 budget 长度内尽量保留参数行，放不下的部分以 "… N more params" 标记替代并附带详情链接
+func truncateParamLines(channel types.NotificationChannel, lines []string, budget int, detailURL string) string {
+	total := 0
+	for _, line := range lines {
+		total += channelMessageLength(channel, line)
+	}
+	if total <= budget || len(lines) == 0 {
+		return strings.Join(lines, "")
+	}
+
+	marker := func(remaining int) string {
+		m := fmt.Sprintf("    … %d more params", remaining)
+		if detailURL != "" {
+			m += fmt.Sprintf(", full details: %s", detailURL)
+		}
+		return m + "\n"
+	}
+
+	var b strings.Builder
+	used := 0
+	for i, line := range lines {
+		lineLen := channelMessageLength(channel, line)
+		// 保证放入当前行后仍有空间放下剩余参数的标记
+		if used+lineLen+channelMessageLength(channel, marker(len(lines)-i-1)) > budget {
+			b.WriteString(marker(len(lines) - i))
+			return b.String()
+		}
+		b.WriteString(line)
+		used += lineLen
+	}
+	return b.String()
+}
+
 // sendTelegramNotification 发送Telegram通知
 func (s *notificationService) sendTelegramNotification(ctx context.Context, config *types.TelegramConfig, message, flowID, standard string, chainID int, contractAddress, statusFrom, statusTo string, txHash *string) {
 	// 检查是否已发送过此通知
-	exists, err := s.repo.CheckNotificationLogExists(ctx, types.ChannelTelegram, config.UserAddress, config.ID, flowID, statusTo)
+	exists, err := s.notificationLogExists(ctx, types.ChannelTelegram, config.UserAddress, config.ID, flowID, statusTo)
 	if err != nil {
 		logger.Error("Failed to check telegram notification log", err, "configID", config.ID, "flowID", flowID)
 		return
@@ -812,7 +1289,7 @@ func (s *notificationService) sendTelegramNotification(ctx context.Context, conf
 	}
 
 	// 发送消息
-	err = s.telegramSender.SendMessage(config.BotToken, config.ChatID, message)
+	providerMessageID, err := s.telegramSender.SendMessage(ctx, config.BotToken, config.ChatID, configMessage(types.ChannelTelegram, message, config.Prefix, config.Suffix))
 	sendStatus := "success"
 	var errorMessage *string
 	if err != nil {
@@ -839,7 +1316,8 @@ func (s *notificationService) sendTelegramNotification(ctx context.Context, conf
 			}
 			return ""
 		}(),
-		SendStatus: sendStatus,
+		SendStatus:        sendStatus,
+		ProviderMessageID: providerMessageID,
 		ErrorMessage: func() string {
 			if errorMessage != nil {
 				return *errorMessage
@@ -849,19 +1327,19 @@ func (s *notificationService) sendTelegramNotification(ctx context.Context, conf
 		SentAt: time.Now(),
 	}
 
-	if err := s.repo.CreateNotificationLog(ctx, log); err != nil {
+	if err := s.createNotificationLog(ctx, log); err != nil {
 		logger.Error("Failed to create telegram notification log", err, "configID", config.ID, "flowID", flowID)
 	}
 
 	if sendStatus == "success" {
-		logger.Info("Telegram notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo)
+		logger.Info("Telegram notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo, "providerMessageID", providerMessageID)
 	}
 }
 
 // sendLarkNotification 发送Lark通知
 func (s *notificationService) sendLarkNotification(ctx context.Context, config *types.LarkConfig, message, flowID, standard string, chainID int, contractAddress, statusFrom, statusTo string, txHash *string) {
 	// 检查是否已发送过此通知
-	exists, err := s.repo.CheckNotificationLogExists(ctx, types.ChannelLark, config.UserAddress, config.ID, flowID, statusTo)
+	exists, err := s.notificationLogExists(ctx, types.ChannelLark, config.UserAddress, config.ID, flowID, statusTo)
 	if err != nil {
 		logger.Error("Failed to check lark notification log", err, "configID", config.ID, "flowID", flowID)
 		return
@@ -872,7 +1350,7 @@ func (s *notificationService) sendLarkNotification(ctx context.Context, config *
 	}
 
 	// 发送消息
-	err = s.larkSender.SendMessage(config.WebhookURL, config.Secret, message)
+	providerMessageID, err := s.larkSender.SendMessage(ctx, config.WebhookURL, config.Secret, configMessage(types.ChannelLark, message, config.Prefix, config.Suffix))
 	sendStatus := "success"
 	var errorMessage *string
 	if err != nil {
@@ -899,7 +1377,8 @@ func (s *notificationService) sendLarkNotification(ctx context.Context, config *
 			}
 			return ""
 		}(),
-		SendStatus: sendStatus,
+		SendStatus:        sendStatus,
+		ProviderMessageID: providerMessageID,
 		ErrorMessage: func() string {
 			if errorMessage != nil {
 				return *errorMessage
@@ -909,19 +1388,19 @@ func (s *notificationService) sendLarkNotification(ctx context.Context, config *
 		SentAt: time.Now(),
 	}
 
-	if err := s.repo.CreateNotificationLog(ctx, log); err != nil {
+	if err := s.createNotificationLog(ctx, log); err != nil {
 		logger.Error("Failed to create lark notification log", err, "configID", config.ID, "flowID", flowID)
 	}
 
 	if sendStatus == "success" {
-		logger.Info("Lark notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo)
+		logger.Info("Lark notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo, "providerMessageID", providerMessageID)
 	}
 }
 
 // sendFeishuNotification 发送Feishu通知
 func (s *notificationService) sendFeishuNotification(ctx context.Context, config *types.FeishuConfig, message, flowID, standard string, chainID int, contractAddress, statusFrom, statusTo string, txHash *string) {
 	// 检查是否已发送过此通知
-	exists, err := s.repo.CheckNotificationLogExists(ctx, types.ChannelFeishu, config.UserAddress, config.ID, flowID, statusTo)
+	exists, err := s.notificationLogExists(ctx, types.ChannelFeishu, config.UserAddress, config.ID, flowID, statusTo)
 	if err != nil {
 		logger.Error("Failed to check feishu notification log", err, "configID", config.ID, "flowID", flowID)
 		return
@@ -932,7 +1411,7 @@ func (s *notificationService) sendFeishuNotification(ctx context.Context, config
 	}
 
 	// 发送消息
-	err = s.feishuSender.SendMessage(config.WebhookURL, config.Secret, message)
+	providerMessageID, err := s.feishuSender.SendMessage(ctx, config.WebhookURL, config.Secret, configMessage(types.ChannelFeishu, message, config.Prefix, config.Suffix))
 	sendStatus := "success"
 	var errorMessage *string
 	if err != nil {
@@ -959,7 +1438,8 @@ func (s *notificationService) sendFeishuNotification(ctx context.Context, config
 			}
 			return ""
 		}(),
-		SendStatus: sendStatus,
+		SendStatus:        sendStatus,
+		ProviderMessageID: providerMessageID,
 		ErrorMessage: func() string {
 			if errorMessage != nil {
 				return *errorMessage
@@ -969,19 +1449,19 @@ func (s *notificationService) sendFeishuNotification(ctx context.Context, config
 		SentAt: time.Now(),
 	}
 
-	if err := s.repo.CreateNotificationLog(ctx, log); err != nil {
+	if err := s.createNotificationLog(ctx, log); err != nil {
 		logger.Error("Failed to create feishu notification log", err, "configID", config.ID, "flowID", flowID)
 	}
 
 	if sendStatus == "success" {
-		logger.Info("Feishu notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo)
+		logger.Info("Feishu notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo, "providerMessageID", providerMessageID)
 	}
 }
 
 // sendDiscordNotification 发送Discord通知
 func (s *notificationService) sendDiscordNotification(ctx context.Context, config *types.DiscordConfig, message, flowID, standard string, chainID int, contractAddress, statusFrom, statusTo string, txHash *string) {
 	// 检查是否已发送过此通知
-	exists, err := s.repo.CheckNotificationLogExists(ctx, types.ChannelDiscord, config.UserAddress, config.ID, flowID, statusTo)
+	exists, err := s.notificationLogExists(ctx, types.ChannelDiscord, config.UserAddress, config.ID, flowID, statusTo)
 	if err != nil {
 		logger.Error("Failed to check discord notification log", err, "configID", config.ID, "flowID", flowID)
 		return
@@ -992,7 +1472,7 @@ func (s *notificationService) sendDiscordNotification(ctx context.Context, confi
 	}
 
 	// 发送消息
-	err = s.discordSender.SendMessage(config.WebhookURL, message)
+	providerMessageID, err := s.discordSender.SendMessage(ctx, config.WebhookURL, configMessage(types.ChannelDiscord, message, config.Prefix, config.Suffix))
 	sendStatus := "success"
 	var errorMessage *string
 	if err != nil {
@@ -1019,7 +1499,8 @@ func (s *notificationService) sendDiscordNotification(ctx context.Context, confi
 			}
 			return ""
 		}(),
-		SendStatus: sendStatus,
+		SendStatus:        sendStatus,
+		ProviderMessageID: providerMessageID,
 		ErrorMessage: func() string {
 			if errorMessage != nil {
 				return *errorMessage
@@ -1029,19 +1510,19 @@ func (s *notificationService) sendDiscordNotification(ctx context.Context, confi
 		SentAt: time.Now(),
 	}
 
-	if err := s.repo.CreateNotificationLog(ctx, log); err != nil {
+	if err := s.createNotificationLog(ctx, log); err != nil {
 		logger.Error("Failed to create discord notification log", err, "configID", config.ID, "flowID", flowID)
 	}
 
 	if sendStatus == "success" {
-		logger.Info("Discord notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo)
+		logger.Info("Discord notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo, "providerMessageID", providerMessageID)
 	}
 }
 
 // sendSlackNotification 发送Slack通知
 func (s *notificationService) sendSlackNotification(ctx context.Context, config *types.SlackConfig, message, flowID, standard string, chainID int, contractAddress, statusFrom, statusTo string, txHash *string) {
 	// 检查是否已发送过此通知
-	exists, err := s.repo.CheckNotificationLogExists(ctx, types.ChannelSlack, config.UserAddress, config.ID, flowID, statusTo)
+	exists, err := s.notificationLogExists(ctx, types.ChannelSlack, config.UserAddress, config.ID, flowID, statusTo)
 	if err != nil {
 		logger.Error("Failed to check slack notification log", err, "configID", config.ID, "flowID", flowID)
 		return
@@ -1052,7 +1533,7 @@ func (s *notificationService) sendSlackNotification(ctx context.Context, config
 	}
 
 	// 发送消息
-	err = s.slackSender.SendMessage(config.WebhookURL, message)
+	providerMessageID, err := s.slackSender.SendMessage(ctx, config.WebhookURL, configMessage(types.ChannelSlack, message, config.Prefix, config.Suffix))
 	sendStatus := "success"
 	var errorMessage *string
 	if err != nil {
@@ -1079,7 +1560,8 @@ func (s *notificationService) sendSlackNotification(ctx context.Context, config
 			}
 			return ""
 		}(),
-		SendStatus: sendStatus,
+		SendStatus:        sendStatus,
+		ProviderMessageID: providerMessageID,
 		ErrorMessage: func() string {
 			if errorMessage != nil {
 				return *errorMessage
@@ -1089,11 +1571,77 @@ func (s *notificationService) sendSlackNotification(ctx context.Context, config
 		SentAt: time.Now(),
 	}
 
-	if err := s.repo.CreateNotificationLog(ctx, log); err != nil {
+	if err := s.createNotificationLog(ctx, log); err != nil {
 		logger.Error("Failed to create slack notification log", err, "configID", config.ID, "flowID", flowID)
 	}
 
 	if sendStatus == "success" {
-		logger.Info("Slack notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo)
+		logger.Info("Slack notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo, "providerMessageID", providerMessageID)
+	}
+}
+
+// sendMatrixNotification 发送Matrix通知
+func (s *notificationService) sendMatrixNotification(ctx context.Context, config *types.MatrixConfig, message, flowID, standard string, chainID int, contractAddress, statusFrom, statusTo string, txHash *string) {
+	// 检查是否已发送过此通知
+	exists, err := s.notificationLogExists(ctx, types.ChannelMatrix, config.UserAddress, config.ID, flowID, statusTo)
+	if err != nil {
+		logger.Error("Failed to check matrix notification log", err, "configID", config.ID, "flowID", flowID)
+		return
+	}
+	if exists {
+		logger.Info("Matrix notification already sent", "configID", config.ID, "flowID", flowID, "status", statusTo)
+		return
+	}
+
+	// 发送消息
+	providerMessageID, err := s.matrixSender.SendMessage(ctx, config.HomeserverURL, config.AccessToken, config.RoomID, configMessage(types.ChannelMatrix, message, config.Prefix, config.Suffix))
+	sendStatus := "success"
+	var errorMessage *string
+	if err != nil {
+		sendStatus = "failed"
+		errMsg := err.Error()
+		errorMessage = &errMsg
+		if errors.Is(err, notificationPkg.ErrMatrixTokenInvalid) {
+			// token 失效需要用户重新登录获取，单独记录便于排查
+			logger.Warn("Matrix access token invalid, user needs to re-authenticate", "configID", config.ID, "userAddress", config.UserAddress, "flowID", flowID)
+		} else {
+			logger.Error("Failed to send matrix notification", err, "configID", config.ID, "flowID", flowID)
+		}
+	}
+
+	// 记录发送日志
+	log := &types.NotificationLog{
+		UserAddress:      config.UserAddress,
+		Channel:          types.ChannelMatrix,
+		ConfigID:         config.ID,
+		FlowID:           flowID,
+		TimelockStandard: standard,
+		ChainID:          chainID,
+		ContractAddress:  contractAddress,
+		StatusFrom:       statusFrom,
+		StatusTo:         statusTo,
+		TxHash: func() string {
+			if txHash != nil {
+				return *txHash
+			}
+			return ""
+		}(),
+		SendStatus:        sendStatus,
+		ProviderMessageID: providerMessageID,
+		ErrorMessage: func() string {
+			if errorMessage != nil {
+				return *errorMessage
+			}
+			return ""
+		}(),
+		SentAt: time.Now(),
+	}
+
+	if err := s.createNotificationLog(ctx, log); err != nil {
+		logger.Error("Failed to create matrix notification log", err, "configID", config.ID, "flowID", flowID)
+	}
+
+	if sendStatus == "success" {
+		logger.Info("Matrix notification sent", "configID", config.ID, "flowID", flowID, "status", statusTo, "providerMessageID", providerMessageID)
 	}
 }
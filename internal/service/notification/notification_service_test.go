@@ -0,0 +1,289 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"timelocker-backend/internal/config"
+	chainRepo "timelocker-backend/internal/repository/chain"
+	goldskyRepo "timelocker-backend/internal/repository/goldsky"
+	"timelocker-backend/internal/repository/notification"
+	timelockRepo "timelocker-backend/internal/repository/timelock"
+	"timelocker-backend/internal/types"
+	notificationPkg "timelocker-backend/pkg/notification"
+	"timelocker-backend/pkg/utils"
+)
+
+const (
+	testUserAddress     = "0x1111111111111111111111111111111111111111"
+	testContractAddress = "0x2222222222222222222222222222222222222222"
+	testFlowID          = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+// fakeSendRepo 提供一个相关用户及其激活配置，不记录发送日志
+type fakeSendRepo struct {
+	notification.NotificationRepository
+	configs  *types.UserNotificationConfigs
+	settings []types.NotificationChannelSetting
+}
+
+func (r *fakeSendRepo) GetContractRelatedUserAddresses(ctx context.Context, standard string, chainID int, contractAddress string) ([]string, error) {
+	return []string{testUserAddress}, nil
+}
+
+// GetUserActiveNotificationConfigs 每次返回新的副本，避免 dropDisabledChannels 修改共享数据
+func (r *fakeSendRepo) GetUserActiveNotificationConfigs(ctx context.Context, userAddress string) (*types.UserNotificationConfigs, error) {
+	copied := *r.configs
+	return &copied, nil
+}
+
+func (r *fakeSendRepo) GetNotificationChannelSettings(ctx context.Context, userAddress string) ([]types.NotificationChannelSetting, error) {
+	return r.settings, nil
+}
+
+func (r *fakeSendRepo) CheckNotificationLogExists(ctx context.Context, channel types.NotificationChannel, userAddress string, configID uint, flowID, statusTo string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeSendRepo) CreateNotificationLog(ctx context.Context, log *types.NotificationLog) error {
+	return nil
+}
+
+type fakeSendChainRepo struct{ chainRepo.Repository }
+
+func (fakeSendChainRepo) GetChainByChainID(ctx context.Context, chainID int64) (*types.SupportChain, error) {
+	return &types.SupportChain{ChainID: chainID, DisplayName: "Ethereum", NativeCurrencySymbol: "ETH", BlockExplorerUrls: `["https://etherscan.io"]`}, nil
+}
+
+type fakeSendTimelockRepo struct{ timelockRepo.Repository }
+
+func (fakeSendTimelockRepo) GetCompoundTimeLockByChainAndAddress(ctx context.Context, chainID int, contractAddress string) (*types.CompoundTimeLock, error) {
+	return &types.CompoundTimeLock{Remark: "Treasury & <ops>"}, nil
+}
+
+type fakeSendFlowRepo struct{ goldskyRepo.FlowRepository }
+
+func (fakeSendFlowRepo) GetCompoundFlowByID(ctx context.Context, flowID string, chainID int, contractAddress string) (*types.CompoundTimelockFlowDB, error) {
+	initiator := testUserAddress
+	target := testContractAddress
+	return &types.CompoundTimelockFlowDB{FlowID: flowID, Status: "waiting", InitiatorAddress: &initiator, TargetAddress: &target, Value: "0"}, nil
+}
+
+// capturingWebhook 记录按 webhook 路径收到的消息正文（Discord content / Slack text）
+type capturingWebhook struct {
+	mu       sync.Mutex
+	received map[string]string
+}
+
+func (w *capturingWebhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Content string `json:"content"`
+		Text    string `json:"text"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	w.mu.Lock()
+	w.received[r.URL.Path] = body.Content + body.Text
+	w.mu.Unlock()
+	rw.WriteHeader(http.StatusOK)
+}
+
+// TestPreviewFlowNotificationMatchesSent 预览返回的各配置消息与 SendFlowNotification 实际发出的内容一致
+func TestPreviewFlowNotificationMatchesSent(t *testing.T) {
+	webhook := &capturingWebhook{received: map[string]string{}}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+	if err := utils.SetOutboundAllowlist([]string{"127.0.0.1"}); err != nil {
+		t.Fatalf("SetOutboundAllowlist: %v", err)
+	}
+	defer utils.SetOutboundAllowlist(nil)
+
+	repo := &fakeSendRepo{
+		configs: &types.UserNotificationConfigs{
+			DiscordConfigs: []*types.DiscordConfig{
+				{ID: 1, UserAddress: testUserAddress, Name: "prod", WebhookURL: server.URL + "/discord/prod", Prefix: "[PROD]", Suffix: "-- ops"},
+				{ID: 2, UserAddress: testUserAddress, Name: "plain", WebhookURL: server.URL + "/discord/plain"},
+			},
+			SlackConfigs: []*types.SlackConfig{
+				{ID: 3, UserAddress: testUserAddress, Name: "team", WebhookURL: server.URL + "/slack/team", Prefix: "<team>"},
+			},
+			LarkConfigs: []*types.LarkConfig{
+				{ID: 4, UserAddress: testUserAddress, Name: "muted", WebhookURL: server.URL + "/lark/muted"},
+			},
+		},
+		settings: []types.NotificationChannelSetting{{UserAddress: testUserAddress, Channel: types.ChannelLark, Enabled: false}},
+	}
+	s := &notificationService{
+		repo:          repo,
+		chainRepo:     fakeSendChainRepo{},
+		timelockRepo:  fakeSendTimelockRepo{},
+		flowRepo:      fakeSendFlowRepo{},
+		config:        &config.Config{},
+		discordSender: notificationPkg.NewDiscordSender(0),
+		slackSender:   notificationPkg.NewSlackSender(0),
+	}
+
+	ctx := context.Background()
+	preview, err := s.PreviewFlowNotification(ctx, testUserAddress, "compound", 1, testContractAddress, testFlowID, "", "waiting", nil)
+	if err != nil {
+		t.Fatalf("PreviewFlowNotification: %v", err)
+	}
+	if err := s.SendFlowNotification(ctx, "compound", 1, testContractAddress, testFlowID, "", "waiting", nil, testUserAddress); err != nil {
+		t.Fatalf("SendFlowNotification: %v", err)
+	}
+
+	paths := map[string]string{"prod": "/discord/prod", "plain": "/discord/plain", "team": "/slack/team"}
+	if len(preview.Configs) != len(paths) {
+		t.Fatalf("preview has %d configs, want %d (disabled lark channel excluded): %+v", len(preview.Configs), len(paths), preview.Configs)
+	}
+	webhook.mu.Lock()
+	defer webhook.mu.Unlock()
+	if len(webhook.received) != len(paths) {
+		t.Fatalf("sent to %d webhooks, want %d: %v", len(webhook.received), len(paths), webhook.received)
+	}
+	for _, entry := range preview.Configs {
+		sent, ok := webhook.received[paths[entry.Name]]
+		if !ok {
+			t.Fatalf("config %q was not sent", entry.Name)
+		}
+		if sent != entry.Message {
+			t.Fatalf("config %q: preview\n%q\ndiffers from sent\n%q", entry.Name, entry.Message, sent)
+		}
+	}
+
+	// 前后缀只出现在对应配置的消息中
+	for _, entry := range preview.Configs {
+		if entry.Name == "plain" && entry.Message != preview.ChannelMessages[types.ChannelDiscord] {
+			t.Fatalf("config without prefix/suffix should get the channel message unchanged")
+		}
+	}
+}
+
+func TestConfigMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel types.NotificationChannel
+		prefix  string
+		suffix  string
+		want    string
+	}{
+		{"no affix", types.ChannelDiscord, "", "", "body\n"},
+		{"prefix and suffix", types.ChannelSlack, "[PROD]", "-- ops", "[PROD]\nbody\n-- ops\n"},
+		{"telegram escapes affix", types.ChannelTelegram, "<b>", "a&b", "&lt;b&gt;\nbody\na&amp;b\n"},
+		{"other channels keep affix as is", types.ChannelMatrix, "<b>", "", "<b>\nbody\n"},
+		{"whitespace only affix ignored", types.ChannelLark, "  ", " ", "body\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := configMessage(tt.channel, "body\n", tt.prefix, tt.suffix); got != tt.want {
+				t.Fatalf("configMessage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChannelMessageLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel types.NotificationChannel
+		text    string
+		want    int
+	}{
+		{"ascii", types.ChannelTelegram, "abc", 3},
+		{"bmp characters count once", types.ChannelTelegram, "━⚡中", 3},
+		{"supplementary emoji counts twice on telegram", types.ChannelTelegram, "🧪🔒", 4},
+		{"variation selector is its own unit", types.ChannelTelegram, "⚙️", 2},
+		{"other channels count runes", types.ChannelDiscord, "🧪🔒", 2},
+		{"empty", types.ChannelSlack, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := channelMessageLength(tt.channel, tt.text); got != tt.want {
+				t.Fatalf("channelMessageLength(%s, %q) = %d, want %d", tt.channel, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateParamLinesUTF16Budget(t *testing.T) {
+	// 每行 9 个字符、10 个 UTF-16 码元（含一个 emoji），5 行共 45 个字符、50 个码元
+	line := "🔒 abcdef\n"
+	lines := []string{line, line, line, line, line}
+	all := strings.Repeat(line, len(lines))
+	marker := func(n int) string { return fmt.Sprintf("    … %d more params\n", n) }
+
+	tests := []struct {
+		name    string
+		channel types.NotificationChannel
+		budget  int
+		want    string
+	}{
+		{"all lines fit by runes", types.ChannelDiscord, 45, all},
+		{"all lines fit exactly in utf-16", types.ChannelTelegram, 50, all},
+		{"one unit short truncates", types.ChannelTelegram, 49, line + line + marker(3)},
+		{"rune count would fit but utf-16 does not", types.ChannelTelegram, 45, line + line + marker(3)},
+		{"budget for marker only", types.ChannelTelegram, 20, marker(5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateParamLines(tt.channel, lines, tt.budget, "")
+			if got != tt.want {
+				t.Fatalf("truncateParamLines = %q, want %q", got, tt.want)
+			}
+			if tt.want != all && channelMessageLength(tt.channel, got) > tt.budget {
+				t.Fatalf("truncated output length %d exceeds budget %d", channelMessageLength(tt.channel, got), tt.budget)
+			}
+		})
+	}
+}
+
+// TestGenerateChannelMessagesFitsLimitAfterAffixAndMarker 最长的前后缀与模拟标记加上后仍不超过渠道上限
+func TestGenerateChannelMessagesFitsLimitAfterAffixAndMarker(t *testing.T) {
+	params := make([]types.CalldataParam, 1000)
+	for i := range params {
+		params[i] = types.CalldataParam{Name: fmt.Sprintf("p%d", i), Type: "bytes32", Value: "🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒🔒"}
+	}
+	data := &types.NotificationData{
+		StatusFrom:     "waiting",
+		StatusTo:       "ready",
+		Network:        "Ethereum",
+		Contract:       testContractAddress,
+		Standard:       "compound",
+		TxHash:         testFlowID,
+		CalldataParams: params,
+	}
+	affix := strings.Repeat("&", maxMessageAffixLength)
+	emojiAffix := strings.Repeat("🧪", maxMessageAffixLength)
+
+	s := &notificationService{}
+	for _, simulated := range []bool{false, true} {
+		ctx := context.Background()
+		if simulated {
+			ctx, _ = WithSimulation(ctx)
+		}
+		messages, err := s.generateChannelMessages(ctx, data)
+		if err != nil {
+			t.Fatalf("generateChannelMessages: %v", err)
+		}
+		for channel, message := range messages {
+			if simulated && !strings.HasPrefix(message, SimulatedMessageMarker+"\n") {
+				t.Fatalf("%s: simulated message missing marker", channel)
+			}
+			limit := defaultChannelMessageLimits[channel]
+			for _, a := range []string{affix, emojiAffix} {
+				final := configMessage(channel, message, a, a)
+				if got := channelMessageLength(channel, final); got > limit {
+					t.Fatalf("%s (simulated=%v): final message length %d exceeds limit %d", channel, simulated, got, limit)
+				}
+			}
+			if !strings.Contains(message, "more params") {
+				t.Fatalf("%s: expected params to be truncated", channel)
+			}
+		}
+	}
+}
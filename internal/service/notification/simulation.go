@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"timelocker-backend/internal/types"
+)
+
+// SimulatedMessageMarker 模拟发送时加在渠道消息开头的标记
+const SimulatedMessageMarker = "🧪 [SIMULATED] Test notification triggered by an admin, no on-chain state has changed"
+
+type simulationContextKey struct{}
+
+// SimulationSandbox 模拟发送的独立去重命名空间，只存在于单次模拟请求内：
+// 去重检查与发送记录都落在沙箱里，不读写真实的 notification_logs / email_send_logs
+type SimulationSandbox struct {
+	mu         sync.Mutex
+	sent       map[string]struct{}
+	deliveries []types.SimulatedNotificationDelivery
+}
+
+// WithSimulation 把 ctx 标记为模拟发送并挂上新的沙箱
+func WithSimulation(ctx context.Context) (context.Context, *SimulationSandbox) {
+	sandbox := &SimulationSandbox{sent: make(map[string]struct{})}
+	return context.WithValue(ctx, simulationContextKey{}, sandbox), sandbox
+}
+
+// SimulationFromContext 获取 ctx 上的模拟沙箱，非模拟发送时返回 nil
+func SimulationFromContext(ctx context.Context) *SimulationSandbox {
+	sandbox, _ := ctx.Value(simulationContextKey{}).(*SimulationSandbox)
+	return sandbox
+}
+
+// Exists 检查沙箱内是否已发送过（与真实去重使用相同的维度）
+func (sb *SimulationSandbox) Exists(channel string, target interface{}, flowID, statusTo string) bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	_, ok := sb.sent[simulationKey(channel, target, flowID, statusTo)]
+	return ok
+}
+
+// Record 在沙箱内记录一次发送结果
+func (sb *SimulationSandbox) Record(channel string, target interface{}, flowID, statusTo, sendStatus, errorMessage string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.sent[simulationKey(channel, target, flowID, statusTo)] = struct{}{}
+	sb.deliveries = append(sb.deliveries, types.SimulatedNotificationDelivery{
+		Channel:      channel,
+		Target:       fmt.Sprint(target),
+		SendStatus:   sendStatus,
+		ErrorMessage: errorMessage,
+	})
+}
+
+// Deliveries 返回沙箱内记录的全部发送结果
+func (sb *SimulationSandbox) Deliveries() []types.SimulatedNotificationDelivery {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	deliveries := make([]types.SimulatedNotificationDelivery, len(sb.deliveries))
+	copy(deliveries, sb.deliveries)
+	return deliveries
+}
+
+func simulationKey(channel string, target interface{}, flowID, statusTo string) string {
+	return fmt.Sprintf("%s:%v:%s:%s", channel, target, flowID, statusTo)
+}
+
+// notificationLogExists 检查渠道通知是否已发送；模拟发送时只在沙箱内去重
+func (s *notificationService) notificationLogExists(ctx context.Context, channel types.NotificationChannel, userAddress string, configID uint, flowID, statusTo string) (bool, error) {
+	if sandbox := SimulationFromContext(ctx); sandbox != nil {
+		return sandbox.Exists(string(channel), fmt.Sprintf("%s/%d", userAddress, configID), flowID, statusTo), nil
+	}
+	return s.repo.CheckNotificationLogExists(ctx, channel, userAddress, configID, flowID, statusTo)
+}
+
+// createNotificationLog 记录渠道通知发送日志；模拟发送时只记录到沙箱
+func (s *notificationService) createNotificationLog(ctx context.Context, log *types.NotificationLog) error {
+	if sandbox := SimulationFromContext(ctx); sandbox != nil {
+		sandbox.Record(string(log.Channel), fmt.Sprintf("%s/%d", log.UserAddress, log.ConfigID), log.FlowID, log.StatusTo, log.SendStatus, log.ErrorMessage)
+		return nil
+	}
+	return s.repo.CreateNotificationLog(ctx, log)
+}
@@ -0,0 +1,161 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/types"
+	notificationPkg "timelocker-backend/pkg/notification"
+	"timelocker-backend/pkg/utils"
+)
+
+// loggedSendRepo 模拟真实日志中已有该状态的发送记录，并统计对日志表的读写次数
+type loggedSendRepo struct {
+	*fakeSendRepo
+	mu      sync.Mutex
+	checks  int
+	creates int
+}
+
+func (r *loggedSendRepo) CheckNotificationLogExists(ctx context.Context, channel types.NotificationChannel, userAddress string, configID uint, flowID, statusTo string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks++
+	return true, nil
+}
+
+func (r *loggedSendRepo) CreateNotificationLog(ctx context.Context, log *types.NotificationLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.creates++
+	return nil
+}
+
+// countingWebhook 统计收到的消息并保留最后一条正文
+type countingWebhook struct {
+	capturingWebhook
+	count int
+}
+
+func (w *countingWebhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.capturingWebhook.ServeHTTP(rw, r)
+	w.mu.Lock()
+	w.count++
+	w.mu.Unlock()
+}
+
+func (w *countingWebhook) snapshot() (int, string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count, w.received["/discord/prod"]
+}
+
+// TestSimulationSandboxIsolation 模拟发送不读写真实发送日志：真实日志中已发送的状态仍会模拟发出，
+// 同一沙箱内去重，新的沙箱互不影响，而真实发送仍按日志去重
+func TestSimulationSandboxIsolation(t *testing.T) {
+	webhook := &countingWebhook{capturingWebhook: capturingWebhook{received: map[string]string{}}}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+	if err := utils.SetOutboundAllowlist([]string{"127.0.0.1"}); err != nil {
+		t.Fatalf("SetOutboundAllowlist: %v", err)
+	}
+	defer utils.SetOutboundAllowlist(nil)
+
+	repo := &loggedSendRepo{fakeSendRepo: &fakeSendRepo{
+		configs: &types.UserNotificationConfigs{
+			DiscordConfigs: []*types.DiscordConfig{{ID: 1, UserAddress: testUserAddress, Name: "prod", WebhookURL: server.URL + "/discord/prod"}},
+		},
+	}}
+	s := &notificationService{
+		repo:          repo,
+		chainRepo:     fakeSendChainRepo{},
+		timelockRepo:  fakeSendTimelockRepo{},
+		flowRepo:      fakeSendFlowRepo{},
+		config:        &config.Config{},
+		discordSender: notificationPkg.NewDiscordSender(0),
+	}
+	send := func(ctx context.Context) {
+		t.Helper()
+		if err := s.SendFlowNotification(ctx, "compound", 1, testContractAddress, testFlowID, "", "waiting", nil, testUserAddress); err != nil {
+			t.Fatalf("SendFlowNotification: %v", err)
+		}
+	}
+
+	simCtx, sandbox := WithSimulation(context.Background())
+	send(simCtx)
+	count, message := webhook.snapshot()
+	if count != 1 {
+		t.Fatalf("simulation sent %d messages, want 1 despite the real log entry", count)
+	}
+	if !strings.HasPrefix(message, SimulatedMessageMarker+"\n") {
+		t.Fatalf("simulated message %q does not start with the marker", message)
+	}
+	deliveries := sandbox.Deliveries()
+	if len(deliveries) != 1 || deliveries[0].Channel != string(types.ChannelDiscord) || deliveries[0].Target != testUserAddress+"/1" || deliveries[0].SendStatus != "success" {
+		t.Fatalf("sandbox deliveries = %+v, want one successful discord delivery", deliveries)
+	}
+
+	// 同一沙箱内重复模拟按沙箱去重
+	send(simCtx)
+	if count, _ := webhook.snapshot(); count != 1 {
+		t.Fatalf("repeated simulation in the same sandbox sent %d messages, want 1", count)
+	}
+
+	// 新沙箱不受上一次模拟影响
+	otherCtx, other := WithSimulation(context.Background())
+	send(otherCtx)
+	if count, _ := webhook.snapshot(); count != 2 {
+		t.Fatalf("simulation in a new sandbox sent %d messages in total, want 2", count)
+	}
+	if len(other.Deliveries()) != 1 || len(sandbox.Deliveries()) != 1 {
+		t.Fatalf("sandboxes share deliveries: %d / %d", len(sandbox.Deliveries()), len(other.Deliveries()))
+	}
+
+	repo.mu.Lock()
+	checks, creates := repo.checks, repo.creates
+	repo.mu.Unlock()
+	if checks != 0 || creates != 0 {
+		t.Fatalf("simulation touched notification_logs: %d checks, %d creates", checks, creates)
+	}
+
+	// 真实发送读取真实日志，已发送的状态不会重复发出
+	send(context.Background())
+	if count, _ := webhook.snapshot(); count != 2 {
+		t.Fatalf("real send ignored the existing log entry: %d messages in total", count)
+	}
+	if repo.checks != 1 {
+		t.Fatalf("real send checked notification_logs %d times, want 1", repo.checks)
+	}
+}
+
+func TestSimulationFromContext(t *testing.T) {
+	if SimulationFromContext(context.Background()) != nil {
+		t.Fatal("plain context reported as simulation")
+	}
+	ctx, sandbox := WithSimulation(context.Background())
+	if SimulationFromContext(ctx) != sandbox {
+		t.Fatal("simulation context does not return its sandbox")
+	}
+
+	sandbox.Record("email", 7, testFlowID, "ready", "failed", "smtp down")
+	if !sandbox.Exists("email", 7, testFlowID, "ready") {
+		t.Fatal("recorded delivery not found")
+	}
+	for _, key := range [][3]string{{"discord", testFlowID, "ready"}, {"email", "0xother", "ready"}, {"email", testFlowID, "executed"}} {
+		if sandbox.Exists(key[0], 7, key[1], key[2]) {
+			t.Fatalf("unexpected match for %v", key)
+		}
+	}
+
+	// Deliveries 返回副本
+	deliveries := sandbox.Deliveries()
+	deliveries[0].SendStatus = "changed"
+	if got := sandbox.Deliveries()[0]; got.SendStatus != "failed" || got.Target != "7" || got.ErrorMessage != "smtp down" {
+		t.Fatalf("sandbox delivery = %+v, want the recorded failure unchanged", got)
+	}
+}
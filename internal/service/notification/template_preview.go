@@ -0,0 +1,350 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"timelocker-backend/internal/types"
+)
+
+// maxTemplateOutputBytes 模板渲染结果上限，超出时视为渲染错误
+const maxTemplateOutputBytes = 64 * 1024
+
+// previewTemplateName 试渲染模板的名称，出现在错误位置信息中
+const previewTemplateName = "preview"
+
+// maxTemplateRangeDepth range 最大嵌套层数（.Calls 内再 range .CalldataParams 为两层），
+// 限制迭代次数为各层数据长度之积
+const maxTemplateRangeDepth = 2
+
+// maxSampleSliceLen 示例数据中 Calls 及每组 CalldataParams 的最大长度
+const maxSampleSliceLen = 50
+
+// templateRenderTimeout 模板渲染超时时间
+const templateRenderTimeout = 2 * time.Second
+
+var (
+	// ErrTemplateFlowNotFound 试渲染指定的流程不存在
+	ErrTemplateFlowNotFound = errors.New("flow not found")
+	// ErrTemplateFlowAccessDenied 用户与试渲染指定的流程无关
+	ErrTemplateFlowAccessDenied = errors.New("flow access denied")
+	// ErrTemplateSampleTooLarge 示例数据的 calls 或 calldata_params 超过长度上限
+	ErrTemplateSampleTooLarge = fmt.Errorf("sample calls and calldata_params are limited to %d items", maxSampleSliceLen)
+
+	errTemplateOutputTooLarge = fmt.Errorf("rendered output exceeds %d bytes", maxTemplateOutputBytes)
+	errTemplateRenderTimeout  = fmt.Errorf("rendering exceeded %s", templateRenderTimeout)
+)
+
+// templateFuncs 模板可用的自定义函数（内置函数中 call 被禁用）
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+// templateLocationPattern 解析 text/template 错误信息中的位置，如 "template: preview:3:12: ..."
+var templateLocationPattern = regexp.MustCompile(`(?s)^template: [^:]+:(\d+)(?::(\d+))?: (.*)$`)
+
+// PreviewNotificationTemplate 校验并试渲染通知消息模板，模板本身的错误在响应中返回（valid=false），不作为错误
+// 指定 flow 时使用真实流程数据渲染，流程不存在或与用户无关时返回 ErrTemplateFlowNotFound / ErrTemplateFlowAccessDenied
+func (s *notificationService) PreviewNotificationTemplate(ctx context.Context, userAddress string, req *types.PreviewNotificationTemplateRequest) (*types.PreviewNotificationTemplateResponse, error) {
+	tmpl, templateErr := parseNotificationTemplate(req.Template)
+	if templateErr != nil {
+		return &types.PreviewNotificationTemplateResponse{Error: templateErr}, nil
+	}
+
+	data := sampleNotificationData()
+	switch {
+	case req.Flow != nil:
+		flowData, err := s.templateFlowData(ctx, userAddress, req)
+		if err != nil {
+			return nil, err
+		}
+		data = flowData
+	case req.Sample != nil:
+		if !sampleWithinLimits(req.Sample) {
+			return nil, ErrTemplateSampleTooLarge
+		}
+		data = req.Sample
+	}
+
+	output, templateErr := renderNotificationTemplate(tmpl, data)
+	if templateErr != nil {
+		return &types.PreviewNotificationTemplateResponse{Error: templateErr}, nil
+	}
+	return &types.PreviewNotificationTemplateResponse{Valid: true, Output: output}, nil
+}
+
+// templateFlowData 构建真实流程的通知数据，未指定状态时按流程当前状态渲染
+func (s *notificationService) templateFlowData(ctx context.Context, userAddress string, req *types.PreviewNotificationTemplateRequest) (*types.NotificationData, error) {
+	ref := req.Flow
+	standard := strings.ToLower(strings.TrimSpace(ref.Standard))
+	contractAddress := strings.TrimSpace(ref.ContractAddress)
+	flowID := strings.TrimSpace(ref.FlowID)
+
+	statusTo := req.StatusTo
+	var txHash *string
+	switch standard {
+	case "compound":
+		flow, err := s.flowRepo.GetCompoundFlowByID(ctx, flowID, ref.ChainID, contractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrTemplateFlowNotFound
+		}
+		if statusTo == "" {
+			statusTo = flow.Status
+		}
+		txHash = flow.TxHashForStatus(statusTo)
+	case "openzeppelin":
+		flow, err := s.flowRepo.GetOpenzeppelinFlowByID(ctx, flowID, ref.ChainID, contractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow: %w", err)
+		}
+		if flow == nil {
+			return nil, ErrTemplateFlowNotFound
+		}
+		if statusTo == "" {
+			statusTo = flow.Status
+		}
+		txHash = flow.TxHashForStatus(statusTo)
+	default:
+		return nil, fmt.Errorf("invalid standard: %s", ref.Standard)
+	}
+
+	related, err := s.flowRepo.IsUserRelatedToFlow(ctx, userAddress, standard, ref.ChainID, contractAddress, flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check flow access: %w", err)
+	}
+	if !related {
+		return nil, ErrTemplateFlowAccessDenied
+	}
+
+	data, err := s.buildNotificationData(ctx, standard, ref.ChainID, contractAddress, flowID, req.StatusFrom, statusTo, txHash, "")
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrTemplateFlowNotFound
+	}
+	return data, nil
+}
+
+// sampleWithinLimits 示例数据由用户提交，限制其切片长度，避免 range 的迭代次数失控
+func sampleWithinLimits(data *types.NotificationData) bool {
+	if len(data.CalldataParams) > maxSampleSliceLen || len(data.Calls) > maxSampleSliceLen {
+		return false
+	}
+	for _, call := range data.Calls {
+		if len(call.CalldataParams) > maxSampleSliceLen {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNotificationTemplate 解析模板并拒绝危险用法：
+// call（调用任意函数值）、template/block/define（模板嵌套与递归）、对非数据字段的 range（如 range 一个大整数）、
+// 超过 maxTemplateRangeDepth 层的 range 嵌套
+func parseNotificationTemplate(text string) (*template.Template, *types.NotificationTemplateError) {
+	tmpl, err := template.New(previewTemplateName).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, newTemplateError("parse", err)
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, &types.NotificationTemplateError{Stage: "forbidden", Message: "define/block is not allowed"}
+	}
+	if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return tmpl, nil
+	}
+	if node, reason := findForbiddenNode(tmpl.Tree.Root, 0); node != nil {
+		location, _ := tmpl.Tree.ErrorContext(node)
+		templateErr := &types.NotificationTemplateError{Stage: "forbidden", Message: reason}
+		templateErr.Line, templateErr.Column = parseTemplateLocation(location)
+		return nil, templateErr
+	}
+	return tmpl, nil
+}
+
+// findForbiddenNode 遍历语法树，返回第一个不允许的节点及原因，rangeDepth 为当前所在的 range 嵌套层数
+func findForbiddenNode(node parse.Node, rangeDepth int) (parse.Node, string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil, ""
+		}
+		for _, child := range n.Nodes {
+			if bad, reason := findForbiddenNode(child, rangeDepth); bad != nil {
+				return bad, reason
+			}
+		}
+	case *parse.ActionNode:
+		return findForbiddenNode(n.Pipe, rangeDepth)
+	case *parse.IfNode:
+		return findForbiddenBranch(&n.BranchNode, rangeDepth)
+	case *parse.WithNode:
+		return findForbiddenBranch(&n.BranchNode, rangeDepth)
+	case *parse.RangeNode:
+		if !isDataPipe(n.Pipe) {
+			return n, "range is only allowed over data fields"
+		}
+		if rangeDepth >= maxTemplateRangeDepth {
+			return n, fmt.Sprintf("range nesting deeper than %d is not allowed", maxTemplateRangeDepth)
+		}
+		return findForbiddenBranch(&n.BranchNode, rangeDepth+1)
+	case *parse.TemplateNode:
+		return n, fmt.Sprintf("template %q is not allowed", n.Name)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil, ""
+		}
+		for _, cmd := range n.Cmds {
+			if bad, reason := findForbiddenNode(cmd, rangeDepth); bad != nil {
+				return bad, reason
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if bad, reason := findForbiddenNode(arg, rangeDepth); bad != nil {
+				return bad, reason
+			}
+		}
+	case *parse.ChainNode:
+		return findForbiddenNode(n.Node, rangeDepth)
+	case *parse.IdentifierNode:
+		if n.Ident == "call" {
+			return n, "function \"call\" is not allowed"
+		}
+	}
+	return nil, ""
+}
+
+// findForbiddenBranch 检查 if/with/range 的条件与各分支
+func findForbiddenBranch(n *parse.BranchNode, rangeDepth int) (parse.Node, string) {
+	if bad, reason := findForbiddenNode(n.Pipe, rangeDepth); bad != nil {
+		return bad, reason
+	}
+	if bad, reason := findForbiddenNode(n.List, rangeDepth); bad != nil {
+		return bad, reason
+	}
+	if n.ElseList != nil {
+		return findForbiddenNode(n.ElseList, rangeDepth)
+	}
+	return nil, ""
+}
+
+// isDataPipe range 的对象只能是数据字段（.Calls、$.Calls、$call.CalldataParams），保证迭代次数受数据大小约束
+func isDataPipe(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	switch arg := pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		return true
+	case *parse.VariableNode:
+		return len(arg.Ident) > 1
+	}
+	return false
+}
+
+// renderNotificationTemplate 执行模板，输出超过上限或超过 templateRenderTimeout 时中止
+// text/template 无法从外部取消，超时后由 limitedBuffer 在下一次写入时返回错误使 Execute 尽快退出
+func renderNotificationTemplate(tmpl *template.Template, data *types.NotificationData) (string, *types.NotificationTemplateError) {
+	w := &limitedBuffer{limit: maxTemplateOutputBytes, done: make(chan struct{})}
+	result := make(chan error, 1)
+	go func() {
+		result <- tmpl.Execute(w, data)
+	}()
+
+	timer := time.NewTimer(templateRenderTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		if err != nil {
+			if errors.Is(err, errTemplateOutputTooLarge) {
+				return "", &types.NotificationTemplateError{Stage: "render", Message: errTemplateOutputTooLarge.Error()}
+			}
+			return "", newTemplateError("render", err)
+		}
+		return w.String(), nil
+	case <-timer.C:
+		close(w.done)
+		return "", &types.NotificationTemplateError{Stage: "render", Message: errTemplateRenderTimeout.Error()}
+	}
+}
+
+// newTemplateError 将 text/template 错误转换为带行列号的错误信息
+func newTemplateError(stage string, err error) *types.NotificationTemplateError {
+	templateErr := &types.NotificationTemplateError{Stage: stage, Message: err.Error()}
+	if m := templateLocationPattern.FindStringSubmatch(err.Error()); m != nil {
+		templateErr.Line, _ = strconv.Atoi(m[1])
+		templateErr.Column, _ = strconv.Atoi(m[2])
+		templateErr.Message = m[3]
+	}
+	return templateErr
+}
+
+// parseTemplateLocation 解析 ErrorContext 返回的位置 "preview:3:12"
+func parseTemplateLocation(location string) (line, column int) {
+	parts := strings.Split(location, ":")
+	if len(parts) >= 3 {
+		line, _ = strconv.Atoi(parts[len(parts)-2])
+		column, _ = strconv.Atoi(parts[len(parts)-1])
+	}
+	return line, column
+}
+
+// limitedBuffer 写入超过 limit 字节时返回 errTemplateOutputTooLarge，done 关闭后返回 errTemplateRenderTimeout
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+	done  chan struct{}
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	select {
+	case <-b.done:
+		return 0, errTemplateRenderTimeout
+	default:
+	}
+	if b.Len()+len(p) > b.limit {
+		return 0, errTemplateOutputTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+// sampleNotificationData 未提供示例数据时使用的内置示例
+func sampleNotificationData() *types.NotificationData {
+	return &types.NotificationData{
+		StatusFrom:  "waiting",
+		StatusTo:    "ready",
+		Standard:    "compound",
+		Network:     "Ethereum Mainnet",
+		Contract:    "0x1111111111111111111111111111111111111111",
+		ContractUrl: "https://etherscan.io/address/0x1111111111111111111111111111111111111111",
+		Remark:      "Treasury timelock",
+		Nickname:    "Treasury",
+		Caller:      "0x2222222222222222222222222222222222222222",
+		Target:      "0x3333333333333333333333333333333333333333",
+		TargetUrl:   "https://etherscan.io/address/0x3333333333333333333333333333333333333333",
+		Value:       "0 ETH",
+		Function:    "transfer(address,uint256)",
+		CalldataParams: []types.CalldataParam{
+			{Name: "to", Type: "address", Value: "0x4444444444444444444444444444444444444444"},
+			{Name: "amount", Type: "uint256", Value: "1000000000000000000"},
+		},
+		TxHash:       "0x5555555555555555555555555555555555555555555555555555555555555555",
+		TxUrl:        "https://etherscan.io/tx/0x5555555555555555555555555555555555555555555555555555555555555555",
+		DashboardUrl: "https://timelock.example.com",
+		Severity:     types.NotificationSeverityNormal,
+	}
+}
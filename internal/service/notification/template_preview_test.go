@@ -0,0 +1,154 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/types"
+)
+
+func TestParseNotificationTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		wantStage string // 为空表示期望合法
+		wantLine  int
+	}{
+		{"plain fields", "{{.Network}} {{.StatusFrom}} -> {{.StatusTo}}", "", 0},
+		{"custom funcs", "{{upper .Standard}} {{.Remark | trim | lower}}", "", 0},
+		{"range over data field", "{{range .CalldataParams}}{{.Name}}={{.Value}}\n{{end}}", "", 0},
+		{"two level range", "{{range $c := .Calls}}{{range $c.CalldataParams}}{{.Name}}{{end}}{{end}}", "", 0},
+		{"range over root variable", "{{range $.Calls}}{{.Function}}{{end}}", "", 0},
+		{"if and with", "{{if .Nickname}}{{.Nickname}}{{else}}{{.Remark}}{{end}}{{with .TxUrl}}{{.}}{{end}}", "", 0},
+		{"parse error", "line1\n{{.Network", "parse", 2},
+		{"call is forbidden", "{{call .Network}}", "forbidden", 1},
+		{"define is forbidden", `{{define "x"}}a{{end}}{{template "x"}}`, "forbidden", 0},
+		{"template is forbidden", "\n{{template \"preview\"}}", "forbidden", 2},
+		{"range over literal", "{{range 1000000000}}x{{end}}", "forbidden", 1},
+		{"range over pipeline", "{{range .CalldataParams | len}}x{{end}}", "forbidden", 1},
+		{"three level range", "{{range .Calls}}{{range $.Calls}}{{range $.CalldataParams}}x{{end}}{{end}}{{end}}", "forbidden", 1},
+		{"nested range in else branch", "{{range .Calls}}{{else}}{{range .Calls}}{{range .Calls}}x{{end}}{{end}}{{end}}", "forbidden", 1},
+		{"call inside range body", "{{range .Calls}}{{call $.Network}}{{end}}", "forbidden", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, templateErr := parseNotificationTemplate(tt.template)
+			if tt.wantStage == "" {
+				if templateErr != nil {
+					t.Fatalf("unexpected error %+v", templateErr)
+				}
+				if _, templateErr := renderNotificationTemplate(tmpl, sampleNotificationData()); templateErr != nil {
+					t.Fatalf("unexpected render error %+v", templateErr)
+				}
+				return
+			}
+			if templateErr == nil {
+				t.Fatalf("expected %s error", tt.wantStage)
+			}
+			if templateErr.Stage != tt.wantStage {
+				t.Fatalf("stage = %q, want %q (%s)", templateErr.Stage, tt.wantStage, templateErr.Message)
+			}
+			if tt.wantLine != 0 && templateErr.Line != tt.wantLine {
+				t.Fatalf("line = %d, want %d (%s)", templateErr.Line, tt.wantLine, templateErr.Message)
+			}
+		})
+	}
+}
+
+func TestRenderNotificationTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		want        string
+		wantMessage string // 非空表示期望渲染错误，且错误信息包含该内容
+	}{
+		{"fields and funcs", "{{upper .Standard}} on {{.Network}}", "COMPOUND on Ethereum Mainnet", ""},
+		{"range calldata params", "{{range .CalldataParams}}{{.Name}};{{end}}", "to;amount;", ""},
+		{"missing field", "{{.NoSuchField}}", "", "NoSuchField"},
+		{"output too large", "{{range .CalldataParams}}" + strings.Repeat("x", maxTemplateOutputBytes/2+1) + "{{end}}", "", "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, templateErr := parseNotificationTemplate(tt.template)
+			if templateErr != nil {
+				t.Fatalf("parse: %+v", templateErr)
+			}
+			output, templateErr := renderNotificationTemplate(tmpl, sampleNotificationData())
+			if tt.wantMessage != "" {
+				if templateErr == nil || templateErr.Stage != "render" || !strings.Contains(templateErr.Message, tt.wantMessage) {
+					t.Fatalf("error = %+v, want render error containing %q", templateErr, tt.wantMessage)
+				}
+				return
+			}
+			if templateErr != nil {
+				t.Fatalf("render: %+v", templateErr)
+			}
+			if output != tt.want {
+				t.Fatalf("output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderNotificationTemplateBounded 两层 range 在示例数据上限内的最坏情况也能在超时前完成
+func TestRenderNotificationTemplateBounded(t *testing.T) {
+	data := sampleNotificationData()
+	params := make([]types.CalldataParam, maxSampleSliceLen)
+	data.CalldataParams = params
+	data.Calls = make([]types.NotificationCall, maxSampleSliceLen)
+	for i := range data.Calls {
+		data.Calls[i].CalldataParams = params
+	}
+	if !sampleWithinLimits(data) {
+		t.Fatal("sample at the limit should be accepted")
+	}
+
+	tmpl, templateErr := parseNotificationTemplate("{{range .Calls}}{{range $.CalldataParams}}{{end}}{{end}}")
+	if templateErr != nil {
+		t.Fatalf("parse: %+v", templateErr)
+	}
+	start := time.Now()
+	if _, templateErr := renderNotificationTemplate(tmpl, data); templateErr != nil {
+		t.Fatalf("render: %+v", templateErr)
+	}
+	if elapsed := time.Since(start); elapsed > templateRenderTimeout {
+		t.Fatalf("render took %s", elapsed)
+	}
+}
+
+func TestSampleWithinLimits(t *testing.T) {
+	tooMany := make([]types.CalldataParam, maxSampleSliceLen+1)
+	tests := []struct {
+		name string
+		data *types.NotificationData
+		want bool
+	}{
+		{"builtin sample", sampleNotificationData(), true},
+		{"empty", &types.NotificationData{}, true},
+		{"too many params", &types.NotificationData{CalldataParams: tooMany}, false},
+		{"too many calls", &types.NotificationData{Calls: make([]types.NotificationCall, maxSampleSliceLen+1)}, false},
+		{"too many params in a call", &types.NotificationData{Calls: []types.NotificationCall{{CalldataParams: tooMany}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sampleWithinLimits(tt.data); got != tt.want {
+				t.Fatalf("sampleWithinLimits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitedBufferStopsAfterDone(t *testing.T) {
+	w := &limitedBuffer{limit: maxTemplateOutputBytes, done: make(chan struct{})}
+	if _, err := w.Write([]byte("ok")); err != nil {
+		t.Fatalf("write before done: %v", err)
+	}
+	close(w.done)
+	if _, err := w.Write([]byte("late")); err != errTemplateRenderTimeout {
+		t.Fatalf("write after done: err = %v, want errTemplateRenderTimeout", err)
+	}
+	if w.String() != "ok" {
+		t.Fatalf("buffer = %q, want %q", w.String(), "ok")
+	}
+}
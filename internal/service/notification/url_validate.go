@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+)
+
+// webhookProbeTimeout 单次探测 webhook URL 的超时时间
+const webhookProbeTimeout = 10 * time.Second
+
+// webhookProbeClient 探测 webhook URL 使用的 HTTP 客户端，连接时再次校验目标 IP
+var webhookProbeClient = utils.NewSafeHTTPClient(webhookProbeTimeout)
+
+// ValidateWebhookURL 校验 webhook URL 的可达性：先发 HEAD，服务端不支持 HEAD 时改发空 JSON 的测试 POST
+// URL 指向内网/本机地址时返回 utils.ErrUnsafeURL；网络不可达不视为错误，在响应中标记 reachable=false
+func (s *notificationService) ValidateWebhookURL(ctx context.Context, userAddress string, req *types.ValidateWebhookURLRequest) (*types.ValidateWebhookURLResponse, error) {
+	u, err := utils.ValidateOutboundURL(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp := &types.ValidateWebhookURLResponse{Method: http.MethodHead}
+	statusCode, err := probeWebhookURL(ctx, http.MethodHead, u.String())
+	// 多数 webhook 只接受 POST，HEAD 返回 404/405 时再用测试 POST 确认
+	if err == nil && (statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusNotFound) {
+		resp.Method = http.MethodPost
+		statusCode, err = probeWebhookURL(ctx, http.MethodPost, u.String())
+	}
+	resp.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		resp.Error = err.Error()
+		logger.Warn("Webhook url unreachable", "user_address", userAddress, "host", u.Host, "error", err)
+		return resp, nil
+	}
+	resp.Reachable = true
+	resp.StatusCode = statusCode
+	resp.Success = statusCode >= 200 && statusCode < 300
+	logger.Info("Webhook url validated", "user_address", userAddress, "host", u.Host, "method", resp.Method, "status_code", statusCode)
+	return resp, nil
+}
+
+// probeWebhookURL 发送一次探测请求并返回状态码
+func probeWebhookURL(ctx context.Context, method, url string) (int, error) {
+	var body io.Reader
+	if method == http.MethodPost {
+		body = bytes.NewReader([]byte("{}"))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := webhookProbeClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
+
+// validateDestinationURLs 保存配置前校验用户提供的 webhook/homeserver URL，拒绝指向内网/本机地址（SSRF 防护）
+// 为空的 URL 不校验（由各渠道的必填校验处理）
+func validateDestinationURLs(ctx context.Context, urls ...string) error {
+	for _, raw := range urls {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if _, err := utils.ValidateOutboundURL(ctx, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// derefString 取指针字符串的值，nil 时返回空字符串
+func derefString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
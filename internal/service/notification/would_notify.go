@@ -0,0 +1,96 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+)
+
+// notificationConfigRef 判定用的渠道配置摘要
+type notificationConfigRef struct {
+	channel  types.NotificationChannel
+	id       uint
+	name     string
+	isActive bool
+}
+
+// ExplainFlowNotification 按与 SendFlowNotification 相同的筛选逻辑（合约相关用户、配置启用状态、渠道总开关、发送去重）
+// 判定用户自己的每个渠道配置是否会收到该状态的通知，不发送也不写通知日志；related 表示用户是否在合约的通知对象中
+func (s *notificationService) ExplainFlowNotification(ctx context.Context, userAddress, standard string, chainID int, contractAddress, flowID, statusTo string) (bool, []types.WouldNotifyConfig, error) {
+	userAddress = strings.ToLower(userAddress)
+
+	relatedUsers, err := s.repo.GetContractRelatedUserAddresses(ctx, standard, chainID, contractAddress)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get contract related users: %w", err)
+	}
+	related := false
+	for _, address := range relatedUsers {
+		if strings.EqualFold(address, userAddress) {
+			related = true
+			break
+		}
+	}
+
+	list, err := s.listNotificationConfigs(ctx, userAddress)
+	if err != nil {
+		return false, nil, err
+	}
+	disabled, err := s.disabledChannels(ctx, userAddress)
+	if err != nil {
+		// 与发送时一致：读取渠道开关失败时按全部开启处理
+		logger.Error("Failed to get notification channel settings, treating all channels as enabled", err, "userAddress", userAddress)
+		disabled = nil
+	}
+
+	results := make([]types.WouldNotifyConfig, 0)
+	for _, ref := range flattenNotificationConfigs(list) {
+		result := types.WouldNotifyConfig{Channel: ref.channel, ConfigID: ref.id, Name: ref.name}
+		switch {
+		case !related:
+			result.Reason = types.WouldNotifyReasonNotRelatedUser
+		case !ref.isActive:
+			result.Reason = types.WouldNotifyReasonConfigInactive
+		case disabled[ref.channel]:
+			result.Reason = types.WouldNotifyReasonChannelDisabled
+		default:
+			exists, err := s.repo.CheckNotificationLogExists(ctx, ref.channel, userAddress, ref.id, flowID, statusTo)
+			if err != nil {
+				return false, nil, fmt.Errorf("failed to check notification log: %w", err)
+			}
+			if exists {
+				result.Reason = types.WouldNotifyReasonAlreadySent
+			} else {
+				result.WouldFire = true
+			}
+		}
+		results = append(results, result)
+	}
+	return related, results, nil
+}
+
+// flattenNotificationConfigs 按渠道展示顺序展开全部渠道配置
+func flattenNotificationConfigs(list *types.NotificationConfigListResponse) []notificationConfigRef {
+	var refs []notificationConfigRef
+	for _, c := range list.TelegramConfigs {
+		refs = append(refs, notificationConfigRef{types.ChannelTelegram, c.ID, c.Name, c.IsActive})
+	}
+	for _, c := range list.LarkConfigs {
+		refs = append(refs, notificationConfigRef{types.ChannelLark, c.ID, c.Name, c.IsActive})
+	}
+	for _, c := range list.FeishuConfigs {
+		refs = append(refs, notificationConfigRef{types.ChannelFeishu, c.ID, c.Name, c.IsActive})
+	}
+	for _, c := range list.DiscordConfigs {
+		refs = append(refs, notificationConfigRef{types.ChannelDiscord, c.ID, c.Name, c.IsActive})
+	}
+	for _, c := range list.SlackConfigs {
+		refs = append(refs, notificationConfigRef{types.ChannelSlack, c.ID, c.Name, c.IsActive})
+	}
+	for _, c := range list.MatrixConfigs {
+		refs = append(refs, notificationConfigRef{types.ChannelMatrix, c.ID, c.Name, c.IsActive})
+	}
+	return refs
+}
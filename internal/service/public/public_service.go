@@ -2,15 +2,23 @@ package public
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 	"timelocker-backend/internal/repository/public"
 	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
 )
 
+var ErrInvalidStatsRange = errors.New("invalid stats history range")
+
 // Service 公共数据服务接口
 type Service interface {
 	GetStats(ctx context.Context, req *types.GetStatsRequest) (*types.GetStatsResponse, error)
+	// 获取全局统计快照序列（趋势图）
+	GetStatsHistory(ctx context.Context, req *types.GetStatsHistoryRequest) (*types.GetStatsHistoryResponse, error)
+	// 写入一条当前全局统计的快照（定时任务）
+	SnapshotStats(ctx context.Context) error
 }
 
 // service 公共数据服务实现
@@ -58,3 +66,42 @@ func (s *service) GetStats(ctx context.Context, req *types.GetStatsRequest) (*ty
 	logger.Info("GetStats: ", "chains", chainCount, "contracts", contractCount, "transactions", transactionCount)
 	return response, nil
 }
+
+// GetStatsHistory 按时间范围分页获取全局统计快照，按快照时间升序
+func (s *service) GetStatsHistory(ctx context.Context, req *types.GetStatsHistoryRequest) (*types.GetStatsHistoryResponse, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidStatsRange)
+	}
+
+	page, pageSize := types.ClampPagination(req.Page, req.PageSize, types.MaxPageSize)
+	points, total, err := s.publicRepo.ListStatsSnapshots(ctx, req.From, req.To, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stats snapshots: %w", err)
+	}
+
+	return &types.GetStatsHistoryResponse{
+		Points:         points,
+		PaginationMeta: types.NewPaginationMeta(total, page, pageSize),
+	}, nil
+}
+
+// SnapshotStats 将当前全局统计（与 GetStats 一致）写入历史表
+func (s *service) SnapshotStats(ctx context.Context) error {
+	stats, err := s.GetStats(ctx, &types.GetStatsRequest{})
+	if err != nil {
+		return err
+	}
+
+	snapshot := &types.GlobalStatsSnapshot{
+		ChainCount:       stats.ChainCount,
+		ContractCount:    stats.ContractCount,
+		TransactionCount: stats.TransactionCount,
+		SnapshotAt:       time.Now(),
+	}
+	if err := s.publicRepo.CreateStatsSnapshot(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to save stats snapshot: %w", err)
+	}
+
+	logger.Info("SnapshotStats: ", "chains", snapshot.ChainCount, "contracts", snapshot.ContractCount, "transactions", snapshot.TransactionCount)
+	return nil
+}
@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// batchCallMaxSize 单个 JSON-RPC batch 的最大请求数（多数服务商限制在 100 左右）
+	batchCallMaxSize = 50
+	// batchCapabilityTTL 探测为不支持 batch 后的重新探测间隔，避免一次偶发失败永久降级
+	batchCapabilityTTL = time.Hour
+)
+
+// BatchCallContract 对同一条链执行多个 eth_call：RPC 支持 JSON-RPC batch 时合并为一次请求，否则逐个调用。
+//
+// 返回结果与输入按索引对齐。子调用 revert 等 JSON-RPC 错误只会标记该项 Success=false，
+// 由调用方按 Success 判断（Call3.AllowFailure 在这里不生效）；网络等整体错误会重试并返回错误。
+// 适用于链上未部署 Multicall3 时读取合约元数据。
+func (rm *RPCManager) BatchCallContract(ctx context.Context, chainID int, calls []Call3) ([]Call3Result, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	results := make([]Call3Result, len(calls))
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		supported, known := rm.batchSupported(chainID)
+		if !known || supported {
+			err := batchEthCall(ctx, client, calls, results)
+			if err == nil {
+				if !known {
+					rm.storeBatchCapability(types.RPCBatchCapability{ChainID: chainID, Supported: true, ProbedAt: time.Now()})
+					logger.Info("RPC supports JSON-RPC batch", "chain_id", chainID)
+				}
+				return nil
+			}
+			if known {
+				return err // 已确认支持 batch，按普通错误处理
+			}
+			logger.Warn("JSON-RPC batch not supported, falling back to sequential eth_call", "chain_id", chainID, "error", err)
+			rm.storeBatchCapability(types.RPCBatchCapability{ChainID: chainID, Supported: false, ProbedAt: time.Now(), LastError: err.Error()})
+		}
+		return sequentialEthCall(ctx, client, calls, results)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchSupported 返回 (是否支持, 是否已探测)；不支持的探测结果超过 batchCapabilityTTL 视为未探测
+func (rm *RPCManager) batchSupported(chainID int) (bool, bool) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	batchCap, ok := rm.batchCaps[chainID]
+	if !ok {
+		return false, false
+	}
+	if !batchCap.Supported && time.Since(batchCap.ProbedAt) > batchCapabilityTTL {
+		return false, false
+	}
+	return batchCap.Supported, true
+}
+
+// storeBatchCapability 缓存 batch 支持情况
+func (rm *RPCManager) storeBatchCapability(batchCap types.RPCBatchCapability) {
+	rm.mutex.Lock()
+	rm.batchCaps[batchCap.ChainID] = batchCap
+	rm.mutex.Unlock()
+}
+
+// batchEthCall 按 batchCallMaxSize 分批发送 JSON-RPC batch 请求
+func batchEthCall(ctx context.Context, client *ethclient.Client, calls []Call3, results []Call3Result) error {
+	for start := 0; start < len(calls); start += batchCallMaxSize {
+		end := start + batchCallMaxSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		elems := make([]rpc.BatchElem, end-start)
+		raws := make([]hexutil.Bytes, end-start)
+		for i, c := range calls[start:end] {
+			elems[i] = rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{toCallArg(c), "latest"},
+				Result: &raws[i],
+			}
+		}
+
+		if err := client.Client().BatchCallContext(ctx, elems); err != nil {
+			return err
+		}
+		for i, elem := range elems {
+			if elem.Error != nil {
+				results[start+i] = Call3Result{Success: false}
+				continue
+			}
+			results[start+i] = Call3Result{Success: true, ReturnData: raws[i]}
+		}
+	}
+	return nil
+}
+
+// sequentialEthCall 逐个执行 eth_call；JSON-RPC 错误（如 revert）记为该项失败，其余错误直接返回
+func sequentialEthCall(ctx context.Context, client *ethclient.Client, calls []Call3, results []Call3Result) error {
+	for i, c := range calls {
+		target := c.Target
+		data, err := client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: c.CallData}, nil)
+		if err != nil {
+			var rpcErr rpc.Error
+			if errors.As(err, &rpcErr) {
+				results[i] = Call3Result{Success: false}
+				continue
+			}
+			return err
+		}
+		results[i] = Call3Result{Success: true, ReturnData: data}
+	}
+	return nil
+}
+
+// toCallArg 构造 eth_call 的调用参数
+func toCallArg(c Call3) map[string]interface{} {
+	return map[string]interface{}{
+		"to":   c.Target,
+		"data": hexutil.Bytes(c.CallData),
+	}
+}
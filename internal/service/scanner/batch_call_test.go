@@ -0,0 +1,194 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testRevertTarget 的 eth_call 返回 execution reverted
+var testRevertTarget = common.HexToAddress("0x00000000000000000000000000000000000000ff")
+
+type jsonRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// ethCallStub 模拟只实现 eth_call 的节点：返回值为 32 字节、末字节等于目标地址末字节；
+// batch=false 时拒绝 JSON-RPC batch 请求，latency 为每个 HTTP 请求的往返延迟
+type ethCallStub struct {
+	batch    bool
+	latency  time.Duration
+	requests atomic.Int64
+}
+
+func (s *ethCallStub) reply(req jsonRPCRequest) map[string]interface{} {
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	var arg struct {
+		To common.Address `json:"to"`
+	}
+	if req.Method != "eth_call" || len(req.Params) == 0 || json.Unmarshal(req.Params[0], &arg) != nil {
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		return resp
+	}
+	if arg.To == testRevertTarget {
+		resp["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+		return resp
+	}
+	word := make([]byte, 32)
+	word[31] = arg.To.Bytes()[19]
+	resp["result"] = hexutil.Bytes(word)
+	return resp
+}
+
+func (s *ethCallStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	if s.latency > 0 {
+		time.Sleep(s.latency)
+	}
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		if !s.batch {
+			http.Error(w, "batch requests are not supported", http.StatusBadRequest)
+			return
+		}
+		var reqs []jsonRPCRequest
+		_ = json.Unmarshal(body, &reqs)
+		resps := make([]map[string]interface{}, len(reqs))
+		for i, req := range reqs {
+			resps[i] = s.reply(req)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var req jsonRPCRequest
+	_ = json.Unmarshal(body, &req)
+	_ = json.NewEncoder(w).Encode(s.reply(req))
+}
+
+func newEthCallClient(tb testing.TB, stub *ethCallStub) *ethclient.Client {
+	tb.Helper()
+	server := httptest.NewServer(stub)
+	tb.Cleanup(server.Close)
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		tb.Fatalf("DialHTTP: %v", err)
+	}
+	tb.Cleanup(c.Close)
+	return ethclient.NewClient(c)
+}
+
+// testCalls 构造 n 个子调用，目标地址末字节依次为 1..n，revertAt 处的调用 revert（-1 表示无）
+func testCalls(n, revertAt int) []Call3 {
+	calls := make([]Call3, n)
+	for i := range calls {
+		calls[i] = Call3{Target: common.BigToAddress(big.NewInt(int64(i + 1))), CallData: []byte{0x01, 0x02, 0x03, 0x04}}
+		if i == revertAt {
+			calls[i].Target = testRevertTarget
+		}
+	}
+	return calls
+}
+
+func TestBatchCallContract(t *testing.T) {
+	tests := []struct {
+		name         string
+		batch        bool
+		calls        int
+		wantRequests int64
+	}{
+		{"batch supported", true, 6, 1},
+		{"batch split by max size", true, batchCallMaxSize + 1, 2},
+		{"batch unsupported falls back to sequential", false, 6, 1 + 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &ethCallStub{batch: tt.batch}
+			rm := NewRPCManager(&config.Config{}, &countingChainRepo{})
+			rm.clients[testChainID] = newEthCallClient(t, stub)
+
+			calls := testCalls(tt.calls, 2)
+			results, err := rm.BatchCallContract(context.Background(), testChainID, calls)
+			if err != nil {
+				t.Fatalf("BatchCallContract: %v", err)
+			}
+			if len(results) != len(calls) {
+				t.Fatalf("got %d results, want %d", len(results), len(calls))
+			}
+			for i, res := range results {
+				if i == 2 {
+					if res.Success {
+						t.Fatal("reverted call reported as success")
+					}
+					continue
+				}
+				if !res.Success || len(res.ReturnData) != 32 || res.ReturnData[31] != byte(i+1) {
+					t.Fatalf("result %d = %+v, want success with last byte %d", i, res, i+1)
+				}
+			}
+			if got := stub.requests.Load(); got != tt.wantRequests {
+				t.Fatalf("server received %d HTTP requests, want %d", got, tt.wantRequests)
+			}
+
+			supported, known := rm.batchSupported(testChainID)
+			if !known || supported != tt.batch {
+				t.Fatalf("batchSupported = (%v, %v), want (%v, true)", supported, known, tt.batch)
+			}
+
+			// 已缓存不支持时直接逐个调用，不再尝试 batch
+			stub.requests.Store(0)
+			if _, err := rm.BatchCallContract(context.Background(), testChainID, calls); err != nil {
+				t.Fatalf("second BatchCallContract: %v", err)
+			}
+			want := int64(len(calls))
+			if tt.batch {
+				want = tt.wantRequests
+			}
+			if got := stub.requests.Load(); got != want {
+				t.Fatalf("second call sent %d HTTP requests, want %d", got, want)
+			}
+		})
+	}
+}
+
+// BenchmarkRefreshMetadataCalls 对比一次典型 Compound 刷新（6 个 eth_call）在 batch 与逐个调用下的耗时，
+// 桩节点为每个 HTTP 请求加入 2ms 往返延迟以模拟远程 RPC
+func BenchmarkRefreshMetadataCalls(b *testing.B) {
+	// delay、admin、pendingAdmin、GRACE_PERIOD、MINIMUM_DELAY、MAXIMUM_DELAY
+	calls := testCalls(6, -1)
+	client := newEthCallClient(b, &ethCallStub{batch: true, latency: 2 * time.Millisecond})
+	ctx := context.Background()
+
+	b.Run("batch", func(b *testing.B) {
+		results := make([]Call3Result, len(calls))
+		for i := 0; i < b.N; i++ {
+			if err := batchEthCall(ctx, client, calls, results); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("sequential", func(b *testing.B) {
+		results := make([]Call3Result, len(calls))
+		for i := 0; i < b.N; i++ {
+			if err := sequentialEthCall(ctx, client, calls, results); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// defaultLogsBlockRange 未探测或探测失败时使用的保守区块跨度
+	defaultLogsBlockRange uint64 = 500
+	// logsProbeTimeout 单次探测调用超时
+	logsProbeTimeout = 15 * time.Second
+)
+
+// logsProbeRanges 探测时依次尝试的区块跨度（由大到小，首个成功即为上限）
+var logsProbeRanges = []uint64{10000, 5000, 2000, 1000, 500, 100, 10}
+
+// ProbeAllLogsCapabilities 对所有已连接链探测 eth_getLogs 最大区块跨度
+func (rm *RPCManager) ProbeAllLogsCapabilities(ctx context.Context) {
+	rm.mutex.RLock()
+	chainIDs := make([]int, 0, len(rm.clients))
+	for chainID := range rm.clients {
+		chainIDs = append(chainIDs, chainID)
+	}
+	rm.mutex.RUnlock()
+
+	for _, chainID := range chainIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		rm.ProbeLogsCapability(ctx, chainID)
+	}
+}
+
+// ProbeLogsCapability 探测指定链 RPC 的 eth_getLogs 最大区块跨度并缓存结果
+func (rm *RPCManager) ProbeLogsCapability(ctx context.Context, chainID int) types.RPCLogsCapability {
+	result := types.RPCLogsCapability{
+		ChainID:       chainID,
+		MaxBlockRange: defaultLogsBlockRange,
+		ProbedAt:      time.Now(),
+	}
+
+	client, err := rm.GetOrCreateClient(ctx, chainID)
+	if err != nil {
+		return rm.storeFailedLogsProbe(result, err)
+	}
+
+	maxRange, err := probeLogsRange(ctx, client)
+	if err != nil {
+		return rm.storeFailedLogsProbe(result, err)
+	}
+	result.MaxBlockRange = maxRange
+	result.Probed = true
+	logger.Info("Probed eth_getLogs block range", "chain_id", chainID, "max_block_range", maxRange)
+
+	rm.storeLogsCapability(result)
+	return result
+}
+
+// GetLogsMaxBlockRange 获取指定链 eth_getLogs 的安全区块跨度，未探测时返回保守默认值
+func (rm *RPCManager) GetLogsMaxBlockRange(chainID int) uint64 {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	if logsCap, ok := rm.logsCaps[chainID]; ok && logsCap.MaxBlockRange > 0 {
+		return logsCap.MaxBlockRange
+	}
+	return defaultLogsBlockRange
+}
+
+// FilterLogsChunked 按探测到的区块跨度分段执行 eth_getLogs，避免因跨度过大被 RPC 拒绝
+func (rm *RPCManager) FilterLogsChunked(ctx context.Context, chainID int, query ethereum.FilterQuery) ([]ethTypes.Log, error) {
+	if query.FromBlock == nil || query.ToBlock == nil {
+		return nil, fmt.Errorf("from_block and to_block are required")
+	}
+
+	chunks := ComputeLogChunks(query.FromBlock.Uint64(), query.ToBlock.Uint64(), rm.GetLogsMaxBlockRange(chainID))
+
+	var logs []ethTypes.Log
+	for _, chunk := range chunks {
+		q := query
+		q.FromBlock = new(big.Int).SetUint64(chunk[0])
+		q.ToBlock = new(big.Int).SetUint64(chunk[1])
+
+		var chunkLogs []ethTypes.Log
+		err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+			var err error
+			chunkLogs, err = client.FilterLogs(ctx, q)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter logs for blocks %d-%d: %w", chunk[0], chunk[1], err)
+		}
+		logs = append(logs, chunkLogs...)
+	}
+
+	return logs, nil
+}
+
+// LatestBlockNumber 获取指定链的最新区块高度
+func (rm *RPCManager) LatestBlockNumber(ctx context.Context, chainID int) (uint64, error) {
+	var latest uint64
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		var err error
+		latest, err = client.BlockNumber(ctx)
+		return err
+	})
+	return latest, err
+}
+
+// BlockTimestamp 获取指定区块的时间戳
+func (rm *RPCManager) BlockTimestamp(ctx context.Context, chainID int, blockNumber uint64) (time.Time, error) {
+	var ts time.Time
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return err
+		}
+		ts = time.Unix(int64(header.Time), 0)
+		return nil
+	})
+	return ts, err
+}
+
+// TransactionSender 获取交易发起地址（log 中的 blockHash/txIndex 可让节点直接命中缓存）
+func (rm *RPCManager) TransactionSender(ctx context.Context, chainID int, txHash common.Hash, blockHash common.Hash, txIndex uint) (common.Address, error) {
+	var sender common.Address
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		tx, _, err := client.TransactionByHash(ctx, txHash)
+		if err != nil {
+			return err
+		}
+		sender, err = client.TransactionSender(ctx, tx, blockHash, txIndex)
+		return err
+	})
+	return sender, err
+}
+
+// TransactionReceipt 获取交易回执；交易不在当前规范链上（未打包或已被重组移除）时返回 nil
+func (rm *RPCManager) TransactionReceipt(ctx context.Context, chainID int, txHash common.Hash) (*ethTypes.Receipt, error) {
+	var receipt *ethTypes.Receipt
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		r, err := client.TransactionReceipt(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			return nil // 找不到回执不是连接错误，不重试
+		}
+		if err != nil {
+			return err
+		}
+		receipt = r
+		return nil
+	})
+	return receipt, err
+}
+
+// CanonicalBlockHash 获取指定高度的规范区块哈希
+func (rm *RPCManager) CanonicalBlockHash(ctx context.Context, chainID int, blockNumber uint64) (common.Hash, error) {
+	var hash common.Hash
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return err
+		}
+		hash = header.Hash()
+		return nil
+	})
+	return hash, err
+}
+
+// ContractCode 获取合约在最新区块的字节码（eth_getCode），地址无合约时返回空
+func (rm *RPCManager) ContractCode(ctx context.Context, chainID int, address common.Address) ([]byte, error) {
+	var code []byte
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		var err error
+		code, err = client.CodeAt(ctx, address, nil)
+		return err
+	})
+	return code, err
+}
+
+// CallContract 在最新区块执行只读调用（eth_call）；revert 等 JSON-RPC 错误原样返回，不重试
+func (rm *RPCManager) CallContract(ctx context.Context, chainID int, msg ethereum.CallMsg) ([]byte, error) {
+	var result []byte
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		var err error
+		result, err = client.CallContract(ctx, msg, nil)
+		return err
+	})
+	return result, err
+}
+
+// BalanceAt 获取地址在最新区块的原生代币余额
+func (rm *RPCManager) BalanceAt(ctx context.Context, chainID int, address common.Address) (*big.Int, error) {
+	var balance *big.Int
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		var err error
+		balance, err = client.BalanceAt(ctx, address, nil)
+		return err
+	})
+	return balance, err
+}
+
+// StorageAt 获取合约在最新区块的存储槽值（eth_getStorageAt）
+func (rm *RPCManager) StorageAt(ctx context.Context, chainID int, address common.Address, slot common.Hash) ([]byte, error) {
+	var value []byte
+	err := rm.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		var err error
+		value, err = client.StorageAt(ctx, address, slot, nil)
+		return err
+	})
+	return value, err
+}
+
+// ComputeLogChunks 将 [from, to] 按 maxRange 切分为闭区间列表
+func ComputeLogChunks(from, to, maxRange uint64) [][2]uint64 {
+	if from > to {
+		return nil
+	}
+	if maxRange == 0 {
+		maxRange = defaultLogsBlockRange
+	}
+
+	chunks := make([][2]uint64, 0, (to-from)/maxRange+1)
+	for start := from; start <= to; {
+		end := start + maxRange - 1
+		if end > to || end < start { // end < start 防止溢出
+			end = to
+		}
+		chunks = append(chunks, [2]uint64{start, end})
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+	return chunks
+}
+
+// storeFailedLogsProbe 记录探测失败；之前探测成功过时保留上次的跨度，否则使用保守默认值
+func (rm *RPCManager) storeFailedLogsProbe(result types.RPCLogsCapability, err error) types.RPCLogsCapability {
+	result.LastError = err.Error()
+
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+	if prev, ok := rm.logsCaps[result.ChainID]; ok && prev.Probed && prev.MaxBlockRange > 0 {
+		result.MaxBlockRange = prev.MaxBlockRange
+		result.Probed = true
+		logger.Warn("Failed to re-probe eth_getLogs block range, keeping last probed range", "chain_id", result.ChainID, "max_block_range", prev.MaxBlockRange, "error", err)
+	} else {
+		logger.Warn("Failed to probe eth_getLogs block range, using default", "chain_id", result.ChainID, "default_range", defaultLogsBlockRange, "error", err)
+	}
+	rm.logsCaps[result.ChainID] = result
+	return result
+}
+
+// storeLogsCapability 缓存探测结果
+func (rm *RPCManager) storeLogsCapability(logsCap types.RPCLogsCapability) {
+	rm.mutex.Lock()
+	rm.logsCaps[logsCap.ChainID] = logsCap
+	rm.mutex.Unlock()
+}
+
+// probeLogsRange 从大到小尝试区块跨度，返回首个成功的跨度
+func probeLogsRange(ctx context.Context, client *ethclient.Client) (uint64, error) {
+	callCtx, cancel := context.WithTimeout(ctx, logsProbeTimeout)
+	latest, err := client.BlockNumber(callCtx)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	var lastErr error
+	for _, r := range logsProbeRanges {
+		if latest < r {
+			continue
+		}
+		// 使用零地址过滤，仅验证 RPC 是否接受该跨度，避免返回大量数据
+		query := ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(latest - r + 1),
+			ToBlock:   new(big.Int).SetUint64(latest),
+			Addresses: []common.Address{{}},
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, logsProbeTimeout)
+		_, err := client.FilterLogs(callCtx, query)
+		cancel()
+		if err == nil {
+			return r, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("chain too short to probe")
+	}
+	return 0, lastErr
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"timelocker-backend/internal/types"
+)
+
+func TestComputeLogChunks(t *testing.T) {
+	const maxUint64 = ^uint64(0)
+	tests := []struct {
+		name     string
+		from, to uint64
+		maxRange uint64
+		want     [][2]uint64
+	}{
+		{"from after to", 10, 9, 5, nil},
+		{"single block", 7, 7, 5, [][2]uint64{{7, 7}}},
+		{"exactly one chunk", 1, 5, 5, [][2]uint64{{1, 5}}},
+		{"one block over", 1, 6, 5, [][2]uint64{{1, 5}, {6, 6}}},
+		{"even split", 0, 9, 5, [][2]uint64{{0, 4}, {5, 9}}},
+		{"range of one", 3, 5, 1, [][2]uint64{{3, 3}, {4, 4}, {5, 5}}},
+		{"zero range uses default", 0, 999, 0, [][2]uint64{{0, 499}, {500, 999}}},
+		{"near uint64 max does not overflow", maxUint64 - 2, maxUint64, 10, [][2]uint64{{maxUint64 - 2, maxUint64}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeLogChunks(tt.from, tt.to, tt.maxRange); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ComputeLogChunks(%d, %d, %d) = %v, want %v", tt.from, tt.to, tt.maxRange, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeLogsCapabilityFailureKeepsLastGoodRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev       *types.RPCLogsCapability
+		wantRange  uint64
+		wantProbed bool
+	}{
+		{"never probed falls back to default", nil, defaultLogsBlockRange, false},
+		{"previous failure stays at default", &types.RPCLogsCapability{ChainID: testChainID, MaxBlockRange: defaultLogsBlockRange}, defaultLogsBlockRange, false},
+		{"previous success is kept", &types.RPCLogsCapability{ChainID: testChainID, MaxBlockRange: 5000, Probed: true}, 5000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 测试客户端指向不可达地址，探测必然失败
+			rm := newTestRPCManager(t)
+			if tt.prev != nil {
+				rm.storeLogsCapability(*tt.prev)
+			}
+
+			result := rm.ProbeLogsCapability(context.Background(), testChainID)
+			if result.LastError == "" {
+				t.Fatal("expected probe error to be recorded")
+			}
+			if result.MaxBlockRange != tt.wantRange || result.Probed != tt.wantProbed {
+				t.Fatalf("result = %+v, want range %d probed %v", result, tt.wantRange, tt.wantProbed)
+			}
+			if got := rm.GetLogsMaxBlockRange(testChainID); got != tt.wantRange {
+				t.Fatalf("GetLogsMaxBlockRange = %d, want %d", got, tt.wantRange)
+			}
+		})
+	}
+}
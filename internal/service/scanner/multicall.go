@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -57,6 +58,9 @@ func init() {
 	parsedMulticallABI, multicallInitErr = abi.JSON(strings.NewReader(multicall3ABI))
 }
 
+// ErrMulticallUnavailable 链上 Multicall3 地址无合约（eth_call 返回空数据），调用方应改用 BatchCallContract
+var ErrMulticallUnavailable = errors.New("multicall3 not deployed on this chain")
+
 // Call3 对应 Multicall3.Call3 的一次子调用
 type Call3 struct {
 	Target       common.Address
@@ -117,6 +121,9 @@ func AggregateCall3(ctx context.Context, client *ethclient.Client, calls []Call3
 	if err != nil {
 		return nil, fmt.Errorf("failed to call multicall3: %w", err)
 	}
+	if len(raw) == 0 {
+		return nil, ErrMulticallUnavailable
+	}
 
 	decoded, err := parsedMulticallABI.Unpack("aggregate3", raw)
 	if err != nil {
@@ -2,9 +2,13 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"timelocker-backend/internal/config"
@@ -13,17 +17,29 @@ import (
 	"timelocker-backend/pkg/logger"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // RPCManager RPC管理器，只使用Alchemy RPC
 type RPCManager struct {
 	rpcConfig  *config.RPCConfig
 	chainRepo  chain.Repository
-	clients    map[int]*ethclient.Client  // 直接使用chainID作为key
-	chainInfos map[int]types.ChainRPCInfo // chainID -> 链配置，避免每次重查 DB
+	clients    map[int]*ethclient.Client        // 直接使用chainID作为key
+	chainInfos map[int]types.ChainRPCInfo       // chainID -> 链配置，避免每次重查 DB
+	logsCaps   map[int]types.RPCLogsCapability  // chainID -> eth_getLogs 能力探测结果
+	batchCaps  map[int]types.RPCBatchCapability // chainID -> JSON-RPC batch 支持情况
+	downSince  map[int]time.Time                // chainID -> 所有RPC均不可用的起始时间
+	retryDelay time.Duration                    // 首次重试等待时间，之后指数退避
 	mutex      sync.RWMutex
 }
 
+const (
+	// rpcMaxRetries ExecuteWithRetry 的最大尝试次数
+	rpcMaxRetries = 5
+	// defaultRPCRetryDelay 首次重试等待时间
+	defaultRPCRetryDelay = 10 * time.Second
+)
+
 // NewRPCManager 创建RPC管理器
 func NewRPCManager(cfg *config.Config, chainRepo chain.Repository) *RPCManager {
 	return &RPCManager{
@@ -31,6 +47,10 @@ func NewRPCManager(cfg *config.Config, chainRepo chain.Repository) *RPCManager {
 		chainRepo:  chainRepo,
 		clients:    make(map[int]*ethclient.Client),
 		chainInfos: make(map[int]types.ChainRPCInfo),
+		logsCaps:   make(map[int]types.RPCLogsCapability),
+		batchCaps:  make(map[int]types.RPCBatchCapability),
+		downSince:  make(map[int]time.Time),
+		retryDelay: defaultRPCRetryDelay,
 	}
 }
 
@@ -187,54 +207,113 @@ func (rm *RPCManager) createClient(ctx context.Context, chainID int, rpcURL stri
 }
 
 // ExecuteWithRetry 带重试的RPC调用执行
+// 只有传输层错误（连接失败、超时、HTTP 错误）与获取客户端失败会重试，重试耗尽后标记链不可用；
+// 节点返回的 JSON-RPC 错误（含 revert）、NotFound、ABI 解码失败等说明节点可用，直接返回不重试
 func (rm *RPCManager) ExecuteWithRetry(ctx context.Context, chainID int, fn func(*ethclient.Client) error) error {
 	var lastErr error
-	retryDelay := 10 * time.Second
+	retryDelay := rm.retryDelay
 
-	for i := 0; i < 5; i++ {
+	for i := 0; i < rpcMaxRetries; i++ {
 		client, err := rm.GetOrCreateClient(ctx, chainID)
 		if err != nil {
 			lastErr = err
 			logger.Warn("Failed to get RPC client", "chain_id", chainID, "attempt", i+1, "error", err)
-
-			// 等待重试延迟
-			if i < 5-1 {
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(retryDelay):
-					retryDelay *= 2 // 指数退避
-				}
+		} else if err := fn(client); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !isTransportError(err) {
+				rm.markChainRecovered(chainID)
+				return err
 			}
-			continue
-		}
-
-		// 执行RPC调用
-		if err := fn(client); err != nil {
 			lastErr = err
 			logger.Warn("RPC call failed", "chain_id", chainID, "attempt", i+1, "error", err)
 
-			// 如果是连接错误，移除客户端以便下次重新创建
+			// 连接错误，移除客户端以便下次重新创建
 			rm.removeClient(chainID)
+		} else {
+			// 成功执行
+			rm.markChainRecovered(chainID)
+			return nil
+		}
 
-			// 等待重试延迟
-			if i < 5-1 {
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(retryDelay):
-					retryDelay *= 2 // 指数退避
-				}
+		// 等待重试延迟
+		if i < rpcMaxRetries-1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryDelay):
+				retryDelay *= 2 // 指数退避
 			}
-			continue
 		}
+	}
 
-		// 成功执行
-		return nil
+	rm.markChainDown(chainID, lastErr)
+	return fmt.Errorf("RPC call failed after %d retries: %w", rpcMaxRetries, lastErr)
+}
+
+// isTransportError 判断是否为传输层错误（节点不可达、超时、HTTP 非 2xx、连接中断）
+func isTransportError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false // 节点已返回 JSON-RPC 错误
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// markChainDown 标记链的RPC全部不可用；仅在进入该状态时输出一次错误日志，避免每次调用都刷错误
+func (rm *RPCManager) markChainDown(chainID int, lastErr error) {
+	rm.mutex.Lock()
+	_, alreadyDown := rm.downSince[chainID]
+	if !alreadyDown {
+		rm.downSince[chainID] = time.Now()
 	}
+	rm.mutex.Unlock()
 
-	logger.Error("RPC call failed after all retries", lastErr, "chain_id", chainID, "max_retries", 5)
-	return fmt.Errorf("RPC call failed after %d retries: %w", 5, lastErr)
+	if alreadyDown {
+		logger.Warn("RPC call failed after all retries, chain still unavailable", "chain_id", chainID, "error", lastErr)
+		return
+	}
+	logger.Error("All RPCs unavailable for chain", lastErr, "chain_id", chainID, "max_retries", rpcMaxRetries)
+}
+
+// markChainRecovered 链的RPC恢复可用时清除不可用状态并记录恢复日志
+func (rm *RPCManager) markChainRecovered(chainID int) {
+	rm.mutex.RLock()
+	_, down := rm.downSince[chainID]
+	rm.mutex.RUnlock()
+	if !down {
+		return
+	}
+
+	rm.mutex.Lock()
+	since, down := rm.downSince[chainID]
+	delete(rm.downSince, chainID)
+	rm.mutex.Unlock()
+
+	if down {
+		logger.Info("RPC recovered for chain", "chain_id", chainID, "down_duration", time.Since(since).String())
+	}
+}
+
+// IsChainDown 判断链的RPC是否处于全部不可用状态
+func (rm *RPCManager) IsChainDown(chainID int) bool {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+	_, down := rm.downSince[chainID]
+	return down
 }
 
 // removeClient 移除指定链的客户端
@@ -263,5 +342,23 @@ func (rm *RPCManager) GetStatus() map[string]interface{} {
 		status["chains"] = append(status["chains"].([]int), chainID)
 	}
 
+	logsCaps := make([]types.RPCLogsCapability, 0, len(rm.logsCaps))
+	for _, logsCap := range rm.logsCaps {
+		logsCaps = append(logsCaps, logsCap)
+	}
+	status["logs_capabilities"] = logsCaps
+
+	batchCaps := make([]types.RPCBatchCapability, 0, len(rm.batchCaps))
+	for _, batchCap := range rm.batchCaps {
+		batchCaps = append(batchCaps, batchCap)
+	}
+	status["batch_capabilities"] = batchCaps
+
+	downChains := make(map[int]time.Time, len(rm.downSince))
+	for chainID, since := range rm.downSince {
+		downChains[chainID] = since
+	}
+	status["down_chains"] = downChains
+
 	return status
 }
@@ -0,0 +1,192 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/chain"
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// countingChainRepo 只实现 GetRPCEnabledChains，返回空列表使 GetOrCreateClient 失败，并记录回源次数
+type countingChainRepo struct {
+	chain.Repository
+	calls int
+}
+
+func (r *countingChainRepo) GetRPCEnabledChains(ctx context.Context, includeTestnets bool) ([]types.ChainRPCInfo, error) {
+	r.calls++
+	return nil, nil
+}
+
+// fakeJSONRPCError 模拟节点返回的 JSON-RPC 错误（如 execution reverted）
+type fakeJSONRPCError struct{ code int }
+
+func (e fakeJSONRPCError) Error() string  { return "execution reverted" }
+func (e fakeJSONRPCError) ErrorCode() int { return e.code }
+
+const testChainID = 1
+
+func newTestRPCManager(t *testing.T) *RPCManager {
+	t.Helper()
+	rm := NewRPCManager(&config.Config{}, &countingChainRepo{})
+	rm.retryDelay = time.Millisecond
+	installTestClient(t, rm)
+	return rm
+}
+
+// installTestClient 放入一个不会被真正调用的客户端（rpc.DialHTTP 不会建立连接）
+func installTestClient(t *testing.T, rm *RPCManager) {
+	t.Helper()
+	c, err := rpc.DialHTTP("http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("DialHTTP: %v", err)
+	}
+	rm.mutex.Lock()
+	rm.clients[testChainID] = ethclient.NewClient(c)
+	rm.mutex.Unlock()
+}
+
+func hasClient(rm *RPCManager) bool {
+	_, err := rm.GetClient(testChainID)
+	return err == nil
+}
+
+func TestIsTransportError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"wrapped connection reset", fmt.Errorf("call failed: %w", syscall.ECONNRESET), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"http 503", rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}, true},
+		{"http 429", rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, true},
+		{"revert", fakeJSONRPCError{code: 3}, false},
+		{"wrapped json-rpc error", fmt.Errorf("eth_call: %w", fakeJSONRPCError{code: -32000}), false},
+		{"not found", ethereum.NotFound, false},
+		{"abi unpack", errors.New("abi: cannot marshal in to go type"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransportError(tt.err); got != tt.want {
+				t.Fatalf("isTransportError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteWithRetryNonTransportErrorReturnsImmediately(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"revert", fakeJSONRPCError{code: 3}},
+		{"not found", ethereum.NotFound},
+		{"abi unpack", errors.New("abi: attempting to unmarshal an empty string")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := newTestRPCManager(t)
+			calls := 0
+			err := rm.ExecuteWithRetry(context.Background(), testChainID, func(*ethclient.Client) error {
+				calls++
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if calls != 1 {
+				t.Fatalf("fn called %d times, want 1", calls)
+			}
+			if rm.IsChainDown(testChainID) {
+				t.Fatal("chain marked down for a non-transport error")
+			}
+			if !hasClient(rm) {
+				t.Fatal("client removed for a non-transport error")
+			}
+		})
+	}
+}
+
+func TestExecuteWithRetryEntersAndLeavesDownState(t *testing.T) {
+	rm := newTestRPCManager(t)
+
+	// 传输错误：移除客户端并重试，之后获取客户端也失败，重试耗尽后进入不可用状态
+	calls := 0
+	transportErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	err := rm.ExecuteWithRetry(context.Background(), testChainID, func(*ethclient.Client) error {
+		calls++
+		return transportErr
+	})
+	if err == nil {
+		t.Fatal("expected error after retries")
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1 (client removed after transport error)", calls)
+	}
+	if hasClient(rm) {
+		t.Fatal("client not removed after transport error")
+	}
+	if !rm.IsChainDown(testChainID) {
+		t.Fatal("chain not marked down after retries exhausted")
+	}
+
+	// 不可用期间的非传输错误说明节点已恢复响应
+	installTestClient(t, rm)
+	err = rm.ExecuteWithRetry(context.Background(), testChainID, func(*ethclient.Client) error {
+		return fakeJSONRPCError{code: 3}
+	})
+	if err == nil {
+		t.Fatal("expected revert error")
+	}
+	if rm.IsChainDown(testChainID) {
+		t.Fatal("chain still down after node answered")
+	}
+
+	// 再次进入不可用状态，成功调用后恢复
+	err = rm.ExecuteWithRetry(context.Background(), testChainID, func(*ethclient.Client) error {
+		return transportErr
+	})
+	if err == nil || !rm.IsChainDown(testChainID) {
+		t.Fatalf("expected chain down, err = %v", err)
+	}
+	installTestClient(t, rm)
+	if err := rm.ExecuteWithRetry(context.Background(), testChainID, func(*ethclient.Client) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rm.IsChainDown(testChainID) {
+		t.Fatal("chain still down after successful call")
+	}
+}
+
+func TestExecuteWithRetryRetriesTransportError(t *testing.T) {
+	repo := &countingChainRepo{}
+	rm := NewRPCManager(&config.Config{}, repo)
+	rm.retryDelay = time.Millisecond
+	installTestClient(t, rm)
+
+	err := rm.ExecuteWithRetry(context.Background(), testChainID, func(*ethclient.Client) error {
+		return io.ErrUnexpectedEOF
+	})
+	if err == nil {
+		t.Fatal("expected error after retries")
+	}
+	// 第 1 次使用已有客户端，之后 4 次都需要重新获取客户端
+	if repo.calls != rpcMaxRetries-1 {
+		t.Fatalf("GetRPCEnabledChains called %d times, want %d", repo.calls, rpcMaxRetries-1)
+	}
+}
@@ -0,0 +1,126 @@
+package timelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"timelocker-backend/internal/repository/audit"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+)
+
+// BulkUpdateTimeLockRemarks 批量更新合约备注/昵称：逐项校验所有权与内容，单项失败不影响其他项
+func (s *service) BulkUpdateTimeLockRemarks(ctx context.Context, userAddress string, req *types.BulkUpdateTimeLockRemarksRequest) *types.BulkUpdateTimeLockRemarksResponse {
+	normalizedUser := crypto.NormalizeAddress(userAddress)
+	logger.Info("BulkUpdateTimeLockRemarks", "user_address", normalizedUser, "count", len(req.Items))
+
+	response := &types.BulkUpdateTimeLockRemarksResponse{
+		Results: make([]types.BulkUpdateTimeLockRemarkResult, 0, len(req.Items)),
+	}
+	for _, item := range req.Items {
+		result := types.BulkUpdateTimeLockRemarkResult{Standard: item.Standard, ID: item.ID}
+		if err := s.updateTimeLockRemarkByID(ctx, normalizedUser, &item); err != nil {
+			result.ErrorCode = bulkUpdateErrorCode(err)
+			result.Error = err.Error()
+			response.Failed++
+			logger.Warn("BulkUpdateTimeLockRemarks item failed", "user_address", normalizedUser, "standard", item.Standard, "id", item.ID, "error", err)
+		} else {
+			result.Success = true
+			response.Updated++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	logger.Info("BulkUpdateTimeLockRemarks success", "user_address", normalizedUser, "updated", response.Updated, "failed", response.Failed)
+	return response
+}
+
+// updateTimeLockRemarkByID 按主键更新单个合约的备注/昵称，校验规则与 UpdateTimeLock 一致（仅创建者/导入者可修改）
+func (s *service) updateTimeLockRemarkByID(ctx context.Context, normalizedUser string, item *types.BulkUpdateTimeLockRemarkItem) error {
+	if item.Remark == nil && item.Nickname == nil {
+		return fmt.Errorf("%w: remark or nickname is required", ErrInvalidContractParams)
+	}
+	if item.Remark != nil {
+		if err := s.validateRemark(*item.Remark); err != nil {
+			return err
+		}
+	}
+	var sanitizedNickname *string
+	if item.Nickname != nil {
+		if err := s.validateNickname(*item.Nickname); err != nil {
+			return err
+		}
+		nickname := html.EscapeString(strings.TrimSpace(*item.Nickname))
+		sanitizedNickname = &nickname
+	}
+
+	var chainID int
+	var contractAddress, creator, remark string
+	switch item.Standard {
+	case "compound":
+		timeLock, err := s.timeLockRepo.GetCompoundTimeLockByID(ctx, item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get timelock: %w", err)
+		}
+		if timeLock == nil {
+			return ErrTimeLockNotFound
+		}
+		chainID, contractAddress, creator, remark = timeLock.ChainID, timeLock.ContractAddress, timeLock.CreatorAddress, timeLock.Remark
+	case "openzeppelin":
+		timeLock, err := s.timeLockRepo.GetOpenzeppelinTimeLockByID(ctx, item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get timelock: %w", err)
+		}
+		if timeLock == nil {
+			return ErrTimeLockNotFound
+		}
+		chainID, contractAddress, creator, remark = timeLock.ChainID, timeLock.ContractAddress, timeLock.CreatorAddress, timeLock.Remark
+	default:
+		return ErrInvalidStandard
+	}
+	if !strings.EqualFold(creator, normalizedUser) {
+		return ErrUnauthorized
+	}
+	// 未传备注时沿用已保存（已转义）的值
+	if item.Remark != nil {
+		remark = html.EscapeString(strings.TrimSpace(*item.Remark))
+	}
+
+	normalizedContract := crypto.NormalizeAddress(contractAddress)
+	auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetTimelock,
+		timelockAuditTarget(item.Standard, chainID, normalizedContract), "bulk updated remark")
+	var err error
+	if item.Standard == "compound" {
+		err = s.timeLockRepo.UpdateCompoundTimeLockRemark(auditCtx, chainID, normalizedContract, normalizedUser, remark, sanitizedNickname)
+	} else {
+		err = s.timeLockRepo.UpdateOpenzeppelinTimeLockRemark(auditCtx, chainID, normalizedContract, normalizedUser, remark, sanitizedNickname)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update timelock: %w", err)
+	}
+	return nil
+}
+
+// bulkUpdateErrorCode 单项失败原因对应的错误码（与 UpdateTimeLock 接口的错误码一致）
+func bulkUpdateErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrTimeLockNotFound):
+		return "TIMELOCK_NOT_FOUND"
+	case errors.Is(err, ErrUnauthorized):
+		return "UNAUTHORIZED_ACCESS"
+	case errors.Is(err, ErrInvalidRemark):
+		return "INVALID_REMARK"
+	case errors.Is(err, ErrInvalidNickname):
+		return "INVALID_NICKNAME"
+	case errors.Is(err, ErrInvalidStandard):
+		return "INVALID_STANDARD"
+	case errors.Is(err, ErrInvalidContractParams):
+		return "INVALID_PARAMETERS"
+	default:
+		return "INTERNAL_ERROR"
+	}
+}
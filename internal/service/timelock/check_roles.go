@@ -0,0 +1,156 @@
+package timelock
+
+import (
+	"context"
+	"fmt"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// CheckTimeLockRoles 批量查询地址在合约中的角色
+// 角色基于数据库数据计算（与详情接口的 user_permissions 一致）；live=true 时额外实时读取链上角色
+func (s *service) CheckTimeLockRoles(ctx context.Context, userAddress string, id int64, req *types.CheckTimeLockRolesRequest) (*types.CheckTimeLockRolesResponse, error) {
+	logger.Info("CheckTimeLockRoles", "user_address", userAddress, "standard", req.Standard, "id", id, "address_count", len(req.Addresses), "live", req.Live)
+	normalizedUser := crypto.NormalizeAddress(userAddress)
+
+	addresses := make([]string, 0, len(req.Addresses))
+	for _, addr := range req.Addresses {
+		addresses = append(addresses, crypto.NormalizeAddress(addr))
+	}
+
+	switch req.Standard {
+	case "compound":
+		return s.checkCompoundRoles(ctx, normalizedUser, id, addresses, req.Live)
+	case "openzeppelin":
+		return s.checkOpenzeppelinRoles(ctx, normalizedUser, id, addresses, req.Live)
+	default:
+		logger.Error("Invalid standard", fmt.Errorf("invalid standard: %s", req.Standard))
+		return nil, ErrInvalidStandard
+	}
+}
+
+// 私有方法 - 查询Compound合约角色
+func (s *service) checkCompoundRoles(ctx context.Context, userAddress string, id int64, addresses []string, live bool) (*types.CheckTimeLockRolesResponse, error) {
+	timeLock, err := s.timeLockRepo.GetCompoundTimeLockByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timelock: %w", err)
+	}
+	if timeLock == nil {
+		return nil, ErrTimeLockNotFound
+	}
+	if !s.checkCompoundPermission(timeLock, userAddress) {
+		logger.Error("User has no permission to view timelock", ErrUnauthorized, "user_address", userAddress, "id", id)
+		return nil, ErrUnauthorized
+	}
+
+	// 链上角色：用实时读取的 admin/pendingAdmin 替换数据库中的值后复用同一套角色计算
+	var onchainTimeLock *types.CompoundTimeLock
+	if live {
+		data, err := s.readCompoundTimeLockFromChain(ctx, timeLock.ChainID, timeLock.ContractAddress)
+		if err != nil {
+			return nil, wrapOnchainReadError(err)
+		}
+		copied := *timeLock
+		copied.Admin = data.Admin
+		copied.PendingAdmin = data.PendingAdmin
+		onchainTimeLock = &copied
+	}
+
+	results := make([]types.AddressRoles, 0, len(addresses))
+	for _, addr := range addresses {
+		item := types.AddressRoles{Address: addr, Roles: nonNilRoles(s.buildCompoundPermissions(timeLock, addr))}
+		if onchainTimeLock != nil {
+			item.OnchainRoles = nonNilRoles(s.buildCompoundPermissions(onchainTimeLock, addr))
+		}
+		results = append(results, item)
+	}
+
+	return &types.CheckTimeLockRolesResponse{
+		ID:              timeLock.ID,
+		Standard:        "compound",
+		ChainID:         timeLock.ChainID,
+		ContractAddress: timeLock.ContractAddress,
+		Live:            live,
+		Results:         results,
+	}, nil
+}
+
+// 私有方法 - 查询OpenZeppelin合约角色
+func (s *service) checkOpenzeppelinRoles(ctx context.Context, userAddress string, id int64, addresses []string, live bool) (*types.CheckTimeLockRolesResponse, error) {
+	timeLock, err := s.timeLockRepo.GetOpenzeppelinTimeLockByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timelock: %w", err)
+	}
+	if timeLock == nil {
+		return nil, ErrTimeLockNotFound
+	}
+	if !s.checkOpenzeppelinPermission(timeLock, userAddress) {
+		logger.Error("User has no permission to view timelock", ErrUnauthorized, "user_address", userAddress, "id", id)
+		return nil, ErrUnauthorized
+	}
+
+	var client *ethclient.Client
+	if live {
+		client, err = s.rpcManager.GetOrCreateClient(ctx, timeLock.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRPCConnection, err)
+		}
+	}
+	contractAddr := common.HexToAddress(timeLock.ContractAddress)
+
+	results := make([]types.AddressRoles, 0, len(addresses))
+	for _, addr := range addresses {
+		roles := s.buildOpenzeppelinPermissions(timeLock, addr)
+		if timeLock.Admin != "" && timeLock.Admin == addr {
+			roles = append(roles, "admin")
+		}
+		item := types.AddressRoles{Address: addr, Roles: nonNilRoles(roles)}
+
+		if client != nil {
+			onchainRoles := []string{}
+			if timeLock.CreatorAddress == addr {
+				onchainRoles = append(onchainRoles, "creator")
+			}
+			for _, role := range []struct {
+				name string
+				hash common.Hash
+			}{
+				{"proposer", ozProposerRole},
+				{"executor", ozExecutorRole},
+				{"admin", ozDefaultAdminRole},
+			} {
+				holders, err := s.filterRoleHolders(ctx, client, contractAddr, role.hash, []string{addr})
+				if err != nil {
+					return nil, wrapOnchainReadError(err)
+				}
+				if len(holders) > 0 {
+					onchainRoles = append(onchainRoles, role.name)
+				}
+			}
+			item.OnchainRoles = onchainRoles
+		}
+		results = append(results, item)
+	}
+
+	return &types.CheckTimeLockRolesResponse{
+		ID:              timeLock.ID,
+		Standard:        "openzeppelin",
+		ChainID:         timeLock.ChainID,
+		ContractAddress: timeLock.ContractAddress,
+		Live:            live,
+		Results:         results,
+	}, nil
+}
+
+// nonNilRoles 无角色时返回空数组而不是 null
+func nonNilRoles(roles []string) []string {
+	if roles == nil {
+		return []string{}
+	}
+	return roles
+}
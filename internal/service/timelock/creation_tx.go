@@ -0,0 +1,169 @@
+package timelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// contractCreation 从部署交易解析出的合约信息
+type contractCreation struct {
+	ContractAddress string
+	BlockNumber     int64
+	TxHash          string
+}
+
+// callFrame debug_traceTransaction callTracer 的调用帧
+type callFrame struct {
+	Type  string      `json:"type"`
+	To    string      `json:"to"`
+	Error string      `json:"error,omitempty"`
+	Calls []callFrame `json:"calls,omitempty"`
+}
+
+// resolveContractCreation 根据部署交易解析创建的合约地址
+// 直接部署时使用 receipt.ContractAddress；通过工厂合约部署时从 callTracer 中查找 CREATE/CREATE2 调用
+// expectedAddress 非空时要求其为该交易创建的合约之一
+func (s *service) resolveContractCreation(ctx context.Context, chainID int, txHash, expectedAddress string) (*contractCreation, error) {
+	txHash = strings.TrimSpace(txHash)
+	if !utils.IsValidTxHash(txHash) {
+		return nil, fmt.Errorf("%w: invalid creation tx hash", ErrInvalidContractParams)
+	}
+	hash := common.HexToHash(txHash)
+
+	var receipt *ethTypes.Receipt
+	if err := s.rpcManager.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		r, err := client.TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return nil // 交易不存在不重试
+		}
+		if err != nil {
+			return err
+		}
+		receipt = r
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRPCConnection, err)
+	}
+	if receipt == nil {
+		return nil, ErrCreationTxNotFound
+	}
+	if receipt.Status != ethTypes.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: creation transaction reverted", ErrInvalidContractParams)
+	}
+
+	var candidates []string
+	if receipt.ContractAddress != (common.Address{}) {
+		candidates = []string{strings.ToLower(receipt.ContractAddress.Hex())}
+	} else {
+		created, err := s.traceCreatedContracts(ctx, chainID, hash)
+		if err != nil {
+			logger.Warn("Failed to trace creation transaction, trying block explorer", "chain_id", chainID, "tx_hash", txHash, "error", err)
+			created, err = s.explorerCreatedContracts(ctx, chainID, txHash, expectedAddress)
+			if err != nil {
+				logger.Warn("Failed to confirm creation transaction via block explorer", "chain_id", chainID, "tx_hash", txHash, "error", err)
+				return nil, fmt.Errorf("%w: transaction did not deploy a contract directly and trace is unavailable", ErrInvalidContractParams)
+			}
+		}
+		candidates = created
+	}
+
+	address, err := pickCreatedContract(candidates, expectedAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockNumber int64
+	if receipt.BlockNumber != nil {
+		blockNumber = receipt.BlockNumber.Int64()
+	}
+	return &contractCreation{
+		ContractAddress: address,
+		BlockNumber:     blockNumber,
+		TxHash:          strings.ToLower(txHash),
+	}, nil
+}
+
+// traceCreatedContracts 通过 debug_traceTransaction 获取交易中创建的全部合约（节点不支持 trace 时返回错误，不重试）
+func (s *service) traceCreatedContracts(ctx context.Context, chainID int, hash common.Hash) ([]string, error) {
+	client, err := s.rpcManager.GetOrCreateClient(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var root callFrame
+	if err := client.Client().CallContext(ctx, &root, "debug_traceTransaction", hash, map[string]interface{}{
+		"tracer": "callTracer",
+	}); err != nil {
+		return nil, err
+	}
+	return collectCreatedContracts(&root, nil), nil
+}
+
+// explorerCreatedContracts 节点不支持 trace 时向区块浏览器查询 expectedAddress 的创建交易，与 txHash 一致则视为该交易创建
+// 浏览器只能按合约查询，未指定 expectedAddress 或浏览器不可用（未配置、key 限流）时返回错误
+func (s *service) explorerCreatedContracts(ctx context.Context, chainID int, txHash, expectedAddress string) ([]string, error) {
+	expected := strings.ToLower(strings.TrimSpace(expectedAddress))
+	if s.explorer == nil || expected == "" {
+		return nil, fmt.Errorf("block explorer lookup requires contract_address")
+	}
+	creationTx, err := s.explorer.LookupContractCreationTx(ctx, int64(chainID), expected)
+	if err != nil {
+		return nil, err
+	}
+	if creationTx == "" {
+		return nil, fmt.Errorf("block explorer has no creation record for %s", expected)
+	}
+	if !strings.EqualFold(creationTx, txHash) {
+		return nil, nil // 合约由其他交易创建，由 pickCreatedContract 报告
+	}
+	return []string{expected}, nil
+}
+
+// collectCreatedContracts 深度优先收集成功的 CREATE/CREATE2 调用创建的地址
+func collectCreatedContracts(frame *callFrame, created []string) []string {
+	if frame.Error != "" {
+		return created // 回滚的子调用创建的合约不存在
+	}
+	switch strings.ToUpper(frame.Type) {
+	case "CREATE", "CREATE2":
+		if common.IsHexAddress(frame.To) {
+			created = append(created, strings.ToLower(frame.To))
+		}
+	}
+	for i := range frame.Calls {
+		created = collectCreatedContracts(&frame.Calls[i], created)
+	}
+	return created
+}
+
+// pickCreatedContract 从交易创建的合约中确定要导入的地址
+func pickCreatedContract(candidates []string, expectedAddress string) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: transaction did not create any contract", ErrInvalidContractParams)
+	}
+
+	expected := strings.ToLower(strings.TrimSpace(expectedAddress))
+	if expected != "" {
+		for _, c := range candidates {
+			if c == expected {
+				return c, nil
+			}
+		}
+		return "", fmt.Errorf("%w: contract %s was not created by this transaction", ErrInvalidContractParams, expectedAddress)
+	}
+
+	if len(candidates) > 1 {
+		return "", fmt.Errorf("%w: transaction created %d contracts, please specify contract_address", ErrInvalidContractParams, len(candidates))
+	}
+	return candidates[0], nil
+}
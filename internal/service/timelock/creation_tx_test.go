@@ -0,0 +1,271 @@
+package timelock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/chain"
+	"timelocker-backend/internal/service/scanner"
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	testDirectTx   = "0x1000000000000000000000000000000000000000000000000000000000000001"
+	testFactoryTx  = "0x1000000000000000000000000000000000000000000000000000000000000002"
+	testRevertedTx = "0x1000000000000000000000000000000000000000000000000000000000000003"
+	testNoTraceTx  = "0x1000000000000000000000000000000000000000000000000000000000000004"
+	testMissingTx  = "0x1000000000000000000000000000000000000000000000000000000000000005"
+
+	testDeployed  = "0x2222222222222222222222222222222222222222"
+	testFactoryA  = "0x3333333333333333333333333333333333333333"
+	testFactoryB  = "0x4444444444444444444444444444444444444444"
+	testFactoryTo = "0x5555555555555555555555555555555555555555"
+)
+
+// fakeCreationChainRepo 提供链信息与 RPC 地址（指向 JSON-RPC 桩）
+type fakeCreationChainRepo struct {
+	chain.Repository
+	rpcURL string
+	chains map[int64]*types.SupportChain
+}
+
+func (r fakeCreationChainRepo) GetChainByChainID(ctx context.Context, chainID int64) (*types.SupportChain, error) {
+	if c, ok := r.chains[chainID]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("chain not found")
+}
+
+func (r fakeCreationChainRepo) GetRPCEnabledChains(ctx context.Context, includeTestnets bool) ([]types.ChainRPCInfo, error) {
+	url := r.rpcURL
+	return []types.ChainRPCInfo{{ChainID: 1, AlchemyRPCTemplate: &url, RPCEnabled: true}}, nil
+}
+
+// newCreationRPCStub 模拟节点的 eth_getTransactionReceipt 与 debug_traceTransaction
+func newCreationRPCStub(t *testing.T) *httptest.Server {
+	t.Helper()
+	receipt := func(status uint64, contract string) *ethTypes.Receipt {
+		return &ethTypes.Receipt{
+			Status:          status,
+			ContractAddress: common.HexToAddress(contract),
+			BlockNumber:     big.NewInt(123),
+			Logs:            []*ethTypes.Log{},
+		}
+	}
+	receipts := map[string]*ethTypes.Receipt{
+		testDirectTx:   receipt(ethTypes.ReceiptStatusSuccessful, testDeployed),
+		testFactoryTx:  receipt(ethTypes.ReceiptStatusSuccessful, ""),
+		testRevertedTx: receipt(ethTypes.ReceiptStatusFailed, testDeployed),
+		testNoTraceTx:  receipt(ethTypes.ReceiptStatusSuccessful, ""),
+	}
+	trace := callFrame{Type: "CALL", To: testFactoryTo, Calls: []callFrame{
+		{Type: "CREATE2", To: testFactoryA},
+		{Type: "CREATE", To: testFactoryB},
+	}}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var hash string
+		if len(req.Params) > 0 {
+			_ = json.Unmarshal(req.Params[0], &hash)
+		}
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			resp["result"] = "0x1"
+		case "eth_getTransactionReceipt":
+			if rc, ok := receipts[hash]; ok {
+				resp["result"] = rc
+			} else {
+				resp["result"] = nil
+			}
+		case "debug_traceTransaction":
+			if hash == testFactoryTx {
+				resp["result"] = trace
+			} else {
+				resp["error"] = map[string]interface{}{"code": -32601, "message": "the method debug_traceTransaction does not exist"}
+			}
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func newCreationTestService(t *testing.T) *service {
+	t.Helper()
+	srv := newCreationRPCStub(t)
+	t.Cleanup(srv.Close)
+	repo := fakeCreationChainRepo{rpcURL: srv.URL, chains: map[int64]*types.SupportChain{
+		1: {ChainID: 1, ChainName: "test", IsActive: true},
+		2: {ChainID: 2, ChainName: "inactive", IsActive: false},
+	}}
+	return &service{chainRepo: repo, rpcManager: scanner.NewRPCManager(&config.Config{}, repo)}
+}
+
+func TestResolveContractCreation(t *testing.T) {
+	s := newCreationTestService(t)
+
+	tests := []struct {
+		name     string
+		txHash   string
+		expected string
+		want     string
+		wantErr  error
+	}{
+		{"direct deployment", testDirectTx, "", testDeployed, nil},
+		{"direct deployment with expected address", testDirectTx, testDeployed, testDeployed, nil},
+		{"direct deployment with other address", testDirectTx, testFactoryA, "", ErrInvalidContractParams},
+		{"factory deployment picks expected address", testFactoryTx, testFactoryB, testFactoryB, nil},
+		{"factory deployment with several contracts needs address", testFactoryTx, "", "", ErrInvalidContractParams},
+		{"reverted transaction", testRevertedTx, "", "", ErrInvalidContractParams},
+		{"trace unavailable", testNoTraceTx, testFactoryA, "", ErrInvalidContractParams},
+		{"transaction not found", testMissingTx, "", "", ErrCreationTxNotFound},
+		{"invalid hash", "0x1234", "", "", ErrInvalidContractParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.resolveContractCreation(context.Background(), 1, tt.txHash, tt.expected)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveContractCreation: %v", err)
+			}
+			if got.ContractAddress != tt.want || got.BlockNumber != 123 || got.TxHash != tt.txHash {
+				t.Fatalf("unexpected creation %+v", got)
+			}
+		})
+	}
+}
+
+// fakeCreationLookup 模拟区块浏览器按合约查询创建交易
+type fakeCreationLookup struct {
+	txs map[string]string
+	err error
+}
+
+func (l fakeCreationLookup) LookupContractCreationTx(ctx context.Context, chainID int64, contractAddress string) (string, error) {
+	return l.txs[contractAddress], l.err
+}
+
+// TestResolveContractCreationExplorerFallback 节点不支持 trace 时用区块浏览器确认 contract_address 的创建交易
+func TestResolveContractCreationExplorerFallback(t *testing.T) {
+	lookup := fakeCreationLookup{txs: map[string]string{testFactoryA: testNoTraceTx, testFactoryB: testDirectTx}}
+	tests := []struct {
+		name     string
+		explorer CreationTxLookup
+		expected string
+		want     string
+		wantErr  bool
+	}{
+		{"explorer confirms creation tx", lookup, testFactoryA, testFactoryA, false},
+		{"explorer reports a different creation tx", lookup, testFactoryB, "", true},
+		{"explorer has no record", lookup, testDeployed, "", true},
+		{"explorer unavailable", fakeCreationLookup{err: errors.New("explorer api key is rate limited")}, testFactoryA, "", true},
+		{"no explorer configured", nil, testFactoryA, "", true},
+		{"explorer needs contract_address", lookup, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newCreationTestService(t)
+			s.explorer = tt.explorer
+			got, err := s.resolveContractCreation(context.Background(), 1, testNoTraceTx, tt.expected)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidContractParams) {
+					t.Fatalf("error = %v, want ErrInvalidContractParams", err)
+				}
+				return
+			}
+			if err != nil || got.ContractAddress != tt.want {
+				t.Fatalf("resolveContractCreation = %+v, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateOrImportTimeLockChecksChainFirst 不支持的链在解析部署交易之前返回 ErrChainNotSupported
+func TestCreateOrImportTimeLockChecksChainFirst(t *testing.T) {
+	s := newCreationTestService(t)
+	for _, chainID := range []int{2, 999} {
+		// rpcManager 置空：若先解析部署交易会直接 panic
+		s.rpcManager = nil
+		req := &types.CreateOrImportTimelockContractRequest{ChainID: chainID, Standard: "compound", CreationTxHash: testDirectTx}
+		if _, err := s.CreateOrImportTimeLock(context.Background(), testDeployed, req); !errors.Is(err, ErrChainNotSupported) {
+			t.Fatalf("chain %d: error = %v, want ErrChainNotSupported", chainID, err)
+		}
+	}
+}
+
+func TestCollectCreatedContracts(t *testing.T) {
+	root := &callFrame{Type: "CALL", To: testFactoryTo, Calls: []callFrame{
+		{Type: "CREATE", To: "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", Calls: []callFrame{
+			{Type: "create2", To: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+		}},
+		{Type: "CREATE2", To: "0xcccccccccccccccccccccccccccccccccccccccc", Error: "execution reverted", Calls: []callFrame{
+			{Type: "CREATE", To: "0xdddddddddddddddddddddddddddddddddddddddd"},
+		}},
+		{Type: "CREATE", To: "not-an-address"},
+		{Type: "DELEGATECALL", To: "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"},
+	}}
+	want := []string{"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"}
+	if got := collectCreatedContracts(root, nil); !reflect.DeepEqual(got, want) {
+		t.Fatalf("collectCreatedContracts = %v, want %v", got, want)
+	}
+	if got := collectCreatedContracts(&callFrame{Type: "CREATE", To: testDeployed, Error: "out of gas"}, nil); len(got) != 0 {
+		t.Fatalf("reverted root should create nothing, got %v", got)
+	}
+}
+
+func TestPickCreatedContract(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		expected   string
+		want       string
+		wantErr    bool
+	}{
+		{"no candidates", nil, "", "", true},
+		{"single candidate", []string{testDeployed}, "", testDeployed, false},
+		{"expected matches case-insensitively", []string{testFactoryA, testFactoryB}, " 0x4444444444444444444444444444444444444444 ", testFactoryB, false},
+		{"expected not created", []string{testFactoryA}, testFactoryB, "", true},
+		{"ambiguous without expected", []string{testFactoryA, testFactoryB}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pickCreatedContract(tt.candidates, tt.expected)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidContractParams) {
+					t.Fatalf("error = %v, want ErrInvalidContractParams", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("pickCreatedContract = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package timelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+)
+
+// ManifestABIService 合约配置清单使用的 ABI 服务（导出合约关联的 ABI、导入时为用户创建 ABI）
+type ManifestABIService interface {
+	GetContractDecodeABIs(ctx context.Context, walletAddress, standard string, chainID int, contractAddress string) ([]types.ABI, error)
+	GetABIList(ctx context.Context, walletAddress string) (*types.ABIListResponse, error)
+	CreateABI(ctx context.Context, walletAddress string, req *types.CreateABIRequest) (*types.ABIResponse, error)
+}
+
+// ManifestNotificationService 合约配置清单使用的通知渠道开关服务
+type ManifestNotificationService interface {
+	GetNotificationChannelSettings(ctx context.Context, userAddress string) (*types.NotificationChannelSettingsResponse, error)
+	SetNotificationChannelEnabled(ctx context.Context, userAddress, channel string, enabled bool) (*types.NotificationChannelSettingsResponse, error)
+}
+
+var ErrInvalidManifest = errors.New("invalid timelock manifest")
+
+// ABI 导入结果状态
+const (
+	manifestABICreated = "created"
+	manifestABIExists  = "exists"
+	manifestABIFailed  = "failed"
+)
+
+// GetTimeLockManifest 导出合约配置清单（仅导入者可导出）
+// 清单包含合约基本信息、备注、导出者解码该合约流程时使用过的 ABI 与通知渠道开关，不包含任何地址绑定的私有信息与凭证
+func (s *service) GetTimeLockManifest(ctx context.Context, userAddress string, id int64, req *types.GetTimeLockManifestRequest) (*types.TimeLockManifest, error) {
+	logger.Info("GetTimeLockManifest", "user_address", userAddress, "standard", req.Standard, "id", id)
+	normalizedUser := crypto.NormalizeAddress(userAddress)
+
+	manifest := &types.TimeLockManifest{
+		Version:    types.TimeLockManifestVersion,
+		Standard:   req.Standard,
+		ABIs:       []types.TimeLockManifestABI{},
+		ExportedAt: time.Now(),
+	}
+	var creator, status string
+	switch req.Standard {
+	case "compound":
+		timeLock, err := s.timeLockRepo.GetCompoundTimeLockByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get timelock: %w", err)
+		}
+		if timeLock == nil {
+			return nil, ErrTimeLockNotFound
+		}
+		creator, status = timeLock.CreatorAddress, timeLock.Status
+		manifest.ChainID = timeLock.ChainID
+		manifest.ChainName = timeLock.ChainName
+		manifest.ContractAddress = timeLock.ContractAddress
+		manifest.Remark = timeLock.Remark
+		manifest.Nickname = timeLock.Nickname
+	case "openzeppelin":
+		timeLock, err := s.timeLockRepo.GetOpenzeppelinTimeLockByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get timelock: %w", err)
+		}
+		if timeLock == nil {
+			return nil, ErrTimeLockNotFound
+		}
+		creator, status = timeLock.CreatorAddress, timeLock.Status
+		manifest.ChainID = timeLock.ChainID
+		manifest.ChainName = timeLock.ChainName
+		manifest.ContractAddress = timeLock.ContractAddress
+		manifest.Remark = timeLock.Remark
+		manifest.Nickname = timeLock.Nickname
+	default:
+		return nil, ErrInvalidStandard
+	}
+	if status == "deleted" {
+		return nil, ErrTimeLockNotFound
+	}
+	if creator != normalizedUser {
+		logger.Error("User has no permission to export timelock manifest", ErrUnauthorized, "user_address", normalizedUser, "id", id)
+		return nil, ErrUnauthorized
+	}
+
+	if s.abiSvc != nil {
+		abis, err := s.abiSvc.GetContractDecodeABIs(ctx, normalizedUser, req.Standard, manifest.ChainID, manifest.ContractAddress)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range abis {
+			manifest.ABIs = append(manifest.ABIs, types.TimeLockManifestABI{
+				Name:        item.Name,
+				Description: item.Description,
+				ABIContent:  item.ABIContent,
+				IsShared:    item.IsShared,
+			})
+		}
+	}
+
+	if s.channelSvc != nil {
+		settings, err := s.channelSvc.GetNotificationChannelSettings(ctx, normalizedUser)
+		if err != nil {
+			return nil, err
+		}
+		manifest.NotificationDefaults = &types.TimeLockManifestNotificationDefaults{Channels: settings.Channels}
+	}
+
+	return manifest, nil
+}
+
+// ImportTimeLockManifest 导入合约配置清单：按清单创建/导入合约（与 create-or-import 相同的链上校验），
+// 再为当前用户创建清单中的 ABI（已有相同内容时复用），apply_notification_defaults=true 时应用渠道开关
+// 合约导入失败时整体失败；ABI 与渠道开关逐项处理，失败记录在响应中
+func (s *service) ImportTimeLockManifest(ctx context.Context, userAddress string, req *types.ImportTimeLockManifestRequest) (*types.ImportTimeLockManifestResponse, error) {
+	manifest := &req.Manifest
+	manifest.Standard = strings.ToLower(strings.TrimSpace(manifest.Standard))
+	manifest.ContractAddress = strings.TrimSpace(manifest.ContractAddress)
+	if manifest.Version != types.TimeLockManifestVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidManifest, manifest.Version)
+	}
+	if manifest.ChainID <= 0 || !crypto.ValidateEthereumAddress(manifest.ContractAddress) {
+		return nil, fmt.Errorf("%w: chain_id and contract_address are required", ErrInvalidManifest)
+	}
+	for i, item := range manifest.ABIs {
+		if strings.TrimSpace(item.Name) == "" || strings.TrimSpace(item.ABIContent) == "" {
+			return nil, fmt.Errorf("%w: abis[%d] requires name and abi_content", ErrInvalidManifest, i)
+		}
+	}
+
+	timeLock, err := s.CreateOrImportTimeLock(ctx, userAddress, &types.CreateOrImportTimelockContractRequest{
+		Standard:        manifest.Standard,
+		ContractAddress: manifest.ContractAddress,
+		ChainID:         manifest.ChainID,
+		IsImported:      true,
+		Remark:          manifest.Remark,
+		Nickname:        manifest.Nickname,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &types.ImportTimeLockManifestResponse{
+		TimeLock: timeLock,
+		ABIs:     s.importManifestABIs(ctx, userAddress, manifest.ABIs),
+	}
+
+	if req.ApplyNotificationDefaults && manifest.NotificationDefaults != nil && s.channelSvc != nil {
+		for _, channel := range manifest.NotificationDefaults.Channels {
+			if _, err := s.channelSvc.SetNotificationChannelEnabled(ctx, userAddress, string(channel.Channel), channel.Enabled); err != nil {
+				response.Errors = append(response.Errors, fmt.Sprintf("channel %s: %v", channel.Channel, err))
+				continue
+			}
+			response.NotificationDefaultsApplied = true
+		}
+	}
+
+	logger.Info("ImportTimeLockManifest success", "user_address", userAddress, "standard", manifest.Standard, "chain_id", manifest.ChainID,
+		"contract_address", manifest.ContractAddress, "abis", len(manifest.ABIs), "notification_defaults_applied", response.NotificationDefaultsApplied)
+	return response, nil
+}
+
+// importManifestABIs 为用户创建清单中的 ABI：用户已能访问内容相同的 ABI（自己的或共享的）时直接复用
+func (s *service) importManifestABIs(ctx context.Context, userAddress string, items []types.TimeLockManifestABI) []types.ManifestABIImportResult {
+	results := make([]types.ManifestABIImportResult, 0, len(items))
+	if len(items) == 0 {
+		return results
+	}
+	if s.abiSvc == nil {
+		for _, item := range items {
+			results = append(results, types.ManifestABIImportResult{Name: item.Name, Status: manifestABIFailed, Reason: "ABI service not configured"})
+		}
+		return results
+	}
+
+	existing := make(map[string]int64)
+	if list, err := s.abiSvc.GetABIList(ctx, userAddress); err != nil {
+		logger.Warn("Failed to get ABI list for manifest import", "user_address", userAddress, "error", err)
+	} else {
+		for _, item := range list.ABIs {
+			existing[strings.TrimSpace(item.ABIContent)] = item.ID
+		}
+	}
+
+	for _, item := range items {
+		content := strings.TrimSpace(item.ABIContent)
+		if id, ok := existing[content]; ok {
+			results = append(results, types.ManifestABIImportResult{Name: item.Name, Status: manifestABIExists, ABIID: id})
+			continue
+		}
+		created, err := s.abiSvc.CreateABI(ctx, userAddress, &types.CreateABIRequest{
+			Name:        item.Name,
+			ABIContent:  item.ABIContent,
+			Description: item.Description,
+		})
+		if err != nil {
+			results = append(results, types.ManifestABIImportResult{Name: item.Name, Status: manifestABIFailed, Reason: err.Error()})
+			continue
+		}
+		existing[content] = created.ID
+		results = append(results, types.ManifestABIImportResult{Name: item.Name, Status: manifestABICreated, ABIID: created.ID})
+	}
+	return results
+}
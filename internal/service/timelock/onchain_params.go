@@ -0,0 +1,215 @@
+package timelock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// OpenZeppelin TimelockController 角色定义
+var (
+	ozDefaultAdminRole = common.Hash{}                                                                          // DEFAULT_ADMIN_ROLE
+	ozProposerRole     = common.HexToHash("0xb09aa5aeb3702cfd50b6b62bc4532604938f21248a27a1d5ca736082b6819cc1") // PROPOSER_ROLE
+	ozExecutorRole     = common.HexToHash("0xd8aa0f3194971a2a116679f7c2090f6939c8d4e01a2a8d7e41d55e5351469e63") // EXECUTOR_ROLE
+)
+
+// ozParamsABI 实时读取 OpenZeppelin TimelockController 参数所需的 view 方法
+var ozParamsABI abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(`[
+		{"inputs":[],"name":"getMinDelay","outputs":[{"internalType":"uint256","name":"duration","type":"uint256"}],"stateMutability":"view","type":"function"},
+		{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"account","type":"address"}],"name":"hasRole","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}
+	]`))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse openzeppelin timelock ABI: %v", err))
+	}
+	ozParamsABI = parsed
+}
+
+// GetTimeLockOnchainParams 实时读取合约链上参数，并与数据库中保存的值逐项对比
+func (s *service) GetTimeLockOnchainParams(ctx context.Context, userAddress string, id int64, req *types.GetTimeLockOnchainParamsRequest) (*types.GetTimeLockOnchainParamsResponse, error) {
+	logger.Info("GetTimeLockOnchainParams", "user_address", userAddress, "standard", req.Standard, "id", id)
+	normalizedUser := crypto.NormalizeAddress(userAddress)
+
+	switch req.Standard {
+	case "compound":
+		return s.getCompoundOnchainParams(ctx, normalizedUser, id)
+	case "openzeppelin":
+		return s.getOpenzeppelinOnchainParams(ctx, normalizedUser, id)
+	default:
+		logger.Error("Invalid standard", fmt.Errorf("invalid standard: %s", req.Standard))
+		return nil, ErrInvalidStandard
+	}
+}
+
+// 私有方法 - 实时读取Compound合约参数
+func (s *service) getCompoundOnchainParams(ctx context.Context, userAddress string, id int64) (*types.GetTimeLockOnchainParamsResponse, error) {
+	timeLock, err := s.timeLockRepo.GetCompoundTimeLockByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timelock: %w", err)
+	}
+	if timeLock == nil {
+		return nil, ErrTimeLockNotFound
+	}
+	if timeLock.CreatorAddress != userAddress {
+		logger.Error("User has no permission to view timelock onchain params", ErrUnauthorized, "user_address", userAddress, "id", id)
+		return nil, ErrUnauthorized
+	}
+
+	data, err := s.readCompoundTimeLockFromChain(ctx, timeLock.ChainID, timeLock.ContractAddress)
+	if err != nil {
+		return nil, wrapOnchainReadError(err)
+	}
+
+	params := []types.OnchainParamDiff{
+		int64Diff("delay", timeLock.Delay, data.Delay),
+		addressDiff("admin", timeLock.Admin, data.Admin),
+		addressDiff("pending_admin", derefAddress(timeLock.PendingAdmin), derefAddress(data.PendingAdmin)),
+		int64Diff("grace_period", timeLock.GracePeriod, data.GracePeriod),
+		int64Diff("minimum_delay", timeLock.MinimumDelay, data.MinimumDelay),
+		int64Diff("maximum_delay", timeLock.MaximumDelay, data.MaximumDelay),
+	}
+	return buildOnchainParamsResponse(timeLock.ID, "compound", timeLock.ChainID, timeLock.ContractAddress, params), nil
+}
+
+// 私有方法 - 实时读取OpenZeppelin合约参数
+// TimelockController 不支持枚举角色成员，因此角色参数以数据库中的成员为准，通过 hasRole 逐个确认是否仍持有该角色
+func (s *service) getOpenzeppelinOnchainParams(ctx context.Context, userAddress string, id int64) (*types.GetTimeLockOnchainParamsResponse, error) {
+	timeLock, err := s.timeLockRepo.GetOpenzeppelinTimeLockByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timelock: %w", err)
+	}
+	if timeLock == nil {
+		return nil, ErrTimeLockNotFound
+	}
+	if timeLock.CreatorAddress != userAddress {
+		logger.Error("User has no permission to view timelock onchain params", ErrUnauthorized, "user_address", userAddress, "id", id)
+		return nil, ErrUnauthorized
+	}
+
+	client, err := s.rpcManager.GetOrCreateClient(ctx, timeLock.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRPCConnection, err)
+	}
+	contractAddr := common.HexToAddress(timeLock.ContractAddress)
+
+	result, err := s.callContract(ctx, client, contractAddr, ozParamsABI, "getMinDelay")
+	if err != nil {
+		return nil, wrapOnchainReadError(err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: empty getMinDelay result", ErrContractNotTimelock)
+	}
+	delay, ok := result[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("invalid delay type")
+	}
+
+	params := []types.OnchainParamDiff{int64Diff("delay", timeLock.Delay, delay.Int64())}
+	if timeLock.Admin != "" {
+		holders, err := s.filterRoleHolders(ctx, client, contractAddr, ozDefaultAdminRole, []string{timeLock.Admin})
+		if err != nil {
+			return nil, wrapOnchainReadError(err)
+		}
+		params = append(params, roleMembersDiff("admin", []string{timeLock.Admin}, holders))
+	}
+	for _, role := range []struct {
+		field   string
+		hash    common.Hash
+		members string
+	}{
+		{"proposers", ozProposerRole, timeLock.Proposers},
+		{"executors", ozExecutorRole, timeLock.Executors},
+	} {
+		var stored []string
+		if err := json.Unmarshal([]byte(role.members), &stored); err != nil {
+			stored = []string{}
+		}
+		holders, err := s.filterRoleHolders(ctx, client, contractAddr, role.hash, stored)
+		if err != nil {
+			return nil, wrapOnchainReadError(err)
+		}
+		params = append(params, roleMembersDiff(role.field, stored, holders))
+	}
+
+	return buildOnchainParamsResponse(timeLock.ID, "openzeppelin", timeLock.ChainID, timeLock.ContractAddress, params), nil
+}
+
+// 私有方法 - 返回 accounts 中当前在链上仍持有 role 的地址
+func (s *service) filterRoleHolders(ctx context.Context, client *ethclient.Client, contractAddr common.Address, role common.Hash, accounts []string) ([]string, error) {
+	holders := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		result, err := s.callContract(ctx, client, contractAddr, ozParamsABI, "hasRole", role, common.HexToAddress(account))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read role of %s: %w", account, err)
+		}
+		if len(result) > 0 {
+			if has, ok := result[0].(bool); ok && has {
+				holders = append(holders, strings.ToLower(account))
+			}
+		}
+	}
+	return holders, nil
+}
+
+// wrapOnchainReadError 链上读取失败时区分合约不兼容与 RPC 不可用
+func wrapOnchainReadError(err error) error {
+	if errors.Is(err, ErrContractNotTimelock) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrRPCConnection, err)
+}
+
+// buildOnchainParamsResponse 汇总各参数的对比结果
+func buildOnchainParamsResponse(id int64, standard string, chainID int, contractAddress string, params []types.OnchainParamDiff) *types.GetTimeLockOnchainParamsResponse {
+	inSync := true
+	for _, p := range params {
+		if !p.InSync {
+			inSync = false
+			break
+		}
+	}
+	return &types.GetTimeLockOnchainParamsResponse{
+		ID:              id,
+		Standard:        standard,
+		ChainID:         chainID,
+		ContractAddress: contractAddress,
+		InSync:          inSync,
+		Params:          params,
+		CheckedAt:       time.Now(),
+	}
+}
+
+func int64Diff(field string, stored, onchain int64) types.OnchainParamDiff {
+	return types.OnchainParamDiff{Field: field, Stored: stored, Onchain: onchain, InSync: stored == onchain}
+}
+
+func addressDiff(field, stored, onchain string) types.OnchainParamDiff {
+	return types.OnchainParamDiff{Field: field, Stored: stored, Onchain: onchain, InSync: strings.EqualFold(stored, onchain)}
+}
+
+// roleMembersDiff 数据库中的成员全部仍持有角色时视为一致
+func roleMembersDiff(field string, stored, holders []string) types.OnchainParamDiff {
+	return types.OnchainParamDiff{Field: field, Stored: stored, Onchain: holders, InSync: len(stored) == len(holders)}
+}
+
+// derefAddress 空指针与零地址都视为未设置
+func derefAddress(addr *string) string {
+	if addr == nil || common.HexToAddress(*addr) == (common.Address{}) {
+		return ""
+	}
+	return strings.ToLower(*addr)
+}
@@ -0,0 +1,159 @@
+package timelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/crypto"
+	"timelocker-backend/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrInvalidHashParams 计算 txHash / operation id 的参数无效
+var ErrInvalidHashParams = errors.New("invalid hash parameters")
+
+// ComputeCompoundTxHash 按 Compound Timelock 的算法计算 queueTransaction 将产生的 txHash
+// 结果与 flow_id 一致，前端可在索引完成前用它关联待处理交易与流程
+func (s *service) ComputeCompoundTxHash(ctx context.Context, req *types.ComputeCompoundTxHashRequest) (*types.ComputeCompoundTxHashResponse, error) {
+	target, err := parseHashAddress("target", req.Target)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseHashValue("value", req.Value)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parseHashBytes("data", req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := utils.ComputeCompoundTxHash(target, value, strings.TrimSpace(req.Signature), data, big.NewInt(req.Eta))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHashParams, err)
+	}
+	return &types.ComputeCompoundTxHashResponse{TxHash: hash.Hex()}, nil
+}
+
+// ComputeOzOperationId 按 TimelockController.hashOperation 计算单笔操作 id，结果与 flow_id 一致
+func (s *service) ComputeOzOperationId(ctx context.Context, req *types.ComputeOzOperationIdRequest) (*types.ComputeOzOperationIdResponse, error) {
+	target, err := parseHashAddress("target", req.Target)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseHashValue("value", req.Value)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parseHashBytes("data", req.Data)
+	if err != nil {
+		return nil, err
+	}
+	predecessor, err := parseHashBytes32("predecessor", req.Predecessor)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := parseHashBytes32("salt", req.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := utils.ComputeOzOperationId(target, value, data, predecessor, salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHashParams, err)
+	}
+	return &types.ComputeOzOperationIdResponse{OperationID: hash.Hex()}, nil
+}
+
+// ComputeOzOperationBatchId 按 TimelockController.hashOperationBatch 计算批量操作 id，结果与 flow_id 一致
+func (s *service) ComputeOzOperationBatchId(ctx context.Context, req *types.ComputeOzOperationBatchIdRequest) (*types.ComputeOzOperationIdResponse, error) {
+	if len(req.Values) != len(req.Targets) || len(req.Payloads) != len(req.Targets) {
+		return nil, fmt.Errorf("%w: targets, values and payloads must have the same length (%d/%d/%d)",
+			ErrInvalidHashParams, len(req.Targets), len(req.Values), len(req.Payloads))
+	}
+
+	targets := make([]common.Address, len(req.Targets))
+	values := make([]*big.Int, len(req.Targets))
+	payloads := make([][]byte, len(req.Targets))
+	for i := range req.Targets {
+		var err error
+		if targets[i], err = parseHashAddress(fmt.Sprintf("targets[%d]", i), req.Targets[i]); err != nil {
+			return nil, err
+		}
+		if values[i], err = parseHashValue(fmt.Sprintf("values[%d]", i), req.Values[i]); err != nil {
+			return nil, err
+		}
+		if payloads[i], err = parseHashBytes(fmt.Sprintf("payloads[%d]", i), req.Payloads[i]); err != nil {
+			return nil, err
+		}
+	}
+	predecessor, err := parseHashBytes32("predecessor", req.Predecessor)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := parseHashBytes32("salt", req.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := utils.ComputeOzOperationBatchId(targets, values, payloads, predecessor, salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHashParams, err)
+	}
+	return &types.ComputeOzOperationIdResponse{OperationID: hash.Hex()}, nil
+}
+
+// parseHashAddress 解析地址参数
+func parseHashAddress(field, value string) (common.Address, error) {
+	value = strings.TrimSpace(value)
+	if !crypto.ValidateEthereumAddress(value) {
+		return common.Address{}, fmt.Errorf("%w: %s is not a valid address", ErrInvalidHashParams, field)
+	}
+	return common.HexToAddress(value), nil
+}
+
+// parseHashValue 解析十进制 wei 金额，为空时为 0
+func parseHashValue(field, value string) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s must be a non-negative decimal integer", ErrInvalidHashParams, field)
+	}
+	return v, nil
+}
+
+// parseHashBytes 解析 0x 开头的十六进制数据，为空时为空字节
+func parseHashBytes(field, value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0x" {
+		return []byte{}, nil
+	}
+	data, err := hexutil.Decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be 0x-prefixed hex: %v", ErrInvalidHashParams, field, err)
+	}
+	return data, nil
+}
+
+// parseHashBytes32 解析 bytes32 参数，为空时为全零
+func parseHashBytes32(field, value string) ([32]byte, error) {
+	var out [32]byte
+	data, err := parseHashBytes(field, value)
+	if err != nil {
+		return out, err
+	}
+	if len(data) != 0 && len(data) != 32 {
+		return out, fmt.Errorf("%w: %s must be 32 bytes, got %d", ErrInvalidHashParams, field, len(data))
+	}
+	copy(out[:], data)
+	return out, nil
+}
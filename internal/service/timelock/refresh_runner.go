@@ -0,0 +1,132 @@
+package timelock
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"timelocker-backend/pkg/logger"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// refreshProgressEvery 全量刷新每完成多少个合约输出一次进度日志
+const refreshProgressEvery = 50
+
+// refreshTask 单个合约的刷新任务
+type refreshTask struct {
+	standard        string
+	chainID         int
+	contractAddress string
+	refresh         func(ctx context.Context) error
+}
+
+// refreshStats 全量刷新结果统计
+type refreshStats struct {
+	total     int
+	succeeded int64
+	failed    int64
+	skipped   int64 // 因 ctx 取消未执行的任务
+}
+
+// chainRateLimiter 按链限制刷新速率：同一条链相邻两次刷新至少间隔 interval
+type chainRateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     map[int]time.Time
+}
+
+// newChainRateLimiter 创建按链限速器，perSecond <= 0 时返回 nil（不限速）
+func newChainRateLimiter(perSecond float64) *chainRateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &chainRateLimiter{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		next:     make(map[int]time.Time),
+	}
+}
+
+// Wait 预占该链的下一个刷新时间片并等待到达，ctx 取消时立即返回错误
+func (l *chainRateLimiter) Wait(ctx context.Context, chainID int) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[chainID]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[chainID] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// refreshChainRateLimit 取配置的每条链每秒刷新数，未配置时不限速
+func (s *service) refreshChainRateLimit() float64 {
+	if s.cfg != nil && s.cfg.RefreshChainRateLimit > 0 {
+		return s.cfg.RefreshChainRateLimit
+	}
+	return 0
+}
+
+// runRefreshTasks 以有限并发执行刷新任务，并按链限速
+// 单个合约失败只记录日志不中断全量；ctx 取消后不再调度新任务，已在等待限速的任务立即退出
+func (s *service) runRefreshTasks(ctx context.Context, tasks []refreshTask) *refreshStats {
+	stats := &refreshStats{total: len(tasks)}
+	limiter := newChainRateLimiter(s.refreshChainRateLimit())
+
+	var done int64
+	g := new(errgroup.Group)
+	g.SetLimit(s.refreshConcurrency())
+	for i := range tasks {
+		if ctx.Err() != nil {
+			atomic.AddInt64(&stats.skipped, int64(len(tasks)-i))
+			break
+		}
+		task := tasks[i]
+		g.Go(func() error {
+			if err := limiter.Wait(ctx, task.chainID); err != nil {
+				atomic.AddInt64(&stats.skipped, 1)
+				return nil
+			}
+
+			if err := task.refresh(ctx); err != nil {
+				atomic.AddInt64(&stats.failed, 1)
+				switch {
+				case ctx.Err() != nil:
+					// 关停导致的失败不逐合约告警
+				case s.rpcManager.IsChainDown(task.chainID):
+					// 整条链 RPC 不可用时已由 RPCManager 统一告警，这里降级避免逐合约刷错误
+					logger.Warn("Skip refreshing timelock, chain RPC unavailable", "standard", task.standard, "chain_id", task.chainID, "contract_address", task.contractAddress)
+				default:
+					logger.Error("Failed to refresh timelock", err, "standard", task.standard, "chain_id", task.chainID, "contract_address", task.contractAddress)
+				}
+			} else {
+				atomic.AddInt64(&stats.succeeded, 1)
+			}
+
+			if n := atomic.AddInt64(&done, 1); n%refreshProgressEvery == 0 {
+				logger.Info("RefreshAllTimeLockData progress", "done", n, "total", stats.total, "failed", atomic.LoadInt64(&stats.failed))
+			}
+			return nil // 单个失败不中断全量
+		})
+	}
+	_ = g.Wait()
+	return stats
+}
@@ -0,0 +1,144 @@
+package timelock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// reasonContractCodeMissing 合约地址上已没有代码（selfdestruct 等）
+	reasonContractCodeMissing = "contract code no longer exists at this address"
+	// maxStatusReasonLength status_reason 列长度
+	maxStatusReasonLength = 500
+)
+
+// contractHasCode 检查合约地址上是否仍有代码
+func (s *service) contractHasCode(ctx context.Context, chainID int, contractAddress string) (bool, error) {
+	var hasCode bool
+	err := s.rpcManager.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
+		code, err := client.CodeAt(ctx, common.HexToAddress(contractAddress), nil)
+		if err != nil {
+			return err
+		}
+		hasCode = len(code) > 0
+		return nil
+	})
+	return hasCode, err
+}
+
+// compoundChangeReason 比较库中记录与链上最新数据，返回需要告警的异常变化（无异常时返回空）
+// GRACE_PERIOD/MINIMUM_DELAY/MAXIMUM_DELAY 为合约常量，变化说明实现被升级；
+// admin 变为库中记录的 pendingAdmin 属于正常的 acceptAdmin 流程，其他 admin 变化（如两次刷新之间完成的
+// setPendingAdmin + acceptAdmin）需要导入者确认。合约仍是 timelock，只告警不标记 inactive
+func compoundChangeReason(timeLock *types.CompoundTimeLock, data *CompoundTimeLockData) string {
+	var changes []string
+	// 任一方为兜底估算值时宽限期不可比较，由刷新直接修正
+	if !timeLock.GracePeriodEstimated && !data.GracePeriodEstimated && timeLock.GracePeriod != data.GracePeriod {
+		changes = append(changes, fmt.Sprintf("GRACE_PERIOD %d -> %d", timeLock.GracePeriod, data.GracePeriod))
+	}
+	// 链上缺少 MINIMUM_DELAY / MAXIMUM_DELAY 时为默认值，不视为合约常量变化
+	if !data.MinimumDelayDefaulted && timeLock.MinimumDelay != data.MinimumDelay {
+		changes = append(changes, fmt.Sprintf("MINIMUM_DELAY %d -> %d", timeLock.MinimumDelay, data.MinimumDelay))
+	}
+	if !data.MaximumDelayDefaulted && timeLock.MaximumDelay != data.MaximumDelay {
+		changes = append(changes, fmt.Sprintf("MAXIMUM_DELAY %d -> %d", timeLock.MaximumDelay, data.MaximumDelay))
+	}
+
+	oldAdmin := strings.ToLower(timeLock.Admin)
+	newAdmin := strings.ToLower(data.Admin)
+	if oldAdmin != newAdmin {
+		acceptedPending := timeLock.PendingAdmin != nil && strings.ToLower(*timeLock.PendingAdmin) == newAdmin
+		if !acceptedPending {
+			changes = append(changes, fmt.Sprintf("admin %s -> %s without a pending admin transfer", oldAdmin, newAdmin))
+		}
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+	return "contract parameters changed unexpectedly: " + strings.Join(changes, ", ")
+}
+
+// reactivateCompoundTimeLock inactive 合约复核通过时恢复为 active 并清除状态原因（由调用方保存）
+func (s *service) reactivateCompoundTimeLock(timeLock *types.CompoundTimeLock) {
+	if timeLock.Status != "inactive" {
+		return
+	}
+	timeLock.Status = "active"
+	timeLock.StatusReason = nil
+	logger.Info("Compound timelock reactivated after re-verification", "chain_id", timeLock.ChainID, "contract_address", timeLock.ContractAddress, "creator", timeLock.CreatorAddress)
+}
+
+// reactivateOpenzeppelinTimeLock inactive 合约复核通过时恢复为 active 并清除状态原因（由调用方保存）
+func (s *service) reactivateOpenzeppelinTimeLock(timeLock *types.OpenzeppelinTimeLock) {
+	if timeLock.Status != "inactive" {
+		return
+	}
+	timeLock.Status = "active"
+	timeLock.StatusReason = nil
+	logger.Info("OpenZeppelin timelock reactivated after re-verification", "chain_id", timeLock.ChainID, "contract_address", timeLock.ContractAddress, "creator", timeLock.CreatorAddress)
+}
+
+// deactivateCompoundTimeLock 将 Compound timelock 标记为 inactive 并通知导入者
+func (s *service) deactivateCompoundTimeLock(ctx context.Context, timeLock *types.CompoundTimeLock, reason string) error {
+	reason = truncateStatusReason(reason)
+	alreadyInactive := timeLock.Status == "inactive"
+	timeLock.Status = "inactive"
+	timeLock.StatusReason = &reason
+	timeLock.UpdatedAt = time.Now()
+	if err := s.timeLockRepo.UpdateCompoundTimeLock(ctx, timeLock); err != nil {
+		return fmt.Errorf("failed to deactivate compound timelock: %w", err)
+	}
+
+	logger.Warn("Compound timelock marked inactive after re-verification", "chain_id", timeLock.ChainID, "contract_address", timeLock.ContractAddress, "creator", timeLock.CreatorAddress, "reason", reason)
+	if alreadyInactive {
+		return nil // 用户手动刷新已失效的合约时不重复通知
+	}
+	s.notifyContractAlert(ctx, timeLock.CreatorAddress, "compound", timeLock.ChainID, timeLock.ContractAddress, reason, true)
+	return nil
+}
+
+// deactivateOpenzeppelinTimeLock 将 OpenZeppelin timelock 标记为 inactive 并通知导入者
+func (s *service) deactivateOpenzeppelinTimeLock(ctx context.Context, timeLock *types.OpenzeppelinTimeLock, reason string) error {
+	reason = truncateStatusReason(reason)
+	alreadyInactive := timeLock.Status == "inactive"
+	timeLock.Status = "inactive"
+	timeLock.StatusReason = &reason
+	timeLock.UpdatedAt = time.Now()
+	if err := s.timeLockRepo.UpdateOpenzeppelinTimeLock(ctx, timeLock); err != nil {
+		return fmt.Errorf("failed to deactivate openzeppelin timelock: %w", err)
+	}
+
+	logger.Warn("OpenZeppelin timelock marked inactive after re-verification", "chain_id", timeLock.ChainID, "contract_address", timeLock.ContractAddress, "creator", timeLock.CreatorAddress, "reason", reason)
+	if alreadyInactive {
+		return nil // 用户手动刷新已失效的合约时不重复通知
+	}
+	s.notifyContractAlert(ctx, timeLock.CreatorAddress, "openzeppelin", timeLock.ChainID, timeLock.ContractAddress, reason, true)
+	return nil
+}
+
+// notifyContractAlert 通知导入者合约复核异常（deactivated 表示已被标记为 inactive），失败只记录日志
+func (s *service) notifyContractAlert(ctx context.Context, userAddress, standard string, chainID int, contractAddress, reason string, deactivated bool) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendContractAlert(ctx, userAddress, standard, chainID, contractAddress, reason, deactivated); err != nil {
+		logger.Error("Failed to send contract alert", err, "user_address", userAddress, "chain_id", chainID, "contract_address", contractAddress, "deactivated", deactivated)
+	}
+}
+
+// truncateStatusReason 截断到 status_reason 列长度
+func truncateStatusReason(reason string) string {
+	if len(reason) <= maxStatusReasonLength {
+		return reason
+	}
+	return reason[:maxStatusReasonLength-3] + "..."
+}
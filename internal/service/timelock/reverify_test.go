@@ -0,0 +1,198 @@
+package timelock
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"timelocker-backend/internal/service/scanner"
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func strPtr(s string) *string { return &s }
+
+func baseCompoundTimeLock() *types.CompoundTimeLock {
+	return &types.CompoundTimeLock{
+		Admin:        "0x1111111111111111111111111111111111111111",
+		GracePeriod:  14 * 24 * 3600,
+		MinimumDelay: 2 * 24 * 3600,
+		MaximumDelay: 30 * 24 * 3600,
+	}
+}
+
+func baseCompoundData() *CompoundTimeLockData {
+	return &CompoundTimeLockData{
+		Delay:        2 * 24 * 3600,
+		Admin:        "0x1111111111111111111111111111111111111111",
+		GracePeriod:  14 * 24 * 3600,
+		MinimumDelay: 2 * 24 * 3600,
+		MaximumDelay: 30 * 24 * 3600,
+	}
+}
+
+func TestCompoundChangeReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(tl *types.CompoundTimeLock, data *CompoundTimeLockData)
+		contains []string // 为空表示期望无变化
+	}{
+		{
+			name:   "unchanged",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {},
+		},
+		{
+			name: "delay change is a normal setDelay",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				tl.Delay = 3600
+				data.Delay = 7200
+			},
+		},
+		{
+			name: "grace period changed",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				data.GracePeriod = 7 * 24 * 3600
+			},
+			contains: []string{"GRACE_PERIOD 1209600 -> 604800"},
+		},
+		{
+			name: "stored grace period was estimated",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				tl.GracePeriodEstimated = true
+				data.GracePeriod = 7 * 24 * 3600
+			},
+		},
+		{
+			name: "fresh grace period is estimated",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				data.GracePeriodEstimated = true
+				data.GracePeriod = 7 * 24 * 3600
+			},
+		},
+		{
+			name: "minimum and maximum delay changed",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				data.MinimumDelay = 3600
+				data.MaximumDelay = 60 * 24 * 3600
+			},
+			contains: []string{"MINIMUM_DELAY 172800 -> 3600", "MAXIMUM_DELAY 2592000 -> 5184000"},
+		},
+		{
+			name: "admin accepted from pending admin",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				tl.PendingAdmin = strPtr("0x2222222222222222222222222222222222222222")
+				data.Admin = "0x2222222222222222222222222222222222222222"
+			},
+		},
+		{
+			name: "admin comparison is case-insensitive",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				tl.Admin = "0xABCDEFABCDEFABCDEFABCDEFABCDEFABCDEFABCD"
+				data.Admin = "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"
+			},
+		},
+		{
+			name: "admin changed without pending transfer",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				data.Admin = "0x3333333333333333333333333333333333333333"
+			},
+			contains: []string{"admin 0x1111111111111111111111111111111111111111 -> 0x3333333333333333333333333333333333333333 without a pending admin transfer"},
+		},
+		{
+			name: "admin changed to someone other than pending admin",
+			mutate: func(tl *types.CompoundTimeLock, data *CompoundTimeLockData) {
+				tl.PendingAdmin = strPtr("0x2222222222222222222222222222222222222222")
+				data.Admin = "0x3333333333333333333333333333333333333333"
+			},
+			contains: []string{"admin 0x1111111111111111111111111111111111111111 -> 0x3333333333333333333333333333333333333333"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl, data := baseCompoundTimeLock(), baseCompoundData()
+			tt.mutate(tl, data)
+			reason := compoundChangeReason(tl, data)
+			if len(tt.contains) == 0 {
+				if reason != "" {
+					t.Fatalf("compoundChangeReason = %q, want no change", reason)
+				}
+				return
+			}
+			if !strings.HasPrefix(reason, "contract parameters changed unexpectedly: ") {
+				t.Fatalf("compoundChangeReason = %q, want parameter change reason", reason)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(reason, want) {
+					t.Fatalf("compoundChangeReason = %q, want it to contain %q", reason, want)
+				}
+			}
+		})
+	}
+}
+
+// compoundResult 按 compoundCallOrder 构造成功的子调用结果
+func compoundResult(t *testing.T, method string, value interface{}) scanner.Call3Result {
+	t.Helper()
+	out, err := compoundTimelockABI.Methods[method].Outputs.Pack(value)
+	if err != nil {
+		t.Fatalf("pack %s: %v", method, err)
+	}
+	return scanner.Call3Result{Success: true, ReturnData: out}
+}
+
+func TestDecodeCompoundResults(t *testing.T) {
+	admin := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	full := func() []scanner.Call3Result {
+		return []scanner.Call3Result{
+			compoundResult(t, "delay", big.NewInt(172800)),
+			compoundResult(t, "admin", admin),
+			compoundResult(t, "pendingAdmin", common.Address{}),
+			compoundResult(t, "GRACE_PERIOD", big.NewInt(1209600)),
+			compoundResult(t, "MINIMUM_DELAY", big.NewInt(172800)),
+			compoundResult(t, "MAXIMUM_DELAY", big.NewInt(2592000)),
+		}
+	}
+
+	data, err := decodeCompoundResults(full())
+	if err != nil {
+		t.Fatalf("decodeCompoundResults: %v", err)
+	}
+	if data.Delay != 172800 || data.Admin != strings.ToLower(admin.Hex()) || data.PendingAdmin != nil ||
+		data.GracePeriod != 1209600 || data.MinimumDelay != 172800 || data.MaximumDelay != 2592000 {
+		t.Fatalf("unexpected data %+v", data)
+	}
+
+	// 可选方法失败时保留零值，由 applyCompoundDefaults 填充
+	results := full()
+	for i := 2; i < len(results); i++ {
+		results[i] = scanner.Call3Result{Success: false}
+	}
+	data, err = decodeCompoundResults(results)
+	if err != nil {
+		t.Fatalf("decodeCompoundResults with optional failures: %v", err)
+	}
+	if data.GracePeriod != 0 || data.MinimumDelay != 0 || data.MaximumDelay != 0 || data.PendingAdmin != nil {
+		t.Fatalf("optional fields should be empty, got %+v", data)
+	}
+
+	// 必需方法 revert 或返回无法解码的数据时判定为非 timelock
+	for _, idx := range []int{0, 1} {
+		results := full()
+		results[idx] = scanner.Call3Result{Success: false}
+		if _, err := decodeCompoundResults(results); !errors.Is(err, ErrContractNotTimelock) {
+			t.Fatalf("failed %s: error = %v, want ErrContractNotTimelock", compoundCallOrder[idx], err)
+		}
+
+		results = full()
+		results[idx] = scanner.Call3Result{Success: true, ReturnData: []byte{0x01}}
+		if _, err := decodeCompoundResults(results); !errors.Is(err, ErrContractNotTimelock) {
+			t.Fatalf("garbage %s: error = %v, want ErrContractNotTimelock", compoundCallOrder[idx], err)
+		}
+	}
+
+	if _, err := decodeCompoundResults(full()[:3]); err == nil {
+		t.Fatal("expected error for short result list")
+	}
+}
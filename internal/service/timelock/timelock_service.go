@@ -8,10 +8,13 @@ import (
 	"html"
 	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/audit"
 	"timelocker-backend/internal/repository/chain"
 	"timelocker-backend/internal/repository/timelock"
 	"timelocker-backend/internal/service/scanner"
@@ -23,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
@@ -30,6 +34,17 @@ import (
 // GoldskyService Goldsky服务接口（用于同步flow）
 type GoldskyService interface {
 	SyncFlowsForContract(ctx context.Context, chainID int, standard, contractAddress string) error
+	ApplyCompoundGracePeriod(ctx context.Context, chainID int, contractAddress string, gracePeriod int64, estimated bool) error
+}
+
+// CreationTxLookup 区块浏览器查询合约创建交易的接口（部署交易无法 trace 时用于确认合约创建者）
+type CreationTxLookup interface {
+	LookupContractCreationTx(ctx context.Context, chainID int64, contractAddress string) (string, error)
+}
+
+// ContractAlertNotifier 合约告警通知接口（用于合约复核失败时通知导入者）
+type ContractAlertNotifier interface {
+	SendContractAlert(ctx context.Context, userAddress, standard string, chainID int, contractAddress, reason string, deactivated bool) error
 }
 
 var (
@@ -39,11 +54,13 @@ var (
 	ErrInvalidStandard       = errors.New("invalid contract standard")
 	ErrUnauthorized          = errors.New("unauthorized access")
 	ErrInvalidRemark         = errors.New("invalid remark content")
+	ErrInvalidNickname       = errors.New("invalid nickname content")
 	ErrInvalidContractParams = errors.New("invalid contract parameters")
 	ErrInvalidPermissions    = errors.New("insufficient permissions")
 	ErrChainNotSupported     = errors.New("chain not supported")
 	ErrRPCConnection         = errors.New("failed to connect to RPC")
 	ErrContractNotTimelock   = errors.New("contract is not a valid timelock")
+	ErrCreationTxNotFound    = errors.New("creation transaction not found")
 )
 
 // Service timelock服务接口
@@ -57,8 +74,22 @@ type Service interface {
 	// 获取timelock详情
 	GetTimeLockDetail(ctx context.Context, userAddress string, req *types.GetTimeLockDetailRequest) (*types.GetTimeLockDetailResponse, error)
 
+	// 实时读取合约链上参数并与数据库中的值对比（仅导入者可查看）
+	GetTimeLockOnchainParams(ctx context.Context, userAddress string, id int64, req *types.GetTimeLockOnchainParamsRequest) (*types.GetTimeLockOnchainParamsResponse, error)
+
+	// 批量查询地址在合约中的角色（基于数据库数据，可选实时读取链上角色）
+	CheckTimeLockRoles(ctx context.Context, userAddress string, id int64, req *types.CheckTimeLockRolesRequest) (*types.CheckTimeLockRolesResponse, error)
+
+	// 检查合约是否已被当前用户导入
+	CheckTimeLockExists(ctx context.Context, userAddress string, req *types.CheckTimeLockExistsRequest) (*types.CheckTimeLockExistsResponse, error)
+
+	// 获取用户有合约的链（前端链筛选）
+	GetTimeLockChains(ctx context.Context, userAddress string) (*types.GetTimeLockChainsResponse, error)
+
 	// 更新timelock备注
 	UpdateTimeLock(ctx context.Context, userAddress string, req *types.UpdateTimeLockRequest) error
+	// 批量更新timelock备注/昵称，返回逐项结果
+	BulkUpdateTimeLockRemarks(ctx context.Context, userAddress string, req *types.BulkUpdateTimeLockRemarksRequest) *types.BulkUpdateTimeLockRemarksResponse
 
 	// 删除timelock
 	DeleteTimeLock(ctx context.Context, userAddress string, req *types.DeleteTimeLockRequest) error
@@ -66,6 +97,17 @@ type Service interface {
 	// 刷新用户所有timelock合约权限
 	RefreshTimeLockPermissions(ctx context.Context, userAddress string) error
 
+	// 计算 Compound queueTransaction 将产生的 txHash
+	ComputeCompoundTxHash(ctx context.Context, req *types.ComputeCompoundTxHashRequest) (*types.ComputeCompoundTxHashResponse, error)
+
+	// 计算 OpenZeppelin 单笔/批量操作的 id（hashOperation / hashOperationBatch）
+	ComputeOzOperationId(ctx context.Context, req *types.ComputeOzOperationIdRequest) (*types.ComputeOzOperationIdResponse, error)
+	ComputeOzOperationBatchId(ctx context.Context, req *types.ComputeOzOperationBatchIdRequest) (*types.ComputeOzOperationIdResponse, error)
+
+	// 导出可分享的合约配置清单 / 导入他人分享的清单
+	GetTimeLockManifest(ctx context.Context, userAddress string, id int64, req *types.GetTimeLockManifestRequest) (*types.TimeLockManifest, error)
+	ImportTimeLockManifest(ctx context.Context, userAddress string, req *types.ImportTimeLockManifestRequest) (*types.ImportTimeLockManifestResponse, error)
+
 	// 刷新所有timelock合约数据（定时任务）
 	RefreshAllTimeLockData(ctx context.Context) error
 }
@@ -75,16 +117,24 @@ type service struct {
 	chainRepo    chain.Repository
 	rpcManager   *scanner.RPCManager
 	goldskySvc   GoldskyService
+	notifier     ContractAlertNotifier
+	abiSvc       ManifestABIService
+	channelSvc   ManifestNotificationService
+	explorer     CreationTxLookup
 	cfg          *config.TimelockConfig
 }
 
 // NewService 创建timelock服务实例
-func NewService(timeLockRepo timelock.Repository, chainRepo chain.Repository, rpcManager *scanner.RPCManager, goldskySvc GoldskyService, cfg *config.TimelockConfig) Service {
+func NewService(timeLockRepo timelock.Repository, chainRepo chain.Repository, rpcManager *scanner.RPCManager, goldskySvc GoldskyService, notifier ContractAlertNotifier, abiSvc ManifestABIService, channelSvc ManifestNotificationService, explorer CreationTxLookup, cfg *config.TimelockConfig) Service {
 	return &service{
 		timeLockRepo: timeLockRepo,
 		chainRepo:    chainRepo,
 		rpcManager:   rpcManager,
 		goldskySvc:   goldskySvc,
+		notifier:     notifier,
+		abiSvc:       abiSvc,
+		channelSvc:   channelSvc,
+		explorer:     explorer,
 		cfg:          cfg,
 	}
 }
@@ -99,6 +149,25 @@ func (s *service) refreshConcurrency() int {
 
 // CreateOrImportTimeLock 创建或导入timelock合约记录
 func (s *service) CreateOrImportTimeLock(ctx context.Context, userAddress string, req *types.CreateOrImportTimelockContractRequest) (interface{}, error) {
+	// 先确认链受支持，避免对不支持的链发起 RPC 调用
+	chainInfo, err := s.getSupportedChain(ctx, req.ChainID)
+	if err != nil {
+		logger.Error("Failed to get chain info", err, "chain_id", req.ChainID)
+		return nil, err
+	}
+
+	// 提供部署交易时，从交易回执解析合约地址
+	var creation *contractCreation
+	if strings.TrimSpace(req.CreationTxHash) != "" {
+		resolved, err := s.resolveContractCreation(ctx, req.ChainID, req.CreationTxHash, req.ContractAddress)
+		if err != nil {
+			logger.Error("CreateOrImportTimeLock resolve creation tx error", err, "chain_id", req.ChainID, "tx_hash", req.CreationTxHash)
+			return nil, err
+		}
+		creation = resolved
+		req.ContractAddress = resolved.ContractAddress
+	}
+
 	// 标准化地址
 	normalizedUser := crypto.NormalizeAddress(userAddress)
 	normalizedContract := crypto.NormalizeAddress(req.ContractAddress)
@@ -115,25 +184,33 @@ func (s *service) CreateOrImportTimeLock(ctx context.Context, userAddress string
 		return nil, err
 	}
 
-	// 获取链信息
-	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(req.ChainID))
-	if err != nil {
-		logger.Error("Failed to get chain info", err, "chain_id", req.ChainID)
-		return nil, fmt.Errorf("failed to get chain info: %w", err)
-	}
-
 	// 从链上读取合约数据并验证
 	switch req.Standard {
 	case "compound":
-		return s.createOrImportCompoundTimeLock(ctx, normalizedUser, normalizedContract, req, chainInfo)
+		return s.createOrImportCompoundTimeLock(ctx, normalizedUser, normalizedContract, req, chainInfo, creation)
 	case "openzeppelin":
-		return s.createOrImportOpenzeppelinTimeLock(ctx, normalizedUser, normalizedContract, req, chainInfo)
+		return s.createOrImportOpenzeppelinTimeLock(ctx, normalizedUser, normalizedContract, req, chainInfo, creation)
 	default:
 		logger.Error("Invalid standard", fmt.Errorf("invalid standard: %s", req.Standard))
 		return nil, ErrInvalidStandard
 	}
 }
 
+// getSupportedChain 获取链信息，链不存在或未激活时返回 ErrChainNotSupported
+func (s *service) getSupportedChain(ctx context.Context, chainID int) (*types.SupportChain, error) {
+	chainInfo, err := s.chainRepo.GetChainByChainID(ctx, int64(chainID))
+	if err != nil {
+		if err.Error() == "chain not found" { // 链仓库对不存在的链返回该文本错误
+			return nil, fmt.Errorf("%w: %d", ErrChainNotSupported, chainID)
+		}
+		return nil, fmt.Errorf("failed to get chain info: %w", err)
+	}
+	if !chainInfo.IsActive {
+		return nil, fmt.Errorf("%w: %d is not active", ErrChainNotSupported, chainID)
+	}
+	return chainInfo, nil
+}
+
 // GetTimeLockList 获取timelock列表（根据用户权限筛选）
 func (s *service) GetTimeLockList(ctx context.Context, userAddress string, req *types.GetTimeLockListRequest) (*types.GetTimeLockListResponse, error) {
 	logger.Info("GetTimeLockList", "user_address", userAddress, "standard", req.Standard, "status", req.Status)
@@ -148,10 +225,16 @@ func (s *service) GetTimeLockList(ctx context.Context, userAddress string, req *
 		return nil, fmt.Errorf("failed to get timelock list: %w", err)
 	}
 
+	// 列表不分页，单页即全部；空列表时 page_size 至少为 1，避免返回 0
+	pageSize := int(total)
+	if pageSize < 1 {
+		pageSize = 1
+	}
 	response := &types.GetTimeLockListResponse{
 		CompoundTimeLocks:     compoundList,
 		OpenzeppelinTimeLocks: openzeppelinList,
 		Total:                 total,
+		PaginationMeta:        types.NewPaginationMeta(total, 1, pageSize),
 	}
 
 	logger.Info("GetTimeLockList success", "user_address", normalizedUser, "total", total, "compound_count", len(compoundList), "openzeppelin_count", len(openzeppelinList))
@@ -177,6 +260,39 @@ func (s *service) GetTimeLockDetail(ctx context.Context, userAddress string, req
 	}
 }
 
+// CheckTimeLockExists 检查合约是否已被当前用户导入（两种标准都查）；已删除的合约为物理删除，返回未导入
+func (s *service) CheckTimeLockExists(ctx context.Context, userAddress string, req *types.CheckTimeLockExistsRequest) (*types.CheckTimeLockExistsResponse, error) {
+	normalizedUser := crypto.NormalizeAddress(userAddress)
+	normalizedContract := crypto.NormalizeAddress(req.ContractAddress)
+
+	compoundTimeLock, err := s.timeLockRepo.GetCompoundTimeLockByCreator(ctx, req.ChainID, normalizedContract, normalizedUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check compound timelock: %w", err)
+	}
+	if compoundTimeLock != nil {
+		return &types.CheckTimeLockExistsResponse{Exists: true, Standard: "compound", ID: compoundTimeLock.ID, Status: compoundTimeLock.Status}, nil
+	}
+
+	ozTimeLock, err := s.timeLockRepo.GetOpenzeppelinTimeLockByCreator(ctx, req.ChainID, normalizedContract, normalizedUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check openzeppelin timelock: %w", err)
+	}
+	if ozTimeLock != nil {
+		return &types.CheckTimeLockExistsResponse{Exists: true, Standard: "openzeppelin", ID: ozTimeLock.ID, Status: ozTimeLock.Status}, nil
+	}
+
+	return &types.CheckTimeLockExistsResponse{Exists: false}, nil
+}
+
+// GetTimeLockChains 获取用户有合约的链（未删除的合约，范围与合约列表一致）
+func (s *service) GetTimeLockChains(ctx context.Context, userAddress string) (*types.GetTimeLockChainsResponse, error) {
+	chains, err := s.timeLockRepo.GetUserTimeLockChains(ctx, crypto.NormalizeAddress(userAddress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timelock chains: %w", err)
+	}
+	return &types.GetTimeLockChainsResponse{Chains: chains}, nil
+}
+
 // UpdateTimeLock 更新timelock备注
 func (s *service) UpdateTimeLock(ctx context.Context, userAddress string, req *types.UpdateTimeLockRequest) error {
 	logger.Info("UpdateTimeLock", "user_address", userAddress, "standard", req.Standard, "chain_id", req.ChainID, "contract_address", req.ContractAddress)
@@ -193,6 +309,17 @@ func (s *service) UpdateTimeLock(ctx context.Context, userAddress string, req *t
 
 	sanitizedRemark := html.EscapeString(strings.TrimSpace(req.Remark))
 
+	// 验证昵称（不传时保持不变）
+	var sanitizedNickname *string
+	if req.Nickname != nil {
+		if err := s.validateNickname(*req.Nickname); err != nil {
+			logger.Error("UpdateTimeLock nickname validation error", err, "user_address", normalizedUser)
+			return err
+		}
+		nickname := html.EscapeString(strings.TrimSpace(*req.Nickname))
+		sanitizedNickname = &nickname
+	}
+
 	switch req.Standard {
 	case "compound":
 		// 验证所有权（创建者或导入者）
@@ -206,7 +333,9 @@ func (s *service) UpdateTimeLock(ctx context.Context, userAddress string, req *t
 			return ErrUnauthorized
 		}
 
-		if err := s.timeLockRepo.UpdateCompoundTimeLockRemark(ctx, req.ChainID, normalizedContract, normalizedUser, sanitizedRemark); err != nil {
+		auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetTimelock,
+			timelockAuditTarget(req.Standard, req.ChainID, normalizedContract), "updated remark")
+		if err := s.timeLockRepo.UpdateCompoundTimeLockRemark(auditCtx, req.ChainID, normalizedContract, normalizedUser, sanitizedRemark, sanitizedNickname); err != nil {
 			logger.Error("UpdateTimeLock repository error", err, "user_address", normalizedUser)
 			return fmt.Errorf("failed to update timelock: %w", err)
 		}
@@ -223,7 +352,9 @@ func (s *service) UpdateTimeLock(ctx context.Context, userAddress string, req *t
 			return ErrUnauthorized
 		}
 
-		if err := s.timeLockRepo.UpdateOpenzeppelinTimeLockRemark(ctx, req.ChainID, normalizedContract, normalizedUser, sanitizedRemark); err != nil {
+		auditCtx := audit.WithLog(ctx, types.AuditActionUpdate, types.AuditTargetTimelock,
+			timelockAuditTarget(req.Standard, req.ChainID, normalizedContract), "updated remark")
+		if err := s.timeLockRepo.UpdateOpenzeppelinTimeLockRemark(auditCtx, req.ChainID, normalizedContract, normalizedUser, sanitizedRemark, sanitizedNickname); err != nil {
 			logger.Error("UpdateTimeLock repository error", err, "user_address", normalizedUser)
 			return fmt.Errorf("failed to update timelock: %w", err)
 		}
@@ -257,7 +388,9 @@ func (s *service) DeleteTimeLock(ctx context.Context, userAddress string, req *t
 			return ErrUnauthorized
 		}
 
-		if err := s.timeLockRepo.DeleteCompoundTimeLock(ctx, req.ChainID, normalizedContract, normalizedUser); err != nil {
+		auditCtx := audit.WithLog(ctx, types.AuditActionDelete, types.AuditTargetTimelock,
+			timelockAuditTarget(req.Standard, req.ChainID, normalizedContract), "deleted compound timelock")
+		if err := s.timeLockRepo.DeleteCompoundTimeLock(auditCtx, req.ChainID, normalizedContract, normalizedUser); err != nil {
 			logger.Error("DeleteTimeLock repository error", err, "user_address", normalizedUser)
 			return fmt.Errorf("failed to delete timelock: %w", err)
 		}
@@ -274,7 +407,9 @@ func (s *service) DeleteTimeLock(ctx context.Context, userAddress string, req *t
 			return ErrUnauthorized
 		}
 
-		if err := s.timeLockRepo.DeleteOpenzeppelinTimeLock(ctx, req.ChainID, normalizedContract, normalizedUser); err != nil {
+		auditCtx := audit.WithLog(ctx, types.AuditActionDelete, types.AuditTargetTimelock,
+			timelockAuditTarget(req.Standard, req.ChainID, normalizedContract), "deleted openzeppelin timelock")
+		if err := s.timeLockRepo.DeleteOpenzeppelinTimeLock(auditCtx, req.ChainID, normalizedContract, normalizedUser); err != nil {
 			logger.Error("DeleteTimeLock repository error", err, "user_address", normalizedUser)
 			return fmt.Errorf("failed to delete timelock: %w", err)
 		}
@@ -345,67 +480,78 @@ func (s *service) RefreshAllTimeLockData(ctx context.Context) error {
 	start := time.Now()
 	logger.Info("RefreshAllTimeLockData started")
 
-	// 获取所有活跃的Compound timelock合约
-	compoundTimelocks, err := s.timeLockRepo.GetAllActiveCompoundTimeLocks(ctx)
+	// 获取所有需要刷新的Compound timelock合约（inactive 合约也重新复核，通过后恢复为 active）
+	compoundTimelocks, err := s.timeLockRepo.GetAllRefreshableCompoundTimeLocks(ctx)
 	if err != nil {
 		logger.Error("Failed to get all compound timelocks", err)
 		return fmt.Errorf("failed to get compound timelocks: %w", err)
 	}
 
-	// 获取所有活跃的OpenZeppelin timelock合约
-	openzeppelinTimelocks, err := s.timeLockRepo.GetAllActiveOpenzeppelinTimeLocks(ctx)
+	// 获取所有需要刷新的OpenZeppelin timelock合约
+	openzeppelinTimelocks, err := s.timeLockRepo.GetAllRefreshableOpenzeppelinTimeLocks(ctx)
 	if err != nil {
 		logger.Error("Failed to get all openzeppelin timelocks", err)
 		return fmt.Errorf("failed to get openzeppelin timelocks: %w", err)
 	}
 
-	concurrency := s.refreshConcurrency()
-
-	// 并发刷新 Compound 合约数据（每个合约 1 次 Multicall3 RPC）
-	compoundGroup, compoundCtx := errgroup.WithContext(ctx)
-	compoundGroup.SetLimit(concurrency)
+	// Compound 每个合约 1 次 Multicall3 RPC；按导入顺序先 Compound 后 OpenZeppelin
+	tasks := make([]refreshTask, 0, len(compoundTimelocks)+len(openzeppelinTimelocks))
 	for i := range compoundTimelocks {
-		tl := compoundTimelocks[i]
-		compoundGroup.Go(func() error {
-			if err := s.refreshCompoundTimeLockData(compoundCtx, &tl); err != nil {
-				logger.Error("Failed to refresh compound timelock", err, "contract_address", tl.ContractAddress)
-			}
-			return nil // 单个失败不中断全量
+		tl := &compoundTimelocks[i]
+		tasks = append(tasks, refreshTask{
+			standard:        "compound",
+			chainID:         tl.ChainID,
+			contractAddress: tl.ContractAddress,
+			refresh:         func(ctx context.Context) error { return s.refreshCompoundTimeLockData(ctx, tl) },
 		})
 	}
-	_ = compoundGroup.Wait()
-
-	// 并发刷新 OpenZeppelin 合约数据
-	ozGroup, ozCtx := errgroup.WithContext(ctx)
-	ozGroup.SetLimit(concurrency)
 	for i := range openzeppelinTimelocks {
-		tl := openzeppelinTimelocks[i]
-		ozGroup.Go(func() error {
-			if err := s.refreshOpenzeppelinTimeLockData(ozCtx, &tl); err != nil {
-				logger.Error("Failed to refresh openzeppelin timelock", err, "contract_address", tl.ContractAddress)
-			}
-			return nil
+		tl := &openzeppelinTimelocks[i]
+		tasks = append(tasks, refreshTask{
+			standard:        "openzeppelin",
+			chainID:         tl.ChainID,
+			contractAddress: tl.ContractAddress,
+			refresh:         func(ctx context.Context) error { return s.refreshOpenzeppelinTimeLockData(ctx, tl) },
 		})
 	}
-	_ = ozGroup.Wait()
+
+	stats := s.runRefreshTasks(ctx, tasks)
+
+	if err := ctx.Err(); err != nil {
+		logger.Warn("RefreshAllTimeLockData cancelled",
+			"total", stats.total,
+			"succeeded", stats.succeeded,
+			"failed", stats.failed,
+			"skipped", stats.skipped,
+			"elapsed_ms", time.Since(start).Milliseconds(),
+		)
+		return err
+	}
 
 	logger.Info("RefreshAllTimeLockData completed",
 		"compound_count", len(compoundTimelocks),
 		"openzeppelin_count", len(openzeppelinTimelocks),
-		"concurrency", concurrency,
+		"succeeded", stats.succeeded,
+		"failed", stats.failed,
+		"concurrency", s.refreshConcurrency(),
+		"chain_rate_limit", s.refreshChainRateLimit(),
 		"elapsed_ms", time.Since(start).Milliseconds(),
 	)
 	return nil
 }
 
 // 私有方法 - 创建或导入Compound timelock
-func (s *service) createOrImportCompoundTimeLock(ctx context.Context, userAddress, contractAddress string, req *types.CreateOrImportTimelockContractRequest, chainInfo *types.SupportChain) (*types.CompoundTimeLock, error) {
+func (s *service) createOrImportCompoundTimeLock(ctx context.Context, userAddress, contractAddress string, req *types.CreateOrImportTimelockContractRequest, chainInfo *types.SupportChain, creation *contractCreation) (*types.CompoundTimeLock, error) {
 	// 从链上读取合约数据
 	contractData, err := s.readCompoundTimeLockFromChain(ctx, req.ChainID, contractAddress)
 	if err != nil {
 		logger.Error("Failed to read compound timelock from chain", err, "contract_address", contractAddress)
 		return nil, fmt.Errorf("failed to read contract data: %w", err)
 	}
+	if err := validateCompoundDelays(contractData); err != nil {
+		logger.Warn("Compound timelock delay out of range", "contract_address", contractAddress, "chain_id", req.ChainID, "error", err)
+		return nil, err
+	}
 
 	timeLock := &types.CompoundTimeLock{
 		CreatorAddress:  userAddress,
@@ -419,22 +565,36 @@ func (s *service) createOrImportCompoundTimeLock(ctx context.Context, userAddres
 		MinimumDelay:    contractData.MinimumDelay,
 		MaximumDelay:    contractData.MaximumDelay,
 		Remark:          html.EscapeString(strings.TrimSpace(req.Remark)),
+		Nickname:        html.EscapeString(strings.TrimSpace(req.Nickname)),
 		Status:          "active",
 		IsImported:      req.IsImported,
+		DelayWarning:    s.compoundDelayWarning(contractData),
+
+		GracePeriodEstimated: contractData.GracePeriodEstimated,
+	}
+	if creation != nil {
+		timeLock.CreationBlock = &creation.BlockNumber
+		timeLock.CreationTx = &creation.TxHash
 	}
 
-	if err := s.timeLockRepo.CreateCompoundTimeLock(ctx, timeLock); err != nil {
+	auditCtx := audit.WithLog(ctx, types.AuditActionCreate, types.AuditTargetTimelock,
+		timelockAuditTarget("compound", req.ChainID, contractAddress), fmt.Sprintf("created compound timelock (imported=%t)", req.IsImported))
+	if err := s.timeLockRepo.CreateCompoundTimeLock(auditCtx, timeLock); err != nil {
 		logger.Error("Failed to create compound timelock", err)
 		return nil, fmt.Errorf("failed to create compound timelock: %w", err)
 	}
 
 	logger.Info("CreateOrImportCompoundTimeLock success", "timelock_id", timeLock.ID, "user_address", userAddress, "contract_address", contractAddress)
+	if timeLock.DelayWarning != nil {
+		logger.Warn("Timelock imported with a delay below the safety threshold", "timelock_id", timeLock.ID, "chain_id", req.ChainID, "contract_address", contractAddress, "delay", timeLock.Delay)
+	}
 
 	// 【优化】创建合约后异步同步 Goldsky flows，接口立即返回。
 	// 使用独立 context 避免请求 ctx 被取消时中断后台同步。
 	if s.goldskySvc != nil {
 		chainID := req.ChainID
 		addr := contractAddress
+		gracePeriod, estimated := timeLock.GracePeriod, timeLock.GracePeriodEstimated
 		go func() {
 			bg, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 			defer cancel()
@@ -444,6 +604,10 @@ func (s *service) createOrImportCompoundTimeLock(ctx context.Context, userAddres
 			} else {
 				logger.Info("Successfully synced flows after compound timelock creation", "contract_address", addr, "chain_id", chainID)
 			}
+			// 数据源未提供过期时间的 flow 按合约宽限期补算
+			if err := s.goldskySvc.ApplyCompoundGracePeriod(bg, chainID, addr, gracePeriod, estimated); err != nil {
+				logger.Error("Failed to apply grace period after compound timelock creation", err, "contract_address", addr, "chain_id", chainID)
+			}
 		}()
 	}
 
@@ -451,7 +615,7 @@ func (s *service) createOrImportCompoundTimeLock(ctx context.Context, userAddres
 }
 
 // 私有方法 - 创建或导入OpenZeppelin timelock
-func (s *service) createOrImportOpenzeppelinTimeLock(ctx context.Context, userAddress, contractAddress string, req *types.CreateOrImportTimelockContractRequest, chainInfo *types.SupportChain) (*types.OpenzeppelinTimeLock, error) {
+func (s *service) createOrImportOpenzeppelinTimeLock(ctx context.Context, userAddress, contractAddress string, req *types.CreateOrImportTimelockContractRequest, chainInfo *types.SupportChain, creation *contractCreation) (*types.OpenzeppelinTimeLock, error) {
 	// 从链上读取合约数据
 	contractData, err := s.readOpenzeppelinTimeLockFromChain(ctx, req.ChainID, contractAddress)
 	if err != nil {
@@ -478,16 +642,27 @@ func (s *service) createOrImportOpenzeppelinTimeLock(ctx context.Context, userAd
 		Proposers:       string(proposersJSON),
 		Executors:       string(executorsJSON),
 		Remark:          html.EscapeString(strings.TrimSpace(req.Remark)),
+		Nickname:        html.EscapeString(strings.TrimSpace(req.Nickname)),
 		Status:          "active",
 		IsImported:      req.IsImported,
+		DelayWarning:    s.delayWarning(contractData.Delay),
+	}
+	if creation != nil {
+		timeLock.CreationBlock = &creation.BlockNumber
+		timeLock.CreationTx = &creation.TxHash
 	}
 
-	if err := s.timeLockRepo.CreateOpenzeppelinTimeLock(ctx, timeLock); err != nil {
+	auditCtx := audit.WithLog(ctx, types.AuditActionCreate, types.AuditTargetTimelock,
+		timelockAuditTarget("openzeppelin", req.ChainID, contractAddress), fmt.Sprintf("created openzeppelin timelock (imported=%t)", req.IsImported))
+	if err := s.timeLockRepo.CreateOpenzeppelinTimeLock(auditCtx, timeLock); err != nil {
 		logger.Error("Failed to create openzeppelin timelock", err)
 		return nil, fmt.Errorf("failed to create openzeppelin timelock: %w", err)
 	}
 
 	logger.Info("CreateOrImportOpenzeppelinTimeLock success", "timelock_id", timeLock.ID, "user_address", userAddress, "contract_address", contractAddress)
+	if timeLock.DelayWarning != nil {
+		logger.Warn("Timelock imported with a delay below the safety threshold", "timelock_id", timeLock.ID, "chain_id", req.ChainID, "contract_address", contractAddress, "delay", timeLock.Delay)
+	}
 
 	// 【优化】创建合约后异步同步 Goldsky flows，接口立即返回
 	if s.goldskySvc != nil {
@@ -580,6 +755,11 @@ type CompoundTimeLockData struct {
 	GracePeriod  int64   `json:"grace_period"`
 	MinimumDelay int64   `json:"minimum_delay"`
 	MaximumDelay int64   `json:"maximum_delay"`
+	// GRACE_PERIOD 读取失败，grace_period 为配置的兜底值
+	GracePeriodEstimated bool `json:"grace_period_estimated"`
+	// MINIMUM_DELAY / MAXIMUM_DELAY 读取失败，对应字段为配置的默认值，不参与拒绝导入的范围校验，只用于 delay_warning
+	MinimumDelayDefaulted bool `json:"minimum_delay_defaulted"`
+	MaximumDelayDefaulted bool `json:"maximum_delay_defaulted"`
 }
 
 type OpenzeppelinTimeLockData struct {
@@ -610,7 +790,96 @@ func init() {
 // compoundCallOrder 索引与 aggregate3 的输入/输出一一对应
 var compoundCallOrder = []string{"delay", "admin", "pendingAdmin", "GRACE_PERIOD", "MINIMUM_DELAY", "MAXIMUM_DELAY"}
 
-// 私有方法 - 从链上读取Compound timelock数据（使用 Multicall3 合并为 1 次 eth_call，带重试）
+// compoundOptionalMethods 调用失败时使用默认值的方法（delay/admin 失败说明不是 Compound timelock）
+var compoundOptionalMethods = map[string]bool{
+	"pendingAdmin":  true,
+	"GRACE_PERIOD":  true,
+	"MINIMUM_DELAY": true,
+	"MAXIMUM_DELAY": true,
+}
+
+// applyCompoundDefaults 合约未返回 GRACE_PERIOD / MINIMUM_DELAY / MAXIMUM_DELAY 时填充默认值
+// 默认值只来自配置（timelock.compound_default_*，由 LoadConfig 设置标准 Compound 的取值），配置为 0 时不填充；
+// GRACE_PERIOD 优先使用该链配置的兜底值，并标记为估算
+func (s *service) applyCompoundDefaults(chainID int, data *CompoundTimeLockData) {
+	var gracePeriod, minimumDelay, maximumDelay time.Duration
+	if s.cfg != nil {
+		gracePeriod = s.cfg.CompoundDefaultGracePeriod
+		if chainGrace := s.cfg.CompoundGracePeriodByChain[strconv.Itoa(chainID)]; chainGrace > 0 {
+			gracePeriod = chainGrace
+		}
+		minimumDelay = s.cfg.CompoundDefaultMinimumDelay
+		maximumDelay = s.cfg.CompoundDefaultMaximumDelay
+	}
+
+	if data.GracePeriod <= 0 {
+		data.GracePeriod = int64(gracePeriod.Seconds())
+		data.GracePeriodEstimated = true
+	}
+	if data.MinimumDelay <= 0 {
+		data.MinimumDelay = int64(minimumDelay.Seconds())
+		data.MinimumDelayDefaulted = true
+	}
+	if data.MaximumDelay <= 0 {
+		data.MaximumDelay = int64(maximumDelay.Seconds())
+		data.MaximumDelayDefaulted = true
+	}
+}
+
+// delayWarning delay（秒）低于配置的安全阈值时返回警告，否则返回 nil（不阻止导入）
+func (s *service) delayWarning(delay int64) *string {
+	if s.cfg == nil || s.cfg.MinDelayWarning <= 0 {
+		return nil
+	}
+	threshold := int64(s.cfg.MinDelayWarning.Seconds())
+	if delay >= threshold {
+		return nil
+	}
+	warning := fmt.Sprintf("delay %ds is below the safety threshold of %ds, the timelock may be misconfigured", delay, threshold)
+	return &warning
+}
+
+// compoundDelayWarning 在 delayWarning 之外检查默认填充的上下限：
+// 填充值只是估算，delay 超出范围或上下限不一致时返回警告，不拒绝导入
+func (s *service) compoundDelayWarning(data *CompoundTimeLockData) *string {
+	if warning := s.delayWarning(data.Delay); warning != nil {
+		return warning
+	}
+	defaulted := data.MinimumDelayDefaulted || data.MaximumDelayDefaulted
+	var warning string
+	switch {
+	case defaulted && data.MaximumDelay > 0 && data.MinimumDelay > data.MaximumDelay:
+		warning = fmt.Sprintf("minimum_delay %ds is greater than maximum_delay %ds, the contract does not expose both and defaults were assumed", data.MinimumDelay, data.MaximumDelay)
+	case data.MinimumDelayDefaulted && data.Delay < data.MinimumDelay:
+		warning = fmt.Sprintf("delay %ds is below the assumed minimum_delay of %ds, the contract does not expose MINIMUM_DELAY", data.Delay, data.MinimumDelay)
+	case data.MaximumDelayDefaulted && data.MaximumDelay > 0 && data.Delay > data.MaximumDelay:
+		warning = fmt.Sprintf("delay %ds exceeds the assumed maximum_delay of %ds, the contract does not expose MAXIMUM_DELAY", data.Delay, data.MaximumDelay)
+	default:
+		return nil
+	}
+	return &warning
+}
+
+// validateCompoundDelays 校验 minimum_delay <= delay <= maximum_delay
+// 只用链上实际读到的上下限拒绝导入：缺少 MINIMUM_DELAY / MAXIMUM_DELAY 的分叉合约填充的默认值
+// 由 compoundDelayWarning 提示，不拒绝
+func validateCompoundDelays(data *CompoundTimeLockData) error {
+	if !data.MinimumDelayDefaulted && !data.MaximumDelayDefaulted && data.MinimumDelay > data.MaximumDelay {
+		return fmt.Errorf("%w: minimum_delay %d is greater than maximum_delay %d", ErrInvalidContractParams, data.MinimumDelay, data.MaximumDelay)
+	}
+	if !data.MinimumDelayDefaulted && data.Delay < data.MinimumDelay {
+		return fmt.Errorf("%w: delay %d is below minimum_delay %d", ErrInvalidContractParams, data.Delay, data.MinimumDelay)
+	}
+	if !data.MaximumDelayDefaulted && data.Delay > data.MaximumDelay {
+		return fmt.Errorf("%w: delay %d exceeds maximum_delay %d", ErrInvalidContractParams, data.Delay, data.MaximumDelay)
+	}
+	if data.GracePeriod <= 0 {
+		return fmt.Errorf("%w: grace_period must be positive", ErrInvalidContractParams)
+	}
+	return nil
+}
+
+// 私有方法 - 从链上读取Compound timelock数据（使用 Multicall3 合并为 1 次 eth_call，带重试；链上无 Multicall3 时改用 JSON-RPC batch）
 func (s *service) readCompoundTimeLockFromChain(ctx context.Context, chainID int, contractAddress string) (*CompoundTimeLockData, error) {
 	start := time.Now()
 	contractAddr := common.HexToAddress(contractAddress)
@@ -624,15 +893,21 @@ func (s *service) readCompoundTimeLockFromChain(ctx context.Context, chainID int
 		}
 		calls = append(calls, scanner.Call3{
 			Target: contractAddr,
-			// pendingAdmin 可能未实现或返回零地址，允许失败
-			AllowFailure: method == "pendingAdmin",
+			// 所有子调用都允许失败：必需方法 revert 时 aggregate3 不整体回滚，按各自结果判定是否为 timelock；
+			// pendingAdmin 可能未实现或返回零地址，部分 fork 未实现常量，失败时使用默认值
+			AllowFailure: true,
 			CallData:     callData,
 		})
 	}
 
 	var results []scanner.Call3Result
+	multicallUnavailable := false
 	if err := s.rpcManager.ExecuteWithRetry(ctx, chainID, func(client *ethclient.Client) error {
 		r, err := scanner.AggregateCall3(ctx, client, calls)
+		if errors.Is(err, scanner.ErrMulticallUnavailable) {
+			multicallUnavailable = true
+			return nil // 未部署 Multicall3 不重试，改用 JSON-RPC batch
+		}
 		if err != nil {
 			return err
 		}
@@ -641,26 +916,55 @@ func (s *service) readCompoundTimeLockFromChain(ctx context.Context, chainID int
 	}); err != nil {
 		return nil, fmt.Errorf("failed to multicall compound timelock: %w", err)
 	}
-	if len(results) != len(calls) {
-		return nil, fmt.Errorf("unexpected multicall result length: got %d, want %d", len(results), len(calls))
+	if multicallUnavailable {
+		r, err := s.rpcManager.BatchCallContract(ctx, chainID, calls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to batch call compound timelock: %w", err)
+		}
+		results = r
+	}
+	data, err := decodeCompoundResults(results)
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyCompoundDefaults(chainID, data)
+	if data.GracePeriodEstimated {
+		logger.Warn("Failed to read compound GRACE_PERIOD, using fallback", "chain_id", chainID, "contract_address", contractAddress, "grace_period", data.GracePeriod)
 	}
 
+	logger.Info("readCompoundTimeLockFromChain via multicall",
+		"chain_id", chainID,
+		"batch_fallback", multicallUnavailable,
+		"contract_address", contractAddress,
+		"elapsed_ms", time.Since(start).Milliseconds(),
+	)
+
+	return data, nil
+}
+
+// decodeCompoundResults 按子调用结果解析 Compound timelock 数据：
+// delay/admin 调用失败或无法解码说明合约不是 Compound timelock，返回 ErrContractNotTimelock；其余方法失败时留空由默认值填充
+func decodeCompoundResults(results []scanner.Call3Result) (*CompoundTimeLockData, error) {
+	if len(results) != len(compoundCallOrder) {
+		return nil, fmt.Errorf("unexpected multicall result length: got %d, want %d", len(results), len(compoundCallOrder))
+	}
 	data := &CompoundTimeLockData{}
 	for i, method := range compoundCallOrder {
 		res := results[i]
 		if !res.Success {
-			if method == "pendingAdmin" {
+			if compoundOptionalMethods[method] {
 				continue // 允许失败
 			}
-			return nil, fmt.Errorf("multicall sub-call %s failed", method)
+			return nil, fmt.Errorf("%w: sub-call %s failed", ErrContractNotTimelock, method)
 		}
 		values, err := compoundTimelockABI.Unpack(method, res.ReturnData)
 		if err != nil {
-			if method == "pendingAdmin" {
-				logger.Warn("Failed to unpack pendingAdmin", "error", err)
+			if compoundOptionalMethods[method] {
+				logger.Warn("Failed to unpack optional compound method", "method", method, "error", err)
 				continue
 			}
-			return nil, fmt.Errorf("failed to unpack %s: %w", method, err)
+			return nil, fmt.Errorf("%w: failed to unpack %s: %v", ErrContractNotTimelock, method, err)
 		}
 		if len(values) == 0 {
 			continue
@@ -703,13 +1007,6 @@ func (s *service) readCompoundTimeLockFromChain(ctx context.Context, chainID int
 			data.MaximumDelay = v.Int64()
 		}
 	}
-
-	logger.Info("readCompoundTimeLockFromChain via multicall",
-		"chain_id", chainID,
-		"contract_address", contractAddress,
-		"elapsed_ms", time.Since(start).Milliseconds(),
-	)
-
 	return data, nil
 }
 
@@ -829,40 +1126,102 @@ func (s *service) callContract(ctx context.Context, client *ethclient.Client, co
 		Data: callData,
 	}, nil)
 	if err != nil {
+		// JSON-RPC 错误（revert 等）说明合约不支持该方法，区别于网络错误
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return nil, fmt.Errorf("%w: %s reverted: %v", ErrContractNotTimelock, method, err)
+		}
 		return nil, fmt.Errorf("failed to call contract: %w", err)
 	}
 
-	return parsedABI.Unpack(method, result)
+	values, err := parsedABI.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unpack %s: %v", ErrContractNotTimelock, method, err)
+	}
+	return values, nil
 }
 
 // 私有方法 - 刷新Compound timelock数据
+// 刷新前先复核合约：合约代码不存在或不再响应 Compound timelock 接口时标记为 inactive 并通知导入者；
+// 关键参数异常变化只记录 status_reason 并告警，合约保持 active 继续监控；inactive 合约复核通过后恢复为 active
 func (s *service) refreshCompoundTimeLockData(ctx context.Context, timeLock *types.CompoundTimeLock) error {
+	hasCode, err := s.contractHasCode(ctx, timeLock.ChainID, timeLock.ContractAddress)
+	if err != nil {
+		return fmt.Errorf("failed to read contract code: %w", err)
+	}
+	if !hasCode {
+		return s.deactivateCompoundTimeLock(ctx, timeLock, reasonContractCodeMissing)
+	}
+
 	// 从链上读取最新数据
 	contractData, err := s.readCompoundTimeLockFromChain(ctx, timeLock.ChainID, timeLock.ContractAddress)
 	if err != nil {
+		if errors.Is(err, ErrContractNotTimelock) {
+			return s.deactivateCompoundTimeLock(ctx, timeLock, fmt.Sprintf("contract no longer responds as a compound timelock (%v)", err))
+		}
 		return fmt.Errorf("failed to read contract data: %w", err)
 	}
+	reason := compoundChangeReason(timeLock, contractData)
+	s.reactivateCompoundTimeLock(timeLock)
 
 	// 更新数据库中的数据
 	timeLock.Delay = contractData.Delay
 	timeLock.Admin = contractData.Admin
 	timeLock.PendingAdmin = contractData.PendingAdmin
-	timeLock.GracePeriod = contractData.GracePeriod
+	// 本次仍读取失败时保留已有的宽限期，不用兜底值覆盖
+	if !contractData.GracePeriodEstimated || timeLock.GracePeriodEstimated {
+		timeLock.GracePeriod = contractData.GracePeriod
+		timeLock.GracePeriodEstimated = contractData.GracePeriodEstimated
+	}
 	timeLock.MinimumDelay = contractData.MinimumDelay
 	timeLock.MaximumDelay = contractData.MaximumDelay
+	timeLock.DelayWarning = s.compoundDelayWarning(contractData)
 	timeLock.UpdatedAt = time.Now()
 
-	return s.timeLockRepo.UpdateCompoundTimeLock(ctx, timeLock)
+	if reason != "" {
+		reason = truncateStatusReason(reason)
+		timeLock.StatusReason = &reason
+	} else {
+		timeLock.StatusReason = nil // 参数已稳定，清除上次复核记录的变化原因
+	}
+	if err := s.timeLockRepo.UpdateCompoundTimeLock(ctx, timeLock); err != nil {
+		return err
+	}
+	if reason != "" {
+		logger.Warn("Compound timelock parameters changed during re-verification", "chain_id", timeLock.ChainID, "contract_address", timeLock.ContractAddress, "creator", timeLock.CreatorAddress, "reason", reason)
+		s.notifyContractAlert(ctx, timeLock.CreatorAddress, "compound", timeLock.ChainID, timeLock.ContractAddress, reason, false)
+	}
+	// 此前按兜底值估算的 flow 过期时间用最新宽限期修正
+	if s.goldskySvc != nil {
+		if err := s.goldskySvc.ApplyCompoundGracePeriod(ctx, timeLock.ChainID, timeLock.ContractAddress, timeLock.GracePeriod, timeLock.GracePeriodEstimated); err != nil {
+			logger.Error("Failed to apply grace period to compound flows", err, "chain_id", timeLock.ChainID, "contract_address", timeLock.ContractAddress)
+		}
+	}
+	return nil
 }
 
 // 私有方法 - 刷新OpenZeppelin timelock数据
+// 刷新前先复核合约：合约代码不存在或不再响应 TimelockController 接口时标记为 inactive 并通知导入者；inactive 合约复核通过后恢复为 active
 func (s *service) refreshOpenzeppelinTimeLockData(ctx context.Context, timeLock *types.OpenzeppelinTimeLock) error {
+	hasCode, err := s.contractHasCode(ctx, timeLock.ChainID, timeLock.ContractAddress)
+	if err != nil {
+		return fmt.Errorf("failed to read contract code: %w", err)
+	}
+	if !hasCode {
+		return s.deactivateOpenzeppelinTimeLock(ctx, timeLock, reasonContractCodeMissing)
+	}
+
 	// 从链上读取最新数据
 	contractData, err := s.readOpenzeppelinTimeLockFromChain(ctx, timeLock.ChainID, timeLock.ContractAddress)
 	if err != nil {
+		if errors.Is(err, ErrContractNotTimelock) {
+			return s.deactivateOpenzeppelinTimeLock(ctx, timeLock, fmt.Sprintf("contract no longer responds as an openzeppelin timelock (%v)", err))
+		}
 		return fmt.Errorf("failed to read contract data: %w", err)
 	}
 
+	s.reactivateOpenzeppelinTimeLock(timeLock)
+
 	// JSON序列化
 	proposersJSON, _ := json.Marshal(contractData.Proposers)
 	executorsJSON, _ := json.Marshal(contractData.Executors)
@@ -876,6 +1235,7 @@ func (s *service) refreshOpenzeppelinTimeLockData(ctx context.Context, timeLock
 	}
 	timeLock.Proposers = string(proposersJSON)
 	timeLock.Executors = string(executorsJSON)
+	timeLock.DelayWarning = s.delayWarning(contractData.Delay)
 	timeLock.UpdatedAt = time.Now()
 
 	return s.timeLockRepo.UpdateOpenzeppelinTimeLock(ctx, timeLock)
@@ -942,6 +1302,11 @@ func (s *service) validateCreateOrImportRequest(req *types.CreateOrImportTimeloc
 		return err
 	}
 
+	// 验证昵称
+	if err := s.validateNickname(req.Nickname); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -978,6 +1343,28 @@ func (s *service) validateRemark(remark string) error {
 		logger.Warn("Remark contains HTML-sensitive characters, will be escaped", "remark", remark)
 	}
 
+	if containsMaliciousContent(remark) {
+		return fmt.Errorf("%w: remark contains potentially malicious content", ErrInvalidRemark)
+	}
+
+	return nil
+}
+
+// validateNickname 验证昵称
+func (s *service) validateNickname(nickname string) error {
+	if utf8.RuneCountInString(nickname) > 100 {
+		return fmt.Errorf("%w: nickname too long (max 100 characters)", ErrInvalidNickname)
+	}
+
+	if containsMaliciousContent(nickname) {
+		return fmt.Errorf("%w: nickname contains potentially malicious content", ErrInvalidNickname)
+	}
+
+	return nil
+}
+
+// containsMaliciousContent 检查备注、昵称等用户输入是否包含脚本注入内容
+func containsMaliciousContent(content string) bool {
 	maliciousPatterns := []string{
 		`(?i)javascript:`,
 		`(?i)data:`,
@@ -987,12 +1374,11 @@ func (s *service) validateRemark(remark string) error {
 	}
 
 	for _, pattern := range maliciousPatterns {
-		if matched, _ := regexp.MatchString(pattern, remark); matched {
-			return fmt.Errorf("%w: remark contains potentially malicious content", ErrInvalidRemark)
+		if matched, _ := regexp.MatchString(pattern, content); matched {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
 // containsAddress 检查地址是否在列表中
@@ -1009,3 +1395,8 @@ func (s *service) containsAddress(jsonAddresses, address string) bool {
 	}
 	return false
 }
+
+// timelockAuditTarget timelock 合约在审计日志中的目标标识，如 compound:1:0x...
+func timelockAuditTarget(standard string, chainID int, contractAddress string) string {
+	return fmt.Sprintf("%s:%d:%s", standard, chainID, strings.ToLower(contractAddress))
+}
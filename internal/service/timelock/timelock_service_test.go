@@ -0,0 +1,231 @@
+package timelock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"timelocker-backend/internal/config"
+	"timelocker-backend/internal/repository/timelock"
+	"timelocker-backend/internal/types"
+)
+
+// fakeListRepo 只实现 GetTimeLocksByUserPermissions，返回固定数量的 compound 合约
+type fakeListRepo struct {
+	timelock.Repository
+	count int
+}
+
+func (r fakeListRepo) GetTimeLocksByUserPermissions(ctx context.Context, userAddress string, req *types.GetTimeLockListRequest) ([]types.CompoundTimeLockWithPermission, []types.OpenzeppelinTimeLockWithPermission, int64, error) {
+	list := make([]types.CompoundTimeLockWithPermission, r.count)
+	return list, nil, int64(r.count), nil
+}
+
+func TestGetTimeLockListPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		pageSize  int
+		pages     int
+		hasNext   bool
+		hasPrev   bool
+		wantTotal int64
+	}{
+		{"empty list", 0, 1, 0, false, false, 0},
+		{"single item", 1, 1, 1, false, false, 1},
+		{"several items on one page", 5, 5, 1, false, false, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{timeLockRepo: fakeListRepo{count: tt.count}}
+			resp, err := s.GetTimeLockList(context.Background(), "0x1111111111111111111111111111111111111111", &types.GetTimeLockListRequest{})
+			if err != nil {
+				t.Fatalf("GetTimeLockList: %v", err)
+			}
+			meta := resp.PaginationMeta
+			if resp.Total != tt.wantTotal || meta.Page != 1 || meta.PageSize != tt.pageSize || meta.TotalPages != tt.pages ||
+				meta.HasNext != tt.hasNext || meta.HasPrev != tt.hasPrev {
+				t.Fatalf("unexpected pagination: total=%d meta=%+v", resp.Total, meta)
+			}
+		})
+	}
+}
+
+func TestApplyCompoundDefaults(t *testing.T) {
+	cfg := &config.TimelockConfig{
+		CompoundDefaultGracePeriod:  14 * 24 * time.Hour,
+		CompoundDefaultMinimumDelay: 2 * 24 * time.Hour,
+		CompoundDefaultMaximumDelay: 30 * 24 * time.Hour,
+		CompoundGracePeriodByChain:  map[string]time.Duration{"56": 7 * 24 * time.Hour},
+	}
+	tests := []struct {
+		name    string
+		cfg     *config.TimelockConfig
+		chainID int
+		data    CompoundTimeLockData
+		want    CompoundTimeLockData
+	}{
+		{
+			name:    "on-chain values kept",
+			cfg:     cfg,
+			chainID: 1,
+			data:    CompoundTimeLockData{GracePeriod: 100, MinimumDelay: 10, MaximumDelay: 1000},
+			want:    CompoundTimeLockData{GracePeriod: 100, MinimumDelay: 10, MaximumDelay: 1000},
+		},
+		{
+			name:    "missing values filled from config",
+			cfg:     cfg,
+			chainID: 1,
+			want: CompoundTimeLockData{
+				GracePeriod: 1209600, MinimumDelay: 172800, MaximumDelay: 2592000,
+				GracePeriodEstimated: true, MinimumDelayDefaulted: true, MaximumDelayDefaulted: true,
+			},
+		},
+		{
+			name:    "per-chain grace period preferred",
+			cfg:     cfg,
+			chainID: 56,
+			data:    CompoundTimeLockData{MinimumDelay: 10, MaximumDelay: 1000},
+			want:    CompoundTimeLockData{GracePeriod: 604800, MinimumDelay: 10, MaximumDelay: 1000, GracePeriodEstimated: true},
+		},
+		{
+			name:    "no config leaves values unset",
+			chainID: 1,
+			want:    CompoundTimeLockData{GracePeriodEstimated: true, MinimumDelayDefaulted: true, MaximumDelayDefaulted: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{cfg: tt.cfg}
+			data := tt.data
+			s.applyCompoundDefaults(tt.chainID, &data)
+			if data != tt.want {
+				t.Fatalf("applyCompoundDefaults = %+v, want %+v", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCompoundDelays(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    CompoundTimeLockData
+		wantErr bool
+	}{
+		{"within on-chain bounds", CompoundTimeLockData{Delay: 100, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 1000}, false},
+		{"equal to bounds", CompoundTimeLockData{Delay: 10, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 10}, false},
+		{"below on-chain minimum", CompoundTimeLockData{Delay: 5, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 1000}, true},
+		{"above on-chain maximum", CompoundTimeLockData{Delay: 2000, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 1000}, true},
+		{"on-chain minimum above maximum", CompoundTimeLockData{Delay: 100, GracePeriod: 1, MinimumDelay: 1000, MaximumDelay: 10}, true},
+		{"non-positive grace period", CompoundTimeLockData{Delay: 100, MinimumDelay: 10, MaximumDelay: 1000}, true},
+		{"below defaulted minimum", CompoundTimeLockData{Delay: 5, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 1000, MinimumDelayDefaulted: true}, false},
+		{"above defaulted maximum", CompoundTimeLockData{Delay: 2000, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 1000, MaximumDelayDefaulted: true}, false},
+		{"defaulted minimum above on-chain maximum", CompoundTimeLockData{Delay: 5, GracePeriod: 1, MinimumDelay: 1000, MaximumDelay: 10, MinimumDelayDefaulted: true}, false},
+		{"on-chain maximum still enforced with defaulted minimum", CompoundTimeLockData{Delay: 2000, GracePeriod: 1, MinimumDelay: 10, MaximumDelay: 1000, MinimumDelayDefaulted: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			err := validateCompoundDelays(&data)
+			if tt.wantErr && !errors.Is(err, ErrInvalidContractParams) {
+				t.Fatalf("validateCompoundDelays = %v, want ErrInvalidContractParams", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("validateCompoundDelays = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestCompoundDelayWarning(t *testing.T) {
+	s := &service{cfg: &config.TimelockConfig{MinDelayWarning: time.Hour}}
+	tests := []struct {
+		name     string
+		data     CompoundTimeLockData
+		contains string // 为空表示期望无警告
+	}{
+		{"within bounds", CompoundTimeLockData{Delay: 7200, MinimumDelay: 3600, MaximumDelay: 86400}, ""},
+		{"below safety threshold", CompoundTimeLockData{Delay: 60, MinimumDelay: 10, MaximumDelay: 86400}, "below the safety threshold"},
+		{"on-chain bound violation is not a warning", CompoundTimeLockData{Delay: 7200, MinimumDelay: 10000, MaximumDelay: 86400}, ""},
+		{"below defaulted minimum", CompoundTimeLockData{Delay: 7200, MinimumDelay: 10000, MaximumDelay: 86400, MinimumDelayDefaulted: true}, "below the assumed minimum_delay of 10000s"},
+		{"above defaulted maximum", CompoundTimeLockData{Delay: 90000, MinimumDelay: 3600, MaximumDelay: 86400, MaximumDelayDefaulted: true}, "exceeds the assumed maximum_delay of 86400s"},
+		{"defaulted bounds inconsistent", CompoundTimeLockData{Delay: 7200, MinimumDelay: 90000, MaximumDelay: 86400, MaximumDelayDefaulted: true}, "minimum_delay 90000s is greater than maximum_delay 86400s"},
+		{"unset defaulted bounds ignored", CompoundTimeLockData{Delay: 7200, MinimumDelayDefaulted: true, MaximumDelayDefaulted: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			got := s.compoundDelayWarning(&data)
+			if tt.contains == "" {
+				if got != nil {
+					t.Fatalf("compoundDelayWarning = %q, want nil", *got)
+				}
+				return
+			}
+			if got == nil || !strings.Contains(*got, tt.contains) {
+				t.Fatalf("compoundDelayWarning = %v, want it to contain %q", got, tt.contains)
+			}
+			if len(*got) > 200 {
+				t.Fatalf("warning length %d exceeds the delay_warning column size", len(*got))
+			}
+		})
+	}
+}
+
+// fakeExistsRepo 只实现按创建者查询，记录传入的（已规范化的）地址
+type fakeExistsRepo struct {
+	timelock.Repository
+	compound     *types.CompoundTimeLock
+	openzeppelin *types.OpenzeppelinTimeLock
+	err          error
+	gotUser      string
+	gotContract  string
+}
+
+func (r *fakeExistsRepo) GetCompoundTimeLockByCreator(ctx context.Context, chainID int, contractAddress string, userAddress string) (*types.CompoundTimeLock, error) {
+	r.gotUser, r.gotContract = userAddress, contractAddress
+	return r.compound, r.err
+}
+
+func (r *fakeExistsRepo) GetOpenzeppelinTimeLockByCreator(ctx context.Context, chainID int, contractAddress string, userAddress string) (*types.OpenzeppelinTimeLock, error) {
+	return r.openzeppelin, r.err
+}
+
+func TestCheckTimeLockExists(t *testing.T) {
+	const (
+		user     = "0xAbCdEfAbCdEfAbCdEfAbCdEfAbCdEfAbCdEfAbCd"
+		contract = "0x1234567890ABCDEF1234567890ABCDEF12345678"
+	)
+	tests := []struct {
+		name string
+		repo *fakeExistsRepo
+		want types.CheckTimeLockExistsResponse
+	}{
+		{"active compound", &fakeExistsRepo{compound: &types.CompoundTimeLock{ID: 7, Status: "active"}}, types.CheckTimeLockExistsResponse{Exists: true, Standard: "compound", ID: 7, Status: "active"}},
+		{"inactive openzeppelin", &fakeExistsRepo{openzeppelin: &types.OpenzeppelinTimeLock{ID: 9, Status: "inactive"}}, types.CheckTimeLockExistsResponse{Exists: true, Standard: "openzeppelin", ID: 9, Status: "inactive"}},
+		// 删除为物理删除，删除后与从未导入一致
+		{"absent or deleted", &fakeExistsRepo{}, types.CheckTimeLockExistsResponse{Exists: false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{timeLockRepo: tt.repo}
+			got, err := s.CheckTimeLockExists(context.Background(), user, &types.CheckTimeLockExistsRequest{ChainID: 1, ContractAddress: contract})
+			if err != nil {
+				t.Fatalf("CheckTimeLockExists: %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("CheckTimeLockExists = %+v, want %+v", *got, tt.want)
+			}
+			if tt.repo.gotUser != strings.ToLower(user) || tt.repo.gotContract != strings.ToLower(contract) {
+				t.Fatalf("repository queried with %q / %q, want lower-case addresses", tt.repo.gotUser, tt.repo.gotContract)
+			}
+		})
+	}
+
+	s := &service{timeLockRepo: &fakeExistsRepo{err: errors.New("db down")}}
+	if _, err := s.CheckTimeLockExists(context.Background(), user, &types.CheckTimeLockExistsRequest{ChainID: 1, ContractAddress: contract}); err == nil {
+		t.Fatal("expected repository error")
+	}
+}
@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"timelocker-backend/internal/service/email"
+	"timelocker-backend/internal/service/notification"
+	"timelocker-backend/internal/types"
+	"timelocker-backend/pkg/logger"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxSettingsEmails 设置汇总中读取的邮箱数量上限
+const maxSettingsEmails = types.MaxPageSize
+
+// Service 用户设置服务接口
+type Service interface {
+	// 获取用户设置汇总（已验证邮箱、通知渠道概况、邮件偏好）
+	GetSettings(ctx context.Context, userID int64, walletAddress string) (*types.UserSettingsResponse, error)
+	// 一次更新多个标量设置，返回更新后的汇总
+	UpdateSettings(ctx context.Context, userID int64, walletAddress string, req *types.UpdateUserSettingsRequest) (*types.UserSettingsResponse, error)
+}
+
+// service 用户设置服务实现
+type service struct {
+	emailSvc        email.EmailService
+	notificationSvc notification.NotificationService
+}
+
+// NewService 创建用户设置服务实例
+func NewService(emailSvc email.EmailService, notificationSvc notification.NotificationService) Service {
+	return &service{
+		emailSvc:        emailSvc,
+		notificationSvc: notificationSvc,
+	}
+}
+
+// GetSettings 并发读取各处设置并汇总
+func (s *service) GetSettings(ctx context.Context, userID int64, walletAddress string) (*types.UserSettingsResponse, error) {
+	var (
+		emails      *types.EmailListResponse
+		configs     *types.NotificationConfigListResponse
+		preferences *types.EmailPreferencesResponse
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		emails, err = s.emailSvc.GetUserEmails(gctx, userID, 1, maxSettingsEmails)
+		if err != nil {
+			return fmt.Errorf("failed to get user emails: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		configs, err = s.notificationSvc.GetAllNotificationConfigs(gctx, walletAddress, false)
+		if err != nil {
+			return fmt.Errorf("failed to get notification configs: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		preferences, err = s.emailSvc.GetEmailPreferences(gctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get email preferences: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		logger.Error("Failed to get user settings", err, "userID", userID)
+		return nil, err
+	}
+
+	return buildSettingsResponse(walletAddress, emails, configs, preferences), nil
+}
+
+// UpdateSettings 更新标量设置后返回最新汇总
+func (s *service) UpdateSettings(ctx context.Context, userID int64, walletAddress string, req *types.UpdateUserSettingsRequest) (*types.UserSettingsResponse, error) {
+	if req.EmailCcEmail != nil || req.EmailBccEmail != nil || req.EmailFromName != nil {
+		if _, err := s.emailSvc.UpdateEmailPreferences(ctx, userID, &types.UpdateEmailPreferencesRequest{
+			CcEmail:  req.EmailCcEmail,
+			BccEmail: req.EmailBccEmail,
+			FromName: req.EmailFromName,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("User settings updated", "userID", userID)
+	return s.GetSettings(ctx, userID, walletAddress)
+}
+
+// buildSettingsResponse 组装设置汇总
+func buildSettingsResponse(walletAddress string, emails *types.EmailListResponse, configs *types.NotificationConfigListResponse, preferences *types.EmailPreferencesResponse) *types.UserSettingsResponse {
+	response := &types.UserSettingsResponse{
+		WalletAddress:  walletAddress,
+		VerifiedEmails: []types.UserEmailResponse{},
+		Channels:       summarizeChannels(configs),
+	}
+	if emails != nil {
+		for _, e := range emails.Emails {
+			if e.IsVerified {
+				response.VerifiedEmails = append(response.VerifiedEmails, e)
+			}
+		}
+	}
+	if preferences != nil {
+		response.EmailPreferences = *preferences
+	}
+	return response
+}
+
+// summarizeChannels 统计各通知渠道的配置总数与激活数（渠道顺序固定）
+func summarizeChannels(configs *types.NotificationConfigListResponse) []types.ChannelSettingsSummary {
+	if configs == nil {
+		configs = &types.NotificationConfigListResponse{}
+	}
+
+	summary := func(channel string, actives []bool) types.ChannelSettingsSummary {
+		item := types.ChannelSettingsSummary{Channel: channel, Total: len(actives)}
+		for _, active := range actives {
+			if active {
+				item.Active++
+			}
+		}
+		return item
+	}
+
+	telegram := make([]bool, len(configs.TelegramConfigs))
+	for i, c := range configs.TelegramConfigs {
+		telegram[i] = c.IsActive
+	}
+	lark := make([]bool, len(configs.LarkConfigs))
+	for i, c := range configs.LarkConfigs {
+		lark[i] = c.IsActive
+	}
+	feishu := make([]bool, len(configs.FeishuConfigs))
+	for i, c := range configs.FeishuConfigs {
+		feishu[i] = c.IsActive
+	}
+	discord := make([]bool, len(configs.DiscordConfigs))
+	for i, c := range configs.DiscordConfigs {
+		discord[i] = c.IsActive
+	}
+	slack := make([]bool, len(configs.SlackConfigs))
+	for i, c := range configs.SlackConfigs {
+		slack[i] = c.IsActive
+	}
+	matrix := make([]bool, len(configs.MatrixConfigs))
+	for i, c := range configs.MatrixConfigs {
+		matrix[i] = c.IsActive
+	}
+
+	return []types.ChannelSettingsSummary{
+		summary("telegram", telegram),
+		summary("lark", lark),
+		summary("feishu", feishu),
+		summary("discord", discord),
+		summary("slack", slack),
+		summary("matrix", matrix),
+	}
+}
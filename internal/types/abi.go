@@ -21,6 +21,20 @@ func (ABI) TableName() string {
 	return "abis"
 }
 
+// ABIFunction ABI 函数选择器索引，每个 ABI 的每个函数一行，用于按选择器反查 ABI 与统计使用情况
+type ABIFunction struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ABIID     int64     `json:"abi_id" gorm:"column:abi_id;not null;index"`
+	Selector  string    `json:"selector" gorm:"size:10;not null;index"` // 0x开头的4字节选择器（小写）
+	Signature string    `json:"signature" gorm:"type:text;not null"`    // 规范化函数签名，如 transfer(address,uint256)
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 设置表名
+func (ABIFunction) TableName() string {
+	return "abi_functions"
+}
+
 // CreateABIRequest 创建ABI请求
 type CreateABIRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=200"`
@@ -40,6 +54,67 @@ type ABIListResponse struct {
 	ABIs []ABI `json:"abis"` // 用户创建的ABI及平台共享的ABI
 }
 
+// GetABIPageRequest 分页查询用户ABI请求
+type GetABIPageRequest struct {
+	Page          int    `form:"page"`           // 页码，默认为1
+	PageSize      int    `form:"page_size"`      // 每页大小，默认为20，最大100
+	Name          string `form:"name"`           // 按名称模糊搜索（不区分大小写），为空时不过滤
+	IncludeShared bool   `form:"include_shared"` // 是否同时返回平台共享的ABI
+}
+
+// ABISummary ABI列表项（不含 abi_content，详情通过 /abi/get 获取）
+type ABISummary struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Owner         string    `json:"owner"`
+	Description   string    `json:"description"`
+	IsShared      bool      `json:"is_shared"`
+	FunctionCount int       `json:"function_count"` // ABI 中的函数数量
+	UsedInFlows   int64     `json:"used_in_flows"`  // 用户相关流程中调用了该 ABI 函数的流程数
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// GetABIPageResponse 分页查询用户ABI响应
+type GetABIPageResponse struct {
+	ABIs  []ABISummary `json:"abis"`
+	Total int64        `json:"total"`
+	PaginationMeta
+}
+
+// SearchSharedABIsRequest 按函数名搜索共享ABI请求
+type SearchSharedABIsRequest struct {
+	Function string `form:"function" binding:"required,max=100"` // 函数名（不区分大小写的模糊匹配），也可传 0x 开头的 4 字节选择器精确匹配
+}
+
+// ABIFunctionMatch 共享ABI中匹配的函数（每个匹配函数一行）
+type ABIFunctionMatch struct {
+	ABIID       int64  `gorm:"column:abi_id"`
+	Name        string `gorm:"column:name"`
+	Description string `gorm:"column:description"`
+	Selector    string `gorm:"column:selector"`
+	Signature   string `gorm:"column:signature"`
+}
+
+// SharedABIFunction 共享ABI中匹配的函数
+type SharedABIFunction struct {
+	Selector  string `json:"selector"`  // 0x开头的4字节选择器
+	Signature string `json:"signature"` // 规范化函数签名
+}
+
+// SharedABISearchResult 包含匹配函数的共享ABI（详情通过 /abi/get 获取）
+type SharedABISearchResult struct {
+	ID          int64               `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Functions   []SharedABIFunction `json:"functions"` // 匹配的函数
+}
+
+// SearchSharedABIsResponse 按函数名搜索共享ABI响应
+type SearchSharedABIsResponse struct {
+	ABIs []SharedABISearchResult `json:"abis"`
+}
+
 // ABIResponse ABI详情响应
 type ABIResponse struct {
 	ID          int64     `json:"id"`
@@ -74,5 +149,124 @@ type UpdateABIWithIDRequest struct {
 
 // DeleteABIRequest 删除ABI请求
 type DeleteABIRequest struct {
-	ID int64 `json:"id" binding:"required"`
+	ID    int64 `json:"id" binding:"required"`
+	Force bool  `json:"force"` // 仍被流程使用时是否强制删除
+}
+
+// DecodeCalldataRequest 解码任意calldata请求
+// abi_id 与 abi_content 二选一；function_signature 为空时 calldata_hex 需带4字节选择器，
+// 非空时按 Compound 风格（签名 + 不含选择器的参数数据）解析，此时 ABI 可选
+type DecodeCalldataRequest struct {
+	ABIID             int64  `json:"abi_id"`
+	ABIContent        string `json:"abi_content"`
+	CalldataHex       string `json:"calldata_hex" binding:"required"`
+	FunctionSignature string `json:"function_signature"` // 如 transfer(address,uint256)
+}
+
+// DecodedCalldata 解码后的calldata
+type DecodedCalldata struct {
+	FunctionName      string          `json:"function_name"`
+	FunctionSignature string          `json:"function_signature"`
+	Selector          string          `json:"selector"` // 0x开头的4字节选择器
+	Params            []CalldataParam `json:"params"`
+}
+
+// 尽力解码的结果来源
+const (
+	DecodeSourceUserABI           = "user_abi"
+	DecodeSourceSharedABI         = "shared_abi"
+	DecodeSourceSignatureDatabase = "signature_database"
+)
+
+// DecodeBestEffortRequest 尽力解码calldata请求（calldata_hex 需带4字节选择器）
+type DecodeBestEffortRequest struct {
+	CalldataHex string `json:"calldata_hex" binding:"required"`
+}
+
+// DecodeCandidate 一个可成功解码的候选
+type DecodeCandidate struct {
+	Source            string `json:"source"`             // user_abi, shared_abi, signature_database
+	Confidence        string `json:"confidence"`         // high（用户ABI）, medium（共享ABI）, low（公共签名库，可能存在选择器碰撞）
+	ABIID             int64  `json:"abi_id,omitempty"`   // 来源ABI（签名库来源时为空）
+	ABIName           string `json:"abi_name,omitempty"` // 来源ABI名称
+	FunctionSignature string `json:"function_signature"`
+}
+
+// DecodeBestEffortResponse 尽力解码calldata响应
+type DecodeBestEffortResponse struct {
+	Decoded *DecodedCalldata `json:"decoded"`
+	DecodeCandidate
+	Alternatives []DecodeCandidate `json:"alternatives"` // 其他同样可以解码的候选，按优先级排序
+}
+
+// 合约代理类型（校验ABI时跟随到实现合约）
+const (
+	ContractProxyTypeEIP1167 = "eip1167"
+	ContractProxyTypeEIP1967 = "eip1967"
+)
+
+// VerifyABIAgainstContractRequest 校验ABI与已部署合约请求
+type VerifyABIAgainstContractRequest struct {
+	ABIID           int64  `json:"abi_id" binding:"required"`
+	ChainID         int    `json:"chain_id" binding:"required"`
+	ContractAddress string `json:"contract_address" binding:"required"`
+}
+
+// ABIFunctionPresence ABI函数在合约字节码中的存在情况
+type ABIFunctionPresence struct {
+	Signature string `json:"signature"` // 规范化函数签名，如 transfer(address,uint256)
+	Selector  string `json:"selector"`  // 0x开头的4字节选择器
+}
+
+// VerifyABIAgainstContractResponse 校验ABI与已部署合约响应
+// 选择器通过扫描字节码中的 PUSH 立即数得到，implemented 可能存在极少量误报（如选择器恰好等于某个常量）
+type VerifyABIAgainstContractResponse struct {
+	ABIID           int64                 `json:"abi_id"`
+	ChainID         int                   `json:"chain_id"`
+	ContractAddress string                `json:"contract_address"`
+	CodeAddress     string                `json:"code_address"`         // 实际扫描字节码的地址（代理合约时为实现合约地址）
+	ProxyType       string                `json:"proxy_type,omitempty"` // eip1167, eip1967，非代理时为空
+	CodeSize        int                   `json:"code_size"`            // 扫描的字节码长度
+	Implemented     []ABIFunctionPresence `json:"implemented"`
+	Missing         []ABIFunctionPresence `json:"missing"`
+}
+
+// FlowDecodedCall 按用户ABI解码的流程调用缓存（按用户隔离，ABI 删除时级联删除）
+type FlowDecodedCall struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement"`
+	UserAddress       string    `gorm:"size:42;not null"` // 上传 ABI 并触发解码的用户（小写）
+	Standard          string    `gorm:"size:20;not null"` // compound, openzeppelin
+	ChainID           int       `gorm:"not null"`
+	ContractAddress   string    `gorm:"size:42;not null"` // 合约地址（小写）
+	FlowID            string    `gorm:"size:128;not null"`
+	CallIndex         int       `gorm:"not null"` // Compound 固定为 0，OpenZeppelin 批量操作内的调用序号
+	ABIID             int64     `gorm:"column:abi_id;not null"`
+	FunctionName      string    `gorm:"size:200;not null"`
+	FunctionSignature string    `gorm:"type:text;not null"`
+	Params            string    `gorm:"type:jsonb;not null"` // []CalldataParam 的 JSON
+	DecodedAt         time.Time `gorm:"not null"`
+}
+
+// TableName 设置表名
+func (FlowDecodedCall) TableName() string {
+	return "flow_decoded_calls"
+}
+
+// DecodedFlowCall 流程响应中的调用解码结果
+type DecodedFlowCall struct {
+	CallIndex         int             `json:"call_index"`
+	ABIID             int64           `json:"abi_id"`
+	FunctionName      string          `json:"function_name"`
+	FunctionSignature string          `json:"function_signature"`
+	Params            []CalldataParam `json:"params"`
+	DecodedAt         time.Time       `json:"decoded_at"`
+}
+
+// RedecodeFlowsResponse 使用ABI重新解码历史流程响应
+type RedecodeFlowsResponse struct {
+	ABIID        int64 `json:"abi_id"`
+	FlowsMatched int   `json:"flows_matched"` // 调用了该 ABI 函数的用户相关流程数
+	FlowsDecoded int   `json:"flows_decoded"` // 至少一笔调用解码成功的流程数
+	CallsDecoded int   `json:"calls_decoded"` // 解码成功的调用数
+	Truncated    bool  `json:"truncated"`     // 匹配的流程超过单次上限，只处理了最近的一部分
 }
@@ -0,0 +1,231 @@
+package types
+
+import "time"
+
+// ResendFlowNotificationRequest 管理员重发流程通知请求
+type ResendFlowNotificationRequest struct {
+	FlowIdentifier
+	StatusFrom string `json:"status_from" binding:"omitempty,oneof=waiting ready executed cancelled expired"` // 变更前状态，新建流程时为空
+	StatusTo   string `json:"status_to" binding:"required,oneof=waiting ready executed cancelled expired"`    // 需要重发的目标状态
+}
+
+// ResendFlowNotificationResponse 管理员重发流程通知响应
+type ResendFlowNotificationResponse struct {
+	ClearedNotificationLogs int64    `json:"cleared_notification_logs"` // 清除的渠道通知去重记录数
+	ClearedEmailLogs        int64    `json:"cleared_email_logs"`        // 清除的邮件发送去重记录数
+	Errors                  []string `json:"errors,omitempty"`          // 重发过程中的错误（部分渠道失败）
+}
+
+// SimulateFlowTransitionRequest 管理员模拟流程状态变化通知请求
+type SimulateFlowTransitionRequest struct {
+	FlowIdentifier
+	StatusFrom string `json:"status_from" binding:"omitempty,oneof=waiting ready executed cancelled expired"` // 模拟的变更前状态，为空表示新建流程
+	StatusTo   string `json:"status_to" binding:"required,oneof=waiting ready executed cancelled expired"`    // 模拟的目标状态
+}
+
+// SimulatedNotificationDelivery 模拟发送的单条结果
+type SimulatedNotificationDelivery struct {
+	Channel      string `json:"channel"`                 // 渠道（telegram/lark/feishu/discord/slack/matrix/email）
+	Target       string `json:"target"`                  // 渠道为 用户地址/配置ID，邮件为邮箱ID
+	SendStatus   string `json:"send_status"`             // success / failed
+	ErrorMessage string `json:"error_message,omitempty"` // 发送失败原因
+}
+
+// SimulateFlowTransitionResponse 管理员模拟流程状态变化通知响应
+type SimulateFlowTransitionResponse struct {
+	StatusFrom string                          `json:"status_from"`
+	StatusTo   string                          `json:"status_to"`
+	Deliveries []SimulatedNotificationDelivery `json:"deliveries"`       // 本次模拟实际发出的通知（不写入真实发送日志）
+	Errors     []string                        `json:"errors,omitempty"` // 模拟过程中的错误（部分渠道失败）
+}
+
+// SetFlowStatusRequest 管理员手动设置流程状态请求（用于恢复卡住的流程）
+type SetFlowStatusRequest struct {
+	FlowIdentifier
+	StatusFrom string `json:"status_from" binding:"required,oneof=waiting ready executed cancelled expired"` // 期望的当前状态，与实际不一致时拒绝
+	StatusTo   string `json:"status_to" binding:"required,oneof=ready executed cancelled expired"`           // 目标状态
+	Reason     string `json:"reason" binding:"required,max=500"`                                             // 变更原因，写入状态历史
+	Verify     bool   `json:"verify"`                                                                        // 是否先向 Goldsky（Compound）或链上（OpenZeppelin）确认目标状态
+}
+
+// SetFlowStatusResponse 管理员手动设置流程状态响应
+type SetFlowStatusResponse struct {
+	StatusFrom     string `json:"status_from"`
+	StatusTo       string `json:"status_to"`
+	Verified       bool   `json:"verified"`                  // 是否已通过外部数据源确认
+	VerifiedStatus string `json:"verified_status,omitempty"` // 外部数据源给出的状态
+}
+
+// GetAdminFlowListRequest 管理员查询全部流程请求（不限于与当前用户相关的合约）
+type GetAdminFlowListRequest struct {
+	Standard        string     `json:"standard" form:"standard" binding:"omitempty,oneof=compound openzeppelin"`                    // 标准compound, openzeppelin，为空时查询全部
+	ChainID         int        `json:"chain_id" form:"chain_id"`                                                                    // 链ID，为空时查询全部
+	Status          string     `json:"status" form:"status" binding:"omitempty,oneof=all waiting ready executed cancelled expired"` // 状态，为空或 all 时查询全部
+	ContractAddress string     `json:"contract_address" form:"contract_address"`                                                    // 合约地址
+	Initiator       string     `json:"initiator" form:"initiator"`                                                                  // 发起人地址（不区分大小写）
+	CreatedFrom     *time.Time `json:"created_from" form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`                    // 创建时间下限（含），RFC3339
+	CreatedTo       *time.Time `json:"created_to" form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`                        // 创建时间上限（不含），RFC3339
+	IncludeArchived bool       `json:"include_archived" form:"include_archived"`                                                    // 是否包含已归档的终态流程
+	Page            int        `json:"page" form:"page"`                                                                            // 页码，默认为1
+	PageSize        int        `json:"page_size" form:"page_size"`                                                                  // 每页大小，默认为20，最大100
+}
+
+// AdminFlowFilter 管理员查询全部流程的过滤条件（已标准化）
+type AdminFlowFilter struct {
+	Standard        string
+	ChainID         int
+	Status          string
+	ContractAddress string
+	Initiator       string
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	IncludeArchived bool
+}
+
+// AdminFlowResponse 管理员流程列表项（合并 Compound 与 OpenZeppelin）
+type AdminFlowResponse struct {
+	Standard          string     `json:"standard"`                     // 标准compound, openzeppelin
+	FlowID            string     `json:"flow_id"`                      // 流程ID
+	ChainID           int        `json:"chain_id"`                     // 链ID
+	ContractAddress   string     `json:"contract_address"`             // 合约地址
+	ContractRemark    string     `json:"contract_remark"`              // 合约备注（合约被多个用户导入时取最早导入的记录）
+	ContractCreator   string     `json:"contract_creator"`             // 合约创建者/导入者地址（同上）
+	ContractRemoved   bool       `json:"contract_removed"`             // 合约已被所有导入者删除（flow 仍保留，等待孤立 flow 清理任务归档）
+	Status            string     `json:"status"`                       // 状态
+	QueueTxHash       *string    `json:"queue_tx_hash,omitempty"`      // 排队交易哈希（OpenZeppelin 为 schedule 交易）
+	ExecuteTxHash     *string    `json:"execute_tx_hash,omitempty"`    // 执行交易哈希
+	CancelTxHash      *string    `json:"cancel_tx_hash,omitempty"`     // 取消交易哈希
+	InitiatorAddress  *string    `json:"initiator_address,omitempty"`  // 发起者地址
+	TargetAddress     *string    `json:"target_address,omitempty"`     // 目标地址
+	FunctionSignature *string    `json:"function_signature,omitempty"` // 函数签名（仅 Compound）
+	Value             string     `json:"value"`                        // 价值（wei）
+	Eta               *time.Time `json:"eta,omitempty"`                // 可执行时间
+	ExecutedAt        *time.Time `json:"executed_at,omitempty"`        // 执行时间
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`       // 取消时间
+	CreatedAt         time.Time  `json:"created_at"`                   // 创建时间
+	UpdatedAt         time.Time  `json:"updated_at"`                   // 更新时间
+}
+
+// OrphanedFlowContract 合约已被删除但仍有 flow 留在热表中的合约
+type OrphanedFlowContract struct {
+	Standard        string    `json:"standard"`         // 标准compound, openzeppelin
+	ChainID         int       `json:"chain_id"`         // 链ID
+	ContractAddress string    `json:"contract_address"` // 合约地址（小写）
+	FlowCount       int64     `json:"flow_count"`       // 孤立 flow 数量
+	LastUpdatedAt   time.Time `json:"last_updated_at"`  // 这些 flow 中最近一次更新时间
+}
+
+// GetOrphanedFlowsResponse 孤立 flow 汇总
+type GetOrphanedFlowsResponse struct {
+	Contracts  []OrphanedFlowContract `json:"contracts"`   // 按 flow 数量倒序
+	TotalFlows int64                  `json:"total_flows"` // 孤立 flow 总数
+}
+
+// GetAdminFlowListResponse 管理员查询全部流程响应
+type GetAdminFlowListResponse struct {
+	Flows []AdminFlowResponse `json:"flows"` // 按创建时间倒序
+	PaginationMeta
+}
+
+// MaintenanceStatus 维护模式状态
+type MaintenanceStatus struct {
+	Enabled   bool      `json:"enabled"`           // 是否处于维护模式
+	Message   string    `json:"message,omitempty"` // 维护提示（仅开启时返回）
+	UpdatedBy string    `json:"updated_by"`        // 最后切换人（管理员地址，启动时为 config）
+	UpdatedAt time.Time `json:"updated_at"`        // 最后切换时间
+}
+
+// SetMaintenanceModeRequest 管理员切换维护模式请求
+type SetMaintenanceModeRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"` // 是否开启维护模式
+	Message string `json:"message" binding:"max=500"`  // 维护提示，为空时使用默认提示
+}
+
+// SubgraphCheckResult 单条链的 subgraph 自检结果
+type SubgraphCheckResult struct {
+	ChainID           int       `json:"chain_id"`
+	SubgraphURL       string    `json:"subgraph_url"`
+	Reachable         bool      `json:"reachable"`                     // 请求是否成功返回（HTTP 200 且为合法 JSON）
+	SchemaCompatible  bool      `json:"schema_compatible"`             // 是否包含服务端查询依赖的实体与字段
+	LatestBlock       *int64    `json:"latest_block,omitempty"`        // subgraph 已索引到的区块
+	HasIndexingErrors bool      `json:"has_indexing_errors,omitempty"` // subgraph 是否报告索引错误
+	Error             string    `json:"error,omitempty"`               // 不可用原因
+	LatencyMs         int64     `json:"latency_ms"`
+	CheckedAt         time.Time `json:"checked_at"`
+}
+
+// Healthy subgraph 可达且 schema 兼容
+func (r SubgraphCheckResult) Healthy() bool {
+	return r.Reachable && r.SchemaCompatible
+}
+
+// CheckSubgraphsResponse 管理员 subgraph 自检响应
+type CheckSubgraphsResponse struct {
+	Results []SubgraphCheckResult `json:"results"` // 按 chain_id 升序
+	Healthy int                   `json:"healthy"`
+	Broken  int                   `json:"broken"`
+}
+
+// GetAdminErrorLogListRequest 管理员查询 error_logs 请求
+type GetAdminErrorLogListRequest struct {
+	Level    string     `json:"level" form:"level" binding:"omitempty,oneof=error fatal warn"` // 日志级别，为空时查询全部
+	Caller   string     `json:"caller" form:"caller"`                                          // 调用位置（文件:行号）前缀
+	Function string     `json:"function" form:"function"`                                      // 函数名
+	Keyword  string     `json:"keyword" form:"keyword" binding:"max=200"`                      // 消息或错误信息关键字（不区分大小写）
+	From     *time.Time `json:"from" form:"from" time_format:"2006-01-02T15:04:05Z07:00"`      // 时间下限（含），RFC3339
+	To       *time.Time `json:"to" form:"to" time_format:"2006-01-02T15:04:05Z07:00"`          // 时间上限（不含），RFC3339
+	Page     int        `json:"page" form:"page"`                                              // 页码，默认为1
+	PageSize int        `json:"page_size" form:"page_size"`                                    // 每页大小，默认为20，最大100
+}
+
+// AdminErrorLogFilter 管理员查询 error_logs 的过滤条件（已标准化）
+type AdminErrorLogFilter struct {
+	Level    string
+	Caller   string
+	Function string
+	Keyword  string
+	From     *time.Time
+	To       *time.Time
+}
+
+// AdminErrorLog error_logs 表中的一条日志
+type AdminErrorLog struct {
+	ID        int64     `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"` // error, fatal, warn
+	Caller    string    `json:"caller"`
+	Function  string    `json:"function"`
+	Message   string    `json:"message"`
+	Error     string    `json:"error"`
+	Context   string    `json:"context"` // 上下文字段（JSON 字符串）
+}
+
+// GetAdminErrorLogListResponse 管理员查询 error_logs 响应
+type GetAdminErrorLogListResponse struct {
+	Logs []AdminErrorLog `json:"logs"` // 按时间倒序
+	PaginationMeta
+}
+
+// RecalcFlowStatusRequest 管理员立即重算流程状态请求，user_address 与 chain_id + contract_address 二选一
+type RecalcFlowStatusRequest struct {
+	UserAddress     string `json:"user_address"`     // 用户地址，重算与该用户相关的流程
+	ChainID         int    `json:"chain_id"`         // 链ID（合约范围）
+	ContractAddress string `json:"contract_address"` // 合约地址（合约范围）
+}
+
+// FlowStatusChange 一次流程状态变更
+type FlowStatusChange struct {
+	Standard        string `json:"standard"`
+	FlowID          string `json:"flow_id"`
+	ChainID         int    `json:"chain_id"`
+	ContractAddress string `json:"contract_address"`
+	StatusFrom      string `json:"status_from"`
+	StatusTo        string `json:"status_to"`
+}
+
+// RecalcFlowStatusResponse 管理员立即重算流程状态响应
+type RecalcFlowStatusResponse struct {
+	Checked   int                `json:"checked"`   // 到期待重算的流程数
+	Changes   []FlowStatusChange `json:"changes"`   // 实际发生的状态变更（已写库并投递通知）
+	Truncated bool               `json:"truncated"` // 是否达到单次上限，需再次调用
+}
@@ -0,0 +1,52 @@
+package types
+
+import "time"
+
+// APIToken 作用域
+const (
+	APITokenScopeRead = "read" // 只读：仅允许访问查询类接口
+)
+
+// APIToken 长期有效的API访问令牌（用于脚本/集成方免SIWE登录）
+type APIToken struct {
+	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        int64      `json:"user_id" gorm:"not null;index"`
+	WalletAddress string     `json:"wallet_address" gorm:"size:42;not null;index"`
+	Name          string     `json:"name" gorm:"size:100;not null"`
+	TokenHash     string     `json:"-" gorm:"size:64;not null;unique"`     // sha256(token) 十六进制，不存明文
+	TokenPrefix   string     `json:"token_prefix" gorm:"size:16;not null"` // 明文前缀，便于用户识别
+	Scope         string     `json:"scope" gorm:"size:20;not null;default:'read'"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	LastUsedAt    *time.Time `json:"last_used_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// CreateAPITokenRequest 创建API令牌请求
+type CreateAPITokenRequest struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=1,max=3650"` // 为空表示永不过期
+}
+
+// CreateAPITokenResponse 创建API令牌响应（明文token仅返回这一次）
+type CreateAPITokenResponse struct {
+	Token    string   `json:"token"`
+	APIToken APIToken `json:"api_token"`
+}
+
+// RevokeAPITokenRequest 吊销API令牌请求
+type RevokeAPITokenRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+// APITokenListResponse API令牌列表响应
+type APITokenListResponse struct {
+	Tokens []APIToken `json:"tokens"`
+	Total  int64      `json:"total"`
+}
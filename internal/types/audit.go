@@ -0,0 +1,56 @@
+package types
+
+import "time"
+
+// 审计日志动作
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// 审计日志目标类型
+const (
+	AuditTargetNotificationConfig = "notification_config"
+	AuditTargetTimelock           = "timelock"
+	AuditTargetEmail              = "email"
+	AuditTargetAPIToken           = "api_token"
+)
+
+// AuditLog 配置变更审计日志，与被审计的变更写在同一事务中
+type AuditLog struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorAddress string    `json:"actor_address" gorm:"size:42;not null"` // 操作人钱包地址（小写）
+	Action       string    `json:"action" gorm:"size:20;not null"`        // create, update, delete
+	TargetType   string    `json:"target_type" gorm:"size:50;not null"`   // notification_config, timelock, email, api_token
+	TargetID     string    `json:"target_id" gorm:"size:200;not null"`    // 目标标识，如 telegram:name、compound:1:0x...、用户邮箱ID
+	Summary      string    `json:"summary" gorm:"type:text;not null"`     // 变更摘要（只含字段名与非敏感值）
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 设置表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// GetAuditLogsRequest 查询审计日志请求
+type GetAuditLogsRequest struct {
+	Actor      string `json:"actor" form:"actor"`                                                                                    // 操作人地址，仅管理员可指定，为空时管理员查询全部
+	TargetType string `json:"target_type" form:"target_type" binding:"omitempty,oneof=notification_config timelock email api_token"` // 目标类型，为空时查询全部
+	Action     string `json:"action" form:"action" binding:"omitempty,oneof=create update delete"`                                   // 动作，为空时查询全部
+	Page       int    `json:"page" form:"page"`                                                                                      // 页码，默认为1
+	PageSize   int    `json:"page_size" form:"page_size"`                                                                            // 每页大小，默认为20，最大100
+}
+
+// AuditLogFilter 审计日志过滤条件（已标准化）
+type AuditLogFilter struct {
+	ActorAddress string
+	TargetType   string
+	Action       string
+}
+
+// GetAuditLogsResponse 查询审计日志响应
+type GetAuditLogsResponse struct {
+	Logs []AuditLog `json:"logs"` // 按创建时间倒序
+	PaginationMeta
+}
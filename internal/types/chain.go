@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -24,6 +25,8 @@ type SupportChain struct {
 	SubgraphURL            string    `json:"subgraph_url" gorm:"type:text"`                       // Goldsky Subgraph URL
 	CompoundWebhookSecret  string    `json:"compound_webhook_secret" gorm:"type:text"`            // Goldsky Compound Webhook Secret
 	OZWebhookSecret        string    `json:"oz_webhook_secret" gorm:"type:text"`                  // Goldsky OpenZeppelin Webhook Secret
+	ExplorerAPIKey         string    `json:"-" gorm:"type:text"`                                  // 区块浏览器 API Key（不对外输出）
+	ConfirmationDepth      int       `json:"confirmation_depth" gorm:"not null;default:0"`        // Webhook 事件所在区块需达到的确认数，0 表示收到即处理
 	CreatedAt              time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt              time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -54,6 +57,18 @@ func (SupportChain) TableName() string {
 	return "support_chains"
 }
 
+// FallbackSupportChain 链信息查询失败时通知展示用的占位链信息：名称显示为链ID，没有区块浏览器链接，原生代币按 ETH 展示
+func FallbackSupportChain(chainID int) *SupportChain {
+	name := strconv.Itoa(chainID)
+	return &SupportChain{
+		ChainName:            name,
+		DisplayName:          name,
+		ChainID:              int64(chainID),
+		NativeCurrencySymbol: "ETH",
+		BlockExplorerUrls:    "[]",
+	}
+}
+
 // WalletChainConfig 钱包插件添加链的配置数据
 type WalletChainConfig struct {
 	ChainID           string               `json:"chainId"`
@@ -107,3 +122,29 @@ type ChainRPCInfo struct {
 	RPCEnabled         bool    `json:"rpc_enabled" db:"rpc_enabled"`
 	IsTestnet          bool    `json:"is_testnet" db:"is_testnet"`
 }
+
+// SetExplorerAPIKeyRequest 管理员设置/轮换区块浏览器 API Key 请求
+type SetExplorerAPIKeyRequest struct {
+	ChainID int64  `json:"chain_id" binding:"required"` // 链ID
+	APIKey  string `json:"api_key" binding:"max=200"`   // 新的 API Key，为空时清除（回退到默认 key）
+}
+
+// SetConfirmationDepthRequest 管理员设置链的 Webhook 事件确认深度请求
+type SetConfirmationDepthRequest struct {
+	ChainID           int64 `json:"chain_id" binding:"required"`                          // 链ID
+	ConfirmationDepth *int  `json:"confirmation_depth" binding:"required,min=0,max=1000"` // 确认数，0 表示收到即处理
+}
+
+// ConfirmationDepthStatus 链的 Webhook 事件确认深度
+type ConfirmationDepthStatus struct {
+	ChainID           int64 `json:"chain_id"`
+	ConfirmationDepth int   `json:"confirmation_depth"`
+}
+
+// ExplorerAPIKeyStatus 区块浏览器 API Key 配置状态（不返回明文）
+type ExplorerAPIKeyStatus struct {
+	ChainID         int64  `json:"chain_id"`
+	Configured      bool   `json:"configured"`           // 链是否单独配置了 key
+	MaskedKey       string `json:"masked_key,omitempty"` // 脱敏后的 key
+	UsingDefaultKey bool   `json:"using_default_key"`    // 是否回退使用默认 key
+}
@@ -26,6 +26,7 @@ type UserEmail struct {
 	Remark         *string    `json:"remark" gorm:"size:200"`
 	IsVerified     bool       `json:"is_verified" gorm:"not null;default:false"`
 	LastVerifiedAt *time.Time `json:"last_verified_at"`
+	NotifyStatuses *string    `json:"notify_statuses" gorm:"type:text"` // 接收通知的目标状态列表（JSON），NULL 表示全部
 	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -73,6 +74,10 @@ type EmailSendLog struct {
 	ErrorMessage     *string   `json:"error_message" gorm:"type:text"`
 	RetryCount       int       `json:"retry_count" gorm:"not null;default:0"`
 	SentAt           time.Time `json:"sent_at" gorm:"not null;autoCreateTime"`
+	// 失败日志：PermanentFailure 为 true 表示永久失败（5xx 或重试次数耗尽），不再重发；
+	// 否则为临时失败，后台任务在 NextRetryAt 之后重发
+	PermanentFailure bool       `json:"permanent_failure" gorm:"not null;default:false"`
+	NextRetryAt      *time.Time `json:"next_retry_at"`
 
 	// 关联
 	Email *Email `json:"email,omitempty" gorm:"foreignKey:EmailID"`
@@ -83,6 +88,22 @@ func (EmailSendLog) TableName() string {
 	return "email_send_logs"
 }
 
+// UserEmailPreference 用户邮件发送偏好（抄送、密送与发件人名称）
+type UserEmailPreference struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `json:"user_id" gorm:"not null;uniqueIndex"`
+	CcEmail   *string   `json:"cc_email" gorm:"size:200"`
+	BccEmail  *string   `json:"bcc_email" gorm:"size:200"`
+	FromName  *string   `json:"from_name" gorm:"size:64"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (UserEmailPreference) TableName() string {
+	return "user_email_preferences"
+}
+
 // ===== 请求响应结构体 =====
 
 // UpdateEmailRemarkRequest 更新邮箱备注请求
@@ -102,12 +123,42 @@ type SendVerificationCodeRequest struct {
 	Remark *string `json:"remark"`
 }
 
+// 批量重发验证码的单个邮箱结果
+const (
+	ResendVerificationStatusSent          = "sent"          // 已发送新验证码
+	ResendVerificationStatusRateLimited   = "rate_limited"  // 该邮箱最近已发送过验证码，需稍后再试
+	ResendVerificationStatusUndeliverable = "undeliverable" // 邮箱被标记为不可投递，跳过
+	ResendVerificationStatusFailed        = "failed"        // 发送失败
+)
+
+// ResendVerificationResult 批量重发验证码的单个邮箱结果
+type ResendVerificationResult struct {
+	UserEmailID int64  `json:"user_email_id"`
+	Email       string `json:"email"`
+	Success     bool   `json:"success"`
+	Status      string `json:"status"`          // 见 ResendVerificationStatus*
+	Error       string `json:"error,omitempty"` // 失败原因
+}
+
+// ResendAllVerificationResponse 批量重发验证码响应（只包含未验证的邮箱）
+type ResendAllVerificationResponse struct {
+	Results []ResendVerificationResult `json:"results"`
+	Sent    int                        `json:"sent"`
+	Failed  int                        `json:"failed"` // 未发送的数量（限频、不可投递或发送失败）
+}
+
 // VerifyEmailRequest 验证邮箱请求
 type VerifyEmailRequest struct {
 	Email string `json:"email"`
 	Code  string `json:"code"`
 }
 
+// UpdateEmailNotifyStatusesRequest 设置邮箱接收通知的状态过滤
+type UpdateEmailNotifyStatusesRequest struct {
+	ID             int64    `json:"id" binding:"required"`
+	NotifyStatuses []string `json:"notify_statuses" binding:"dive,oneof=waiting ready executed cancelled expired"` // 为空表示接收全部状态
+}
+
 // DeleteEmailRequest 删除邮箱请求
 type DeleteEmailRequest struct {
 	ID int64 `json:"id" binding:"required"`
@@ -116,7 +167,7 @@ type DeleteEmailRequest struct {
 // GetEmailsRequest 获取邮箱列表请求
 type GetEmailsRequest struct {
 	Page     int `json:"page" form:"page"`
-	PageSize int `json:"page_size" form:"page_size"`
+	PageSize int `json:"page_size" form:"page_size"` // 每页大小，默认为10，最大100
 }
 
 // UserEmailResponse 用户邮箱响应
@@ -126,6 +177,7 @@ type UserEmailResponse struct {
 	Remark         *string    `json:"remark"`
 	IsVerified     bool       `json:"is_verified"`
 	LastVerifiedAt *time.Time `json:"last_verified_at"`
+	NotifyStatuses []string   `json:"notify_statuses"` // 接收通知的目标状态，为空表示全部
 	CreatedAt      time.Time  `json:"created_at"`
 }
 
@@ -133,6 +185,21 @@ type UserEmailResponse struct {
 type EmailListResponse struct {
 	Emails []UserEmailResponse `json:"emails"`
 	Total  int64               `json:"total"`
+	PaginationMeta
+}
+
+// UpdateEmailPreferencesRequest 更新邮件偏好请求（字段为 nil 表示不修改，空字符串表示清除）
+type UpdateEmailPreferencesRequest struct {
+	CcEmail  *string `json:"cc_email"`
+	BccEmail *string `json:"bcc_email"`
+	FromName *string `json:"from_name"`
+}
+
+// EmailPreferencesResponse 邮件偏好响应
+type EmailPreferencesResponse struct {
+	CcEmail  string `json:"cc_email"`
+	BccEmail string `json:"bcc_email"`
+	FromName string `json:"from_name"` // 未设置时为系统默认发件人名称
 }
 
 // NotificationStatus 通知状态枚举
@@ -4,16 +4,20 @@ import "time"
 
 // GetCompoundFlowListRequest 获取流程列表请求
 type GetCompoundFlowListRequest struct {
-	Status   *string `json:"status" form:"status"`       // 状态all, waiting, ready, executed, cancelled, expired
-	Standard *string `json:"standard" form:"standard"`   // 标准compound, openzeppelin
-	Page     int     `json:"page" form:"page"`           // 页码，默认为1
-	PageSize int     `json:"page_size" form:"page_size"` // 每页大小，默认为10，最大100
+	Status    *string `json:"status" form:"status"`       // 状态all, waiting, ready, executed, cancelled, expired
+	Standard  *string `json:"standard" form:"standard"`   // 标准compound, openzeppelin
+	Initiator string  `json:"initiator" form:"initiator"` // 发起人地址（不区分大小写），为空时不过滤
+	Page      int     `json:"page" form:"page"`           // 页码，默认为1
+	PageSize  int     `json:"page_size" form:"page_size"` // 每页大小，默认为10，最大100
+	// 是否包含已归档的终态流程（默认只查询未归档的流程）
+	IncludeArchived bool `json:"include_archived" form:"include_archived"`
 }
 
 // GetCompoundFlowListResponse 获取流程列表响应
 type GetCompoundFlowListResponse struct {
 	Flows []CompoundFlowResponse `json:"flows"` // 流程列表
 	Total int64                  `json:"total"` // 总数
+	PaginationMeta
 }
 
 // CompoundFlowResponse 流程响应结构
@@ -29,6 +33,7 @@ type CompoundFlowResponse struct {
 	ExecuteTxHash     *string    `json:"execute_tx_hash,omitempty"`    // 执行交易哈希
 	CancelTxHash      *string    `json:"cancel_tx_hash,omitempty"`     // 取消交易哈希
 	InitiatorAddress  *string    `json:"initiator_address,omitempty"`  // 发起者地址(FromAddress)
+	CancellerAddress  *string    `json:"canceller_address,omitempty"`  // 取消者地址（取消交易的发起地址）
 	TargetAddress     *string    `json:"target_address,omitempty"`     // 目标地址
 	FunctionSignature *string    `json:"function_signature,omitempty"` // 函数签名
 	CallDataHex       *string    `json:"call_data_hex,omitempty"`      // 调用数据
@@ -39,6 +44,25 @@ type CompoundFlowResponse struct {
 	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`       // 取消时间
 	CreatedAt         time.Time  `json:"created_at"`                   // 创建时间
 	UpdatedAt         time.Time  `json:"updated_at"`                   // 更新时间
+	// 目标地址对应的、当前用户管理的其他 timelock 合约（便于在关联的 timelock 之间跳转）
+	RelatedContracts []RelatedContract `json:"related_contracts,omitempty"`
+	// 相关用户附加的链下备注（如取消原因）
+	Note *FlowNote `json:"note,omitempty"`
+	// 当前用户通过 /abi/:id/redecode 解码得到的调用信息
+	DecodedCalls []DecodedFlowCall `json:"decoded_calls,omitempty"`
+	// expired_at 是否为按兜底宽限期估算的值
+	ExpiredAtEstimated bool `json:"expired_at_estimated,omitempty"`
+	// 执行交易回执状态 success/failed；failed 时 execute_tx_hash 为回滚的执行交易
+	ExecuteTxStatus *string `json:"execute_tx_status,omitempty"`
+}
+
+// RelatedContract 流程目标地址对应的用户 timelock 合约
+type RelatedContract struct {
+	Standard        string `json:"standard"`         // 标准compound, openzeppelin
+	ID              int64  `json:"id"`               // 合约记录ID
+	ChainID         int    `json:"chain_id"`         // 链ID
+	ContractAddress string `json:"contract_address"` // 合约地址
+	Remark          string `json:"remark"`           // 合约备注
 }
 
 type FlowStatusCount struct {
@@ -57,3 +81,211 @@ type GetCompoundFlowListCountRequest struct {
 type GetCompoundFlowListCountResponse struct {
 	FlowCount FlowStatusCount `json:"flow_count"` // 流程数量
 }
+
+// FlowNote 相关用户附加在流程上的链下备注（每个流程一条，后写覆盖）
+type FlowNote struct {
+	ID              int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	Standard        string    `json:"-" gorm:"size:20;not null"`
+	ChainID         int       `json:"-" gorm:"not null"`
+	ContractAddress string    `json:"-" gorm:"size:42;not null"` // 小写
+	FlowID          string    `json:"-" gorm:"size:128;not null"`
+	Note            string    `json:"note" gorm:"type:text;not null"`
+	UpdatedBy       string    `json:"updated_by" gorm:"size:42;not null"` // 最后修改人地址（小写）
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (FlowNote) TableName() string {
+	return "flow_notes"
+}
+
+// SetFlowNoteRequest 设置流程备注请求，note 为空时清除备注
+type SetFlowNoteRequest struct {
+	FlowIdentifier
+	Note string `json:"note" binding:"max=1000"` // 备注内容，最多 1000 字符
+}
+
+// SetFlowNoteResponse 设置流程备注响应
+type SetFlowNoteResponse struct {
+	Standard        string    `json:"standard"`
+	ChainID         int       `json:"chain_id"`
+	ContractAddress string    `json:"contract_address"`
+	FlowID          string    `json:"flow_id"`
+	Note            *FlowNote `json:"note"` // 清除备注时为 null
+}
+
+// FlowFlag 相关用户对可疑流程的标记（如非预期的管理员变更），同一流程可被多次标记
+type FlowFlag struct {
+	ID              int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Standard        string    `json:"standard" gorm:"size:20;not null"`
+	ChainID         int       `json:"chain_id" gorm:"not null"`
+	ContractAddress string    `json:"contract_address" gorm:"size:42;not null"` // 小写
+	FlowID          string    `json:"flow_id" gorm:"size:128;not null"`
+	FlaggedBy       string    `json:"flagged_by" gorm:"size:42;not null"` // 标记人地址（小写）
+	Note            string    `json:"note" gorm:"type:text;not null"`
+	Notified        bool      `json:"notified" gorm:"not null;default:false"` // 是否已向标记人的通知渠道发送告警
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 设置表名
+func (FlowFlag) TableName() string {
+	return "flow_flags"
+}
+
+// FlagFlowRequest 标记可疑流程请求
+type FlagFlowRequest struct {
+	FlowIdentifier
+	Note   string `json:"note" binding:"required,max=1000"` // 标记原因，最多 1000 字符
+	Notify bool   `json:"notify"`                           // 是否向自己的通知渠道发送高优先级告警
+}
+
+// GetFlowFlagsRequest 查询合约下的流程标记请求
+type GetFlowFlagsRequest struct {
+	Standard        string `json:"standard" form:"standard" binding:"required,oneof=compound openzeppelin"` // 标准compound, openzeppelin
+	ChainID         int    `json:"chain_id" form:"chain_id" binding:"required"`                             // 链ID
+	ContractAddress string `json:"contract_address" form:"contract_address" binding:"required"`             // 合约地址
+	FlowID          string `json:"flow_id" form:"flow_id"`                                                  // 流程ID，为空时查询合约下全部流程的标记
+	Page            int    `json:"page" form:"page"`                                                        // 页码，默认为1
+	PageSize        int    `json:"page_size" form:"page_size"`                                              // 每页大小，默认为20，最大100
+}
+
+// GetFlowFlagsResponse 查询流程标记响应
+type GetFlowFlagsResponse struct {
+	Flags []FlowFlag `json:"flags"` // 按标记时间倒序
+	PaginationMeta
+}
+
+// FlowIdentifier 定位单个流程的通用参数
+type FlowIdentifier struct {
+	Standard        string `json:"standard" form:"standard" binding:"required,oneof=compound openzeppelin"` // 标准compound, openzeppelin
+	ChainID         int    `json:"chain_id" form:"chain_id" binding:"required"`                             // 链ID
+	ContractAddress string `json:"contract_address" form:"contract_address" binding:"required"`             // 合约地址
+	FlowID          string `json:"flow_id" form:"flow_id" binding:"required"`                               // 流程ID
+}
+
+// FlowStatusHistory 流程状态变更历史
+type FlowStatusHistory struct {
+	ID               int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	FlowID           string    `json:"flow_id" gorm:"size:128;not null"`
+	TimelockStandard string    `json:"timelock_standard" gorm:"size:20;not null"`
+	ChainID          int       `json:"chain_id" gorm:"not null"`
+	ContractAddress  string    `json:"contract_address" gorm:"size:42;not null"`
+	StatusFrom       string    `json:"status_from" gorm:"size:20"` // 新建流程时为空
+	StatusTo         string    `json:"status_to" gorm:"size:20;not null"`
+	ChangedAt        time.Time `json:"changed_at" gorm:"not null"`
+	ChangedBy        *string   `json:"changed_by,omitempty" gorm:"size:42"` // 管理员手动变更时的操作人地址
+	Reason           *string   `json:"reason,omitempty" gorm:"size:500"`    // 管理员手动变更的原因
+}
+
+// TableName 设置表名
+func (FlowStatusHistory) TableName() string {
+	return "flow_status_history"
+}
+
+// GetFlowStatusHistoryRequest 获取流程状态历史请求
+type GetFlowStatusHistoryRequest struct {
+	FlowIdentifier
+}
+
+// GetFlowStatusHistoryResponse 获取流程状态历史响应
+type GetFlowStatusHistoryResponse struct {
+	History []FlowStatusHistory `json:"history"` // 按时间升序
+}
+
+// GetFlowCallsRequest 获取流程子调用请求
+type GetFlowCallsRequest struct {
+	FlowIdentifier
+}
+
+// FlowCallResponse 流程子调用
+type FlowCallResponse struct {
+	Index         int    `json:"index"`          // 子调用序号
+	TargetAddress string `json:"target_address"` // 目标地址
+	Value         string `json:"value"`          // 价值
+	CallDataHex   string `json:"call_data_hex"`  // 调用数据（含函数选择器）
+}
+
+// GetFlowCallsResponse 获取流程子调用响应
+type GetFlowCallsResponse struct {
+	IsBatch              bool               `json:"is_batch"`               // 是否为批量操作（scheduleBatch）
+	Calls                []FlowCallResponse `json:"calls"`                  // 按序号升序
+	Predecessor          string             `json:"predecessor,omitempty"`  // 前置操作 id（仅 OpenZeppelin，没有依赖时为空）
+	BlockedByPredecessor bool               `json:"blocked_by_predecessor"` // 是否因前置操作尚未执行而无法执行
+}
+
+// GetActionableFlowsRequest 获取待处理流程请求
+type GetActionableFlowsRequest struct {
+	Limit int `json:"limit" form:"limit"` // 返回数量，默认为50，最大100
+}
+
+// ActionableFlowResponse 待处理流程（ready 可立即执行，或 waiting 且即将到达 eta）
+type ActionableFlowResponse struct {
+	Standard         string             `json:"standard"`                    // 标准compound, openzeppelin
+	FlowID           string             `json:"flow_id"`                     // 流程ID
+	ChainID          int                `json:"chain_id"`                    // 链ID
+	ContractAddress  string             `json:"contract_address"`            // 合约地址
+	Status           string             `json:"status"`                      // 状态 ready, waiting
+	Eta              *time.Time         `json:"eta,omitempty"`               // 可执行时间
+	ExpiredAt        *time.Time         `json:"expired_at,omitempty"`        // 过期时间（仅 Compound）
+	InitiatorAddress *string            `json:"initiator_address,omitempty"` // 发起者地址
+	Target           string             `json:"target"`                      // 目标地址，批量操作为 "Batch (N calls)"
+	Function         string             `json:"function"`                    // 函数签名（OpenZeppelin 无 ABI 时为选择器）
+	Value            string             `json:"value"`                       // 价值（原生代币，已格式化）
+	CalldataParams   []CalldataParam    `json:"calldata_params"`             // 解码后的参数
+	Calls            []NotificationCall `json:"calls,omitempty"`             // 批量操作的子调用
+	// 前置操作 id（仅 OpenZeppelin，没有依赖时为空）
+	Predecessor string `json:"predecessor,omitempty"`
+	// 是否因前置操作尚未执行而无法执行（eta 已到也保持 waiting）
+	BlockedByPredecessor bool `json:"blocked_by_predecessor"`
+	// expired_at 是否为按兜底宽限期估算的值（仅 Compound）
+	ExpiredAtEstimated bool `json:"expired_at_estimated,omitempty"`
+}
+
+// GetActionableFlowsResponse 获取待处理流程响应
+type GetActionableFlowsResponse struct {
+	Flows []ActionableFlowResponse `json:"flows"` // 按 eta 升序（最紧急的在前）
+}
+
+// GetFlowCalendarRequest 获取流程日历请求
+type GetFlowCalendarRequest struct {
+	From     string `json:"from" form:"from" binding:"required"` // 开始日期（含），YYYY-MM-DD
+	To       string `json:"to" form:"to" binding:"required"`     // 结束日期（含），YYYY-MM-DD
+	Timezone string `json:"tz" form:"tz"`                        // 按该时区划分日期（IANA 名称，如 Asia/Shanghai），默认 UTC
+}
+
+// FlowCalendarItem 日历中的流程摘要
+type FlowCalendarItem struct {
+	Standard          string    `json:"standard"`                     // 标准compound, openzeppelin
+	FlowID            string    `json:"flow_id"`                      // 流程ID
+	ChainID           int       `json:"chain_id"`                     // 链ID
+	ContractAddress   string    `json:"contract_address"`             // 合约地址
+	Status            string    `json:"status"`                       // 状态 waiting, ready
+	Eta               time.Time `json:"eta"`                          // 可执行时间
+	TargetAddress     *string   `json:"target_address,omitempty"`     // 目标地址
+	FunctionSignature *string   `json:"function_signature,omitempty"` // 函数签名（仅 Compound）
+}
+
+// FlowCalendarEntry 按日期分桶后的流程（仓储层查询结果）
+type FlowCalendarEntry struct {
+	Day string // 所在日期（按请求时区），YYYY-MM-DD
+	FlowCalendarItem
+}
+
+// FlowCalendarDay 单日的流程汇总
+type FlowCalendarDay struct {
+	Date    string             `json:"date"`    // 日期，YYYY-MM-DD
+	Total   int                `json:"total"`   // 当日流程数
+	Waiting int                `json:"waiting"` // waiting 流程数
+	Ready   int                `json:"ready"`   // ready 流程数
+	Flows   []FlowCalendarItem `json:"flows"`   // 当日流程，按 eta 升序
+}
+
+// GetFlowCalendarResponse 获取流程日历响应
+type GetFlowCalendarResponse struct {
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Timezone  string            `json:"tz"`
+	Days      []FlowCalendarDay `json:"days"`      // 只包含有流程的日期，按日期升序
+	Truncated bool              `json:"truncated"` // 流程数超过上限时为 true，只返回最早的部分
+}
@@ -1,6 +1,12 @@
 package types
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// ExecuteTxStatusSuccess 执行交易状态：执行事件只来自成功的交易（回滚的交易不产生日志）
+const ExecuteTxStatusSuccess = "success"
 
 // CompoundTimelockFlowDB Compound Timelock Flow 数据库模型
 type CompoundTimelockFlowDB struct {
@@ -14,6 +20,7 @@ type CompoundTimelockFlowDB struct {
 	ExecuteTxHash     *string    `gorm:"size:66"`
 	CancelTxHash      *string    `gorm:"size:66"`
 	InitiatorAddress  *string    `gorm:"size:42"`
+	CancellerAddress  *string    `gorm:"size:42"` // 取消交易的发起地址（cancel_tx_hash 的 sender）
 	TargetAddress     *string    `gorm:"size:42"`
 	Value             string     `gorm:"type:decimal(78,0);not null;default:0"`
 	CallData          []byte     `gorm:"type:bytea"`
@@ -26,6 +33,10 @@ type CompoundTimelockFlowDB struct {
 	CancelledAt       *time.Time `gorm:"type:timestamptz"`
 	CreatedAt         time.Time  `gorm:"not null;default:now()"`
 	UpdatedAt         time.Time  `gorm:"not null;default:now()"`
+	// grace_period/expired_at 由合约宽限期兜底值估算（数据源未提供且合约 GRACE_PERIOD 读取失败）
+	GracePeriodEstimated bool `gorm:"not null;default:false"`
+	// 执行交易回执状态 success/failed，未检查时为空；failed 时流程保持原状态，execute_tx_hash 为回滚的交易
+	ExecuteTxStatus *string `gorm:"size:20"`
 }
 
 // TableName 设置表名
@@ -33,6 +44,19 @@ func (CompoundTimelockFlowDB) TableName() string {
 	return "compound_timelock_flows"
 }
 
+// TxHashForStatus 获取进入指定状态的交易哈希（ready/expired 由定时任务推进，没有交易）
+func (f *CompoundTimelockFlowDB) TxHashForStatus(status string) *string {
+	switch status {
+	case "waiting":
+		return f.QueueTxHash
+	case "executed":
+		return f.ExecuteTxHash
+	case "cancelled":
+		return f.CancelTxHash
+	}
+	return nil
+}
+
 // OpenzeppelinTimelockFlowDB OpenZeppelin Timelock Flow 数据库模型
 type OpenzeppelinTimelockFlowDB struct {
 	ID               int64      `gorm:"primaryKey;autoIncrement"`
@@ -45,9 +69,11 @@ type OpenzeppelinTimelockFlowDB struct {
 	ExecuteTxHash    *string    `gorm:"size:66"`
 	CancelTxHash     *string    `gorm:"size:66"`
 	InitiatorAddress *string    `gorm:"size:42"`
+	CancellerAddress *string    `gorm:"size:42"` // 取消交易的发起地址（cancel_tx_hash 的 sender）
 	TargetAddress    *string    `gorm:"size:42"`
 	Value            string     `gorm:"type:decimal(78,0);not null;default:0"`
 	CallData         []byte     `gorm:"type:bytea"`
+	Predecessor      *string    `gorm:"size:66"` // 前置操作 id（小写），必须先执行该操作本操作才能执行；没有依赖（全零）时为空
 	QueuedAt         *time.Time `gorm:"type:timestamptz"`
 	Delay            *int64
 	Eta              *time.Time `gorm:"type:timestamptz"`
@@ -55,6 +81,8 @@ type OpenzeppelinTimelockFlowDB struct {
 	CancelledAt      *time.Time `gorm:"type:timestamptz"`
 	CreatedAt        time.Time  `gorm:"not null;default:now()"`
 	UpdatedAt        time.Time  `gorm:"not null;default:now()"`
+	// 执行交易回执状态 success/failed，未检查时为空；failed 时流程保持原状态，execute_tx_hash 为回滚的交易
+	ExecuteTxStatus *string `gorm:"size:20"`
 }
 
 // TableName 设置表名
@@ -62,8 +90,64 @@ func (OpenzeppelinTimelockFlowDB) TableName() string {
 	return "openzeppelin_timelock_flows"
 }
 
+// NormalizeOpenzeppelinPredecessor 标准化事件中的 predecessor：转小写，全零（无依赖）或为空时返回 nil
+func NormalizeOpenzeppelinPredecessor(predecessor *string) *string {
+	if predecessor == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*predecessor))
+	if strings.Trim(strings.TrimPrefix(normalized, "0x"), "0") == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// TxHashForStatus 获取进入指定状态的交易哈希（ready 由定时任务推进，没有交易）
+func (f *OpenzeppelinTimelockFlowDB) TxHashForStatus(status string) *string {
+	switch status {
+	case "waiting":
+		return f.ScheduleTxHash
+	case "executed":
+		return f.ExecuteTxHash
+	case "cancelled":
+		return f.CancelTxHash
+	}
+	return nil
+}
+
+// OpenzeppelinFlowCallDB OpenZeppelin 操作中的子调用（scheduleBatch 每个 CallScheduled 事件对应一条）
+type OpenzeppelinFlowCallDB struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement"`
+	FlowID          string    `gorm:"size:128;not null"`
+	ChainID         int       `gorm:"not null"`
+	ContractAddress string    `gorm:"size:42;not null"`
+	CallIndex       int       `gorm:"not null"` // 事件中的 index，批量操作内从 0 开始
+	TargetAddress   string    `gorm:"size:42;not null"`
+	Value           string    `gorm:"type:decimal(78,0);not null;default:0"`
+	CallData        []byte    `gorm:"type:bytea"`
+	CreatedAt       time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName 设置表名
+func (OpenzeppelinFlowCallDB) TableName() string {
+	return "openzeppelin_flow_calls"
+}
+
 // GraphQL 返回的数据结构（从 Goldsky 获取）
 
+// RPCFallbackCursor 无 subgraph 的链按合约记录的 RPC 日志扫描进度（持久化，重启后从游标继续）
+type RPCFallbackCursor struct {
+	ChainID         int       `json:"chain_id" gorm:"primaryKey"`
+	ContractAddress string    `json:"contract_address" gorm:"primaryKey;size:42"` // 合约地址（小写）
+	LastBlock       int64     `json:"last_block" gorm:"not null"`                 // 已扫描到的区块（含）
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (RPCFallbackCursor) TableName() string {
+	return "rpc_fallback_cursors"
+}
+
 // GoldskyCompoundFlow Goldsky 返回的 Compound Flow 数据
 type GoldskyCompoundFlow struct {
 	ID                 string                      `json:"id"`
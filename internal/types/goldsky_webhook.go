@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // GraphQLWebhookPayload GraphQL变更通知Webhook请求体
 type GraphQLWebhookPayload struct {
 	Data struct {
@@ -112,3 +114,47 @@ type ParsedWebhookTransaction struct {
 	Eta               *string
 	Delay             *string
 }
+
+// Webhook 事件处理状态
+const (
+	WebhookEventStatusProcessing = "processing"
+	WebhookEventStatusProcessed  = "processed"
+	WebhookEventStatusFailed     = "failed"
+	WebhookEventStatusDeferred   = "deferred" // 确认数不足，暂存在内存中等待确认
+)
+
+// GoldskyWebhookEvent 已接收的 Goldsky webhook 事件（按 event_key 去重，Goldsky 重试投递时不会重复处理）
+// 处理失败的事件保留错误信息，允许 Goldsky 下次重试时重新处理
+type GoldskyWebhookEvent struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	EventKey     string    `json:"event_key" gorm:"size:256;not null;uniqueIndex"` // chain_id:standard:实体ID
+	ChainID      int       `json:"chain_id" gorm:"not null"`
+	Standard     string    `json:"standard" gorm:"size:20;not null"`
+	EventType    string    `json:"event_type" gorm:"size:50;not null"`
+	TxHash       string    `json:"tx_hash" gorm:"size:66"`
+	Status       string    `json:"status" gorm:"size:20;not null;default:'processing'"` // processing, processed, failed, deferred
+	Attempts     int       `json:"attempts" gorm:"not null;default:1"`
+	ErrorMessage *string   `json:"error_message"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 设置表名
+func (GoldskyWebhookEvent) TableName() string {
+	return "goldsky_webhook_events"
+}
+
+// GoldskyWebhookDeadLetter 未通过结构校验被拒绝的 webhook 请求，保留原始内容便于排查
+type GoldskyWebhookDeadLetter struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	WebhookID  string    `json:"webhook_id" gorm:"size:256"`
+	RemoteAddr string    `json:"remote_addr" gorm:"size:64"`
+	Reason     string    `json:"reason" gorm:"type:text;not null"`  // 校验失败原因，多条以 "; " 分隔
+	Payload    string    `json:"payload" gorm:"type:text;not null"` // 原始请求体（超长时截断）
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 设置表名
+func (GoldskyWebhookDeadLetter) TableName() string {
+	return "goldsky_webhook_dead_letters"
+}
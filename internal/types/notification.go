@@ -14,6 +14,7 @@ const (
 	ChannelFeishu   NotificationChannel = "feishu"
 	ChannelDiscord  NotificationChannel = "discord"
 	ChannelSlack    NotificationChannel = "slack"
+	ChannelMatrix   NotificationChannel = "matrix"
 )
 
 // TelegramConfig Telegram通知配置
@@ -23,6 +24,8 @@ type TelegramConfig struct {
 	Name        string    `json:"name" gorm:"size:100"`                       // 名称
 	BotToken    string    `json:"bot_token" gorm:"not null;size:500"`         // 机器人token
 	ChatID      string    `json:"chat_id" gorm:"not null;size:100"`           // 聊天ID
+	Prefix      string    `json:"prefix" gorm:"not null;default:'';size:100"` // 消息前缀（如 [PROD]），为空时不添加
+	Suffix      string    `json:"suffix" gorm:"not null;default:'';size:100"` // 消息后缀，为空时不添加
 	IsActive    bool      `json:"is_active" gorm:"default:true"`              // 是否激活
 	CreatedAt   time.Time `json:"created_at"`                                 // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`                                 // 更新时间
@@ -39,6 +42,8 @@ type LarkConfig struct {
 	Name        string    `json:"name" gorm:"size:100"`                       // 名称
 	WebhookURL  string    `json:"webhook_url" gorm:"not null;size:1000"`      // 网络钩子URL
 	Secret      string    `json:"secret" gorm:"size:500"`                     // 签名验证时的密钥
+	Prefix      string    `json:"prefix" gorm:"not null;default:'';size:100"` // 消息前缀（如 [PROD]），为空时不添加
+	Suffix      string    `json:"suffix" gorm:"not null;default:'';size:100"` // 消息后缀，为空时不添加
 	IsActive    bool      `json:"is_active" gorm:"default:true"`              // 是否激活
 	CreatedAt   time.Time `json:"created_at"`                                 // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`                                 // 更新时间
@@ -55,6 +60,8 @@ type FeishuConfig struct {
 	Name        string    `json:"name" gorm:"size:100"`                       // 名称
 	WebhookURL  string    `json:"webhook_url" gorm:"not null;size:1000"`      // 网络钩子URL
 	Secret      string    `json:"secret" gorm:"size:500"`                     // 签名验证时的密钥
+	Prefix      string    `json:"prefix" gorm:"not null;default:'';size:100"` // 消息前缀（如 [PROD]），为空时不添加
+	Suffix      string    `json:"suffix" gorm:"not null;default:'';size:100"` // 消息后缀，为空时不添加
 	IsActive    bool      `json:"is_active" gorm:"default:true"`              // 是否激活
 	CreatedAt   time.Time `json:"created_at"`                                 // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`                                 // 更新时间
@@ -70,6 +77,8 @@ type DiscordConfig struct {
 	UserAddress string    `json:"user_address" gorm:"not null;index;size:42"` // 用户地址
 	Name        string    `json:"name" gorm:"size:100"`                       // 名称
 	WebhookURL  string    `json:"webhook_url" gorm:"not null;size:1000"`      // 网络钩子URL
+	Prefix      string    `json:"prefix" gorm:"not null;default:'';size:100"` // 消息前缀（如 [PROD]），为空时不添加
+	Suffix      string    `json:"suffix" gorm:"not null;default:'';size:100"` // 消息后缀，为空时不添加
 	IsActive    bool      `json:"is_active" gorm:"default:true"`              // 是否激活
 	CreatedAt   time.Time `json:"created_at"`                                 // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`                                 // 更新时间
@@ -85,6 +94,8 @@ type SlackConfig struct {
 	UserAddress string    `json:"user_address" gorm:"not null;index;size:42"` // 用户地址
 	Name        string    `json:"name" gorm:"size:100"`                       // 名称
 	WebhookURL  string    `json:"webhook_url" gorm:"not null;size:1000"`      // 网络钩子URL
+	Prefix      string    `json:"prefix" gorm:"not null;default:'';size:100"` // 消息前缀（如 [PROD]），为空时不添加
+	Suffix      string    `json:"suffix" gorm:"not null;default:'';size:100"` // 消息后缀，为空时不添加
 	IsActive    bool      `json:"is_active" gorm:"default:true"`              // 是否激活
 	CreatedAt   time.Time `json:"created_at"`                                 // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`                                 // 更新时间
@@ -94,6 +105,39 @@ func (SlackConfig) TableName() string {
 	return "slack_configs"
 }
 
+// MatrixConfig Matrix通知配置
+type MatrixConfig struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`                       // ID
+	UserAddress   string    `json:"user_address" gorm:"not null;index;size:42"` // 用户地址
+	Name          string    `json:"name" gorm:"size:100"`                       // 名称
+	HomeserverURL string    `json:"homeserver_url" gorm:"not null;size:1000"`   // Homeserver 地址，如 https://matrix.org
+	AccessToken   string    `json:"access_token" gorm:"not null;size:500"`      // 发送账号的 access token
+	RoomID        string    `json:"room_id" gorm:"not null;size:255"`           // 房间ID，如 !abc:matrix.org
+	Prefix        string    `json:"prefix" gorm:"not null;default:'';size:100"` // 消息前缀（如 [PROD]），为空时不添加
+	Suffix        string    `json:"suffix" gorm:"not null;default:'';size:100"` // 消息后缀，为空时不添加
+	IsActive      bool      `json:"is_active" gorm:"default:true"`              // 是否激活
+	CreatedAt     time.Time `json:"created_at"`                                 // 创建时间
+	UpdatedAt     time.Time `json:"updated_at"`                                 // 更新时间
+}
+
+func (MatrixConfig) TableName() string {
+	return "matrix_configs"
+}
+
+// NotificationChannelSetting 用户按渠道类型的总开关，关闭后该渠道下所有配置都不再发送；没有记录时视为开启
+type NotificationChannelSetting struct {
+	ID          int64               `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserAddress string              `json:"user_address" gorm:"not null;size:42;uniqueIndex:idx_notification_channel_settings_user_channel"` // 用户地址（小写）
+	Channel     NotificationChannel `json:"channel" gorm:"not null;size:20;uniqueIndex:idx_notification_channel_settings_user_channel"`      // 渠道
+	Enabled     bool                `json:"enabled" gorm:"not null"`                                                                         // 是否开启（不带 gorm default，保证 false 能写入）
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func (NotificationChannelSetting) TableName() string {
+	return "notification_channel_settings"
+}
+
 // NotificationLog 通知发送日志
 type NotificationLog struct {
 	ID               uint                `json:"id" gorm:"primaryKey"`
@@ -109,7 +153,9 @@ type NotificationLog struct {
 	TxHash           string              `json:"tx_hash" gorm:"size:66"`                     // 交易哈希
 	SendStatus       string              `json:"send_status" gorm:"not null;size:20"`        // 发送状态
 	ErrorMessage     string              `json:"error_message" gorm:"type:text"`             // 错误消息
-	SentAt           time.Time           `json:"sent_at"`                                    // 发送时间
+	// 服务商返回的消息ID（Telegram message_id、Discord 消息ID、Matrix event_id），webhook 不返回ID的渠道为空
+	ProviderMessageID string    `json:"provider_message_id" gorm:"size:128"`
+	SentAt            time.Time `json:"sent_at"` // 发送时间
 }
 
 func (NotificationLog) TableName() string {
@@ -122,7 +168,7 @@ type NotificationConfig struct {
 	ID          uint      `json:"id"`
 	UserAddress string    `json:"user_address"`
 	Name        string    `json:"name"`
-	Channel     string    `json:"channel"` // telegram / lark / feishu / discord / slack
+	Channel     string    `json:"channel"` // telegram / lark / feishu / discord / slack / matrix
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -131,26 +177,42 @@ type NotificationConfig struct {
 	ChatID     *string `json:"chat_id,omitempty"`
 	WebhookURL *string `json:"webhook_url,omitempty"`
 	Secret     *string `json:"secret,omitempty"`
+	// matrix
+	HomeserverURL *string `json:"homeserver_url,omitempty"`
+	AccessToken   *string `json:"access_token,omitempty"`
+	RoomID        *string `json:"room_id,omitempty"`
+	// 消息前后缀
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
 }
 
 // CreateNotificationRequest 创建通知通用请求
 type CreateNotificationRequest struct {
 	// 通用
 	Name    string `json:"name" binding:"required"`    // 名称
-	Channel string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack
+	Channel string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack,matrix
 	// telegram
 	BotToken string `json:"bot_token"` // 机器人token
 	ChatID   string `json:"chat_id"`   // 聊天ID
 	// lark feishu discord slack
 	WebhookURL string `json:"webhook_url"` // 网络钩子URL
 	Secret     string `json:"secret"`      // 签名验证时的密钥
+	// matrix
+	HomeserverURL string `json:"homeserver_url"` // Homeserver 地址
+	AccessToken   string `json:"access_token"`   // access token
+	RoomID        string `json:"room_id"`        // 房间ID
+	// 消息前后缀（可选，如 [PROD]），最长 32 个字符且不能换行
+	Prefix string `json:"prefix"`
+	Suffix string `json:"suffix"`
+	// 允许与已有激活配置的目标（bot_token+chat_id 或 webhook_url）重复
+	AllowDuplicate bool `json:"allow_duplicate"`
 }
 
 // UpdateNotificationRequest 更新通知通用请求
 type UpdateNotificationRequest struct {
 	// 通用
 	Name     *string `json:"name" binding:"required"`    // 名称
-	Channel  *string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack
+	Channel  *string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack,matrix
 	IsActive *bool   `json:"is_active"`                  // 是否激活
 	// telegram
 	BotToken *string `json:"bot_token"` // 机器人token
@@ -158,13 +220,76 @@ type UpdateNotificationRequest struct {
 	// lark feishu discord slack
 	WebhookURL *string `json:"webhook_url"` // 网络钩子URL
 	Secret     *string `json:"secret"`      // 签名验证时的密钥
+	// matrix
+	HomeserverURL *string `json:"homeserver_url"` // Homeserver 地址
+	AccessToken   *string `json:"access_token"`   // access token
+	RoomID        *string `json:"room_id"`        // 房间ID
+	// 消息前后缀，传空字符串清除
+	Prefix *string `json:"prefix"`
+	Suffix *string `json:"suffix"`
 }
 
 // DeleteNotificationRequest 删除通知通用请求
 type DeleteNotificationRequest struct {
 	// 通用
 	Name    string `json:"name" binding:"required"`    // 名称
-	Channel string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack
+	Channel string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack,matrix
+}
+
+// GetNotificationConfigRequest 获取单个通知配置请求
+type GetNotificationConfigRequest struct {
+	Channel string `form:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack,matrix
+	Name    string `form:"name" binding:"required"`    // 名称
+	Reveal  bool   `form:"reveal"`                     // 返回敏感字段明文，需最近重新签名登录
+}
+
+// GetAllNotificationConfigsRequest 获取所有通知配置请求（query 参数）
+type GetAllNotificationConfigsRequest struct {
+	Reveal bool `form:"reveal"` // 返回敏感字段明文，需最近重新签名登录
+}
+
+// SetNotificationChannelEnabledRequest 开关整个通知渠道请求
+type SetNotificationChannelEnabledRequest struct {
+	Channel string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack,matrix
+	Enabled *bool  `json:"enabled" binding:"required"` // 是否开启
+}
+
+// NotificationChannelStatus 单个渠道的开关状态
+type NotificationChannelStatus struct {
+	Channel NotificationChannel `json:"channel"`
+	Enabled bool                `json:"enabled"`
+}
+
+// NotificationChannelSettingsResponse 用户全部渠道的开关状态
+type NotificationChannelSettingsResponse struct {
+	Channels []NotificationChannelStatus `json:"channels"`
+}
+
+// 通知配置字段类型
+const (
+	NotificationFieldTypeString = "string"
+	NotificationFieldTypeURL    = "url"
+)
+
+// NotificationChannelField 渠道配置表单的单个字段
+type NotificationChannelField struct {
+	Name        string `json:"name"`                 // 请求中的字段名，与 CreateNotificationRequest 的 json 名一致
+	Type        string `json:"type"`                 // string / url（url 需为 http(s) 且不能指向内网地址）
+	Required    bool   `json:"required"`             // 创建时是否必填
+	Sensitive   bool   `json:"sensitive"`            // 敏感字段，查询时脱敏返回
+	MaxLength   int    `json:"max_length,omitempty"` // 最大字符数，0 表示不限制
+	Description string `json:"description"`
+}
+
+// NotificationChannelSchema 单个渠道的配置字段
+type NotificationChannelSchema struct {
+	Channel NotificationChannel        `json:"channel"`
+	Fields  []NotificationChannelField `json:"fields"`
+}
+
+// NotificationChannelSchemaResponse 全部支持渠道的配置字段
+type NotificationChannelSchemaResponse struct {
+	Channels []NotificationChannelSchema `json:"channels"`
 }
 
 // UserNotificationConfigs 用户通知配置集合
@@ -174,6 +299,7 @@ type UserNotificationConfigs struct {
 	FeishuConfigs   []*FeishuConfig   `json:"feishu_configs"`
 	DiscordConfigs  []*DiscordConfig  `json:"discord_configs"`
 	SlackConfigs    []*SlackConfig    `json:"slack_configs"`
+	MatrixConfigs   []*MatrixConfig   `json:"matrix_configs"`
 }
 
 // NotificationConfigListResponse 通知配置列表响应
@@ -183,6 +309,7 @@ type NotificationConfigListResponse struct {
 	FeishuConfigs   []*FeishuConfig   `json:"feishu_configs"`
 	DiscordConfigs  []*DiscordConfig  `json:"discord_configs"`
 	SlackConfigs    []*SlackConfig    `json:"slack_configs"`
+	MatrixConfigs   []*MatrixConfig   `json:"matrix_configs"`
 }
 
 type CalldataParam struct {
@@ -191,23 +318,272 @@ type CalldataParam struct {
 	Value string `json:"value"` // 值
 }
 
-type NotificationData struct {
-	BgColorFrom    template.CSS    `json:"bg_color_from"`
-	TextColorFrom  template.CSS    `json:"text_color_from"`
-	StatusFrom     string          `json:"status_from"`
-	BgColorTo      template.CSS    `json:"bg_color_to"`
-	TextColorTo    template.CSS    `json:"text_color_to"`
-	StatusTo       string          `json:"status_to"`
-	Standard       string          `json:"standard"`
-	Network        string          `json:"network"`
-	Contract       string          `json:"contract"`
-	Remark         string          `json:"remark"`
-	Caller         string          `json:"caller"`
+// NotificationCall 批量操作中的单个子调用
+type NotificationCall struct {
+	Index          int             `json:"index"`
 	Target         string          `json:"target"`
+	TargetUrl      string          `json:"target_url"` // 目标地址的区块浏览器链接，未配置浏览器时为空
 	Value          string          `json:"value"`
 	Function       string          `json:"function"`
 	CalldataParams []CalldataParam `json:"calldata_params"`
-	TxUrl          string          `json:"tx_url"`
-	TxHash         string          `json:"tx_hash"`
-	DashboardUrl   string          `json:"dashboard_url"`
+}
+
+type NotificationData struct {
+	BgColorFrom    template.CSS       `json:"bg_color_from"`
+	TextColorFrom  template.CSS       `json:"text_color_from"`
+	StatusFrom     string             `json:"status_from"`
+	BgColorTo      template.CSS       `json:"bg_color_to"`
+	TextColorTo    template.CSS       `json:"text_color_to"`
+	StatusTo       string             `json:"status_to"`
+	Standard       string             `json:"standard"`
+	Network        string             `json:"network"`
+	Contract       string             `json:"contract"`
+	ContractUrl    string             `json:"contract_url"` // 合约的区块浏览器链接，未配置浏览器时为空
+	Remark         string             `json:"remark"`
+	Nickname       string             `json:"nickname,omitempty"` // 合约昵称，设置时通知中代替备注展示
+	Caller         string             `json:"caller"`
+	Canceller      string             `json:"canceller,omitempty"` // 取消交易的发起地址，仅流程被取消时填充
+	Target         string             `json:"target"`
+	TargetUrl      string             `json:"target_url"` // 目标地址的区块浏览器链接，未配置浏览器或批量操作时为空
+	Value          string             `json:"value"`
+	Function       string             `json:"function"`
+	CalldataParams []CalldataParam    `json:"calldata_params"`
+	Calls          []NotificationCall `json:"calls,omitempty"` // 批量操作的全部子调用（仅多于一个时填充）
+	TxUrl          string             `json:"tx_url"`
+	TxHash         string             `json:"tx_hash"`
+	DashboardUrl   string             `json:"dashboard_url"`
+	Severity       string             `json:"severity"`             // 通知级别，normal / critical
+	SelfTargeting  bool               `json:"self_targeting"`       // 是否为以 timelock 自身为目标的治理变更（如 setDelay、setPendingAdmin）
+	BlockedBy      string             `json:"blocked_by,omitempty"` // 尚未执行的前置操作 id（仅 OpenZeppelin），该操作在其执行前无法执行
+}
+
+// 通知级别
+const (
+	NotificationSeverityNormal   = "normal"
+	NotificationSeverityCritical = "critical"
+)
+
+// NotificationConfigRedacted 早期导出文件中被隐藏的敏感字段占位值，导入时仍需识别
+const NotificationConfigRedacted = "[REDACTED]"
+
+// NotificationSecretMask 敏感字段脱敏前缀，脱敏后形如 ****abcd
+const NotificationSecretMask = "****"
+
+// ExportNotificationConfigsRequest 导出通知配置请求
+type ExportNotificationConfigsRequest struct {
+	IncludeSecrets bool `json:"include_secrets"` // 是否包含 bot_token/webhook_url/secret/access_token 明文，默认脱敏；为 true 时需最近重新签名登录
+}
+
+// NotificationConfigItem 导出/导入的单条通知配置
+type NotificationConfigItem struct {
+	Name     string `json:"name" binding:"required"`    // 名称
+	Channel  string `json:"channel" binding:"required"` // 渠道,telegram,lark,feishu,discord,slack,matrix
+	IsActive *bool  `json:"is_active"`                  // 是否激活，为空时默认激活
+	// telegram
+	BotToken string `json:"bot_token,omitempty"` // 机器人token
+	ChatID   string `json:"chat_id,omitempty"`   // 聊天ID
+	// lark feishu discord slack
+	WebhookURL string `json:"webhook_url,omitempty"` // 网络钩子URL
+	Secret     string `json:"secret,omitempty"`      // 签名验证时的密钥
+	// matrix
+	HomeserverURL string `json:"homeserver_url,omitempty"` // Homeserver 地址
+	AccessToken   string `json:"access_token,omitempty"`   // access token
+	RoomID        string `json:"room_id,omitempty"`        // 房间ID
+	// 消息前后缀
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// ExportNotificationConfigsResponse 导出通知配置响应
+type ExportNotificationConfigsResponse struct {
+	ExportedAt      time.Time                `json:"exported_at"`
+	SecretsIncluded bool                     `json:"secrets_included"`
+	Configs         []NotificationConfigItem `json:"configs"`
+}
+
+// ImportNotificationConfigsRequest 导入通知配置请求
+type ImportNotificationConfigsRequest struct {
+	Configs []NotificationConfigItem `json:"configs" binding:"required,min=1,max=100,dive"`
+}
+
+// ImportedNotificationConfig 导入结果中的单条配置
+type ImportedNotificationConfig struct {
+	Name    string `json:"name"`
+	Channel string `json:"channel"`
+	Reason  string `json:"reason,omitempty"` // 跳过原因
+}
+
+// ImportNotificationConfigsResponse 导入通知配置响应
+type ImportNotificationConfigsResponse struct {
+	Created []ImportedNotificationConfig `json:"created"`
+	Skipped []ImportedNotificationConfig `json:"skipped"`
+}
+
+// PreviewFlowNotificationRequest 预览流程通知请求
+type PreviewFlowNotificationRequest struct {
+	FlowIdentifier
+	StatusFrom string `json:"status_from" form:"status_from" binding:"omitempty,oneof=waiting ready executed cancelled expired"` // 变更前状态，新建流程时为空
+	StatusTo   string `json:"status_to" form:"status_to" binding:"required,oneof=waiting ready executed cancelled expired"`      // 变更后状态
+}
+
+// PreviewFlowNotificationResponse 预览流程通知响应
+type PreviewFlowNotificationResponse struct {
+	Message         string                           `json:"message"`          // 完整消息（不截断）
+	ChannelMessages map[NotificationChannel]string   `json:"channel_messages"` // 各渠道的消息（按长度上限截断，未加配置前后缀）
+	Configs         []PreviewNotificationConfigEntry `json:"configs"`          // 当前用户各激活配置实际会收到的消息（含前后缀，已排除关闭的渠道类型）
+}
+
+// PreviewNotificationConfigEntry 单条渠道配置会收到的通知消息
+type PreviewNotificationConfigEntry struct {
+	Channel NotificationChannel `json:"channel"`
+	Name    string              `json:"name"`
+	Message string              `json:"message"`
+}
+
+// PreviewNotificationTemplateRequest 试渲染通知消息模板请求
+// 渲染数据优先级：flow（真实流程）> sample（示例数据）> 内置示例数据
+type PreviewNotificationTemplateRequest struct {
+	Template   string            `json:"template" binding:"required,max=8000"`                                           // Go text/template 语法，模板数据为 NotificationData
+	Sample     *NotificationData `json:"sample"`                                                                         // 示例数据（可选）
+	Flow       *FlowIdentifier   `json:"flow"`                                                                           // 使用真实流程数据渲染（可选，需与该流程相关）
+	StatusFrom string            `json:"status_from" binding:"omitempty,oneof=waiting ready executed cancelled expired"` // 使用真实流程时的变更前状态
+	StatusTo   string            `json:"status_to" binding:"omitempty,oneof=waiting ready executed cancelled expired"`   // 使用真实流程时的变更后状态，默认为流程当前状态
+}
+
+// NotificationTemplateError 通知模板解析/渲染错误
+type NotificationTemplateError struct {
+	Stage   string `json:"stage"`            // parse / forbidden / render
+	Line    int    `json:"line,omitempty"`   // 出错行号（从 1 开始），无法定位时为 0
+	Column  int    `json:"column,omitempty"` // 出错列号（从 1 开始），无法定位时为 0
+	Message string `json:"message"`          // 错误信息
+}
+
+// PreviewNotificationTemplateResponse 试渲染通知消息模板响应
+type PreviewNotificationTemplateResponse struct {
+	Valid  bool                       `json:"valid"`            // 模板是否可正常解析与渲染
+	Output string                     `json:"output,omitempty"` // 渲染结果
+	Error  *NotificationTemplateError `json:"error,omitempty"`  // 解析或渲染错误
+}
+
+// GetNotificationLogsRequest 获取通知发送日志请求
+type GetNotificationLogsRequest struct {
+	Channel    string `json:"channel" binding:"omitempty,oneof=telegram lark feishu discord slack matrix"` // 渠道，为空时查询全部渠道
+	FlowID     string `json:"flow_id"`                                                                     // 流程ID，为空时不过滤
+	SendStatus string `json:"send_status" binding:"omitempty,oneof=success failed"`                        // 发送状态，为空时不过滤
+	Page       int    `json:"page"`                                                                        // 页码，默认为1
+	PageSize   int    `json:"page_size"`                                                                   // 每页大小，默认为20，最大100
+}
+
+// GetNotificationLogsResponse 获取通知发送日志响应
+type GetNotificationLogsResponse struct {
+	Logs  []NotificationLog `json:"logs"`  // 日志列表（按发送时间倒序）
+	Total int64             `json:"total"` // 总数
+	PaginationMeta
+}
+
+// GetNotificationStatsRequest 获取通知发送统计请求
+type GetNotificationStatsRequest struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=90"` // 统计最近多少天，默认7，最大90
+}
+
+// NotificationSendStatusCount 按渠道与发送状态分组的计数（仓库层查询结果）
+type NotificationSendStatusCount struct {
+	Channel    NotificationChannel `json:"channel"`
+	SendStatus string              `json:"send_status"`
+	Count      int64               `json:"count"`
+}
+
+// NotificationChannelStats 单个渠道的发送统计
+type NotificationChannelStats struct {
+	Channel NotificationChannel `json:"channel"`
+	Sent    int64               `json:"sent"`   // 发送成功数
+	Failed  int64               `json:"failed"` // 发送失败数
+	Total   int64               `json:"total"`
+}
+
+// GetNotificationStatsResponse 获取通知发送统计响应
+type GetNotificationStatsResponse struct {
+	Days     int                        `json:"days"`
+	Since    time.Time                  `json:"since"` // 统计起点（含）
+	Sent     int64                      `json:"sent"`
+	Failed   int64                      `json:"failed"`
+	Total    int64                      `json:"total"`
+	Channels []NotificationChannelStats `json:"channels"` // 只包含窗口内有发送记录的渠道
+}
+
+// ValidateWebhookURLRequest 校验 webhook URL 可达性请求
+type ValidateWebhookURLRequest struct {
+	URL string `json:"url" binding:"required"` // 待校验的 webhook URL（仅支持 http/https，禁止内网/本机地址）
+}
+
+// ValidateWebhookURLResponse 校验 webhook URL 可达性响应
+type ValidateWebhookURLResponse struct {
+	Reachable  bool   `json:"reachable"`             // 是否收到 HTTP 响应
+	Success    bool   `json:"success"`               // 响应状态码是否为 2xx
+	StatusCode int    `json:"status_code,omitempty"` // HTTP 状态码
+	Method     string `json:"method"`                // 最终使用的探测方法（HEAD 或 POST）
+	LatencyMs  int64  `json:"latency_ms"`            // 探测耗时（毫秒）
+	Error      string `json:"error,omitempty"`       // 不可达时的错误信息
+}
+
+// NotificationDrainSummary 关闭时排空通知队列的结果
+type NotificationDrainSummary struct {
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Pending    int       `json:"pending"`   // 开始排空时队列中的任务数
+	Sent       int64     `json:"sent"`      // 排空窗口内处理完成的任务数
+	Dropped    int64     `json:"dropped"`   // 排空窗口内丢弃的任务数（超时未处理、处理中被取消、关闭时被拒绝投递）
+	TimedOut   bool      `json:"timed_out"` // 是否超过 drain_timeout
+}
+
+// NotificationQueueStats 通知分发队列统计（进程启动以来累计）
+type NotificationQueueStats struct {
+	Workers   int                       `json:"workers"`
+	Capacity  int                       `json:"capacity"` // 全部 worker 队列总容量
+	Pending   int                       `json:"pending"`  // 当前排队中的任务数
+	Enqueued  int64                     `json:"enqueued"`
+	Sent      int64                     `json:"sent"`
+	Dropped   int64                     `json:"dropped"`
+	LastDrain *NotificationDrainSummary `json:"last_drain,omitempty"` // 最近一次关闭排空结果，未关闭时为空
+}
+
+// 通知不会发送的原因（would-notify 排查接口）
+const (
+	WouldNotifyReasonNotRelatedUser   = "not_related_user"   // 用户不是合约的 admin/pending_admin（Compound）或 proposer/executor（OpenZeppelin）
+	WouldNotifyReasonConfigInactive   = "config_inactive"    // 通知配置已停用
+	WouldNotifyReasonChannelDisabled  = "channel_disabled"   // 渠道总开关已关闭
+	WouldNotifyReasonEmailNotVerified = "email_not_verified" // 邮箱未验证
+	WouldNotifyReasonStatusFiltered   = "status_filtered"    // 邮箱的 notify_statuses 不包含该状态
+	WouldNotifyReasonAlreadySent      = "already_sent"       // 该状态的通知已发送过（去重）
+)
+
+// GetFlowWouldNotifyRequest 查询流程状态变化会触发哪些通知的请求
+type GetFlowWouldNotifyRequest struct {
+	FlowIdentifier
+	StatusTo string `json:"status_to" form:"status_to" binding:"required,oneof=waiting ready executed cancelled expired"` // 目标状态
+}
+
+// WouldNotifyConfig 单个渠道通知配置的判定结果
+type WouldNotifyConfig struct {
+	Channel   NotificationChannel `json:"channel"`
+	ConfigID  uint                `json:"config_id"`
+	Name      string              `json:"name"`
+	WouldFire bool                `json:"would_fire"`
+	Reason    string              `json:"reason,omitempty"` // 不会发送的原因，见 WouldNotifyReason*
+}
+
+// WouldNotifyEmail 单个邮箱的判定结果
+type WouldNotifyEmail struct {
+	UserEmailID int64  `json:"user_email_id"`
+	Email       string `json:"email"`
+	WouldFire   bool   `json:"would_fire"`
+	Reason      string `json:"reason,omitempty"` // 不会发送的原因，见 WouldNotifyReason*
+}
+
+// GetFlowWouldNotifyResponse 查询流程状态变化会触发哪些通知的响应（只包含当前用户自己的配置与邮箱）
+type GetFlowWouldNotifyResponse struct {
+	StatusTo    string              `json:"status_to"`
+	RelatedUser bool                `json:"related_user"` // 用户是否在合约的通知对象中
+	Configs     []WouldNotifyConfig `json:"configs"`
+	Emails      []WouldNotifyEmail  `json:"emails"`
 }
@@ -0,0 +1,46 @@
+package types
+
+// MaxPageSize 列表接口单页最大条数，超出时服务端截断为该值而非报错
+const MaxPageSize = 100
+
+// ClampPagination 规范化分页参数：page 最小为 1，page_size 未传时取 defaultPageSize，超过 MaxPageSize 时截断
+// 返回实际生效的值，响应中的 PaginationMeta 应使用该值
+func ClampPagination(page, pageSize, defaultPageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
+
+// PaginationMeta 分页导航信息（由 total/page/page_size 推导，列表响应统一内嵌）
+type PaginationMeta struct {
+	Page       int  `json:"page"`        // 当前页码
+	PageSize   int  `json:"page_size"`   // 实际生效的每页大小（超过 MaxPageSize 时已截断）
+	TotalPages int  `json:"total_pages"` // 总页数
+	HasNext    bool `json:"has_next"`    // 是否有下一页
+	HasPrev    bool `json:"has_prev"`    // 是否有上一页
+}
+
+// NewPaginationMeta 根据总数、页码和每页大小计算分页信息
+func NewPaginationMeta(total int64, page, pageSize int) PaginationMeta {
+	if page <= 0 {
+		page = 1
+	}
+	totalPages := 0
+	if pageSize > 0 && total > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	return PaginationMeta{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
@@ -0,0 +1,55 @@
+package types
+
+import "testing"
+
+func TestClampPagination(t *testing.T) {
+	tests := []struct {
+		name                    string
+		page, pageSize, defSize int
+		wantPage, wantPageSize  int
+	}{
+		{"values kept", 3, 25, 20, 3, 25},
+		{"zero page becomes first page", 0, 25, 20, 1, 25},
+		{"negative page becomes first page", -2, 25, 20, 1, 25},
+		{"missing page size uses default", 1, 0, 20, 1, 20},
+		{"negative page size uses default", 1, -5, 10, 1, 10},
+		{"page size at max kept", 1, MaxPageSize, 20, 1, MaxPageSize},
+		{"page size above max clamped", 2, MaxPageSize + 1, 20, 2, MaxPageSize},
+		{"default above max clamped", 1, 0, MaxPageSize * 2, 1, MaxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, pageSize := ClampPagination(tt.page, tt.pageSize, tt.defSize)
+			if page != tt.wantPage || pageSize != tt.wantPageSize {
+				t.Fatalf("ClampPagination(%d, %d, %d) = (%d, %d), want (%d, %d)",
+					tt.page, tt.pageSize, tt.defSize, page, pageSize, tt.wantPage, tt.wantPageSize)
+			}
+		})
+	}
+}
+
+func TestNewPaginationMeta(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int64
+		page     int
+		pageSize int
+		want     PaginationMeta
+	}{
+		{"empty result", 0, 1, 20, PaginationMeta{Page: 1, PageSize: 20}},
+		{"single partial page", 5, 1, 20, PaginationMeta{Page: 1, PageSize: 20, TotalPages: 1}},
+		{"exact multiple", 40, 1, 20, PaginationMeta{Page: 1, PageSize: 20, TotalPages: 2, HasNext: true}},
+		{"middle page", 45, 2, 20, PaginationMeta{Page: 2, PageSize: 20, TotalPages: 3, HasNext: true, HasPrev: true}},
+		{"last page", 45, 3, 20, PaginationMeta{Page: 3, PageSize: 20, TotalPages: 3, HasPrev: true}},
+		{"page past the end", 45, 5, 20, PaginationMeta{Page: 5, PageSize: 20, TotalPages: 3, HasPrev: true}},
+		{"zero page treated as first", 45, 0, 20, PaginationMeta{Page: 1, PageSize: 20, TotalPages: 3, HasNext: true}},
+		{"zero page size", 45, 1, 0, PaginationMeta{Page: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPaginationMeta(tt.total, tt.page, tt.pageSize); got != tt.want {
+				t.Fatalf("NewPaginationMeta(%d, %d, %d) = %+v, want %+v", tt.total, tt.page, tt.pageSize, got, tt.want)
+			}
+		})
+	}
+}
@@ -27,3 +27,31 @@ type ChainStatistics struct {
 func (ChainStatistics) TableName() string {
 	return "chain_statistics"
 }
+
+// GlobalStatsSnapshot 全局统计快照（由定时任务周期写入，用于统计趋势图）
+type GlobalStatsSnapshot struct {
+	ID               int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	ChainCount       int64     `json:"chain_count" gorm:"not null;default:0"`       // 支持的链数量
+	ContractCount    int64     `json:"contract_count" gorm:"not null;default:0"`    // timelock合约数量
+	TransactionCount int64     `json:"transaction_count" gorm:"not null;default:0"` // 交易数量
+	SnapshotAt       time.Time `json:"snapshot_at" gorm:"type:timestamptz;not null"`
+}
+
+// TableName 设置表名
+func (GlobalStatsSnapshot) TableName() string {
+	return "global_stats_history"
+}
+
+// GetStatsHistoryRequest 获取统计历史请求
+type GetStatsHistoryRequest struct {
+	From     *time.Time `json:"from" form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // 时间下限（含），RFC3339，为空时不限
+	To       *time.Time `json:"to" form:"to" time_format:"2006-01-02T15:04:05Z07:00"`     // 时间上限（不含），RFC3339，为空时不限
+	Page     int        `json:"page" form:"page"`                                         // 页码，默认为1
+	PageSize int        `json:"page_size" form:"page_size"`                               // 每页大小，默认为100，最大100
+}
+
+// GetStatsHistoryResponse 获取统计历史响应
+type GetStatsHistoryResponse struct {
+	Points []GlobalStatsSnapshot `json:"points"` // 按快照时间升序
+	PaginationMeta
+}
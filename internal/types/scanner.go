@@ -297,3 +297,20 @@ type HealthCheckResult struct {
 	ErrorMessage string        `json:"error_message,omitempty"`
 	Timestamp    time.Time     `json:"timestamp"`
 }
+
+// RPCLogsCapability RPC eth_getLogs 能力探测结果
+type RPCLogsCapability struct {
+	ChainID       int       `json:"chain_id"`
+	MaxBlockRange uint64    `json:"max_block_range"` // 探测到的单次 eth_getLogs 最大安全区块跨度
+	Probed        bool      `json:"probed"`          // false 表示从未探测成功，MaxBlockRange 为保守默认值；重探失败时保留上次结果并记录 LastError
+	ProbedAt      time.Time `json:"probed_at"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+// RPCBatchCapability RPC JSON-RPC batch 请求支持情况
+type RPCBatchCapability struct {
+	ChainID   int       `json:"chain_id"`
+	Supported bool      `json:"supported"`
+	ProbedAt  time.Time `json:"probed_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
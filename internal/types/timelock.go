@@ -18,10 +18,17 @@ type CompoundTimeLock struct {
 	MinimumDelay    int64     `json:"minimum_delay" gorm:"not null"`                                                                            // 最小延迟时间（秒），从链上读取
 	MaximumDelay    int64     `json:"maximum_delay" gorm:"not null"`                                                                            // 最大延迟时间（秒），从链上读取
 	Remark          string    `json:"remark" gorm:"size:500"`                                                                                   // 备注
+	Nickname        string    `json:"nickname" gorm:"size:100"`                                                                                 // 合约昵称，通知中优先于备注展示
 	Status          string    `json:"status" gorm:"size:20;not null;default:'active';index"`                                                    // 状态（active, inactive, deleted）
 	IsImported      bool      `json:"is_imported" gorm:"not null;default:false"`                                                                // 是否导入的合约
+	CreationBlock   *int64    `json:"creation_block"`                                                                                           // 部署区块号（通过部署交易导入时填充）
+	CreationTx      *string   `json:"creation_tx" gorm:"size:66"`                                                                               // 部署交易哈希（通过部署交易导入时填充）
+	StatusReason    *string   `json:"status_reason,omitempty" gorm:"size:500"`                                                                  // 状态原因（刷新时复核失败被标记为 inactive 的原因）
+	DelayWarning    *string   `json:"delay_warning,omitempty" gorm:"size:200"`                                                                  // delay 低于安全阈值时的警告，刷新时随链上 delay 更新
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	// 读取 GRACE_PERIOD 失败，grace_period 为配置的兜底值，后续刷新读到链上值后修正
+	GracePeriodEstimated bool `json:"grace_period_estimated" gorm:"not null;default:false"`
 }
 
 // TableName 设置表名
@@ -41,8 +48,13 @@ type OpenzeppelinTimeLock struct {
 	Proposers       string    `json:"proposers" gorm:"type:text;not null"`                                                                // 提议者地址列表（JSON），从链上读取
 	Executors       string    `json:"executors" gorm:"type:text;not null"`                                                                // 执行者地址列表（JSON），从链上读取
 	Remark          string    `json:"remark" gorm:"size:500"`                                                                             // 备注
+	Nickname        string    `json:"nickname" gorm:"size:100"`                                                                           // 合约昵称，通知中优先于备注展示
 	Status          string    `json:"status" gorm:"size:20;not null;default:'active';index"`                                              // 状态（active, inactive, deleted）
 	IsImported      bool      `json:"is_imported" gorm:"not null;default:false"`                                                          // 是否导入的合约
+	CreationBlock   *int64    `json:"creation_block"`                                                                                     // 部署区块号（通过部署交易导入时填充）
+	CreationTx      *string   `json:"creation_tx" gorm:"size:66"`                                                                         // 部署交易哈希（通过部署交易导入时填充）
+	StatusReason    *string   `json:"status_reason,omitempty" gorm:"size:500"`                                                            // 状态原因（刷新时复核失败被标记为 inactive 的原因）
+	DelayWarning    *string   `json:"delay_warning,omitempty" gorm:"size:200"`                                                            // delay 低于安全阈值时的警告，刷新时随链上 delay 更新
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -55,18 +67,50 @@ func (OpenzeppelinTimeLock) TableName() string {
 // CreateOrImportTimelockContractRequest 创建或导入合约请求
 type CreateOrImportTimelockContractRequest struct {
 	Standard        string `json:"standard" binding:"required,oneof=compound openzeppelin"`
-	ContractAddress string `json:"contract_address" binding:"required"`
+	ContractAddress string `json:"contract_address" binding:"required_without=CreationTxHash"`
+	CreationTxHash  string `json:"creation_tx_hash"` // 部署交易哈希，提供时从交易回执自动解析合约地址
 	ChainID         int    `json:"chain_id" binding:"required"`
 	IsImported      bool   `json:"is_imported"`
 	Remark          string `json:"remark" binding:"max=500"`
+	Nickname        string `json:"nickname" binding:"max=100"` // 合约昵称，通知中优先于备注展示
 }
 
 // UpdateTimeLockRequest 更新timelock合约请求
 type UpdateTimeLockRequest struct {
-	Standard        string `json:"standard" binding:"required,oneof=compound openzeppelin"`
-	ChainID         int    `json:"chain_id" binding:"required"`
-	ContractAddress string `json:"contract_address" binding:"required"`
-	Remark          string `json:"remark" binding:"max=500"`
+	Standard        string  `json:"standard" binding:"required,oneof=compound openzeppelin"`
+	ChainID         int     `json:"chain_id" binding:"required"`
+	ContractAddress string  `json:"contract_address" binding:"required"`
+	Remark          string  `json:"remark" binding:"max=500"`
+	Nickname        *string `json:"nickname" binding:"omitempty,max=100"` // 合约昵称，不传时保持不变，传空字符串时清除
+}
+
+// BulkUpdateTimeLockRemarkItem 批量更新备注/昵称的单项
+type BulkUpdateTimeLockRemarkItem struct {
+	Standard string  `json:"standard" binding:"required,oneof=compound openzeppelin"`
+	ID       int64   `json:"id" binding:"required"`
+	Remark   *string `json:"remark" binding:"omitempty,max=500"`   // 备注，不传时保持不变
+	Nickname *string `json:"nickname" binding:"omitempty,max=100"` // 合约昵称，不传时保持不变，传空字符串时清除
+}
+
+// BulkUpdateTimeLockRemarksRequest 批量更新备注/昵称请求
+type BulkUpdateTimeLockRemarksRequest struct {
+	Items []BulkUpdateTimeLockRemarkItem `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkUpdateTimeLockRemarkResult 单项更新结果
+type BulkUpdateTimeLockRemarkResult struct {
+	Standard  string `json:"standard"`
+	ID        int64  `json:"id"`
+	Success   bool   `json:"success"`
+	ErrorCode string `json:"error_code,omitempty"` // TIMELOCK_NOT_FOUND, UNAUTHORIZED_ACCESS, INVALID_REMARK, INVALID_NICKNAME, INVALID_STANDARD, INVALID_PARAMETERS, INTERNAL_ERROR
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkUpdateTimeLockRemarksResponse 批量更新备注/昵称响应
+type BulkUpdateTimeLockRemarksResponse struct {
+	Results []BulkUpdateTimeLockRemarkResult `json:"results"` // 与请求 items 顺序一致
+	Updated int                              `json:"updated"`
+	Failed  int                              `json:"failed"`
 }
 
 // DeleteTimeLockRequest 删除timelock合约请求
@@ -87,6 +131,7 @@ type GetTimeLockListResponse struct {
 	CompoundTimeLocks     []CompoundTimeLockWithPermission     `json:"compound_timelocks"`
 	OpenzeppelinTimeLocks []OpenzeppelinTimeLockWithPermission `json:"openzeppelin_timelocks"`
 	Total                 int64                                `json:"total"`
+	PaginationMeta                                             // 列表不分页，固定为单页
 }
 
 // GetTimeLockDetailRequest 获取timelock详情请求
@@ -96,6 +141,120 @@ type GetTimeLockDetailRequest struct {
 	ContractAddress string `json:"contract_address" form:"contract_address" binding:"required"`
 }
 
+// CheckTimeLockExistsRequest 检查合约是否已被当前用户导入请求
+type CheckTimeLockExistsRequest struct {
+	ChainID         int    `json:"chain_id" form:"chain_id" binding:"required"`
+	ContractAddress string `json:"contract_address" form:"contract_address" binding:"required"`
+}
+
+// CheckTimeLockExistsResponse 检查合约是否已被当前用户导入响应
+type CheckTimeLockExistsResponse struct {
+	Exists   bool   `json:"exists"`
+	Standard string `json:"standard,omitempty"` // compound / openzeppelin
+	ID       int64  `json:"id,omitempty"`
+	Status   string `json:"status,omitempty"` // active, inactive
+}
+
+// TimeLockChain 用户有合约的链（用于前端链筛选下拉框）
+type TimeLockChain struct {
+	ChainID       int    `json:"chain_id"`
+	ChainName     string `json:"chain_name"`
+	DisplayName   string `json:"display_name"` // 来自 support_chains，链未配置时为空
+	LogoURL       string `json:"logo_url"`
+	IsTestnet     bool   `json:"is_testnet"`
+	ContractCount int64  `json:"contract_count"` // 用户在该链上的合约数（两种标准合计）
+}
+
+// GetTimeLockChainsResponse 用户有合约的链列表响应
+type GetTimeLockChainsResponse struct {
+	Chains []TimeLockChain `json:"chains"` // 按链ID升序
+}
+
+// GetTimeLockOnchainParamsRequest 实时读取合约链上参数请求（合约 ID 在路径中，按标准区分表）
+type GetTimeLockOnchainParamsRequest struct {
+	Standard string `json:"standard" form:"standard" binding:"required,oneof=compound openzeppelin"`
+}
+
+// OnchainParamDiff 单个参数的数据库值与链上实时值对比
+type OnchainParamDiff struct {
+	Field   string      `json:"field"`   // 参数名
+	Stored  interface{} `json:"stored"`  // 数据库中的值
+	Onchain interface{} `json:"onchain"` // 链上实时值
+	InSync  bool        `json:"in_sync"` // 是否一致
+}
+
+// GetTimeLockOnchainParamsResponse 合约链上参数实时读取响应
+type GetTimeLockOnchainParamsResponse struct {
+	ID              int64              `json:"id"`
+	Standard        string             `json:"standard"`
+	ChainID         int                `json:"chain_id"`
+	ContractAddress string             `json:"contract_address"`
+	InSync          bool               `json:"in_sync"` // 所有参数是否都与链上一致
+	Params          []OnchainParamDiff `json:"params"`
+	CheckedAt       time.Time          `json:"checked_at"` // 读取链上数据的时间
+}
+
+// CheckTimeLockRolesRequest 批量查询地址在合约中的角色请求（合约 ID 在路径中）
+type CheckTimeLockRolesRequest struct {
+	Standard  string   `json:"standard" binding:"required,oneof=compound openzeppelin"`
+	Addresses []string `json:"addresses" binding:"required,min=1,max=100"`
+	Live      bool     `json:"live"` // 是否同时实时读取链上角色
+}
+
+// AddressRoles 单个地址的角色
+type AddressRoles struct {
+	Address      string   `json:"address"`
+	Roles        []string `json:"roles"`         // 基于数据库数据的角色（creator/admin/pending_admin/proposer/executor）
+	OnchainRoles []string `json:"onchain_roles"` // 基于链上实时数据的角色（live=false 时为 null）
+}
+
+// CheckTimeLockRolesResponse 批量查询地址角色响应
+type CheckTimeLockRolesResponse struct {
+	ID              int64          `json:"id"`
+	Standard        string         `json:"standard"`
+	ChainID         int            `json:"chain_id"`
+	ContractAddress string         `json:"contract_address"`
+	Live            bool           `json:"live"`
+	Results         []AddressRoles `json:"results"`
+}
+
+// ComputeCompoundTxHashRequest 计算 Compound queueTransaction 产生的 txHash 请求
+type ComputeCompoundTxHashRequest struct {
+	Target    string `json:"target" binding:"required"` // 目标合约地址
+	Value     string `json:"value"`                     // 转账金额（wei，十进制字符串），为空时为 0
+	Signature string `json:"signature"`                 // 函数签名，如 transfer(address,uint256)，可为空
+	Data      string `json:"data"`                      // 调用参数（0x 开头十六进制，不含函数选择器），可为空
+	Eta       int64  `json:"eta" binding:"required,min=1"`
+}
+
+// ComputeCompoundTxHashResponse 计算 Compound txHash 响应
+type ComputeCompoundTxHashResponse struct {
+	TxHash string `json:"tx_hash"` // 与 flow_id 一致
+}
+
+// ComputeOzOperationIdRequest 计算 OpenZeppelin 单笔操作 id（hashOperation）请求
+type ComputeOzOperationIdRequest struct {
+	Target      string `json:"target" binding:"required"` // 目标合约地址
+	Value       string `json:"value"`                     // 转账金额（wei，十进制字符串），为空时为 0
+	Data        string `json:"data"`                      // 完整 calldata（0x 开头十六进制，含函数选择器），可为空
+	Predecessor string `json:"predecessor"`               // 前置操作 id（bytes32），为空时为 0x00..00
+	Salt        string `json:"salt"`                      // bytes32，为空时为 0x00..00
+}
+
+// ComputeOzOperationBatchIdRequest 计算 OpenZeppelin 批量操作 id（hashOperationBatch）请求
+type ComputeOzOperationBatchIdRequest struct {
+	Targets     []string `json:"targets" binding:"required,min=1,max=100"`
+	Values      []string `json:"values" binding:"required"`   // 与 targets 等长
+	Payloads    []string `json:"payloads" binding:"required"` // 与 targets 等长
+	Predecessor string   `json:"predecessor"`
+	Salt        string   `json:"salt"`
+}
+
+// ComputeOzOperationIdResponse 计算 OpenZeppelin 操作 id 响应
+type ComputeOzOperationIdResponse struct {
+	OperationID string `json:"operation_id"` // 与 flow_id 一致
+}
+
 // GetTimeLockDetailResponse timelock详情响应
 type GetTimeLockDetailResponse struct {
 	Standard         string                              `json:"standard"`
@@ -114,3 +273,60 @@ type OpenzeppelinTimeLockWithPermission struct {
 	OpenzeppelinTimeLock
 	UserPermissions []string `json:"user_permissions"` // creator, proposer, executor, canceller
 }
+
+// TimeLockManifestVersion 合约配置清单格式版本
+const TimeLockManifestVersion = 1
+
+// GetTimeLockManifestRequest 导出合约配置清单请求
+type GetTimeLockManifestRequest struct {
+	Standard string `json:"standard" form:"standard" binding:"required,oneof=compound openzeppelin"`
+}
+
+// TimeLockManifest 可分享的合约配置清单，不含导出者地址、通知配置凭证等用户私有信息
+type TimeLockManifest struct {
+	Version              int                                   `json:"version"`                         // 清单格式版本
+	Standard             string                                `json:"standard"`                        // compound, openzeppelin
+	ChainID              int                                   `json:"chain_id"`                        // 链ID
+	ChainName            string                                `json:"chain_name,omitempty"`            // 链名称（仅展示）
+	ContractAddress      string                                `json:"contract_address"`                // 合约地址
+	Remark               string                                `json:"remark"`                          // 合约备注
+	Nickname             string                                `json:"nickname,omitempty"`              // 合约昵称
+	ABIs                 []TimeLockManifestABI                 `json:"abis"`                            // 导出者解码该合约流程时使用过的 ABI
+	NotificationDefaults *TimeLockManifestNotificationDefaults `json:"notification_defaults,omitempty"` // 导出者的通知渠道开关
+	ExportedAt           time.Time                             `json:"exported_at"`
+}
+
+// TimeLockManifestABI 清单中的 ABI
+type TimeLockManifestABI struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ABIContent  string `json:"abi_content"`
+	IsShared    bool   `json:"is_shared"` // 平台共享 ABI，导入时不重复创建
+}
+
+// TimeLockManifestNotificationDefaults 清单中的通知默认设置（只含渠道开关，不含任何渠道配置与凭证）
+type TimeLockManifestNotificationDefaults struct {
+	Channels []NotificationChannelStatus `json:"channels"`
+}
+
+// ImportTimeLockManifestRequest 导入合约配置清单请求
+type ImportTimeLockManifestRequest struct {
+	Manifest                  TimeLockManifest `json:"manifest"`
+	ApplyNotificationDefaults bool             `json:"apply_notification_defaults"` // 是否用清单中的渠道开关覆盖自己的设置（默认不修改）
+}
+
+// ManifestABIImportResult 清单中单个 ABI 的导入结果
+type ManifestABIImportResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`           // created, exists, failed
+	ABIID  int64  `json:"abi_id,omitempty"` // 新建或已有的 ABI ID
+	Reason string `json:"reason,omitempty"` // 失败原因
+}
+
+// ImportTimeLockManifestResponse 导入合约配置清单响应
+type ImportTimeLockManifestResponse struct {
+	TimeLock                    interface{}               `json:"timelock"` // 与 create-or-import 返回的合约记录一致
+	ABIs                        []ManifestABIImportResult `json:"abis"`
+	NotificationDefaultsApplied bool                      `json:"notification_defaults_applied"`
+	Errors                      []string                  `json:"errors,omitempty"` // 非致命错误（如渠道开关写入失败）
+}
@@ -54,3 +54,18 @@ type OpenzeppelinTimelockTransactionDetail struct {
 	EventPredecessor *string   `json:"event_predecessor"` // 事件前驱（包含前驱交易哈希）
 	EventDelay       *int64    `json:"event_delay"`       // 事件延迟
 }
+
+// GetFlowGoldskyTxRequest 获取流程底层 Goldsky 交易请求
+type GetFlowGoldskyTxRequest struct {
+	FlowIdentifier
+	TxType string `json:"tx_type" form:"tx_type" binding:"omitempty,oneof=queue execute cancel"` // 交易类型，默认 queue（OpenZeppelin 为 schedule 交易）
+}
+
+// GetFlowGoldskyTxResponse 获取流程底层 Goldsky 交易响应，按标准只返回 compound 或 openzeppelin 之一
+type GetFlowGoldskyTxResponse struct {
+	Standard     string                                 `json:"standard"`
+	TxType       string                                 `json:"tx_type"`
+	TxHash       string                                 `json:"tx_hash"`
+	Compound     *CompoundTimelockTransactionDetail     `json:"compound,omitempty"`
+	Openzeppelin *OpenzeppelinTimelockTransactionDetail `json:"openzeppelin,omitempty"`
+}
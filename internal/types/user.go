@@ -51,12 +51,13 @@ type GetNonceResponse struct {
 
 // WalletConnectRequest 钱包连接请求
 type WalletConnectRequest struct {
-	ChainID       int    `json:"chain_id,omitempty"` // Safe需要指定链ID
+	ChainID       int    `json:"chain_id,omitempty"` // Safe需要指定链ID；智能合约钱包（EIP-1271）签名登录也需要
 	WalletAddress string `json:"wallet_address" binding:"required,len=42"`
-	Signature     string `json:"signature,omitempty"`                                  // EOA钱包需要，Safe钱包可选
-	Message       string `json:"message,omitempty"`                                    // EOA钱包需要，Safe钱包可选
+	Signature     string `json:"signature,omitempty"`                                  // EOA与Safe钱包均需要
+	Message       string `json:"message,omitempty"`                                    // EOA与Safe钱包均需要
 	WalletType    string `json:"wallet_type,omitempty"`                                // "eoa", "safe"
-	Nonce         string `json:"nonce" binding:"required_if=WalletType eoa,omitempty"` // EOA钱包需要nonce，Safe钱包不需要
+	Nonce         string `json:"nonce" binding:"required_if=WalletType eoa,omitempty"` // EOA与Safe钱包均需要（Safe 缺失时由服务层返回 MISSING_REQUIRED_FIELDS）
+	SignerAddress string `json:"signer_address,omitempty"`                             // Safe钱包可选：签名的 Safe 所有者地址，提供时会在链上确认其仍为所有者；不提供时签名按 EIP-1271 由 Safe 合约校验
 }
 
 // WalletConnectResponse 钱包连接响应
@@ -83,7 +84,9 @@ type UserProfile struct {
 type JWTClaims struct {
 	UserID        int64  `json:"user_id"`
 	WalletAddress string `json:"wallet_address"`
-	Type          string `json:"type"` // access or refresh
+	Type          string `json:"type"`                // access, refresh or api_token
+	Scope         string `json:"scope,omitempty"`     // 仅 api_token 使用，如 read
+	AuthTime      int64  `json:"auth_time,omitempty"` // 最近一次钱包签名登录的时间（Unix 秒），刷新令牌时沿用
 }
 
 // APIResponse 统一API响应格式
@@ -105,3 +108,25 @@ type ErrorResponse struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+// ChannelSettingsSummary 单个通知渠道的配置概况
+type ChannelSettingsSummary struct {
+	Channel string `json:"channel"` // telegram, lark, feishu, discord, slack, matrix
+	Total   int    `json:"total"`   // 配置总数
+	Active  int    `json:"active"`  // 激活的配置数
+}
+
+// UserSettingsResponse 用户设置汇总
+type UserSettingsResponse struct {
+	WalletAddress    string                   `json:"wallet_address"`
+	VerifiedEmails   []UserEmailResponse      `json:"verified_emails"`   // 已验证邮箱（含各自的通知状态过滤）
+	Channels         []ChannelSettingsSummary `json:"channels"`          // 各通知渠道配置概况
+	EmailPreferences EmailPreferencesResponse `json:"email_preferences"` // 通知邮件的抄送、密送与发件人名称
+}
+
+// UpdateUserSettingsRequest 更新用户标量设置（字段为 nil 表示不修改）
+type UpdateUserSettingsRequest struct {
+	EmailCcEmail  *string `json:"email_cc_email"`  // 通知邮件抄送地址，空字符串表示清除
+	EmailBccEmail *string `json:"email_bcc_email"` // 通知邮件密送地址，空字符串表示清除
+	EmailFromName *string `json:"email_from_name"` // 通知邮件发件人名称，空字符串表示恢复默认
+}
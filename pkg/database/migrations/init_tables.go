@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"timelocker-backend/internal/types"
 	"timelocker-backend/pkg/logger"
+	"timelocker-backend/pkg/utils"
 
 	"gorm.io/gorm"
 )
@@ -80,6 +82,37 @@ func (h *MigrationHandler) runMigrations(ctx context.Context) error {
 		{"v1.0.1", "Create indexes", h.createIndexes},
 		{"v1.0.2", "Insert default chains data", h.insertSupportedChains},
 		{"v1.0.3", "Insert shared ABIs data", h.insertSharedABIs},
+		{"v1.0.4", "Create api_tokens table", h.createAPITokensTable},
+		{"v1.0.5", "Create flow_status_history table", h.createFlowStatusHistoryTable},
+		{"v1.0.6", "Create openzeppelin_flow_calls table", h.createOpenzeppelinFlowCallsTable},
+		{"v1.0.7", "Add creation tx columns to timelock tables", h.addTimelockCreationColumns},
+		{"v1.0.8", "Create flow archive tables", h.createFlowArchiveTables},
+		{"v1.0.9", "Create matrix_configs table", h.createMatrixConfigsTable},
+		{"v1.0.10", "Add provider_message_id column to notification_logs", h.addNotificationLogProviderMessageID},
+		{"v1.0.11", "Add status_reason column to timelock tables", h.addTimelockStatusReasonColumns},
+		{"v1.0.12", "Create user_email_preferences table", h.createUserEmailPreferencesTable},
+		{"v1.0.13", "Add manual change audit columns to flow_status_history", h.addFlowStatusHistoryAuditColumns},
+		{"v1.0.14", "Add notify_statuses column to user_emails", h.addUserEmailNotifyStatuses},
+		{"v1.0.15", "Add explorer_api_key column to support_chains", h.addSupportChainExplorerAPIKey},
+		{"v1.0.16", "Add retry columns to email_send_logs", h.addEmailSendLogRetryColumns},
+		{"v1.0.17", "Create goldsky_webhook_events table", h.createGoldskyWebhookEventsTable},
+		{"v1.0.18", "Create notification_channel_settings table", h.createNotificationChannelSettingsTable},
+		{"v1.0.19", "Add confirmation_depth column to support_chains", h.addSupportChainConfirmationDepth},
+		{"v1.0.20", "Add canceller_address to flow tables and create flow_notes table", h.addFlowCancellerAndNotes},
+		{"v1.0.21", "Create abi_functions selector index table", h.createABIFunctionsTable},
+		{"v1.0.22", "Create flow_decoded_calls table", h.createFlowDecodedCallsTable},
+		{"v1.0.23", "Add prefix/suffix to notification config tables", h.addNotificationMessageAffix},
+		{"v1.0.24", "Create audit_logs table", h.createAuditLogsTable},
+		{"v1.0.25", "Add level column to error_logs", h.addErrorLogLevel},
+		{"v1.0.26", "Add predecessor to openzeppelin flow tables", h.addOpenzeppelinFlowPredecessor},
+		{"v1.0.27", "Create goldsky_webhook_dead_letters table", h.createGoldskyWebhookDeadLettersTable},
+		{"v1.0.28", "Add grace_period_estimated to compound timelock and flow tables", h.addCompoundGracePeriodEstimated},
+		{"v1.0.29", "Add execute_tx_status to flow tables", h.addFlowExecuteTxStatus},
+		{"v1.0.30", "Create flow_flags table", h.createFlowFlagsTable},
+		{"v1.0.31", "Create global_stats_history table", h.createGlobalStatsHistoryTable},
+		{"v1.0.32", "Add delay_warning to timelock tables", h.addTimelockDelayWarning},
+		{"v1.0.33", "Add nickname to timelock tables", h.addTimelockNickname},
+		{"v1.0.34", "Create rpc_fallback_cursors table", h.createRPCFallbackCursorsTable},
 	}
 
 	for _, migration := range migrations {
@@ -1205,9 +1238,780 @@ func (h *MigrationHandler) insertSharedABIs(ctx context.Context) error {
 	return nil
 }
 
+// createAPITokensTable 创建API令牌表（v1.0.4）
+func (h *MigrationHandler) createAPITokensTable(ctx context.Context) error {
+	logger.Info("Creating api_tokens table...")
+
+	if !h.db.Migrator().HasTable("api_tokens") {
+		sql := `
+        CREATE TABLE api_tokens (
+            id BIGSERIAL PRIMARY KEY,
+            user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            wallet_address VARCHAR(42) NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            token_hash VARCHAR(64) NOT NULL UNIQUE,       -- sha256(token)，不存明文
+            token_prefix VARCHAR(16) NOT NULL,            -- 明文前缀，便于识别
+            scope VARCHAR(20) NOT NULL DEFAULT 'read',
+            expires_at TIMESTAMPTZ,
+            last_used_at TIMESTAMPTZ,
+            revoked_at TIMESTAMPTZ,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`
+		if err := h.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to create api_tokens table: %w", err)
+		}
+		logger.Info("Created table: api_tokens")
+	}
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_tokens_wallet ON api_tokens(wallet_address)`,
+	}
+	for _, indexSQL := range indexes {
+		if err := h.db.WithContext(ctx).Exec(indexSQL).Error; err != nil {
+			logger.Error("Failed to create index", err, "sql", indexSQL)
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// createFlowStatusHistoryTable 创建流程状态变更历史表（v1.0.5）
+func (h *MigrationHandler) createFlowStatusHistoryTable(ctx context.Context) error {
+	logger.Info("Creating flow_status_history table...")
+
+	if !h.db.Migrator().HasTable("flow_status_history") {
+		sql := `
+        CREATE TABLE flow_status_history (
+            id BIGSERIAL PRIMARY KEY,
+            flow_id VARCHAR(128) NOT NULL,
+            timelock_standard VARCHAR(20) NOT NULL,
+            chain_id INTEGER NOT NULL,
+            contract_address VARCHAR(42) NOT NULL,
+            status_from VARCHAR(20),                     -- 新建流程时为空
+            status_to VARCHAR(20) NOT NULL,
+            changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`
+		if err := h.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to create flow_status_history table: %w", err)
+		}
+		logger.Info("Created table: flow_status_history")
+	}
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_flow_status_history_flow ON flow_status_history(flow_id, chain_id, contract_address)`,
+		`CREATE INDEX IF NOT EXISTS idx_flow_status_history_changed_at ON flow_status_history(changed_at)`,
+	}
+	for _, indexSQL := range indexes {
+		if err := h.db.WithContext(ctx).Exec(indexSQL).Error; err != nil {
+			logger.Error("Failed to create index", err, "sql", indexSQL)
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // GetMigrationStatus 获取迁移状态（用于监控和调试）
 func GetMigrationStatus(db *gorm.DB) ([]Migration, error) {
 	var migrations []Migration
 	err := db.Order("created_at ASC").Find(&migrations).Error
 	return migrations, err
 }
+
+// createOpenzeppelinFlowCallsTable 创建 OpenZeppelin 操作子调用表（v1.0.6）
+func (h *MigrationHandler) createOpenzeppelinFlowCallsTable(ctx context.Context) error {
+	logger.Info("Creating openzeppelin_flow_calls table...")
+
+	if !h.db.Migrator().HasTable("openzeppelin_flow_calls") {
+		sql := `
+        CREATE TABLE openzeppelin_flow_calls (
+            id BIGSERIAL PRIMARY KEY,
+            flow_id VARCHAR(128) NOT NULL,               -- 操作ID（与 openzeppelin_timelock_flows.flow_id 一致）
+            chain_id INTEGER NOT NULL,
+            contract_address VARCHAR(42) NOT NULL,
+            call_index INTEGER NOT NULL,                 -- CallScheduled 事件中的 index
+            target_address VARCHAR(42) NOT NULL,
+            value DECIMAL(78,0) NOT NULL DEFAULT 0,
+            call_data BYTEA,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`
+		if err := h.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to create openzeppelin_flow_calls table: %w", err)
+		}
+		logger.Info("Created table: openzeppelin_flow_calls")
+	}
+
+	indexes := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_openzeppelin_flow_calls_unique ON openzeppelin_flow_calls(flow_id, chain_id, contract_address, call_index)`,
+	}
+	for _, indexSQL := range indexes {
+		if err := h.db.WithContext(ctx).Exec(indexSQL).Error; err != nil {
+			logger.Error("Failed to create index", err, "sql", indexSQL)
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	logger.Info("openzeppelin_flow_calls table created successfully")
+	return nil
+}
+
+// addTimelockCreationColumns 为 timelock 表添加部署区块/交易列（v1.0.7）
+func (h *MigrationHandler) addTimelockCreationColumns(ctx context.Context) error {
+	logger.Info("Adding creation columns to timelock tables...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelocks ADD COLUMN IF NOT EXISTS creation_block BIGINT`,
+		`ALTER TABLE compound_timelocks ADD COLUMN IF NOT EXISTS creation_tx VARCHAR(66)`,
+		`ALTER TABLE openzeppelin_timelocks ADD COLUMN IF NOT EXISTS creation_block BIGINT`,
+		`ALTER TABLE openzeppelin_timelocks ADD COLUMN IF NOT EXISTS creation_tx VARCHAR(66)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add creation column", err, "sql", stmt)
+			return fmt.Errorf("failed to add creation column: %w", err)
+		}
+	}
+
+	logger.Info("Timelock creation columns added successfully")
+	return nil
+}
+
+// createFlowArchiveTables 创建终态 flow 归档表（v1.0.8）
+// 归档表与热表列顺序一致（按 SELECT * 移动），热表后续增加列时需同步修改归档表
+func (h *MigrationHandler) createFlowArchiveTables(ctx context.Context) error {
+	logger.Info("Creating flow archive tables...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS compound_timelock_flows_archive (LIKE compound_timelock_flows INCLUDING DEFAULTS)`,
+		`CREATE TABLE IF NOT EXISTS openzeppelin_timelock_flows_archive (LIKE openzeppelin_timelock_flows INCLUDING DEFAULTS)`,
+		// 归档表只保留去重和按合约查询所需的索引
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_compound_flows_archive_unique ON compound_timelock_flows_archive(flow_id, chain_id, contract_address)`,
+		`CREATE INDEX IF NOT EXISTS idx_compound_flows_archive_chain_contract ON compound_timelock_flows_archive(chain_id, contract_address)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_oz_flows_archive_unique ON openzeppelin_timelock_flows_archive(flow_id, chain_id, contract_address)`,
+		`CREATE INDEX IF NOT EXISTS idx_oz_flows_archive_chain_contract ON openzeppelin_timelock_flows_archive(chain_id, contract_address)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create flow archive table", err, "sql", stmt)
+			return fmt.Errorf("failed to create flow archive table: %w", err)
+		}
+	}
+
+	logger.Info("Flow archive tables created successfully")
+	return nil
+}
+
+// createMatrixConfigsTable 创建 Matrix 通知配置表（v1.0.9）
+func (h *MigrationHandler) createMatrixConfigsTable(ctx context.Context) error {
+	logger.Info("Creating matrix_configs table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS matrix_configs (
+            id BIGSERIAL PRIMARY KEY,
+            user_address VARCHAR(42) NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            homeserver_url VARCHAR(1000) NOT NULL,
+            access_token VARCHAR(500) NOT NULL,
+            room_id VARCHAR(255) NOT NULL,
+            is_active BOOLEAN NOT NULL DEFAULT TRUE,
+            created_at TIMESTAMPTZ DEFAULT NOW(),
+            updated_at TIMESTAMPTZ DEFAULT NOW(),
+            UNIQUE(user_address, name)
+        )`,
+		`CREATE INDEX IF NOT EXISTS idx_matrix_configs_user ON matrix_configs(user_address)`,
+		`CREATE INDEX IF NOT EXISTS idx_matrix_configs_active ON matrix_configs(is_active)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create matrix_configs table", err, "sql", stmt)
+			return fmt.Errorf("failed to create matrix_configs table: %w", err)
+		}
+	}
+
+	logger.Info("matrix_configs table created successfully")
+	return nil
+}
+
+// addNotificationLogProviderMessageID 为 notification_logs 添加服务商消息ID列（v1.0.10）
+func (h *MigrationHandler) addNotificationLogProviderMessageID(ctx context.Context) error {
+	logger.Info("Adding provider_message_id column to notification_logs...")
+
+	stmt := `ALTER TABLE notification_logs ADD COLUMN IF NOT EXISTS provider_message_id VARCHAR(128) NOT NULL DEFAULT ''`
+	if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		logger.Error("Failed to add provider_message_id column", err, "sql", stmt)
+		return fmt.Errorf("failed to add provider_message_id column: %w", err)
+	}
+
+	logger.Info("provider_message_id column added successfully")
+	return nil
+}
+
+// addTimelockStatusReasonColumns 为 timelock 表添加状态原因列（v1.0.11）
+func (h *MigrationHandler) addTimelockStatusReasonColumns(ctx context.Context) error {
+	logger.Info("Adding status_reason columns to timelock tables...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelocks ADD COLUMN IF NOT EXISTS status_reason VARCHAR(500)`,
+		`ALTER TABLE openzeppelin_timelocks ADD COLUMN IF NOT EXISTS status_reason VARCHAR(500)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add status_reason column", err, "sql", stmt)
+			return fmt.Errorf("failed to add status_reason column: %w", err)
+		}
+	}
+
+	logger.Info("Timelock status_reason columns added successfully")
+	return nil
+}
+
+// createUserEmailPreferencesTable 创建用户邮件偏好表（v1.0.12）
+func (h *MigrationHandler) createUserEmailPreferencesTable(ctx context.Context) error {
+	logger.Info("Creating user_email_preferences table...")
+
+	stmt := `CREATE TABLE IF NOT EXISTS user_email_preferences (
+            id BIGSERIAL PRIMARY KEY,
+            user_id BIGINT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+            cc_email VARCHAR(200),
+            bcc_email VARCHAR(200),
+            from_name VARCHAR(64),
+            created_at TIMESTAMPTZ DEFAULT NOW(),
+            updated_at TIMESTAMPTZ DEFAULT NOW()
+        )`
+	if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		logger.Error("Failed to create user_email_preferences table", err, "sql", stmt)
+		return fmt.Errorf("failed to create user_email_preferences table: %w", err)
+	}
+
+	logger.Info("user_email_preferences table created successfully")
+	return nil
+}
+
+// addFlowStatusHistoryAuditColumns 为 flow_status_history 添加人工变更的操作人与原因列（v1.0.13）
+func (h *MigrationHandler) addFlowStatusHistoryAuditColumns(ctx context.Context) error {
+	logger.Info("Adding audit columns to flow_status_history...")
+
+	statements := []string{
+		`ALTER TABLE flow_status_history ADD COLUMN IF NOT EXISTS changed_by VARCHAR(42)`,
+		`ALTER TABLE flow_status_history ADD COLUMN IF NOT EXISTS reason VARCHAR(500)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add flow_status_history audit column", err, "sql", stmt)
+			return fmt.Errorf("failed to add flow_status_history audit column: %w", err)
+		}
+	}
+
+	logger.Info("flow_status_history audit columns added successfully")
+	return nil
+}
+
+// addUserEmailNotifyStatuses 为 user_emails 添加通知状态过滤列（v1.0.14），NULL 表示接收全部状态
+func (h *MigrationHandler) addUserEmailNotifyStatuses(ctx context.Context) error {
+	logger.Info("Adding notify_statuses column to user_emails...")
+
+	stmt := `ALTER TABLE user_emails ADD COLUMN IF NOT EXISTS notify_statuses TEXT`
+	if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		logger.Error("Failed to add notify_statuses column", err, "sql", stmt)
+		return fmt.Errorf("failed to add notify_statuses column: %w", err)
+	}
+
+	logger.Info("notify_statuses column added successfully")
+	return nil
+}
+
+// addSupportChainExplorerAPIKey 为 support_chains 添加区块浏览器 API Key 列（v1.0.15）
+func (h *MigrationHandler) addSupportChainExplorerAPIKey(ctx context.Context) error {
+	logger.Info("Adding explorer_api_key column to support_chains...")
+
+	stmt := `ALTER TABLE support_chains ADD COLUMN IF NOT EXISTS explorer_api_key TEXT`
+	if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		logger.Error("Failed to add explorer_api_key column", err, "sql", stmt)
+		return fmt.Errorf("failed to add explorer_api_key column: %w", err)
+	}
+
+	logger.Info("explorer_api_key column added successfully")
+	return nil
+}
+
+// addEmailSendLogRetryColumns 为 email_send_logs 添加临时失败重发相关列（v1.0.16）
+func (h *MigrationHandler) addEmailSendLogRetryColumns(ctx context.Context) error {
+	logger.Info("Adding retry columns to email_send_logs...")
+
+	statements := []string{
+		`ALTER TABLE email_send_logs ADD COLUMN IF NOT EXISTS permanent_failure BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE email_send_logs ADD COLUMN IF NOT EXISTS next_retry_at TIMESTAMPTZ`,
+		// 历史失败日志无法判断错误类型，直接视为永久失败，避免升级后集中重发
+		`UPDATE email_send_logs SET permanent_failure = TRUE WHERE send_status = 'failed' AND next_retry_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_send_logs_retry ON email_send_logs(next_retry_at) WHERE send_status = 'failed' AND permanent_failure = FALSE`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add email_send_logs retry column", err, "sql", stmt)
+			return fmt.Errorf("failed to add email_send_logs retry column: %w", err)
+		}
+	}
+
+	logger.Info("email_send_logs retry columns added successfully")
+	return nil
+}
+
+// createGoldskyWebhookEventsTable 创建 Goldsky webhook 事件去重表（v1.0.17）
+func (h *MigrationHandler) createGoldskyWebhookEventsTable(ctx context.Context) error {
+	logger.Info("Creating goldsky_webhook_events table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS goldsky_webhook_events (
+            id BIGSERIAL PRIMARY KEY,
+            event_key VARCHAR(256) NOT NULL,             -- chain_id:standard:实体ID
+            chain_id INTEGER NOT NULL,
+            standard VARCHAR(20) NOT NULL,
+            event_type VARCHAR(50) NOT NULL,
+            tx_hash VARCHAR(66),
+            status VARCHAR(20) NOT NULL DEFAULT 'processing', -- processing, processed, failed
+            attempts INTEGER NOT NULL DEFAULT 1,
+            error_message TEXT,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_goldsky_webhook_events_key ON goldsky_webhook_events(event_key)`,
+		`CREATE INDEX IF NOT EXISTS idx_goldsky_webhook_events_failed ON goldsky_webhook_events(updated_at) WHERE status = 'failed'`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create goldsky_webhook_events table", err, "sql", stmt)
+			return fmt.Errorf("failed to create goldsky_webhook_events table: %w", err)
+		}
+	}
+
+	logger.Info("goldsky_webhook_events table created successfully")
+	return nil
+}
+
+// createNotificationChannelSettingsTable 创建用户按渠道类型的通知开关表（v1.0.18）
+func (h *MigrationHandler) createNotificationChannelSettingsTable(ctx context.Context) error {
+	logger.Info("Creating notification_channel_settings table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS notification_channel_settings (
+            id BIGSERIAL PRIMARY KEY,
+            user_address VARCHAR(42) NOT NULL,           -- 用户地址（小写）
+            channel VARCHAR(20) NOT NULL,                -- telegram, lark, feishu, discord, slack, matrix
+            enabled BOOLEAN NOT NULL DEFAULT TRUE,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_channel_settings_user_channel ON notification_channel_settings(user_address, channel)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create notification_channel_settings table", err, "sql", stmt)
+			return fmt.Errorf("failed to create notification_channel_settings table: %w", err)
+		}
+	}
+
+	logger.Info("notification_channel_settings table created successfully")
+	return nil
+}
+
+// addSupportChainConfirmationDepth 为 support_chains 添加 Webhook 事件确认深度列（v1.0.19）
+func (h *MigrationHandler) addSupportChainConfirmationDepth(ctx context.Context) error {
+	logger.Info("Adding confirmation_depth column to support_chains...")
+
+	stmt := `ALTER TABLE support_chains ADD COLUMN IF NOT EXISTS confirmation_depth INTEGER NOT NULL DEFAULT 0`
+	if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		logger.Error("Failed to add confirmation_depth column", err, "sql", stmt)
+		return fmt.Errorf("failed to add confirmation_depth column: %w", err)
+	}
+
+	logger.Info("confirmation_depth column added successfully")
+	return nil
+}
+
+// addFlowCancellerAndNotes 为 flow 表添加取消者地址列并创建流程备注表（v1.0.20）
+// 归档表按 SELECT * 移动，需与热表同步添加列
+func (h *MigrationHandler) addFlowCancellerAndNotes(ctx context.Context) error {
+	logger.Info("Adding canceller_address to flow tables and creating flow_notes table...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelock_flows ADD COLUMN IF NOT EXISTS canceller_address VARCHAR(42)`,
+		`ALTER TABLE compound_timelock_flows_archive ADD COLUMN IF NOT EXISTS canceller_address VARCHAR(42)`,
+		`ALTER TABLE openzeppelin_timelock_flows ADD COLUMN IF NOT EXISTS canceller_address VARCHAR(42)`,
+		`ALTER TABLE openzeppelin_timelock_flows_archive ADD COLUMN IF NOT EXISTS canceller_address VARCHAR(42)`,
+		`CREATE TABLE IF NOT EXISTS flow_notes (
+            id BIGSERIAL PRIMARY KEY,
+            standard VARCHAR(20) NOT NULL,               -- compound, openzeppelin
+            chain_id INTEGER NOT NULL,
+            contract_address VARCHAR(42) NOT NULL,       -- 合约地址（小写）
+            flow_id VARCHAR(128) NOT NULL,
+            note TEXT NOT NULL,
+            updated_by VARCHAR(42) NOT NULL,             -- 最后修改人地址（小写）
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_flow_notes_flow ON flow_notes(standard, chain_id, contract_address, flow_id)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add flow canceller and notes", err, "sql", stmt)
+			return fmt.Errorf("failed to add flow canceller and notes: %w", err)
+		}
+	}
+
+	logger.Info("flow canceller_address column and flow_notes table created successfully")
+	return nil
+}
+
+// createABIFunctionsTable 创建 ABI 函数选择器索引表并为已有 ABI 建立索引（v1.0.21）
+// 删除 ABI 时级联删除其索引行
+func (h *MigrationHandler) createABIFunctionsTable(ctx context.Context) error {
+	logger.Info("Creating abi_functions table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS abi_functions (
+            id BIGSERIAL PRIMARY KEY,
+            abi_id BIGINT NOT NULL REFERENCES abis(id) ON DELETE CASCADE,
+            selector VARCHAR(10) NOT NULL,               -- 0x开头的4字节选择器（小写）
+            signature TEXT NOT NULL,                     -- 规范化函数签名
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE INDEX IF NOT EXISTS idx_abi_functions_abi_id ON abi_functions(abi_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_abi_functions_selector ON abi_functions(selector)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create abi_functions table", err, "sql", stmt)
+			return fmt.Errorf("failed to create abi_functions table: %w", err)
+		}
+	}
+
+	// 为已有 ABI 建立索引；无法解析的 ABI 跳过（只影响使用统计）
+	var abis []types.ABI
+	if err := h.db.WithContext(ctx).Select("id, abi_content").Find(&abis).Error; err != nil {
+		return fmt.Errorf("failed to load abis for function index: %w", err)
+	}
+	indexed := 0
+	for _, abi := range abis {
+		functions, err := utils.ExtractABIFunctions(abi.ABIContent)
+		if err != nil {
+			logger.Warn("Skipping unparsable ABI for function index", "abi_id", abi.ID, "error", err)
+			continue
+		}
+		if len(functions) == 0 {
+			continue
+		}
+		for i := range functions {
+			functions[i].ABIID = abi.ID
+		}
+		if err := h.db.WithContext(ctx).CreateInBatches(&functions, 200).Error; err != nil {
+			return fmt.Errorf("failed to index functions for abi %d: %w", abi.ID, err)
+		}
+		indexed++
+	}
+
+	logger.Info("abi_functions table created successfully", "indexed_abis", indexed)
+	return nil
+}
+
+// createFlowDecodedCallsTable 创建流程调用解码缓存表（v1.0.22）
+// 按用户隔离，ABI 删除时级联删除由该 ABI 解码的结果
+func (h *MigrationHandler) createFlowDecodedCallsTable(ctx context.Context) error {
+	logger.Info("Creating flow_decoded_calls table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS flow_decoded_calls (
+            id BIGSERIAL PRIMARY KEY,
+            user_address VARCHAR(42) NOT NULL,           -- 触发解码的用户地址（小写）
+            standard VARCHAR(20) NOT NULL,               -- compound, openzeppelin
+            chain_id INTEGER NOT NULL,
+            contract_address VARCHAR(42) NOT NULL,       -- 合约地址（小写）
+            flow_id VARCHAR(128) NOT NULL,
+            call_index INTEGER NOT NULL DEFAULT 0,
+            abi_id BIGINT NOT NULL REFERENCES abis(id) ON DELETE CASCADE,
+            function_name VARCHAR(200) NOT NULL,
+            function_signature TEXT NOT NULL,
+            params JSONB NOT NULL,
+            decoded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_flow_decoded_calls_call ON flow_decoded_calls(user_address, standard, chain_id, contract_address, flow_id, call_index)`,
+		`CREATE INDEX IF NOT EXISTS idx_flow_decoded_calls_abi_id ON flow_decoded_calls(abi_id)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create flow_decoded_calls table", err, "sql", stmt)
+			return fmt.Errorf("failed to create flow_decoded_calls table: %w", err)
+		}
+	}
+
+	logger.Info("flow_decoded_calls table created successfully")
+	return nil
+}
+
+// addNotificationMessageAffix 为各渠道通知配置表添加消息前缀/后缀列（v1.0.23）
+func (h *MigrationHandler) addNotificationMessageAffix(ctx context.Context) error {
+	logger.Info("Adding prefix/suffix to notification config tables...")
+
+	for _, table := range []string{"telegram_configs", "lark_configs", "feishu_configs", "discord_configs", "slack_configs", "matrix_configs"} {
+		statements := []string{
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS prefix VARCHAR(100) NOT NULL DEFAULT ''`, table),
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS suffix VARCHAR(100) NOT NULL DEFAULT ''`, table),
+		}
+		for _, stmt := range statements {
+			if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+				logger.Error("Failed to add notification message prefix/suffix", err, "sql", stmt)
+				return fmt.Errorf("failed to add notification message prefix/suffix: %w", err)
+			}
+		}
+	}
+
+	logger.Info("notification config prefix/suffix columns added successfully")
+	return nil
+}
+
+// createAuditLogsTable 创建配置变更审计日志表（v1.0.24）
+func (h *MigrationHandler) createAuditLogsTable(ctx context.Context) error {
+	logger.Info("Creating audit_logs table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS audit_logs (
+            id BIGSERIAL PRIMARY KEY,
+            actor_address VARCHAR(42) NOT NULL,           -- 操作人钱包地址（小写）
+            action VARCHAR(20) NOT NULL,                  -- create, update, delete
+            target_type VARCHAR(50) NOT NULL,             -- notification_config, timelock, email, api_token
+            target_id VARCHAR(200) NOT NULL,
+            summary TEXT NOT NULL,                        -- 变更摘要，不含凭证明文
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_created ON audit_logs(actor_address, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_created ON audit_logs(created_at DESC)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create audit_logs table", err, "sql", stmt)
+			return fmt.Errorf("failed to create audit_logs table: %w", err)
+		}
+	}
+
+	logger.Info("audit_logs table created successfully")
+	return nil
+}
+
+// addErrorLogLevel 为 error_logs 添加日志级别列（v1.0.25），历史记录均为 error
+func (h *MigrationHandler) addErrorLogLevel(ctx context.Context) error {
+	logger.Info("Adding level column to error_logs...")
+
+	statements := []string{
+		`ALTER TABLE error_logs ADD COLUMN IF NOT EXISTS level VARCHAR(10) NOT NULL DEFAULT 'error'`,
+		`CREATE INDEX IF NOT EXISTS idx_error_logs_level_timestamp ON error_logs(level, timestamp DESC)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add error_logs level column", err, "sql", stmt)
+			return fmt.Errorf("failed to add error_logs level column: %w", err)
+		}
+	}
+
+	logger.Info("error_logs level column added successfully")
+	return nil
+}
+
+// addOpenzeppelinFlowPredecessor 为 OpenZeppelin flow 表（含归档表）添加前置操作列（v1.0.26）
+func (h *MigrationHandler) addOpenzeppelinFlowPredecessor(ctx context.Context) error {
+	logger.Info("Adding predecessor column to openzeppelin flow tables...")
+
+	statements := []string{
+		`ALTER TABLE openzeppelin_timelock_flows ADD COLUMN IF NOT EXISTS predecessor VARCHAR(66)`,
+		`ALTER TABLE openzeppelin_timelock_flows_archive ADD COLUMN IF NOT EXISTS predecessor VARCHAR(66)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add openzeppelin flow predecessor column", err, "sql", stmt)
+			return fmt.Errorf("failed to add openzeppelin flow predecessor column: %w", err)
+		}
+	}
+
+	logger.Info("openzeppelin flow predecessor column added successfully")
+	return nil
+}
+
+// createGoldskyWebhookDeadLettersTable 创建未通过结构校验的 webhook 请求记录表（v1.0.27）
+func (h *MigrationHandler) createGoldskyWebhookDeadLettersTable(ctx context.Context) error {
+	logger.Info("Creating goldsky_webhook_dead_letters table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS goldsky_webhook_dead_letters (
+            id BIGSERIAL PRIMARY KEY,
+            webhook_id VARCHAR(256),
+            remote_addr VARCHAR(64),
+            reason TEXT NOT NULL,                        -- 校验失败原因
+            payload TEXT NOT NULL,                       -- 原始请求体（超长时截断）
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE INDEX IF NOT EXISTS idx_goldsky_webhook_dead_letters_created ON goldsky_webhook_dead_letters(created_at DESC)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create goldsky_webhook_dead_letters table", err, "sql", stmt)
+			return fmt.Errorf("failed to create goldsky_webhook_dead_letters table: %w", err)
+		}
+	}
+
+	logger.Info("goldsky_webhook_dead_letters table created successfully")
+	return nil
+}
+
+// addCompoundGracePeriodEstimated 为 Compound 合约表与 flow 表（含归档表）添加宽限期估算标记（v1.0.28）
+func (h *MigrationHandler) addCompoundGracePeriodEstimated(ctx context.Context) error {
+	logger.Info("Adding grace_period_estimated column to compound tables...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelocks ADD COLUMN IF NOT EXISTS grace_period_estimated BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE compound_timelock_flows ADD COLUMN IF NOT EXISTS grace_period_estimated BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE compound_timelock_flows_archive ADD COLUMN IF NOT EXISTS grace_period_estimated BOOLEAN NOT NULL DEFAULT FALSE`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add grace_period_estimated column", err, "sql", stmt)
+			return fmt.Errorf("failed to add grace_period_estimated column: %w", err)
+		}
+	}
+
+	logger.Info("grace_period_estimated column added successfully")
+	return nil
+}
+
+// addFlowExecuteTxStatus 为 flow 表（含归档表）添加执行交易回执状态（v1.0.29）
+func (h *MigrationHandler) addFlowExecuteTxStatus(ctx context.Context) error {
+	logger.Info("Adding execute_tx_status column to flow tables...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelock_flows ADD COLUMN IF NOT EXISTS execute_tx_status VARCHAR(20)`,
+		`ALTER TABLE compound_timelock_flows_archive ADD COLUMN IF NOT EXISTS execute_tx_status VARCHAR(20)`,
+		`ALTER TABLE openzeppelin_timelock_flows ADD COLUMN IF NOT EXISTS execute_tx_status VARCHAR(20)`,
+		`ALTER TABLE openzeppelin_timelock_flows_archive ADD COLUMN IF NOT EXISTS execute_tx_status VARCHAR(20)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add execute_tx_status column", err, "sql", stmt)
+			return fmt.Errorf("failed to add execute_tx_status column: %w", err)
+		}
+	}
+
+	logger.Info("execute_tx_status column added successfully")
+	return nil
+}
+
+// createFlowFlagsTable 创建可疑流程标记表（v1.0.30）
+func (h *MigrationHandler) createFlowFlagsTable(ctx context.Context) error {
+	logger.Info("Creating flow_flags table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS flow_flags (
+            id BIGSERIAL PRIMARY KEY,
+            standard VARCHAR(20) NOT NULL,               -- compound, openzeppelin
+            chain_id INTEGER NOT NULL,
+            contract_address VARCHAR(42) NOT NULL,       -- 合约地址（小写）
+            flow_id VARCHAR(128) NOT NULL,
+            flagged_by VARCHAR(42) NOT NULL,             -- 标记人地址（小写）
+            note TEXT NOT NULL,
+            notified BOOLEAN NOT NULL DEFAULT FALSE,     -- 是否已发送告警
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`,
+		`CREATE INDEX IF NOT EXISTS idx_flow_flags_contract ON flow_flags(standard, chain_id, contract_address, created_at DESC)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create flow_flags table", err, "sql", stmt)
+			return fmt.Errorf("failed to create flow_flags table: %w", err)
+		}
+	}
+
+	logger.Info("flow_flags table created successfully")
+	return nil
+}
+
+// createGlobalStatsHistoryTable 创建全局统计快照表（v1.0.31）
+func (h *MigrationHandler) createGlobalStatsHistoryTable(ctx context.Context) error {
+	logger.Info("Creating global_stats_history table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS global_stats_history (
+            id BIGSERIAL PRIMARY KEY,
+            chain_count BIGINT NOT NULL DEFAULT 0,
+            contract_count BIGINT NOT NULL DEFAULT 0,
+            transaction_count BIGINT NOT NULL DEFAULT 0,
+            snapshot_at TIMESTAMPTZ NOT NULL
+        )`,
+		`CREATE INDEX IF NOT EXISTS idx_global_stats_history_snapshot_at ON global_stats_history(snapshot_at)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to create global_stats_history table", err, "sql", stmt)
+			return fmt.Errorf("failed to create global_stats_history table: %w", err)
+		}
+	}
+
+	logger.Info("global_stats_history table created successfully")
+	return nil
+}
+
+// addTimelockDelayWarning 为合约表添加 delay 过短警告（v1.0.32）
+func (h *MigrationHandler) addTimelockDelayWarning(ctx context.Context) error {
+	logger.Info("Adding delay_warning column to timelock tables...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelocks ADD COLUMN IF NOT EXISTS delay_warning VARCHAR(200)`,
+		`ALTER TABLE openzeppelin_timelocks ADD COLUMN IF NOT EXISTS delay_warning VARCHAR(200)`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add delay_warning column", err, "sql", stmt)
+			return fmt.Errorf("failed to add delay_warning column: %w", err)
+		}
+	}
+
+	logger.Info("delay_warning column added successfully")
+	return nil
+}
+
+// addTimelockNickname 为合约表添加通知中展示的合约昵称（v1.0.33）
+func (h *MigrationHandler) addTimelockNickname(ctx context.Context) error {
+	logger.Info("Adding nickname column to timelock tables...")
+
+	statements := []string{
+		`ALTER TABLE compound_timelocks ADD COLUMN IF NOT EXISTS nickname VARCHAR(100) DEFAULT ''`,
+		`ALTER TABLE openzeppelin_timelocks ADD COLUMN IF NOT EXISTS nickname VARCHAR(100) DEFAULT ''`,
+	}
+	for _, stmt := range statements {
+		if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			logger.Error("Failed to add nickname column", err, "sql", stmt)
+			return fmt.Errorf("failed to add nickname column: %w", err)
+		}
+	}
+
+	logger.Info("nickname column added successfully")
+	return nil
+}
+
+// createRPCFallbackCursorsTable 创建无 subgraph 链的 RPC 日志扫描游标表（v1.0.34）
+func (h *MigrationHandler) createRPCFallbackCursorsTable(ctx context.Context) error {
+	logger.Info("Creating rpc_fallback_cursors table...")
+
+	stmt := `CREATE TABLE IF NOT EXISTS rpc_fallback_cursors (
+            chain_id INTEGER NOT NULL,
+            contract_address VARCHAR(42) NOT NULL,      -- 合约地址（小写）
+            last_block BIGINT NOT NULL,                 -- 已扫描到的区块（含）
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            PRIMARY KEY (chain_id, contract_address)
+        )`
+	if err := h.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		logger.Error("Failed to create rpc_fallback_cursors table", err, "sql", stmt)
+		return fmt.Errorf("failed to create rpc_fallback_cursors table: %w", err)
+	}
+
+	logger.Info("rpc_fallback_cursors table created successfully")
+	return nil
+}
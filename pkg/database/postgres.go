@@ -8,11 +8,18 @@ import (
 	"timelocker-backend/pkg/database/migrations"
 	"timelocker-backend/pkg/logger"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
 )
 
+// queryCancelDeadlineDelay 发送取消请求后等待服务端响应的时间，超时后关闭连接
+const queryCancelDeadlineDelay = 2 * time.Second
+
 // NewPostgresConnection 创建PostgreSQL数据库连接
 func NewPostgresConnection(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
@@ -20,7 +27,18 @@ func NewPostgresConnection(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		logger.Error("Failed to parse database config", err)
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
+	}
+	// 请求上下文取消或超时（如读接口的查询超时）时向 PostgreSQL 发送取消请求，避免慢查询在服务端继续占用连接；
+	// 默认处理只关闭客户端连接
+	connConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: pgConn, DeadlineDelay: queryCancelDeadlineDelay}
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: stdlib.OpenDB(*connConfig)}), &gorm.Config{
 		Logger: gormLogger.Default.LogMode(gormLogger.Error),
 	})
 	if err != nil {
@@ -0,0 +1,28 @@
+package email
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+)
+
+// IsTransientError 判断 SMTP 发送错误是否为临时性错误（可重试）
+// 4xx 回复码（如 421 服务不可用、450 邮箱忙、451 本地错误、452 存储不足）以及网络错误视为临时错误；
+// 5xx 回复码（如 550 邮箱不存在、535 认证失败）及其余错误视为永久错误，重试无意义
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
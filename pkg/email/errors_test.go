@@ -0,0 +1,42 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"421 service not available", &textproto.Error{Code: 421, Msg: "Service not available"}, true},
+		{"450 mailbox busy", &textproto.Error{Code: 450, Msg: "Mailbox unavailable"}, true},
+		{"wrapped 451", fmt.Errorf("send mail: %w", &textproto.Error{Code: 451, Msg: "Local error"}), true},
+		{"550 mailbox not found", &textproto.Error{Code: 550, Msg: "No such user"}, false},
+		{"535 authentication failed", &textproto.Error{Code: 535, Msg: "Authentication failed"}, false},
+		{"dial refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"dns error", &net.DNSError{Err: "no such host", Name: "smtp.example.com"}, true},
+		{"deadline exceeded", os.ErrDeadlineExceeded, true},
+		{"io eof", io.EOF, true},
+		{"wrapped unexpected eof", fmt.Errorf("read reply: %w", io.ErrUnexpectedEOF), true},
+		{"context canceled", context.Canceled, false},
+		{"plain error", errors.New("invalid recipient"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.want {
+				t.Fatalf("IsTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package email
 
 import (
 	"fmt"
+	"net/mail"
 	"net/smtp"
+	"strings"
 	"timelocker-backend/internal/config"
 )
 
@@ -37,6 +39,56 @@ func (s *SMTPSender) SendEmail(to, subject, body string) error {
 	return nil
 }
 
+// SendOptions 单封邮件的可选发送参数
+type SendOptions struct {
+	FromName string // 发件人名称，为空时使用配置中的默认名称
+	Cc       string // 抄送地址，写入 Cc 头
+	Bcc      string // 密送地址，仅作为收件人投递，不写入邮件头
+}
+
+// SendHTMLEmailWithOptions 按可选参数发送HTML格式邮件
+func (s *SMTPSender) SendHTMLEmailWithOptions(to, subject, htmlBody string, opts SendOptions) error {
+	auth := smtp.PlainAuth("", s.config.SMTPUsername, s.config.SMTPPassword, s.config.SMTPHost)
+
+	msg, recipients := s.buildMessage(to, subject, htmlBody, opts)
+
+	addr := fmt.Sprintf("%s:%d", s.config.SMTPHost, s.config.SMTPPort)
+	if err := smtp.SendMail(addr, auth, s.config.FromEmail, recipients, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// buildMessage 构建HTML邮件内容与投递地址列表
+func (s *SMTPSender) buildMessage(to, subject, htmlBody string, opts SendOptions) ([]byte, []string) {
+	fromName := sanitizeHeaderValue(opts.FromName)
+	if fromName == "" {
+		fromName = s.config.FromName
+	}
+	// mail.Address 会对非 ASCII 名称做 RFC 2047 编码
+	from := (&mail.Address{Name: fromName, Address: s.config.FromEmail}).String()
+
+	recipients := []string{to}
+	var b strings.Builder
+	fmt.Fprintf(&b, "To: %s\r\nFrom: %s\r\n", to, from)
+	if cc := sanitizeHeaderValue(opts.Cc); cc != "" && !strings.EqualFold(cc, to) {
+		fmt.Fprintf(&b, "Cc: %s\r\n", cc)
+		recipients = append(recipients, cc)
+	}
+	if bcc := sanitizeHeaderValue(opts.Bcc); bcc != "" && !strings.EqualFold(bcc, to) {
+		recipients = append(recipients, bcc)
+	}
+	fmt.Fprintf(&b, "Subject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s", subject, htmlBody)
+	return []byte(b.String()), recipients
+}
+
+// sanitizeHeaderValue 去除换行符，防止邮件头注入
+func sanitizeHeaderValue(v string) string {
+	v = strings.ReplaceAll(v, "\r", "")
+	v = strings.ReplaceAll(v, "\n", "")
+	return strings.TrimSpace(v)
+}
+
 // SendHTMLEmail 发送HTML格式邮件
 func (s *SMTPSender) SendHTMLEmail(to, subject, htmlBody string) error {
 	return s.SendEmail(to, subject, htmlBody)
@@ -0,0 +1,96 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"timelocker-backend/internal/config"
+)
+
+func TestBuildMessage(t *testing.T) {
+	s := NewSMTPSender(&config.EmailConfig{FromName: "TimeLocker", FromEmail: "noreply@timelocker.io"})
+	const to = "user@example.com"
+
+	tests := []struct {
+		name           string
+		opts           SendOptions
+		wantFrom       string
+		wantCc         string // 为空表示不应有 Cc 头
+		wantRecipients []string
+	}{
+		{
+			name:           "defaults",
+			wantFrom:       "From: \"TimeLocker\" <noreply@timelocker.io>\r\n",
+			wantRecipients: []string{to},
+		},
+		{
+			name:           "custom from name",
+			opts:           SendOptions{FromName: "Treasury Ops"},
+			wantFrom:       "From: \"Treasury Ops\" <noreply@timelocker.io>\r\n",
+			wantRecipients: []string{to},
+		},
+		{
+			name:           "non-ascii from name is rfc 2047 encoded",
+			opts:           SendOptions{FromName: "时间锁"},
+			wantFrom:       "From: =?utf-8?q?=E6=97=B6=E9=97=B4=E9=94=81?= <noreply@timelocker.io>\r\n",
+			wantRecipients: []string{to},
+		},
+		{
+			name:           "blank from name falls back to config",
+			opts:           SendOptions{FromName: " \r\n "},
+			wantFrom:       "From: \"TimeLocker\" <noreply@timelocker.io>\r\n",
+			wantRecipients: []string{to},
+		},
+		{
+			name:           "cc and bcc",
+			opts:           SendOptions{Cc: "team@example.com", Bcc: "audit@example.com"},
+			wantFrom:       "From: \"TimeLocker\" <noreply@timelocker.io>\r\n",
+			wantCc:         "Cc: team@example.com\r\n",
+			wantRecipients: []string{to, "team@example.com", "audit@example.com"},
+		},
+		{
+			name:           "cc and bcc equal to recipient are skipped",
+			opts:           SendOptions{Cc: "USER@example.com", Bcc: "user@example.com"},
+			wantFrom:       "From: \"TimeLocker\" <noreply@timelocker.io>\r\n",
+			wantRecipients: []string{to},
+		},
+		{
+			name:           "header injection stripped",
+			opts:           SendOptions{FromName: "Ops\r\nBcc: evil@example.com", Cc: "team@example.com\r\nBcc: evil@example.com"},
+			wantFrom:       "From: \"OpsBcc: evil@example.com\" <noreply@timelocker.io>\r\n",
+			wantCc:         "Cc: team@example.comBcc: evil@example.com\r\n",
+			wantRecipients: []string{to, "team@example.comBcc: evil@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, recipients := s.buildMessage(to, "Subject line", "<p>body</p>", tt.opts)
+			header, body, ok := strings.Cut(string(msg), "\r\n\r\n")
+			if !ok {
+				t.Fatalf("message has no header/body separator: %q", msg)
+			}
+			header += "\r\n"
+			if body != "<p>body</p>" {
+				t.Fatalf("body = %q", body)
+			}
+			if !strings.HasPrefix(header, "To: "+to+"\r\n") {
+				t.Fatalf("header %q does not start with the To line", header)
+			}
+			if !strings.Contains(header, tt.wantFrom) {
+				t.Fatalf("header %q does not contain %q", header, tt.wantFrom)
+			}
+			if tt.wantCc == "" && strings.Contains(header, "Cc:") {
+				t.Fatalf("header %q has an unexpected Cc line", header)
+			}
+			if tt.wantCc != "" && !strings.Contains(header, tt.wantCc) {
+				t.Fatalf("header %q does not contain %q", header, tt.wantCc)
+			}
+			if strings.Count(header, "\r\n") != strings.Count(header, "\n") || strings.Contains(header, "\r\nBcc:") {
+				t.Fatalf("header %q contains an injected or bare line break", header)
+			}
+			if strings.Join(recipients, ",") != strings.Join(tt.wantRecipients, ",") {
+				t.Fatalf("recipients = %v, want %v", recipients, tt.wantRecipients)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ var (
 type ErrorLog struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
 	Timestamp time.Time `gorm:"autoCreateTime" json:"timestamp"`
+	Level     string    `gorm:"size:10;not null;default:'error'" json:"level"` // error, fatal, warn
 	Caller    string    `gorm:"size:255;not null" json:"caller"`
 	Function  string    `gorm:"size:255;not null" json:"function"`
 	Message   string    `gorm:"not null" json:"message"`
@@ -47,12 +48,13 @@ func (ErrorLog) TableName() string {
 
 // DBErrorWriter 数据库错误日志写入器
 type DBErrorWriter struct {
-	db *gorm.DB
+	db          *gorm.DB
+	persistWarn bool // 是否同时写入 warn 级别日志
 }
 
-// NewDBErrorWriter 创建数据库错误日志写入器
-func NewDBErrorWriter(db *gorm.DB) *DBErrorWriter {
-	return &DBErrorWriter{db: db}
+// NewDBErrorWriter 创建数据库错误日志写入器，minLevel 为 WARN 时同时写入警告，其他值只写入错误
+func NewDBErrorWriter(db *gorm.DB, minLevel LogLevel) *DBErrorWriter {
+	return &DBErrorWriter{db: db, persistWarn: minLevel == WARN}
 }
 
 // WriteError 写入错误日志到数据库
@@ -93,6 +95,7 @@ func (w *DBErrorWriter) WriteError(level, message string, err error, fields ...i
 	// 创建错误日志记录
 	errorLog := &ErrorLog{
 		Timestamp: time.Now(),
+		Level:     level,
 		Caller:    caller,
 		Function:  function,
 		Message:   message,
@@ -228,9 +231,10 @@ func Init(config *Config) {
 }
 
 // SetDB 设置数据库连接，用于错误日志写入
-func SetDB(db *gorm.DB) {
+// minLevel 为 WARN 时 Warn 日志也写入数据库，其他值（默认）只写入 Error/Fatal
+func SetDB(db *gorm.DB, minLevel LogLevel) {
 	if db != nil {
-		dbWriter = NewDBErrorWriter(db)
+		dbWriter = NewDBErrorWriter(db, minLevel)
 	}
 }
 
@@ -442,6 +446,11 @@ func Warn(msg string, fields ...interface{}) {
 	}
 
 	globalLogger.Warn(msg, zapFields...)
+
+	// 配置了写入警告时写入数据库（异步）
+	if dbWriter != nil && dbWriter.persistWarn {
+		dbWriter.WriteError("warn", msg, nil, fields...)
+	}
 }
 
 // Error 错误日志
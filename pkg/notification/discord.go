@@ -1,19 +1,22 @@
 package notification
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 // DiscordSender Discord消息发送器
-type DiscordSender struct{}
+type DiscordSender struct {
+	timeout time.Duration
+}
 
-// NewDiscordSender 创建Discord发送器实例
-func NewDiscordSender() *DiscordSender {
-	return &DiscordSender{}
+// NewDiscordSender 创建Discord发送器实例，timeout 为单次发送超时（<= 0 时使用 DefaultSendTimeout）
+func NewDiscordSender(timeout time.Duration) *DiscordSender {
+	return &DiscordSender{timeout: sendTimeoutOrDefault(timeout)}
 }
 
 // DiscordMessage Discord消息结构
@@ -21,33 +24,49 @@ type DiscordMessage struct {
 	Content string `json:"content"`
 }
 
-// SendMessage 发送Discord消息
-func (s *DiscordSender) SendMessage(webhookURL, message string) error {
+// discordSendResponse Discord webhook 在 wait=true 时返回的消息对象
+type discordSendResponse struct {
+	ID string `json:"id"`
+}
+
+// SendMessage 发送Discord消息，返回 Discord 的消息ID
+// 请求带上 wait=true，Discord 会等消息创建后返回消息对象（否则只返回 204）
+func (s *DiscordSender) SendMessage(ctx context.Context, webhookURL, message string) (string, error) {
 	discordMsg := DiscordMessage{
 		Content: message,
 	}
 
 	jsonData, err := json.Marshal(discordMsg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal discord message: %w", err)
-	}
-
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+		return "", fmt.Errorf("failed to marshal discord message: %w", err)
 	}
 
-	// 发送请求
-	resp, err := client.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	// 发送请求（超时由 context 控制）
+	statusCode, respBody, err := doJSON(ctx, s.timeout, http.MethodPost, discordWaitURL(webhookURL), nil, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to send discord message: %w", err)
+		return "", fmt.Errorf("failed to send discord message: %w", err)
 	}
-	defer resp.Body.Close()
 
 	// 检查响应状态码
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	if statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+		return "", fmt.Errorf("discord webhook returned status %d", statusCode)
 	}
 
-	return nil
+	var resp discordSendResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return "", nil
+	}
+	return resp.ID, nil
+}
+
+// discordWaitURL 为 webhook URL 加上 wait=true，保留已有的查询参数（如 thread_id）
+func discordWaitURL(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	q := u.Query()
+	q.Set("wait", "true")
+	u.RawQuery = q.Encode()
+	return u.String()
 }
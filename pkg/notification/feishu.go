@@ -1,7 +1,7 @@
 package notification
 
 import (
-	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -13,11 +13,13 @@ import (
 )
 
 // FeishuSender 飞书消息发送器
-type FeishuSender struct{}
+type FeishuSender struct {
+	timeout time.Duration
+}
 
-// NewFeishuSender 创建飞书发送器实例
-func NewFeishuSender() *FeishuSender {
-	return &FeishuSender{}
+// NewFeishuSender 创建飞书发送器实例，timeout 为单次发送超时（<= 0 时使用 DefaultSendTimeout）
+func NewFeishuSender(timeout time.Duration) *FeishuSender {
+	return &FeishuSender{timeout: sendTimeoutOrDefault(timeout)}
 }
 
 // FeishuMessage 飞书消息结构
@@ -34,7 +36,8 @@ type FeishuMessageContent struct {
 }
 
 // SendMessage 发送飞书消息
-func (s *FeishuSender) SendMessage(webhookURL, secret, message string) error {
+// 自定义机器人 webhook 不返回消息ID，成功时返回空字符串
+func (s *FeishuSender) SendMessage(ctx context.Context, webhookURL, secret, message string) (string, error) {
 	feishuMsg := FeishuMessage{
 		MsgType: "text",
 		Content: FeishuMessageContent{
@@ -52,27 +55,21 @@ func (s *FeishuSender) SendMessage(webhookURL, secret, message string) error {
 
 	jsonData, err := json.Marshal(feishuMsg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal feishu message: %w", err)
-	}
-
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+		return "", fmt.Errorf("failed to marshal feishu message: %w", err)
 	}
 
-	// 发送请求
-	resp, err := client.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	// 发送请求（超时由 context 控制）
+	statusCode, err := postJSON(ctx, s.timeout, webhookURL, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to send feishu message: %w", err)
+		return "", fmt.Errorf("failed to send feishu message: %w", err)
 	}
-	defer resp.Body.Close()
 
 	// 检查响应状态码
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("feishu webhook returned status %d", resp.StatusCode)
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("feishu webhook returned status %d", statusCode)
 	}
 
-	return nil
+	return "", nil
 }
 
 // generateSign 生成飞书签名
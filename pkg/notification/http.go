@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"timelocker-backend/pkg/utils"
+)
+
+// DefaultSendTimeout 未配置渠道超时时单次发送的超时时间
+const DefaultSendTimeout = 30 * time.Second
+
+// ErrSendTimeout 发送超时（服务商无响应），记录为失败以便后续重试
+var ErrSendTimeout = errors.New("notification send timeout")
+
+// httpClient 各发送器共用的 HTTP 客户端，超时由每次请求的 context 控制；
+// 连接时校验目标 IP，拒绝用户配置的 URL 指向内网/本机地址（SSRF 防护）
+var httpClient = utils.NewSafeHTTPClient(0)
+
+// sendTimeoutOrDefault timeout <= 0 时使用默认超时
+func sendTimeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultSendTimeout
+	}
+	return timeout
+}
+
+// postJSON 在 timeout 内发送 JSON POST 请求并返回响应状态码，超时返回 ErrSendTimeout
+func postJSON(ctx context.Context, timeout time.Duration, url string, body []byte) (int, error) {
+	statusCode, _, err := doJSON(ctx, timeout, http.MethodPost, url, nil, body)
+	return statusCode, err
+}
+
+// doJSON 在 timeout 内发送 JSON 请求，返回响应状态码与响应体，超时返回 ErrSendTimeout
+func doJSON(ctx context.Context, timeout time.Duration, method, url string, headers map[string]string, body []byte) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, nil, fmt.Errorf("%w after %s", ErrSendTimeout, timeout)
+		}
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	// 只读取有限长度的响应体，用于解析错误信息
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, nil, fmt.Errorf("%w after %s", ErrSendTimeout, timeout)
+		}
+		return resp.StatusCode, nil, err
+	}
+	return resp.StatusCode, respBody, nil
+}
@@ -1,7 +1,7 @@
 package notification
 
 import (
-	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -13,11 +13,13 @@ import (
 )
 
 // LarkSender Lark消息发送器
-type LarkSender struct{}
+type LarkSender struct {
+	timeout time.Duration
+}
 
-// NewLarkSender 创建Lark发送器实例
-func NewLarkSender() *LarkSender {
-	return &LarkSender{}
+// NewLarkSender 创建Lark发送器实例，timeout 为单次发送超时（<= 0 时使用 DefaultSendTimeout）
+func NewLarkSender(timeout time.Duration) *LarkSender {
+	return &LarkSender{timeout: sendTimeoutOrDefault(timeout)}
 }
 
 // LarkMessage Lark消息结构
@@ -34,7 +36,8 @@ type LarkMessageContent struct {
 }
 
 // SendMessage 发送Lark消息
-func (s *LarkSender) SendMessage(webhookURL, secret, message string) error {
+// 自定义机器人 webhook 不返回消息ID，成功时返回空字符串
+func (s *LarkSender) SendMessage(ctx context.Context, webhookURL, secret, message string) (string, error) {
 	larkMsg := LarkMessage{
 		MsgType: "text",
 		Content: LarkMessageContent{
@@ -52,27 +55,21 @@ func (s *LarkSender) SendMessage(webhookURL, secret, message string) error {
 
 	jsonData, err := json.Marshal(larkMsg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal lark message: %w", err)
-	}
-
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+		return "", fmt.Errorf("failed to marshal lark message: %w", err)
 	}
 
-	// 发送请求
-	resp, err := client.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	// 发送请求（超时由 context 控制）
+	statusCode, err := postJSON(ctx, s.timeout, webhookURL, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to send lark message: %w", err)
+		return "", fmt.Errorf("failed to send lark message: %w", err)
 	}
-	defer resp.Body.Close()
 
 	// 检查响应状态码
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("lark webhook returned status %d", resp.StatusCode)
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("lark webhook returned status %d", statusCode)
 	}
 
-	return nil
+	return "", nil
 }
 
 // generateSign 生成Lark签名
@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrMatrixTokenInvalid Matrix access token 无效或已过期，用户需要重新登录获取 token
+var ErrMatrixTokenInvalid = errors.New("matrix access token is invalid or expired, please re-authenticate")
+
+// MatrixSender Matrix消息发送器
+type MatrixSender struct {
+	timeout time.Duration
+	txnSeq  atomic.Uint64
+}
+
+// NewMatrixSender 创建Matrix发送器实例，timeout 为单次发送超时（<= 0 时使用 DefaultSendTimeout）
+func NewMatrixSender(timeout time.Duration) *MatrixSender {
+	return &MatrixSender{timeout: sendTimeoutOrDefault(timeout)}
+}
+
+// MatrixMessage Matrix m.room.message 消息事件内容
+type MatrixMessage struct {
+	MsgType string `json:"msgtype"`
+	Body    string `json:"body"`
+}
+
+// matrixSendResponse Matrix 发送消息事件的响应
+type matrixSendResponse struct {
+	EventID string `json:"event_id"`
+}
+
+// matrixErrorResponse Matrix client-server API 错误响应
+type matrixErrorResponse struct {
+	ErrCode string `json:"errcode"`
+	Error   string `json:"error"`
+}
+
+// SendMessage 发送Matrix消息
+// 通过 client-server API 向房间发送 m.room.message 事件：
+// PUT /_matrix/client/v3/rooms/{roomId}/send/m.room.message/{txnId}
+// 成功时返回事件的 event_id
+func (s *MatrixSender) SendMessage(ctx context.Context, homeserverURL, accessToken, roomID, message string) (string, error) {
+	matrixMsg := MatrixMessage{
+		MsgType: "m.text",
+		Body:    message,
+	}
+
+	jsonData, err := json.Marshal(matrixMsg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal matrix message: %w", err)
+	}
+
+	// 发送请求（超时由 context 控制）
+	headers := map[string]string{"Authorization": "Bearer " + accessToken}
+	statusCode, respBody, err := doJSON(ctx, s.timeout, http.MethodPut, s.sendURL(homeserverURL, roomID), headers, jsonData)
+	if err != nil {
+		return "", fmt.Errorf("failed to send matrix message: %w", err)
+	}
+
+	// 检查响应状态码
+	if statusCode != http.StatusOK {
+		return "", classifyMatrixError(statusCode, respBody)
+	}
+
+	var resp matrixSendResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return "", nil
+	}
+	return resp.EventID, nil
+}
+
+// sendURL 构造发送消息的URL，txnId 在进程内唯一
+func (s *MatrixSender) sendURL(homeserverURL, roomID string) string {
+	txnID := fmt.Sprintf("timelocker-%d-%d", time.Now().UnixNano(), s.txnSeq.Add(1))
+	return fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(strings.TrimSpace(homeserverURL), "/"),
+		url.PathEscape(roomID),
+		url.PathEscape(txnID),
+	)
+}
+
+// classifyMatrixError 将 Matrix 错误响应转换为错误，token 无效时返回 ErrMatrixTokenInvalid
+func classifyMatrixError(statusCode int, respBody []byte) error {
+	var errResp matrixErrorResponse
+	_ = json.Unmarshal(respBody, &errResp)
+
+	switch errResp.ErrCode {
+	case "M_UNKNOWN_TOKEN", "M_MISSING_TOKEN":
+		return fmt.Errorf("%w: %s", ErrMatrixTokenInvalid, errResp.Error)
+	}
+	if statusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w: status %d", ErrMatrixTokenInvalid, statusCode)
+	}
+	if errResp.ErrCode != "" {
+		return fmt.Errorf("matrix homeserver returned status %d: %s %s", statusCode, errResp.ErrCode, errResp.Error)
+	}
+	return fmt.Errorf("matrix homeserver returned status %d", statusCode)
+}
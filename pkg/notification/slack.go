@@ -1,7 +1,7 @@
 package notification
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,11 +9,13 @@ import (
 )
 
 // SlackSender Slack消息发送器
-type SlackSender struct{}
+type SlackSender struct {
+	timeout time.Duration
+}
 
-// NewSlackSender 创建Slack发送器实例
-func NewSlackSender() *SlackSender {
-	return &SlackSender{}
+// NewSlackSender 创建Slack发送器实例，timeout 为单次发送超时（<= 0 时使用 DefaultSendTimeout）
+func NewSlackSender(timeout time.Duration) *SlackSender {
+	return &SlackSender{timeout: sendTimeoutOrDefault(timeout)}
 }
 
 // SlackMessage Slack消息结构
@@ -22,32 +24,27 @@ type SlackMessage struct {
 }
 
 // SendMessage 发送Slack消息
-func (s *SlackSender) SendMessage(webhookURL, message string) error {
+// Incoming Webhook 只返回 "ok"，不返回消息 ts，成功时返回空字符串
+func (s *SlackSender) SendMessage(ctx context.Context, webhookURL, message string) (string, error) {
 	slackMsg := SlackMessage{
 		Text: message,
 	}
 
 	jsonData, err := json.Marshal(slackMsg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal slack message: %w", err)
-	}
-
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+		return "", fmt.Errorf("failed to marshal slack message: %w", err)
 	}
 
-	// 发送请求
-	resp, err := client.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	// 发送请求（超时由 context 控制）
+	statusCode, err := postJSON(ctx, s.timeout, webhookURL, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to send slack message: %w", err)
+		return "", fmt.Errorf("failed to send slack message: %w", err)
 	}
-	defer resp.Body.Close()
 
 	// 检查响应状态码
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("slack webhook returned status %d", statusCode)
 	}
 
-	return nil
+	return "", nil
 }
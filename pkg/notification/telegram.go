@@ -1,19 +1,22 @@
 package notification
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 // TelegramSender Telegram消息发送器
-type TelegramSender struct{}
+type TelegramSender struct {
+	timeout time.Duration
+}
 
-// NewTelegramSender 创建Telegram发送器实例
-func NewTelegramSender() *TelegramSender {
-	return &TelegramSender{}
+// NewTelegramSender 创建Telegram发送器实例，timeout 为单次发送超时（<= 0 时使用 DefaultSendTimeout）
+func NewTelegramSender(timeout time.Duration) *TelegramSender {
+	return &TelegramSender{timeout: sendTimeoutOrDefault(timeout)}
 }
 
 // TelegramMessage Telegram消息结构
@@ -23,8 +26,16 @@ type TelegramMessage struct {
 	ParseMode string `json:"parse_mode,omitempty"`
 }
 
-// SendMessage 发送Telegram消息
-func (s *TelegramSender) SendMessage(botToken, chatID, message string) error {
+// telegramSendResponse Telegram sendMessage 响应
+type telegramSendResponse struct {
+	OK     bool `json:"ok"`
+	Result struct {
+		MessageID int64 `json:"message_id"`
+	} `json:"result"`
+}
+
+// SendMessage 发送Telegram消息，返回 Telegram 的 message_id
+func (s *TelegramSender) SendMessage(ctx context.Context, botToken, chatID, message string) (string, error) {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken)
 
 	telegramMsg := TelegramMessage{
@@ -35,25 +46,24 @@ func (s *TelegramSender) SendMessage(botToken, chatID, message string) error {
 
 	jsonData, err := json.Marshal(telegramMsg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal telegram message: %w", err)
+		return "", fmt.Errorf("failed to marshal telegram message: %w", err)
 	}
 
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	// 发送请求
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	// 发送请求（超时由 context 控制）
+	statusCode, respBody, err := doJSON(ctx, s.timeout, http.MethodPost, url, nil, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to send telegram message: %w", err)
+		return "", fmt.Errorf("failed to send telegram message: %w", err)
 	}
-	defer resp.Body.Close()
 
 	// 检查响应状态码
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("telegram API returned status %d", statusCode)
 	}
 
-	return nil
+	// 消息已发送成功，解析失败时只是拿不到消息ID
+	var resp telegramSendResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil || resp.Result.MessageID == 0 {
+		return "", nil
+	}
+	return strconv.FormatInt(resp.Result.MessageID, 10), nil
 }
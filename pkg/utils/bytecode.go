@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	opPush1  = 0x60
+	opPush4  = 0x63
+	opPush32 = 0x7f
+)
+
+// EIP-1167 最小代理字节码的前后缀，中间为 20 字节实现合约地址
+var (
+	minimalProxyPrefix = common.FromHex("0x363d3d373d3d3d363d73")
+	minimalProxySuffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// ExtractBytecodeSelectors 扫描合约字节码，收集可能的函数选择器
+// Solidity/Vyper 的函数分发使用 PUSH4 <selector>；选择器高位为 0 时编译器会改用更短的 PUSH，
+// 因此 PUSH1~PUSH4 的立即数都左补零为 4 字节收集。结果是候选集合，只适合判断已知选择器是否存在
+func ExtractBytecodeSelectors(code []byte) map[[4]byte]struct{} {
+	selectors := make(map[[4]byte]struct{})
+	for pc := 0; pc < len(code); pc++ {
+		op := code[pc]
+		if op < opPush1 || op > opPush32 {
+			continue
+		}
+		size := int(op-opPush1) + 1
+		if pc+size >= len(code) {
+			break // 字节码末尾被截断的 PUSH（通常是元数据）
+		}
+		if op <= opPush4 {
+			var selector [4]byte
+			copy(selector[4-size:], code[pc+1:pc+1+size])
+			selectors[selector] = struct{}{}
+		}
+		pc += size // 跳过立即数，避免把数据误当作操作码
+	}
+	return selectors
+}
+
+// ParseMinimalProxy 判断字节码是否为 EIP-1167 最小代理，是则返回实现合约地址
+func ParseMinimalProxy(code []byte) (common.Address, bool) {
+	if len(code) != len(minimalProxyPrefix)+common.AddressLength+len(minimalProxySuffix) ||
+		!bytes.HasPrefix(code, minimalProxyPrefix) || !bytes.HasSuffix(code, minimalProxySuffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+common.AddressLength]), true
+}
+
+// HasBytecodeSelector 判断选择器是否在 ExtractBytecodeSelectors 的结果中
+func HasBytecodeSelector(selectors map[[4]byte]struct{}, selector []byte) bool {
+	if len(selector) != 4 {
+		return false
+	}
+	_, ok := selectors[[4]byte(selector)]
+	return ok
+}
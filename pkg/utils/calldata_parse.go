@@ -4,15 +4,24 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"timelocker-backend/internal/types"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrSelectorNotInABI = errors.New("function selector not found in ABI")
+	ErrFunctionNotInABI = errors.New("function signature not found in ABI")
+	ErrABIParseFailed   = errors.New("ABI parse failed")
 )
 
 // ParseCalldataNoSelector 解析calldata(不含函数选择器), 返回参数列表
@@ -79,6 +88,107 @@ func ParseCalldataNoSelector(functionSig string, calldata []byte) ([]types.Calld
 	return results, nil
 }
 
+// DecodeCalldataWithSelector 使用ABI解析带4字节选择器的calldata
+func DecodeCalldataWithSelector(abiContent string, calldata []byte) (*types.DecodedCalldata, error) {
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("calldata too short: need at least 4 bytes for function selector, got %d", len(calldata))
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiContent))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrABIParseFailed, err)
+	}
+
+	method, err := parsedABI.MethodById(calldata[:4])
+	if err != nil {
+		return nil, fmt.Errorf("%w: 0x%s", ErrSelectorNotInABI, hex.EncodeToString(calldata[:4]))
+	}
+
+	return decodeWithMethod(method, calldata[4:])
+}
+
+// ExtractABIFunctions 解析ABI中的所有函数，返回选择器与规范化签名（按签名排序，ABIID 由调用方填写）
+func ExtractABIFunctions(abiContent string) ([]types.ABIFunction, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(abiContent))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrABIParseFailed, err)
+	}
+
+	functions := make([]types.ABIFunction, 0, len(parsedABI.Methods))
+	for _, method := range parsedABI.Methods {
+		functions = append(functions, types.ABIFunction{
+			Selector:  "0x" + hex.EncodeToString(method.ID),
+			Signature: method.Sig,
+		})
+	}
+	sort.Slice(functions, func(i, j int) bool { return functions[i].Signature < functions[j].Signature })
+	return functions, nil
+}
+
+// DecodeCalldataWithSignature 按函数签名解析不含选择器的calldata（Compound风格）
+// abiContent 非空时从ABI中查找该签名以获得参数名，否则参数名为 param[i]
+func DecodeCalldataWithSignature(abiContent string, functionSig string, calldata []byte) (*types.DecodedCalldata, error) {
+	funcName, paramTypes, err := parseAndValidateFunctionSig(functionSig)
+	if err != nil {
+		return nil, err
+	}
+	normalizedSig := funcName + "(" + strings.Join(paramTypes, ",") + ")"
+
+	if abiContent == "" {
+		params, err := ParseCalldataNoSelector(normalizedSig, calldata)
+		if err != nil {
+			return nil, err
+		}
+		return &types.DecodedCalldata{
+			FunctionName:      funcName,
+			FunctionSignature: normalizedSig,
+			Selector:          "0x" + hex.EncodeToString(crypto.Keccak256([]byte(normalizedSig))[:4]),
+			Params:            params,
+		}, nil
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiContent))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrABIParseFailed, err)
+	}
+
+	for _, method := range parsedABI.Methods {
+		if method.Sig == normalizedSig {
+			m := method
+			return decodeWithMethod(&m, calldata)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFunctionNotInABI, normalizedSig)
+}
+
+// decodeWithMethod 按ABI方法解码参数数据（不含选择器）
+func decodeWithMethod(method *abi.Method, data []byte) (*types.DecodedCalldata, error) {
+	vals, err := method.Inputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("calldata decode failed: data format does not match function parameters (%w)", err)
+	}
+
+	params := make([]types.CalldataParam, len(vals))
+	for i, v := range vals {
+		name := method.Inputs[i].Name
+		if name == "" {
+			name = fmt.Sprintf("param[%d]", i)
+		}
+		params[i] = types.CalldataParam{
+			Name:  name,
+			Type:  method.Inputs[i].Type.String(),
+			Value: formatValue(v),
+		}
+	}
+
+	return &types.DecodedCalldata{
+		FunctionName:      method.RawName,
+		FunctionSignature: method.Sig,
+		Selector:          "0x" + hex.EncodeToString(method.ID),
+		Params:            params,
+	}, nil
+}
+
 // parseAndValidateFunctionSig 解析并验证函数签名
 func parseAndValidateFunctionSig(sig string) (string, []string, error) {
 	sig = strings.TrimSpace(sig)
@@ -372,3 +482,111 @@ func WeiToEth(weiStr string, nativeToken string) (string, error) {
 
 	return fmt.Sprintf("%s.%06d %s", ethInt.String(), remainder6.Int64(), nativeToken), nil
 }
+
+// DescribeCalldataWithSelector 在没有ABI的情况下描述带函数选择器的calldata：函数显示为选择器，参数以原始字节展示
+func DescribeCalldataWithSelector(calldata []byte) (string, []types.CalldataParam) {
+	if len(calldata) == 0 {
+		return "No Function Call", []types.CalldataParam{}
+	}
+	if len(calldata) < 4 {
+		return "Unknown", []types.CalldataParam{
+			{Name: "param[0]", Type: "bytes", Value: "0x" + hex.EncodeToString(calldata)},
+		}
+	}
+
+	params := []types.CalldataParam{}
+	if len(calldata) > 4 {
+		params = append(params, types.CalldataParam{Name: "param[0]", Type: "bytes", Value: "0x" + hex.EncodeToString(calldata[4:])})
+	}
+	return "0x" + hex.EncodeToString(calldata[:4]), params
+}
+
+// FillOpenzeppelinCallsNotificationData 根据 OpenZeppelin 操作的子调用填充通知中的调用信息
+// 单个调用直接展示；批量操作（多于一个子调用）汇总展示总金额，并在 Calls 中按顺序列出全部子调用
+// 没有子调用记录的历史数据退化为 flow 自身的单个调用
+func FillOpenzeppelinCallsNotificationData(data *types.NotificationData, flow *types.OpenzeppelinTimelockFlowDB, calls []types.OpenzeppelinFlowCallDB, nativeToken string) {
+	if len(calls) == 0 {
+		var target string
+		if flow.TargetAddress != nil {
+			target = *flow.TargetAddress
+		}
+		calls = []types.OpenzeppelinFlowCallDB{{TargetAddress: target, Value: flow.Value, CallData: flow.CallData}}
+	}
+
+	notificationCalls := buildNotificationCalls(calls, nativeToken)
+	if len(notificationCalls) == 1 {
+		call := notificationCalls[0]
+		data.Target = call.Target
+		if data.Target == "" {
+			data.Target = "Unknown"
+		}
+		data.Function = call.Function
+		data.Value = call.Value
+		data.CalldataParams = call.CalldataParams
+		return
+	}
+
+	total := new(big.Int)
+	for _, call := range calls {
+		if v, ok := new(big.Int).SetString(call.Value, 10); ok {
+			total.Add(total, v)
+		}
+	}
+	value, err := WeiToEth(total.String(), nativeToken)
+	if err != nil {
+		value = fmt.Sprintf("0 %s", nativeToken)
+	}
+
+	data.Target = fmt.Sprintf("Batch (%d calls)", len(notificationCalls))
+	data.Function = "scheduleBatch"
+	data.Value = value
+	data.CalldataParams = []types.CalldataParam{}
+	data.Calls = notificationCalls
+}
+
+// buildNotificationCalls 将 OpenZeppelin 操作的子调用转换为通知中的展示结构（按原顺序）
+func buildNotificationCalls(calls []types.OpenzeppelinFlowCallDB, nativeToken string) []types.NotificationCall {
+	result := make([]types.NotificationCall, len(calls))
+	for i, call := range calls {
+		value, err := WeiToEth(call.Value, nativeToken)
+		if err != nil {
+			value = fmt.Sprintf("0 %s", nativeToken)
+		}
+		function, params := DescribeCalldataWithSelector(call.CallData)
+		result[i] = types.NotificationCall{
+			Index:          call.CallIndex,
+			Target:         call.TargetAddress,
+			Value:          value,
+			Function:       function,
+			CalldataParams: params,
+		}
+	}
+	return result
+}
+
+// IsSelfTargeting 判断流程是否以 timelock 合约自身为目标（任一子调用的目标为合约地址即视为自我管理变更）
+func IsSelfTargeting(data *types.NotificationData) bool {
+	contract := strings.ToLower(strings.TrimSpace(data.Contract))
+	if !common.IsHexAddress(contract) {
+		return false
+	}
+	if strings.ToLower(strings.TrimSpace(data.Target)) == contract {
+		return true
+	}
+	for _, call := range data.Calls {
+		if strings.ToLower(strings.TrimSpace(call.Target)) == contract {
+			return true
+		}
+	}
+	return false
+}
+
+// FillNotificationSeverity 根据流程内容填充通知级别：以 timelock 自身为目标的流程为 critical
+func FillNotificationSeverity(data *types.NotificationData) {
+	data.SelfTargeting = IsSelfTargeting(data)
+	if data.SelfTargeting {
+		data.Severity = types.NotificationSeverityCritical
+		return
+	}
+	data.Severity = types.NotificationSeverityNormal
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"timelocker-backend/internal/types"
+)
+
+func TestIsSelfTargeting(t *testing.T) {
+	contract := testTarget.Hex()
+	other := testTarget2.Hex()
+
+	tests := []struct {
+		name string
+		data types.NotificationData
+		want bool
+	}{
+		{"target is contract", types.NotificationData{Contract: contract, Target: contract}, true},
+		{"case and whitespace ignored", types.NotificationData{Contract: strings.ToLower(contract), Target: " " + strings.ToUpper(contract) + " "}, true},
+		{"external target", types.NotificationData{Contract: contract, Target: other}, false},
+		{"batch with self call", types.NotificationData{Contract: contract, Target: "Batch (2 calls)", Calls: []types.NotificationCall{{Target: other}, {Target: contract}}}, true},
+		{"batch without self call", types.NotificationData{Contract: contract, Target: "Batch (2 calls)", Calls: []types.NotificationCall{{Target: other}, {Target: other}}}, false},
+		{"unknown target", types.NotificationData{Contract: contract, Target: "Unknown"}, false},
+		{"invalid contract never matches", types.NotificationData{Contract: "Unknown", Target: "Unknown"}, false},
+		{"empty contract never matches", types.NotificationData{Calls: []types.NotificationCall{{Target: ""}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if got := IsSelfTargeting(&data); got != tt.want {
+				t.Fatalf("IsSelfTargeting = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillNotificationSeverity(t *testing.T) {
+	data := &types.NotificationData{Contract: testTarget.Hex(), Target: testTarget.Hex()}
+	FillNotificationSeverity(data)
+	if !data.SelfTargeting || data.Severity != types.NotificationSeverityCritical {
+		t.Fatalf("self-targeting flow: SelfTargeting=%v Severity=%q, want true/%q", data.SelfTargeting, data.Severity, types.NotificationSeverityCritical)
+	}
+
+	// 数据复用时重新计算，不保留上一次的 critical
+	data.Target = testTarget2.Hex()
+	FillNotificationSeverity(data)
+	if data.SelfTargeting || data.Severity != types.NotificationSeverityNormal {
+		t.Fatalf("external flow: SelfTargeting=%v Severity=%q, want false/%q", data.SelfTargeting, data.Severity, types.NotificationSeverityNormal)
+	}
+}
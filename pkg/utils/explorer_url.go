@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"timelocker-backend/internal/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// explorerBaseURL 取第一个有效的区块浏览器地址（去掉末尾的 /），未配置时返回空字符串
+func explorerBaseURL(explorerURLs []string) string {
+	for _, raw := range explorerURLs {
+		if base := strings.TrimRight(strings.TrimSpace(raw), "/"); base != "" {
+			return base
+		}
+	}
+	return ""
+}
+
+// ExplorerTxURL 构建交易的区块浏览器链接，未配置浏览器或哈希为空时返回空字符串
+func ExplorerTxURL(explorerURLs []string, txHash string) string {
+	base := explorerBaseURL(explorerURLs)
+	if base == "" || txHash == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/tx/%s", base, txHash)
+}
+
+// ExplorerAddressURL 构建地址的区块浏览器链接，未配置浏览器或地址非法（如 "Unknown"）时返回空字符串
+func ExplorerAddressURL(explorerURLs []string, address string) string {
+	base := explorerBaseURL(explorerURLs)
+	if base == "" || !common.IsHexAddress(address) {
+		return ""
+	}
+	return fmt.Sprintf("%s/address/%s", base, address)
+}
+
+// FillExplorerLinks 填充通知数据中合约、目标地址及批量子调用目标的区块浏览器链接
+func FillExplorerLinks(data *types.NotificationData, explorerURLs []string) {
+	data.ContractUrl = ExplorerAddressURL(explorerURLs, data.Contract)
+	data.TargetUrl = ExplorerAddressURL(explorerURLs, data.Target)
+	for i := range data.Calls {
+		data.Calls[i].TargetUrl = ExplorerAddressURL(explorerURLs, data.Calls[i].Target)
+	}
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"testing"
+
+	"timelocker-backend/internal/types"
+)
+
+const (
+	testExplorerTx      = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	testExplorerAddress = "0xc00e94Cb662C3520282E6f5717214004A7f26888"
+	testExplorerTarget  = "0x6d903f6003cca6255D85CcA4D3B5E5146dC33925"
+)
+
+func TestExplorerTxURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		urls   []string
+		txHash string
+		want   string
+	}{
+		{"first explorer used", []string{"https://etherscan.io", "https://other.io"}, testExplorerTx, "https://etherscan.io/tx/" + testExplorerTx},
+		{"trailing slash trimmed", []string{"https://etherscan.io/"}, testExplorerTx, "https://etherscan.io/tx/" + testExplorerTx},
+		{"blank entries skipped", []string{"", "  ", " https://bscscan.com// "}, testExplorerTx, "https://bscscan.com/tx/" + testExplorerTx},
+		{"no explorer configured", nil, testExplorerTx, ""},
+		{"only blank explorers", []string{"", "/"}, testExplorerTx, ""},
+		{"empty hash", []string{"https://etherscan.io"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExplorerTxURL(tt.urls, tt.txHash); got != tt.want {
+				t.Fatalf("ExplorerTxURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplorerAddressURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    []string
+		address string
+		want    string
+	}{
+		{"valid address", []string{"https://etherscan.io/"}, testExplorerAddress, "https://etherscan.io/address/" + testExplorerAddress},
+		{"unknown placeholder", []string{"https://etherscan.io"}, "Unknown", ""},
+		{"empty address", []string{"https://etherscan.io"}, "", ""},
+		{"no explorer configured", nil, testExplorerAddress, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExplorerAddressURL(tt.urls, tt.address); got != tt.want {
+				t.Fatalf("ExplorerAddressURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillExplorerLinks(t *testing.T) {
+	data := &types.NotificationData{
+		Contract: testExplorerAddress,
+		Target:   "Unknown",
+		Calls: []types.NotificationCall{
+			{Index: 0, Target: testExplorerTarget},
+			{Index: 1, Target: ""},
+		},
+	}
+	FillExplorerLinks(data, []string{"https://etherscan.io/"})
+
+	if want := "https://etherscan.io/address/" + testExplorerAddress; data.ContractUrl != want {
+		t.Fatalf("ContractUrl = %q, want %q", data.ContractUrl, want)
+	}
+	if data.TargetUrl != "" {
+		t.Fatalf("TargetUrl = %q, want empty for an invalid target", data.TargetUrl)
+	}
+	if want := "https://etherscan.io/address/" + testExplorerTarget; data.Calls[0].TargetUrl != want {
+		t.Fatalf("Calls[0].TargetUrl = %q, want %q", data.Calls[0].TargetUrl, want)
+	}
+	if data.Calls[1].TargetUrl != "" {
+		t.Fatalf("Calls[1].TargetUrl = %q, want empty", data.Calls[1].TargetUrl)
+	}
+
+	// 未配置浏览器时清空已有链接
+	FillExplorerLinks(data, nil)
+	if data.ContractUrl != "" || data.Calls[0].TargetUrl != "" {
+		t.Fatalf("links not cleared without an explorer: %+v", data)
+	}
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"timelocker-backend/internal/types"
@@ -14,18 +16,95 @@ type JWTManager struct {
 	secret        []byte
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	keyID         string            // 当前签名密钥的 kid，为空时签发的令牌不带 kid 头
+	keys          map[string][]byte // kid -> 验证密钥（含当前签名密钥与轮换下来仍在有效期内的旧密钥）
+	issuer        string
+	audience      string
 }
 
-func NewJWTManager(secret string, accessExpiry, refreshExpiry time.Duration) *JWTManager {
+// JWTOptions JWT 签发与校验的可选配置
+type JWTOptions struct {
+	KeyID            string            // 当前签名密钥的 kid
+	VerificationKeys map[string]string // 轮换下来的旧密钥（kid -> secret），其签发的令牌在过期前仍然有效
+	Issuer           string            // 非空时签发 iss 声明并在校验时要求一致
+	Audience         string            // 非空时签发 aud 声明并在校验时要求包含
+}
+
+func NewJWTManager(secret string, accessExpiry, refreshExpiry time.Duration, opts JWTOptions) *JWTManager {
+	keys := make(map[string][]byte, len(opts.VerificationKeys)+1)
+	for kid, key := range opts.VerificationKeys {
+		keys[kid] = []byte(key)
+	}
+	if opts.KeyID != "" {
+		keys[opts.KeyID] = []byte(secret)
+	}
 	return &JWTManager{
 		secret:        []byte(secret),
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
+		keyID:         opts.KeyID,
+		keys:          keys,
+		issuer:        opts.Issuer,
+		audience:      opts.Audience,
+	}
+}
+
+// ParseJWTVerificationKeys 解析 "kid:secret" 格式的旧密钥列表
+func ParseJWTVerificationKeys(entries []string) (map[string]string, error) {
+	keys := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(entry, ":")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("invalid jwt verification key %q, expected kid:secret", kid)
+		}
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("duplicate jwt verification key id %q", kid)
+		}
+		keys[kid] = secret
+	}
+	return keys, nil
+}
+
+// signToken 使用当前密钥签名，配置了 kid/iss/aud 时一并写入
+func (j *JWTManager) signToken(claims jwt.MapClaims) (string, error) {
+	if j.issuer != "" {
+		claims["iss"] = j.issuer
+	}
+	if j.audience != "" {
+		claims["aud"] = j.audience
 	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if j.keyID != "" {
+		token.Header["kid"] = j.keyID
+	}
+	return token.SignedString(j.secret)
+}
+
+// verificationKey 按令牌头中的 kid 选择验证密钥；无 kid 的令牌（引入 kid 之前签发）使用当前密钥
+func (j *JWTManager) verificationKey(token *jwt.Token) ([]byte, error) {
+	kidValue, present := token.Header["kid"]
+	if !present {
+		return j.secret, nil
+	}
+	kid, ok := kidValue.(string)
+	if !ok {
+		return nil, errors.New("invalid kid header")
+	}
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key id: %s", kid)
+	}
+	return key, nil
 }
 
 // GenerateTokens 生成访问令牌和刷新令牌
-func (j *JWTManager) GenerateTokens(userID int64, walletAddress string) (string, string, time.Time, error) {
+// authTime 为最近一次钱包签名登录的时间，写入 auth_time 声明，刷新令牌时沿用；为零值时不写入
+func (j *JWTManager) GenerateTokens(userID int64, walletAddress string, authTime time.Time) (string, string, time.Time, error) {
 	// 生成访问令牌
 	accessClaims := jwt.MapClaims{
 		"user_id":        userID,
@@ -34,9 +113,11 @@ func (j *JWTManager) GenerateTokens(userID int64, walletAddress string) (string,
 		"exp":            time.Now().Add(j.accessExpiry).Unix(),
 		"iat":            time.Now().Unix(),
 	}
+	if !authTime.IsZero() {
+		accessClaims["auth_time"] = authTime.Unix()
+	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(j.secret)
+	accessTokenString, err := j.signToken(accessClaims)
 	if err != nil {
 		logger.Error("GenerateTokens Error: ", errors.New("failed to generate access token"), "error: ", err)
 		return "", "", time.Time{}, err
@@ -50,9 +131,11 @@ func (j *JWTManager) GenerateTokens(userID int64, walletAddress string) (string,
 		"exp":            time.Now().Add(j.refreshExpiry).Unix(),
 		"iat":            time.Now().Unix(),
 	}
+	if !authTime.IsZero() {
+		refreshClaims["auth_time"] = authTime.Unix()
+	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString(j.secret)
+	refreshTokenString, err := j.signToken(refreshClaims)
 	if err != nil {
 		logger.Error("GenerateTokens Error: ", errors.New("failed to generate refresh token"), "error: ", err)
 		return "", "", time.Time{}, err
@@ -76,15 +159,23 @@ func (j *JWTManager) VerifyRefreshToken(tokenString string) (*types.JWTClaims, e
 	return j.verifyToken(tokenString, "refresh")
 }
 
-// verifyToken 验证令牌
+// verifyToken 验证令牌（配置了 issuer/audience 时同时校验 iss/aud 声明）
 func (j *JWTManager) verifyToken(tokenString, expectedType string) (*types.JWTClaims, error) {
+	var parserOpts []jwt.ParserOption
+	if j.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(j.issuer))
+	}
+	if j.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(j.audience))
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			logger.Error("verifyToken Error: ", errors.New("unexpected signing method"))
 			return nil, errors.New("unexpected signing method")
 		}
-		return j.secret, nil
-	})
+		return j.verificationKey(token)
+	}, parserOpts...)
 
 	if err != nil {
 		logger.Error("verifyToken Error: ", errors.New("failed to parse token"), "error: ", err)
@@ -120,10 +211,17 @@ func (j *JWTManager) verifyToken(tokenString, expectedType string) (*types.JWTCl
 		return nil, errors.New("invalid wallet_address in token")
 	}
 
+	// auth_time 为可选声明，引入之前签发的令牌没有该字段
+	var authTime int64
+	if v, ok := claims["auth_time"].(float64); ok {
+		authTime = int64(v)
+	}
+
 	logger.Info("verifyToken Success: ", "token verified successfully", "user_id", userID, "wallet_address", walletAddress, "token_type", tokenType)
 	return &types.JWTClaims{
 		UserID:        int64(userID),
 		WalletAddress: walletAddress,
 		Type:          tokenType,
+		AuthTime:      authTime,
 	}, nil
 }
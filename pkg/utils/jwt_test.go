@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTManagerRoundTrip(t *testing.T) {
+	m := NewJWTManager("current-secret", time.Hour, 24*time.Hour, JWTOptions{KeyID: "k2", Issuer: "timelocker", Audience: "timelocker-web"})
+	authTime := time.Unix(1700000000, 0)
+
+	access, refresh, _, err := m.GenerateTokens(42, "0xabc", authTime)
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+
+	claims, err := m.VerifyAccessToken(access)
+	if err != nil {
+		t.Fatalf("VerifyAccessToken: %v", err)
+	}
+	if claims.UserID != 42 || claims.WalletAddress != "0xabc" || claims.Type != "access" || claims.AuthTime != authTime.Unix() {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	if _, err := m.VerifyRefreshToken(refresh); err != nil {
+		t.Fatalf("VerifyRefreshToken: %v", err)
+	}
+
+	// 令牌类型不能混用
+	if _, err := m.VerifyRefreshToken(access); err == nil {
+		t.Fatal("access token accepted as refresh token")
+	}
+	if _, err := m.VerifyAccessToken(refresh); err == nil {
+		t.Fatal("refresh token accepted as access token")
+	}
+}
+
+func TestJWTManagerKeyRotation(t *testing.T) {
+	oldManager := NewJWTManager("old-secret", time.Hour, time.Hour, JWTOptions{KeyID: "k1"})
+	oldToken, _, _, err := oldManager.GenerateTokens(1, "0xabc", time.Time{})
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+	legacyManager := NewJWTManager("old-secret", time.Hour, time.Hour, JWTOptions{})
+	legacyToken, _, _, err := legacyManager.GenerateTokens(1, "0xabc", time.Time{})
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		manager *JWTManager
+		token   string
+		wantErr bool
+	}{
+		{
+			name:    "rotated-out kid still verifies",
+			manager: NewJWTManager("new-secret", time.Hour, time.Hour, JWTOptions{KeyID: "k2", VerificationKeys: map[string]string{"k1": "old-secret"}}),
+			token:   oldToken,
+		},
+		{
+			name:    "unknown kid rejected",
+			manager: NewJWTManager("new-secret", time.Hour, time.Hour, JWTOptions{KeyID: "k2"}),
+			token:   oldToken,
+			wantErr: true,
+		},
+		{
+			name:    "known kid with wrong key rejected",
+			manager: NewJWTManager("new-secret", time.Hour, time.Hour, JWTOptions{KeyID: "k2", VerificationKeys: map[string]string{"k1": "other-secret"}}),
+			token:   oldToken,
+			wantErr: true,
+		},
+		{
+			name:    "token without kid uses current secret",
+			manager: NewJWTManager("old-secret", time.Hour, time.Hour, JWTOptions{KeyID: "k2"}),
+			token:   legacyToken,
+		},
+		{
+			name:    "token without kid does not fall back to old keys",
+			manager: NewJWTManager("new-secret", time.Hour, time.Hour, JWTOptions{KeyID: "k2", VerificationKeys: map[string]string{"k1": "old-secret"}}),
+			token:   legacyToken,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.manager.VerifyAccessToken(tt.token)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("VerifyAccessToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTManagerIssuerAudience(t *testing.T) {
+	sign := func(opts JWTOptions) string {
+		token, _, _, err := NewJWTManager("secret", time.Hour, time.Hour, opts).GenerateTokens(1, "0xabc", time.Time{})
+		if err != nil {
+			t.Fatalf("GenerateTokens: %v", err)
+		}
+		return token
+	}
+
+	verifier := NewJWTManager("secret", time.Hour, time.Hour, JWTOptions{Issuer: "timelocker", Audience: "timelocker-web"})
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"matching iss and aud", sign(JWTOptions{Issuer: "timelocker", Audience: "timelocker-web"}), false},
+		{"iss mismatch", sign(JWTOptions{Issuer: "other", Audience: "timelocker-web"}), true},
+		{"aud mismatch", sign(JWTOptions{Issuer: "timelocker", Audience: "other-web"}), true},
+		{"missing iss", sign(JWTOptions{Audience: "timelocker-web"}), true},
+		{"missing aud", sign(JWTOptions{Issuer: "timelocker"}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.VerifyAccessToken(tt.token)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("VerifyAccessToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTManagerRejectsExpiredAndForeignAlg(t *testing.T) {
+	expired := NewJWTManager("secret", -time.Minute, time.Hour, JWTOptions{})
+	token, _, _, err := expired.GenerateTokens(1, "0xabc", time.Time{})
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+	if _, err := expired.VerifyAccessToken(token); err == nil {
+		t.Fatal("expired token accepted")
+	}
+
+	// alg=none 的令牌必须被拒绝
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"user_id":        1,
+		"wallet_address": "0xabc",
+		"type":           "access",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	})
+	noneToken, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token: %v", err)
+	}
+	if _, err := expired.VerifyAccessToken(noneToken); err == nil {
+		t.Fatal("alg=none token accepted")
+	}
+}
+
+func TestParseJWTVerificationKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"empty", nil, map[string]string{}, false},
+		{"single", []string{"k1:secret"}, map[string]string{"k1": "secret"}, false},
+		{"trims and skips blanks", []string{" k1 :secret", "", "  "}, map[string]string{"k1": "secret"}, false},
+		{"secret may contain colon", []string{"k1:a:b"}, map[string]string{"k1": "a:b"}, false},
+		{"missing separator", []string{"k1secret"}, nil, true},
+		{"missing kid", []string{":secret"}, nil, true},
+		{"missing secret", []string{"k1:"}, nil, true},
+		{"duplicate kid", []string{"k1:a", "k1:b"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJWTVerificationKeys(tt.entries)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for kid, secret := range tt.want {
+				if got[kid] != secret {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
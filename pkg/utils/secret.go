@@ -0,0 +1,14 @@
+package utils
+
+import "strings"
+
+// MaskSecret 脱敏显示密钥：保留前后各 4 位，过短时全部隐藏，空字符串原样返回
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", len(secret)-8) + secret[len(secret)-4:]
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	abiAddress, _      = abi.NewType("address", "", nil)
+	abiUint256, _      = abi.NewType("uint256", "", nil)
+	abiString, _       = abi.NewType("string", "", nil)
+	abiBytes, _        = abi.NewType("bytes", "", nil)
+	abiBytes32, _      = abi.NewType("bytes32", "", nil)
+	abiAddressArray, _ = abi.NewType("address[]", "", nil)
+	abiUint256Array, _ = abi.NewType("uint256[]", "", nil)
+	abiBytesArray, _   = abi.NewType("bytes[]", "", nil)
+)
+
+// ComputeCompoundTxHash 计算 Compound Timelock 的 txHash
+// 与合约一致：keccak256(abi.encode(target, value, signature, data, eta))
+func ComputeCompoundTxHash(target common.Address, value *big.Int, signature string, data []byte, eta *big.Int) (common.Hash, error) {
+	args := abi.Arguments{{Type: abiAddress}, {Type: abiUint256}, {Type: abiString}, {Type: abiBytes}, {Type: abiUint256}}
+	encoded, err := args.Pack(target, nonNilBig(value), signature, data, nonNilBig(eta))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode compound tx: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// ComputeOzOperationId 计算 OpenZeppelin TimelockController 单笔操作的 id
+// 与合约 hashOperation 一致：keccak256(abi.encode(target, value, data, predecessor, salt))
+func ComputeOzOperationId(target common.Address, value *big.Int, data []byte, predecessor [32]byte, salt [32]byte) (common.Hash, error) {
+	args := abi.Arguments{{Type: abiAddress}, {Type: abiUint256}, {Type: abiBytes}, {Type: abiBytes32}, {Type: abiBytes32}}
+	encoded, err := args.Pack(target, nonNilBig(value), data, predecessor, salt)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode oz operation: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// ComputeOzOperationBatchId 计算 OpenZeppelin TimelockController 批量操作的 id
+// 与合约 hashOperationBatch 一致：keccak256(abi.encode(targets, values, payloads, predecessor, salt))
+func ComputeOzOperationBatchId(targets []common.Address, values []*big.Int, payloads [][]byte, predecessor [32]byte, salt [32]byte) (common.Hash, error) {
+	if len(targets) != len(values) || len(targets) != len(payloads) {
+		return common.Hash{}, fmt.Errorf("batch length mismatch: targets=%d values=%d payloads=%d", len(targets), len(values), len(payloads))
+	}
+	normalizedValues := make([]*big.Int, len(values))
+	for i, v := range values {
+		normalizedValues[i] = nonNilBig(v)
+	}
+
+	args := abi.Arguments{{Type: abiAddressArray}, {Type: abiUint256Array}, {Type: abiBytesArray}, {Type: abiBytes32}, {Type: abiBytes32}}
+	encoded, err := args.Pack(targets, normalizedValues, payloads, predecessor, salt)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode oz operation batch: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+func nonNilBig(v *big.Int) *big.Int {
+	if v == nil {
+		return big.NewInt(0)
+	}
+	return v
+}
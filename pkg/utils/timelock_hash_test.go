@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 测试向量按 Solidity abi.encode 规则逐个 32 字节字手工展开，不经过 abi.Pack，
+// 期望值即合约中 keccak256(abi.encode(...)) 的结果
+
+var (
+	testTarget   = common.HexToAddress("0xc00e94Cb662C3520282E6f5717214004A7f26888")
+	testTarget2  = common.HexToAddress("0x6d903f6003cca6255D85CcA4D3B5E5146dC33925")
+	testSalt     = common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")
+	testPrevious = common.HexToHash("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+)
+
+// abiWords 拼接 32 字节字（每个参数为 64 位十六进制，不带 0x）并计算 keccak256
+func abiWords(t *testing.T, words ...string) common.Hash {
+	t.Helper()
+	for i, w := range words {
+		if len(w) != 64 {
+			t.Fatalf("word %d has length %d, want 64", i, len(w))
+		}
+	}
+	return crypto.Keccak256Hash(hexutil.MustDecode("0x" + strings.Join(words, "")))
+}
+
+func TestComputeCompoundTxHash(t *testing.T) {
+	// _setPendingAdmin(address)，data 为 abi.encode(testTarget2)，value 0，eta 1700000000
+	data := common.LeftPadBytes(testTarget2.Bytes(), 32)
+	want := abiWords(t,
+		"000000000000000000000000c00e94cb662c3520282e6f5717214004a7f26888", // target
+		"0000000000000000000000000000000000000000000000000000000000000000", // value
+		"00000000000000000000000000000000000000000000000000000000000000a0", // signature 偏移
+		"00000000000000000000000000000000000000000000000000000000000000e0", // data 偏移
+		"000000000000000000000000000000000000000000000000000000006553f100", // eta
+		"0000000000000000000000000000000000000000000000000000000000000019", // signature 长度 25
+		"5f73657450656e64696e6741646d696e28616464726573732900000000000000", // "_setPendingAdmin(address)"
+		"0000000000000000000000000000000000000000000000000000000000000020", // data 长度 32
+		"0000000000000000000000006d903f6003cca6255d85cca4d3b5e5146dc33925", // data
+	)
+
+	tests := []struct {
+		name  string
+		value *big.Int
+	}{
+		{"zero value", big.NewInt(0)},
+		{"nil value treated as zero", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeCompoundTxHash(testTarget, tt.value, "_setPendingAdmin(address)", data, big.NewInt(1700000000))
+			if err != nil {
+				t.Fatalf("ComputeCompoundTxHash: %v", err)
+			}
+			if got != want {
+				t.Fatalf("ComputeCompoundTxHash = %s, want %s", got.Hex(), want.Hex())
+			}
+		})
+	}
+
+	// 空 signature（data 已含 selector）与 1 ether 的 value
+	wantRaw := abiWords(t,
+		"000000000000000000000000c00e94cb662c3520282e6f5717214004a7f26888", // target
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000", // value 1e18
+		"00000000000000000000000000000000000000000000000000000000000000a0", // signature 偏移
+		"00000000000000000000000000000000000000000000000000000000000000c0", // data 偏移
+		"0000000000000000000000000000000000000000000000000000000000000001", // eta
+		"0000000000000000000000000000000000000000000000000000000000000000", // signature 长度 0
+		"0000000000000000000000000000000000000000000000000000000000000004", // data 长度 4
+		"8129fc1c00000000000000000000000000000000000000000000000000000000", // initialize()
+	)
+	got, err := ComputeCompoundTxHash(testTarget, big.NewInt(1e18), "", hexutil.MustDecode("0x8129fc1c"), big.NewInt(1))
+	if err != nil {
+		t.Fatalf("ComputeCompoundTxHash: %v", err)
+	}
+	if got != wantRaw {
+		t.Fatalf("ComputeCompoundTxHash = %s, want %s", got.Hex(), wantRaw.Hex())
+	}
+}
+
+func TestComputeOzOperationId(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       *big.Int
+		data        []byte
+		predecessor common.Hash
+		want        common.Hash
+	}{
+		{
+			name:  "selector only, no predecessor",
+			value: big.NewInt(0),
+			data:  hexutil.MustDecode("0x8129fc1c"),
+			want: abiWords(t,
+				"000000000000000000000000c00e94cb662c3520282e6f5717214004a7f26888", // target
+				"0000000000000000000000000000000000000000000000000000000000000000", // value
+				"00000000000000000000000000000000000000000000000000000000000000a0", // data 偏移
+				"0000000000000000000000000000000000000000000000000000000000000000", // predecessor
+				"0000000000000000000000000000000000000000000000000000000000000001", // salt
+				"0000000000000000000000000000000000000000000000000000000000000004", // data 长度
+				"8129fc1c00000000000000000000000000000000000000000000000000000000", // data
+			),
+		},
+		{
+			name:        "empty data with predecessor and value",
+			value:       big.NewInt(1e18),
+			data:        nil,
+			predecessor: testPrevious,
+			want: abiWords(t,
+				"000000000000000000000000c00e94cb662c3520282e6f5717214004a7f26888", // target
+				"0000000000000000000000000000000000000000000000000de0b6b3a7640000", // value 1e18
+				"00000000000000000000000000000000000000000000000000000000000000a0", // data 偏移
+				"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", // predecessor
+				"0000000000000000000000000000000000000000000000000000000000000001", // salt
+				"0000000000000000000000000000000000000000000000000000000000000000", // data 长度 0
+			),
+		},
+		{
+			name:  "nil value treated as zero",
+			value: nil,
+			data:  hexutil.MustDecode("0x8129fc1c"),
+			want: abiWords(t,
+				"000000000000000000000000c00e94cb662c3520282e6f5717214004a7f26888",
+				"0000000000000000000000000000000000000000000000000000000000000000",
+				"00000000000000000000000000000000000000000000000000000000000000a0",
+				"0000000000000000000000000000000000000000000000000000000000000000",
+				"0000000000000000000000000000000000000000000000000000000000000001",
+				"0000000000000000000000000000000000000000000000000000000000000004",
+				"8129fc1c00000000000000000000000000000000000000000000000000000000",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeOzOperationId(testTarget, tt.value, tt.data, tt.predecessor, testSalt)
+			if err != nil {
+				t.Fatalf("ComputeOzOperationId: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ComputeOzOperationId = %s, want %s", got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}
+
+func TestComputeOzOperationBatchId(t *testing.T) {
+	want := abiWords(t,
+		"00000000000000000000000000000000000000000000000000000000000000a0", // targets 偏移
+		"0000000000000000000000000000000000000000000000000000000000000100", // values 偏移
+		"0000000000000000000000000000000000000000000000000000000000000160", // payloads 偏移
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", // predecessor
+		"0000000000000000000000000000000000000000000000000000000000000001", // salt
+		"0000000000000000000000000000000000000000000000000000000000000002", // targets 长度
+		"000000000000000000000000c00e94cb662c3520282e6f5717214004a7f26888",
+		"0000000000000000000000006d903f6003cca6255d85cca4d3b5e5146dc33925",
+		"0000000000000000000000000000000000000000000000000000000000000002", // values 长度
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		"0000000000000000000000000000000000000000000000000000000000000002", // payloads 长度
+		"0000000000000000000000000000000000000000000000000000000000000040", // payloads[0] 偏移
+		"0000000000000000000000000000000000000000000000000000000000000080", // payloads[1] 偏移
+		"0000000000000000000000000000000000000000000000000000000000000004", // payloads[0] 长度
+		"8129fc1c00000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000", // payloads[1] 长度 0
+	)
+
+	targets := []common.Address{testTarget, testTarget2}
+	payloads := [][]byte{hexutil.MustDecode("0x8129fc1c"), {}}
+	got, err := ComputeOzOperationBatchId(targets, []*big.Int{nil, big.NewInt(1e18)}, payloads, testPrevious, testSalt)
+	if err != nil {
+		t.Fatalf("ComputeOzOperationBatchId: %v", err)
+	}
+	if got != want {
+		t.Fatalf("ComputeOzOperationBatchId = %s, want %s", got.Hex(), want.Hex())
+	}
+
+	// 单元素批量与单笔操作的 id 不同（编码结构不同）
+	single, err := ComputeOzOperationId(testTarget, big.NewInt(0), payloads[0], testPrevious, testSalt)
+	if err != nil {
+		t.Fatalf("ComputeOzOperationId: %v", err)
+	}
+	batch, err := ComputeOzOperationBatchId(targets[:1], []*big.Int{big.NewInt(0)}, payloads[:1], testPrevious, testSalt)
+	if err != nil {
+		t.Fatalf("ComputeOzOperationBatchId: %v", err)
+	}
+	if single == batch {
+		t.Fatal("single-call batch id equals operation id")
+	}
+
+	if _, err := ComputeOzOperationBatchId(targets, []*big.Int{big.NewInt(0)}, payloads, testPrevious, testSalt); err == nil {
+		t.Fatal("expected error for length mismatch")
+	}
+}
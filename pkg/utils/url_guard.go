@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrUnsafeURL URL 不合法或指向内网/本机地址（SSRF 防护）
+var ErrUnsafeURL = errors.New("unsafe url")
+
+// cgnatRange 运营商级 NAT 地址段（100.64.0.0/10），net.IP.IsPrivate 不包含
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// outboundAllowlist 允许访问的内网主机名/地址段（自建 Matrix、webhook 网关等），由 SetOutboundAllowlist 在启动时设置
+var outboundAllowlist struct {
+	sync.RWMutex
+	hosts map[string]bool
+	nets  []*net.IPNet
+}
+
+// SetOutboundAllowlist 设置对外请求的白名单，条目可以是主机名（精确匹配，不区分大小写）、IP 或 CIDR；
+// 白名单内的目标跳过内网地址拦截
+func SetOutboundAllowlist(entries []string) error {
+	hosts := make(map[string]bool)
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return fmt.Errorf("invalid allowlist cidr %q: %w", entry, err)
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		hosts[entry] = true
+	}
+
+	outboundAllowlist.Lock()
+	outboundAllowlist.hosts = hosts
+	outboundAllowlist.nets = nets
+	outboundAllowlist.Unlock()
+	return nil
+}
+
+// isAllowlistedHost 主机名是否在白名单内
+func isAllowlistedHost(host string) bool {
+	outboundAllowlist.RLock()
+	defer outboundAllowlist.RUnlock()
+	return outboundAllowlist.hosts[strings.ToLower(host)]
+}
+
+// isAllowlistedIP IP 是否在白名单地址段内
+func isAllowlistedIP(ip net.IP) bool {
+	outboundAllowlist.RLock()
+	defer outboundAllowlist.RUnlock()
+	for _, n := range outboundAllowlist.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDisallowedIP 属于禁止地址段且不在白名单内
+func isDisallowedIP(ip net.IP) bool {
+	return IsBlockedIP(ip) && !isAllowlistedIP(ip)
+}
+
+// IsBlockedIP 判断 IP 是否属于禁止对外请求访问的地址段（回环、私有、链路本地、CGNAT、未指定、组播）
+func IsBlockedIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		cgnatRange.Contains(ip)
+}
+
+// ValidateOutboundURL 校验用户提供的对外请求 URL：仅允许 http/https，解析主机名并拒绝指向内网/本机的地址（白名单除外）
+func ValidateOutboundURL(ctx context.Context, rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsafeURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: scheme must be http or https", ErrUnsafeURL)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("%w: missing host", ErrUnsafeURL)
+	}
+	if isAllowlistedHost(host) {
+		return u, nil
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return nil, fmt.Errorf("%w: host %s is not allowed", ErrUnsafeURL, host)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isDisallowedIP(ip) {
+			return nil, fmt.Errorf("%w: address %s is not allowed", ErrUnsafeURL, ip)
+		}
+		return u, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to resolve host %s: %v", ErrUnsafeURL, host, err)
+	}
+	for _, addr := range addrs {
+		if isDisallowedIP(addr.IP) {
+			return nil, fmt.Errorf("%w: host %s resolves to disallowed address %s", ErrUnsafeURL, host, addr.IP)
+		}
+	}
+	return u, nil
+}
+
+// NewSafeHTTPClient 创建在建立连接时校验目标 IP 的 HTTP 客户端，可防止 DNS 重绑定与重定向到内网地址；
+// timeout <= 0 时不设置整体超时，由请求的 context 控制
+func NewSafeHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	guarded := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrUnsafeURL, err)
+			}
+			if ip := net.ParseIP(host); ip == nil || isDisallowedIP(ip) {
+				return fmt.Errorf("%w: address %s is not allowed", ErrUnsafeURL, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // 走代理时校验的是代理地址，无法保证最终目标安全
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && isAllowlistedHost(host) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+	client := &http.Client{Transport: transport}
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+	return client
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"127.8.9.10", true},
+		{"::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"10.0.0.1", true},
+		{"172.16.5.4", true},
+		{"172.31.255.255", true},
+		{"192.168.1.1", true},
+		{"::ffff:192.168.1.1", true},
+		{"fd00::1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"224.0.0.1", true},
+		{"ff02::1", true},
+		{"8.8.8.8", false},
+		{"172.32.0.1", false},
+		{"100.128.0.1", false},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("invalid test ip %s", tt.ip)
+			}
+			if got := IsBlockedIP(ip); got != tt.blocked {
+				t.Fatalf("IsBlockedIP(%s) = %v, want %v", tt.ip, got, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestValidateOutboundURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"public ipv4", "https://8.8.8.8/hook", false},
+		{"public ipv6", "http://[2001:4860:4860::8888]:8080/hook", false},
+		{"loopback", "http://127.0.0.1/hook", true},
+		{"loopback with port", "http://127.0.0.1:6379", true},
+		{"ipv6 loopback", "http://[::1]/", true},
+		{"ipv4-mapped loopback", "http://[::ffff:127.0.0.1]/", true},
+		{"rfc1918 10/8", "http://10.0.0.5/", true},
+		{"rfc1918 172.16/12", "http://172.20.1.1/", true},
+		{"rfc1918 192.168/16", "http://192.168.0.10/", true},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data/", true},
+		{"cgnat", "http://100.64.12.7/", true},
+		{"unspecified", "http://0.0.0.0/", true},
+		{"localhost", "http://localhost:8080/", true},
+		{"localhost subdomain", "http://api.localhost/", true},
+		{"unsupported scheme", "ftp://8.8.8.8/file", true},
+		{"file scheme", "file:///etc/passwd", true},
+		{"missing host", "http:///hook", true},
+		{"malformed", "http://[::1/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateOutboundURL(context.Background(), tt.url)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsafeURL) {
+					t.Fatalf("ValidateOutboundURL(%q) error = %v, want ErrUnsafeURL", tt.url, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateOutboundURL(%q) unexpected error: %v", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestValidateOutboundURLAllowlist(t *testing.T) {
+	if err := SetOutboundAllowlist([]string{"10.1.0.0/16", "192.168.1.5", "Matrix.Internal", " "}); err != nil {
+		t.Fatalf("SetOutboundAllowlist: %v", err)
+	}
+	t.Cleanup(func() { _ = SetOutboundAllowlist(nil) })
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"cidr entry", "http://10.1.2.3/hook", false},
+		{"outside cidr", "http://10.2.0.1/hook", true},
+		{"single ip entry", "http://192.168.1.5:8008/", false},
+		{"neighbour of single ip", "http://192.168.1.6/", true},
+		{"ipv4-mapped single ip", "http://[::ffff:192.168.1.5]/", false},
+		// 白名单主机名直接放行，不做 DNS 解析
+		{"hostname entry case-insensitive", "https://matrix.INTERNAL/_matrix", false},
+		{"loopback still blocked", "http://127.0.0.1/", true},
+		{"metadata still blocked", "http://169.254.169.254/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateOutboundURL(context.Background(), tt.url)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ValidateOutboundURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetOutboundAllowlistInvalidCIDR(t *testing.T) {
+	t.Cleanup(func() { _ = SetOutboundAllowlist(nil) })
+	if err := SetOutboundAllowlist([]string{"10.0.0.0/33"}); err == nil {
+		t.Fatal("expected error for invalid cidr")
+	}
+}
@@ -0,0 +1,32 @@
+package version
+
+import "runtime"
+
+// 构建信息，发布构建时通过 ldflags 注入，例如：
+//
+//	go build -ldflags "-X timelocker-backend/pkg/version.Version=v1.2.0 \
+//	  -X timelocker-backend/pkg/version.GitCommit=$(git rev-parse --short HEAD) \
+//	  -X timelocker-backend/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+var (
+	Version   = "v1.0.0"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get 返回当前进程的构建信息
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}